@@ -1,12 +1,24 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"os"
+	"time"
 
 	"crypto-exchange/internal/config"
 	"crypto-exchange/internal/handler"
+	"crypto-exchange/internal/logic/deposit"
+	"crypto-exchange/internal/logic/market"
+	"crypto-exchange/internal/logic/reconciliation"
+	"crypto-exchange/internal/logic/reserves"
+	"crypto-exchange/internal/logic/withdrawal"
+	"crypto-exchange/internal/por"
+	"crypto-exchange/internal/rbac"
 	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
 
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/rest"
@@ -17,15 +29,161 @@ var configFile = flag.String("f", "etc/exchange-api.yaml", "the config file")
 func main() {
 	flag.Parse()
 
-	var c config.Config
-	conf.MustLoad(*configFile, &c)
+	// 子命令：
+	//   exchange -f etc/exchange-api.yaml reconcile [-auto-freeze]
+	//   exchange -f etc/exchange-api.yaml reserves-snapshot
+	//   exchange -f etc/exchange-api.yaml grant-role -email <email> -role super_admin（初始化第一个管理员）
+	//   exchange verify-proof -user-id <id> -file <proof.json>（离线验证，不连接数据库）
+	switch flag.Arg(0) {
+	case "verify-proof":
+		os.Exit(runVerifyProof(flag.Args()[1:]))
+	case "reconcile":
+		os.Exit(runReconcile(loadConfig(), flag.Args()[1:]))
+	case "reserves-snapshot":
+		os.Exit(runReservesSnapshot(loadConfig()))
+	case "grant-role":
+		os.Exit(runGrantRole(loadConfig(), flag.Args()[1:]))
+	}
+
+	c := loadConfig()
 
 	server := rest.MustNewServer(c.RestConf)
 	defer server.Stop()
 
 	ctx := svc.NewServiceContext(c)
+	server.Use(ctx.ClientInfo)
 	handler.RegisterHandlers(server, ctx)
 
+	// 启动交易对状态排期任务
+	stopScheduler := market.StartTradingPairStatusScheduler(ctx, 30*time.Second)
+	defer stopScheduler()
+
+	// 启动定时余额对账任务
+	stopReconciliation := reconciliation.StartReconciliationScheduler(ctx,
+		time.Duration(c.Reconciliation.Interval)*time.Second, c.Reconciliation.AutoFreeze)
+	defer stopReconciliation()
+
+	// 启动定时储备金证明快照任务
+	stopReserves := reserves.StartSnapshotScheduler(ctx, time.Duration(c.ProofOfReserves.Interval)*time.Second)
+	defer stopReserves()
+
+	// 启动提现广播和链上状态跟踪任务
+	stopDispatcher := withdrawal.StartDispatcher(ctx, time.Duration(c.Chain.DispatchInterval)*time.Second)
+	defer stopDispatcher()
+
+	// 启动链上充值扫描任务
+	stopWatcher := deposit.StartWatcher(ctx, time.Duration(c.Chain.DepositScanInterval)*time.Second)
+	defer stopWatcher()
+
 	fmt.Printf("Starting server at %s:%d...\n", c.Host, c.Port)
 	server.Start()
 }
+
+func loadConfig() config.Config {
+	var c config.Config
+	conf.MustLoad(*configFile, &c)
+	return c
+}
+
+// runReconcile 执行一次余额对账并输出差异报告，存在不平账户时返回非零退出码
+func runReconcile(c config.Config, args []string) int {
+	fs := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	autoFreeze := fs.Bool("auto-freeze", c.Reconciliation.AutoFreeze, "freeze users whose accounts are unbalanced in two consecutive reconciliations")
+	fs.Parse(args)
+
+	ctx := svc.NewServiceContext(c)
+	report, discrepancies, err := reconciliation.NewReconciler(context.Background(), ctx).Run(*autoFreeze)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Reconciliation failed: %v\n", err)
+		return 2
+	}
+
+	fmt.Printf("Reconciliation report %d: checked %d accounts, %d discrepancies, %d users frozen\n",
+		report.ID, report.CheckedAccounts, report.DiscrepancyCount, report.FrozenUsers)
+	for _, d := range discrepancies {
+		fmt.Printf("user=%d currency=%s expected_total=%s actual_total=%s expected_frozen=%s actual_frozen=%s user_frozen=%t\n",
+			d.UserID, d.Currency, d.ExpectedTotal, d.ActualTotal, d.ExpectedFrozen, d.ActualFrozen, d.UserFrozen)
+	}
+
+	if len(discrepancies) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runReservesSnapshot 生成一批储备金证明快照并输出各币种的根哈希和负债总额
+func runReservesSnapshot(c config.Config) int {
+	ctx := svc.NewServiceContext(c)
+	snapshots, err := reserves.NewSnapshotJob(context.Background(), ctx).Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Proof of reserves snapshot failed: %v\n", err)
+		return 1
+	}
+
+	for _, s := range snapshots {
+		fmt.Printf("currency=%s root=%s total_liabilities=%s leaves=%d\n", s.Currency, s.RootHash, s.TotalLiabilities, s.LeafCount)
+	}
+	return 0
+}
+
+// runGrantRole 通过命令行授予角色，用于在没有管理员时初始化超级管理员，操作人记为0
+func runGrantRole(c config.Config, args []string) int {
+	fs := flag.NewFlagSet("grant-role", flag.ExitOnError)
+	email := fs.String("email", "", "email of the user to grant the role to")
+	role := fs.String("role", model.RoleSuperAdmin, "role to grant")
+	reason := fs.String("reason", "granted via command line", "reason recorded in the audit log")
+	fs.Parse(args)
+
+	if !rbac.IsValidRole(*role) {
+		fmt.Fprintf(os.Stderr, "Invalid role: %s\n", *role)
+		return 2
+	}
+
+	ctx := svc.NewServiceContext(c)
+	user, err := ctx.UserModel.FindOneByEmail(context.Background(), *email)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to find user %s: %v\n", *email, err)
+		return 1
+	}
+
+	now := time.Now()
+	err = ctx.UserRoleModel.Grant(context.Background(),
+		&model.UserRole{UserID: user.ID, Role: *role, CreatedAt: now},
+		&model.RoleAuditLog{UserID: user.ID, Role: *role, Action: model.RoleAuditActionGrant, Reason: *reason, CreatedAt: now})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to grant role %s to user %s: %v\n", *role, *email, err)
+		return 1
+	}
+
+	fmt.Printf("Granted role %s to user %d (%s)\n", *role, user.ID, user.Email)
+	return 0
+}
+
+// runVerifyProof 离线验证用户从 /api/v1/reserves/proof 下载的包含证明
+func runVerifyProof(args []string) int {
+	fs := flag.NewFlagSet("verify-proof", flag.ExitOnError)
+	userID := fs.Uint64("user-id", 0, "your user ID")
+	file := fs.String("file", "", "proof JSON file downloaded from /api/v1/reserves/proof")
+	fs.Parse(args)
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read proof file: %v\n", err)
+		return 2
+	}
+
+	var proof por.Proof
+	if err := json.Unmarshal(data, &proof); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid proof file: %v\n", err)
+		return 2
+	}
+
+	if err := por.VerifyProof(&proof, *userID); err != nil {
+		fmt.Printf("Proof INVALID: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Proof OK: balance %s %s is included in root %s (total liabilities %s)\n",
+		proof.Balance, proof.Currency, proof.RootHash, proof.TotalLiabilities)
+	return 0
+}
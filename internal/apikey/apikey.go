@@ -0,0 +1,60 @@
+// Package apikey API密钥的生成和HMAC-SHA256请求签名。
+//
+// 签名以明文密钥作为HMAC密钥；服务端加密保存密钥（见 totp.Authenticator），校验时解密，
+// 数据库中的内容泄露后不能直接用于签名：
+//
+//	payload   = timestamp + METHOD + requestURI + body
+//	signature = hex(HMAC-SHA256(secret, payload))
+//
+// 其中timestamp为毫秒时间戳，与X-API-TIMESTAMP请求头一致；requestURI包含查询参数。
+package apikey
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	keyIDBytes  = 16 // 密钥ID随机字节数
+	secretBytes = 32 // 密钥随机字节数
+)
+
+// Generate 生成新的密钥ID和明文密钥
+func Generate() (keyID, secret string, err error) {
+	keyID, err = randomHex(keyIDBytes)
+	if err != nil {
+		return "", "", err
+	}
+	secret, err = randomHex(secretBytes)
+	if err != nil {
+		return "", "", err
+	}
+	return keyID, secret, nil
+}
+
+// Sign 使用明文密钥对请求签名
+func Sign(secret, timestamp, method, requestURI string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte(strings.ToUpper(method)))
+	mac.Write([]byte(requestURI))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验请求签名，使用常量时间比较
+func Verify(secret, timestamp, method, requestURI string, body []byte, signature string) bool {
+	expected := Sign(secret, timestamp, method, requestURI, body)
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(signature)))
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,37 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	keyID, secret, err := Generate()
+	assert.NoError(t, err)
+	assert.Len(t, keyID, 2*keyIDBytes)
+	assert.Len(t, secret, 2*secretBytes)
+
+	otherKeyID, otherSecret, err := Generate()
+	assert.NoError(t, err)
+	assert.NotEqual(t, keyID, otherKeyID)
+	assert.NotEqual(t, secret, otherSecret)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	secret := "secret"
+	body := []byte(`{"symbol":"BTC/USDT","amount":"1"}`)
+	signature := Sign(secret, "1700000000000", "post", "/api/v1/trading/orders", body)
+
+	// 方法大小写和签名大小写不影响校验
+	assert.True(t, Verify(secret, "1700000000000", "POST", "/api/v1/trading/orders", body, signature))
+	assert.True(t, Verify(secret, "1700000000000", "POST", "/api/v1/trading/orders", body, strings.ToUpper(signature)))
+
+	// 任一签名字段变化都会导致校验失败
+	assert.False(t, Verify(secret, "1700000000001", "POST", "/api/v1/trading/orders", body, signature))
+	assert.False(t, Verify(secret, "1700000000000", "DELETE", "/api/v1/trading/orders", body, signature))
+	assert.False(t, Verify(secret, "1700000000000", "POST", "/api/v1/trading/orders?id=1", body, signature))
+	assert.False(t, Verify(secret, "1700000000000", "POST", "/api/v1/trading/orders", []byte(`{}`), signature))
+	assert.False(t, Verify("other", "1700000000000", "POST", "/api/v1/trading/orders", body, signature))
+}
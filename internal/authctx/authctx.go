@@ -0,0 +1,160 @@
+// Package authctx 请求上下文中的认证身份。
+//
+// 认证中间件把JWT声明或API密钥解析为类型化的 Principal 并写入上下文，业务逻辑统一通过
+// FromContext / UserID 获取当前用户，不再直接读取 ctx.Value("userId")。
+// 上下文中没有 Principal 时（如只经过go-zero JWT校验的路由），从go-zero写入的JWT声明中解析。
+package authctx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// JWT声明名，go-zero校验JWT后以同名键把声明写入请求上下文
+const (
+	ClaimUserID    = "userId" // 用户ID
+	ClaimEmail     = "email"  // 邮箱
+	ClaimRoles     = "roles"  // 管理后台角色
+	ClaimSessionID = "sid"    // 登录会话ID
+)
+
+// Method 认证方式
+type Method string
+
+const (
+	MethodJWT    Method = "jwt"     // 登录JWT
+	MethodApiKey Method = "api_key" // API密钥签名
+)
+
+var ErrUnauthenticated = errors.New("no authenticated user in context")
+
+// Principal 当前请求的认证身份
+type Principal struct {
+	UserID    uint64   // 用户ID
+	Email     string   // 邮箱
+	Method    Method   // 认证方式
+	Roles     []string // 管理后台角色，仅JWT认证时有值
+	SessionID string   // 登录会话ID，仅JWT认证时有值
+	ApiKeyID  string   // API密钥ID，仅API密钥认证时有值
+	Scopes    []string // API密钥权限，仅API密钥认证时有值
+}
+
+// HasRole 判断是否具有指定的管理后台角色
+func (p *Principal) HasRole(role string) bool {
+	return contains(p.Roles, role)
+}
+
+// HasScope 判断是否具有指定的API密钥权限，JWT认证不受API密钥权限限制
+func (p *Principal) HasScope(scope string) bool {
+	if p.Method != MethodApiKey {
+		return true
+	}
+	return contains(p.Scopes, scope)
+}
+
+type principalKey struct{}
+
+// WithPrincipal 把认证身份写入上下文
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext 获取当前请求的认证身份
+func FromContext(ctx context.Context) (*Principal, error) {
+	if p, ok := ctx.Value(principalKey{}).(*Principal); ok && p != nil {
+		return p, nil
+	}
+	return FromClaims(ctx)
+}
+
+// UserID 获取当前请求的用户ID
+func UserID(ctx context.Context) (uint64, error) {
+	p, err := FromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return p.UserID, nil
+}
+
+// FromClaims 从go-zero写入上下文的JWT声明中解析认证身份
+func FromClaims(ctx context.Context) (*Principal, error) {
+	value := ctx.Value(ClaimUserID)
+	if value == nil {
+		return nil, ErrUnauthenticated
+	}
+	userID, err := ParseUserID(value)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Principal{UserID: userID, Method: MethodJWT}
+	p.Email, _ = ctx.Value(ClaimEmail).(string)
+	p.SessionID, _ = ctx.Value(ClaimSessionID).(string)
+	switch roles := ctx.Value(ClaimRoles).(type) {
+	case []interface{}:
+		for _, role := range roles {
+			if s, ok := role.(string); ok {
+				p.Roles = append(p.Roles, s)
+			}
+		}
+	case []string:
+		p.Roles = roles
+	}
+	return p, nil
+}
+
+// ParseUserID 解析用户ID声明
+// go-zero解析JWT时数值为json.Number，其他JSON解码方式为float64，测试和内部调用可能直接使用整数或字符串
+func ParseUserID(value interface{}) (uint64, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return parseUint(v.String())
+	case string:
+		return parseUint(v)
+	case float64:
+		if v <= 0 || v != float64(uint64(v)) {
+			return 0, fmt.Errorf("invalid user ID %v", v)
+		}
+		return uint64(v), nil
+	case uint64:
+		if v == 0 {
+			return 0, ErrUnauthenticated
+		}
+		return v, nil
+	case int64:
+		if v <= 0 {
+			return 0, fmt.Errorf("invalid user ID %d", v)
+		}
+		return uint64(v), nil
+	case int:
+		if v <= 0 {
+			return 0, fmt.Errorf("invalid user ID %d", v)
+		}
+		return uint64(v), nil
+	default:
+		return 0, fmt.Errorf("invalid user ID type %T", value)
+	}
+}
+
+func parseUint(s string) (uint64, error) {
+	userID, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid user ID %q", s)
+	}
+	if userID == 0 {
+		return 0, ErrUnauthenticated
+	}
+	return userID, nil
+}
+
+func contains(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package authctx
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUserID(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    uint64
+		wantErr bool
+	}{
+		{"go-zero JWT声明", json.Number("123"), 123, false},
+		{"字符串", "456", 456, false},
+		{"float64", float64(789), 789, false},
+		{"uint64", uint64(101112), 101112, false},
+		{"int64", int64(7), 7, false},
+		{"int", int(8), 8, false},
+		{"无效字符串", "invalid", 0, true},
+		{"小数", float64(1.5), 0, true},
+		{"负数", int64(-1), 0, true},
+		{"零", json.Number("0"), 0, true},
+		{"bool", true, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUserID(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	_, err := FromContext(context.Background())
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+
+	// 从go-zero写入的原始声明解析
+	ctx := NewClaimsContext(map[string]interface{}{
+		ClaimUserID:    json.Number("42"),
+		ClaimEmail:     "alice@example.com",
+		ClaimSessionID: "s1",
+		ClaimRoles:     []interface{}{"finance", "auditor"},
+	})
+	p, err := FromContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &Principal{
+		UserID:    42,
+		Email:     "alice@example.com",
+		Method:    MethodJWT,
+		Roles:     []string{"finance", "auditor"},
+		SessionID: "s1",
+	}, p)
+	assert.True(t, p.HasRole("finance"))
+	assert.True(t, p.HasScope("withdraw"))
+
+	// 中间件写入的身份优先于原始声明
+	p, err = FromContext(WithPrincipal(ctx, &Principal{UserID: 7, Method: MethodApiKey, Scopes: []string{"read"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), p.UserID)
+	assert.True(t, p.HasScope("read"))
+	assert.False(t, p.HasScope("trade"))
+
+	userID, err := UserID(NewUserContext(9, "super_admin"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(9), userID)
+}
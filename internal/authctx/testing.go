@@ -0,0 +1,34 @@
+package authctx
+
+import "context"
+
+// 以下函数供测试注入认证身份使用
+
+// NewUserContext 返回JWT登录用户的上下文
+func NewUserContext(userID uint64, roles ...string) context.Context {
+	return WithPrincipal(context.Background(), &Principal{
+		UserID:    userID,
+		Method:    MethodJWT,
+		Roles:     roles,
+		SessionID: "test-session",
+	})
+}
+
+// NewApiKeyContext 返回API密钥认证的上下文
+func NewApiKeyContext(userID uint64, apiKeyID string, scopes ...string) context.Context {
+	return WithPrincipal(context.Background(), &Principal{
+		UserID:   userID,
+		Method:   MethodApiKey,
+		ApiKeyID: apiKeyID,
+		Scopes:   scopes,
+	})
+}
+
+// NewClaimsContext 模拟go-zero校验JWT后写入上下文的原始声明，数值声明为json.Number
+func NewClaimsContext(claims map[string]interface{}) context.Context {
+	ctx := context.Background()
+	for key, value := range claims {
+		ctx = context.WithValue(ctx, key, value)
+	}
+	return ctx
+}
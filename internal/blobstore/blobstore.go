@@ -0,0 +1,147 @@
+// Package blobstore 文件存储：业务代码只依赖Store接口，默认使用本地文件系统，
+// 可替换为对象存储等其他实现。
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 存储类型
+const (
+	TypeLocal = "local" // 本地文件系统
+)
+
+var (
+	// ErrNotFound 文件不存在
+	ErrNotFound = errors.New("blob not found")
+	// ErrInvalidKey 文件key格式不合法
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// keyPattern key由斜杠分隔的若干段组成，每段只能包含字母、数字、点、下划线和连字符，且不能以点开头
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_\-][A-Za-z0-9_.\-]*(/[A-Za-z0-9_\-][A-Za-z0-9_.\-]*)*$`)
+
+// Store 文件存储接口
+type Store interface {
+	// Put 写入文件，key已存在时覆盖
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open 打开文件，不存在时返回ErrNotFound
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete 删除文件，不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+}
+
+// Conf 文件存储配置
+type Conf struct {
+	Type string `json:",default=local,options=local"` // 存储类型
+	Dir  string `json:",default=data/blobs"`          // 本地存储的根目录
+}
+
+// NewStore 按配置创建文件存储
+func NewStore(c Conf) (Store, error) {
+	switch c.Type {
+	case TypeLocal, "":
+		return NewLocalStore(c.Dir)
+	default:
+		return nil, fmt.Errorf("unsupported blob store type %q", c.Type)
+	}
+}
+
+// MustNewStore 按配置创建文件存储，失败时退出进程
+func MustNewStore(c Conf) Store {
+	store, err := NewStore(c)
+	logx.Must(err)
+	return store
+}
+
+// ValidKey key是否合法，每段不能以点开头，因此不会出现".."等路径跳转
+func ValidKey(key string) bool {
+	return keyPattern.MatchString(key)
+}
+
+// LocalStore 将文件保存在本地目录中，key中的斜杠对应子目录
+type LocalStore struct {
+	root string
+}
+
+// NewLocalStore 创建本地文件存储，根目录不存在时自动创建
+func NewLocalStore(root string) (*LocalStore, error) {
+	if root == "" {
+		return nil, errors.New("blob store directory is required")
+	}
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, err
+	}
+	return &LocalStore{root: root}, nil
+}
+
+// Put 先写入临时文件再重命名，避免读取到写了一半的文件
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Open 打开文件
+func (s *LocalStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// Delete 删除文件
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	if !ValidKey(key) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
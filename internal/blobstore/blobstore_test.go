@@ -0,0 +1,51 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidKey(t *testing.T) {
+	assert.True(t, ValidKey("kyc/1/abc-0.png"))
+	assert.True(t, ValidKey("file"))
+	assert.False(t, ValidKey(""))
+	assert.False(t, ValidKey("/etc/passwd"))
+	assert.False(t, ValidKey("kyc/../secret"))
+	assert.False(t, ValidKey("kyc//1"))
+	assert.False(t, ValidKey(".hidden"))
+	assert.False(t, ValidKey("kyc/1/a b"))
+}
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.Put(ctx, "kyc/1/doc", strings.NewReader("hello")))
+	r, err := store.Open(ctx, "kyc/1/doc")
+	if assert.NoError(t, err) {
+		data, _ := io.ReadAll(r)
+		r.Close()
+		assert.Equal(t, "hello", string(data))
+	}
+
+	// 覆盖写入
+	assert.NoError(t, store.Put(ctx, "kyc/1/doc", strings.NewReader("world")))
+	r, err = store.Open(ctx, "kyc/1/doc")
+	if assert.NoError(t, err) {
+		data, _ := io.ReadAll(r)
+		r.Close()
+		assert.Equal(t, "world", string(data))
+	}
+
+	assert.NoError(t, store.Delete(ctx, "kyc/1/doc"))
+	assert.NoError(t, store.Delete(ctx, "kyc/1/doc"))
+	_, err = store.Open(ctx, "kyc/1/doc")
+	assert.Equal(t, ErrNotFound, err)
+
+	assert.Equal(t, ErrInvalidKey, store.Put(ctx, "../escape", strings.NewReader("x")))
+}
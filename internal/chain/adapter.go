@@ -0,0 +1,115 @@
+// Package chain 定义区块链接入接口：每个充提网络对应一个ChainAdapter，负责生成充值地址、
+// 扫描入账转账及其确认数、广播提现交易和查询交易状态。
+// 本包同时提供进程内的模拟链实现，用于在没有真实节点的环境下跑通充值确认和提现广播流程。
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrNetworkNotSupported = errors.New("no chain adapter for network")
+	ErrTxNotFound          = errors.New("transaction not found on chain")
+	ErrBroadcastRejected   = errors.New("transaction rejected by chain")
+)
+
+// 链上交易状态 / Chain Transaction Status
+const (
+	TxStatusPending   int64 = 1 // 已广播，尚未打包
+	TxStatusConfirmed int64 = 2 // 已打包执行成功，确认数见Confirmations
+	TxStatusFailed    int64 = 3 // 已打包但执行失败，资金未转出
+)
+
+// Transfer 链上入账转账
+type Transfer struct {
+	TxHash        string // 交易哈希
+	Index         int64  // 同一交易内的转账序号，(TxHash, Index)唯一标识一笔转账
+	Currency      string // 币种代码，同一网络上可能有多个币种（如ERC20上的ETH和USDT）
+	Address       string // 收款地址
+	Amount        string // 转账金额
+	BlockHeight   int64  // 所在区块高度
+	Confirmations int64  // 当前确认数，所在区块本身算1个确认
+}
+
+// BroadcastRequest 提现广播请求
+type BroadcastRequest struct {
+	Reference string // 业务引用（提现交易ID），适配器据此保证重复广播不会重复转账
+	Currency  string // 币种代码
+	Address   string // 收款地址
+	Amount    string // 转账金额（不含手续费）
+}
+
+// TxInfo 链上交易状态
+type TxInfo struct {
+	TxHash        string
+	Status        int64 // 交易状态：1-待打包，2-已确认，3-执行失败
+	BlockHeight   int64 // 所在区块高度，待打包时为0
+	Confirmations int64 // 当前确认数，待打包时为0
+}
+
+// Adapter 单个网络的区块链接入接口
+type Adapter interface {
+	// Network 网络代码，与currency_networks.network一致
+	Network() string
+	// DeriveAddress 按派生序号生成充值地址，同一序号总是得到同一地址
+	DeriveAddress(ctx context.Context, index uint64) (string, error)
+	// LatestHeight 当前最新区块高度
+	LatestHeight(ctx context.Context) (int64, error)
+	// Transfers 返回区块高度不低于fromHeight的全部入账转账及当前确认数
+	// 已被链重组移除的转账不再返回，调用方需自行比对已入账记录
+	Transfers(ctx context.Context, fromHeight int64) ([]*Transfer, error)
+	// Broadcast 广播提现交易并返回交易哈希；被链拒绝时返回ErrBroadcastRejected，其他错误可重试
+	Broadcast(ctx context.Context, req *BroadcastRequest) (string, error)
+	// Transaction 查询交易状态，交易不存在（如被重组移除且未重新打包）时返回ErrTxNotFound
+	Transaction(ctx context.Context, txHash string) (*TxInfo, error)
+}
+
+// Factory 按网络代码创建适配器，用于未显式注册的网络
+type Factory func(network string) Adapter
+
+// Registry 网络代码到适配器的注册表
+type Registry struct {
+	mu       sync.RWMutex
+	adapters map[string]Adapter
+	factory  Factory
+}
+
+// NewRegistry 创建适配器注册表，factory不为nil时未注册的网络按需创建适配器
+func NewRegistry(factory Factory) *Registry {
+	return &Registry{
+		adapters: make(map[string]Adapter),
+		factory:  factory,
+	}
+}
+
+// Register 注册网络适配器，同一网络重复注册时覆盖
+func (r *Registry) Register(adapter Adapter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.adapters[adapter.Network()] = adapter
+}
+
+// Get 获取网络适配器
+func (r *Registry) Get(network string) (Adapter, error) {
+	r.mu.RLock()
+	adapter, ok := r.adapters[network]
+	r.mu.RUnlock()
+	if ok {
+		return adapter, nil
+	}
+	if r.factory == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNetworkNotSupported, network)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if adapter, ok := r.adapters[network]; ok {
+		return adapter, nil
+	}
+	adapter = r.factory(network)
+	r.adapters[network] = adapter
+	return adapter, nil
+}
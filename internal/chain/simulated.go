@@ -0,0 +1,250 @@
+package chain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var _ Adapter = (*SimulatedChain)(nil)
+
+// simTx 模拟链上的交易
+type simTx struct {
+	hash        string
+	reference   string      // 提现广播的业务引用，入账交易为空
+	transfers   []*Transfer // 入账转账，提现交易为空
+	blockHeight int64       // 所在区块高度，0表示尚未打包
+	failed      bool        // 打包后执行失败
+}
+
+// SimulatedChain 进程内模拟链，所有数据保存在内存中
+// blockTime大于0时按时间自动出块（在每次调用时补齐经过的区块），为0时只能通过Mine手动出块
+type SimulatedChain struct {
+	network   string
+	blockTime time.Duration
+
+	mu          sync.Mutex
+	height      int64
+	lastBlockAt time.Time
+	seq         uint64
+	txs         map[string]*simTx
+	order       []*simTx          // 按提交顺序
+	references  map[string]*simTx // 业务引用 -> 提现交易
+	failHashes  map[string]bool   // 打包后执行失败的交易
+	rejectNext  int               // 接下来拒绝的广播次数
+}
+
+// NewSimulatedChain 创建模拟链
+func NewSimulatedChain(network string, blockTime time.Duration) *SimulatedChain {
+	return &SimulatedChain{
+		network:     network,
+		blockTime:   blockTime,
+		lastBlockAt: time.Now(),
+		txs:         make(map[string]*simTx),
+		references:  make(map[string]*simTx),
+		failHashes:  make(map[string]bool),
+	}
+}
+
+// SimulatedFactory 返回按网络创建模拟链的工厂函数
+func SimulatedFactory(blockTime time.Duration) Factory {
+	return func(network string) Adapter {
+		return NewSimulatedChain(network, blockTime)
+	}
+}
+
+func (c *SimulatedChain) Network() string {
+	return c.network
+}
+
+// DeriveAddress 模拟地址为网络代码和派生序号哈希后取前20字节的十六进制表示
+func (c *SimulatedChain) DeriveAddress(ctx context.Context, index uint64) (string, error) {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/address/%d", c.network, index)))
+	return "0x" + hex.EncodeToString(sum[:20]), nil
+}
+
+func (c *SimulatedChain) LatestHeight(ctx context.Context) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advance()
+	return c.height, nil
+}
+
+func (c *SimulatedChain) Transfers(ctx context.Context, fromHeight int64) ([]*Transfer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advance()
+
+	var resp []*Transfer
+	for _, tx := range c.order {
+		if tx.blockHeight == 0 || tx.blockHeight < fromHeight {
+			continue
+		}
+		for _, t := range tx.transfers {
+			transfer := *t
+			transfer.BlockHeight = tx.blockHeight
+			transfer.Confirmations = c.height - tx.blockHeight + 1
+			resp = append(resp, &transfer)
+		}
+	}
+	return resp, nil
+}
+
+// Broadcast 提现交易进入待打包队列；同一业务引用重复广播时返回已有的交易哈希
+func (c *SimulatedChain) Broadcast(ctx context.Context, req *BroadcastRequest) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advance()
+
+	if tx, ok := c.references[req.Reference]; ok {
+		return tx.hash, nil
+	}
+	if c.rejectNext > 0 {
+		c.rejectNext--
+		return "", fmt.Errorf("%w: simulated rejection of %s", ErrBroadcastRejected, req.Reference)
+	}
+
+	tx := c.submit(nil)
+	tx.reference = req.Reference
+	c.references[req.Reference] = tx
+	return tx.hash, nil
+}
+
+func (c *SimulatedChain) Transaction(ctx context.Context, txHash string) (*TxInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advance()
+
+	tx, ok := c.txs[txHash]
+	if !ok {
+		return nil, ErrTxNotFound
+	}
+
+	info := &TxInfo{TxHash: tx.hash, Status: TxStatusPending}
+	if tx.blockHeight > 0 {
+		info.BlockHeight = tx.blockHeight
+		info.Confirmations = c.height - tx.blockHeight + 1
+		info.Status = TxStatusConfirmed
+		if tx.failed {
+			info.Status = TxStatusFailed
+		}
+	}
+	return info, nil
+}
+
+// Deposit 模拟外部向address转入一笔资金，交易在下一个区块打包，返回交易哈希
+func (c *SimulatedChain) Deposit(address, currency, amount string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advance()
+
+	tx := c.submit([]*Transfer{{Currency: currency, Address: address, Amount: amount}})
+	return tx.hash
+}
+
+// Mine 出n个区块，待打包的交易全部进入第一个区块
+func (c *SimulatedChain) Mine(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mine(n)
+}
+
+// Reorg 回滚最近depth个区块：其中的提现交易回到待打包队列，入账交易被丢弃（模拟双花），
+// 之后需要重新出块才能恢复高度
+func (c *SimulatedChain) Reorg(depth int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reorg(depth, true)
+}
+
+// ReorgKeepDeposits 回滚最近depth个区块，其中的全部交易回到待打包队列，在新的分叉上重新打包，
+// 之后需要重新出块才能恢复高度
+func (c *SimulatedChain) ReorgKeepDeposits(depth int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reorg(depth, false)
+}
+
+// FailTransaction 使提现交易打包后执行失败，已打包的交易立即变为失败
+func (c *SimulatedChain) FailTransaction(txHash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failHashes[txHash] = true
+	if tx, ok := c.txs[txHash]; ok && tx.blockHeight > 0 {
+		tx.failed = true
+	}
+}
+
+// RejectNextBroadcasts 使接下来的n次广播被拒绝
+func (c *SimulatedChain) RejectNextBroadcasts(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rejectNext = n
+}
+
+// reorg 回滚最近depth个区块，dropDeposits为true时丢弃其中的入账交易，调用方需持有锁
+func (c *SimulatedChain) reorg(depth int, dropDeposits bool) {
+	if int64(depth) > c.height {
+		depth = int(c.height)
+	}
+	c.height -= int64(depth)
+
+	kept := c.order[:0]
+	for _, tx := range c.order {
+		if tx.blockHeight > c.height {
+			if tx.reference == "" && dropDeposits {
+				delete(c.txs, tx.hash)
+				continue
+			}
+			tx.blockHeight = 0
+			tx.failed = false
+		}
+		kept = append(kept, tx)
+	}
+	c.order = kept
+}
+
+// submit 创建交易并放入待打包队列，调用方需持有锁
+func (c *SimulatedChain) submit(transfers []*Transfer) *simTx {
+	c.seq++
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/tx/%d", c.network, c.seq)))
+	tx := &simTx{hash: "0x" + hex.EncodeToString(sum[:]), transfers: transfers}
+	for i, t := range transfers {
+		t.TxHash = tx.hash
+		t.Index = int64(i)
+	}
+	c.txs[tx.hash] = tx
+	c.order = append(c.order, tx)
+	return tx
+}
+
+// mine 出n个区块，调用方需持有锁
+func (c *SimulatedChain) mine(n int) {
+	if n <= 0 {
+		return
+	}
+	c.height++
+	for _, tx := range c.order {
+		if tx.blockHeight == 0 {
+			tx.blockHeight = c.height
+			tx.failed = c.failHashes[tx.hash]
+		}
+	}
+	c.height += int64(n - 1)
+}
+
+// advance 按出块时间补齐经过的区块，调用方需持有锁
+func (c *SimulatedChain) advance() {
+	if c.blockTime <= 0 {
+		return
+	}
+	elapsed := time.Since(c.lastBlockAt)
+	if n := int(elapsed / c.blockTime); n > 0 {
+		c.mine(n)
+		c.lastBlockAt = c.lastBlockAt.Add(time.Duration(n) * c.blockTime)
+	}
+}
@@ -0,0 +1,133 @@
+package chain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulatedChain_DepositConfirmations(t *testing.T) {
+	ctx := context.Background()
+	c := NewSimulatedChain("ERC20", 0)
+
+	addr, err := c.DeriveAddress(ctx, 1)
+	assert.NoError(t, err)
+	again, _ := c.DeriveAddress(ctx, 1)
+	other, _ := c.DeriveAddress(ctx, 2)
+	assert.Equal(t, addr, again)
+	assert.NotEqual(t, addr, other)
+	assert.Len(t, addr, 42)
+
+	hash := c.Deposit(addr, "USDT", "100")
+
+	// 未打包的转账不返回
+	transfers, err := c.Transfers(ctx, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, transfers)
+
+	c.Mine(1)
+	transfers, _ = c.Transfers(ctx, 0)
+	assert.Len(t, transfers, 1)
+	assert.Equal(t, hash, transfers[0].TxHash)
+	assert.Equal(t, int64(1), transfers[0].BlockHeight)
+	assert.Equal(t, int64(1), transfers[0].Confirmations)
+
+	c.Mine(11)
+	transfers, _ = c.Transfers(ctx, 0)
+	assert.Equal(t, int64(12), transfers[0].Confirmations)
+
+	height, _ := c.LatestHeight(ctx)
+	assert.Equal(t, int64(12), height)
+
+	// 从更高的区块开始扫描时不包含该转账
+	transfers, _ = c.Transfers(ctx, 2)
+	assert.Empty(t, transfers)
+}
+
+func TestSimulatedChain_Reorg(t *testing.T) {
+	ctx := context.Background()
+	c := NewSimulatedChain("BTC", 0)
+
+	c.Mine(5)
+	deposit := c.Deposit("addr", "BTC", "1")
+	withdraw, err := c.Broadcast(ctx, &BroadcastRequest{Reference: "WTH_1", Currency: "BTC", Address: "ext", Amount: "0.5"})
+	assert.NoError(t, err)
+	c.Mine(2)
+
+	info, _ := c.Transaction(ctx, withdraw)
+	assert.Equal(t, TxStatusConfirmed, info.Status)
+	assert.Equal(t, int64(6), info.BlockHeight)
+
+	c.Reorg(2)
+
+	// 入账交易被丢弃，提现交易回到待打包队列
+	transfers, _ := c.Transfers(ctx, 0)
+	assert.Empty(t, transfers)
+	_, err = c.Transaction(ctx, deposit)
+	assert.ErrorIs(t, err, ErrTxNotFound)
+	info, _ = c.Transaction(ctx, withdraw)
+	assert.Equal(t, TxStatusPending, info.Status)
+	assert.Equal(t, int64(0), info.Confirmations)
+
+	c.Mine(1)
+	info, _ = c.Transaction(ctx, withdraw)
+	assert.Equal(t, int64(6), info.BlockHeight)
+}
+
+func TestSimulatedChain_Broadcast(t *testing.T) {
+	ctx := context.Background()
+	c := NewSimulatedChain("TRC20", 0)
+	req := &BroadcastRequest{Reference: "WTH_1", Currency: "USDT", Address: "T...", Amount: "10"}
+
+	hash, err := c.Broadcast(ctx, req)
+	assert.NoError(t, err)
+
+	// 同一业务引用重复广播返回同一交易
+	again, err := c.Broadcast(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, hash, again)
+
+	c.RejectNextBroadcasts(1)
+	_, err = c.Broadcast(ctx, &BroadcastRequest{Reference: "WTH_2"})
+	assert.ErrorIs(t, err, ErrBroadcastRejected)
+	_, err = c.Broadcast(ctx, &BroadcastRequest{Reference: "WTH_2"})
+	assert.NoError(t, err)
+
+	c.FailTransaction(hash)
+	c.Mine(1)
+	info, _ := c.Transaction(ctx, hash)
+	assert.Equal(t, TxStatusFailed, info.Status)
+
+	_, err = c.Transaction(ctx, "0xunknown")
+	assert.ErrorIs(t, err, ErrTxNotFound)
+}
+
+func TestSimulatedChain_AutoMine(t *testing.T) {
+	c := NewSimulatedChain("ERC20", time.Millisecond)
+	c.lastBlockAt = time.Now().Add(-10 * time.Millisecond)
+
+	height, _ := c.LatestHeight(context.Background())
+	assert.GreaterOrEqual(t, height, int64(10))
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(nil)
+	_, err := r.Get("ERC20")
+	assert.ErrorIs(t, err, ErrNetworkNotSupported)
+
+	c := NewSimulatedChain("ERC20", 0)
+	r.Register(c)
+	adapter, err := r.Get("ERC20")
+	assert.NoError(t, err)
+	assert.Same(t, c, adapter)
+
+	// 带工厂的注册表按需创建并缓存适配器
+	r = NewRegistry(SimulatedFactory(0))
+	first, err := r.Get("BTC")
+	assert.NoError(t, err)
+	second, _ := r.Get("BTC")
+	assert.Same(t, first, second)
+	assert.Equal(t, "BTC", first.Network())
+}
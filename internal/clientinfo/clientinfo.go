@@ -0,0 +1,74 @@
+// Package clientinfo 请求来源信息：客户端IP、User-Agent和设备指纹。
+//
+// ClientInfo中间件在请求入口解析来源信息并写入上下文，业务逻辑通过 FromContext 获取，
+// 用于安全事件记录和新设备登录提醒等场景，无需在每个请求类型中声明相关请求头。
+package clientinfo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// HeaderDeviceID 客户端上报的设备标识，App端应在安装后生成并持久保存
+const HeaderDeviceID = "X-Device-Id"
+
+// maxUserAgentLength 记录的User-Agent最大长度，超出部分截断
+const maxUserAgentLength = 512
+
+// Info 请求来源信息
+type Info struct {
+	IP                string // 客户端IP
+	UserAgent         string // 客户端标识
+	DeviceFingerprint string // 设备指纹，由设备标识、User-Agent和Accept-Language计算
+}
+
+type infoKey struct{}
+
+// WithInfo 把请求来源信息写入上下文
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// FromContext 获取请求来源信息，上下文中没有时返回零值
+func FromContext(ctx context.Context) Info {
+	info, _ := ctx.Value(infoKey{}).(Info)
+	return info
+}
+
+// FromRequest 解析请求来源信息，trustForwardedFor见 ClientIP
+func FromRequest(r *http.Request, trustForwardedFor bool) Info {
+	userAgent := r.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	return Info{
+		IP:                ClientIP(r, trustForwardedFor),
+		UserAgent:         userAgent,
+		DeviceFingerprint: Fingerprint(r.Header.Get(HeaderDeviceID), r.UserAgent(), r.Header.Get("Accept-Language")),
+	}
+}
+
+// ClientIP 获取请求来源IP，只有部署在可信代理之后时才使用X-Forwarded-For
+func ClientIP(r *http.Request, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Fingerprint 计算设备指纹（十六进制SHA-256的前32位）
+// 浏览器通常没有设备标识，此时按User-Agent和Accept-Language区分设备，浏览器升级后会被识别为新设备
+func Fingerprint(deviceID, userAgent, acceptLanguage string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(deviceID) + "\n" + userAgent + "\n" + acceptLanguage))
+	return hex.EncodeToString(sum[:16])
+}
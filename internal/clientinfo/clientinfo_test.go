@@ -0,0 +1,46 @@
+package clientinfo
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "10.0.0.1:12345"
+	r.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.2")
+
+	assert.Equal(t, "10.0.0.1", ClientIP(r, false))
+	assert.Equal(t, "203.0.113.7", ClientIP(r, true))
+
+	r.Header.Del("X-Forwarded-For")
+	assert.Equal(t, "10.0.0.1", ClientIP(r, true))
+}
+
+func TestFromRequest(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "10.0.0.1:12345"
+	r.Header.Set("User-Agent", strings.Repeat("a", 1000))
+	r.Header.Set("Accept-Language", "zh-CN")
+	r.Header.Set(HeaderDeviceID, "device-1")
+
+	info := FromRequest(r, false)
+	assert.Equal(t, "10.0.0.1", info.IP)
+	assert.Len(t, info.UserAgent, maxUserAgentLength)
+	assert.Len(t, info.DeviceFingerprint, 32)
+
+	// 设备标识不同时指纹不同
+	r.Header.Set(HeaderDeviceID, "device-2")
+	assert.NotEqual(t, info.DeviceFingerprint, FromRequest(r, false).DeviceFingerprint)
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, Info{}, FromContext(context.Background()))
+
+	info := Info{IP: "203.0.113.7", UserAgent: "ua", DeviceFingerprint: "fp"}
+	assert.Equal(t, info, FromContext(WithInfo(context.Background(), info)))
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"crypto-exchange/internal/blobstore"
+	"crypto-exchange/internal/ratelimit"
+
 	"github.com/zeromicro/go-zero/core/stores/redis"
 	"github.com/zeromicro/go-zero/rest"
 )
@@ -13,8 +16,115 @@ type Config struct {
 	}
 	DataSource string
 	Redis      redis.RedisConf
-	RateLimit  struct {
-		Seconds int
-		Quota   int
+	// 接口限流配置：按用户、API密钥和IP在滑动窗口内按接口权重计数，额度为0表示不限制
+	// 通过API密钥调用时同时扣减API密钥和所属用户的额度，未登录的请求按IP计数
+	RateLimit struct {
+		Seconds         int              `json:",default=60"`     // 限流窗口（秒）
+		Quota           int              `json:",default=1200"`   // 每个用户窗口内的权重额度
+		ApiKeyQuota     int              `json:",default=1200"`   // 每个API密钥窗口内的权重额度
+		IPQuota         int              `json:",default=600"`    // 未登录请求每个IP窗口内的权重额度
+		OrdersPerSecond int              `json:",default=10"`     // 每个用户每秒最多下单数量，不计入权重额度
+		OrdersPerDay    int              `json:",default=200000"` // 每个用户每天（滑动24小时）最多下单数量
+		Weights         []ratelimit.Rule `json:",optional"`       // 接口权重规则，按顺序使用第一条匹配的规则
+	}
+	// 币种注册表配置，币种配置在进程内缓存，管理后台修改后立即失效，其他实例在缓存过期后生效
+	Currency struct {
+		CacheTTL int64 `json:",default=60"` // 缓存有效期（秒）
+	}
+	// 提现安全配置：地址簿冷却期和提现确认码
+	Withdraw struct {
+		AddressCooldown    int64 `json:",default=86400"` // 新添加的提现地址冷却时长（秒）
+		ConfirmTTL         int64 `json:",default=900"`   // 提现确认码有效期（秒），过期未确认的提现自动取消
+		ConfirmCodeDigits  int   `json:",default=6"`     // 提现确认码位数
+		ConfirmMaxAttempts int   `json:",default=5"`     // 确认码最多校验失败次数，超过后提现取消
+	}
+	// 提现限额配置：按用户认证等级限制24小时滚动窗口内的提现金额（按参考币种估值）和次数
+	// 未配置用户等级对应的档位时使用不高于该等级的最高档位，没有任何可用档位时不限制
+	WithdrawLimit struct {
+		ReferenceCurrency  string         `json:",default=USDT"`  // 限额计价的参考币种
+		PeggedCurrencies   []string       `json:",optional"`      // 与参考币种1:1估值的币种，如USDC
+		PasswordChangeLock int64          `json:",default=86400"` // 修改密码后禁止提现的时长（秒），0表示不限制
+		MinLevel           int64          `json:",default=0"`     // 允许提现的最低认证等级，0表示不限制
+		Tiers              []WithdrawTier `json:",optional"`      // 各认证等级的限额档位
+	}
+	// 站内转账配置：限制每个用户24小时滚动窗口内的转出金额（按提现限额的参考币种估值）和次数，0表示不限制
+	Transfer struct {
+		DailyAmount string `json:",default=0"` // 24小时内累计转出金额上限
+		DailyCount  int    `json:",default=0"` // 24小时内转出次数上限
+	}
+	// 子账户配置
+	SubAccount struct {
+		MaxPerMaster int64 `json:",default=20"` // 每个母账户最多可创建的子账户数量
+	}
+	// 两步验证配置
+	TwoFactor struct {
+		Issuer        string `json:",default=CryptoExchange"` // 认证器App中显示的发行方名称
+		EncryptionKey string // TOTP密钥和API密钥的加密密钥，修改后已绑定的两步验证和已创建的API密钥将无法使用
+		Skew          int    `json:",default=1"`   // 允许的时钟偏差（30秒步长数）
+		PreAuthExpire int64  `json:",default=300"` // 两步登录中预认证token的有效期（秒）
+		RecoveryCodes int    `json:",default=10"`  // 绑定时生成的恢复码数量
+	}
+	// 登录会话配置，访问令牌有效期见Auth.AccessExpire，建议配置为较短的时间
+	Session struct {
+		RefreshExpire int64 `json:",default=2592000"` // 刷新令牌有效期（秒），每次刷新后重新计算
+	}
+	// 邮件配置：邮箱验证和重置密码邮件，链接为URL后附加token查询参数
+	Email struct {
+		SMTP struct {
+			Host     string
+			Port     int    `json:",default=587"`
+			Username string `json:",optional"` // 为空时不进行SMTP认证
+			Password string `json:",optional"`
+			From     string // 发件人地址
+		}
+		VerifyURL      string // 邮箱验证页面地址
+		ResetURL       string // 重置密码页面地址
+		VerifyTokenTTL int64  `json:",default=86400"` // 邮箱验证令牌有效期（秒）
+		ResetTokenTTL  int64  `json:",default=1800"`  // 重置密码令牌有效期（秒）
+		ResendInterval int64  `json:",default=60"`    // 同一用户两次发送同类邮件的最小间隔（秒）
 	}
+	// 身份认证（KYC）配置：证件文件保存在Storage配置的文件存储中
+	// 提交申请接口的请求体上限为20MB（见api定义中的maxBytes），MaxFiles×MaxFileSize不应超过该值
+	Kyc struct {
+		Storage     blobstore.Conf
+		MaxFiles    int   `json:",default=4"`       // 每次申请最多上传的证件文件数量
+		MaxFileSize int64 `json:",default=5242880"` // 单个证件文件大小上限（字节）
+	}
+	// 账户安全配置：登录和安全设置变更记录为安全事件，近期未使用过的设备或IP登录时发送提醒
+	Security struct {
+		NewDeviceAlert  bool  `json:",default=true"` // 是否发送新设备登录提醒
+		KnownDeviceDays int64 `json:",default=90"`   // 最近多少天内登录过的设备和IP视为已知
+	}
+	// API密钥配置
+	ApiKey struct {
+		RecvWindow        int64 `json:",default=5000"`  // 请求时间戳与服务器时间允许的最大偏差（毫秒），窗口内同一签名只能使用一次
+		MaxPerUser        int64 `json:",default=20"`    // 每个用户最多可创建的API密钥数量
+		TrustForwardedFor bool  `json:",default=false"` // 是否按X-Forwarded-For获取客户端IP（IP白名单校验和安全事件记录），仅部署在可信反向代理之后时开启
+	}
+	// 余额对账任务配置，Interval为0时不在服务内定时执行，仍可通过reconcile子命令手动执行
+	Reconciliation struct {
+		Interval   int64  `json:",default=0"`     // 执行间隔（秒）
+		AutoFreeze bool   `json:",default=false"` // 是否自动冻结连续两次对账都不平的账户的用户
+		Tolerance  string `json:",default=0"`     // 允许的差额容差
+	}
+	// 储备金证明快照配置，Interval为0时只能通过reserves-snapshot子命令生成快照
+	ProofOfReserves struct {
+		Interval int64 `json:",default=0"` // 快照间隔（秒）
+	}
+	// 区块链接入配置，Simulated为true时所有网络使用进程内模拟链（仅用于开发和测试）
+	Chain struct {
+		Simulated           bool  `json:",default=true"` // 是否使用模拟链
+		BlockTime           int64 `json:",default=5"`    // 模拟链出块间隔（秒）
+		DispatchInterval    int64 `json:",default=15"`   // 提现广播和链上状态跟踪间隔（秒），0表示不启动
+		DepositScanInterval int64 `json:",default=15"`   // 充值扫描间隔（秒），0表示不启动
+		ReorgWindow         int64 `json:",default=64"`   // 每轮回溯重新扫描的区块数，用于发现链重组
+	}
+}
+
+// WithdrawTier 某一认证等级的提现限额档位，金额为0或次数为0表示不限制
+type WithdrawTier struct {
+	Level       int64  // 用户认证等级
+	DailyAmount string `json:",default=0"` // 24小时内累计提现金额上限（参考币种计价）
+	DailyCount  int    `json:",default=0"` // 24小时内提现次数上限
+	HourlyCount int    `json:",default=0"` // 1小时内提现次数上限
 }
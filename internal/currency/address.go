@@ -0,0 +1,169 @@
+package currency
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"crypto-exchange/model"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// AddressValidator 某种地址格式的校验器，校验完全离线进行，不访问节点
+type AddressValidator interface {
+	Validate(address string) error
+}
+
+var (
+	validatorsMu      sync.RWMutex
+	addressValidators = map[string]AddressValidator{
+		model.AddressTypeBitcoin: BitcoinValidator{PubKeyHashVersion: 0x00, ScriptHashVersion: 0x05, HRP: "bc"},
+		model.AddressTypeEVM:     EVMValidator{},
+		model.AddressTypeTron:    TronValidator{},
+	}
+)
+
+// RegisterAddressValidator 注册或替换地址格式的校验器，需在服务启动时调用
+// 注册后即可在币种网络配置中使用该地址格式
+func RegisterAddressValidator(addressType string, validator AddressValidator) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	addressValidators[addressType] = validator
+}
+
+// AddressValidatorFor 获取地址格式对应的校验器
+func AddressValidatorFor(addressType string) (AddressValidator, bool) {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	validator, ok := addressValidators[addressType]
+	return validator, ok
+}
+
+// SupportedAddressTypes 返回已注册的地址格式，按名称排序
+func SupportedAddressTypes() []string {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	types := make([]string, 0, len(addressValidators))
+	for addressType := range addressValidators {
+		types = append(types, addressType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// IsSupportedAddressType 判断是否支持该地址格式
+func IsSupportedAddressType(addressType string) bool {
+	_, ok := AddressValidatorFor(addressType)
+	return ok
+}
+
+// ValidateAddress 按网络的地址格式校验地址，格式不符时返回ErrInvalidAddress
+func ValidateAddress(addressType, address string) error {
+	if address == "" {
+		return fmt.Errorf("%w: address is required", model.ErrInvalidAddress)
+	}
+
+	validator, ok := AddressValidatorFor(addressType)
+	if !ok {
+		return fmt.Errorf("unsupported address type %q", addressType)
+	}
+	if err := validator.Validate(address); err != nil {
+		return fmt.Errorf("%w: %s address expected: %v", model.ErrInvalidAddress, addressType, err)
+	}
+	return nil
+}
+
+// BitcoinValidator 比特币系地址校验：Base58Check编码的P2PKH/P2SH地址，以及bech32/bech32m编码的隔离见证地址
+// 版本字节和HRP可配置，用于校验使用相同格式的其他链（如莱特币）
+type BitcoinValidator struct {
+	PubKeyHashVersion byte   // P2PKH地址版本字节，比特币主网为0x00（1开头）
+	ScriptHashVersion byte   // P2SH地址版本字节，比特币主网为0x05（3开头）
+	HRP               string // 隔离见证地址的HRP，比特币主网为bc
+}
+
+func (v BitcoinValidator) Validate(address string) error {
+	if v.HRP != "" && strings.HasPrefix(strings.ToLower(address), v.HRP+"1") {
+		_, _, err := decodeSegwitAddress(v.HRP, address)
+		return err
+	}
+
+	version, payload, err := base58CheckDecode(address)
+	if err != nil {
+		return err
+	}
+	if version != v.PubKeyHashVersion && version != v.ScriptHashVersion {
+		return fmt.Errorf("unexpected version byte 0x%02x", version)
+	}
+	if len(payload) != 20 {
+		return fmt.Errorf("unexpected payload length %d", len(payload))
+	}
+	return nil
+}
+
+// evmAddressPattern 以太坊兼容链地址：0x + 40位十六进制
+var evmAddressPattern = regexp.MustCompile(`^0x[a-fA-F0-9]{40}$`)
+
+var errEIP55Checksum = errors.New("EIP-55 checksum mismatch")
+
+// EVMValidator 以太坊兼容链地址校验；大小写混合的地址按EIP-55校验和校验，
+// 全小写或全大写的地址不携带校验和，只校验格式
+type EVMValidator struct{}
+
+func (EVMValidator) Validate(address string) error {
+	if !evmAddressPattern.MatchString(address) {
+		return errors.New("should be 0x followed by 40 hex characters")
+	}
+
+	body := address[2:]
+	if body == strings.ToLower(body) || body == strings.ToUpper(body) {
+		return nil
+	}
+	if ToChecksumAddress(address) != address {
+		return errEIP55Checksum
+	}
+	return nil
+}
+
+// ToChecksumAddress 按EIP-55把地址转换为大小写混合的校验和格式，调用方需保证地址格式正确
+func ToChecksumAddress(address string) string {
+	lower := strings.ToLower(strings.TrimPrefix(address, "0x"))
+
+	hasher := sha3.NewLegacyKeccak256()
+	hasher.Write([]byte(lower))
+	hash := hex.EncodeToString(hasher.Sum(nil))
+
+	result := []byte(lower)
+	for i, c := range result {
+		// 哈希对应位置的十六进制数字≥8时，字母转为大写
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			result[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(result)
+}
+
+// tronAddressVersion 波场主网地址的版本字节，Base58Check编码后以T开头
+const tronAddressVersion = 0x41
+
+// TronValidator 波场地址校验：Base58Check编码，版本字节0x41，载荷为20字节
+type TronValidator struct{}
+
+func (TronValidator) Validate(address string) error {
+	if len(address) != 34 || address[0] != 'T' {
+		return errors.New("should be 34 characters starting with T")
+	}
+
+	version, payload, err := base58CheckDecode(address)
+	if err != nil {
+		return err
+	}
+	if version != tronAddressVersion || len(payload) != 20 {
+		return fmt.Errorf("unexpected version byte 0x%02x", version)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package currency
+
+import (
+	"errors"
+	"testing"
+
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type addressCase struct {
+	name    string
+	address string
+	valid   bool
+}
+
+func runAddressCases(t *testing.T, validator AddressValidator, tests []addressCase) {
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.address)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestBitcoinValidator(t *testing.T) {
+	validator, ok := AddressValidatorFor(model.AddressTypeBitcoin)
+	assert.True(t, ok)
+
+	// 测试向量来自BIP173和BIP350
+	runAddressCases(t, validator, []addressCase{
+		{"P2PKH创世区块地址", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", true},
+		{"P2PKH", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", true},
+		{"P2SH", "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", true},
+		{"P2WPKH", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", true},
+		{"P2WPKH大写", "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", true},
+		{"P2WSH", "bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3", true},
+		{"P2TR使用bech32m", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", true},
+		{"Base58校验和错误", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", false},
+		{"Base58非法字符", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfN0", false},
+		{"测试网版本字节", "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", false},
+		{"波场地址", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", false},
+		{"bech32校验和错误", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", false},
+		{"大小写混合", "bc1qW508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", false},
+		{"测试网HRP", "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", false},
+		{"v1地址使用bech32校验和", "bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7k7grplx", false},
+		{"v0地址使用bech32m校验和", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh", false},
+		{"v0见证程序长度错误", "bc1qr508d6qejxtdg4y5r3zarvaryvqyzf3du", false},
+		{"空字符串", "", false},
+	})
+}
+
+func TestBitcoinValidator_CustomParams(t *testing.T) {
+	// 莱特币主网：P2PKH版本0x30（L开头），P2SH版本0x32（M开头），HRP为ltc
+	litecoin := BitcoinValidator{PubKeyHashVersion: 0x30, ScriptHashVersion: 0x32, HRP: "ltc"}
+
+	runAddressCases(t, litecoin, []addressCase{
+		{"莱特币P2PKH", "LaMT348PWRnrqeeWArpwQPbuanpXDZGEUz", true},
+		{"比特币P2PKH", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", false},
+		{"比特币bech32", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", false},
+	})
+}
+
+func TestEVMValidator(t *testing.T) {
+	validator, ok := AddressValidatorFor(model.AddressTypeEVM)
+	assert.True(t, ok)
+
+	// 测试向量来自EIP-55
+	runAddressCases(t, validator, []addressCase{
+		{"EIP-55校验和1", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true},
+		{"EIP-55校验和2", "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", true},
+		{"EIP-55校验和3", "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB", true},
+		{"EIP-55校验和4", "0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb", true},
+		{"全小写不带校验和", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", true},
+		{"全大写不带校验和", "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", true},
+		{"校验和错误", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", false},
+		{"长度不足", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", false},
+		{"缺少0x前缀", "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed00", false},
+		{"非十六进制字符", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg", false},
+	})
+
+	assert.Equal(t, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", ToChecksumAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"))
+}
+
+func TestTronValidator(t *testing.T) {
+	validator, ok := AddressValidatorFor(model.AddressTypeTron)
+	assert.True(t, ok)
+
+	runAddressCases(t, validator, []addressCase{
+		{"USDT合约地址", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", true},
+		{"普通地址", "TLa2f6VPqDgRE67v1736s7bJ8Ray5wYjU7", true},
+		{"校验和错误", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6u", false},
+		{"非T开头", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", false},
+		{"长度错误", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6", false},
+		{"以太坊地址", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", false},
+	})
+}
+
+type prefixValidator struct{ prefix string }
+
+func (v prefixValidator) Validate(address string) error {
+	if len(address) < len(v.prefix) || address[:len(v.prefix)] != v.prefix {
+		return errors.New("bad prefix")
+	}
+	return nil
+}
+
+func TestValidateAddress(t *testing.T) {
+	assert.NoError(t, ValidateAddress(model.AddressTypeEVM, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"))
+	assert.ErrorIs(t, ValidateAddress(model.AddressTypeEVM, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"), model.ErrInvalidAddress)
+	assert.ErrorIs(t, ValidateAddress(model.AddressTypeTron, ""), model.ErrInvalidAddress)
+
+	// 未注册的地址格式
+	err := ValidateAddress("solana", "anything")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, model.ErrInvalidAddress)
+	assert.False(t, IsSupportedAddressType("solana"))
+
+	// 注册新的地址格式后即可使用
+	RegisterAddressValidator("test", prefixValidator{prefix: "test_"})
+	defer func() {
+		validatorsMu.Lock()
+		delete(addressValidators, "test")
+		validatorsMu.Unlock()
+	}()
+	assert.True(t, IsSupportedAddressType("test"))
+	assert.Contains(t, SupportedAddressTypes(), "test")
+	assert.NoError(t, ValidateAddress("test", "test_abc"))
+	assert.ErrorIs(t, ValidateAddress("test", "abc"), model.ErrInvalidAddress)
+}
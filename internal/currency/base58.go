@@ -0,0 +1,84 @@
+package currency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+)
+
+// base58Alphabet 比特币使用的Base58字母表，去掉了容易混淆的0、O、I、l
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	errInvalidBase58  = errors.New("invalid base58 string")
+	errBase58Checksum = errors.New("base58 checksum mismatch")
+	errBase58TooShort = errors.New("base58check data too short")
+	base58AlphabetIdx = buildBase58Index()
+)
+
+func buildBase58Index() [256]int {
+	var idx [256]int
+	for i := range idx {
+		idx[i] = -1
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		idx[base58Alphabet[i]] = i
+	}
+	return idx
+}
+
+// base58Decode 解码Base58字符串，前导的'1'对应前导零字节
+func base58Decode(s string) ([]byte, error) {
+	if s == "" {
+		return nil, errInvalidBase58
+	}
+
+	zeros := 0
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+
+	// 以小端序累加：number = number*58 + digit
+	var number []byte
+	for i := 0; i < len(s); i++ {
+		digit := base58AlphabetIdx[s[i]]
+		if digit < 0 {
+			return nil, errInvalidBase58
+		}
+		carry := digit
+		for j := range number {
+			carry += int(number[j]) * 58
+			number[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			number = append(number, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	result := make([]byte, zeros+len(number))
+	for i, b := range number {
+		result[len(result)-1-i] = b
+	}
+	return result, nil
+}
+
+// base58CheckDecode 解码Base58Check字符串并校验末尾4字节的双SHA256校验和，返回版本字节和载荷
+func base58CheckDecode(s string) (version byte, payload []byte, err error) {
+	decoded, err := base58Decode(s)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(decoded) < 5 {
+		return 0, nil, errBase58TooShort
+	}
+
+	data, checksum := decoded[:len(decoded)-4], decoded[len(decoded)-4:]
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], checksum) {
+		return 0, nil, errBase58Checksum
+	}
+	return data[0], data[1:], nil
+}
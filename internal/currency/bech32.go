@@ -0,0 +1,169 @@
+package currency
+
+import (
+	"errors"
+	"strings"
+)
+
+// bech32Charset bech32数据部分使用的32个字符
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32编码变体：BIP173定义的bech32用于隔离见证v0，BIP350定义的bech32m用于v1及以上（如Taproot）
+const (
+	bech32Const  uint32 = 1
+	bech32mConst uint32 = 0x2bc830a3
+)
+
+type bech32Encoding int
+
+const (
+	encodingBech32 bech32Encoding = iota + 1
+	encodingBech32m
+)
+
+var (
+	errInvalidBech32    = errors.New("invalid bech32 string")
+	errBech32Checksum   = errors.New("bech32 checksum mismatch")
+	errBech32MixedCase  = errors.New("bech32 string has mixed case")
+	errInvalidWitness   = errors.New("invalid segwit witness program")
+	errWitnessEncoding  = errors.New("segwit version does not match bech32 variant")
+	errBech32WrongHRP   = errors.New("bech32 human-readable part mismatch")
+	bech32CharsetRevIdx = buildBech32Index()
+)
+
+func buildBech32Index() [128]int {
+	var idx [128]int
+	for i := range idx {
+		idx[i] = -1
+	}
+	for i := 0; i < len(bech32Charset); i++ {
+		idx[bech32Charset[i]] = i
+	}
+	return idx
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+// bech32Decode 解码bech32/bech32m字符串，返回小写的HRP、去掉校验和的5位数据和编码变体
+func bech32Decode(s string) (string, []byte, bech32Encoding, error) {
+	if len(s) < 8 || len(s) > 90 {
+		return "", nil, 0, errInvalidBech32
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 33 || s[i] > 126 {
+			return "", nil, 0, errInvalidBech32
+		}
+	}
+	lower := strings.ToLower(s)
+	if s != lower && s != strings.ToUpper(s) {
+		return "", nil, 0, errBech32MixedCase
+	}
+
+	sep := strings.LastIndexByte(lower, '1')
+	if sep < 1 || sep+7 > len(lower) {
+		return "", nil, 0, errInvalidBech32
+	}
+
+	hrp := lower[:sep]
+	data := make([]byte, 0, len(lower)-sep-1)
+	for i := sep + 1; i < len(lower); i++ {
+		v := bech32CharsetRevIdx[lower[i]]
+		if v < 0 {
+			return "", nil, 0, errInvalidBech32
+		}
+		data = append(data, byte(v))
+	}
+
+	var encoding bech32Encoding
+	switch bech32Polymod(append(bech32HRPExpand(hrp), data...)) {
+	case bech32Const:
+		encoding = encodingBech32
+	case bech32mConst:
+		encoding = encodingBech32m
+	default:
+		return "", nil, 0, errBech32Checksum
+	}
+	return hrp, data[:len(data)-6], encoding, nil
+}
+
+// convertBits 在不同位宽之间重新分组，用于把5位的bech32数据转换为8位字节
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	acc, bits := uint32(0), uint(0)
+	maxValue := uint32(1)<<toBits - 1
+	result := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, errInvalidWitness
+		}
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(acc>>bits&maxValue))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(acc<<(toBits-bits)&maxValue))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxValue != 0 {
+		return nil, errInvalidWitness
+	}
+	return result, nil
+}
+
+// decodeSegwitAddress 按BIP173/BIP350解码隔离见证地址，返回见证版本和见证程序
+func decodeSegwitAddress(hrp, address string) (byte, []byte, error) {
+	gotHRP, data, encoding, err := bech32Decode(address)
+	if err != nil {
+		return 0, nil, err
+	}
+	if gotHRP != hrp {
+		return 0, nil, errBech32WrongHRP
+	}
+	if len(data) < 1 || data[0] > 16 {
+		return 0, nil, errInvalidWitness
+	}
+
+	version := data[0]
+	program, err := convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(program) < 2 || len(program) > 40 {
+		return 0, nil, errInvalidWitness
+	}
+	if version == 0 && len(program) != 20 && len(program) != 32 {
+		return 0, nil, errInvalidWitness
+	}
+	if (version == 0 && encoding != encodingBech32) || (version != 0 && encoding != encodingBech32m) {
+		return 0, nil, errWitnessEncoding
+	}
+	return version, program, nil
+}
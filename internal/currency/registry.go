@@ -0,0 +1,360 @@
+// Package currency 提供币种注册表：从currencies和currency_networks表加载币种及网络配置并在进程内缓存，
+// 充值、提现和交易对创建统一通过注册表校验币种、网络、地址及金额限制。
+package currency
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-exchange/model"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// codePattern 币种代码格式，与交易对符号中的币种格式保持一致
+	codePattern = regexp.MustCompile(`^[A-Z]{2,10}$`)
+	// networkPattern 网络代码格式，如ERC20、TRC20、BTC
+	networkPattern = regexp.MustCompile(`^[A-Z0-9]{2,20}$`)
+)
+
+// Registry 币种注册表，缓存过期后在下次访问时从数据库重新加载
+// 返回的币种对象为缓存共享数据，调用方不能修改
+type Registry struct {
+	currencyModel model.CurrencyModel
+	networkModel  model.CurrencyNetworkModel
+	ttl           time.Duration
+
+	mu       sync.RWMutex
+	snapshot *snapshot
+	expireAt time.Time
+}
+
+// snapshot 某一时刻加载的币种和网络配置，加载后只读
+type snapshot struct {
+	currencies map[string]*model.Currency
+	networks   map[string][]*model.CurrencyNetwork // 币种代码 -> 网络配置，按网络代码排序
+}
+
+// NewRegistry 创建币种注册表，ttl为缓存有效期
+func NewRegistry(currencyModel model.CurrencyModel, networkModel model.CurrencyNetworkModel, ttl time.Duration) *Registry {
+	return &Registry{
+		currencyModel: currencyModel,
+		networkModel:  networkModel,
+		ttl:           ttl,
+	}
+}
+
+// Get 获取币种配置，币种不存在时返回ErrCurrencyNotFound
+func (r *Registry) Get(ctx context.Context, code string) (*model.Currency, error) {
+	snap, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c, ok := snap.currencies[code]
+	if !ok {
+		return nil, model.ErrCurrencyNotFound
+	}
+	return c, nil
+}
+
+// List 获取全部币种配置，按币种代码排序
+func (r *Registry) List(ctx context.Context) ([]*model.Currency, error) {
+	snap, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*model.Currency, 0, len(snap.currencies))
+	for _, c := range snap.currencies {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list, nil
+}
+
+// Networks 获取币种支持的网络配置，按网络代码排序
+func (r *Registry) Networks(ctx context.Context, code string) ([]*model.CurrencyNetwork, error) {
+	snap, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := snap.currencies[code]; !ok {
+		return nil, model.ErrCurrencyNotFound
+	}
+	return snap.networks[code], nil
+}
+
+// Resolve 获取币种及指定网络的配置；network为空时，只支持一个网络的币种使用该网络，
+// 支持多个网络的币种返回ErrNetworkRequired；币种不支持该网络时返回ErrNetworkNotSupported
+func (r *Registry) Resolve(ctx context.Context, code, network string) (*model.Currency, *model.CurrencyNetwork, error) {
+	snap, err := r.load(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, ok := snap.currencies[code]
+	if !ok {
+		return nil, nil, model.ErrCurrencyNotFound
+	}
+
+	networks := snap.networks[code]
+	if network == "" {
+		switch len(networks) {
+		case 0:
+			return nil, nil, model.ErrNetworkNotSupported
+		case 1:
+			return c, networks[0], nil
+		default:
+			return nil, nil, model.ErrNetworkRequired
+		}
+	}
+
+	for _, n := range networks {
+		if n.Network == network {
+			return c, n, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%w: %s on %s", model.ErrNetworkNotSupported, code, network)
+}
+
+// ValidateNetworkAddress 按网络的地址格式校验地址，用于与币种无关的场景（如提现地址簿、充值地址池）
+// 同一网络上各币种的地址格式相同，网络未配置给任何币种时返回ErrNetworkNotSupported
+func (r *Registry) ValidateNetworkAddress(ctx context.Context, network, address string) error {
+	snap, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, networks := range snap.networks {
+		for _, n := range networks {
+			if n.Network == network {
+				return ValidateAddress(n.AddressType, address)
+			}
+		}
+	}
+	return fmt.Errorf("%w: %s", model.ErrNetworkNotSupported, network)
+}
+
+// Invalidate 使缓存失效，币种或网络配置变更后调用
+func (r *Registry) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.snapshot = nil
+}
+
+// ValidateDeposit 校验币种和网络是否开放充值以及充值金额是否符合精度和限额
+func (r *Registry) ValidateDeposit(ctx context.Context, code, network string, amount decimal.Decimal) (*model.Currency, *model.CurrencyNetwork, error) {
+	c, n, err := r.Resolve(ctx, code, network)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.Status != model.CurrencyStatusEnabled {
+		return nil, nil, model.ErrCurrencyDisabled
+	}
+	if !c.DepositEnabled || !n.DepositEnabled {
+		return nil, nil, model.ErrDepositDisabled
+	}
+
+	if err := checkAmount(c, amount, n.MinDeposit, c.MaxDeposit, "deposit"); err != nil {
+		return nil, nil, err
+	}
+	return c, n, nil
+}
+
+// ValidateWithdraw 校验币种和网络是否开放提现、提现金额是否符合精度和限额，以及提现地址是否符合网络的地址格式
+func (r *Registry) ValidateWithdraw(ctx context.Context, code, network string, amount decimal.Decimal, address string) (*model.Currency, *model.CurrencyNetwork, error) {
+	c, n, err := r.Resolve(ctx, code, network)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.Status != model.CurrencyStatusEnabled {
+		return nil, nil, model.ErrCurrencyDisabled
+	}
+	if !c.WithdrawEnabled || !n.WithdrawEnabled {
+		return nil, nil, model.ErrWithdrawDisabled
+	}
+
+	if err := checkAmount(c, amount, n.MinWithdraw, c.MaxWithdraw, "withdraw"); err != nil {
+		return nil, nil, err
+	}
+	if err := ValidateAddress(n.AddressType, address); err != nil {
+		return nil, nil, err
+	}
+	return c, n, nil
+}
+
+// ValidateTransfer 校验币种是否可用于站内转账以及转账金额是否符合精度，站内转账不经过链上，不受充提网络开关和限额约束
+func (r *Registry) ValidateTransfer(ctx context.Context, code string, amount decimal.Decimal) (*model.Currency, error) {
+	c, err := r.Get(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != model.CurrencyStatusEnabled {
+		return nil, model.ErrCurrencyDisabled
+	}
+
+	if err := checkAmount(c, amount, "0", "0", "transfer"); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ValidateTradable 校验币种是否可用于交易对
+func (r *Registry) ValidateTradable(ctx context.Context, code string) error {
+	c, err := r.Get(ctx, code)
+	if err != nil {
+		return err
+	}
+	if c.Status != model.CurrencyStatusEnabled {
+		return model.ErrCurrencyDisabled
+	}
+	return nil
+}
+
+// ValidateConfig 校验币种配置是否合法，用于管理后台创建和修改币种
+func ValidateConfig(c *model.Currency) error {
+	if !codePattern.MatchString(c.Code) {
+		return fmt.Errorf("invalid currency code %q, should be 2-10 uppercase letters", c.Code)
+	}
+	if c.Name == "" {
+		return fmt.Errorf("currency name cannot be empty")
+	}
+	if c.Precision < 0 || c.Precision > 18 {
+		return fmt.Errorf("precision must be between 0 and 18")
+	}
+	if c.Status != model.CurrencyStatusEnabled && c.Status != model.CurrencyStatusDisabled {
+		return fmt.Errorf("invalid status, must be 1 (enabled) or 2 (disabled)")
+	}
+
+	for _, field := range []struct{ name, value string }{
+		{"max_deposit", c.MaxDeposit},
+		{"max_withdraw", c.MaxWithdraw},
+	} {
+		if _, err := parseNonNegative(field.name, field.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateNetworkConfig 校验币种网络配置是否合法，用于管理后台创建和修改网络
+func ValidateNetworkConfig(n *model.CurrencyNetwork) error {
+	if !networkPattern.MatchString(n.Network) {
+		return fmt.Errorf("invalid network code %q, should be 2-20 uppercase letters or digits", n.Network)
+	}
+	if !IsSupportedAddressType(n.AddressType) {
+		return fmt.Errorf("unsupported address type %q, should be one of: %s", n.AddressType, strings.Join(SupportedAddressTypes(), ", "))
+	}
+	if n.Confirmations < 0 {
+		return fmt.Errorf("confirmations cannot be negative")
+	}
+
+	for _, field := range []struct{ name, value string }{
+		{"withdraw_fee_fixed", n.WithdrawFeeFixed},
+		{"min_deposit", n.MinDeposit},
+		{"min_withdraw", n.MinWithdraw},
+	} {
+		if _, err := parseNonNegative(field.name, field.value); err != nil {
+			return err
+		}
+	}
+
+	rate, err := parseNonNegative("withdraw_fee_rate", n.WithdrawFeeRate)
+	if err != nil {
+		return err
+	}
+	if rate.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return fmt.Errorf("withdraw_fee_rate must be less than 1")
+	}
+
+	return nil
+}
+
+// parseNonNegative 解析非负的金额配置
+func parseNonNegative(field, value string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid %s: %s", field, value)
+	}
+	if d.IsNegative() {
+		return decimal.Zero, fmt.Errorf("%s cannot be negative", field)
+	}
+	return d, nil
+}
+
+// load 返回缓存的配置快照，缓存为空或已过期时重新加载
+func (r *Registry) load(ctx context.Context) (*snapshot, error) {
+	r.mu.RLock()
+	if r.snapshot != nil && time.Now().Before(r.expireAt) {
+		snap := r.snapshot
+		r.mu.RUnlock()
+		return snap, nil
+	}
+	r.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 等待写锁期间其他协程可能已经完成加载
+	if r.snapshot != nil && time.Now().Before(r.expireAt) {
+		return r.snapshot, nil
+	}
+
+	currencies, err := r.currencyModel.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load currencies: %w", err)
+	}
+	networks, err := r.networkModel.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load currency networks: %w", err)
+	}
+
+	snap := &snapshot{
+		currencies: make(map[string]*model.Currency, len(currencies)),
+		networks:   make(map[string][]*model.CurrencyNetwork, len(currencies)),
+	}
+	for _, c := range currencies {
+		snap.currencies[c.Code] = c
+	}
+	for _, n := range networks {
+		snap.networks[n.Currency] = append(snap.networks[n.Currency], n)
+	}
+	for _, list := range snap.networks {
+		sort.Slice(list, func(i, j int) bool { return list[i].Network < list[j].Network })
+	}
+
+	r.snapshot = snap
+	r.expireAt = time.Now().Add(r.ttl)
+	return snap, nil
+}
+
+// checkAmount 校验金额为正数、不超过币种精度且在[min, max]范围内，max为0表示不限制
+func checkAmount(c *model.Currency, amount decimal.Decimal, minValue, maxValue string, action string) error {
+	if !amount.IsPositive() {
+		return model.ErrInvalidAmount
+	}
+
+	if !amount.Equal(amount.Truncate(int32(c.Precision))) {
+		return fmt.Errorf("amount precision exceeds %d decimal places", c.Precision)
+	}
+
+	minAmount, err := decimal.NewFromString(minValue)
+	if err == nil && amount.LessThan(minAmount) {
+		return fmt.Errorf("amount below minimum %s limit: %s", action, minAmount.String())
+	}
+
+	maxAmount, err := decimal.NewFromString(maxValue)
+	if err == nil && maxAmount.IsPositive() && amount.GreaterThan(maxAmount) {
+		return fmt.Errorf("amount exceeds maximum %s limit: %s", action, maxAmount.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,304 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-exchange/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingCurrencyModel 记录FindAll调用次数，用于验证缓存
+type countingCurrencyModel struct {
+	model.CurrencyModel
+	currencies []*model.Currency
+	err        error
+	calls      int
+}
+
+func (m *countingCurrencyModel) FindAll(ctx context.Context) ([]*model.Currency, error) {
+	m.calls++
+	return m.currencies, m.err
+}
+
+type countingNetworkModel struct {
+	model.CurrencyNetworkModel
+	networks []*model.CurrencyNetwork
+	calls    int
+}
+
+func (m *countingNetworkModel) FindAll(ctx context.Context) ([]*model.CurrencyNetwork, error) {
+	m.calls++
+	return m.networks, nil
+}
+
+func testCurrencies() []*model.Currency {
+	return []*model.Currency{
+		{
+			Code: "USDT", Name: "Tether USD", Precision: 6, DepositEnabled: true, WithdrawEnabled: true,
+			MaxDeposit: "0", MaxWithdraw: "100000", Status: model.CurrencyStatusEnabled,
+		},
+		{
+			Code: "BTC", Name: "Bitcoin", Precision: 8, DepositEnabled: true, WithdrawEnabled: false,
+			MaxDeposit: "100", MaxWithdraw: "10", Status: model.CurrencyStatusEnabled,
+		},
+		{
+			Code: "XRP", Name: "XRP", Precision: 6, DepositEnabled: true, WithdrawEnabled: true,
+			MaxDeposit: "0", MaxWithdraw: "0", Status: model.CurrencyStatusDisabled,
+		},
+		{
+			Code: "DOT", Name: "Polkadot", Precision: 8, DepositEnabled: true, WithdrawEnabled: true,
+			MaxDeposit: "0", MaxWithdraw: "0", Status: model.CurrencyStatusEnabled,
+		},
+	}
+}
+
+func testNetworks() []*model.CurrencyNetwork {
+	return []*model.CurrencyNetwork{
+		{
+			Currency: "USDT", Network: "TRC20", AddressType: model.AddressTypeTron, DepositEnabled: true, WithdrawEnabled: false,
+			WithdrawFeeFixed: "1", WithdrawFeeRate: "0", MinDeposit: "1", MinWithdraw: "10", Confirmations: 20,
+		},
+		{
+			Currency: "USDT", Network: "ERC20", AddressType: model.AddressTypeEVM, DepositEnabled: true, WithdrawEnabled: true,
+			WithdrawFeeFixed: "1", WithdrawFeeRate: "0.001", MinDeposit: "1", MinWithdraw: "10", Confirmations: 12,
+		},
+		{
+			Currency: "BTC", Network: "BTC", AddressType: model.AddressTypeBitcoin, DepositEnabled: true, WithdrawEnabled: true,
+			WithdrawFeeFixed: "0.0005", WithdrawFeeRate: "0", MinDeposit: "0.0001", MinWithdraw: "0.001", Confirmations: 2,
+		},
+		{
+			Currency: "XRP", Network: "XRP", AddressType: model.AddressTypeBitcoin, DepositEnabled: true, WithdrawEnabled: true,
+			WithdrawFeeFixed: "0", WithdrawFeeRate: "0", MinDeposit: "0", MinWithdraw: "0",
+		},
+	}
+}
+
+func newTestRegistry() *Registry {
+	return NewRegistry(&countingCurrencyModel{currencies: testCurrencies()}, &countingNetworkModel{networks: testNetworks()}, time.Minute)
+}
+
+func TestRegistry_Cache(t *testing.T) {
+	ctx := context.Background()
+	currencyModel := &countingCurrencyModel{currencies: testCurrencies()}
+	networkModel := &countingNetworkModel{networks: testNetworks()}
+	registry := NewRegistry(currencyModel, networkModel, time.Minute)
+
+	c, err := registry.Get(ctx, "BTC")
+	assert.NoError(t, err)
+	assert.Equal(t, "Bitcoin", c.Name)
+
+	_, err = registry.Get(ctx, "DOGE")
+	assert.ErrorIs(t, err, model.ErrCurrencyNotFound)
+
+	list, err := registry.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BTC", "DOT", "USDT", "XRP"}, []string{list[0].Code, list[1].Code, list[2].Code, list[3].Code})
+	assert.Equal(t, 1, currencyModel.calls)
+	assert.Equal(t, 1, networkModel.calls)
+
+	// 网络按代码排序
+	networks, err := registry.Networks(ctx, "USDT")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ERC20", "TRC20"}, []string{networks[0].Network, networks[1].Network})
+	_, err = registry.Networks(ctx, "DOGE")
+	assert.ErrorIs(t, err, model.ErrCurrencyNotFound)
+
+	// 失效后重新加载
+	registry.Invalidate()
+	_, err = registry.Get(ctx, "BTC")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, currencyModel.calls)
+
+	// 缓存过期后重新加载
+	expired := NewRegistry(currencyModel, networkModel, 0)
+	_, _ = expired.Get(ctx, "BTC")
+	_, _ = expired.Get(ctx, "BTC")
+	assert.Equal(t, 4, currencyModel.calls)
+
+	// 加载失败不缓存
+	failing := NewRegistry(&countingCurrencyModel{err: errors.New("db down")}, networkModel, time.Minute)
+	_, err = failing.Get(ctx, "BTC")
+	assert.Error(t, err)
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	ctx := context.Background()
+	registry := newTestRegistry()
+
+	// 只有一个网络时可以不指定
+	_, n, err := registry.Resolve(ctx, "BTC", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "BTC", n.Network)
+
+	_, n, err = registry.Resolve(ctx, "USDT", "TRC20")
+	assert.NoError(t, err)
+	assert.Equal(t, model.AddressTypeTron, n.AddressType)
+
+	_, _, err = registry.Resolve(ctx, "USDT", "")
+	assert.ErrorIs(t, err, model.ErrNetworkRequired)
+
+	_, _, err = registry.Resolve(ctx, "USDT", "BEP20")
+	assert.ErrorIs(t, err, model.ErrNetworkNotSupported)
+
+	// 没有配置任何网络的币种
+	_, _, err = registry.Resolve(ctx, "DOT", "")
+	assert.ErrorIs(t, err, model.ErrNetworkNotSupported)
+
+	_, _, err = registry.Resolve(ctx, "DOGE", "BTC")
+	assert.ErrorIs(t, err, model.ErrCurrencyNotFound)
+}
+
+func TestRegistry_ValidateNetworkAddress(t *testing.T) {
+	ctx := context.Background()
+	registry := newTestRegistry()
+
+	assert.NoError(t, registry.ValidateNetworkAddress(ctx, "ERC20", "0x742d35Cc6634C0532925a3b844Bc454e4438f44e"))
+	assert.NoError(t, registry.ValidateNetworkAddress(ctx, "BTC", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"))
+	assert.ErrorIs(t, registry.ValidateNetworkAddress(ctx, "ERC20", "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"), model.ErrInvalidAddress)
+	assert.ErrorIs(t, registry.ValidateNetworkAddress(ctx, "SOL", "anything"), model.ErrNetworkNotSupported)
+}
+
+func TestRegistry_ValidateDepositAndWithdraw(t *testing.T) {
+	ctx := context.Background()
+	registry := newTestRegistry()
+
+	const (
+		evmAddress  = "0x742d35Cc6634C0532925a3b844Bc454e4438f44e"
+		tronAddress = "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"
+		btcAddress  = "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"
+	)
+
+	tests := []struct {
+		name     string
+		withdraw bool
+		code     string
+		network  string
+		amount   string
+		address  string
+		wantErr  error
+		errMsg   string
+	}{
+		{name: "有效充值", code: "BTC", amount: "1"},
+		{name: "充值不限最大金额", code: "USDT", network: "ERC20", amount: "100000000"},
+		{name: "充值低于最小金额", code: "BTC", amount: "0.00001", errMsg: "below minimum deposit"},
+		{name: "充值超过最大金额", code: "BTC", amount: "101", errMsg: "exceeds maximum deposit"},
+		{name: "充值精度超过币种精度", code: "USDT", network: "TRC20", amount: "1.0000001", errMsg: "precision exceeds 6"},
+		{name: "末尾零不算超精度", code: "USDT", network: "TRC20", amount: "1.00000000"},
+		{name: "充值金额为0", code: "BTC", amount: "0", wantErr: model.ErrInvalidAmount},
+		{name: "多网络币种未指定网络", code: "USDT", amount: "1", wantErr: model.ErrNetworkRequired},
+		{name: "不支持的网络", code: "USDT", network: "BEP20", amount: "1", wantErr: model.ErrNetworkNotSupported},
+		{name: "停用币种", code: "XRP", amount: "1", wantErr: model.ErrCurrencyDisabled},
+		{name: "未登记币种", code: "DOGE", amount: "1", wantErr: model.ErrCurrencyNotFound},
+		{name: "有效提现", withdraw: true, code: "USDT", network: "ERC20", amount: "100", address: evmAddress},
+		{name: "币种未开放提现", withdraw: true, code: "BTC", amount: "1", address: btcAddress, wantErr: model.ErrWithdrawDisabled},
+		{name: "网络未开放提现", withdraw: true, code: "USDT", network: "TRC20", amount: "100", address: tronAddress, wantErr: model.ErrWithdrawDisabled},
+		{name: "提现低于最小金额", withdraw: true, code: "USDT", network: "ERC20", amount: "5", address: evmAddress, errMsg: "below minimum withdraw"},
+		{name: "提现超过最大金额", withdraw: true, code: "USDT", network: "ERC20", amount: "100001", address: evmAddress, errMsg: "exceeds maximum withdraw"},
+		{name: "地址与网络不匹配", withdraw: true, code: "USDT", network: "ERC20", amount: "100", address: tronAddress, wantErr: model.ErrInvalidAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount := decimal.RequireFromString(tt.amount)
+			var err error
+			if tt.withdraw {
+				_, _, err = registry.ValidateWithdraw(ctx, tt.code, tt.network, amount, tt.address)
+			} else {
+				_, _, err = registry.ValidateDeposit(ctx, tt.code, tt.network, amount)
+			}
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.errMsg != "":
+				assert.ErrorContains(t, err, tt.errMsg)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.NoError(t, registry.ValidateTradable(ctx, "BTC"))
+	assert.ErrorIs(t, registry.ValidateTradable(ctx, "XRP"), model.ErrCurrencyDisabled)
+}
+
+func TestRegistry_ValidateTransfer(t *testing.T) {
+	ctx := context.Background()
+	registry := newTestRegistry()
+
+	// 站内转账不受提现开关和单笔限额约束
+	_, err := registry.ValidateTransfer(ctx, "BTC", decimal.RequireFromString("20"))
+	assert.NoError(t, err)
+
+	_, err = registry.ValidateTransfer(ctx, "USDT", decimal.RequireFromString("1.0000001"))
+	assert.ErrorContains(t, err, "precision exceeds 6")
+	_, err = registry.ValidateTransfer(ctx, "USDT", decimal.Zero)
+	assert.ErrorIs(t, err, model.ErrInvalidAmount)
+	_, err = registry.ValidateTransfer(ctx, "XRP", decimal.RequireFromString("1"))
+	assert.ErrorIs(t, err, model.ErrCurrencyDisabled)
+}
+
+func TestCurrencyNetwork_WithdrawFee(t *testing.T) {
+	erc20 := testNetworks()[1]
+
+	// 1 + 1234.5678 × 0.001 = 2.2345678，按6位精度向上取整
+	assert.Equal(t, "2.234568", erc20.WithdrawFee(decimal.RequireFromString("1234.5678"), 6).String())
+	assert.Equal(t, "1", testNetworks()[0].WithdrawFee(decimal.RequireFromString("100"), 6).String())
+}
+
+func TestValidateConfig(t *testing.T) {
+	valid := func() *model.Currency { return testCurrencies()[0] }
+
+	assert.NoError(t, ValidateConfig(valid()))
+
+	tests := []struct {
+		name   string
+		modify func(c *model.Currency)
+	}{
+		{"小写币种代码", func(c *model.Currency) { c.Code = "usdt" }},
+		{"名称为空", func(c *model.Currency) { c.Name = "" }},
+		{"精度超过18", func(c *model.Currency) { c.Precision = 19 }},
+		{"无效状态", func(c *model.Currency) { c.Status = 3 }},
+		{"无效最大充值", func(c *model.Currency) { c.MaxDeposit = "abc" }},
+		{"负数最大提现", func(c *model.Currency) { c.MaxWithdraw = "-1" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.modify(c)
+			assert.Error(t, ValidateConfig(c))
+		})
+	}
+}
+
+func TestValidateNetworkConfig(t *testing.T) {
+	valid := func() *model.CurrencyNetwork { return testNetworks()[1] }
+
+	assert.NoError(t, ValidateNetworkConfig(valid()))
+
+	tests := []struct {
+		name   string
+		modify func(n *model.CurrencyNetwork)
+	}{
+		{"小写网络代码", func(n *model.CurrencyNetwork) { n.Network = "erc20" }},
+		{"不支持的地址格式", func(n *model.CurrencyNetwork) { n.AddressType = "solana" }},
+		{"负数确认数", func(n *model.CurrencyNetwork) { n.Confirmations = -1 }},
+		{"无效手续费", func(n *model.CurrencyNetwork) { n.WithdrawFeeFixed = "abc" }},
+		{"负数最小充值", func(n *model.CurrencyNetwork) { n.MinDeposit = "-1" }},
+		{"比例手续费不小于1", func(n *model.CurrencyNetwork) { n.WithdrawFeeRate = "1" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := valid()
+			tt.modify(n)
+			assert.Error(t, ValidateNetworkConfig(n))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package apikey
+
+import (
+	"net/http"
+
+	"crypto-exchange/internal/logic/apikey"
+	"crypto-exchange/internal/svc"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func GetApiKeysHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := apikey.NewGetApiKeysLogic(r.Context(), svcCtx)
+		resp, err := l.GetApiKeys()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
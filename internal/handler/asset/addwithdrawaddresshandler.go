@@ -0,0 +1,28 @@
+package asset
+
+import (
+	"net/http"
+
+	"crypto-exchange/internal/logic/asset"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func AddWithdrawAddressHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.AddWithdrawAddressRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		l := asset.NewAddWithdrawAddressLogic(r.Context(), svcCtx)
+		resp, err := l.AddWithdrawAddress(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -9,16 +9,16 @@ import (
 	"github.com/zeromicro/go-zero/rest/httpx"
 )
 
-func DepositHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+func TransferHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var req types.DepositRequest
+		var req types.TransferRequest
 		if err := httpx.Parse(r, &req); err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
 			return
 		}
 
-		l := asset.NewDepositLogic(r.Context(), svcCtx)
-		resp, err := l.Deposit(&req)
+		l := asset.NewTransferLogic(r.Context(), svcCtx)
+		resp, err := l.Transfer(&req)
 		if err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
 		} else {
@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"net/http"
+
+	"crypto-exchange/internal/logic/auth"
+	"crypto-exchange/internal/svc"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func ResendVerificationHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := auth.NewResendVerificationLogic(r.Context(), svcCtx)
+		resp, err := l.ResendVerification()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
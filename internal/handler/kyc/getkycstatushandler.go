@@ -0,0 +1,21 @@
+package kyc
+
+import (
+	"net/http"
+
+	"crypto-exchange/internal/logic/kyc"
+	"crypto-exchange/internal/svc"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func GetKycStatusHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := kyc.NewGetKycStatusLogic(r.Context(), svcCtx)
+		resp, err := l.GetKycStatus()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package kyc
+
+import (
+	"mime/multipart"
+	"net/http"
+
+	"crypto-exchange/internal/logic/kyc"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func SubmitKycHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.SubmitKycRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		}
+
+		// 解析表单时已读取multipart请求体，证件文件在documents字段中
+		var files []*multipart.FileHeader
+		if r.MultipartForm != nil {
+			files = r.MultipartForm.File["documents"]
+		}
+
+		l := kyc.NewSubmitKycLogic(r.Context(), svcCtx)
+		resp, err := l.SubmitKyc(&req, files)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package reserves
+
+import (
+	"net/http"
+
+	"crypto-exchange/internal/logic/reserves"
+	"crypto-exchange/internal/svc"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func GetReserveRootsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := reserves.NewGetReserveRootsLogic(r.Context(), svcCtx)
+		resp, err := l.GetReserveRoots()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
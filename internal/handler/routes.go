@@ -5,10 +5,16 @@ import (
 	"net/http"
 
 	admin "crypto-exchange/internal/handler/admin"
+	apikey "crypto-exchange/internal/handler/apikey"
 	asset "crypto-exchange/internal/handler/asset"
 	auth "crypto-exchange/internal/handler/auth"
+	kyc "crypto-exchange/internal/handler/kyc"
 	market "crypto-exchange/internal/handler/market"
+	reserves "crypto-exchange/internal/handler/reserves"
+	sessions "crypto-exchange/internal/handler/sessions"
+	subaccount "crypto-exchange/internal/handler/subaccount"
 	trading "crypto-exchange/internal/handler/trading"
+	twofactor "crypto-exchange/internal/handler/twofactor"
 	user "crypto-exchange/internal/handler/user"
 	"crypto-exchange/internal/svc"
 
@@ -17,152 +23,581 @@ import (
 
 func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 	server.AddRoutes(
-		[]rest.Route{
-			{
-				Method:  http.MethodPost,
-				Path:    "/register",
-				Handler: auth.RegisterHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodPost,
-				Path:    "/login",
-				Handler: auth.LoginHandler(serverCtx),
-			},
-		},
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.RateLimit},
+			[]rest.Route{
+				{
+					Method:  http.MethodPost,
+					Path:    "/register",
+					Handler: auth.RegisterHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/login",
+					Handler: auth.LoginHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/login/2fa",
+					Handler: auth.LoginTwoFactorHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/refresh",
+					Handler: auth.RefreshTokenHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/verify-email",
+					Handler: auth.VerifyEmailHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/forgot-password",
+					Handler: auth.ForgotPasswordHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/reset-password",
+					Handler: auth.ResetPasswordHandler(serverCtx),
+				},
+			}...,
+		),
 		rest.WithPrefix("/api/v1/auth"),
 	)
 
 	server.AddRoutes(
-		[]rest.Route{
-			{
-				Method:  http.MethodGet,
-				Path:    "/profile",
-				Handler: user.ProfileHandler(serverCtx),
-			},
-		},
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.SessionAuth, serverCtx.RateLimit},
+			[]rest.Route{
+				{
+					Method:  http.MethodPost,
+					Path:    "/resend-verification",
+					Handler: auth.ResendVerificationHandler(serverCtx),
+				},
+			}...,
+		),
 		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithPrefix("/api/v1/auth"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.ApiKeyAuth, serverCtx.SessionAuth, serverCtx.RateLimit},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/profile",
+					Handler: user.ProfileHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/security-events",
+					Handler: user.GetSecurityEventsHandler(serverCtx),
+				},
+			}...,
+		),
 		rest.WithPrefix("/api/v1/user"),
 	)
 
 	server.AddRoutes(
-		[]rest.Route{
-			{
-				Method:  http.MethodPost,
-				Path:    "/orders",
-				Handler: trading.CreateOrderHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodDelete,
-				Path:    "/orders",
-				Handler: trading.CancelOrderHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/orders",
-				Handler: trading.QueryOrdersHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/orders/:id",
-				Handler: trading.GetOrderHandler(serverCtx),
-			},
-		},
-		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.ApiKeyAuth, serverCtx.SessionAuth, serverCtx.RateLimit},
+			[]rest.Route{
+				{
+					Method:  http.MethodPost,
+					Path:    "/orders",
+					Handler: trading.CreateOrderHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/orders",
+					Handler: trading.CancelOrderHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/orders",
+					Handler: trading.QueryOrdersHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/orders/:id",
+					Handler: trading.GetOrderHandler(serverCtx),
+				},
+			}...,
+		),
 		rest.WithPrefix("/api/v1/trading"),
 	)
 
 	server.AddRoutes(
-		[]rest.Route{
-			{
-				Method:  http.MethodGet,
-				Path:    "/balances",
-				Handler: asset.GetBalancesHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodPost,
-				Path:    "/deposit",
-				Handler: asset.DepositHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodPost,
-				Path:    "/withdraw",
-				Handler: asset.WithdrawHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/history",
-				Handler: asset.GetAssetHistoryHandler(serverCtx),
-			},
-		},
-		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.ApiKeyAuth, serverCtx.SessionAuth, serverCtx.RateLimit},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/balances",
+					Handler: asset.GetBalancesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/withdraw",
+					Handler: asset.WithdrawHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/withdraw/confirm",
+					Handler: asset.ConfirmWithdrawHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/withdraw-addresses",
+					Handler: asset.GetWithdrawAddressesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/withdraw-addresses",
+					Handler: asset.AddWithdrawAddressHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/withdraw-addresses/:id",
+					Handler: asset.DeleteWithdrawAddressHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/withdraw-settings",
+					Handler: asset.UpdateWithdrawSettingsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/transfer",
+					Handler: asset.TransferHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/history",
+					Handler: asset.GetAssetHistoryHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/statement",
+					Handler: asset.GetAccountStatementHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/deposit-address",
+					Handler: asset.GetDepositAddressHandler(serverCtx),
+				},
+			}...,
+		),
 		rest.WithPrefix("/api/v1/asset"),
 	)
 
 	server.AddRoutes(
-		[]rest.Route{
-			{
-				Method:  http.MethodGet,
-				Path:    "/trading-pairs",
-				Handler: market.GetTradingPairsHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/trading-pairs/:symbol",
-				Handler: market.GetTradingPairHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/depth",
-				Handler: market.GetOrderBookHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/klines",
-				Handler: market.GetKlinesHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/trades",
-				Handler: market.GetTradeHistoryHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/ticker/:symbol",
-				Handler: market.GetTickerHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/tickers",
-				Handler: market.GetAllTickersHandler(serverCtx),
-			},
-		},
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.ApiKeyAuth, serverCtx.SessionAuth, serverCtx.RateLimit},
+			[]rest.Route{
+				{
+					Method:  http.MethodPost,
+					Path:    "/",
+					Handler: subaccount.CreateSubAccountHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/",
+					Handler: subaccount.GetSubAccountsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/:id/status",
+					Handler: subaccount.UpdateSubAccountStatusHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/:id/freeze",
+					Handler: subaccount.FreezeSubAccountHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/:id/freeze",
+					Handler: subaccount.UnfreezeSubAccountHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/transfer",
+					Handler: subaccount.SubAccountTransferHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/balances",
+					Handler: subaccount.GetSubAccountBalancesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/orders",
+					Handler: subaccount.GetSubAccountOrdersHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/v1/sub-accounts"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.SessionAuth, serverCtx.RateLimit},
+			[]rest.Route{
+				{
+					Method:  http.MethodPost,
+					Path:    "/",
+					Handler: apikey.CreateApiKeyHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/",
+					Handler: apikey.GetApiKeysHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/:id",
+					Handler: apikey.DeleteApiKeyHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithPrefix("/api/v1/api-keys"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.RateLimit},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/trading-pairs",
+					Handler: market.GetTradingPairsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/trading-pairs/:symbol",
+					Handler: market.GetTradingPairHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/depth",
+					Handler: market.GetOrderBookHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/klines",
+					Handler: market.GetKlinesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/trades",
+					Handler: market.GetTradeHistoryHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/ticker/:symbol",
+					Handler: market.GetTickerHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/tickers",
+					Handler: market.GetAllTickersHandler(serverCtx),
+				},
+			}...,
+		),
 		rest.WithPrefix("/api/v1/market"),
 	)
 
 	server.AddRoutes(
-		[]rest.Route{
-			{
-				Method:  http.MethodPost,
-				Path:    "/trading-pairs",
-				Handler: admin.CreateTradingPairHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodPut,
-				Path:    "/trading-pairs/:symbol",
-				Handler: admin.UpdateTradingPairHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/trading-pairs/stats",
-				Handler: admin.GetTradingPairStatsHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/trading-pairs",
-				Handler: admin.GetAllTradingPairsHandler(serverCtx),
-			},
-		},
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.SessionAuth, serverCtx.AdminAuth, serverCtx.RateLimit},
+			[]rest.Route{
+				{
+					Method:  http.MethodPost,
+					Path:    "/trading-pairs",
+					Handler: admin.CreateTradingPairHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/trading-pairs/:symbol",
+					Handler: admin.UpdateTradingPairHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/trading-pairs/stats",
+					Handler: admin.GetTradingPairStatsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/trading-pairs",
+					Handler: admin.GetAllTradingPairsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/trading-pairs/:symbol/status",
+					Handler: admin.ChangeTradingPairStatusHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/trading-pairs/:symbol/status-changes",
+					Handler: admin.GetTradingPairStatusChangesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/trading-pairs/status-changes/:id",
+					Handler: admin.CancelTradingPairStatusChangeHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/currencies",
+					Handler: admin.GetCurrenciesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/currencies",
+					Handler: admin.CreateCurrencyHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/currencies/:code",
+					Handler: admin.UpdateCurrencyHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/currencies/:code",
+					Handler: admin.DeleteCurrencyHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/currencies/:code/networks",
+					Handler: admin.CreateCurrencyNetworkHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPut,
+					Path:    "/currencies/:code/networks/:network",
+					Handler: admin.UpdateCurrencyNetworkHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/currencies/:code/networks/:network",
+					Handler: admin.DeleteCurrencyNetworkHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/withdrawals",
+					Handler: admin.GetWithdrawalsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/withdrawals/:transaction_id/approve",
+					Handler: admin.ApproveWithdrawalHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/withdrawals/:transaction_id/reject",
+					Handler: admin.RejectWithdrawalHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/withdrawals/:transaction_id/audit-logs",
+					Handler: admin.GetWithdrawalAuditLogsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/deposit-address-pool",
+					Handler: admin.ImportDepositAddressesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/users/:id/roles",
+					Handler: admin.GetUserRolesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/users/:id/roles",
+					Handler: admin.GrantRoleHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/users/:id/roles/:role",
+					Handler: admin.RevokeRoleHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/role-audit-logs",
+					Handler: admin.GetRoleAuditLogsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/kyc/submissions",
+					Handler: admin.GetKycSubmissionsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/kyc/submissions/:id",
+					Handler: admin.GetKycSubmissionHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/kyc/submissions/:id/documents/:index",
+					Handler: admin.GetKycDocumentHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/kyc/submissions/:id/approve",
+					Handler: admin.ApproveKycSubmissionHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/kyc/submissions/:id/reject",
+					Handler: admin.RejectKycSubmissionHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/kyc/users/:id/level",
+					Handler: admin.SetVerificationLevelHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/kyc/users/:id/level-changes",
+					Handler: admin.GetVerificationLevelChangesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/restrictions/users/:id",
+					Handler: admin.GetUserRestrictionsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/restrictions/users/:id",
+					Handler: admin.ApplyRestrictionHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/restrictions/users/:id/:type",
+					Handler: admin.LiftRestrictionHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/restrictions/audit-logs",
+					Handler: admin.GetRestrictionAuditLogsHandler(serverCtx),
+				},
+			}...,
+		),
 		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
 		rest.WithPrefix("/api/v1/admin"),
 	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.SessionAuth, serverCtx.RateLimit},
+			[]rest.Route{
+				{
+					Method:  http.MethodPost,
+					Path:    "/submissions",
+					Handler: kyc.SubmitKycHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/status",
+					Handler: kyc.GetKycStatusHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithPrefix("/api/v1/kyc"),
+		rest.WithMaxBytes(20971520),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.RateLimit},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/roots",
+					Handler: reserves.GetReserveRootsHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/v1/reserves"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.SessionAuth, serverCtx.RateLimit},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/proof",
+					Handler: reserves.GetReserveProofHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithPrefix("/api/v1/reserves"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.SessionAuth, serverCtx.RateLimit},
+			[]rest.Route{
+				{
+					Method:  http.MethodPost,
+					Path:    "/enroll",
+					Handler: twofactor.EnrollTwoFactorHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/verify",
+					Handler: twofactor.VerifyTwoFactorHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/disable",
+					Handler: twofactor.DisableTwoFactorHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/status",
+					Handler: twofactor.GetTwoFactorStatusHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithPrefix("/api/v1/2fa"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.SessionAuth, serverCtx.RateLimit},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/",
+					Handler: sessions.GetSessionsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/:id",
+					Handler: sessions.RevokeSessionHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/logout",
+					Handler: sessions.LogoutHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/logout-all",
+					Handler: sessions.LogoutAllHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithJwt(serverCtx.Config.Auth.AccessSecret),
+		rest.WithPrefix("/api/v1/sessions"),
+	)
 }
@@ -0,0 +1,21 @@
+package sessions
+
+import (
+	"net/http"
+
+	"crypto-exchange/internal/logic/sessions"
+	"crypto-exchange/internal/svc"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func LogoutAllHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := sessions.NewLogoutAllLogic(r.Context(), svcCtx)
+		resp, err := l.LogoutAll()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package subaccount
+
+import (
+	"net/http"
+
+	"crypto-exchange/internal/logic/subaccount"
+	"crypto-exchange/internal/svc"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func GetSubAccountBalancesHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := subaccount.NewGetSubAccountBalancesLogic(r.Context(), svcCtx)
+		resp, err := l.GetSubAccountBalances()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
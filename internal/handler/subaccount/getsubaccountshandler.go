@@ -0,0 +1,21 @@
+package subaccount
+
+import (
+	"net/http"
+
+	"crypto-exchange/internal/logic/subaccount"
+	"crypto-exchange/internal/svc"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func GetSubAccountsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := subaccount.NewGetSubAccountsLogic(r.Context(), svcCtx)
+		resp, err := l.GetSubAccounts()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package twofactor
+
+import (
+	"net/http"
+
+	"crypto-exchange/internal/logic/twofactor"
+	"crypto-exchange/internal/svc"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+func EnrollTwoFactorHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := twofactor.NewEnrollTwoFactorLogic(r.Context(), svcCtx)
+		resp, err := l.EnrollTwoFactor()
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ApproveKycSubmissionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewApproveKycSubmissionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ApproveKycSubmissionLogic {
+	return &ApproveKycSubmissionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ApproveKycSubmission 审核通过认证申请，用户认证等级的提升和变更记录在同一事务中写入
+func (l *ApproveKycSubmissionLogic) ApproveKycSubmission(req *types.ReviewKycRequest) (resp *types.KycSubmission, err error) {
+	reviewerID, submission, err := checkKycReview(l.ctx, l.svcCtx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	change, err := l.svcCtx.KycSubmissionModel.Approve(l.ctx, submission.ID, reviewerID, reason, time.Now())
+	if err != nil {
+		if errors.Is(err, model.ErrKycSubmissionReviewed) {
+			return nil, err
+		}
+		l.Errorf("Failed to approve kyc submission %d: %v", submission.ID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	if change != nil {
+		l.Infof("User %d approved kyc submission %d, user %d verification level %d -> %d",
+			reviewerID, submission.ID, submission.UserID, change.FromLevel, change.ToLevel)
+	} else {
+		l.Infof("User %d approved kyc submission %d, user %d already at level %d or above",
+			reviewerID, submission.ID, submission.UserID, submission.Level)
+	}
+	return reloadKycSubmission(l.ctx, l.svcCtx, submission.ID)
+}
+
+// checkKycReview 校验审核请求，返回审核人用户ID和待审核的申请
+// 审核人不能审核自己的申请
+func checkKycReview(ctx context.Context, svcCtx *svc.ServiceContext, id uint64) (uint64, *model.KycSubmission, error) {
+	reviewerID, err := authctx.UserID(ctx)
+	if err != nil {
+		return 0, nil, model.ErrUnauthorized
+	}
+	submission, err := findKycSubmission(ctx, svcCtx, id)
+	if err != nil {
+		return 0, nil, err
+	}
+	if submission.UserID == reviewerID {
+		return 0, nil, model.ErrCannotReviewOwnKyc
+	}
+	if submission.Status != model.KycStatusPending {
+		return 0, nil, model.ErrKycSubmissionReviewed
+	}
+	return reviewerID, submission, nil
+}
+
+// reloadKycSubmission 重新查询审核后的申请
+func reloadKycSubmission(ctx context.Context, svcCtx *svc.ServiceContext, id uint64) (*types.KycSubmission, error) {
+	submission, err := findKycSubmission(ctx, svcCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	return convertKycSubmission(ctx, submission)
+}
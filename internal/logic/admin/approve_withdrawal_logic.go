@@ -0,0 +1,57 @@
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/restriction"
+	"crypto-exchange/internal/logic/withdrawal"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ApproveWithdrawalLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewApproveWithdrawalLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ApproveWithdrawalLogic {
+	return &ApproveWithdrawalLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ApproveWithdrawal 审核通过待审核的提现，金额保持冻结直到广播结果确定
+func (l *ApproveWithdrawalLogic) ApproveWithdrawal(req *types.ReviewWithdrawalRequest) (resp *types.Withdrawal, err error) {
+	operatorID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	// 被限制提现的用户的提现不能审核通过，只能拒绝
+	pending, err := l.svcCtx.AssetTransactionModel.FindByTransactionID(l.ctx, req.TransactionID)
+	if err != nil && !errors.Is(err, model.ErrNotFound) {
+		l.Errorf("Failed to find withdraw %s: %v", req.TransactionID, err)
+		return nil, model.ErrInternalServer
+	}
+	if pending != nil {
+		if err := restriction.CheckRestrictions(l.ctx, l.svcCtx, pending.UserID, model.RestrictionWithdraw); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := withdrawal.NewWorkflow(l.ctx, l.svcCtx).Approve(req.TransactionID, operatorID, req.Remark)
+	if err != nil {
+		return nil, err
+	}
+
+	result := convertWithdrawal(tx)
+	return &result, nil
+}
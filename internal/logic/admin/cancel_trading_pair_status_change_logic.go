@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"context"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/market"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CancelTradingPairStatusChangeLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCancelTradingPairStatusChangeLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CancelTradingPairStatusChangeLogic {
+	return &CancelTradingPairStatusChangeLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CancelTradingPairStatusChange 取消尚未生效的交易对状态变更排期
+func (l *CancelTradingPairStatusChangeLogic) CancelTradingPairStatusChange(req *types.CancelTradingPairStatusChangeRequest) (resp *types.TradingPairStatusChange, err error) {
+	operatorID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	manager := market.NewTradingPairManager(l.ctx, l.svcCtx)
+	if err := manager.CancelScheduledStatusChange(req.ID, operatorID); err != nil {
+		l.Errorf("Failed to cancel status change %d: %v", req.ID, err)
+		return nil, err
+	}
+
+	change, err := l.svcCtx.TradingPairStatusChangeModel.FindOne(l.ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := convertStatusChange(change)
+	return &result, nil
+}
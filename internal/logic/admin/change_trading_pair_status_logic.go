@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"context"
+	"time"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/market"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ChangeTradingPairStatusLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewChangeTradingPairStatusLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ChangeTradingPairStatusLogic {
+	return &ChangeTradingPairStatusLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ChangeTradingPairStatus 变更交易对状态，指定未来生效时间时写入排期，否则立即执行
+func (l *ChangeTradingPairStatusLogic) ChangeTradingPairStatus(req *types.ChangeTradingPairStatusRequest) (resp *types.TradingPairStatusChange, err error) {
+	operatorID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	var effectiveAt time.Time
+	if req.EffectiveAt > 0 {
+		effectiveAt = time.Unix(req.EffectiveAt, 0)
+	}
+
+	manager := market.NewTradingPairManager(l.ctx, l.svcCtx)
+	change, err := manager.ScheduleStatusChange(req.Symbol, req.Status, effectiveAt, operatorID, req.Reason)
+	if err != nil {
+		l.Errorf("Failed to change status of trading pair %s: %v", req.Symbol, err)
+		return nil, err
+	}
+
+	result := convertStatusChange(change)
+	return &result, nil
+}
+
+// convertStatusChange 将状态变更记录转换为响应格式
+func convertStatusChange(change *model.TradingPairStatusChange) types.TradingPairStatusChange {
+	result := types.TradingPairStatusChange{
+		ID:          change.ID,
+		Symbol:      change.Symbol,
+		FromStatus:  change.FromStatus,
+		ToStatus:    change.ToStatus,
+		EffectiveAt: change.EffectiveAt.Format(time.RFC3339),
+		State:       change.State,
+		OperatorID:  change.OperatorID,
+		Reason:      change.Reason,
+		Remark:      change.Remark,
+		CreatedAt:   change.CreatedAt.Format(time.RFC3339),
+	}
+	if change.ExecutedAt.Valid {
+		result.ExecutedAt = change.ExecutedAt.Time.Format(time.RFC3339)
+	}
+	return result
+}
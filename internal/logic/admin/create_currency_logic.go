@@ -0,0 +1,79 @@
+package admin
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"crypto-exchange/internal/currency"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateCurrencyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateCurrencyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateCurrencyLogic {
+	return &CreateCurrencyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CreateCurrency 创建币种，创建后使币种注册表缓存失效
+// 新币种没有网络，需要再通过网络接口添加后才能充提
+func (l *CreateCurrencyLogic) CreateCurrency(req *types.CreateCurrencyRequest) (resp *types.Currency, err error) {
+	now := time.Now()
+	c := &model.Currency{
+		Code:            strings.ToUpper(req.Code),
+		Name:            req.Name,
+		Precision:       req.Precision,
+		DepositEnabled:  req.DepositEnabled,
+		WithdrawEnabled: req.WithdrawEnabled,
+		MaxDeposit:      zeroIfEmpty(req.MaxDeposit),
+		MaxWithdraw:     zeroIfEmpty(req.MaxWithdraw),
+		Status:          req.Status,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if c.Status == 0 {
+		c.Status = model.CurrencyStatusEnabled
+	}
+
+	if err := currency.ValidateConfig(c); err != nil {
+		return nil, err
+	}
+
+	_, err = l.svcCtx.CurrencyModel.FindOne(l.ctx, c.Code)
+	if err == nil {
+		return nil, model.ErrCurrencyExists
+	}
+	if err != model.ErrNotFound {
+		l.Errorf("Failed to check currency %s: %v", c.Code, err)
+		return nil, err
+	}
+
+	if _, err := l.svcCtx.CurrencyModel.Insert(l.ctx, c); err != nil {
+		l.Errorf("Failed to create currency %s: %v", c.Code, err)
+		return nil, err
+	}
+	l.svcCtx.CurrencyRegistry.Invalidate()
+
+	l.Infof("Created currency %s", c.Code)
+	return convertCurrency(c, nil), nil
+}
+
+// zeroIfEmpty 未传入的金额配置默认为0
+func zeroIfEmpty(value string) string {
+	if value == "" {
+		return "0"
+	}
+	return value
+}
@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"crypto-exchange/internal/currency"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateCurrencyNetworkLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateCurrencyNetworkLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateCurrencyNetworkLogic {
+	return &CreateCurrencyNetworkLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CreateCurrencyNetwork 为币种添加网络，添加后使币种注册表缓存失效
+func (l *CreateCurrencyNetworkLogic) CreateCurrencyNetwork(req *types.CreateCurrencyNetworkRequest) (resp *types.CurrencyNetwork, err error) {
+	code := strings.ToUpper(req.Code)
+	if _, err := l.svcCtx.CurrencyModel.FindOne(l.ctx, code); err != nil {
+		if err == model.ErrNotFound {
+			return nil, model.ErrCurrencyNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	n := &model.CurrencyNetwork{
+		Currency:         code,
+		Network:          strings.ToUpper(req.Network),
+		AddressType:      req.AddressType,
+		DepositEnabled:   req.DepositEnabled,
+		WithdrawEnabled:  req.WithdrawEnabled,
+		WithdrawFeeFixed: zeroIfEmpty(req.WithdrawFeeFixed),
+		WithdrawFeeRate:  zeroIfEmpty(req.WithdrawFeeRate),
+		MinDeposit:       zeroIfEmpty(req.MinDeposit),
+		MinWithdraw:      zeroIfEmpty(req.MinWithdraw),
+		Confirmations:    req.Confirmations,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	if err := currency.ValidateNetworkConfig(n); err != nil {
+		return nil, err
+	}
+
+	_, err = l.svcCtx.CurrencyNetworkModel.FindByCurrencyAndNetwork(l.ctx, n.Currency, n.Network)
+	if err == nil {
+		return nil, model.ErrNetworkExists
+	}
+	if err != model.ErrNotFound {
+		l.Errorf("Failed to check network %s of currency %s: %v", n.Network, n.Currency, err)
+		return nil, err
+	}
+
+	result, err := l.svcCtx.CurrencyNetworkModel.Insert(l.ctx, n)
+	if err != nil {
+		l.Errorf("Failed to create network %s of currency %s: %v", n.Network, n.Currency, err)
+		return nil, err
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		n.ID = uint64(id)
+	}
+	l.svcCtx.CurrencyRegistry.Invalidate()
+
+	l.Infof("Created network %s for currency %s", n.Network, n.Currency)
+	return convertCurrencyNetwork(n), nil
+}
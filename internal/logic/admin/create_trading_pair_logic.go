@@ -2,9 +2,13 @@ package admin
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	"crypto-exchange/internal/logic/market"
 	"crypto-exchange/internal/svc"
 	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
 )
@@ -23,8 +27,29 @@ func NewCreateTradingPairLogic(ctx context.Context, svcCtx *svc.ServiceContext)
 	}
 }
 
+// CreateTradingPair 创建交易对，币种必须已在币种注册表中启用
+// 新交易对处于预上线状态，通过状态变更接口开放交易
 func (l *CreateTradingPairLogic) CreateTradingPair(req *types.CreateTradingPairRequest) (resp *types.TradingPair, err error) {
-	// todo: add your logic here and delete this line
+	pair := &model.TradingPair{
+		Symbol:        strings.ToUpper(req.Symbol),
+		BaseCurrency:  strings.ToUpper(req.BaseCurrency),
+		QuoteCurrency: strings.ToUpper(req.QuoteCurrency),
+		MinAmount:     req.MinAmount,
+		MaxAmount:     req.MaxAmount,
+		PriceScale:    req.PriceScale,
+		AmountScale:   req.AmountScale,
+		Status:        model.TradingPairStatusPreTrading,
+	}
+
+	if pair.Symbol != pair.BaseCurrency+"/"+pair.QuoteCurrency {
+		return nil, fmt.Errorf("symbol %s does not match base currency %s and quote currency %s", pair.Symbol, pair.BaseCurrency, pair.QuoteCurrency)
+	}
+
+	manager := market.NewTradingPairManager(l.ctx, l.svcCtx)
+	if err := manager.CreateTradingPair(pair); err != nil {
+		l.Errorf("Failed to create trading pair %s: %v", pair.Symbol, err)
+		return nil, err
+	}
 
-	return
+	return convertTradingPair(pair), nil
 }
@@ -0,0 +1,75 @@
+package admin
+
+import (
+	"context"
+	"strings"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteCurrencyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeleteCurrencyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteCurrencyLogic {
+	return &DeleteCurrencyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// DeleteCurrency 删除币种及其网络配置；已被交易对引用或存在余额记录的币种只能停用，不能删除
+func (l *DeleteCurrencyLogic) DeleteCurrency(req *types.DeleteCurrencyRequest) (resp *types.Currency, err error) {
+	c, err := l.svcCtx.CurrencyModel.FindOne(l.ctx, strings.ToUpper(req.Code))
+	if err == model.ErrNotFound {
+		return nil, model.ErrCurrencyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pairs, err := l.svcCtx.TradingPairModel.FindAll(l.ctx)
+	if err != nil {
+		l.Errorf("Failed to get trading pairs: %v", err)
+		return nil, err
+	}
+	for _, pair := range pairs {
+		if pair.BaseCurrency == c.Code || pair.QuoteCurrency == c.Code {
+			return nil, model.ErrCurrencyInUse
+		}
+	}
+
+	count, err := l.svcCtx.BalanceModel.CountByCurrency(l.ctx, c.Code)
+	if err != nil {
+		l.Errorf("Failed to count balances of currency %s: %v", c.Code, err)
+		return nil, err
+	}
+	if count > 0 {
+		return nil, model.ErrCurrencyInUse
+	}
+
+	networks, err := l.svcCtx.CurrencyNetworkModel.FindByCurrency(l.ctx, c.Code)
+	if err != nil {
+		l.Errorf("Failed to get networks of currency %s: %v", c.Code, err)
+		return nil, err
+	}
+	if err := l.svcCtx.CurrencyNetworkModel.DeleteByCurrency(l.ctx, c.Code); err != nil {
+		l.Errorf("Failed to delete networks of currency %s: %v", c.Code, err)
+		return nil, err
+	}
+	if err := l.svcCtx.CurrencyModel.Delete(l.ctx, c.Code); err != nil {
+		l.Errorf("Failed to delete currency %s: %v", c.Code, err)
+		return nil, err
+	}
+	l.svcCtx.CurrencyRegistry.Invalidate()
+
+	l.Infof("Deleted currency %s", c.Code)
+	return convertCurrency(c, networks), nil
+}
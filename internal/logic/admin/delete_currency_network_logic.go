@@ -0,0 +1,46 @@
+package admin
+
+import (
+	"context"
+	"strings"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteCurrencyNetworkLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeleteCurrencyNetworkLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteCurrencyNetworkLogic {
+	return &DeleteCurrencyNetworkLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// DeleteCurrencyNetwork 删除币种网络，删除后该网络上的充提会被拒绝；历史充提记录保留原网络代码
+func (l *DeleteCurrencyNetworkLogic) DeleteCurrencyNetwork(req *types.DeleteCurrencyNetworkRequest) (resp *types.CurrencyNetwork, err error) {
+	n, err := l.svcCtx.CurrencyNetworkModel.FindByCurrencyAndNetwork(l.ctx, strings.ToUpper(req.Code), strings.ToUpper(req.Network))
+	if err == model.ErrNotFound {
+		return nil, model.ErrNetworkNotSupported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.svcCtx.CurrencyNetworkModel.Delete(l.ctx, n.ID); err != nil {
+		l.Errorf("Failed to delete network %s of currency %s: %v", n.Network, n.Currency, err)
+		return nil, err
+	}
+	l.svcCtx.CurrencyRegistry.Invalidate()
+
+	l.Infof("Deleted network %s of currency %s", n.Network, n.Currency)
+	return convertCurrencyNetwork(n), nil
+}
@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"context"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetCurrenciesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetCurrenciesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetCurrenciesLogic {
+	return &GetCurrenciesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetCurrencies 获取所有币种配置，直接查询数据库，不经过注册表缓存
+func (l *GetCurrenciesLogic) GetCurrencies() (resp *types.CurrencyListResponse, err error) {
+	currencies, err := l.svcCtx.CurrencyModel.FindAll(l.ctx)
+	if err != nil {
+		l.Errorf("Failed to get currencies: %v", err)
+		return nil, err
+	}
+
+	networks, err := l.svcCtx.CurrencyNetworkModel.FindAll(l.ctx)
+	if err != nil {
+		l.Errorf("Failed to get currency networks: %v", err)
+		return nil, err
+	}
+	networksByCurrency := make(map[string][]*model.CurrencyNetwork, len(currencies))
+	for _, n := range networks {
+		networksByCurrency[n.Currency] = append(networksByCurrency[n.Currency], n)
+	}
+
+	resp = &types.CurrencyListResponse{
+		Currencies: make([]types.Currency, 0, len(currencies)),
+	}
+	for _, c := range currencies {
+		resp.Currencies = append(resp.Currencies, *convertCurrency(c, networksByCurrency[c.Code]))
+	}
+
+	return resp, nil
+}
+
+// convertCurrency 将币种模型及其网络配置转换为响应格式
+func convertCurrency(c *model.Currency, networks []*model.CurrencyNetwork) *types.Currency {
+	resp := &types.Currency{
+		Code:            c.Code,
+		Name:            c.Name,
+		Precision:       c.Precision,
+		DepositEnabled:  c.DepositEnabled,
+		WithdrawEnabled: c.WithdrawEnabled,
+		MaxDeposit:      c.MaxDeposit,
+		MaxWithdraw:     c.MaxWithdraw,
+		Networks:        make([]types.CurrencyNetwork, 0, len(networks)),
+		Status:          c.Status,
+		CreatedAt:       c.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:       c.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	for _, n := range networks {
+		resp.Networks = append(resp.Networks, *convertCurrencyNetwork(n))
+	}
+	return resp
+}
+
+// convertCurrencyNetwork 将币种网络模型转换为响应格式
+func convertCurrencyNetwork(n *model.CurrencyNetwork) *types.CurrencyNetwork {
+	return &types.CurrencyNetwork{
+		Currency:         n.Currency,
+		Network:          n.Network,
+		AddressType:      n.AddressType,
+		DepositEnabled:   n.DepositEnabled,
+		WithdrawEnabled:  n.WithdrawEnabled,
+		WithdrawFeeFixed: n.WithdrawFeeFixed,
+		WithdrawFeeRate:  n.WithdrawFeeRate,
+		MinDeposit:       n.MinDeposit,
+		MinWithdraw:      n.MinWithdraw,
+		Confirmations:    n.Confirmations,
+		CreatedAt:        n.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:        n.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+
+	"crypto-exchange/internal/blobstore"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetKycDocumentLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetKycDocumentLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetKycDocumentLogic {
+	return &GetKycDocumentLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetKycDocument 读取认证申请的证件文件，文件内容以base64编码返回
+func (l *GetKycDocumentLogic) GetKycDocument(req *types.KycDocumentRequest) (resp *types.KycDocumentResponse, err error) {
+	submission, err := findKycSubmission(l.ctx, l.svcCtx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := submission.DocumentList()
+	if err != nil {
+		l.Errorf("Invalid documents of kyc submission %d: %v", submission.ID, err)
+		return nil, model.ErrInternalServer
+	}
+	if req.Index < 0 || req.Index >= int64(len(docs)) {
+		return nil, model.ErrKycDocumentNotFound
+	}
+	doc := docs[req.Index]
+
+	r, err := l.svcCtx.KycDocuments.Open(l.ctx, doc.Key)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			l.Errorf("Kyc document %s of submission %d is missing", doc.Key, submission.ID)
+			return nil, model.ErrKycDocumentNotFound
+		}
+		l.Errorf("Failed to open kyc document %s: %v", doc.Key, err)
+		return nil, model.ErrInternalServer
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		l.Errorf("Failed to read kyc document %s: %v", doc.Key, err)
+		return nil, model.ErrInternalServer
+	}
+
+	l.Infof("Kyc document %d of submission %d accessed", req.Index, submission.ID)
+	return &types.KycDocumentResponse{
+		FileName:    doc.FileName,
+		ContentType: doc.ContentType,
+		Content:     base64.StdEncoding.EncodeToString(data),
+	}, nil
+}
@@ -0,0 +1,59 @@
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"crypto-exchange/internal/logic/kyc"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetKycSubmissionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetKycSubmissionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetKycSubmissionLogic {
+	return &GetKycSubmissionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetKycSubmission 查询身份认证申请详情
+func (l *GetKycSubmissionLogic) GetKycSubmission(req *types.KycSubmissionRequest) (resp *types.KycSubmission, err error) {
+	submission, err := findKycSubmission(l.ctx, l.svcCtx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	return convertKycSubmission(l.ctx, submission)
+}
+
+// findKycSubmission 查询认证申请，不存在时返回ErrKycSubmissionNotFound
+func findKycSubmission(ctx context.Context, svcCtx *svc.ServiceContext, id uint64) (*model.KycSubmission, error) {
+	submission, err := svcCtx.KycSubmissionModel.FindOne(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrKycSubmissionNotFound
+		}
+		logx.WithContext(ctx).Errorf("Failed to find kyc submission %d: %v", id, err)
+		return nil, model.ErrInternalServer
+	}
+	return submission, nil
+}
+
+// convertKycSubmission 转换为响应格式，管理后台显示完整的证件号码
+func convertKycSubmission(ctx context.Context, submission *model.KycSubmission) (*types.KycSubmission, error) {
+	resp, err := kyc.ConvertSubmission(submission)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("Invalid documents of kyc submission %d: %v", submission.ID, err)
+		return nil, model.ErrInternalServer
+	}
+	return resp, nil
+}
@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"context"
+
+	"crypto-exchange/internal/logic/kyc"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetKycSubmissionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetKycSubmissionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetKycSubmissionsLogic {
+	return &GetKycSubmissionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetKycSubmissions 分页查询身份认证申请，默认查询待审核队列，按提交时间先后排序
+func (l *GetKycSubmissionsLogic) GetKycSubmissions(req *types.KycSubmissionListRequest) (resp *types.KycSubmissionListResponse, err error) {
+	status := req.Status
+	if status == 0 {
+		status = model.KycStatusPending
+	}
+	if status != model.KycStatusPending && status != model.KycStatusApproved && status != model.KycStatusRejected {
+		return nil, model.ErrInvalidParams
+	}
+
+	// 设置默认分页参数
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	size := req.Size
+	if size <= 0 {
+		size = 20
+	}
+
+	submissions, total, err := l.svcCtx.KycSubmissionModel.FindByStatus(l.ctx, status, page, size)
+	if err != nil {
+		l.Errorf("Failed to find kyc submissions with status %d: %v", status, err)
+		return nil, model.ErrInternalServer
+	}
+
+	resp = &types.KycSubmissionListResponse{
+		Submissions: make([]types.KycSubmission, 0, len(submissions)),
+		Total:       total,
+		Page:        page,
+		Size:        size,
+	}
+	for _, submission := range submissions {
+		item, err := kyc.ConvertSubmission(submission)
+		if err != nil {
+			l.Errorf("Invalid documents of kyc submission %d: %v", submission.ID, err)
+			return nil, model.ErrInternalServer
+		}
+		resp.Submissions = append(resp.Submissions, *item)
+	}
+	return resp, nil
+}
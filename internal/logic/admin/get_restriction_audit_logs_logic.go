@@ -0,0 +1,68 @@
+package admin
+
+import (
+	"context"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetRestrictionAuditLogsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetRestrictionAuditLogsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetRestrictionAuditLogsLogic {
+	return &GetRestrictionAuditLogsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetRestrictionAuditLogs 分页查询账户限制变更记录
+func (l *GetRestrictionAuditLogsLogic) GetRestrictionAuditLogs(req *types.RestrictionAuditLogRequest) (resp *types.RestrictionAuditLogListResponse, err error) {
+	// 设置默认分页参数
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	size := req.Size
+	if size <= 0 {
+		size = 20
+	}
+
+	logs, total, err := l.svcCtx.UserRestrictionModel.FindAuditLogs(l.ctx, req.UserID, page, size)
+	if err != nil {
+		l.Errorf("Failed to find restriction audit logs: %v", err)
+		return nil, model.ErrInternalServer
+	}
+
+	resp = &types.RestrictionAuditLogListResponse{
+		Logs:  make([]types.RestrictionAuditLog, 0, len(logs)),
+		Total: total,
+		Page:  page,
+		Size:  size,
+	}
+	for _, log := range logs {
+		item := types.RestrictionAuditLog{
+			ID:         log.ID,
+			UserID:     log.UserID,
+			Type:       log.Type,
+			Action:     log.Action,
+			Reason:     log.Reason,
+			OperatorID: log.OperatorID,
+			CreatedAt:  log.CreatedAt.Format(time.RFC3339),
+		}
+		if log.ExpiresAt.Valid {
+			item.ExpiresAt = log.ExpiresAt.Time.Format(time.RFC3339)
+		}
+		resp.Logs = append(resp.Logs, item)
+	}
+	return resp, nil
+}
@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"context"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetRoleAuditLogsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetRoleAuditLogsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetRoleAuditLogsLogic {
+	return &GetRoleAuditLogsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetRoleAuditLogs 分页查询角色变更记录
+func (l *GetRoleAuditLogsLogic) GetRoleAuditLogs(req *types.RoleAuditLogRequest) (resp *types.RoleAuditLogListResponse, err error) {
+	// 设置默认分页参数
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	size := req.Size
+	if size <= 0 {
+		size = 20
+	}
+
+	logs, total, err := l.svcCtx.RoleAuditLogModel.FindWithPagination(l.ctx, req.UserID, page, size)
+	if err != nil {
+		l.Errorf("Failed to find role audit logs: %v", err)
+		return nil, model.ErrInternalServer
+	}
+
+	resp = &types.RoleAuditLogListResponse{
+		Logs:  make([]types.RoleAuditLog, 0, len(logs)),
+		Total: total,
+		Page:  page,
+		Size:  size,
+	}
+	for _, log := range logs {
+		resp.Logs = append(resp.Logs, types.RoleAuditLog{
+			ID:         log.ID,
+			UserID:     log.UserID,
+			Role:       log.Role,
+			Action:     log.Action,
+			OperatorID: log.OperatorID,
+			Reason:     log.Reason,
+			CreatedAt:  log.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
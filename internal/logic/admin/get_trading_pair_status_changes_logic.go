@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"context"
+
+	"crypto-exchange/internal/logic/market"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetTradingPairStatusChangesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetTradingPairStatusChangesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetTradingPairStatusChangesLogic {
+	return &GetTradingPairStatusChangesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetTradingPairStatusChanges 分页查询交易对状态变更记录（含排期和审计记录）
+func (l *GetTradingPairStatusChangesLogic) GetTradingPairStatusChanges(req *types.TradingPairStatusChangeListRequest) (resp *types.TradingPairStatusChangeListResponse, err error) {
+	page, size := req.Page, req.Size
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = 20
+	}
+	if size > 100 {
+		size = 100
+	}
+
+	manager := market.NewTradingPairManager(l.ctx, l.svcCtx)
+	changes, total, err := manager.GetStatusChanges(req.Symbol, page, size)
+	if err != nil {
+		l.Errorf("Failed to get status changes of trading pair %s: %v", req.Symbol, err)
+		return nil, err
+	}
+
+	resp = &types.TradingPairStatusChangeListResponse{
+		Changes: make([]types.TradingPairStatusChange, 0, len(changes)),
+		Total:   total,
+		Page:    page,
+		Size:    size,
+	}
+	for _, change := range changes {
+		resp.Changes = append(resp.Changes, convertStatusChange(change))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,68 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetUserRestrictionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetUserRestrictionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetUserRestrictionsLogic {
+	return &GetUserRestrictionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetUserRestrictions 查询用户的账户限制，包括已过期但未解除的限制
+func (l *GetUserRestrictionsLogic) GetUserRestrictions(req *types.UserRestrictionsRequest) (resp *types.UserRestrictionsResponse, err error) {
+	if _, err := l.svcCtx.UserModel.FindOne(l.ctx, req.UserID); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return loadUserRestrictions(l.ctx, l.svcCtx, req.UserID)
+}
+
+// loadUserRestrictions 查询用户当前的账户限制并转换为响应格式
+func loadUserRestrictions(ctx context.Context, svcCtx *svc.ServiceContext, userID uint64) (*types.UserRestrictionsResponse, error) {
+	restrictions, err := svcCtx.UserRestrictionModel.FindByUserID(ctx, userID)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("Failed to find restrictions of user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	now := time.Now()
+	resp := &types.UserRestrictionsResponse{
+		UserID:       userID,
+		Restrictions: make([]types.UserRestriction, 0, len(restrictions)),
+	}
+	for _, r := range restrictions {
+		item := types.UserRestriction{
+			Type:      r.Type,
+			Reason:    r.Reason,
+			Active:    !r.ExpiresAt.Valid || r.ExpiresAt.Time.After(now),
+			CreatedBy: r.CreatedBy,
+			CreatedAt: r.CreatedAt.Format(time.RFC3339),
+			UpdatedAt: r.UpdatedAt.Format(time.RFC3339),
+		}
+		if r.ExpiresAt.Valid {
+			item.ExpiresAt = r.ExpiresAt.Time.Format(time.RFC3339)
+		}
+		resp.Restrictions = append(resp.Restrictions, item)
+	}
+	return resp, nil
+}
@@ -0,0 +1,55 @@
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"crypto-exchange/internal/rbac"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetUserRolesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetUserRolesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetUserRolesLogic {
+	return &GetUserRolesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetUserRoles 查询用户的角色及角色对应的权限
+func (l *GetUserRolesLogic) GetUserRoles(req *types.UserRolesRequest) (resp *types.UserRolesResponse, err error) {
+	if _, err := l.svcCtx.UserModel.FindOne(l.ctx, req.UserID); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return loadUserRoles(l.ctx, l.svcCtx, req.UserID)
+}
+
+// loadUserRoles 查询用户当前的角色并转换为响应格式
+func loadUserRoles(ctx context.Context, svcCtx *svc.ServiceContext, userID uint64) (*types.UserRolesResponse, error) {
+	roles, err := svcCtx.UserRoleModel.FindRolesByUserID(ctx, userID)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("Failed to find roles of user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+	if roles == nil {
+		roles = []string{}
+	}
+	return &types.UserRolesResponse{
+		UserID:      userID,
+		Roles:       roles,
+		Permissions: rbac.Permissions(roles),
+	}, nil
+}
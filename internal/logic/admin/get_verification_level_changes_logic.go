@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetVerificationLevelChangesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetVerificationLevelChangesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetVerificationLevelChangesLogic {
+	return &GetVerificationLevelChangesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetVerificationLevelChanges 查询用户当前认证等级和等级变更记录
+func (l *GetVerificationLevelChangesLogic) GetVerificationLevelChanges(req *types.VerificationLevelChangesRequest) (resp *types.VerificationLevelChangeListResponse, err error) {
+	return loadVerificationLevelChanges(l.ctx, l.svcCtx, req.UserID)
+}
+
+// loadVerificationLevelChanges 查询用户当前认证等级和等级变更记录
+func loadVerificationLevelChanges(ctx context.Context, svcCtx *svc.ServiceContext, userID uint64) (*types.VerificationLevelChangeListResponse, error) {
+	logger := logx.WithContext(ctx)
+	user, err := svcCtx.UserModel.FindOne(ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrUserNotFound
+		}
+		logger.Errorf("Failed to find user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	changes, err := svcCtx.VerificationLevelChangeModel.FindByUserID(ctx, userID)
+	if err != nil {
+		logger.Errorf("Failed to find verification level changes of user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	resp := &types.VerificationLevelChangeListResponse{
+		UserID:  userID,
+		Level:   user.VerificationLevel,
+		Changes: make([]types.VerificationLevelChange, 0, len(changes)),
+	}
+	for _, change := range changes {
+		resp.Changes = append(resp.Changes, types.VerificationLevelChange{
+			ID:           change.ID,
+			UserID:       change.UserID,
+			FromLevel:    change.FromLevel,
+			ToLevel:      change.ToLevel,
+			SubmissionID: change.SubmissionID,
+			ReviewerID:   change.ReviewerID,
+			Reason:       change.Reason,
+			CreatedAt:    change.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
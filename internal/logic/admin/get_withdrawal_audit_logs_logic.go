@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetWithdrawalAuditLogsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetWithdrawalAuditLogsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetWithdrawalAuditLogsLogic {
+	return &GetWithdrawalAuditLogsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetWithdrawalAuditLogs 查询提现信息及其完整的状态流转记录
+func (l *GetWithdrawalAuditLogsLogic) GetWithdrawalAuditLogs(req *types.WithdrawalAuditLogRequest) (resp *types.WithdrawalAuditLogResponse, err error) {
+	tx, err := l.svcCtx.AssetTransactionModel.FindByTransactionID(l.ctx, req.TransactionID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrWithdrawalNotFound
+		}
+		return nil, err
+	}
+	if tx.Type != model.AssetTransactionTypeWithdraw {
+		return nil, model.ErrWithdrawalNotFound
+	}
+
+	logs, err := l.svcCtx.WithdrawalAuditLogModel.FindByTransactionID(l.ctx, req.TransactionID)
+	if err != nil {
+		l.Errorf("Failed to get audit logs of withdrawal %s: %v", req.TransactionID, err)
+		return nil, err
+	}
+
+	resp = &types.WithdrawalAuditLogResponse{
+		Withdrawal: convertWithdrawal(tx),
+		Logs:       make([]types.WithdrawalAuditLog, 0, len(logs)),
+	}
+	for _, log := range logs {
+		resp.Logs = append(resp.Logs, types.WithdrawalAuditLog{
+			FromStatus: log.FromStatus,
+			ToStatus:   log.ToStatus,
+			OperatorID: log.OperatorID,
+			TxHash:     log.TxHash,
+			Remark:     log.Remark,
+			CreatedAt:  log.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,91 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetWithdrawalsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetWithdrawalsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetWithdrawalsLogic {
+	return &GetWithdrawalsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetWithdrawals 按状态分页查询提现，默认查询待审核队列，按申请时间先后排序
+func (l *GetWithdrawalsLogic) GetWithdrawals(req *types.WithdrawalListRequest) (resp *types.WithdrawalListResponse, err error) {
+	status := req.Status
+	if status == 0 {
+		status = model.AssetTransactionStatusPending
+	}
+	if model.WithdrawalStatusText(status) == "unknown" {
+		return nil, fmt.Errorf("invalid withdrawal status: %d", status)
+	}
+
+	page, size := req.Page, req.Size
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = 20
+	}
+	if size > 100 {
+		size = 100
+	}
+
+	withdrawals, err := l.svcCtx.AssetTransactionModel.FindByTypeAndStatus(l.ctx, model.AssetTransactionTypeWithdraw, status, size, (page-1)*size)
+	if err != nil {
+		l.Errorf("Failed to get withdrawals with status %d: %v", status, err)
+		return nil, err
+	}
+	total, err := l.svcCtx.AssetTransactionModel.CountByTypeAndStatus(l.ctx, model.AssetTransactionTypeWithdraw, status)
+	if err != nil {
+		l.Errorf("Failed to count withdrawals with status %d: %v", status, err)
+		return nil, err
+	}
+
+	resp = &types.WithdrawalListResponse{
+		Withdrawals: make([]types.Withdrawal, 0, len(withdrawals)),
+		Total:       total,
+		Page:        page,
+		Size:        size,
+	}
+	for _, withdrawal := range withdrawals {
+		resp.Withdrawals = append(resp.Withdrawals, convertWithdrawal(withdrawal))
+	}
+
+	return resp, nil
+}
+
+// convertWithdrawal 将提现记录转换为接口响应
+func convertWithdrawal(tx *model.AssetTransaction) types.Withdrawal {
+	return types.Withdrawal{
+		TransactionID: tx.TransactionID,
+		UserID:        tx.UserID,
+		Currency:      tx.Currency,
+		Network:       tx.Network,
+		Amount:        tx.Amount,
+		Fee:           tx.Fee,
+		Address:       tx.Address,
+		Status:        tx.Status,
+		StatusText:    model.WithdrawalStatusText(tx.Status),
+		TxHash:        tx.TxHash,
+		Remark:        tx.Remark,
+		CreatedAt:     tx.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     tx.UpdatedAt.Format(time.RFC3339),
+	}
+}
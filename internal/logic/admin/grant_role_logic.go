@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/rbac"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GrantRoleLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGrantRoleLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GrantRoleLogic {
+	return &GrantRoleLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GrantRole 授予用户角色，角色变更和审计日志在同一事务中写入
+// 新角色在用户下次登录后写入JWT，权限校验以数据库中的角色为准
+func (l *GrantRoleLogic) GrantRole(req *types.GrantRoleRequest) (resp *types.UserRolesResponse, err error) {
+	operatorID, err := checkRoleChange(l.ctx, l.svcCtx, req.UserID, req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = l.svcCtx.UserRoleModel.Grant(l.ctx, &model.UserRole{
+		UserID:    req.UserID,
+		Role:      req.Role,
+		GrantedBy: operatorID,
+		CreatedAt: now,
+	}, &model.RoleAuditLog{
+		UserID:     req.UserID,
+		Role:       req.Role,
+		Action:     model.RoleAuditActionGrant,
+		OperatorID: operatorID,
+		Reason:     strings.TrimSpace(req.Reason),
+		CreatedAt:  now,
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrRoleAlreadyGranted) {
+			return nil, err
+		}
+		l.Errorf("Failed to grant role %s to user %d: %v", req.Role, req.UserID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	l.Infof("User %d granted role %s to user %d", operatorID, req.Role, req.UserID)
+	return loadUserRoles(l.ctx, l.svcCtx, req.UserID)
+}
+
+// checkRoleChange 校验角色变更请求，返回操作人用户ID
+// 管理员不能修改自己的角色，避免自我提权或误撤销导致无人可以管理角色
+func checkRoleChange(ctx context.Context, svcCtx *svc.ServiceContext, userID uint64, role string) (uint64, error) {
+	operatorID, err := authctx.UserID(ctx)
+	if err != nil {
+		return 0, model.ErrUnauthorized
+	}
+	if !rbac.IsValidRole(role) {
+		return 0, model.ErrInvalidRole
+	}
+	if operatorID == userID {
+		return 0, model.ErrCannotModifyOwnRoles
+	}
+	if _, err := svcCtx.UserModel.FindOne(ctx, userID); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return 0, model.ErrUserNotFound
+		}
+		return 0, err
+	}
+	return operatorID, nil
+}
@@ -0,0 +1,82 @@
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ImportDepositAddressesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewImportDepositAddressesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ImportDepositAddressesLogic {
+	return &ImportDepositAddressesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ImportDepositAddresses 导入预生成的充值地址到地址池，分配时优先使用地址池中的地址
+// 同一网络上不同币种共用地址池，地址格式按该网络的配置校验
+func (l *ImportDepositAddressesLogic) ImportDepositAddresses(req *types.ImportDepositAddressesRequest) (resp *types.ImportDepositAddressesResponse, err error) {
+	network := strings.ToUpper(req.Network)
+	if network == "" || len(req.Addresses) == 0 {
+		return nil, model.ErrInvalidParams
+	}
+
+	// 先校验全部地址，避免导入一半后失败
+	seen := make(map[string]bool, len(req.Addresses))
+	addresses := make([]string, 0, len(req.Addresses))
+	for _, address := range req.Addresses {
+		address = strings.TrimSpace(address)
+		if err := l.svcCtx.CurrencyRegistry.ValidateNetworkAddress(l.ctx, network, address); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, address)
+		}
+		if seen[address] {
+			continue
+		}
+		seen[address] = true
+		addresses = append(addresses, address)
+	}
+
+	var imported int64
+	for _, address := range addresses {
+		_, err := l.svcCtx.DepositAddressModel.Insert(l.ctx, &model.DepositAddress{
+			Network:         network,
+			Address:         address,
+			DerivationIndex: -1,
+			CreatedAt:       time.Now(),
+			AssignedAt:      sql.NullTime{},
+		})
+		if err != nil {
+			l.Errorf("Failed to import deposit address %s on %s after %d imported: %v", address, network, imported, err)
+			return nil, fmt.Errorf("failed to import address %s (%d imported): %w", address, imported, err)
+		}
+		imported++
+	}
+
+	available, err := l.svcCtx.DepositAddressModel.CountPoolAvailable(l.ctx, network)
+	if err != nil {
+		l.Errorf("Failed to count deposit address pool of %s: %v", network, err)
+		return nil, err
+	}
+
+	l.Infof("Imported %d deposit addresses to %s pool, %d available", imported, network, available)
+	return &types.ImportDepositAddressesResponse{
+		Network:       network,
+		Imported:      imported,
+		PoolAvailable: available,
+	}, nil
+}
@@ -0,0 +1,184 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryKycStore 内存中的用户认证等级、认证申请和等级变更记录
+type memoryKycStore struct {
+	levels      map[uint64]int64
+	submissions map[uint64]*model.KycSubmission
+	changes     []*model.VerificationLevelChange
+}
+
+type memoryKycUserModel struct {
+	model.UserModel
+	store *memoryKycStore
+}
+
+func (m *memoryKycUserModel) FindOne(ctx context.Context, id uint64) (*model.User, error) {
+	level, ok := m.store.levels[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &model.User{ID: id, Status: model.UserStatusActive, VerificationLevel: level}, nil
+}
+
+type memoryKycSubmissionModel struct {
+	model.KycSubmissionModel
+	store *memoryKycStore
+}
+
+func (m *memoryKycSubmissionModel) FindOne(ctx context.Context, id uint64) (*model.KycSubmission, error) {
+	s, ok := m.store.submissions[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (m *memoryKycSubmissionModel) review(id uint64, status int64, reviewerID uint64, reason string, reviewedAt time.Time) (*model.KycSubmission, error) {
+	s, ok := m.store.submissions[id]
+	if !ok || s.Status != model.KycStatusPending {
+		return nil, model.ErrKycSubmissionReviewed
+	}
+	s.Status, s.ReviewerID, s.ReviewReason = status, reviewerID, reason
+	s.ReviewedAt.Time, s.ReviewedAt.Valid = reviewedAt, true
+	return s, nil
+}
+
+func (m *memoryKycSubmissionModel) Approve(ctx context.Context, id, reviewerID uint64, reason string, reviewedAt time.Time) (*model.VerificationLevelChange, error) {
+	s, err := m.review(id, model.KycStatusApproved, reviewerID, reason, reviewedAt)
+	if err != nil {
+		return nil, err
+	}
+	current := m.store.levels[s.UserID]
+	if current >= s.Level {
+		return nil, nil
+	}
+	m.store.levels[s.UserID] = s.Level
+	change := &model.VerificationLevelChange{UserID: s.UserID, FromLevel: current, ToLevel: s.Level, SubmissionID: id, ReviewerID: reviewerID, Reason: reason, CreatedAt: reviewedAt}
+	m.store.changes = append(m.store.changes, change)
+	return change, nil
+}
+
+func (m *memoryKycSubmissionModel) Reject(ctx context.Context, id, reviewerID uint64, reason string, reviewedAt time.Time) error {
+	_, err := m.review(id, model.KycStatusRejected, reviewerID, reason, reviewedAt)
+	return err
+}
+
+type memoryVerificationLevelChangeModel struct {
+	model.VerificationLevelChangeModel
+	store *memoryKycStore
+}
+
+func (m *memoryVerificationLevelChangeModel) FindByUserID(ctx context.Context, userID uint64) ([]*model.VerificationLevelChange, error) {
+	var resp []*model.VerificationLevelChange
+	for i := len(m.store.changes) - 1; i >= 0; i-- {
+		if m.store.changes[i].UserID == userID {
+			resp = append(resp, m.store.changes[i])
+		}
+	}
+	return resp, nil
+}
+
+func (m *memoryVerificationLevelChangeModel) SetLevel(ctx context.Context, data *model.VerificationLevelChange) error {
+	if m.store.levels[data.UserID] != data.FromLevel {
+		return model.ErrVerificationLevelChanged
+	}
+	m.store.levels[data.UserID] = data.ToLevel
+	m.store.changes = append(m.store.changes, data)
+	return nil
+}
+
+func newKycReviewContext() (*svc.ServiceContext, *memoryKycStore) {
+	store := &memoryKycStore{
+		levels: map[uint64]int64{1: model.UserVerificationUnverified, 2: model.UserVerificationUnverified, 3: model.UserVerificationUnverified},
+		submissions: map[uint64]*model.KycSubmission{
+			10: {ID: 10, UserID: 2, Level: model.UserVerificationBasic, Documents: `[{"key":"kyc/2/a/0","content_type":"image/png"}]`, Status: model.KycStatusPending},
+			11: {ID: 11, UserID: 3, Level: model.UserVerificationBasic, Status: model.KycStatusPending},
+			12: {ID: 12, UserID: 1, Level: model.UserVerificationBasic, Status: model.KycStatusPending},
+		},
+	}
+	return &svc.ServiceContext{
+		UserModel:                    &memoryKycUserModel{store: store},
+		KycSubmissionModel:           &memoryKycSubmissionModel{store: store},
+		VerificationLevelChangeModel: &memoryVerificationLevelChangeModel{store: store},
+	}, store
+}
+
+func TestReviewKycSubmission(t *testing.T) {
+	svcCtx, store := newKycReviewContext()
+	ctx := context.WithValue(context.Background(), "userId", json.Number("1"))
+
+	resp, err := NewApproveKycSubmissionLogic(ctx, svcCtx).ApproveKycSubmission(&types.ReviewKycRequest{ID: 10, Reason: "documents match"})
+	assert.NoError(t, err)
+	assert.Equal(t, model.KycStatusApproved, resp.Status)
+	assert.Equal(t, uint64(1), resp.ReviewerID)
+	assert.Len(t, resp.Documents, 1)
+	assert.Equal(t, model.UserVerificationBasic, store.levels[2])
+	if assert.Len(t, store.changes, 1) {
+		assert.Equal(t, uint64(10), store.changes[0].SubmissionID)
+		assert.Equal(t, "documents match", store.changes[0].Reason)
+	}
+
+	// 已审核的申请不能再次审核
+	_, err = NewRejectKycSubmissionLogic(ctx, svcCtx).RejectKycSubmission(&types.ReviewKycRequest{ID: 10, Reason: "blurry"})
+	assert.Equal(t, model.ErrKycSubmissionReviewed, err)
+
+	// 拒绝必须填写原因，且不改变认证等级
+	_, err = NewRejectKycSubmissionLogic(ctx, svcCtx).RejectKycSubmission(&types.ReviewKycRequest{ID: 11})
+	assert.Equal(t, model.ErrInvalidParams, err)
+	resp, err = NewRejectKycSubmissionLogic(ctx, svcCtx).RejectKycSubmission(&types.ReviewKycRequest{ID: 11, Reason: "blurry"})
+	assert.NoError(t, err)
+	assert.Equal(t, model.KycStatusRejected, resp.Status)
+	assert.Equal(t, model.UserVerificationUnverified, store.levels[3])
+	assert.Len(t, store.changes, 1)
+
+	// 不能审核自己的申请
+	_, err = NewApproveKycSubmissionLogic(ctx, svcCtx).ApproveKycSubmission(&types.ReviewKycRequest{ID: 12})
+	assert.Equal(t, model.ErrCannotReviewOwnKyc, err)
+	_, err = NewApproveKycSubmissionLogic(ctx, svcCtx).ApproveKycSubmission(&types.ReviewKycRequest{ID: 99})
+	assert.Equal(t, model.ErrKycSubmissionNotFound, err)
+}
+
+func TestSetVerificationLevel(t *testing.T) {
+	svcCtx, store := newKycReviewContext()
+	ctx := context.WithValue(context.Background(), "userId", json.Number("1"))
+
+	resp, err := NewSetVerificationLevelLogic(ctx, svcCtx).SetVerificationLevel(&types.SetVerificationLevelRequest{UserID: 2, Level: model.UserVerificationAdvanced, Reason: "offline review"})
+	assert.NoError(t, err)
+	assert.Equal(t, model.UserVerificationAdvanced, resp.Level)
+	if assert.Len(t, resp.Changes, 1) {
+		assert.Equal(t, model.UserVerificationUnverified, resp.Changes[0].FromLevel)
+		assert.Equal(t, model.UserVerificationAdvanced, resp.Changes[0].ToLevel)
+		assert.Equal(t, uint64(1), resp.Changes[0].ReviewerID)
+		assert.Equal(t, uint64(0), resp.Changes[0].SubmissionID)
+	}
+
+	// 降级同样记录
+	resp, err = NewSetVerificationLevelLogic(ctx, svcCtx).SetVerificationLevel(&types.SetVerificationLevelRequest{UserID: 2, Level: model.UserVerificationBasic, Reason: "document expired"})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Changes, 2)
+	assert.Equal(t, "document expired", resp.Changes[0].Reason)
+
+	_, err = NewSetVerificationLevelLogic(ctx, svcCtx).SetVerificationLevel(&types.SetVerificationLevelRequest{UserID: 2, Level: model.UserVerificationBasic, Reason: "again"})
+	assert.Equal(t, model.ErrInvalidVerificationLevel, err)
+	_, err = NewSetVerificationLevelLogic(ctx, svcCtx).SetVerificationLevel(&types.SetVerificationLevelRequest{UserID: 2, Level: 3, Reason: "x"})
+	assert.Equal(t, model.ErrInvalidVerificationLevel, err)
+	_, err = NewSetVerificationLevelLogic(ctx, svcCtx).SetVerificationLevel(&types.SetVerificationLevelRequest{UserID: 3, Level: model.UserVerificationBasic})
+	assert.Equal(t, model.ErrInvalidParams, err)
+	_, err = NewSetVerificationLevelLogic(ctx, svcCtx).SetVerificationLevel(&types.SetVerificationLevelRequest{UserID: 1, Level: model.UserVerificationAdvanced, Reason: "self"})
+	assert.Equal(t, model.ErrCannotReviewOwnKyc, err)
+	assert.Equal(t, model.UserVerificationUnverified, store.levels[1])
+}
@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type LiftRestrictionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewLiftRestrictionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *LiftRestrictionLogic {
+	return &LiftRestrictionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// LiftRestriction 解除账户限制，解除后立即生效；冻结期间撤销的挂单和注销的会话不会恢复
+func (l *LiftRestrictionLogic) LiftRestriction(req *types.LiftRestrictionRequest) (resp *types.UserRestrictionsResponse, err error) {
+	operatorID, err := checkRestrictionChange(l.ctx, l.svcCtx, req.UserID, req.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	err = l.svcCtx.UserRestrictionModel.Lift(l.ctx, req.UserID, req.Type, &model.RestrictionAuditLog{
+		UserID:     req.UserID,
+		Type:       req.Type,
+		Action:     model.RestrictionAuditActionLift,
+		Reason:     reason,
+		OperatorID: operatorID,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrRestrictionNotFound) {
+			return nil, err
+		}
+		l.Errorf("Failed to lift %s restriction of user %d: %v", req.Type, req.UserID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	l.Infof("User %d lifted %s restriction of user %d: %s", operatorID, req.Type, req.UserID, reason)
+	return loadUserRestrictions(l.ctx, l.svcCtx, req.UserID)
+}
@@ -0,0 +1,52 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RejectKycSubmissionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRejectKycSubmissionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RejectKycSubmissionLogic {
+	return &RejectKycSubmissionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RejectKycSubmission 拒绝认证申请，必须填写拒绝原因，用户可以修改资料后重新提交
+func (l *RejectKycSubmissionLogic) RejectKycSubmission(req *types.ReviewKycRequest) (resp *types.KycSubmission, err error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, model.ErrInvalidParams
+	}
+
+	reviewerID, submission, err := checkKycReview(l.ctx, l.svcCtx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.svcCtx.KycSubmissionModel.Reject(l.ctx, submission.ID, reviewerID, reason, time.Now()); err != nil {
+		if errors.Is(err, model.ErrKycSubmissionReviewed) {
+			return nil, err
+		}
+		l.Errorf("Failed to reject kyc submission %d: %v", submission.ID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	l.Infof("User %d rejected kyc submission %d of user %d: %s", reviewerID, submission.ID, submission.UserID, reason)
+	return reloadKycSubmission(l.ctx, l.svcCtx, submission.ID)
+}
@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/withdrawal"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RejectWithdrawalLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRejectWithdrawalLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RejectWithdrawalLogic {
+	return &RejectWithdrawalLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RejectWithdrawal 拒绝待审核的提现，冻结的金额和手续费退回用户可用余额
+func (l *RejectWithdrawalLogic) RejectWithdrawal(req *types.ReviewWithdrawalRequest) (resp *types.Withdrawal, err error) {
+	operatorID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	if strings.TrimSpace(req.Remark) == "" {
+		return nil, errors.New("reject reason is required")
+	}
+
+	tx, err := withdrawal.NewWorkflow(l.ctx, l.svcCtx).Reject(req.TransactionID, operatorID, strings.TrimSpace(req.Remark))
+	if err != nil {
+		return nil, err
+	}
+
+	result := convertWithdrawal(tx)
+	return &result, nil
+}
@@ -0,0 +1,123 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/session"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryRestrictionModel struct {
+	model.UserRestrictionModel
+	restrictions []*model.UserRestriction
+	audits       []*model.RestrictionAuditLog
+}
+
+func (m *memoryRestrictionModel) FindByUserID(ctx context.Context, userID uint64) ([]*model.UserRestriction, error) {
+	var found []*model.UserRestriction
+	for _, r := range m.restrictions {
+		if r.UserID == userID {
+			found = append(found, r)
+		}
+	}
+	return found, nil
+}
+
+func (m *memoryRestrictionModel) Apply(ctx context.Context, data *model.UserRestriction, audit *model.RestrictionAuditLog) error {
+	for i, r := range m.restrictions {
+		if r.UserID == data.UserID && r.Type == data.Type {
+			m.restrictions[i] = data
+			m.audits = append(m.audits, audit)
+			return nil
+		}
+	}
+	m.restrictions = append(m.restrictions, data)
+	m.audits = append(m.audits, audit)
+	return nil
+}
+
+func (m *memoryRestrictionModel) Lift(ctx context.Context, userID uint64, restrictionType string, audit *model.RestrictionAuditLog) error {
+	for i, r := range m.restrictions {
+		if r.UserID == userID && r.Type == restrictionType {
+			m.restrictions = append(m.restrictions[:i], m.restrictions[i+1:]...)
+			m.audits = append(m.audits, audit)
+			return nil
+		}
+	}
+	return model.ErrRestrictionNotFound
+}
+
+type memorySessionStore struct {
+	session.Store
+	revoked []uint64
+}
+
+func (s *memorySessionStore) RevokeAll(ctx context.Context, userID uint64) error {
+	s.revoked = append(s.revoked, userID)
+	return nil
+}
+
+func TestApplyAndLiftRestriction(t *testing.T) {
+	restrictions := &memoryRestrictionModel{}
+	sessions := &memorySessionStore{}
+	svcCtx := &svc.ServiceContext{UserModel: &memoryUserModel{}, UserRestrictionModel: restrictions, Sessions: sessions}
+	ctx := context.WithValue(context.Background(), "userId", json.Number("1"))
+
+	resp, err := NewApplyRestrictionLogic(ctx, svcCtx).ApplyRestriction(&types.ApplyRestrictionRequest{UserID: 2, Type: model.RestrictionWithdraw, Reason: "suspicious withdrawals"})
+	assert.NoError(t, err)
+	if assert.Len(t, resp.Restrictions, 1) {
+		assert.Equal(t, model.RestrictionWithdraw, resp.Restrictions[0].Type)
+		assert.True(t, resp.Restrictions[0].Active)
+		assert.Empty(t, resp.Restrictions[0].ExpiresAt)
+	}
+	// 禁止提现不影响登录会话
+	assert.Empty(t, sessions.revoked)
+
+	// 必须填写原因，到期时间必须晚于当前时间
+	_, err = NewApplyRestrictionLogic(ctx, svcCtx).ApplyRestriction(&types.ApplyRestrictionRequest{UserID: 2, Type: model.RestrictionTrading, Reason: "  "})
+	assert.ErrorIs(t, err, model.ErrRestrictionReason)
+	_, err = NewApplyRestrictionLogic(ctx, svcCtx).ApplyRestriction(&types.ApplyRestrictionRequest{UserID: 2, Type: model.RestrictionTrading, Reason: "abuse", ExpiresAt: time.Now().Add(-time.Hour).Unix()})
+	assert.ErrorIs(t, err, model.ErrInvalidRestrictionExpiry)
+	_, err = NewApplyRestrictionLogic(ctx, svcCtx).ApplyRestriction(&types.ApplyRestrictionRequest{UserID: 2, Type: "deposit", Reason: "abuse"})
+	assert.ErrorIs(t, err, model.ErrInvalidRestrictionType)
+	_, err = NewApplyRestrictionLogic(ctx, svcCtx).ApplyRestriction(&types.ApplyRestrictionRequest{UserID: 99, Type: model.RestrictionLogin, Reason: "abuse"})
+	assert.ErrorIs(t, err, model.ErrUserNotFound)
+
+	// 不能限制自己的账户
+	_, err = NewApplyRestrictionLogic(ctx, svcCtx).ApplyRestriction(&types.ApplyRestrictionRequest{UserID: 1, Type: model.RestrictionFreeze, Reason: "test"})
+	assert.ErrorIs(t, err, model.ErrCannotRestrictSelf)
+
+	// 冻结账户时注销用户的全部会话
+	expiresAt := time.Now().Add(24 * time.Hour).Unix()
+	resp, err = NewApplyRestrictionLogic(ctx, svcCtx).ApplyRestriction(&types.ApplyRestrictionRequest{UserID: 2, Type: model.RestrictionFreeze, Reason: "court order", ExpiresAt: expiresAt})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Restrictions, 2)
+	assert.Equal(t, []uint64{2}, sessions.revoked)
+
+	resp, err = NewLiftRestrictionLogic(ctx, svcCtx).LiftRestriction(&types.LiftRestrictionRequest{UserID: 2, Type: model.RestrictionWithdraw, Reason: "reviewed"})
+	assert.NoError(t, err)
+	if assert.Len(t, resp.Restrictions, 1) {
+		assert.Equal(t, model.RestrictionFreeze, resp.Restrictions[0].Type)
+		assert.Equal(t, time.Unix(expiresAt, 0).Format(time.RFC3339), resp.Restrictions[0].ExpiresAt)
+	}
+
+	_, err = NewLiftRestrictionLogic(ctx, svcCtx).LiftRestriction(&types.LiftRestrictionRequest{UserID: 2, Type: model.RestrictionWithdraw})
+	assert.ErrorIs(t, err, model.ErrRestrictionNotFound)
+
+	// 每次成功的变更都有审计记录
+	if assert.Len(t, restrictions.audits, 3) {
+		assert.Equal(t, model.RestrictionAuditActionApply, restrictions.audits[0].Action)
+		assert.Equal(t, uint64(1), restrictions.audits[0].OperatorID)
+		assert.Equal(t, "suspicious withdrawals", restrictions.audits[0].Reason)
+		assert.True(t, restrictions.audits[1].ExpiresAt.Valid)
+		assert.Equal(t, model.RestrictionAuditActionLift, restrictions.audits[2].Action)
+		assert.Equal(t, "reviewed", restrictions.audits[2].Reason)
+	}
+}
@@ -0,0 +1,55 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RevokeRoleLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRevokeRoleLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RevokeRoleLogic {
+	return &RevokeRoleLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RevokeRole 撤销用户角色，撤销后立即生效，不需要等待用户的JWT过期
+func (l *RevokeRoleLogic) RevokeRole(req *types.RevokeRoleRequest) (resp *types.UserRolesResponse, err error) {
+	operatorID, err := checkRoleChange(l.ctx, l.svcCtx, req.UserID, req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.svcCtx.UserRoleModel.Revoke(l.ctx, req.UserID, req.Role, &model.RoleAuditLog{
+		UserID:     req.UserID,
+		Role:       req.Role,
+		Action:     model.RoleAuditActionRevoke,
+		OperatorID: operatorID,
+		Reason:     strings.TrimSpace(req.Reason),
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrRoleNotGranted) {
+			return nil, err
+		}
+		l.Errorf("Failed to revoke role %s from user %d: %v", req.Role, req.UserID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	l.Infof("User %d revoked role %s from user %d", operatorID, req.Role, req.UserID)
+	return loadUserRoles(l.ctx, l.svcCtx, req.UserID)
+}
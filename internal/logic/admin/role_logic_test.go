@@ -0,0 +1,96 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryUserModel struct {
+	model.UserModel
+}
+
+func (m *memoryUserModel) FindOne(ctx context.Context, id uint64) (*model.User, error) {
+	if id > 10 {
+		return nil, model.ErrNotFound
+	}
+	return &model.User{ID: id, Status: model.UserStatusActive, CreatedAt: time.Now()}, nil
+}
+
+type memoryUserRoleModel struct {
+	model.UserRoleModel
+	roles  map[uint64][]string
+	audits []*model.RoleAuditLog
+}
+
+func (m *memoryUserRoleModel) FindRolesByUserID(ctx context.Context, userID uint64) ([]string, error) {
+	return m.roles[userID], nil
+}
+
+func (m *memoryUserRoleModel) Grant(ctx context.Context, data *model.UserRole, audit *model.RoleAuditLog) error {
+	for _, role := range m.roles[data.UserID] {
+		if role == data.Role {
+			return model.ErrRoleAlreadyGranted
+		}
+	}
+	m.roles[data.UserID] = append(m.roles[data.UserID], data.Role)
+	m.audits = append(m.audits, audit)
+	return nil
+}
+
+func (m *memoryUserRoleModel) Revoke(ctx context.Context, userID uint64, role string, audit *model.RoleAuditLog) error {
+	for i, r := range m.roles[userID] {
+		if r == role {
+			m.roles[userID] = append(m.roles[userID][:i], m.roles[userID][i+1:]...)
+			m.audits = append(m.audits, audit)
+			return nil
+		}
+	}
+	return model.ErrRoleNotGranted
+}
+
+func TestGrantAndRevokeRole(t *testing.T) {
+	roles := &memoryUserRoleModel{roles: map[uint64][]string{1: {model.RoleSuperAdmin}}}
+	svcCtx := &svc.ServiceContext{UserModel: &memoryUserModel{}, UserRoleModel: roles}
+	ctx := context.WithValue(context.Background(), "userId", json.Number("1"))
+
+	resp, err := NewGrantRoleLogic(ctx, svcCtx).GrantRole(&types.GrantRoleRequest{UserID: 2, Role: model.RoleFinance, Reason: "withdrawal desk"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{model.RoleFinance}, resp.Roles)
+	assert.Contains(t, resp.Permissions, "withdrawal:review")
+
+	_, err = NewGrantRoleLogic(ctx, svcCtx).GrantRole(&types.GrantRoleRequest{UserID: 2, Role: model.RoleFinance})
+	assert.ErrorIs(t, err, model.ErrRoleAlreadyGranted)
+
+	_, err = NewGrantRoleLogic(ctx, svcCtx).GrantRole(&types.GrantRoleRequest{UserID: 2, Role: "root"})
+	assert.ErrorIs(t, err, model.ErrInvalidRole)
+
+	_, err = NewGrantRoleLogic(ctx, svcCtx).GrantRole(&types.GrantRoleRequest{UserID: 99, Role: model.RoleAuditor})
+	assert.ErrorIs(t, err, model.ErrUserNotFound)
+
+	// 不能修改自己的角色
+	_, err = NewRevokeRoleLogic(ctx, svcCtx).RevokeRole(&types.RevokeRoleRequest{UserID: 1, Role: model.RoleSuperAdmin})
+	assert.ErrorIs(t, err, model.ErrCannotModifyOwnRoles)
+
+	resp, err = NewRevokeRoleLogic(ctx, svcCtx).RevokeRole(&types.RevokeRoleRequest{UserID: 2, Role: model.RoleFinance})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Roles)
+
+	_, err = NewRevokeRoleLogic(ctx, svcCtx).RevokeRole(&types.RevokeRoleRequest{UserID: 2, Role: model.RoleFinance})
+	assert.ErrorIs(t, err, model.ErrRoleNotGranted)
+
+	// 每次成功的变更都有审计记录
+	if assert.Len(t, roles.audits, 2) {
+		assert.Equal(t, model.RoleAuditActionGrant, roles.audits[0].Action)
+		assert.Equal(t, uint64(1), roles.audits[0].OperatorID)
+		assert.Equal(t, "withdrawal desk", roles.audits[0].Reason)
+		assert.Equal(t, model.RoleAuditActionRevoke, roles.audits[1].Action)
+	}
+}
@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"crypto-exchange/internal/currency"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateCurrencyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateCurrencyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateCurrencyLogic {
+	return &UpdateCurrencyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// UpdateCurrency 整体替换币种配置，状态未传时保持不变，更新后使币种注册表缓存失效
+func (l *UpdateCurrencyLogic) UpdateCurrency(req *types.UpdateCurrencyRequest) (resp *types.Currency, err error) {
+	c, err := l.svcCtx.CurrencyModel.FindOne(l.ctx, strings.ToUpper(req.Code))
+	if err == model.ErrNotFound {
+		return nil, model.ErrCurrencyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.Name = req.Name
+	c.Precision = req.Precision
+	c.DepositEnabled = req.DepositEnabled
+	c.WithdrawEnabled = req.WithdrawEnabled
+	c.MaxDeposit = zeroIfEmpty(req.MaxDeposit)
+	c.MaxWithdraw = zeroIfEmpty(req.MaxWithdraw)
+	if req.Status != 0 {
+		c.Status = req.Status
+	}
+	c.UpdatedAt = time.Now()
+
+	if err := currency.ValidateConfig(c); err != nil {
+		return nil, err
+	}
+
+	if err := l.svcCtx.CurrencyModel.Update(l.ctx, c); err != nil {
+		l.Errorf("Failed to update currency %s: %v", c.Code, err)
+		return nil, err
+	}
+	l.svcCtx.CurrencyRegistry.Invalidate()
+
+	networks, err := l.svcCtx.CurrencyNetworkModel.FindByCurrency(l.ctx, c.Code)
+	if err != nil {
+		l.Errorf("Failed to get networks of currency %s: %v", c.Code, err)
+		return nil, err
+	}
+
+	l.Infof("Updated currency %s", c.Code)
+	return convertCurrency(c, networks), nil
+}
@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"crypto-exchange/internal/currency"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateCurrencyNetworkLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateCurrencyNetworkLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateCurrencyNetworkLogic {
+	return &UpdateCurrencyNetworkLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// UpdateCurrencyNetwork 整体替换币种网络配置，更新后使币种注册表缓存失效
+func (l *UpdateCurrencyNetworkLogic) UpdateCurrencyNetwork(req *types.UpdateCurrencyNetworkRequest) (resp *types.CurrencyNetwork, err error) {
+	n, err := l.svcCtx.CurrencyNetworkModel.FindByCurrencyAndNetwork(l.ctx, strings.ToUpper(req.Code), strings.ToUpper(req.Network))
+	if err == model.ErrNotFound {
+		return nil, model.ErrNetworkNotSupported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	n.AddressType = req.AddressType
+	n.DepositEnabled = req.DepositEnabled
+	n.WithdrawEnabled = req.WithdrawEnabled
+	n.WithdrawFeeFixed = zeroIfEmpty(req.WithdrawFeeFixed)
+	n.WithdrawFeeRate = zeroIfEmpty(req.WithdrawFeeRate)
+	n.MinDeposit = zeroIfEmpty(req.MinDeposit)
+	n.MinWithdraw = zeroIfEmpty(req.MinWithdraw)
+	n.Confirmations = req.Confirmations
+	n.UpdatedAt = time.Now()
+
+	if err := currency.ValidateNetworkConfig(n); err != nil {
+		return nil, err
+	}
+
+	if err := l.svcCtx.CurrencyNetworkModel.Update(l.ctx, n); err != nil {
+		l.Errorf("Failed to update network %s of currency %s: %v", n.Network, n.Currency, err)
+		return nil, err
+	}
+	l.svcCtx.CurrencyRegistry.Invalidate()
+
+	l.Infof("Updated network %s of currency %s", n.Network, n.Currency)
+	return convertCurrencyNetwork(n), nil
+}
@@ -2,9 +2,13 @@ package admin
 
 import (
 	"context"
+	"time"
 
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/market"
 	"crypto-exchange/internal/svc"
 	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
 
 	"github.com/zeromicro/go-zero/core/logx"
 )
@@ -23,8 +27,87 @@ func NewUpdateTradingPairLogic(ctx context.Context, svcCtx *svc.ServiceContext)
 	}
 }
 
+// UpdateTradingPair 更新交易对参数，未传的字段保持不变；状态变更立即生效并写入审计记录
 func (l *UpdateTradingPairLogic) UpdateTradingPair(req *types.UpdateTradingPairRequest) (resp *types.TradingPair, err error) {
-	// todo: add your logic here and delete this line
+	operatorID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	manager := market.NewTradingPairManager(l.ctx, l.svcCtx)
+	pair, err := manager.GetTradingPairBySymbol(req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.MinAmount != "" {
+		pair.MinAmount = req.MinAmount
+	}
+	if req.MaxAmount != "" {
+		pair.MaxAmount = req.MaxAmount
+	}
+	if req.PriceScale != 0 {
+		pair.PriceScale = req.PriceScale
+	}
+	if req.AmountScale != 0 {
+		pair.AmountScale = req.AmountScale
+	}
+	if req.TickSize != "" {
+		pair.TickSize = req.TickSize
+	}
+	if req.StepSize != "" {
+		pair.StepSize = req.StepSize
+	}
+	if req.MinNotional != "" {
+		pair.MinNotional = req.MinNotional
+	}
+	// 最大挂单数量：0表示不修改，负数表示取消限制
+	if req.MaxOpenOrders > 0 {
+		pair.MaxOpenOrders = req.MaxOpenOrders
+	} else if req.MaxOpenOrders < 0 {
+		pair.MaxOpenOrders = 0
+	}
+	// 最低认证等级：0表示不修改，负数表示取消限制
+	if req.MinVerificationLevel > 0 {
+		pair.MinVerificationLevel = req.MinVerificationLevel
+	} else if req.MinVerificationLevel < 0 {
+		pair.MinVerificationLevel = 0
+	}
+
+	if err := manager.UpdateTradingPair(pair); err != nil {
+		l.Errorf("Failed to update trading pair %s: %v", req.Symbol, err)
+		return nil, err
+	}
 
-	return
+	// 状态变更走生命周期流程，校验迁移规则并记录操作人
+	if req.Status != 0 && req.Status != pair.Status {
+		if _, err := manager.ScheduleStatusChange(pair.Symbol, req.Status, time.Time{}, operatorID, "updated via trading pair update"); err != nil {
+			l.Errorf("Failed to change status of trading pair %s: %v", req.Symbol, err)
+			return nil, err
+		}
+		pair.Status = req.Status
+	}
+
+	return convertTradingPair(pair), nil
+}
+
+// convertTradingPair 将交易对模型转换为响应格式
+func convertTradingPair(pair *model.TradingPair) *types.TradingPair {
+	return &types.TradingPair{
+		ID:                   pair.ID,
+		Symbol:               pair.Symbol,
+		BaseCurrency:         pair.BaseCurrency,
+		QuoteCurrency:        pair.QuoteCurrency,
+		MinAmount:            pair.MinAmount,
+		MaxAmount:            pair.MaxAmount,
+		PriceScale:           pair.PriceScale,
+		AmountScale:          pair.AmountScale,
+		TickSize:             pair.TickSize,
+		StepSize:             pair.StepSize,
+		MinNotional:          pair.MinNotional,
+		MaxOpenOrders:        pair.MaxOpenOrders,
+		MinVerificationLevel: pair.MinVerificationLevel,
+		Status:               pair.Status,
+		CreatedAt:            pair.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
 }
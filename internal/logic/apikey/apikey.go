@@ -0,0 +1,96 @@
+package apikey
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+)
+
+const (
+	maxLabelLength      = 64 // 备注最大长度
+	maxIPWhitelistCount = 20 // IP白名单最大条目数
+)
+
+// normalizePermissions 校验并去重权限列表，按read、trade、withdraw的顺序返回
+func normalizePermissions(permissions []string) ([]string, error) {
+	if len(permissions) == 0 {
+		return nil, model.ErrInvalidApiKeyPermission
+	}
+	requested := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		p = strings.ToLower(strings.TrimSpace(p))
+		switch p {
+		case model.ApiKeyPermissionRead, model.ApiKeyPermissionTrade, model.ApiKeyPermissionWithdraw:
+			requested[p] = true
+		default:
+			return nil, model.ErrInvalidApiKeyPermission
+		}
+	}
+
+	var resp []string
+	for _, p := range []string{model.ApiKeyPermissionRead, model.ApiKeyPermissionTrade, model.ApiKeyPermissionWithdraw} {
+		if requested[p] {
+			resp = append(resp, p)
+		}
+	}
+	return resp, nil
+}
+
+// normalizeIPWhitelist 校验IP白名单，条目统一为标准格式并去重
+func normalizeIPWhitelist(entries []string) ([]string, error) {
+	if len(entries) > maxIPWhitelistCount {
+		return nil, model.ErrInvalidIPWhitelist
+	}
+	seen := make(map[string]bool, len(entries))
+	var resp []string
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		var normalized string
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, model.ErrInvalidIPWhitelist
+			}
+			normalized = network.String()
+		} else {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, model.ErrInvalidIPWhitelist
+			}
+			normalized = ip.String()
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			resp = append(resp, normalized)
+		}
+	}
+	return resp, nil
+}
+
+// toApiKey 转换为API密钥响应格式，不包含密钥
+func toApiKey(key *model.ApiKey) types.ApiKey {
+	resp := types.ApiKey{
+		ID:          key.ID,
+		KeyID:       key.KeyID,
+		Label:       key.Label,
+		Permissions: key.PermissionList(),
+		IPWhitelist: key.IPWhitelistEntries(),
+		CreatedAt:   key.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if resp.Permissions == nil {
+		resp.Permissions = []string{}
+	}
+	if resp.IPWhitelist == nil {
+		resp.IPWhitelist = []string{}
+	}
+	if key.ExpiresAt.Valid {
+		resp.ExpiresAt = key.ExpiresAt.Time.Format(time.RFC3339)
+	}
+	if key.LastUsedAt.Valid {
+		resp.LastUsedAt = key.LastUsedAt.Time.Format(time.RFC3339)
+	}
+	return resp
+}
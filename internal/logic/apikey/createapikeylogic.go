@@ -0,0 +1,129 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"crypto-exchange/internal/apikey"
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/securityevent"
+	"crypto-exchange/internal/logic/twofactor"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateApiKeyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateApiKeyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateApiKeyLogic {
+	return &CreateApiKeyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CreateApiKey 创建API密钥，明文密钥只在响应中返回一次，服务端加密保存
+func (l *CreateApiKeyLogic) CreateApiKey(req *types.CreateApiKeyRequest) (resp *types.CreateApiKeyResponse, err error) {
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	// 1. 校验参数
+	label := strings.TrimSpace(req.Label)
+	if utf8.RuneCountInString(label) > maxLabelLength || req.ExpiresIn < 0 {
+		return nil, model.ErrInvalidParams
+	}
+	permissions, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	ipWhitelist, err := normalizeIPWhitelist(req.IPWhitelist)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 检查用户状态和密钥数量上限
+	user, err := l.svcCtx.UserModel.FindOne(l.ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrUserNotFound
+		}
+		return nil, err
+	}
+	if user.Status != model.UserStatusActive {
+		return nil, model.ErrUserDisabled
+	}
+	if maxPerUser := l.svcCtx.Config.ApiKey.MaxPerUser; maxPerUser > 0 {
+		count, err := l.svcCtx.ApiKeyModel.CountActiveByUserID(l.ctx, userID)
+		if err != nil {
+			l.Errorf("Failed to count api keys of user %d: %v", userID, err)
+			return nil, model.ErrInternalServer
+		}
+		if count >= maxPerUser {
+			return nil, model.ErrApiKeyLimitExceeded
+		}
+	}
+
+	// 3. 已启用两步验证的用户需要提供新的验证码
+	if err := twofactor.Require(l.ctx, l.svcCtx, userID, req.TotpCode); err != nil {
+		return nil, err
+	}
+
+	// 4. 生成密钥并加密保存
+	keyID, secret, err := apikey.Generate()
+	if err != nil {
+		l.Errorf("Failed to generate api key: %v", err)
+		return nil, model.ErrInternalServer
+	}
+	secretEncrypted, err := l.svcCtx.TOTP.Encrypt(secret)
+	if err != nil {
+		l.Errorf("Failed to encrypt api key secret: %v", err)
+		return nil, model.ErrInternalServer
+	}
+	now := time.Now()
+	key := &model.ApiKey{
+		UserID:          userID,
+		KeyID:           keyID,
+		SecretEncrypted: secretEncrypted,
+		Label:           label,
+		Permissions:     strings.Join(permissions, ","),
+		IPWhitelist:     strings.Join(ipWhitelist, ","),
+		Status:          model.ApiKeyStatusActive,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if req.ExpiresIn > 0 {
+		key.ExpiresAt = sql.NullTime{Time: now.Add(time.Duration(req.ExpiresIn) * time.Second), Valid: true}
+	}
+	if _, err := l.svcCtx.ApiKeyModel.Insert(l.ctx, key); err != nil {
+		l.Errorf("Failed to insert api key for user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	// 获取生成的主键
+	created, err := l.svcCtx.ApiKeyModel.FindOneByKeyID(l.ctx, keyID)
+	if err != nil {
+		l.Errorf("Failed to find created api key %s: %v", keyID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	securityevent.Record(l.ctx, l.svcCtx, userID, model.SecurityEventApiKeyCreated, fmt.Sprintf("%s (%s)", keyID, created.Permissions))
+	l.Infof("User %d created api key %s with permissions %s", userID, keyID, created.Permissions)
+	return &types.CreateApiKeyResponse{
+		ApiKey: toApiKey(created),
+		Secret: secret,
+	}, nil
+}
@@ -0,0 +1,151 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"crypto-exchange/internal/config"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/totp"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryApiKeyModel struct {
+	model.ApiKeyModel
+	keys []*model.ApiKey
+}
+
+func (m *memoryApiKeyModel) Insert(ctx context.Context, data *model.ApiKey) (sql.Result, error) {
+	data.ID = uint64(len(m.keys) + 1)
+	m.keys = append(m.keys, data)
+	return nil, nil
+}
+
+func (m *memoryApiKeyModel) FindOneByKeyID(ctx context.Context, keyID string) (*model.ApiKey, error) {
+	for _, key := range m.keys {
+		if key.KeyID == keyID {
+			return key, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *memoryApiKeyModel) CountActiveByUserID(ctx context.Context, userID uint64) (int64, error) {
+	var count int64
+	for _, key := range m.keys {
+		if key.UserID == userID && key.Status == model.ApiKeyStatusActive {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type memoryUserModel struct {
+	model.UserModel
+}
+
+func (m *memoryUserModel) FindOne(ctx context.Context, id uint64) (*model.User, error) {
+	return &model.User{ID: id, Status: model.UserStatusActive}, nil
+}
+
+// memoryUserTotpModel 用户均未启用两步验证
+type memoryUserTotpModel struct {
+	model.UserTotpModel
+}
+
+func (m *memoryUserTotpModel) FindOneByUserID(ctx context.Context, userID uint64) (*model.UserTotp, error) {
+	return nil, model.ErrNotFound
+}
+
+// memorySecurityEventModel 保存写入的安全事件
+type memorySecurityEventModel struct {
+	model.SecurityEventModel
+	events []*model.SecurityEvent
+}
+
+func (m *memorySecurityEventModel) Insert(ctx context.Context, data *model.SecurityEvent) (sql.Result, error) {
+	m.events = append(m.events, data)
+	return nil, nil
+}
+
+func newTestServiceContext(keys *memoryApiKeyModel) *svc.ServiceContext {
+	var c config.Config
+	c.ApiKey.MaxPerUser = 2
+	return &svc.ServiceContext{
+		Config:             c,
+		UserModel:          &memoryUserModel{},
+		ApiKeyModel:        keys,
+		UserTotpModel:      &memoryUserTotpModel{},
+		SecurityEventModel: &memorySecurityEventModel{},
+		TOTP:               totp.NewAuthenticator("Exchange", "test-encryption-key", 1),
+	}
+}
+
+// jwtContext 模拟go-zero JWT中间件写入的claims
+func jwtContext(userID string) context.Context {
+	return context.WithValue(context.Background(), "userId", json.Number(userID))
+}
+
+func TestCreateApiKey(t *testing.T) {
+	keys := &memoryApiKeyModel{}
+	svcCtx := newTestServiceContext(keys)
+	logic := NewCreateApiKeyLogic(jwtContext("1"), svcCtx)
+
+	resp, err := logic.CreateApiKey(&types.CreateApiKeyRequest{
+		Label:       "grid bot",
+		Permissions: []string{"TRADE", "read", "trade"},
+		IPWhitelist: []string{"10.1.2.3/8", " 192.168.1.10 "},
+		ExpiresIn:   86400,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"read", "trade"}, resp.ApiKey.Permissions)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.10"}, resp.ApiKey.IPWhitelist)
+	assert.NotEmpty(t, resp.ApiKey.ExpiresAt)
+
+	// 密钥加密保存，解密后与返回的明文密钥一致
+	if assert.Len(t, keys.keys, 1) {
+		assert.Equal(t, uint64(1), keys.keys[0].UserID)
+		assert.NotContains(t, keys.keys[0].SecretEncrypted, resp.Secret)
+		secret, err := svcCtx.TOTP.Decrypt(keys.keys[0].SecretEncrypted)
+		assert.NoError(t, err)
+		assert.Equal(t, resp.Secret, secret)
+	}
+
+	// 创建密钥记录为安全事件
+	events := svcCtx.SecurityEventModel.(*memorySecurityEventModel).events
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, uint64(1), events[0].UserID)
+		assert.Equal(t, model.SecurityEventApiKeyCreated, events[0].EventType)
+		assert.Contains(t, events[0].Detail, resp.ApiKey.KeyID)
+	}
+}
+
+func TestCreateApiKey_Validation(t *testing.T) {
+	keys := &memoryApiKeyModel{}
+	logic := NewCreateApiKeyLogic(jwtContext("1"), newTestServiceContext(keys))
+
+	_, err := logic.CreateApiKey(&types.CreateApiKeyRequest{})
+	assert.ErrorIs(t, err, model.ErrInvalidApiKeyPermission)
+
+	_, err = logic.CreateApiKey(&types.CreateApiKeyRequest{Permissions: []string{"read", "admin"}})
+	assert.ErrorIs(t, err, model.ErrInvalidApiKeyPermission)
+
+	_, err = logic.CreateApiKey(&types.CreateApiKeyRequest{Permissions: []string{"read"}, IPWhitelist: []string{"10.0.0.300"}})
+	assert.ErrorIs(t, err, model.ErrInvalidIPWhitelist)
+
+	_, err = logic.CreateApiKey(&types.CreateApiKeyRequest{Permissions: []string{"read"}, ExpiresIn: -1})
+	assert.ErrorIs(t, err, model.ErrInvalidParams)
+
+	// 超过每个用户的密钥数量上限
+	for i := 0; i < 2; i++ {
+		_, err = logic.CreateApiKey(&types.CreateApiKeyRequest{Permissions: []string{"read"}})
+		assert.NoError(t, err)
+	}
+	_, err = logic.CreateApiKey(&types.CreateApiKeyRequest{Permissions: []string{"read"}})
+	assert.ErrorIs(t, err, model.ErrApiKeyLimitExceeded)
+}
@@ -0,0 +1,52 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/securityevent"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteApiKeyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeleteApiKeyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteApiKeyLogic {
+	return &DeleteApiKeyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// DeleteApiKey 删除（吊销）API密钥，删除后使用该密钥签名的请求立即失效
+func (l *DeleteApiKeyLogic) DeleteApiKey(req *types.DeleteApiKeyRequest) (resp *types.BaseResponse, err error) {
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	if err := l.svcCtx.ApiKeyModel.RevokeByUser(l.ctx, userID, req.ID); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrApiKeyNotFound
+		}
+		l.Errorf("Failed to revoke api key %d of user %d: %v", req.ID, userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	securityevent.Record(l.ctx, l.svcCtx, userID, model.SecurityEventApiKeyDeleted, strconv.FormatUint(req.ID, 10))
+	l.Infof("User %d revoked api key %d", userID, req.ID)
+	return &types.BaseResponse{
+		Code:    0,
+		Message: "API key deleted successfully",
+	}, nil
+}
@@ -0,0 +1,46 @@
+package apikey
+
+import (
+	"context"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetApiKeysLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetApiKeysLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetApiKeysLogic {
+	return &GetApiKeysLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetApiKeys 查询当前用户的有效API密钥
+func (l *GetApiKeysLogic) GetApiKeys() (resp *types.ApiKeyListResponse, err error) {
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	keys, err := l.svcCtx.ApiKeyModel.FindByUserID(l.ctx, userID)
+	if err != nil {
+		l.Errorf("Failed to find api keys of user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	apiKeys := make([]types.ApiKey, 0, len(keys))
+	for _, key := range keys {
+		apiKeys = append(apiKeys, toApiKey(key))
+	}
+	return &types.ApiKeyListResponse{ApiKeys: apiKeys}, nil
+}
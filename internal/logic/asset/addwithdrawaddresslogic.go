@@ -0,0 +1,88 @@
+package asset
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/securityevent"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// maxWithdrawAddressLabelLength 地址备注最大长度（字符）
+const maxWithdrawAddressLabelLength = 64
+
+type AddWithdrawAddressLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewAddWithdrawAddressLogic(ctx context.Context, svcCtx *svc.ServiceContext) *AddWithdrawAddressLogic {
+	return &AddWithdrawAddressLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// AddWithdrawAddress 添加提现地址，地址在冷却期结束后才能用于提现
+func (l *AddWithdrawAddressLogic) AddWithdrawAddress(req *types.AddWithdrawAddressRequest) (resp *types.WithdrawAddress, err error) {
+	// 1. 从JWT上下文中获取用户ID
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		l.Errorf("Failed to get user ID from context: %v", err)
+		return nil, model.ErrUnauthorized
+	}
+
+	// 2. 验证参数，地址按网络的地址格式校验
+	network := strings.ToUpper(req.Network)
+	address := strings.TrimSpace(req.Address)
+	label := strings.TrimSpace(req.Label)
+	if network == "" || utf8.RuneCountInString(label) > maxWithdrawAddressLabelLength {
+		return nil, model.ErrInvalidParams
+	}
+	if err := l.svcCtx.CurrencyRegistry.ValidateNetworkAddress(l.ctx, network, address); err != nil {
+		return nil, err
+	}
+
+	_, err = l.svcCtx.WithdrawalAddressModel.FindByUserAndAddress(l.ctx, userID, network, address)
+	if err == nil {
+		return nil, model.ErrWithdrawAddressExists
+	}
+	if !errors.Is(err, model.ErrNotFound) {
+		l.Errorf("Failed to check withdraw address for user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	// 3. 保存地址，冷却期从添加时开始计算
+	now := time.Now()
+	entry := &model.WithdrawalAddress{
+		UserID:      userID,
+		Network:     network,
+		Address:     address,
+		Label:       label,
+		AvailableAt: now.Add(time.Duration(l.svcCtx.Config.Withdraw.AddressCooldown) * time.Second),
+		CreatedAt:   now,
+	}
+	result, err := l.svcCtx.WithdrawalAddressModel.Insert(l.ctx, entry)
+	if err != nil {
+		l.Errorf("Failed to add withdraw address for user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		entry.ID = uint64(id)
+	}
+
+	securityevent.Record(l.ctx, l.svcCtx, userID, model.SecurityEventWithdrawAddressAdded, network+" "+address)
+	l.Infof("User %d added withdraw address %s on %s, available at %s", userID, address, network, entry.AvailableAt.Format(time.RFC3339))
+	converted := convertWithdrawAddress(entry, now)
+	return &converted, nil
+}
@@ -0,0 +1,102 @@
+package asset
+
+import (
+	"context"
+	"errors"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/restriction"
+	"crypto-exchange/internal/logic/withdrawal"
+	"crypto-exchange/internal/onetime"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ConfirmWithdrawLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewConfirmWithdrawLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ConfirmWithdrawLogic {
+	return &ConfirmWithdrawLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ConfirmWithdraw 校验提现确认码，通过后提现进入审核
+// 确认码过期或校验失败次数过多时提现取消，冻结金额退回可用余额
+func (l *ConfirmWithdrawLogic) ConfirmWithdraw(req *types.ConfirmWithdrawRequest) (resp *types.WithdrawResponse, err error) {
+	// 1. 从JWT上下文中获取用户ID
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		l.Errorf("Failed to get user ID from context: %v", err)
+		return nil, model.ErrUnauthorized
+	}
+
+	if req.TransactionID == "" || req.Code == "" {
+		return nil, model.ErrInvalidParams
+	}
+
+	// 2. 只能确认本人等待确认的提现
+	tx, err := l.svcCtx.AssetTransactionModel.FindByTransactionID(l.ctx, req.TransactionID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrWithdrawalNotFound
+		}
+		l.Errorf("Failed to find withdraw %s: %v", req.TransactionID, err)
+		return nil, err
+	}
+	if tx.UserID != userID || tx.Type != model.AssetTransactionTypeWithdraw {
+		return nil, model.ErrWithdrawalNotFound
+	}
+	if tx.Status != model.AssetTransactionStatusUnconfirmed {
+		return nil, withdrawal.ValidateTransition(tx.Status, model.AssetTransactionStatusPending)
+	}
+
+	// 发起提现后被限制提现的用户不能继续确认，未确认的提现到期后自动取消
+	if err := restriction.CheckUserID(l.ctx, l.svcCtx, userID, model.RestrictionWithdraw); err != nil {
+		l.Errorf("Withdraw %s confirmation rejected for user %d: %v", tx.TransactionID, userID, err)
+		return nil, err
+	}
+
+	// 3. 校验确认码
+	workflow := withdrawal.NewWorkflow(l.ctx, l.svcCtx)
+	err = l.svcCtx.ConfirmCodes.Verify(l.ctx, withdrawal.ConfirmPurpose, tx.TransactionID, req.Code)
+	switch {
+	case err == nil:
+	case errors.Is(err, onetime.ErrCodeInvalid):
+		l.Infof("Invalid confirmation code for withdraw %s by user %d", tx.TransactionID, userID)
+		return nil, model.ErrWithdrawConfirmationInvalid
+	case errors.Is(err, onetime.ErrCodeExpired), errors.Is(err, onetime.ErrTooManyAttempts):
+		if _, cancelErr := workflow.Cancel(tx.TransactionID, userID, err.Error()); cancelErr != nil {
+			l.Errorf("Failed to cancel withdraw %s: %v", tx.TransactionID, cancelErr)
+		}
+		return nil, model.ErrWithdrawConfirmationExpired
+	default:
+		l.Errorf("Failed to verify confirmation code for withdraw %s: %v", tx.TransactionID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	// 4. 进入审核
+	tx, err = workflow.SubmitForReview(tx.TransactionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.WithdrawResponse{
+		TransactionID: tx.TransactionID,
+		Currency:      tx.Currency,
+		Network:       tx.Network,
+		Amount:        tx.Amount,
+		Address:       tx.Address,
+		Fee:           tx.Fee,
+		Status:        tx.Status,
+		CreatedAt:     tx.CreatedAt.Format("2006-01-02 15:04:05"),
+	}, nil
+}
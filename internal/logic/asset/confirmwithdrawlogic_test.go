@@ -0,0 +1,108 @@
+package asset
+
+import (
+	"context"
+	"testing"
+
+	"crypto-exchange/internal/onetime"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newUnconfirmedWithdrawal() *model.AssetTransaction {
+	return &model.AssetTransaction{
+		ID:            7,
+		UserID:        1,
+		TransactionID: "WTH_1",
+		Currency:      "BTC",
+		Network:       "BTC",
+		Type:          model.AssetTransactionTypeWithdraw,
+		Amount:        "1",
+		Fee:           "0.0005",
+		Status:        model.AssetTransactionStatusUnconfirmed,
+	}
+}
+
+func newConfirmWithdrawEnv(tx *model.AssetTransaction, verifyErr error) (*svc.ServiceContext, *MockAssetTransactionModel, *MockBalanceModel) {
+	txModel := new(MockAssetTransactionModel)
+	txModel.On("FindByTransactionID", mock.Anything, tx.TransactionID).Return(tx, nil)
+	balanceModel := new(MockBalanceModel)
+	balanceModel.On("Trans", mock.Anything, mock.Anything).Return(nil)
+	auditLogModel := new(MockWithdrawalAuditLogModel)
+	auditLogModel.On("Insert", mock.Anything, mock.Anything).Return(nil)
+	confirmCodes := new(MockConfirmCodes)
+	confirmCodes.On("Verify", mock.Anything, "withdraw", tx.TransactionID, mock.Anything).Return(verifyErr)
+
+	userModel := new(MockUserModel)
+	userModel.On("FindOne", mock.Anything, tx.UserID).Return(&model.User{ID: tx.UserID, Status: model.UserStatusActive}, nil)
+
+	return &svc.ServiceContext{
+		UserModel:               userModel,
+		UserRestrictionModel:    &memoryRestrictionModel{},
+		AssetTransactionModel:   txModel,
+		BalanceModel:            balanceModel,
+		LedgerEntryModel:        NewMockLedgerEntryModel(),
+		WithdrawalAuditLogModel: auditLogModel,
+		ConfirmCodes:            confirmCodes,
+	}, txModel, balanceModel
+}
+
+func TestConfirmWithdrawLogic_ConfirmWithdraw(t *testing.T) {
+	svcCtx, txModel, balanceModel := newConfirmWithdrawEnv(newUnconfirmedWithdrawal(), nil)
+	txModel.On("UpdateStatus", mock.Anything, uint64(7), model.AssetTransactionStatusUnconfirmed, model.AssetTransactionStatusPending, "", "confirmed by user").Return(nil)
+
+	ctx := context.WithValue(context.Background(), "userId", float64(1))
+	resp, err := NewConfirmWithdrawLogic(ctx, svcCtx).ConfirmWithdraw(&types.ConfirmWithdrawRequest{TransactionID: "WTH_1", Code: "123456"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AssetTransactionStatusPending, resp.Status)
+	txModel.AssertExpectations(t)
+	// 确认后金额仍冻结
+	balanceModel.AssertNotCalled(t, "UnfreezeBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConfirmWithdrawLogic_InvalidCode(t *testing.T) {
+	svcCtx, txModel, _ := newConfirmWithdrawEnv(newUnconfirmedWithdrawal(), onetime.ErrCodeInvalid)
+
+	ctx := context.WithValue(context.Background(), "userId", float64(1))
+	resp, err := NewConfirmWithdrawLogic(ctx, svcCtx).ConfirmWithdraw(&types.ConfirmWithdrawRequest{TransactionID: "WTH_1", Code: "000000"})
+
+	assert.Equal(t, model.ErrWithdrawConfirmationInvalid, err)
+	assert.Nil(t, resp)
+	txModel.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConfirmWithdrawLogic_ExpiredCodeCancels(t *testing.T) {
+	for _, verifyErr := range []error{onetime.ErrCodeExpired, onetime.ErrTooManyAttempts} {
+		svcCtx, txModel, balanceModel := newConfirmWithdrawEnv(newUnconfirmedWithdrawal(), verifyErr)
+		txModel.On("UpdateStatus", mock.Anything, uint64(7), model.AssetTransactionStatusUnconfirmed, model.AssetTransactionStatusCancelled, "", verifyErr.Error()).Return(nil)
+		balanceModel.On("UnfreezeBalance", mock.Anything, uint64(1), "BTC", "1.0005").Return(nil)
+
+		ctx := context.WithValue(context.Background(), "userId", float64(1))
+		_, err := NewConfirmWithdrawLogic(ctx, svcCtx).ConfirmWithdraw(&types.ConfirmWithdrawRequest{TransactionID: "WTH_1", Code: "123456"})
+
+		// 确认码失效时取消提现并退回冻结金额
+		assert.Equal(t, model.ErrWithdrawConfirmationExpired, err)
+		txModel.AssertExpectations(t)
+		balanceModel.AssertExpectations(t)
+	}
+}
+
+func TestConfirmWithdrawLogic_NotOwnerOrConfirmed(t *testing.T) {
+	other := newUnconfirmedWithdrawal()
+	other.UserID = 2
+	svcCtx, _, _ := newConfirmWithdrawEnv(other, nil)
+	ctx := context.WithValue(context.Background(), "userId", float64(1))
+	_, err := NewConfirmWithdrawLogic(ctx, svcCtx).ConfirmWithdraw(&types.ConfirmWithdrawRequest{TransactionID: "WTH_1", Code: "123456"})
+	assert.Equal(t, model.ErrWithdrawalNotFound, err)
+
+	confirmed := newUnconfirmedWithdrawal()
+	confirmed.Status = model.AssetTransactionStatusPending
+	svcCtx, _, _ = newConfirmWithdrawEnv(confirmed, nil)
+	_, err = NewConfirmWithdrawLogic(ctx, svcCtx).ConfirmWithdraw(&types.ConfirmWithdrawRequest{TransactionID: "WTH_1", Code: "123456"})
+	assert.ErrorIs(t, err, model.ErrInvalidWithdrawalTransition)
+}
@@ -0,0 +1,64 @@
+package asset
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/securityevent"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DeleteWithdrawAddressLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeleteWithdrawAddressLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeleteWithdrawAddressLogic {
+	return &DeleteWithdrawAddressLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeleteWithdrawAddressLogic) DeleteWithdrawAddress(req *types.DeleteWithdrawAddressRequest) (resp *types.WithdrawAddress, err error) {
+	// 1. 从JWT上下文中获取用户ID
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		l.Errorf("Failed to get user ID from context: %v", err)
+		return nil, model.ErrUnauthorized
+	}
+
+	// 2. 只能删除本人的地址
+	entry, err := l.svcCtx.WithdrawalAddressModel.FindOne(l.ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrWithdrawAddressNotFound
+		}
+		l.Errorf("Failed to find withdraw address %d: %v", req.ID, err)
+		return nil, model.ErrInternalServer
+	}
+	if entry.UserID != userID {
+		return nil, model.ErrWithdrawAddressNotFound
+	}
+
+	if err := l.svcCtx.WithdrawalAddressModel.DeleteByUser(l.ctx, userID, req.ID); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrWithdrawAddressNotFound
+		}
+		l.Errorf("Failed to delete withdraw address %d for user %d: %v", req.ID, userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	securityevent.Record(l.ctx, l.svcCtx, userID, model.SecurityEventWithdrawAddressDeleted, entry.Network+" "+entry.Address)
+	l.Infof("User %d deleted withdraw address %s on %s", userID, entry.Address, entry.Network)
+	converted := convertWithdrawAddress(entry, time.Now())
+	return &converted, nil
+}
@@ -0,0 +1,79 @@
+package asset
+
+import (
+	"context"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetAccountStatementLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetAccountStatementLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetAccountStatementLogic {
+	return &GetAccountStatementLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetAccountStatementLogic) GetAccountStatement(req *types.AccountStatementRequest) (resp *types.AccountStatementResponse, err error) {
+	// 1. 从JWT上下文中获取用户ID
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		l.Errorf("Failed to get user ID from context: %v", err)
+		return nil, model.ErrUnauthorized
+	}
+
+	// 2. 规范化分页参数
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	size := req.Size
+	if size <= 0 {
+		size = 20
+	}
+	if size > 100 {
+		size = 100
+	}
+
+	// 3. 查询用户的账本分录
+	entries, total, err := l.svcCtx.LedgerEntryModel.FindByUserIDWithPagination(l.ctx, userID, req.Currency, page, size)
+	if err != nil {
+		l.Errorf("Failed to find ledger entries for user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	// 4. 转换为响应格式
+	respEntries := make([]types.LedgerEntry, 0, len(entries))
+	for _, entry := range entries {
+		respEntries = append(respEntries, types.LedgerEntry{
+			ID:        entry.ID,
+			JournalID: entry.JournalID,
+			Currency:  entry.Currency,
+			Account:   entry.Account,
+			Direction: entry.Direction,
+			Amount:    entry.Amount,
+			BizType:   entry.BizType,
+			BizID:     entry.BizID,
+			Remark:    entry.Remark,
+			CreatedAt: entry.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	return &types.AccountStatementResponse{
+		Entries: respEntries,
+		Total:   total,
+		Page:    page,
+		Size:    size,
+	}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"crypto-exchange/internal/authctx"
 	"crypto-exchange/internal/svc"
 	"crypto-exchange/internal/types"
 	"crypto-exchange/model"
@@ -28,7 +29,7 @@ func NewGetBalancesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetBa
 
 func (l *GetBalancesLogic) GetBalances() (resp *types.BalanceResponse, err error) {
 	// 1. 从JWT上下文中获取用户ID
-	userID, err := l.getUserIDFromContext()
+	userID, err := authctx.UserID(l.ctx)
 	if err != nil {
 		l.Errorf("Failed to get user ID from context: %v", err)
 		return nil, model.ErrUnauthorized
@@ -72,31 +73,6 @@ func (l *GetBalancesLogic) GetBalances() (resp *types.BalanceResponse, err error
 	return resp, nil
 }
 
-// getUserIDFromContext 从JWT上下文中获取用户ID
-func (l *GetBalancesLogic) getUserIDFromContext() (uint64, error) {
-	// 在go-zero中，JWT中间件会将解析后的claims存储在context中
-	// 键名通常是 "userId"，这与我们在登录时设置的claims一致
-	userIDValue := l.ctx.Value("userId")
-	if userIDValue == nil {
-		return 0, errors.New("user ID not found in context")
-	}
-
-	// JWT claims中的数值通常是float64类型
-	switch v := userIDValue.(type) {
-	case float64:
-		return uint64(v), nil
-	case uint64:
-		return v, nil
-	case int64:
-		return uint64(v), nil
-	case int:
-		return uint64(v), nil
-	default:
-		l.Errorf("Invalid user ID type in context: %T", v)
-		return 0, errors.New("invalid user ID type in context")
-	}
-}
-
 // validateBalanceAmounts 验证余额数值的有效性
 func (l *GetBalancesLogic) validateBalanceAmounts(available, frozen string) error {
 	// 验证可用余额格式
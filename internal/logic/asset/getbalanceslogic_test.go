@@ -205,79 +205,6 @@ func TestGetBalancesLogic_GetBalances(t *testing.T) {
 	}
 }
 
-func TestGetBalancesLogic_getUserIDFromContext(t *testing.T) {
-	tests := []struct {
-		name           string
-		contextValue   interface{}
-		expectedUserID uint64
-		expectedError  bool
-	}{
-		{
-			name:           "float64类型的用户ID",
-			contextValue:   float64(123),
-			expectedUserID: 123,
-			expectedError:  false,
-		},
-		{
-			name:           "uint64类型的用户ID",
-			contextValue:   uint64(456),
-			expectedUserID: 456,
-			expectedError:  false,
-		},
-		{
-			name:           "int64类型的用户ID",
-			contextValue:   int64(789),
-			expectedUserID: 789,
-			expectedError:  false,
-		},
-		{
-			name:           "int类型的用户ID",
-			contextValue:   int(101112),
-			expectedUserID: 101112,
-			expectedError:  false,
-		},
-		{
-			name:           "无效类型的用户ID",
-			contextValue:   "invalid",
-			expectedUserID: 0,
-			expectedError:  true,
-		},
-		{
-			name:           "上下文中无用户ID",
-			contextValue:   nil,
-			expectedUserID: 0,
-			expectedError:  true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// 创建上下文
-			var ctx context.Context
-			if tt.contextValue != nil {
-				ctx = context.WithValue(context.Background(), "userId", tt.contextValue)
-			} else {
-				ctx = context.Background()
-			}
-
-			// 创建逻辑实例
-			logic := NewGetBalancesLogic(ctx, &svc.ServiceContext{})
-
-			// 执行测试
-			userID, err := logic.getUserIDFromContext()
-
-			// 验证结果
-			if tt.expectedError {
-				assert.Error(t, err)
-				assert.Equal(t, uint64(0), userID)
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedUserID, userID)
-			}
-		})
-	}
-}
-
 func TestGetBalancesLogic_validateBalanceAmounts(t *testing.T) {
 	logic := NewGetBalancesLogic(context.Background(), &svc.ServiceContext{})
 
@@ -336,4 +263,4 @@ func TestGetBalancesLogic_validateBalanceAmounts(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
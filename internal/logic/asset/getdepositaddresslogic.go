@@ -0,0 +1,64 @@
+package asset
+
+import (
+	"context"
+	"strings"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/deposit"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetDepositAddressLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetDepositAddressLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetDepositAddressLogic {
+	return &GetDepositAddressLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetDepositAddressLogic) GetDepositAddress(req *types.DepositAddressRequest) (resp *types.DepositAddressResponse, err error) {
+	// 1. 从JWT上下文中获取用户ID
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		l.Errorf("Failed to get user ID from context: %v", err)
+		return nil, model.ErrUnauthorized
+	}
+
+	// 2. 验证参数，币种和网络代码统一为大写
+	if req.Currency == "" {
+		return nil, model.ErrInvalidParams
+	}
+	code := strings.ToUpper(req.Currency)
+	network := strings.ToUpper(req.Network)
+
+	// 3. 获取或分配充值地址
+	address, networkConfig, err := deposit.NewAddressAllocator(l.ctx, l.svcCtx).Allocate(userID, code, network)
+	if err != nil {
+		l.Errorf("Failed to get deposit address of %s on %s for user %d: %v", code, network, userID, err)
+		return nil, err
+	}
+
+	confirmations := networkConfig.Confirmations
+	if confirmations < 1 {
+		confirmations = 1
+	}
+
+	return &types.DepositAddressResponse{
+		Currency:      address.Currency,
+		Network:       address.Network,
+		Address:       address.Address,
+		MinDeposit:    networkConfig.MinDeposit,
+		Confirmations: confirmations,
+	}, nil
+}
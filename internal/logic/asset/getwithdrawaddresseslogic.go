@@ -0,0 +1,80 @@
+package asset
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetWithdrawAddressesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetWithdrawAddressesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetWithdrawAddressesLogic {
+	return &GetWithdrawAddressesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *GetWithdrawAddressesLogic) GetWithdrawAddresses() (resp *types.WithdrawAddressListResponse, err error) {
+	// 1. 从JWT上下文中获取用户ID
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		l.Errorf("Failed to get user ID from context: %v", err)
+		return nil, model.ErrUnauthorized
+	}
+
+	// 2. 查询白名单设置和地址簿
+	user, err := l.svcCtx.UserModel.FindOne(l.ctx, userID)
+	if err != nil {
+		l.Errorf("Failed to find user %d: %v", userID, err)
+		return nil, err
+	}
+
+	addresses, err := l.svcCtx.WithdrawalAddressModel.FindByUserID(l.ctx, userID)
+	if err != nil {
+		l.Errorf("Failed to find withdraw addresses for user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	now := time.Now()
+	list := make([]types.WithdrawAddress, 0, len(addresses))
+	for _, address := range addresses {
+		list = append(list, convertWithdrawAddress(address, now))
+	}
+
+	// 已关闭白名单但仍在冷却期内时返回冷却截止时间
+	var until sql.NullTime
+	if !user.WithdrawWhitelistOnly && user.WithdrawWhitelistEnforced(now) {
+		until = user.WithdrawWhitelistUntil
+	}
+	return &types.WithdrawAddressListResponse{
+		WhitelistOnly:  user.WithdrawWhitelistOnly,
+		WhitelistUntil: formatWhitelistUntil(until),
+		Addresses:      list,
+	}, nil
+}
+
+// convertWithdrawAddress 转换为地址簿响应格式
+func convertWithdrawAddress(address *model.WithdrawalAddress, now time.Time) types.WithdrawAddress {
+	return types.WithdrawAddress{
+		ID:          address.ID,
+		Network:     address.Network,
+		Address:     address.Address,
+		Label:       address.Label,
+		Available:   !now.Before(address.AvailableAt),
+		AvailableAt: address.AvailableAt.Format("2006-01-02 15:04:05"),
+		CreatedAt:   address.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
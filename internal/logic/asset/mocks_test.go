@@ -0,0 +1,370 @@
+package asset
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"crypto-exchange/internal/currency"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// MockBalanceModel 模拟BalanceModel接口
+type MockBalanceModel struct {
+	mock.Mock
+}
+
+func (m *MockBalanceModel) Insert(ctx context.Context, data *model.Balance) (sql.Result, error) {
+	args := m.Called(ctx, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *MockBalanceModel) FindOne(ctx context.Context, id uint64) (*model.Balance, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Balance), args.Error(1)
+}
+
+func (m *MockBalanceModel) FindByUserID(ctx context.Context, userID uint64) ([]*model.Balance, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Balance), args.Error(1)
+}
+
+func (m *MockBalanceModel) FindByUserIDAndCurrency(ctx context.Context, userID uint64, currency string) (*model.Balance, error) {
+	args := m.Called(ctx, userID, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Balance), args.Error(1)
+}
+
+func (m *MockBalanceModel) FindByUserIDAndCurrencyForUpdate(ctx context.Context, userID uint64, currency string) (*model.Balance, error) {
+	args := m.Called(ctx, userID, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Balance), args.Error(1)
+}
+
+func (m *MockBalanceModel) UpdateBalance(ctx context.Context, userID uint64, currency string, available, frozen string) error {
+	args := m.Called(ctx, userID, currency, available, frozen)
+	return args.Error(0)
+}
+
+func (m *MockBalanceModel) FreezeBalance(ctx context.Context, userID uint64, currency string, amount string) error {
+	args := m.Called(ctx, userID, currency, amount)
+	return args.Error(0)
+}
+
+func (m *MockBalanceModel) UnfreezeBalance(ctx context.Context, userID uint64, currency string, amount string) error {
+	args := m.Called(ctx, userID, currency, amount)
+	return args.Error(0)
+}
+
+func (m *MockBalanceModel) AddAvailable(ctx context.Context, userID uint64, currency string, amount string) error {
+	args := m.Called(ctx, userID, currency, amount)
+	return args.Error(0)
+}
+
+func (m *MockBalanceModel) DeductFrozen(ctx context.Context, userID uint64, currency string, amount string) error {
+	args := m.Called(ctx, userID, currency, amount)
+	return args.Error(0)
+}
+
+func (m *MockBalanceModel) FindAll(ctx context.Context) ([]*model.Balance, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*model.Balance), args.Error(1)
+}
+
+func (m *MockBalanceModel) CountByCurrency(ctx context.Context, currency string) (int64, error) {
+	args := m.Called(ctx, currency)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockBalanceModel) Update(ctx context.Context, data *model.Balance) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockBalanceModel) Delete(ctx context.Context, id uint64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockBalanceModel) Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	args := m.Called(ctx, fn)
+	// 执行传入的函数并返回其结果
+	if fn != nil {
+		return fn(ctx, nil)
+	}
+	return args.Error(0)
+}
+
+func (m *MockBalanceModel) TransRepeatableRead(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	return fn(ctx, nil)
+}
+
+func (m *MockBalanceModel) WithSession(session sqlx.Session) model.BalanceModel {
+	return m
+}
+
+// MockCurrencyModel 模拟CurrencyModel接口，FindAll返回固定的币种配置
+type MockCurrencyModel struct {
+	model.CurrencyModel
+	currencies []*model.Currency
+}
+
+func (m *MockCurrencyModel) FindAll(ctx context.Context) ([]*model.Currency, error) {
+	return m.currencies, nil
+}
+
+// MockCurrencyNetworkModel 模拟CurrencyNetworkModel接口，FindAll返回固定的网络配置
+type MockCurrencyNetworkModel struct {
+	model.CurrencyNetworkModel
+	networks []*model.CurrencyNetwork
+}
+
+func (m *MockCurrencyNetworkModel) FindAll(ctx context.Context) ([]*model.CurrencyNetwork, error) {
+	return m.networks, nil
+}
+
+// NewTestCurrencyRegistry 创建测试用币种注册表，配置与初始化脚本中的币种及网络数据一致
+func NewTestCurrencyRegistry() *currency.Registry {
+	newCurrency := func(code, maxWithdraw string) *model.Currency {
+		return &model.Currency{
+			Code:            code,
+			Name:            code,
+			Precision:       8,
+			DepositEnabled:  true,
+			WithdrawEnabled: true,
+			MaxDeposit:      "1000000",
+			MaxWithdraw:     maxWithdraw,
+			Status:          model.CurrencyStatusEnabled,
+		}
+	}
+	newNetwork := func(code, network, addressType, fee, minWithdraw string) *model.CurrencyNetwork {
+		return &model.CurrencyNetwork{
+			Currency:         code,
+			Network:          network,
+			AddressType:      addressType,
+			DepositEnabled:   true,
+			WithdrawEnabled:  true,
+			WithdrawFeeFixed: fee,
+			WithdrawFeeRate:  "0",
+			MinDeposit:       "0.00000001",
+			MinWithdraw:      minWithdraw,
+		}
+	}
+
+	currencyModel := &MockCurrencyModel{currencies: []*model.Currency{
+		newCurrency("BTC", "10"),
+		newCurrency("ETH", "100"),
+		newCurrency("USDT", "100000"),
+		newCurrency("USDC", "100000"),
+		{Code: "BNB", Name: "BNB", Precision: 8, Status: model.CurrencyStatusEnabled}, // 未开放充提
+	}}
+	networkModel := &MockCurrencyNetworkModel{networks: []*model.CurrencyNetwork{
+		newNetwork("BTC", "BTC", model.AddressTypeBitcoin, "0.0005", "0.001"),
+		newNetwork("ETH", "ERC20", model.AddressTypeEVM, "0.005", "0.01"),
+		newNetwork("USDT", "ERC20", model.AddressTypeEVM, "1", "10"),
+		newNetwork("USDT", "TRC20", model.AddressTypeTron, "0.8", "10"),
+		newNetwork("USDC", "ERC20", model.AddressTypeEVM, "1", "10"),
+		{Currency: "BNB", Network: "BEP20", AddressType: model.AddressTypeEVM},
+	}}
+
+	return currency.NewRegistry(currencyModel, networkModel, time.Minute)
+}
+
+// MockLedgerEntryModel 模拟LedgerEntryModel接口
+type MockLedgerEntryModel struct {
+	mock.Mock
+}
+
+// NewMockLedgerEntryModel 创建账本模型mock，默认接受所有记账凭证
+func NewMockLedgerEntryModel() *MockLedgerEntryModel {
+	m := &MockLedgerEntryModel{}
+	m.On("InsertJournal", mock.Anything, mock.Anything).Return(nil)
+	return m
+}
+
+func (m *MockLedgerEntryModel) Insert(ctx context.Context, data *model.LedgerEntry) (sql.Result, error) {
+	args := m.Called(ctx, data)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *MockLedgerEntryModel) FindOne(ctx context.Context, id uint64) (*model.LedgerEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.LedgerEntry), args.Error(1)
+}
+
+func (m *MockLedgerEntryModel) Update(ctx context.Context, data *model.LedgerEntry) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockLedgerEntryModel) Delete(ctx context.Context, id uint64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockLedgerEntryModel) InsertJournal(ctx context.Context, journal *model.LedgerJournal) error {
+	args := m.Called(ctx, journal)
+	return args.Error(0)
+}
+
+func (m *MockLedgerEntryModel) FindByJournalID(ctx context.Context, journalID string) ([]*model.LedgerEntry, error) {
+	args := m.Called(ctx, journalID)
+	return args.Get(0).([]*model.LedgerEntry), args.Error(1)
+}
+
+func (m *MockLedgerEntryModel) FindByUserIDWithPagination(ctx context.Context, userID uint64, currency string, page, size int64) ([]*model.LedgerEntry, int64, error) {
+	args := m.Called(ctx, userID, currency, page, size)
+	return args.Get(0).([]*model.LedgerEntry), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockLedgerEntryModel) Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	_ = m.Called(ctx, fn)
+	return fn(ctx, nil)
+}
+
+func (m *MockLedgerEntryModel) WithSession(session sqlx.Session) model.LedgerEntryModel {
+	return m
+}
+
+// MockAssetTransactionModel 模拟AssetTransactionModel接口
+type MockAssetTransactionModel struct {
+	mock.Mock
+}
+
+func (m *MockAssetTransactionModel) Insert(ctx context.Context, data *model.AssetTransaction) (sql.Result, error) {
+	args := m.Called(ctx, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) FindOne(ctx context.Context, id uint64) (*model.AssetTransaction, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AssetTransaction), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) FindByUserID(ctx context.Context, userID uint64, limit, offset int64) ([]*model.AssetTransaction, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.AssetTransaction), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) FindByTransactionID(ctx context.Context, transactionID string) (*model.AssetTransaction, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AssetTransaction), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) FindByUserIDAndType(ctx context.Context, userID uint64, transactionType int64, limit, offset int64) ([]*model.AssetTransaction, error) {
+	args := m.Called(ctx, userID, transactionType, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.AssetTransaction), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) SumNetFlowByUserAndCurrency(ctx context.Context) ([]*model.BalanceFlow, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*model.BalanceFlow), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) SumInFlightWithdrawalsByUserAndCurrency(ctx context.Context) ([]*model.BalanceFlow, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*model.BalanceFlow), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) FindByTypeAndStatus(ctx context.Context, transactionType, status int64, limit, offset int64) ([]*model.AssetTransaction, error) {
+	args := m.Called(ctx, transactionType, status, limit, offset)
+	return args.Get(0).([]*model.AssetTransaction), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) CountByTypeAndStatus(ctx context.Context, transactionType, status int64) (int64, error) {
+	args := m.Called(ctx, transactionType, status)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) UpdateStatus(ctx context.Context, id uint64, fromStatus, toStatus int64, txHash, remark string) error {
+	args := m.Called(ctx, id, fromStatus, toStatus, txHash, remark)
+	return args.Error(0)
+}
+
+func (m *MockAssetTransactionModel) FindChainDepositsSince(ctx context.Context, network string, fromHeight int64) ([]*model.AssetTransaction, error) {
+	args := m.Called(ctx, network, fromHeight)
+	return args.Get(0).([]*model.AssetTransaction), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) UpdateConfirmations(ctx context.Context, id uint64, blockHeight, confirmations int64) error {
+	args := m.Called(ctx, id, blockHeight, confirmations)
+	return args.Error(0)
+}
+
+func (m *MockAssetTransactionModel) FindWithdrawalsSince(ctx context.Context, userID uint64, since time.Time) ([]*model.AssetTransaction, error) {
+	args := m.Called(ctx, userID, since)
+	return args.Get(0).([]*model.AssetTransaction), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) FindWithdrawalsSinceForUpdate(ctx context.Context, userID uint64, since time.Time) ([]*model.AssetTransaction, error) {
+	args := m.Called(ctx, userID, since)
+	return args.Get(0).([]*model.AssetTransaction), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) FindByUserIDAndTypeSince(ctx context.Context, userID uint64, transactionType int64, since time.Time) ([]*model.AssetTransaction, error) {
+	args := m.Called(ctx, userID, transactionType, since)
+	return args.Get(0).([]*model.AssetTransaction), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) FindByUserIDAndTypeSinceForUpdate(ctx context.Context, userID uint64, transactionType int64, since time.Time) ([]*model.AssetTransaction, error) {
+	args := m.Called(ctx, userID, transactionType, since)
+	return args.Get(0).([]*model.AssetTransaction), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) CountByUserID(ctx context.Context, userID uint64) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) CountByUserIDAndType(ctx context.Context, userID uint64, transactionType int64) (int64, error) {
+	args := m.Called(ctx, userID, transactionType)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAssetTransactionModel) Update(ctx context.Context, data *model.AssetTransaction) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockAssetTransactionModel) Delete(ctx context.Context, id uint64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAssetTransactionModel) WithSession(session sqlx.Session) model.AssetTransactionModel {
+	return m
+}
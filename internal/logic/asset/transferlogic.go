@@ -0,0 +1,363 @@
+package asset
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/market"
+	"crypto-exchange/internal/logic/restriction"
+	"crypto-exchange/internal/logic/twofactor"
+	"crypto-exchange/internal/logic/useremail"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+const (
+	maxClientTransferIDLength = 64  // 客户端幂等键最大长度
+	maxTransferRemarkLength   = 128 // 转账备注最大长度（字符）
+)
+
+// InternalTransfer 一笔站内划转：付款用户的可用余额直接转入收款用户的可用余额，不经过链上
+type InternalTransfer struct {
+	TransferID string          // 划转ID，双方交易ID分别为划转ID加 _OUT / _IN 后缀
+	FromUserID uint64          // 付款用户ID
+	ToUserID   uint64          // 收款用户ID
+	Currency   string          // 币种代码
+	Amount     decimal.Decimal // 划转金额
+	OutType    int64           // 付款方交易记录类型
+	InType     int64           // 收款方交易记录类型
+	Remark     string          // 备注，为空时生成默认备注
+	CheckLimit bool            // 是否在事务中锁定付款用户后校验其24小时转出限额
+}
+
+type TransferLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewTransferLogic(ctx context.Context, svcCtx *svc.ServiceContext) *TransferLogic {
+	return &TransferLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Transfer 站内转账：从当前用户的可用余额转给另一个用户，不经过链上，双方余额在同一个事务中更新
+// 双方各生成一条交易记录（转出/转入），交易ID分别为转账ID加 _OUT / _IN 后缀
+func (l *TransferLogic) Transfer(req *types.TransferRequest) (resp *types.TransferResponse, err error) {
+	// 1. 从JWT上下文中获取用户ID
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		l.Errorf("Failed to get user ID from context: %v", err)
+		return nil, model.ErrUnauthorized
+	}
+
+	// 2. 与提现相同，未验证邮箱或被限制提现的用户不能转出
+	user, err := useremail.Require(l.ctx, l.svcCtx, userID)
+	if err != nil {
+		l.Errorf("Transfer rejected for user %d: %v", userID, err)
+		return nil, err
+	}
+	if err := restriction.Check(l.ctx, l.svcCtx, user, model.RestrictionWithdraw); err != nil {
+		l.Errorf("Transfer rejected for user %d: %v", userID, err)
+		return nil, err
+	}
+
+	// 3. 验证转账参数
+	amount, err := l.validateTransferRequest(req)
+	if err != nil {
+		l.Errorf("Invalid transfer request for user %d: %v", userID, err)
+		return nil, err
+	}
+
+	// 4. 查找收款用户
+	recipient, err := l.findRecipient(req)
+	if err != nil {
+		return nil, err
+	}
+	if recipient.ID == userID {
+		return nil, model.ErrTransferToSelf
+	}
+
+	// 5. 幂等处理：相同幂等键的转账已存在时直接返回首次结果
+	transferID := l.generateTransferID(userID, req.ClientTransferID)
+	if req.ClientTransferID != "" {
+		existing, err := l.svcCtx.AssetTransactionModel.FindByTransactionID(l.ctx, transferID+"_OUT")
+		if err == nil {
+			return l.replay(existing, transferID, req.Currency, amount, recipient.ID)
+		}
+		if !errors.Is(err, model.ErrNotFound) {
+			l.Errorf("Failed to find transfer %s: %v", transferID, err)
+			return nil, model.ErrInternalServer
+		}
+	}
+
+	// 6. 已启用两步验证的用户需要提供新的验证码，放在其他校验之后以免验证码被无效请求消耗
+	if err := twofactor.Require(l.ctx, l.svcCtx, userID, req.TotpCode); err != nil {
+		l.Errorf("Transfer rejected by two-factor check for user %d: %v", userID, err)
+		return nil, err
+	}
+
+	// 7. 在数据库事务中校验24小时转出限额并完成划转
+	now, err := l.Execute(&InternalTransfer{
+		TransferID: transferID,
+		FromUserID: userID,
+		ToUserID:   recipient.ID,
+		Currency:   req.Currency,
+		Amount:     amount,
+		OutType:    model.AssetTransactionTypeTransferOut,
+		InType:     model.AssetTransactionTypeTransferIn,
+		Remark:     req.Remark,
+		CheckLimit: true,
+	})
+	if err != nil {
+		// 相同幂等键的并发请求先提交时，本请求因交易ID唯一约束冲突（或锁定后的限额、余额校验）失败，返回先提交的转账结果
+		if req.ClientTransferID != "" {
+			if existing, findErr := l.svcCtx.AssetTransactionModel.FindByTransactionID(l.ctx, transferID+"_OUT"); findErr == nil {
+				return l.replay(existing, transferID, req.Currency, amount, recipient.ID)
+			}
+		}
+		return nil, err
+	}
+
+	l.Infof("Transfer %s: user %d sent %s %s to user %d", transferID, userID, req.Amount, req.Currency, recipient.ID)
+	return &types.TransferResponse{
+		TransferID: transferID,
+		Currency:   req.Currency,
+		Amount:     req.Amount,
+		FromUserID: userID,
+		ToUserID:   recipient.ID,
+		Status:     model.AssetTransactionStatusSuccess,
+		CreatedAt:  now.Format("2006-01-02 15:04:05"),
+	}, nil
+}
+
+// Execute 在一个数据库事务中完成划转：按用户ID顺序锁定并增减双方可用余额，写入双方交易记录并记账
+// 调用方负责参数和收款用户状态等业务校验，CheckLimit为true时在事务中校验转出限额，返回划转时间
+func (l *TransferLogic) Execute(t *InternalTransfer) (time.Time, error) {
+	now := time.Now()
+	err := l.svcCtx.BalanceModel.Trans(l.ctx, func(ctx context.Context, session sqlx.Session) error {
+		balances := l.svcCtx.BalanceModel.WithSession(session)
+		transactions := l.svcCtx.AssetTransactionModel.WithSession(session)
+		ledger := l.svcCtx.LedgerEntryModel.WithSession(session)
+
+		// 先锁定付款用户行再统计窗口内的转出，同一用户的并发转账在锁上排队，不会同时通过限额检查
+		if t.CheckLimit {
+			if err := l.checkDailyLimits(ctx, transactions, t.FromUserID, t.Currency, t.Amount); err != nil {
+				l.Errorf("Transfer of %s %s rejected by daily limits for user %d: %v", t.Amount.String(), t.Currency, t.FromUserID, err)
+				return err
+			}
+		}
+
+		// 扣减付款用户、增加收款用户可用余额（余额行加锁后相对更新，余额不足时回滚），
+		// 按用户ID从小到大加锁，避免相向划转死锁
+		changes := []struct {
+			userID uint64
+			amount decimal.Decimal
+		}{
+			{t.FromUserID, t.Amount.Neg()},
+			{t.ToUserID, t.Amount},
+		}
+		if t.ToUserID < t.FromUserID {
+			changes[0], changes[1] = changes[1], changes[0]
+		}
+		for _, change := range changes {
+			if err := balances.AddAvailable(ctx, change.userID, t.Currency, change.amount.String()); err != nil {
+				if errors.Is(err, model.ErrNotFound) {
+					return model.ErrBalanceNotFound
+				}
+				if !errors.Is(err, model.ErrInsufficientBalance) {
+					l.Errorf("Failed to update balance for user %d, currency %s: %v", change.userID, t.Currency, err)
+				}
+				return err
+			}
+		}
+
+		// 写入双方交易记录，交易ID唯一约束保证相同幂等键的并发请求只有一个成功
+		outRemark, inRemark := t.Remark, t.Remark
+		if outRemark == "" {
+			outRemark = fmt.Sprintf("Transfer %s %s to user %d", t.Amount.String(), t.Currency, t.ToUserID)
+			inRemark = fmt.Sprintf("Transfer %s %s from user %d", t.Amount.String(), t.Currency, t.FromUserID)
+		}
+		records := []*model.AssetTransaction{
+			newTransferRecord(t.FromUserID, t.ToUserID, t.TransferID+"_OUT", t.OutType, t, outRemark, now),
+			newTransferRecord(t.ToUserID, t.FromUserID, t.TransferID+"_IN", t.InType, t, inRemark, now),
+		}
+		for _, record := range records {
+			if _, err := transactions.Insert(ctx, record); err != nil {
+				l.Errorf("Failed to create transaction record %s: %v", record.TransactionID, err)
+				return err
+			}
+		}
+
+		// 记账：付款用户可用余额 -> 收款用户可用余额
+		journal := model.NewLedgerJournal(model.LedgerBizTransfer, t.TransferID)
+		journal.Transfer(t.Currency, t.Amount.String(), model.UserAvailable(t.FromUserID), model.UserAvailable(t.ToUserID), "internal transfer")
+		if err := ledger.InsertJournal(ctx, journal); err != nil {
+			l.Errorf("Failed to record ledger for transfer %s: %v", t.TransferID, err)
+			return err
+		}
+
+		return nil
+	})
+	return now, err
+}
+
+// validateTransferRequest 验证转账请求参数，返回转账金额
+func (l *TransferLogic) validateTransferRequest(req *types.TransferRequest) (decimal.Decimal, error) {
+	req.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+	req.ToEmail = strings.TrimSpace(req.ToEmail)
+	req.ClientTransferID = strings.TrimSpace(req.ClientTransferID)
+	req.Remark = strings.TrimSpace(req.Remark)
+	if req.Currency == "" || len(req.ClientTransferID) > maxClientTransferIDLength ||
+		utf8.RuneCountInString(req.Remark) > maxTransferRemarkLength {
+		return decimal.Zero, model.ErrInvalidParams
+	}
+	if (req.ToUserID == 0) == (req.ToEmail == "") {
+		return decimal.Zero, model.ErrTransferRecipientRequired
+	}
+
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil || !amount.IsPositive() {
+		return decimal.Zero, model.ErrInvalidAmount
+	}
+
+	// 按币种配置校验是否启用以及金额精度
+	if _, err := l.svcCtx.CurrencyRegistry.ValidateTransfer(l.ctx, req.Currency, amount); err != nil {
+		return decimal.Zero, err
+	}
+	return amount, nil
+}
+
+// findRecipient 按用户ID或邮箱查找收款用户，收款用户必须处于正常状态且未被冻结
+func (l *TransferLogic) findRecipient(req *types.TransferRequest) (*model.User, error) {
+	var recipient *model.User
+	var err error
+	if req.ToUserID != 0 {
+		recipient, err = l.svcCtx.UserModel.FindOne(l.ctx, req.ToUserID)
+	} else {
+		recipient, err = l.svcCtx.UserModel.FindOneByEmail(l.ctx, req.ToEmail)
+	}
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrUserNotFound
+		}
+		l.Errorf("Failed to find transfer recipient: %v", err)
+		return nil, model.ErrInternalServer
+	}
+	// 被禁用、注销或冻结的用户不能收款，冻结时同样返回ErrUserDisabled，不向付款方暴露收款用户的限制详情
+	if err := restriction.Check(l.ctx, l.svcCtx, recipient, model.RestrictionFreeze); err != nil {
+		l.Errorf("Transfer recipient %d rejected: %v", recipient.ID, err)
+		if errors.Is(err, model.ErrAccountRestricted) {
+			return nil, model.ErrUserDisabled
+		}
+		return nil, err
+	}
+	return recipient, nil
+}
+
+// replay 返回相同幂等键的首次转账结果，币种、金额或收款人不一致时拒绝
+func (l *TransferLogic) replay(existing *model.AssetTransaction, transferID, currency string, amount decimal.Decimal, recipientID uint64) (*types.TransferResponse, error) {
+	existingAmount, err := decimal.NewFromString(existing.Amount)
+	if err != nil || existing.Currency != currency || !existingAmount.Equal(amount) || existing.CounterpartyID != recipientID {
+		return nil, model.ErrTransferIdempotencyConflict
+	}
+
+	return &types.TransferResponse{
+		TransferID: transferID,
+		Currency:   existing.Currency,
+		Amount:     existing.Amount,
+		FromUserID: existing.UserID,
+		ToUserID:   existing.CounterpartyID,
+		Status:     existing.Status,
+		CreatedAt:  existing.CreatedAt.Format("2006-01-02 15:04:05"),
+	}, nil
+}
+
+// checkDailyLimits 校验24小时滚动窗口内的转出次数和金额（按提现限额的参考币种估值），本次转账计入窗口内
+// transactions为绑定事务session的模型，查询前锁定用户行
+func (l *TransferLogic) checkDailyLimits(ctx context.Context, transactions model.AssetTransactionModel, userID uint64, currency string, amount decimal.Decimal) error {
+	limits := l.svcCtx.Config.Transfer
+	dailyAmount, err := decimal.NewFromString(limits.DailyAmount)
+	if err != nil {
+		dailyAmount = decimal.Zero
+	}
+	if limits.DailyCount <= 0 && !dailyAmount.IsPositive() {
+		return nil
+	}
+
+	recent, err := transactions.FindByUserIDAndTypeSinceForUpdate(ctx, userID, model.AssetTransactionTypeTransferOut, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		l.Errorf("Failed to find recent transfers for user %d: %v", userID, err)
+		return model.ErrInternalServer
+	}
+	if limits.DailyCount > 0 && len(recent)+1 > limits.DailyCount {
+		return model.ErrTransferDailyCountExceeded
+	}
+	if !dailyAmount.IsPositive() {
+		return nil
+	}
+
+	valuator := market.NewValuator(ctx, l.svcCtx, l.svcCtx.Config.WithdrawLimit.ReferenceCurrency, l.svcCtx.Config.WithdrawLimit.PeggedCurrencies)
+	total, err := valuator.Value(currency, amount)
+	if err != nil {
+		return err
+	}
+	for _, tx := range recent {
+		txAmount, err := decimal.NewFromString(tx.Amount)
+		if err != nil {
+			l.Errorf("Invalid amount format for transfer %s: %s", tx.TransactionID, tx.Amount)
+			return model.ErrInternalServer
+		}
+		value, err := valuator.Value(tx.Currency, txAmount)
+		if err != nil {
+			return err
+		}
+		total = total.Add(value)
+	}
+	if total.GreaterThan(dailyAmount) {
+		return model.ErrTransferDailyAmountExceeded
+	}
+	return nil
+}
+
+// newTransferRecord 创建划转一方的交易记录
+func newTransferRecord(userID, counterpartyID uint64, transactionID string, transactionType int64, t *InternalTransfer, remark string, now time.Time) *model.AssetTransaction {
+	return &model.AssetTransaction{
+		UserID:         userID,
+		TransactionID:  transactionID,
+		Currency:       t.Currency,
+		Type:           transactionType,
+		Amount:         t.Amount.String(),
+		Fee:            "0",
+		Status:         model.AssetTransactionStatusSuccess,
+		CounterpartyID: counterpartyID,
+		Remark:         remark,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// generateTransferID 生成转账ID，提供幂等键时由用户ID和幂等键确定性生成
+func (l *TransferLogic) generateTransferID(userID uint64, clientTransferID string) string {
+	if clientTransferID == "" {
+		return fmt.Sprintf("TRF_%s", strings.ReplaceAll(uuid.New().String(), "-", ""))
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", userID, clientTransferID)))
+	return fmt.Sprintf("TRF_%s", hex.EncodeToString(sum[:16]))
+}
@@ -0,0 +1,268 @@
+package asset
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/config"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// setupTransfer 创建转账测试所需的服务上下文，用户1向用户2转账
+func setupTransfer(transfer func(c *config.Config)) (*svc.ServiceContext, *MockBalanceModel, *MockAssetTransactionModel, *MockLedgerEntryModel) {
+	var c config.Config
+	c.WithdrawLimit.ReferenceCurrency = "USDT"
+	if transfer != nil {
+		transfer(&c)
+	}
+
+	userModel := new(MockUserModel)
+	userModel.On("FindOne", mock.Anything, uint64(1)).Return(&model.User{ID: 1, Status: model.UserStatusActive, EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil)
+	userModel.On("FindOne", mock.Anything, uint64(2)).Return(&model.User{ID: 2, Email: "bob@example.com", Status: model.UserStatusActive}, nil)
+	userModel.On("FindOne", mock.Anything, uint64(3)).Return(&model.User{ID: 3, Status: model.UserStatusDisabled}, nil)
+	userModel.On("FindOne", mock.Anything, uint64(4)).Return(&model.User{ID: 4, Status: model.UserStatusActive}, nil)
+	userModel.On("FindOneByEmail", mock.Anything, "bob@example.com").Return(&model.User{ID: 2, Email: "bob@example.com", Status: model.UserStatusActive}, nil)
+
+	tickerModel := new(MockTickerModel)
+	tickerModel.On("FindBySymbol", mock.Anything, "BTC/USDT").Return(&model.Ticker{Symbol: "BTC/USDT", LastPrice: "50000"}, nil)
+
+	balanceModel := new(MockBalanceModel)
+	balanceModel.On("Trans", mock.Anything, mock.Anything).Return(nil)
+	txModel := new(MockAssetTransactionModel)
+	ledgerModel := NewMockLedgerEntryModel()
+	totpModel := new(MockUserTotpModel)
+	totpModel.On("FindOneByUserID", mock.Anything, uint64(1)).Return((*model.UserTotp)(nil), model.ErrNotFound)
+
+	svcCtx := &svc.ServiceContext{
+		UserRestrictionModel: &memoryRestrictionModel{restrictions: []*model.UserRestriction{
+			{UserID: 4, Type: model.RestrictionFreeze},
+		}},
+		Config:                c,
+		UserModel:             userModel,
+		TickerModel:           tickerModel,
+		BalanceModel:          balanceModel,
+		AssetTransactionModel: txModel,
+		LedgerEntryModel:      ledgerModel,
+		UserTotpModel:         totpModel,
+		CurrencyRegistry:      NewTestCurrencyRegistry(),
+	}
+	return svcCtx, balanceModel, txModel, ledgerModel
+}
+
+func transferContext(userID uint64) context.Context {
+	return context.WithValue(context.Background(), "userId", float64(userID))
+}
+
+func TestTransferLogic_Transfer(t *testing.T) {
+	logx.DisableStat()
+
+	svcCtx, balanceModel, txModel, ledgerModel := setupTransfer(nil)
+	balanceModel.On("AddAvailable", mock.Anything, uint64(1), "BTC", "-0.5").Return(nil)
+	balanceModel.On("AddAvailable", mock.Anything, uint64(2), "BTC", "0.5").Return(nil)
+	txModel.On("Insert", mock.Anything, mock.AnythingOfType("*model.AssetTransaction")).Return(nil, nil)
+
+	resp, err := NewTransferLogic(transferContext(1), svcCtx).Transfer(&types.TransferRequest{
+		Currency: "btc",
+		Amount:   "0.5",
+		ToEmail:  "bob@example.com",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), resp.FromUserID)
+	assert.Equal(t, uint64(2), resp.ToUserID)
+	assert.Equal(t, "BTC", resp.Currency)
+	assert.Equal(t, model.AssetTransactionStatusSuccess, resp.Status)
+
+	// 按用户ID顺序先扣减付款用户，再增加收款用户
+	balanceModel.AssertNumberOfCalls(t, "AddAvailable", 2)
+	assert.Equal(t, uint64(1), balanceModel.Calls[1].Arguments.Get(1))
+	assert.Equal(t, uint64(2), balanceModel.Calls[2].Arguments.Get(1))
+
+	// 双方各一条交易记录，互为对手方
+	txModel.AssertNumberOfCalls(t, "Insert", 2)
+	out := txModel.Calls[0].Arguments.Get(1).(*model.AssetTransaction)
+	in := txModel.Calls[1].Arguments.Get(1).(*model.AssetTransaction)
+	assert.Equal(t, resp.TransferID+"_OUT", out.TransactionID)
+	assert.Equal(t, model.AssetTransactionTypeTransferOut, out.Type)
+	assert.Equal(t, uint64(2), out.CounterpartyID)
+	assert.Equal(t, resp.TransferID+"_IN", in.TransactionID)
+	assert.Equal(t, model.AssetTransactionTypeTransferIn, in.Type)
+	assert.Equal(t, uint64(2), in.UserID)
+	assert.Equal(t, uint64(1), in.CounterpartyID)
+
+	// 记账凭证借贷平衡，从付款用户可用余额转入收款用户可用余额
+	journal := ledgerModel.Calls[0].Arguments.Get(1).(*model.LedgerJournal)
+	assert.Equal(t, model.LedgerBizTransfer, journal.BizType)
+	assert.NoError(t, journal.Validate())
+}
+
+func TestTransferLogic_Transfer_Rejected(t *testing.T) {
+	logx.DisableStat()
+
+	tests := []struct {
+		name          string
+		request       *types.TransferRequest
+		expectedError error
+	}{
+		{
+			name:          "转给自己",
+			request:       &types.TransferRequest{Currency: "BTC", Amount: "1", ToUserID: 1},
+			expectedError: model.ErrTransferToSelf,
+		},
+		{
+			name:          "同时指定用户ID和邮箱",
+			request:       &types.TransferRequest{Currency: "BTC", Amount: "1", ToUserID: 2, ToEmail: "bob@example.com"},
+			expectedError: model.ErrTransferRecipientRequired,
+		},
+		{
+			name:          "收款用户已禁用",
+			request:       &types.TransferRequest{Currency: "BTC", Amount: "1", ToUserID: 3},
+			expectedError: model.ErrUserDisabled,
+		},
+		{
+			name:          "收款用户已冻结",
+			request:       &types.TransferRequest{Currency: "BTC", Amount: "1", ToUserID: 4},
+			expectedError: model.ErrUserDisabled,
+		},
+		{
+			name:          "金额无效",
+			request:       &types.TransferRequest{Currency: "BTC", Amount: "-1", ToUserID: 2},
+			expectedError: model.ErrInvalidAmount,
+		},
+		{
+			name:          "余额不足",
+			request:       &types.TransferRequest{Currency: "BTC", Amount: "3", ToUserID: 2},
+			expectedError: model.ErrInsufficientBalance,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svcCtx, balanceModel, txModel, _ := setupTransfer(nil)
+			balanceModel.On("AddAvailable", mock.Anything, uint64(1), "BTC", "-3").Return(model.ErrInsufficientBalance)
+
+			resp, err := NewTransferLogic(transferContext(1), svcCtx).Transfer(tt.request)
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Nil(t, resp)
+			txModel.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+			balanceModel.AssertNotCalled(t, "AddAvailable", mock.Anything, uint64(2), mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTransferLogic_Transfer_Idempotent(t *testing.T) {
+	logx.DisableStat()
+
+	svcCtx, balanceModel, txModel, _ := setupTransfer(nil)
+	logic := NewTransferLogic(transferContext(1), svcCtx)
+	transferID := logic.generateTransferID(1, "order-42")
+	txModel.On("FindByTransactionID", mock.Anything, transferID+"_OUT").Return(&model.AssetTransaction{
+		UserID:         1,
+		TransactionID:  transferID + "_OUT",
+		Currency:       "BTC",
+		Type:           model.AssetTransactionTypeTransferOut,
+		Amount:         "0.50000000",
+		Status:         model.AssetTransactionStatusSuccess,
+		CounterpartyID: 2,
+		CreatedAt:      time.Now(),
+	}, nil)
+
+	// 相同参数重试返回首次结果，不再重复转账
+	resp, err := logic.Transfer(&types.TransferRequest{Currency: "BTC", Amount: "0.5", ToUserID: 2, ClientTransferID: "order-42"})
+	assert.NoError(t, err)
+	assert.Equal(t, transferID, resp.TransferID)
+	assert.Equal(t, uint64(2), resp.ToUserID)
+	balanceModel.AssertNotCalled(t, "Trans", mock.Anything, mock.Anything)
+
+	// 相同幂等键但金额不同时拒绝
+	_, err = logic.Transfer(&types.TransferRequest{Currency: "BTC", Amount: "0.6", ToUserID: 2, ClientTransferID: "order-42"})
+	assert.ErrorIs(t, err, model.ErrTransferIdempotencyConflict)
+
+	// 幂等键按用户隔离
+	assert.NotEqual(t, transferID, logic.generateTransferID(2, "order-42"))
+}
+
+func TestTransferLogic_Transfer_ConcurrentIdempotent(t *testing.T) {
+	logx.DisableStat()
+
+	svcCtx, balanceModel, txModel, _ := setupTransfer(nil)
+	logic := NewTransferLogic(transferContext(1), svcCtx)
+	transferID := logic.generateTransferID(1, "order-42")
+	balanceModel.On("AddAvailable", mock.Anything, mock.Anything, "BTC", mock.Anything).Return(nil)
+	// 预检时首次请求尚未提交，插入交易记录时与先提交的并发请求发生唯一约束冲突
+	txModel.On("FindByTransactionID", mock.Anything, transferID+"_OUT").Return((*model.AssetTransaction)(nil), model.ErrNotFound).Once()
+	txModel.On("Insert", mock.Anything, mock.Anything).Return(nil, &pq.Error{Code: "23505"})
+	txModel.On("FindByTransactionID", mock.Anything, transferID+"_OUT").Return(&model.AssetTransaction{
+		UserID:         1,
+		TransactionID:  transferID + "_OUT",
+		Currency:       "BTC",
+		Type:           model.AssetTransactionTypeTransferOut,
+		Amount:         "0.5",
+		Status:         model.AssetTransactionStatusSuccess,
+		CounterpartyID: 2,
+		CreatedAt:      time.Now(),
+	}, nil)
+
+	resp, err := logic.Transfer(&types.TransferRequest{Currency: "BTC", Amount: "0.5", ToUserID: 2, ClientTransferID: "order-42"})
+	assert.NoError(t, err)
+	assert.Equal(t, transferID, resp.TransferID)
+	assert.Equal(t, model.AssetTransactionStatusSuccess, resp.Status)
+}
+
+func TestTransferLogic_Transfer_DailyLimits(t *testing.T) {
+	logx.DisableStat()
+
+	recent := []*model.AssetTransaction{
+		{TransactionID: "TRF_1_OUT", Currency: "USDT", Amount: "600", Type: model.AssetTransactionTypeTransferOut},
+		{TransactionID: "TRF_2_OUT", Currency: "BTC", Amount: "0.01", Type: model.AssetTransactionTypeTransferOut},
+	}
+
+	// 已转出 600 USDT + 0.01 BTC(500 USDT)，再转 0.001 BTC(50 USDT) 超过1100的限额
+	svcCtx, balanceModel, txModel, _ := setupTransfer(func(c *config.Config) {
+		c.Transfer.DailyAmount = "1100"
+	})
+	txModel.On("FindByUserIDAndTypeSinceForUpdate", mock.Anything, uint64(1), model.AssetTransactionTypeTransferOut, mock.Anything).Return(recent, nil)
+	_, err := NewTransferLogic(transferContext(1), svcCtx).Transfer(&types.TransferRequest{Currency: "BTC", Amount: "0.001", ToUserID: 2})
+	assert.ErrorIs(t, err, model.ErrTransferDailyAmountExceeded)
+	balanceModel.AssertNotCalled(t, "AddAvailable", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// 次数限制
+	svcCtx, _, txModel, _ = setupTransfer(func(c *config.Config) {
+		c.Transfer.DailyCount = 2
+	})
+	txModel.On("FindByUserIDAndTypeSinceForUpdate", mock.Anything, uint64(1), model.AssetTransactionTypeTransferOut, mock.Anything).Return(recent, nil)
+	_, err = NewTransferLogic(transferContext(1), svcCtx).Transfer(&types.TransferRequest{Currency: "USDT", Amount: "1", ToUserID: 2})
+	assert.ErrorIs(t, err, model.ErrTransferDailyCountExceeded)
+}
+
+func TestTransferLogic_Transfer_SenderChecks(t *testing.T) {
+	logx.DisableStat()
+
+	// 未验证邮箱的用户不能转出
+	svcCtx, balanceModel, _, _ := setupTransfer(nil)
+	userModel := new(MockUserModel)
+	userModel.On("FindOne", mock.Anything, uint64(1)).Return(&model.User{ID: 1, Status: model.UserStatusActive}, nil)
+	svcCtx.UserModel = userModel
+	_, err := NewTransferLogic(transferContext(1), svcCtx).Transfer(&types.TransferRequest{Currency: "BTC", Amount: "0.5", ToUserID: 2})
+	assert.ErrorIs(t, err, model.ErrEmailNotVerified)
+	balanceModel.AssertNotCalled(t, "Trans", mock.Anything, mock.Anything)
+
+	// 已启用两步验证的用户需要提供验证码
+	svcCtx, balanceModel, _, _ = setupTransfer(nil)
+	totpModel := new(MockUserTotpModel)
+	totpModel.On("FindOneByUserID", mock.Anything, uint64(1)).Return(&model.UserTotp{ID: 1, UserID: 1, Status: model.UserTotpStatusEnabled}, nil)
+	svcCtx.UserTotpModel = totpModel
+	_, err = NewTransferLogic(transferContext(1), svcCtx).Transfer(&types.TransferRequest{Currency: "BTC", Amount: "0.5", ToUserID: 2})
+	assert.ErrorIs(t, err, model.ErrTwoFactorCodeRequired)
+	balanceModel.AssertNotCalled(t, "Trans", mock.Anything, mock.Anything)
+}
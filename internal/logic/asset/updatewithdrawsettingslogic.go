@@ -0,0 +1,83 @@
+package asset
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/securityevent"
+	"crypto-exchange/internal/logic/twofactor"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateWithdrawSettingsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateWithdrawSettingsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateWithdrawSettingsLogic {
+	return &UpdateWithdrawSettingsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// UpdateWithdrawSettings 开启或关闭提现白名单
+// 开启立即生效；关闭需要通过两步验证，并与新添加的地址一样经过冷却期才生效，冷却期内仍只能提现到地址簿中的地址
+func (l *UpdateWithdrawSettingsLogic) UpdateWithdrawSettings(req *types.WithdrawSettingsRequest) (resp *types.WithdrawSettingsResponse, err error) {
+	// 1. 从JWT上下文中获取用户ID
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		l.Errorf("Failed to get user ID from context: %v", err)
+		return nil, model.ErrUnauthorized
+	}
+
+	user, err := l.svcCtx.UserModel.FindOne(l.ctx, userID)
+	if err != nil {
+		l.Errorf("Failed to find user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	// 2. 计算关闭白名单的冷却截止时间，重复关闭不会延长或缩短已有的冷却期
+	now := time.Now()
+	var until sql.NullTime
+	if !req.WhitelistOnly && user.WithdrawWhitelistEnforced(now) {
+		if err := twofactor.Require(l.ctx, l.svcCtx, userID, req.TotpCode); err != nil {
+			l.Errorf("Disabling withdraw whitelist rejected by two-factor check for user %d: %v", userID, err)
+			return nil, err
+		}
+		until = user.WithdrawWhitelistUntil
+		if user.WithdrawWhitelistOnly {
+			until = sql.NullTime{Time: now.Add(time.Duration(l.svcCtx.Config.Withdraw.AddressCooldown) * time.Second), Valid: true}
+		}
+	}
+
+	// 3. 更新设置
+	if err := l.svcCtx.UserModel.UpdateWithdrawWhitelistOnly(l.ctx, userID, req.WhitelistOnly, until); err != nil {
+		l.Errorf("Failed to update withdraw settings for user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	securityevent.Record(l.ctx, l.svcCtx, userID, model.SecurityEventWithdrawSettingsChanged, fmt.Sprintf("whitelist_only=%t", req.WhitelistOnly))
+	l.Infof("User %d set withdraw whitelist only to %t", userID, req.WhitelistOnly)
+	return &types.WithdrawSettingsResponse{
+		WhitelistOnly:  req.WhitelistOnly,
+		WhitelistUntil: formatWhitelistUntil(until),
+	}, nil
+}
+
+// formatWhitelistUntil 格式化关闭白名单的冷却截止时间，没有冷却时为空
+func formatWhitelistUntil(until sql.NullTime) string {
+	if !until.Valid {
+		return ""
+	}
+	return until.Time.Format("2006-01-02 15:04:05")
+}
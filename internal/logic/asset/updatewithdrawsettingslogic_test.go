@@ -0,0 +1,103 @@
+package asset
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// memoryWhitelistUserModel 内存中的单个用户，记录提现白名单设置
+type memoryWhitelistUserModel struct {
+	model.UserModel
+	user *model.User
+}
+
+func (m *memoryWhitelistUserModel) FindOne(ctx context.Context, id uint64) (*model.User, error) {
+	copied := *m.user
+	return &copied, nil
+}
+
+func (m *memoryWhitelistUserModel) UpdateWithdrawWhitelistOnly(ctx context.Context, id uint64, whitelistOnly bool, until sql.NullTime) error {
+	m.user.WithdrawWhitelistOnly = whitelistOnly
+	m.user.WithdrawWhitelistUntil = until
+	return nil
+}
+
+type discardSecurityEventModel struct {
+	model.SecurityEventModel
+}
+
+func (m *discardSecurityEventModel) Insert(ctx context.Context, data *model.SecurityEvent) (sql.Result, error) {
+	return nil, nil
+}
+
+func newWithdrawSettingsEnv(totp *model.UserTotp) (*svc.ServiceContext, *memoryWhitelistUserModel) {
+	users := &memoryWhitelistUserModel{user: &model.User{ID: 1, Status: model.UserStatusActive, WithdrawWhitelistOnly: true}}
+	totpModel := new(MockUserTotpModel)
+	if totp != nil {
+		totpModel.On("FindOneByUserID", mock.Anything, uint64(1)).Return(totp, nil)
+	} else {
+		totpModel.On("FindOneByUserID", mock.Anything, uint64(1)).Return((*model.UserTotp)(nil), model.ErrNotFound)
+	}
+
+	svcCtx := &svc.ServiceContext{
+		UserModel:          users,
+		UserTotpModel:      totpModel,
+		SecurityEventModel: &discardSecurityEventModel{},
+	}
+	svcCtx.Config.Withdraw.AddressCooldown = 86400
+	return svcCtx, users
+}
+
+func TestUpdateWithdrawSettingsLogic_DisableCoolsDown(t *testing.T) {
+	svcCtx, users := newWithdrawSettingsEnv(nil)
+	ctx := context.WithValue(context.Background(), "userId", float64(1))
+
+	// 关闭白名单后进入冷却期，冷却期内仍按白名单校验
+	resp, err := NewUpdateWithdrawSettingsLogic(ctx, svcCtx).UpdateWithdrawSettings(&types.WithdrawSettingsRequest{WhitelistOnly: false})
+	assert.NoError(t, err)
+	assert.False(t, resp.WhitelistOnly)
+	assert.NotEmpty(t, resp.WhitelistUntil)
+	until := users.user.WithdrawWhitelistUntil
+	if assert.True(t, until.Valid) {
+		assert.WithinDuration(t, time.Now().Add(24*time.Hour), until.Time, time.Minute)
+	}
+	assert.True(t, users.user.WithdrawWhitelistEnforced(time.Now()))
+	assert.False(t, users.user.WithdrawWhitelistEnforced(until.Time.Add(time.Second)))
+
+	// 重复关闭不会延长冷却期
+	_, err = NewUpdateWithdrawSettingsLogic(ctx, svcCtx).UpdateWithdrawSettings(&types.WithdrawSettingsRequest{WhitelistOnly: false})
+	assert.NoError(t, err)
+	assert.Equal(t, until, users.user.WithdrawWhitelistUntil)
+
+	// 重新开启立即生效并清除冷却期
+	resp, err = NewUpdateWithdrawSettingsLogic(ctx, svcCtx).UpdateWithdrawSettings(&types.WithdrawSettingsRequest{WhitelistOnly: true})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.WhitelistUntil)
+	assert.True(t, users.user.WithdrawWhitelistOnly)
+	assert.False(t, users.user.WithdrawWhitelistUntil.Valid)
+}
+
+func TestUpdateWithdrawSettingsLogic_DisableRequiresTwoFactor(t *testing.T) {
+	svcCtx, users := newWithdrawSettingsEnv(&model.UserTotp{ID: 1, UserID: 1, Status: model.UserTotpStatusEnabled})
+	ctx := context.WithValue(context.Background(), "userId", float64(1))
+
+	_, err := NewUpdateWithdrawSettingsLogic(ctx, svcCtx).UpdateWithdrawSettings(&types.WithdrawSettingsRequest{WhitelistOnly: false})
+	assert.ErrorIs(t, err, model.ErrTwoFactorCodeRequired)
+	assert.True(t, users.user.WithdrawWhitelistOnly)
+	assert.False(t, users.user.WithdrawWhitelistUntil.Valid)
+
+	// 开启白名单不需要两步验证
+	users.user.WithdrawWhitelistOnly = false
+	_, err = NewUpdateWithdrawSettingsLogic(ctx, svcCtx).UpdateWithdrawSettings(&types.WithdrawSettingsRequest{WhitelistOnly: true})
+	assert.NoError(t, err)
+	assert.True(t, users.user.WithdrawWhitelistOnly)
+}
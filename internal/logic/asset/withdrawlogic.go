@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"regexp"
 	"strings"
 	"time"
 
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/twofactor"
+	"crypto-exchange/internal/logic/restriction"
+	"crypto-exchange/internal/logic/useremail"
+	"crypto-exchange/internal/logic/withdrawal"
 	"crypto-exchange/internal/svc"
 	"crypto-exchange/internal/types"
 	"crypto-exchange/model"
@@ -34,36 +38,78 @@ func NewWithdrawLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Withdraw
 
 func (l *WithdrawLogic) Withdraw(req *types.WithdrawRequest) (resp *types.WithdrawResponse, err error) {
 	// 1. 从JWT上下文中获取用户ID
-	userID, err := l.getUserIDFromContext()
+	userID, err := authctx.UserID(l.ctx)
 	if err != nil {
 		l.Errorf("Failed to get user ID from context: %v", err)
 		return nil, model.ErrUnauthorized
 	}
 
-	// 2. 验证提现参数
-	if err := l.validateWithdrawRequest(req); err != nil {
+	// 2. 未验证邮箱或被限制提现的用户不能提现
+	user, err := useremail.Require(l.ctx, l.svcCtx, userID)
+	if err != nil {
+		l.Errorf("Withdraw rejected for user %d: %v", userID, err)
+		return nil, err
+	}
+	if err := restriction.Check(l.ctx, l.svcCtx, user, model.RestrictionWithdraw); err != nil {
+		l.Errorf("Withdraw rejected for user %d: %v", userID, err)
+		return nil, err
+	}
+
+	// 3. 验证提现参数
+	currencyConfig, networkConfig, err := l.validateWithdrawRequest(req)
+	if err != nil {
 		l.Errorf("Invalid withdraw request for user %d: %v", userID, err)
 		return nil, err
 	}
 
-	// 3. 解析提现金额
+	// 4. 校验提现地址：地址簿中的地址需已过冷却期，开启白名单（包括关闭后的冷却期内）时只能提现到地址簿中的地址
+	if err := l.checkWithdrawAddress(userID, req.Network, req.Address); err != nil {
+		l.Errorf("Withdraw address %s on %s rejected for user %d: %v", req.Address, req.Network, userID, err)
+		return nil, err
+	}
+
+	// 5. 解析提现金额
 	amount, err := decimal.NewFromString(req.Amount)
 	if err != nil {
 		l.Errorf("Invalid amount format for user %d: %s", userID, req.Amount)
 		return nil, model.ErrInvalidAmount
 	}
 
-	// 4. 计算提现手续费
-	fee := l.calculateWithdrawFee(req.Currency, amount)
+	// 6. 校验按认证等级的24小时提现限额和频率规则
+	if err := withdrawal.NewLimitChecker(l.ctx, l.svcCtx).Check(userID, req.Currency, amount); err != nil {
+		l.Errorf("Withdraw of %s %s rejected by limit rules for user %d: %v", req.Amount, req.Currency, userID, err)
+		return nil, err
+	}
+
+	// 7. 已启用两步验证的用户需要提供新的验证码，放在其他校验之后以免验证码被无效请求消耗
+	if err := twofactor.Require(l.ctx, l.svcCtx, userID, req.TotpCode); err != nil {
+		l.Errorf("Withdraw rejected by two-factor check for user %d: %v", userID, err)
+		return nil, err
+	}
+
+	// 8. 按提现网络的配置计算提现手续费
+	fee := networkConfig.WithdrawFee(amount, currencyConfig.Precision)
 	totalAmount := amount.Add(fee) // 总扣除金额 = 提现金额 + 手续费
 
-	// 5. 生成交易ID
+	// 9. 生成交易ID
 	transactionID := l.generateTransactionID()
 
-	// 6. 使用数据库事务处理提现
+	// 10. 使用数据库事务处理提现
+	var transaction *model.AssetTransaction
 	err = l.svcCtx.BalanceModel.Trans(l.ctx, func(ctx context.Context, session sqlx.Session) error {
-		// 查找用户余额记录
-		balance, err := l.svcCtx.BalanceModel.FindByUserIDAndCurrency(ctx, userID, req.Currency)
+		balances := l.svcCtx.BalanceModel.WithSession(session)
+		transactions := l.svcCtx.AssetTransactionModel.WithSession(session)
+		ledger := l.svcCtx.LedgerEntryModel.WithSession(session)
+		auditLogs := l.svcCtx.WithdrawalAuditLogModel.WithSession(session)
+
+		// 锁定用户行后重新校验限额，事务外的预检无法防止同一用户的并发提现同时通过
+		if err := withdrawal.NewLimitChecker(ctx, l.svcCtx).CheckLocked(ctx, transactions, userID, req.Currency, amount); err != nil {
+			l.Errorf("Withdraw of %s %s rejected by limit rules for user %d: %v", req.Amount, req.Currency, userID, err)
+			return err
+		}
+
+		// 查找并锁定用户余额记录，防止并发提现重复扣减
+		balance, err := balances.FindByUserIDAndCurrencyForUpdate(ctx, userID, req.Currency)
 		if err != nil {
 			if errors.Is(err, model.ErrNotFound) {
 				l.Errorf("Balance not found for user %d, currency %s", userID, req.Currency)
@@ -86,11 +132,18 @@ func (l *WithdrawLogic) Withdraw(req *types.WithdrawRequest) (resp *types.Withdr
 			return model.ErrInsufficientBalance
 		}
 
-		// 扣减余额
+		currentFrozen, err := decimal.NewFromString(balance.Frozen)
+		if err != nil {
+			l.Errorf("Invalid current frozen balance format for user %d, currency %s: %s", userID, req.Currency, balance.Frozen)
+			return model.ErrInternalServer
+		}
+
+		// 提现金额和手续费转入冻结余额，审核拒绝或提现失败时退回，链上确认后扣除
 		newAvailable := currentAvailable.Sub(totalAmount)
+		newFrozen := currentFrozen.Add(totalAmount)
 
 		// 更新余额
-		err = l.svcCtx.BalanceModel.UpdateBalance(ctx, userID, req.Currency, newAvailable.String(), balance.Frozen)
+		err = balances.UpdateBalance(ctx, userID, req.Currency, newAvailable.String(), newFrozen.String())
 		if err != nil {
 			l.Errorf("Failed to update balance for user %d, currency %s: %v", userID, req.Currency, err)
 			return err
@@ -98,27 +151,50 @@ func (l *WithdrawLogic) Withdraw(req *types.WithdrawRequest) (resp *types.Withdr
 
 		// 创建交易记录
 		now := time.Now()
-		transaction := &model.AssetTransaction{
+		transaction = &model.AssetTransaction{
 			UserID:        userID,
 			TransactionID: transactionID,
 			Currency:      req.Currency,
-			Type:          2, // 2-提现
+			Network:       req.Network,
+			Type:          model.AssetTransactionTypeWithdraw,
 			Amount:        req.Amount,
 			Fee:           fee.String(),
-			Status:        1,           // 1-待审核（提现通常需要人工审核）
+			Status:        model.AssetTransactionStatusUnconfirmed, // 待用户提交确认码，确认后进入审核
 			Address:       req.Address, // 提现地址
 			TxHash:        "",          // 区块链交易哈希，实际场景中在区块链确认后更新
-			Remark:        fmt.Sprintf("Withdraw %s %s to %s", req.Amount, req.Currency, req.Address),
+			Remark:        fmt.Sprintf("Withdraw %s %s to %s via %s", req.Amount, req.Currency, req.Address, req.Network),
 			CreatedAt:     now,
 			UpdatedAt:     now,
 		}
 
-		_, err = l.svcCtx.AssetTransactionModel.Insert(ctx, transaction)
+		_, err = transactions.Insert(ctx, transaction)
 		if err != nil {
 			l.Errorf("Failed to create transaction record for user %d, transaction ID %s: %v", userID, transactionID, err)
 			return err
 		}
 
+		// 记账：用户可用余额 -> 用户冻结余额（提现金额和手续费）
+		journal := model.NewLedgerJournal(model.LedgerBizWithdraw, transactionID)
+		journal.Transfer(req.Currency, totalAmount.String(), model.UserAvailable(userID), model.UserFrozen(userID), "withdraw freeze")
+		if err := ledger.InsertJournal(ctx, journal); err != nil {
+			l.Errorf("Failed to record ledger for withdraw %s: %v", transactionID, err)
+			return err
+		}
+
+		// 审核日志：提现申请由用户本人发起
+		_, err = auditLogs.Insert(ctx, &model.WithdrawalAuditLog{
+			TransactionID: transactionID,
+			FromStatus:    0,
+			ToStatus:      model.AssetTransactionStatusUnconfirmed,
+			OperatorID:    userID,
+			Remark:        "withdraw requested",
+			CreatedAt:     now,
+		})
+		if err != nil {
+			l.Errorf("Failed to record audit log for withdraw %s: %v", transactionID, err)
+			return err
+		}
+
 		return nil
 	})
 
@@ -126,15 +202,32 @@ func (l *WithdrawLogic) Withdraw(req *types.WithdrawRequest) (resp *types.Withdr
 		return nil, err
 	}
 
-	// 7. 构造响应
+	// 11. 生成确认码并通过邮件发送给用户，失败时取消提现并退回冻结金额
+	ttl := time.Duration(l.svcCtx.Config.Withdraw.ConfirmTTL) * time.Second
+	code, err := l.svcCtx.ConfirmCodes.Issue(l.ctx, withdrawal.ConfirmPurpose, transactionID, ttl)
+	if err != nil {
+		l.Errorf("Failed to issue confirmation code for withdraw %s: %v", transactionID, err)
+		l.cancelUnconfirmed(userID, transactionID, "failed to issue confirmation code")
+		return nil, model.ErrInternalServer
+	}
+	if err := useremail.SendWithdrawConfirmCode(l.ctx, l.svcCtx, user, transaction, code, l.svcCtx.Config.Withdraw.ConfirmTTL); err != nil {
+		l.Errorf("Failed to deliver confirmation code for withdraw %s: %v", transactionID, err)
+		l.cancelUnconfirmed(userID, transactionID, "failed to deliver confirmation code")
+		return nil, err
+	}
+
+	// 12. 构造响应
+	now := time.Now()
 	resp = &types.WithdrawResponse{
-		TransactionID: transactionID,
-		Currency:      req.Currency,
-		Amount:        req.Amount,
-		Address:       req.Address,
-		Fee:           fee.String(),
-		Status:        1, // 1-待审核（在真实场景中，提现通常需要人工审核）
-		CreatedAt:     time.Now().Format("2006-01-02 15:04:05"),
+		TransactionID:    transactionID,
+		Currency:         req.Currency,
+		Network:          req.Network,
+		Amount:           req.Amount,
+		Address:          req.Address,
+		Fee:              fee.String(),
+		Status:           model.AssetTransactionStatusUnconfirmed, // 待确认，金额已冻结
+		ConfirmExpiresAt: now.Add(ttl).Format("2006-01-02 15:04:05"),
+		CreatedAt:        now.Format("2006-01-02 15:04:05"),
 	}
 
 	l.Infof("Withdraw request created for user %d: %s %s to %s, fee: %s, transaction ID: %s", 
@@ -142,174 +235,72 @@ func (l *WithdrawLogic) Withdraw(req *types.WithdrawRequest) (resp *types.Withdr
 	return resp, nil
 }
 
-// getUserIDFromContext 从JWT上下文中获取用户ID
-func (l *WithdrawLogic) getUserIDFromContext() (uint64, error) {
-	userIDValue := l.ctx.Value("userId")
-	if userIDValue == nil {
-		return 0, errors.New("user ID not found in context")
+// checkWithdrawAddress 校验提现地址是否允许使用
+func (l *WithdrawLogic) checkWithdrawAddress(userID uint64, network, address string) error {
+	entry, err := l.svcCtx.WithdrawalAddressModel.FindByUserAndAddress(l.ctx, userID, network, address)
+	if err == nil {
+		if time.Now().Before(entry.AvailableAt) {
+			return model.ErrWithdrawAddressCoolingDown
+		}
+		return nil
+	}
+	if !errors.Is(err, model.ErrNotFound) {
+		return err
 	}
 
-	switch v := userIDValue.(type) {
-	case float64:
-		return uint64(v), nil
-	case uint64:
-		return v, nil
-	case int64:
-		return uint64(v), nil
-	case int:
-		return uint64(v), nil
-	default:
-		l.Errorf("Invalid user ID type in context: %T", v)
-		return 0, errors.New("invalid user ID type in context")
+	user, err := l.svcCtx.UserModel.FindOne(l.ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.WithdrawWhitelistEnforced(time.Now()) {
+		return model.ErrWithdrawAddressNotWhitelisted
+	}
+	return nil
+}
+
+// cancelUnconfirmed 确认码未能送达用户时取消提现并退回冻结金额，取消失败只记录日志
+func (l *WithdrawLogic) cancelUnconfirmed(userID uint64, transactionID, reason string) {
+	if _, err := withdrawal.NewWorkflow(l.ctx, l.svcCtx).Cancel(transactionID, userID, reason); err != nil {
+		l.Errorf("Failed to cancel withdraw %s: %v", transactionID, err)
 	}
 }
 
-// validateWithdrawRequest 验证提现请求参数
-func (l *WithdrawLogic) validateWithdrawRequest(req *types.WithdrawRequest) error {
+// validateWithdrawRequest 验证提现请求参数，返回提现币种及网络的配置
+func (l *WithdrawLogic) validateWithdrawRequest(req *types.WithdrawRequest) (*model.Currency, *model.CurrencyNetwork, error) {
 	// 验证币种代码
 	if req.Currency == "" {
-		return model.ErrInvalidParams
+		return nil, nil, model.ErrInvalidParams
 	}
 
-	// 币种代码应该是大写字母
+	// 币种和网络代码应该是大写字母
 	req.Currency = strings.ToUpper(req.Currency)
-
-	// 验证支持的币种（这里简化处理，实际应该从配置或数据库读取）
-	supportedCurrencies := map[string]bool{
-		"BTC":  true,
-		"ETH":  true,
-		"USDT": true,
-		"USDC": true,
-	}
-
-	if !supportedCurrencies[req.Currency] {
-		return model.ErrCurrencyNotFound
-	}
+	req.Network = strings.ToUpper(req.Network)
 
 	// 验证提现金额
 	if req.Amount == "" {
-		return model.ErrInvalidAmount
+		return nil, nil, model.ErrInvalidAmount
 	}
 
 	amount, err := decimal.NewFromString(req.Amount)
 	if err != nil {
-		return model.ErrInvalidAmount
+		return nil, nil, model.ErrInvalidAmount
 	}
 
 	// 提现金额必须大于0
 	if amount.LessThanOrEqual(decimal.Zero) {
-		return model.ErrInvalidAmount
-	}
-
-	// 验证金额精度（最多8位小数）
-	if amount.Exponent() < -8 {
-		return errors.New("amount precision exceeds 8 decimal places")
-	}
-
-	// 验证最小提现金额
-	minAmount := l.getMinWithdrawAmount(req.Currency)
-	if amount.LessThan(minAmount) {
-		return fmt.Errorf("amount below minimum withdraw limit: %s", minAmount.String())
-	}
-
-	// 验证最大提现金额
-	maxAmount := l.getMaxWithdrawAmount(req.Currency)
-	if amount.GreaterThan(maxAmount) {
-		return fmt.Errorf("amount exceeds maximum withdraw limit: %s", maxAmount.String())
-	}
-
-	// 验证提现地址
-	if req.Address == "" {
-		return errors.New("withdraw address is required")
-	}
-
-	if err := l.validateWithdrawAddress(req.Currency, req.Address); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// validateWithdrawAddress 验证提现地址格式
-func (l *WithdrawLogic) validateWithdrawAddress(currency, address string) error {
-	// 简化的地址验证，实际应该根据不同币种使用不同的验证规则
-	switch currency {
-	case "BTC":
-		// Bitcoin地址验证（简化版）
-		if len(address) < 26 || len(address) > 35 {
-			return errors.New("invalid Bitcoin address format")
-		}
-		// 检查是否以1、3或bc1开头
-		if !strings.HasPrefix(address, "1") && !strings.HasPrefix(address, "3") && !strings.HasPrefix(address, "bc1") {
-			return errors.New("invalid Bitcoin address format")
-		}
-	case "ETH", "USDT", "USDC":
-		// Ethereum地址验证（简化版）
-		if len(address) != 42 {
-			return errors.New("invalid Ethereum address format")
-		}
-		if !strings.HasPrefix(address, "0x") {
-			return errors.New("invalid Ethereum address format")
-		}
-		// 检查是否为有效的十六进制字符
-		matched, _ := regexp.MatchString("^0x[a-fA-F0-9]{40}$", address)
-		if !matched {
-			return errors.New("invalid Ethereum address format")
-		}
-	default:
-		return errors.New("unsupported currency for address validation")
+		return nil, nil, model.ErrInvalidAmount
 	}
 
-	return nil
-}
-
-// calculateWithdrawFee 计算提现手续费
-func (l *WithdrawLogic) calculateWithdrawFee(currency string, amount decimal.Decimal) decimal.Decimal {
-	// 简化的手续费计算，实际应该从配置或数据库读取
-	switch currency {
-	case "BTC":
-		// BTC固定手续费 0.0005
-		return decimal.NewFromFloat(0.0005)
-	case "ETH":
-		// ETH固定手续费 0.005
-		return decimal.NewFromFloat(0.005)
-	case "USDT", "USDC":
-		// USDT/USDC固定手续费 1.0
-		return decimal.NewFromFloat(1.0)
-	default:
-		// 默认手续费为提现金额的0.1%
-		return amount.Mul(decimal.NewFromFloat(0.001))
+	// 按币种及网络配置校验是否开放提现、金额精度、提现限额和地址格式
+	currencyConfig, networkConfig, err := l.svcCtx.CurrencyRegistry.ValidateWithdraw(l.ctx, req.Currency, req.Network, amount, req.Address)
+	if err != nil {
+		return nil, nil, err
 	}
-}
 
-// getMinWithdrawAmount 获取最小提现金额
-func (l *WithdrawLogic) getMinWithdrawAmount(currency string) decimal.Decimal {
-	// 简化处理，实际应该从配置读取
-	switch currency {
-	case "BTC":
-		return decimal.NewFromFloat(0.001)
-	case "ETH":
-		return decimal.NewFromFloat(0.01)
-	case "USDT", "USDC":
-		return decimal.NewFromFloat(10.0)
-	default:
-		return decimal.NewFromFloat(0.00000001)
-	}
-}
+	// 未指定网络时使用币种唯一支持的网络
+	req.Network = networkConfig.Network
 
-// getMaxWithdrawAmount 获取最大提现金额
-func (l *WithdrawLogic) getMaxWithdrawAmount(currency string) decimal.Decimal {
-	// 简化处理，实际应该从配置读取
-	switch currency {
-	case "BTC":
-		return decimal.NewFromFloat(10.0)
-	case "ETH":
-		return decimal.NewFromFloat(100.0)
-	case "USDT", "USDC":
-		return decimal.NewFromFloat(100000.0)
-	default:
-		return decimal.NewFromFloat(1000000.0)
-	}
+	return currencyConfig, networkConfig, nil
 }
 
 // generateTransactionID 生成唯一的交易ID
@@ -2,10 +2,13 @@ package asset
 
 import (
 	"context"
+	"database/sql"
 	"strings"
 	"testing"
 	"time"
 
+	"crypto-exchange/internal/config"
+	"crypto-exchange/internal/mailer"
 	"crypto-exchange/internal/svc"
 	"crypto-exchange/internal/types"
 	"crypto-exchange/model"
@@ -14,10 +17,124 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
 
-// 使用 depositlogic_test.go 中的 MockBalanceModel 和 MockAssetTransactionModel
+// 使用 mocks_test.go 中的 MockBalanceModel 和 MockAssetTransactionModel
 
+// MockWithdrawalAuditLogModel 模拟WithdrawalAuditLogModel接口
+type MockWithdrawalAuditLogModel struct {
+	model.WithdrawalAuditLogModel
+	mock.Mock
+}
+
+func (m *MockWithdrawalAuditLogModel) Insert(ctx context.Context, data *model.WithdrawalAuditLog) (sql.Result, error) {
+	args := m.Called(ctx, data)
+	return nil, args.Error(0)
+}
+
+func (m *MockWithdrawalAuditLogModel) WithSession(session sqlx.Session) model.WithdrawalAuditLogModel {
+	return m
+}
+
+// MockWithdrawalAddressModel 模拟WithdrawalAddressModel接口
+type MockWithdrawalAddressModel struct {
+	model.WithdrawalAddressModel
+	mock.Mock
+}
+
+func (m *MockWithdrawalAddressModel) FindByUserAndAddress(ctx context.Context, userID uint64, network, address string) (*model.WithdrawalAddress, error) {
+	args := m.Called(ctx, userID, network, address)
+	return args.Get(0).(*model.WithdrawalAddress), args.Error(1)
+}
+
+// MockUserModel 模拟UserModel接口
+type MockUserModel struct {
+	model.UserModel
+	mock.Mock
+}
+
+func (m *MockUserModel) FindOne(ctx context.Context, id uint64) (*model.User, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (m *MockUserModel) FindOneByEmail(ctx context.Context, email string) (*model.User, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+// MockUserTotpModel 模拟UserTotpModel接口
+type MockUserTotpModel struct {
+	model.UserTotpModel
+	mock.Mock
+}
+
+func (m *MockUserTotpModel) FindOneByUserID(ctx context.Context, userID uint64) (*model.UserTotp, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*model.UserTotp), args.Error(1)
+}
+
+// MockTickerModel 模拟TickerModel接口
+type MockTickerModel struct {
+	model.TickerModel
+	mock.Mock
+}
+
+func (m *MockTickerModel) FindBySymbol(ctx context.Context, symbol string) (*model.Ticker, error) {
+	args := m.Called(ctx, symbol)
+	return args.Get(0).(*model.Ticker), args.Error(1)
+}
+
+// MockConfirmCodes 模拟一次性确认码存储
+type MockConfirmCodes struct {
+	mock.Mock
+}
+
+func (m *MockConfirmCodes) Issue(ctx context.Context, purpose, subject string, ttl time.Duration) (string, error) {
+	args := m.Called(ctx, purpose, subject, ttl)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockConfirmCodes) Verify(ctx context.Context, purpose, subject, code string) error {
+	args := m.Called(ctx, purpose, subject, code)
+	return args.Error(0)
+}
+
+func (m *MockConfirmCodes) Revoke(ctx context.Context, purpose, subject string) error {
+	args := m.Called(ctx, purpose, subject)
+	return args.Error(0)
+}
+
+// setupWithdrawSecurity 设置提现地址校验、两步验证和确认码相关的mock
+// address为nil时地址不在地址簿中，用户均未启用两步验证
+func setupWithdrawSecurity(svcCtx *svc.ServiceContext, whitelistOnly bool, address *model.WithdrawalAddress) (*MockWithdrawalAddressModel, *MockConfirmCodes) {
+	addressModel := new(MockWithdrawalAddressModel)
+	if address != nil {
+		addressModel.On("FindByUserAndAddress", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(address, nil)
+	} else {
+		addressModel.On("FindByUserAndAddress", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return((*model.WithdrawalAddress)(nil), model.ErrNotFound)
+	}
+	userModel := new(MockUserModel)
+	userModel.On("FindOne", mock.Anything, mock.Anything).Return(&model.User{
+		Email:                 "user@example.com",
+		Status:                model.UserStatusActive,
+		WithdrawWhitelistOnly: whitelistOnly,
+		EmailVerifiedAt:       sql.NullTime{Time: time.Now(), Valid: true},
+	}, nil)
+	totpModel := new(MockUserTotpModel)
+	totpModel.On("FindOneByUserID", mock.Anything, mock.Anything).Return((*model.UserTotp)(nil), model.ErrNotFound)
+	confirmCodes := new(MockConfirmCodes)
+	confirmCodes.On("Issue", mock.Anything, "withdraw", mock.Anything, mock.Anything).Return("123456", nil)
+
+	svcCtx.WithdrawalAddressModel = addressModel
+	svcCtx.UserModel = userModel
+	svcCtx.UserRestrictionModel = &memoryRestrictionModel{}
+	svcCtx.UserTotpModel = totpModel
+	svcCtx.ConfirmCodes = confirmCodes
+	svcCtx.Mailer = mailer.NewMemoryMailer()
+	return addressModel, confirmCodes
+}
 
 
 func TestWithdrawLogic_Withdraw(t *testing.T) {
@@ -63,7 +180,7 @@ func TestWithdrawLogic_Withdraw(t *testing.T) {
 				assert.Equal(t, "1.00000000", resp.Amount)
 				assert.Equal(t, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", resp.Address)
 				assert.Equal(t, "0.0005", resp.Fee) // BTC手续费
-				assert.Equal(t, int64(1), resp.Status) // 待审核状态
+				assert.Equal(t, model.AssetTransactionStatusUnconfirmed, resp.Status) // 待用户确认
 				assert.NotEmpty(t, resp.TransactionID)
 				assert.Contains(t, resp.TransactionID, "WTH_")
 				assert.NotEmpty(t, resp.CreatedAt)
@@ -74,8 +191,9 @@ func TestWithdrawLogic_Withdraw(t *testing.T) {
 			userID: 2,
 			request: &types.WithdrawRequest{
 				Currency: "USDT",
+				Network:  "ERC20",
 				Amount:   "100.00000000",
-				Address:  "0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b6",
+				Address:  "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
 			},
 			existingBalance: &model.Balance{
 				ID:        2,
@@ -94,9 +212,10 @@ func TestWithdrawLogic_Withdraw(t *testing.T) {
 			validateResult: func(t *testing.T, resp *types.WithdrawResponse) {
 				assert.NotNil(t, resp)
 				assert.Equal(t, "USDT", resp.Currency)
+				assert.Equal(t, "ERC20", resp.Network)
 				assert.Equal(t, "100.00000000", resp.Amount)
 				assert.Equal(t, "1", resp.Fee) // USDT手续费
-				assert.Equal(t, int64(1), resp.Status)
+				assert.Equal(t, model.AssetTransactionStatusUnconfirmed, resp.Status)
 			},
 		},
 		{
@@ -307,6 +426,8 @@ func TestWithdrawLogic_Withdraw(t *testing.T) {
 			// 创建模拟的BalanceModel和AssetTransactionModel
 			mockBalanceModel := new(MockBalanceModel)
 			mockAssetTransactionModel := new(MockAssetTransactionModel)
+			auditLogModel := new(MockWithdrawalAuditLogModel)
+			auditLogModel.On("Insert", mock.Anything, mock.AnythingOfType("*model.WithdrawalAuditLog")).Return(nil)
 
 			// 设置模拟期望
 			shouldSetupDatabaseMocks := tt.userID > 0 && 
@@ -323,18 +444,18 @@ func TestWithdrawLogic_Withdraw(t *testing.T) {
 				// 模拟Trans方法调用
 				mockBalanceModel.On("Trans", mock.Anything, mock.AnythingOfType("func(context.Context, sqlx.Session) error")).Return(nil)
 
-				// 模拟FindByUserIDAndCurrency调用
+				// 模拟FindByUserIDAndCurrencyForUpdate调用
 				if tt.existingBalance != nil {
-					mockBalanceModel.On("FindByUserIDAndCurrency", mock.Anything, tt.userID, tt.request.Currency).Return(tt.existingBalance, tt.balanceError)
+					mockBalanceModel.On("FindByUserIDAndCurrencyForUpdate", mock.Anything, tt.userID, tt.request.Currency).Return(tt.existingBalance, tt.balanceError)
 				} else {
-					mockBalanceModel.On("FindByUserIDAndCurrency", mock.Anything, tt.userID, tt.request.Currency).Return((*model.Balance)(nil), tt.balanceError)
+					mockBalanceModel.On("FindByUserIDAndCurrencyForUpdate", mock.Anything, tt.userID, tt.request.Currency).Return((*model.Balance)(nil), tt.balanceError)
 				}
 
-				// 如果有现有余额且没有更新错误，设置UpdateBalance期望
+				// 如果有现有余额且没有更新错误，设置UpdateBalance期望（提现金额和手续费转入冻结余额）
 				if tt.existingBalance != nil && tt.updateError == nil && tt.expectedError == nil {
-					mockBalanceModel.On("UpdateBalance", mock.Anything, tt.userID, tt.request.Currency, mock.AnythingOfType("string"), tt.existingBalance.Frozen).Return(tt.updateError)
+					mockBalanceModel.On("UpdateBalance", mock.Anything, tt.userID, tt.request.Currency, mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(tt.updateError)
 				} else if tt.existingBalance != nil && tt.updateError != nil {
-					mockBalanceModel.On("UpdateBalance", mock.Anything, tt.userID, tt.request.Currency, mock.AnythingOfType("string"), tt.existingBalance.Frozen).Return(tt.updateError)
+					mockBalanceModel.On("UpdateBalance", mock.Anything, tt.userID, tt.request.Currency, mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(tt.updateError)
 				}
 
 				// 设置AssetTransactionModel的mock期望
@@ -347,8 +468,12 @@ func TestWithdrawLogic_Withdraw(t *testing.T) {
 			// 创建服务上下文
 			svcCtx := &svc.ServiceContext{
 				BalanceModel:          mockBalanceModel,
+				LedgerEntryModel:      NewMockLedgerEntryModel(),
 				AssetTransactionModel: mockAssetTransactionModel,
+				CurrencyRegistry:      NewTestCurrencyRegistry(),
+				WithdrawalAuditLogModel: auditLogModel,
 			}
+			setupWithdrawSecurity(svcCtx, false, nil)
 
 			// 创建逻辑实例
 			ctx := tt.setupContext()
@@ -383,7 +508,7 @@ func TestWithdrawLogic_Withdraw(t *testing.T) {
 }
 
 func TestWithdrawLogic_validateWithdrawRequest(t *testing.T) {
-	logic := NewWithdrawLogic(context.Background(), &svc.ServiceContext{})
+	logic := NewWithdrawLogic(context.Background(), &svc.ServiceContext{CurrencyRegistry: NewTestCurrencyRegistry()})
 
 	tests := []struct {
 		name          string
@@ -404,7 +529,7 @@ func TestWithdrawLogic_validateWithdrawRequest(t *testing.T) {
 			request: &types.WithdrawRequest{
 				Currency: "ETH",
 				Amount:   "1.00000000",
-				Address:  "0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b6",
+				Address:  "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
 			},
 			expectedError: false,
 		},
@@ -498,6 +623,54 @@ func TestWithdrawLogic_validateWithdrawRequest(t *testing.T) {
 			},
 			expectedError: true,
 		},
+		{
+			name: "未开放提现的币种",
+			request: &types.WithdrawRequest{
+				Currency: "BNB",
+				Amount:   "1.00000000",
+				Address:  "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+			},
+			expectedError: true,
+		},
+		{
+			name: "有效的TRC20提现请求",
+			request: &types.WithdrawRequest{
+				Currency: "USDT",
+				Network:  "TRC20",
+				Amount:   "100",
+				Address:  "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t",
+			},
+			expectedError: false,
+		},
+		{
+			name: "多网络币种未指定网络",
+			request: &types.WithdrawRequest{
+				Currency: "USDT",
+				Amount:   "100",
+				Address:  "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+			},
+			expectedError: true,
+		},
+		{
+			name: "币种不支持的网络",
+			request: &types.WithdrawRequest{
+				Currency: "USDT",
+				Network:  "BEP20",
+				Amount:   "100",
+				Address:  "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+			},
+			expectedError: true,
+		},
+		{
+			name: "地址与网络不匹配",
+			request: &types.WithdrawRequest{
+				Currency: "USDT",
+				Network:  "TRC20",
+				Amount:   "100",
+				Address:  "0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+			},
+			expectedError: true,
+		},
 		{
 			name: "空提现地址",
 			request: &types.WithdrawRequest{
@@ -547,7 +720,7 @@ func TestWithdrawLogic_validateWithdrawRequest(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := logic.validateWithdrawRequest(tt.request)
+			_, _, err := logic.validateWithdrawRequest(tt.request)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -560,12 +733,13 @@ func TestWithdrawLogic_validateWithdrawRequest(t *testing.T) {
 	}
 }
 
-func TestWithdrawLogic_calculateWithdrawFee(t *testing.T) {
-	logic := NewWithdrawLogic(context.Background(), &svc.ServiceContext{})
+func TestWithdrawLogic_WithdrawFee(t *testing.T) {
+	registry := NewTestCurrencyRegistry()
 
 	tests := []struct {
 		name         string
 		currency     string
+		network      string
 		amount       string
 		expectedFee  string
 	}{
@@ -584,9 +758,17 @@ func TestWithdrawLogic_calculateWithdrawFee(t *testing.T) {
 		{
 			name:        "USDT手续费",
 			currency:    "USDT",
+			network:     "ERC20",
 			amount:      "100.00000000",
 			expectedFee: "1",
 		},
+		{
+			name:        "USDT TRC20手续费",
+			currency:    "USDT",
+			network:     "TRC20",
+			amount:      "100.00000000",
+			expectedFee: "0.8",
+		},
 		{
 			name:        "USDC手续费",
 			currency:    "USDC",
@@ -598,8 +780,9 @@ func TestWithdrawLogic_calculateWithdrawFee(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			amount, _ := decimal.NewFromString(tt.amount)
-			fee := logic.calculateWithdrawFee(tt.currency, amount)
-			assert.Equal(t, tt.expectedFee, fee.String())
+			currencyConfig, networkConfig, err := registry.Resolve(context.Background(), tt.currency, tt.network)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedFee, networkConfig.WithdrawFee(amount, currencyConfig.Precision).String())
 		})
 	}
 }
@@ -620,4 +803,205 @@ func TestWithdrawLogic_generateTransactionID(t *testing.T) {
 		assert.False(t, ids[id], "Transaction ID should be unique: %s", id)
 		ids[id] = true
 	}
-}
\ No newline at end of file
+}
+func TestWithdrawLogic_Withdraw_FreezesAmount(t *testing.T) {
+	mockBalanceModel := new(MockBalanceModel)
+	mockAssetTransactionModel := new(MockAssetTransactionModel)
+	ledgerModel := &MockLedgerEntryModel{}
+	auditLogModel := new(MockWithdrawalAuditLogModel)
+
+	mockBalanceModel.On("Trans", mock.Anything, mock.Anything).Return(nil)
+	mockBalanceModel.On("FindByUserIDAndCurrencyForUpdate", mock.Anything, uint64(1), "BTC").Return(&model.Balance{
+		UserID: 1, Currency: "BTC", Available: "2", Frozen: "0.5",
+	}, nil)
+	// 可用余额扣除1.0005，冻结余额增加1.0005，总余额不变
+	mockBalanceModel.On("UpdateBalance", mock.Anything, uint64(1), "BTC", "0.9995", "1.5005").Return(nil)
+	mockAssetTransactionModel.On("Insert", mock.Anything, mock.MatchedBy(func(tx *model.AssetTransaction) bool {
+		return tx.Status == model.AssetTransactionStatusUnconfirmed && tx.Type == model.AssetTransactionTypeWithdraw
+	})).Return(nil, nil)
+	ledgerModel.On("InsertJournal", mock.Anything, mock.MatchedBy(func(j *model.LedgerJournal) bool {
+		return len(j.Entries) == 2 && j.Entries[0].Account == model.LedgerAccountAvailable &&
+			j.Entries[1].Account == model.LedgerAccountFrozen && j.Entries[1].Amount == "1.0005"
+	})).Return(nil)
+	auditLogModel.On("Insert", mock.Anything, mock.MatchedBy(func(log *model.WithdrawalAuditLog) bool {
+		return log.FromStatus == 0 && log.ToStatus == model.AssetTransactionStatusUnconfirmed && log.OperatorID == 1
+	})).Return(nil)
+
+	svcCtx := &svc.ServiceContext{
+		BalanceModel:            mockBalanceModel,
+		LedgerEntryModel:        ledgerModel,
+		AssetTransactionModel:   mockAssetTransactionModel,
+		CurrencyRegistry:        NewTestCurrencyRegistry(),
+		WithdrawalAuditLogModel: auditLogModel,
+	}
+	setupWithdrawSecurity(svcCtx, false, nil)
+	ctx := context.WithValue(context.Background(), "userId", float64(1))
+	resp, err := NewWithdrawLogic(ctx, svcCtx).Withdraw(&types.WithdrawRequest{
+		Currency: "BTC",
+		Amount:   "1",
+		Address:  "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AssetTransactionStatusUnconfirmed, resp.Status)
+	mockBalanceModel.AssertExpectations(t)
+	ledgerModel.AssertExpectations(t)
+	auditLogModel.AssertExpectations(t)
+}
+
+func TestWithdrawLogic_Withdraw_AddressRestrictions(t *testing.T) {
+	tests := []struct {
+		name          string
+		whitelistOnly bool
+		address       *model.WithdrawalAddress
+		expectedError error
+	}{
+		{"开启白名单时地址不在地址簿中", true, nil, model.ErrWithdrawAddressNotWhitelisted},
+		{"地址簿中的地址仍在冷却期", false, &model.WithdrawalAddress{AvailableAt: time.Now().Add(time.Hour)}, model.ErrWithdrawAddressCoolingDown},
+		{"开启白名单时冷却期同样生效", true, &model.WithdrawalAddress{AvailableAt: time.Now().Add(time.Hour)}, model.ErrWithdrawAddressCoolingDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBalanceModel := new(MockBalanceModel)
+			svcCtx := &svc.ServiceContext{
+				BalanceModel:     mockBalanceModel,
+				CurrencyRegistry: NewTestCurrencyRegistry(),
+			}
+			_, confirmCodes := setupWithdrawSecurity(svcCtx, tt.whitelistOnly, tt.address)
+
+			ctx := context.WithValue(context.Background(), "userId", float64(1))
+			resp, err := NewWithdrawLogic(ctx, svcCtx).Withdraw(&types.WithdrawRequest{
+				Currency: "BTC",
+				Amount:   "1",
+				Address:  "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+			})
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Nil(t, resp)
+			// 地址校验失败时不冻结余额、不生成确认码
+			mockBalanceModel.AssertNotCalled(t, "Trans", mock.Anything, mock.Anything)
+			confirmCodes.AssertNotCalled(t, "Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestWithdrawLogic_Withdraw_WhitelistedAddress(t *testing.T) {
+	mockBalanceModel := new(MockBalanceModel)
+	mockAssetTransactionModel := new(MockAssetTransactionModel)
+	auditLogModel := new(MockWithdrawalAuditLogModel)
+
+	mockBalanceModel.On("Trans", mock.Anything, mock.Anything).Return(nil)
+	mockBalanceModel.On("FindByUserIDAndCurrencyForUpdate", mock.Anything, uint64(1), "BTC").Return(&model.Balance{
+		UserID: 1, Currency: "BTC", Available: "2", Frozen: "0",
+	}, nil)
+	mockBalanceModel.On("UpdateBalance", mock.Anything, uint64(1), "BTC", "0.9995", "1.0005").Return(nil)
+	mockAssetTransactionModel.On("Insert", mock.Anything, mock.Anything).Return(nil, nil)
+	auditLogModel.On("Insert", mock.Anything, mock.Anything).Return(nil)
+
+	svcCtx := &svc.ServiceContext{
+		BalanceModel:            mockBalanceModel,
+		LedgerEntryModel:        NewMockLedgerEntryModel(),
+		AssetTransactionModel:   mockAssetTransactionModel,
+		CurrencyRegistry:        NewTestCurrencyRegistry(),
+		WithdrawalAuditLogModel: auditLogModel,
+	}
+	svcCtx.Config.Withdraw.ConfirmTTL = 900
+	_, confirmCodes := setupWithdrawSecurity(svcCtx, true, &model.WithdrawalAddress{AvailableAt: time.Now().Add(-time.Minute)})
+
+	ctx := context.WithValue(context.Background(), "userId", float64(1))
+	resp, err := NewWithdrawLogic(ctx, svcCtx).Withdraw(&types.WithdrawRequest{
+		Currency: "BTC",
+		Amount:   "1",
+		Address:  "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AssetTransactionStatusUnconfirmed, resp.Status)
+	assert.NotEmpty(t, resp.ConfirmExpiresAt)
+	confirmCodes.AssertCalled(t, "Issue", mock.Anything, "withdraw", resp.TransactionID, 900*time.Second)
+
+	// 确认码通过邮件发送给用户
+	msg, ok := svcCtx.Mailer.(*mailer.MemoryMailer).Last("user@example.com")
+	if assert.True(t, ok) {
+		assert.Contains(t, msg.Body, "123456")
+		assert.Contains(t, msg.Body, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa")
+	}
+}
+
+func TestWithdrawLogic_Withdraw_LimitExceeded(t *testing.T) {
+	mockBalanceModel := new(MockBalanceModel)
+	mockAssetTransactionModel := new(MockAssetTransactionModel)
+	// 24小时内已提现0.15 BTC，按50000 USDT估值为7500 USDT
+	mockAssetTransactionModel.On("FindWithdrawalsSince", mock.Anything, uint64(1), mock.Anything).Return([]*model.AssetTransaction{
+		{Currency: "BTC", Amount: "0.15", CreatedAt: time.Now().Add(-3 * time.Hour)},
+	}, nil)
+	tickerModel := new(MockTickerModel)
+	tickerModel.On("FindBySymbol", mock.Anything, "BTC/USDT").Return(&model.Ticker{Symbol: "BTC/USDT", LastPrice: "50000"}, nil)
+
+	svcCtx := &svc.ServiceContext{
+		BalanceModel:          mockBalanceModel,
+		AssetTransactionModel: mockAssetTransactionModel,
+		TickerModel:           tickerModel,
+		CurrencyRegistry:      NewTestCurrencyRegistry(),
+	}
+	svcCtx.Config.WithdrawLimit.ReferenceCurrency = "USDT"
+	svcCtx.Config.WithdrawLimit.Tiers = []config.WithdrawTier{{Level: model.UserVerificationUnverified, DailyAmount: "10000"}}
+	setupWithdrawSecurity(svcCtx, false, nil)
+
+	ctx := context.WithValue(context.Background(), "userId", float64(1))
+	resp, err := NewWithdrawLogic(ctx, svcCtx).Withdraw(&types.WithdrawRequest{
+		Currency: "BTC",
+		Amount:   "0.1",
+		Address:  "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+	})
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, model.ErrWithdrawLimitExceeded)
+	var limitErr *model.WithdrawLimitError
+	assert.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, model.WithdrawLimitReasonDailyAmount, limitErr.Reason)
+	// 被限额拒绝时不冻结余额
+	mockBalanceModel.AssertNotCalled(t, "Trans", mock.Anything, mock.Anything)
+}
+
+func TestWithdrawLogic_Withdraw_EmailNotVerified(t *testing.T) {
+	mockBalanceModel := new(MockBalanceModel)
+	svcCtx := &svc.ServiceContext{
+		BalanceModel:     mockBalanceModel,
+		CurrencyRegistry: NewTestCurrencyRegistry(),
+	}
+	_, confirmCodes := setupWithdrawSecurity(svcCtx, false, nil)
+	userModel := new(MockUserModel)
+	userModel.On("FindOne", mock.Anything, uint64(1)).Return(&model.User{ID: 1, Status: model.UserStatusActive}, nil)
+	svcCtx.UserModel = userModel
+	svcCtx.UserRestrictionModel = &memoryRestrictionModel{}
+
+	ctx := context.WithValue(context.Background(), "userId", float64(1))
+	resp, err := NewWithdrawLogic(ctx, svcCtx).Withdraw(&types.WithdrawRequest{
+		Currency: "BTC",
+		Amount:   "1",
+		Address:  "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+	})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, model.ErrEmailNotVerified, err)
+	mockBalanceModel.AssertNotCalled(t, "Trans", mock.Anything, mock.Anything)
+	confirmCodes.AssertNotCalled(t, "Issue", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// memoryRestrictionModel 进程内账户限制，只实现限制校验用到的查询
+type memoryRestrictionModel struct {
+	model.UserRestrictionModel
+	restrictions []*model.UserRestriction
+}
+
+func (m *memoryRestrictionModel) FindActiveByUserID(ctx context.Context, userID uint64, now time.Time) ([]*model.UserRestriction, error) {
+	var resp []*model.UserRestriction
+	for _, r := range m.restrictions {
+		if r.UserID == userID && (!r.ExpiresAt.Valid || r.ExpiresAt.Time.After(now)) {
+			resp = append(resp, r)
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"crypto-exchange/internal/logic/useremail"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ForgotPasswordLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewForgotPasswordLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ForgotPasswordLogic {
+	return &ForgotPasswordLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ForgotPassword 向注册邮箱发送重置密码链接
+// 为防止通过该接口探测邮箱是否注册，邮箱不存在、账户不可用或发送过于频繁时同样返回成功
+func (l *ForgotPasswordLogic) ForgotPassword(req *types.ForgotPasswordRequest) (resp *types.BaseResponse, err error) {
+	resp = &types.BaseResponse{
+		Code:    0,
+		Message: "If the email is registered, a password reset link has been sent",
+	}
+
+	email := strings.TrimSpace(req.Email)
+	if err := NewRegisterLogic(l.ctx, l.svcCtx).validateEmail(email); err != nil {
+		return nil, err
+	}
+
+	user, err := l.svcCtx.UserModel.FindOneByEmail(l.ctx, email)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return resp, nil
+		}
+		l.Errorf("Failed to find user by email: %v", err)
+		return nil, model.ErrInternalServer
+	}
+	if user.Status != model.UserStatusActive {
+		l.Infof("Password reset requested for inactive user %d", user.ID)
+		return resp, nil
+	}
+
+	if err := useremail.SendPasswordReset(l.ctx, l.svcCtx, user); err != nil {
+		l.Errorf("Failed to send password reset email to user %d: %v", user.ID, err)
+		return resp, nil
+	}
+
+	l.Infof("Password reset email sent to user %d", user.ID)
+	return resp, nil
+}
@@ -7,6 +7,10 @@ import (
 	"strconv"
 	"time"
 
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/restriction"
+	"crypto-exchange/internal/logic/securityevent"
+	"crypto-exchange/internal/logic/twofactor"
 	"crypto-exchange/internal/svc"
 	"crypto-exchange/internal/types"
 	"crypto-exchange/model"
@@ -16,6 +20,22 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// 登录失败原因，记录在安全事件详情中
+const (
+	loginFailInvalidEmail    = "invalid_email"
+	loginFailUserNotFound    = "user_not_found"
+	loginFailUserDisabled    = "user_disabled"
+	loginFailInvalidPassword = "invalid_password"
+	loginFailInvalidCode     = "invalid_2fa_code"
+	loginFailRestricted      = "account_restricted"
+)
+
+// 登录方式，记录在登录成功事件详情中
+const (
+	loginMethodPassword  = "password"
+	loginMethodTwoFactor = "password+2fa"
+)
+
 type LoginLogic struct {
 	logx.Logger
 	ctx    context.Context
@@ -38,7 +58,7 @@ func (l *LoginLogic) Login(req *types.LoginRequest) (resp *types.LoginResponse,
 
 	// 2. 验证邮箱格式
 	if err := l.validateEmail(req.Email); err != nil {
-		l.recordFailedLogin(req.Email)
+		l.recordFailedLogin(req.Email, nil, loginFailInvalidEmail)
 		return nil, err
 	}
 
@@ -46,7 +66,7 @@ func (l *LoginLogic) Login(req *types.LoginRequest) (resp *types.LoginResponse,
 	user, err := l.svcCtx.UserModel.FindOneByEmail(l.ctx, req.Email)
 	if err != nil {
 		if errors.Is(err, model.ErrNotFound) {
-			l.recordFailedLogin(req.Email)
+			l.recordFailedLogin(req.Email, nil, loginFailUserNotFound)
 			return nil, model.ErrUserNotFound
 		}
 		l.Errorf("Failed to find user by email: %v", err)
@@ -55,39 +75,91 @@ func (l *LoginLogic) Login(req *types.LoginRequest) (resp *types.LoginResponse,
 
 	// 4. 检查用户状态
 	if user.Status != 1 {
-		l.recordFailedLogin(req.Email)
+		l.recordFailedLogin(req.Email, user, loginFailUserDisabled)
 		return nil, model.ErrUserDisabled
 	}
 
 	// 5. 验证密码
 	if err := l.verifyPassword(req.Password, user.Password); err != nil {
-		l.recordFailedLogin(req.Email)
+		l.recordFailedLogin(req.Email, user, loginFailInvalidPassword)
 		return nil, model.ErrInvalidPassword
 	}
+	// 密码正确后再检查账户限制，避免向不知道密码的请求方暴露账户被限制
+	if err := restriction.CheckRestrictions(l.ctx, l.svcCtx, user.ID, model.RestrictionLogin); err != nil {
+		l.recordFailedLogin(req.Email, user, loginFailRestricted)
+		return nil, err
+	}
 
-	// 6. 生成JWT token
-	token, err := l.generateJWTToken(user)
+	// 6. 已启用两步验证的用户先返回预认证token，验证码通过后再签发JWT
+	enabled, err := twofactor.IsEnabled(l.ctx, l.svcCtx, user.ID)
 	if err != nil {
-		l.Errorf("Failed to generate JWT token: %v", err)
+		l.Errorf("Failed to find two-factor settings of user %d: %v", user.ID, err)
+		return nil, model.ErrInternalServer
+	}
+	if enabled {
+		preAuthToken, err := generatePreAuthToken(l.svcCtx, user)
+		if err != nil {
+			l.Errorf("Failed to generate pre-auth token: %v", err)
+			return nil, model.ErrInternalServer
+		}
+		return &types.LoginResponse{
+			TwoFactorRequired: true,
+			PreAuthToken:      preAuthToken,
+			User:              toUser(user),
+		}, nil
+	}
+
+	// 7. 创建会话并签发令牌
+	return l.completeLogin(user, req.UserAgent, loginMethodPassword)
+}
+
+// completeLogin 创建登录会话，签发访问令牌和刷新令牌，清除失败登录记录并记录登录成功事件
+func (l *LoginLogic) completeLogin(user *model.User, userAgent, method string) (*types.LoginResponse, error) {
+	sess, refreshToken, err := l.svcCtx.Sessions.Create(l.ctx, user.ID, userAgent)
+	if err != nil {
+		l.Errorf("Failed to create session for user %d: %v", user.ID, err)
 		return nil, model.ErrInternalServer
 	}
+	token, err := l.issueAccessToken(user, sess.ID)
+	if err != nil {
+		return nil, err
+	}
 
-	// 7. 清除失败登录记录
-	l.clearFailedLogin(req.Email)
+	l.clearFailedLogin(user.Email)
+	securityevent.RecordLogin(l.ctx, l.svcCtx, user, method)
+
+	l.Infof("User logged in successfully: %s", user.Email)
+	return &types.LoginResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    l.svcCtx.Config.Auth.AccessExpire,
+		User:         toUser(user),
+	}, nil
+}
 
-	// 8. 返回登录响应
-	resp = &types.LoginResponse{
-		Token: token,
-		User: types.User{
-			ID:       user.ID,
-			Email:    user.Email,
-			Nickname: user.Nickname,
-			Status:   user.Status,
-		},
+// issueAccessToken 签发会话的访问令牌，管理后台角色写入roles声明
+func (l *LoginLogic) issueAccessToken(user *model.User, sessionID string) (string, error) {
+	roles, err := l.svcCtx.UserRoleModel.FindRolesByUserID(l.ctx, user.ID)
+	if err != nil {
+		l.Errorf("Failed to find roles of user %d: %v", user.ID, err)
+		return "", model.ErrInternalServer
+	}
+	token, err := l.generateJWTToken(user, roles, sessionID)
+	if err != nil {
+		l.Errorf("Failed to generate JWT token: %v", err)
+		return "", model.ErrInternalServer
 	}
+	return token, nil
+}
 
-	l.Infof("User logged in successfully: %s", req.Email)
-	return resp, nil
+func toUser(user *model.User) types.User {
+	return types.User{
+		ID:            user.ID,
+		Email:         user.Email,
+		Nickname:      user.Nickname,
+		Status:        user.Status,
+		EmailVerified: user.EmailVerified(),
+	}
 }
 
 // validateEmail 验证邮箱格式（复用注册逻辑中的验证）
@@ -108,16 +180,20 @@ func (l *LoginLogic) verifyPassword(plainPassword, hashedPassword string) error
 	return nil
 }
 
-// generateJWTToken 生成JWT token
-func (l *LoginLogic) generateJWTToken(user *model.User) (string, error) {
+// generateJWTToken 生成JWT token，sid声明为所属会话ID，会话注销后token随之失效
+func (l *LoginLogic) generateJWTToken(user *model.User, roles []string, sessionID string) (string, error) {
 	now := time.Now()
 	expire := now.Add(time.Duration(l.svcCtx.Config.Auth.AccessExpire) * time.Second)
 
 	claims := jwt.MapClaims{
-		"iat":    now.Unix(),
-		"exp":    expire.Unix(),
-		"userId": user.ID,
-		"email":  user.Email,
+		"iat":                  now.Unix(),
+		"exp":                  expire.Unix(),
+		authctx.ClaimUserID:    user.ID,
+		authctx.ClaimEmail:     user.Email,
+		authctx.ClaimSessionID: sessionID,
+	}
+	if len(roles) > 0 {
+		claims[authctx.ClaimRoles] = roles
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -159,15 +235,33 @@ func (l *LoginLogic) checkLoginAttempts(email string) error {
 	return nil
 }
 
-// recordFailedLogin 记录失败登录
-func (l *LoginLogic) recordFailedLogin(email string) {
+// recordFailedLogin 记录失败登录：累加失败次数并写入登录失败安全事件
+// 邮箱不存在时user为nil，事件的用户ID记为0，详情中记录尝试登录的邮箱
+func (l *LoginLogic) recordFailedLogin(email string, user *model.User, reason string) {
+	attempts := l.incrFailedLogin(email)
+
+	var userID uint64
+	detail := reason
+	if user != nil {
+		userID = user.ID
+	} else {
+		detail = fmt.Sprintf("%s: %s", reason, email)
+	}
+	if attempts > 0 {
+		detail = fmt.Sprintf("%s (failed attempts: %d)", detail, attempts)
+	}
+	securityevent.Record(l.ctx, l.svcCtx, userID, model.SecurityEventLoginFailed, detail)
+}
+
+// incrFailedLogin 增加失败次数并返回累计次数，Redis出错时返回0
+func (l *LoginLogic) incrFailedLogin(email string) int {
 	key := fmt.Sprintf("login_attempts:%s", email)
 	
 	// 增加失败次数
-	_, err := l.svcCtx.RedisClient.Incr(key)
+	attempts, err := l.svcCtx.RedisClient.Incr(key)
 	if err != nil {
 		l.Errorf("Failed to increment login attempts: %v", err)
-		return
+		return 0
 	}
 
 	// 设置过期时间为15分钟
@@ -175,6 +269,7 @@ func (l *LoginLogic) recordFailedLogin(email string) {
 	if err != nil {
 		l.Errorf("Failed to set expiration for login attempts: %v", err)
 	}
+	return int(attempts)
 }
 
 // clearFailedLogin 清除失败登录记录
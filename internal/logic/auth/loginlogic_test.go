@@ -26,6 +26,7 @@ func TestLoginLogic_Login_BasicCases(t *testing.T) {
 		setupUser func(*MockUserModel)
 		wantErr   bool
 		errMsg    string
+		eventUser uint64 // 登录失败事件记录的用户ID
 	}{
 		{
 			name: "用户不存在",
@@ -55,8 +56,9 @@ func TestLoginLogic_Login_BasicCases(t *testing.T) {
 				}
 				mockUser.On("FindOneByEmail", mock.Anything, "disabled@example.com").Return(user, nil)
 			},
-			wantErr: true,
-			errMsg:  "user account is disabled",
+			wantErr:   true,
+			errMsg:    "user account is disabled",
+			eventUser: 1,
 		},
 		{
 			name: "邮箱为空",
@@ -91,8 +93,9 @@ func TestLoginLogic_Login_BasicCases(t *testing.T) {
 						AccessExpire: 3600,
 					},
 				},
-				UserModel:   mockUser,
-				RedisClient: &redis.Redis{}, // 使用真实的Redis客户端，但在测试中不会实际调用
+				UserModel:          mockUser,
+				RedisClient:        &redis.Redis{}, // 使用真实的Redis客户端，但在测试中不会实际调用
+				SecurityEventModel: &memorySecurityEventModel{},
 			}
 
 			// 创建逻辑实例
@@ -114,6 +117,15 @@ func TestLoginLogic_Login_BasicCases(t *testing.T) {
 				assert.Greater(t, resp.User.ID, uint64(0))
 			}
 
+			// 登录失败记录为安全事件，邮箱不存在时用户ID为0
+			if tt.wantErr {
+				events := svcCtx.SecurityEventModel.(*memorySecurityEventModel).events
+				if assert.Len(t, events, 1) {
+					assert.Equal(t, model.SecurityEventLoginFailed, events[0].EventType)
+					assert.Equal(t, tt.eventUser, events[0].UserID)
+				}
+			}
+
 			// 验证模拟调用
 			mockUser.AssertExpectations(t)
 		})
@@ -191,7 +203,7 @@ func TestLoginLogic_generateJWTToken(t *testing.T) {
 		Status:   1,
 	}
 
-	token, err := logic.generateJWTToken(user)
+	token, err := logic.generateJWTToken(user, []string{model.RoleFinance}, "session-1")
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
@@ -208,6 +220,8 @@ func TestLoginLogic_generateJWTToken(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, float64(123), claims["userId"])
 	assert.Equal(t, "test@example.com", claims["email"])
+	assert.Equal(t, []interface{}{model.RoleFinance}, claims["roles"])
+	assert.Equal(t, "session-1", claims["sid"])
 
 	// 验证过期时间
 	exp := claims["exp"].(float64)
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"crypto-exchange/internal/logic/restriction"
+	"crypto-exchange/internal/logic/twofactor"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// preAuthPurpose 预认证token的用途声明，防止与其他token混用
+const preAuthPurpose = "2fa_login"
+
+type LoginTwoFactorLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewLoginTwoFactorLogic(ctx context.Context, svcCtx *svc.ServiceContext) *LoginTwoFactorLogic {
+	return &LoginTwoFactorLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// LoginTwoFactor 两步登录第二步：校验预认证token和验证码（或恢复码），通过后签发JWT
+func (l *LoginTwoFactorLogic) LoginTwoFactor(req *types.LoginTwoFactorRequest) (resp *types.LoginResponse, err error) {
+	// 1. 解析预认证token
+	userID, err := parsePreAuthToken(l.svcCtx, req.PreAuthToken)
+	if err != nil {
+		return nil, model.ErrInvalidPreAuthToken
+	}
+
+	// 2. 查找用户并检查状态
+	user, err := l.svcCtx.UserModel.FindOne(l.ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrInvalidPreAuthToken
+		}
+		l.Errorf("Failed to find user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+	if err := restriction.Check(l.ctx, l.svcCtx, user, model.RestrictionLogin); err != nil {
+		return nil, err
+	}
+
+	// 3. 验证码错误与密码错误共用失败次数限制
+	login := NewLoginLogic(l.ctx, l.svcCtx)
+	if err := login.checkLoginAttempts(user.Email); err != nil {
+		return nil, err
+	}
+	if err := twofactor.Verify(l.ctx, l.svcCtx, user.ID, req.Code, true); err != nil {
+		if errors.Is(err, model.ErrInvalidTwoFactorCode) || errors.Is(err, model.ErrTwoFactorCodeRequired) {
+			login.recordFailedLogin(user.Email, user, loginFailInvalidCode)
+		}
+		return nil, err
+	}
+
+	// 4. 创建会话并签发令牌
+	return login.completeLogin(user, req.UserAgent, loginMethodTwoFactor)
+}
+
+// generatePreAuthToken 生成两步验证预认证token
+// 使用由AccessSecret派生的密钥签名，无法作为登录JWT通过接口认证
+func generatePreAuthToken(svcCtx *svc.ServiceContext, user *model.User) (string, error) {
+	now := svcCtx.TOTP.Now()
+	claims := jwt.MapClaims{
+		"iat":     now.Unix(),
+		"exp":     now.Add(time.Duration(svcCtx.Config.TwoFactor.PreAuthExpire) * time.Second).Unix(),
+		"userId":  user.ID,
+		"purpose": preAuthPurpose,
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(preAuthSecret(svcCtx))
+}
+
+// parsePreAuthToken 校验预认证token的签名、用途和有效期，返回用户ID
+func parsePreAuthToken(svcCtx *svc.ServiceContext, tokenString string) (uint64, error) {
+	parser := jwt.Parser{
+		ValidMethods:         []string{jwt.SigningMethodHS256.Alg()},
+		UseJSONNumber:        true,
+		SkipClaimsValidation: true,
+	}
+	token, err := parser.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return preAuthSecret(svcCtx), nil
+	})
+	if err != nil || !token.Valid {
+		return 0, errors.New("invalid pre-auth token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != preAuthPurpose {
+		return 0, errors.New("invalid pre-auth token purpose")
+	}
+	// 有效期按TOTP时钟校验，便于测试中固定时间
+	if !claims.VerifyExpiresAt(svcCtx.TOTP.Now().Unix(), true) {
+		return 0, errors.New("pre-auth token expired")
+	}
+
+	userID, ok := claims["userId"].(json.Number)
+	if !ok {
+		return 0, errors.New("invalid pre-auth token subject")
+	}
+	return strconv.ParseUint(userID.String(), 10, 64)
+}
+
+// preAuthSecret 派生预认证token的签名密钥
+func preAuthSecret(svcCtx *svc.ServiceContext) []byte {
+	mac := hmac.New(sha256.New, []byte(svcCtx.Config.Auth.AccessSecret))
+	mac.Write([]byte("2fa-pre-auth"))
+	return []byte(hex.EncodeToString(mac.Sum(nil)))
+}
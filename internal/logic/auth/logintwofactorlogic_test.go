@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/config"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/totp"
+	"crypto-exchange/model"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreAuthToken(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	authenticator := totp.NewAuthenticator("CryptoExchange", "test-encryption-key", 1)
+	authenticator.Now = func() time.Time { return now }
+
+	var c config.Config
+	c.Auth.AccessSecret = "test-secret"
+	c.TwoFactor.PreAuthExpire = 300
+	svcCtx := &svc.ServiceContext{Config: c, TOTP: authenticator}
+
+	token, err := generatePreAuthToken(svcCtx, &model.User{ID: 42, Email: "alice@example.com"})
+	assert.NoError(t, err)
+
+	userID, err := parsePreAuthToken(svcCtx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(42), userID)
+
+	// 预认证token不能作为登录JWT使用
+	_, err = jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return []byte(c.Auth.AccessSecret), nil })
+	assert.Error(t, err)
+
+	// 登录JWT也不能作为预认证token使用
+	loginToken, err := NewLoginLogic(context.Background(), svcCtx).generateJWTToken(&model.User{ID: 42}, nil, "session-1")
+	assert.NoError(t, err)
+	_, err = parsePreAuthToken(svcCtx, loginToken)
+	assert.Error(t, err)
+
+	now = now.Add(301 * time.Second)
+	_, err = parsePreAuthToken(svcCtx, token)
+	assert.Error(t, err)
+}
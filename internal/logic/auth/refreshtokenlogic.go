@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"crypto-exchange/internal/logic/restriction"
+	"crypto-exchange/internal/session"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RefreshTokenLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRefreshTokenLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RefreshTokenLogic {
+	return &RefreshTokenLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RefreshToken 轮换刷新令牌并签发新的访问令牌
+// 已轮换的旧刷新令牌再次使用时，说明令牌可能已泄露，整个会话立即失效
+func (l *RefreshTokenLogic) RefreshToken(req *types.RefreshTokenRequest) (resp *types.LoginResponse, err error) {
+	// 1. 校验并轮换刷新令牌
+	sess, refreshToken, err := l.svcCtx.Sessions.Refresh(l.ctx, req.RefreshToken, req.UserAgent)
+	if err != nil {
+		switch {
+		case errors.Is(err, session.ErrRefreshTokenInvalid):
+			return nil, model.ErrInvalidRefreshToken
+		case errors.Is(err, session.ErrRefreshTokenReused):
+			l.Infof("Refresh token reuse detected, session revoked")
+			return nil, model.ErrRefreshTokenReused
+		default:
+			l.Errorf("Failed to refresh session: %v", err)
+			return nil, model.ErrInternalServer
+		}
+	}
+
+	// 2. 用户被禁用或被限制登录后不再续期，同时注销会话
+	user, err := l.svcCtx.UserModel.FindOne(l.ctx, sess.UserID)
+	if err != nil && !errors.Is(err, model.ErrNotFound) {
+		l.Errorf("Failed to find user %d: %v", sess.UserID, err)
+		return nil, model.ErrInternalServer
+	}
+	denied := model.ErrUserDisabled
+	if user != nil {
+		denied = restriction.Check(l.ctx, l.svcCtx, user, model.RestrictionLogin)
+	}
+	if denied != nil {
+		if errors.Is(denied, model.ErrUserDisabled) || errors.Is(denied, model.ErrAccountRestricted) {
+			if err := l.svcCtx.Sessions.Revoke(l.ctx, sess.UserID, sess.ID); err != nil && !errors.Is(err, session.ErrSessionNotFound) {
+				l.Errorf("Failed to revoke session %s of user %d: %v", sess.ID, sess.UserID, err)
+			}
+		}
+		return nil, denied
+	}
+
+	// 3. 签发新的访问令牌
+	token, err := NewLoginLogic(l.ctx, l.svcCtx).issueAccessToken(user, sess.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.LoginResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    l.svcCtx.Config.Auth.AccessExpire,
+		User:         toUser(user),
+	}, nil
+}
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/config"
+	"crypto-exchange/internal/session"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// memorySessionStore 按令牌轮换语义模拟会话存储：当前令牌可以换取新令牌，旧令牌再次使用时注销会话
+type memorySessionStore struct {
+	session.Store
+	sessions map[string]*session.Session
+	current  map[string]string // 会话ID -> 当前刷新令牌
+	used     map[string]string // 已轮换的刷新令牌 -> 会话ID
+}
+
+func (m *memorySessionStore) Refresh(ctx context.Context, refreshToken, userAgent string) (*session.Session, string, error) {
+	if sessionID, ok := m.used[refreshToken]; ok {
+		delete(m.sessions, sessionID)
+		return nil, "", session.ErrRefreshTokenReused
+	}
+	for sessionID, token := range m.current {
+		if token == refreshToken {
+			if _, ok := m.sessions[sessionID]; !ok {
+				break
+			}
+			m.used[refreshToken] = sessionID
+			m.current[sessionID] = refreshToken + "'"
+			return m.sessions[sessionID], m.current[sessionID], nil
+		}
+	}
+	return nil, "", session.ErrRefreshTokenInvalid
+}
+
+func (m *memorySessionStore) Revoke(ctx context.Context, userID uint64, sessionID string) error {
+	delete(m.sessions, sessionID)
+	return nil
+}
+
+func (m *memorySessionStore) RevokeAll(ctx context.Context, userID uint64) error {
+	for id, sess := range m.sessions {
+		if sess.UserID == userID {
+			delete(m.sessions, id)
+		}
+	}
+	return nil
+}
+
+type memoryUserRoleModel struct {
+	model.UserRoleModel
+}
+
+func (m *memoryUserRoleModel) FindRolesByUserID(ctx context.Context, userID uint64) ([]string, error) {
+	return nil, nil
+}
+
+func TestRefreshToken(t *testing.T) {
+	store := &memorySessionStore{
+		sessions: map[string]*session.Session{"s1": {ID: "s1", UserID: 1}, "s2": {ID: "s2", UserID: 2}},
+		current:  map[string]string{"s1": "rt1", "s2": "rt2"},
+		used:     map[string]string{},
+	}
+	users := &MockUserModel{}
+	users.On("FindOne", mock.Anything, uint64(1)).Return(&model.User{ID: 1, Email: "alice@example.com", Status: model.UserStatusActive}, nil)
+	users.On("FindOne", mock.Anything, uint64(2)).Return(&model.User{ID: 2, Status: model.UserStatusDisabled}, nil)
+
+	var c config.Config
+	c.Auth.AccessSecret = "test-secret"
+	c.Auth.AccessExpire = 900
+	svcCtx := &svc.ServiceContext{Config: c, UserModel: users, UserRoleModel: &memoryUserRoleModel{}, UserRestrictionModel: &memoryRestrictionModel{}, Sessions: store}
+	logic := NewRefreshTokenLogic(context.Background(), svcCtx)
+
+	resp, err := logic.RefreshToken(&types.RefreshTokenRequest{RefreshToken: "rt1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "rt1'", resp.RefreshToken)
+	assert.Equal(t, int64(900), resp.ExpiresIn)
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(resp.Token, claims, func(*jwt.Token) (interface{}, error) { return []byte("test-secret"), nil })
+	assert.NoError(t, err)
+	assert.Equal(t, "s1", claims["sid"])
+
+	// 旧令牌被重复使用时整个会话失效，新令牌也随之失效
+	_, err = logic.RefreshToken(&types.RefreshTokenRequest{RefreshToken: "rt1"})
+	assert.ErrorIs(t, err, model.ErrRefreshTokenReused)
+	_, err = logic.RefreshToken(&types.RefreshTokenRequest{RefreshToken: "rt1'"})
+	assert.ErrorIs(t, err, model.ErrInvalidRefreshToken)
+
+	// 用户被禁用后会话被注销
+	_, err = logic.RefreshToken(&types.RefreshTokenRequest{RefreshToken: "rt2"})
+	assert.ErrorIs(t, err, model.ErrUserDisabled)
+	assert.NotContains(t, store.sessions, "s2")
+}
+
+// memoryRestrictionModel 进程内账户限制，只实现限制校验用到的查询
+type memoryRestrictionModel struct {
+	model.UserRestrictionModel
+	restrictions []*model.UserRestriction
+}
+
+func (m *memoryRestrictionModel) FindActiveByUserID(ctx context.Context, userID uint64, now time.Time) ([]*model.UserRestriction, error) {
+	var resp []*model.UserRestriction
+	for _, r := range m.restrictions {
+		if r.UserID == userID && (!r.ExpiresAt.Valid || r.ExpiresAt.Time.After(now)) {
+			resp = append(resp, r)
+		}
+	}
+	return resp, nil
+}
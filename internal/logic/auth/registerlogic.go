@@ -2,10 +2,12 @@ package auth
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
 	"time"
 
+	"crypto-exchange/internal/logic/useremail"
 	"crypto-exchange/internal/svc"
 	"crypto-exchange/internal/types"
 	"crypto-exchange/model"
@@ -29,17 +31,76 @@ func NewRegisterLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Register
 }
 
 func (l *RegisterLogic) Register(req *types.RegisterRequest) (resp *types.User, err error) {
-	// 1. 验证邮箱格式
+	// 1. 校验注册信息并生成用户记录
+	user, err := l.newUser(req, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 创建用户记录
+	result, err := l.svcCtx.UserModel.Insert(l.ctx, user)
+	if err != nil {
+		l.Errorf("Failed to insert user: %v", err)
+		return nil, model.ErrInternalServer
+	}
+
+	// 3. 获取插入的用户ID
+	userID, err := result.LastInsertId()
+	if err != nil {
+		l.Errorf("Failed to get last insert id: %v", err)
+		return nil, model.ErrInternalServer
+	}
+	user.ID = uint64(userID)
+
+	// 4. 发送邮箱验证邮件，发送失败不影响注册，用户登录后可以重新发送
+	if err := useremail.SendVerification(l.ctx, l.svcCtx, user); err != nil {
+		l.Errorf("Failed to send verification email to user %d: %v", user.ID, err)
+	}
+
+	// 5. 返回用户信息（不包含密码）
+	u := toUser(user)
+	resp = &u
+
+	l.Infof("User registered successfully: %s", req.Email)
+	return resp, nil
+}
+
+// CreateSubAccount 为母账户创建子账户，子账户使用独立的邮箱和密码登录，校验规则与注册相同
+func (l *RegisterLogic) CreateSubAccount(req *types.RegisterRequest, parentID uint64) (*model.User, error) {
+	user, err := l.newUser(req, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := l.svcCtx.UserModel.Insert(l.ctx, user); err != nil {
+		l.Errorf("Failed to insert sub-account of user %d: %v", parentID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	// 按邮箱回查获取子账户ID
+	created, err := l.svcCtx.UserModel.FindOneByEmail(l.ctx, user.Email)
+	if err != nil {
+		l.Errorf("Failed to find created sub-account %s: %v", user.Email, err)
+		return nil, model.ErrInternalServer
+	}
+
+	l.Infof("Sub-account %d created for user %d: %s", created.ID, parentID, created.Email)
+	return created, nil
+}
+
+// newUser 校验邮箱、密码和邮箱唯一性，生成待插入的用户记录，parentID为0表示普通账户
+func (l *RegisterLogic) newUser(req *types.RegisterRequest, parentID uint64) (*model.User, error) {
+	// 验证邮箱格式
 	if err := l.validateEmail(req.Email); err != nil {
 		return nil, err
 	}
 
-	// 2. 验证密码强度
+	// 验证密码强度
 	if err := l.validatePassword(req.Password); err != nil {
 		return nil, err
 	}
 
-	// 3. 检查用户是否已存在
+	// 检查用户是否已存在
 	existingUser, err := l.svcCtx.UserModel.FindOneByEmail(l.ctx, req.Email)
 	if err != nil && !errors.Is(err, model.ErrNotFound) {
 		l.Errorf("Failed to check user existence: %v", err)
@@ -49,48 +110,28 @@ func (l *RegisterLogic) Register(req *types.RegisterRequest) (resp *types.User,
 		return nil, model.ErrUserExists
 	}
 
-	// 4. 加密密码
+	// 加密密码
 	hashedPassword, err := l.hashPassword(req.Password)
 	if err != nil {
 		l.Errorf("Failed to hash password: %v", err)
 		return nil, model.ErrInternalServer
 	}
 
-	// 5. 创建用户记录
 	now := time.Now()
 	user := &model.User{
 		Email:     req.Email,
 		Password:  hashedPassword,
 		Nickname:  req.Nickname,
-		Status:    1, // 1-正常状态
+		Status:    model.UserStatusActive,
+		ParentID:  parentID,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
-
-	result, err := l.svcCtx.UserModel.Insert(l.ctx, user)
-	if err != nil {
-		l.Errorf("Failed to insert user: %v", err)
-		return nil, model.ErrInternalServer
-	}
-
-	// 6. 获取插入的用户ID
-	userID, err := result.LastInsertId()
-	if err != nil {
-		l.Errorf("Failed to get last insert id: %v", err)
-		return nil, model.ErrInternalServer
+	// 子账户由已登录的母账户创建，邮箱无需验证
+	if parentID != 0 {
+		user.EmailVerifiedAt = sql.NullTime{Time: now, Valid: true}
 	}
-	user.ID = uint64(userID)
-
-	// 7. 返回用户信息（不包含密码）
-	resp = &types.User{
-		ID:       user.ID,
-		Email:    user.Email,
-		Nickname: user.Nickname,
-		Status:   user.Status,
-	}
-
-	l.Infof("User registered successfully: %s", req.Email)
-	return resp, nil
+	return user, nil
 }
 
 // validateEmail 验证邮箱格式
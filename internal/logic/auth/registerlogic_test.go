@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"crypto-exchange/internal/config"
+	"crypto-exchange/internal/mailer"
 	"crypto-exchange/internal/svc"
 	"crypto-exchange/internal/types"
 	"crypto-exchange/model"
@@ -52,6 +53,36 @@ func (m *MockUserModel) Delete(ctx context.Context, id uint64) error {
 	return args.Error(0)
 }
 
+func (m *MockUserModel) UpdateWithdrawWhitelistOnly(ctx context.Context, id uint64, whitelistOnly bool, until sql.NullTime) error {
+	args := m.Called(ctx, id, whitelistOnly, until)
+	return args.Error(0)
+}
+
+func (m *MockUserModel) UpdatePassword(ctx context.Context, id uint64, password string) error {
+	args := m.Called(ctx, id, password)
+	return args.Error(0)
+}
+
+func (m *MockUserModel) UpdateStatus(ctx context.Context, id uint64, status int64) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+func (m *MockUserModel) MarkEmailVerified(ctx context.Context, id uint64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserModel) FindByParentID(ctx context.Context, parentID uint64) ([]*model.User, error) {
+	args := m.Called(ctx, parentID)
+	return args.Get(0).([]*model.User), args.Error(1)
+}
+
+func (m *MockUserModel) CountByParentID(ctx context.Context, parentID uint64) (int64, error) {
+	args := m.Called(ctx, parentID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // MockResult 模拟SQL结果
 type MockResult struct {
 	mock.Mock
@@ -166,9 +197,12 @@ func TestRegisterLogic_Register(t *testing.T) {
 			tt.setup(mockUser, mockResult)
 
 			// 创建服务上下文
+			mails := mailer.NewMemoryMailer()
 			svcCtx := &svc.ServiceContext{
 				Config: config.Config{},
 				UserModel: mockUser,
+				UserTokens: newMemoryTokenStore(),
+				Mailer: mails,
 				RedisClient: &redis.Redis{},
 			}
 
@@ -190,6 +224,12 @@ func TestRegisterLogic_Register(t *testing.T) {
 				assert.Equal(t, tt.req.Nickname, resp.Nickname)
 				assert.Equal(t, int64(1), resp.Status)
 				assert.Greater(t, resp.ID, uint64(0))
+				assert.False(t, resp.EmailVerified)
+
+				// 注册后发送邮箱验证邮件
+				msg, ok := mails.Last(tt.req.Email)
+				assert.True(t, ok)
+				assert.Contains(t, msg.Body, "token=")
 			}
 
 			// 验证模拟调用
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/useremail"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ResendVerificationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewResendVerificationLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ResendVerificationLogic {
+	return &ResendVerificationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ResendVerification 重新发送邮箱验证邮件，之前发送的验证链接随之失效
+func (l *ResendVerificationLogic) ResendVerification() (resp *types.BaseResponse, err error) {
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	user, err := l.svcCtx.UserModel.FindOne(l.ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrUserNotFound
+		}
+		l.Errorf("Failed to find user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+	if user.EmailVerified() {
+		return nil, model.ErrEmailAlreadyVerified
+	}
+
+	if err := useremail.SendVerification(l.ctx, l.svcCtx, user); err != nil {
+		return nil, err
+	}
+
+	l.Infof("Verification email resent to user %d", userID)
+	return &types.BaseResponse{
+		Code:    0,
+		Message: "Verification email sent",
+	}, nil
+}
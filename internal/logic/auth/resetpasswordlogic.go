@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"crypto-exchange/internal/logic/securityevent"
+	"crypto-exchange/internal/logic/useremail"
+	"crypto-exchange/internal/onetime"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ResetPasswordLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewResetPasswordLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ResetPasswordLogic {
+	return &ResetPasswordLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ResetPassword 使用重置密码邮件中的令牌设置新密码，成功后注销用户的全部登录会话
+// 修改密码后一段时间内禁止提现，见WithdrawLimit.PasswordChangeLock
+func (l *ResetPasswordLogic) ResetPassword(req *types.ResetPasswordRequest) (resp *types.BaseResponse, err error) {
+	register := NewRegisterLogic(l.ctx, l.svcCtx)
+
+	// 1. 先校验新密码强度，避免不合格的密码消耗令牌
+	if err := register.validatePassword(req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	// 2. 校验并消耗令牌
+	userID, err := l.svcCtx.UserTokens.Consume(l.ctx, useremail.PurposeResetPassword, strings.TrimSpace(req.Token))
+	if err != nil {
+		if errors.Is(err, onetime.ErrTokenInvalid) {
+			return nil, model.ErrInvalidEmailToken
+		}
+		l.Errorf("Failed to consume password reset token: %v", err)
+		return nil, model.ErrInternalServer
+	}
+
+	// 3. 检查用户状态
+	user, err := l.svcCtx.UserModel.FindOne(l.ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrUserNotFound
+		}
+		l.Errorf("Failed to find user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+	if user.Status != model.UserStatusActive {
+		return nil, model.ErrUserDisabled
+	}
+
+	// 4. 更新密码
+	hashedPassword, err := register.hashPassword(req.NewPassword)
+	if err != nil {
+		l.Errorf("Failed to hash password: %v", err)
+		return nil, model.ErrInternalServer
+	}
+	if err := l.svcCtx.UserModel.UpdatePassword(l.ctx, userID, hashedPassword); err != nil {
+		l.Errorf("Failed to update password of user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	// 5. 重置链接发送到注册邮箱，能够使用说明用户持有该邮箱
+	if !user.EmailVerified() {
+		if err := l.svcCtx.UserModel.MarkEmailVerified(l.ctx, userID); err != nil {
+			l.Errorf("Failed to mark email of user %d as verified: %v", userID, err)
+		}
+	}
+
+	// 6. 注销全部登录会话，已签发的访问令牌随之失效
+	if err := l.svcCtx.Sessions.RevokeAll(l.ctx, userID); err != nil {
+		l.Errorf("Failed to revoke sessions of user %d after password reset: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	securityevent.Record(l.ctx, l.svcCtx, userID, model.SecurityEventPasswordReset, "")
+	useremail.SendPasswordChanged(l.ctx, l.svcCtx, user)
+
+	l.Infof("User %d reset password", userID)
+	return &types.BaseResponse{
+		Code:    0,
+		Message: "Password reset successfully, please log in again",
+	}, nil
+}
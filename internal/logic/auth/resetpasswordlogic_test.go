@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"crypto-exchange/internal/clientinfo"
+	"crypto-exchange/internal/session"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestForgotAndResetPassword(t *testing.T) {
+	user := &model.User{ID: 1, Email: "alice@example.com", Nickname: "alice", Status: model.UserStatusActive}
+	mockUser := &MockUserModel{}
+	mockUser.On("FindOneByEmail", mock.Anything, "alice@example.com").Return(user, nil)
+	mockUser.On("FindOne", mock.Anything, uint64(1)).Return(user, nil)
+	mockUser.On("UpdatePassword", mock.Anything, uint64(1), mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("newpass123")) == nil
+	})).Return(nil).Once()
+	// 重置链接证明用户持有邮箱
+	mockUser.On("MarkEmailVerified", mock.Anything, uint64(1)).Return(nil).Once()
+
+	svcCtx, _, mails := newEmailTestContext(mockUser)
+	sessions := &memorySessionStore{sessions: map[string]*session.Session{
+		"s1": {ID: "s1", UserID: 1},
+		"s2": {ID: "s2", UserID: 1},
+		"s3": {ID: "s3", UserID: 2},
+	}}
+	svcCtx.Sessions = sessions
+	ctx := context.Background()
+
+	resp, err := NewForgotPasswordLogic(ctx, svcCtx).ForgotPassword(&types.ForgotPasswordRequest{Email: "alice@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resp.Code)
+	msg, ok := mails.Last("alice@example.com")
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Contains(t, msg.Body, "https://exchange.example.com/reset-password?lang=zh&token=")
+	assert.Contains(t, msg.Body, "30分钟")
+	token := tokenFromMail(t, msg)
+
+	// 密码强度不够时不消耗令牌
+	_, err = NewResetPasswordLogic(ctx, svcCtx).ResetPassword(&types.ResetPasswordRequest{Token: token, NewPassword: "short"})
+	assert.Error(t, err)
+
+	resetCtx := clientinfo.WithInfo(ctx, clientinfo.Info{IP: "203.0.113.7", UserAgent: "Mozilla/5.0", DeviceFingerprint: "fp1"})
+	_, err = NewResetPasswordLogic(resetCtx, svcCtx).ResetPassword(&types.ResetPasswordRequest{Token: token, NewPassword: "newpass123"})
+	assert.NoError(t, err)
+
+	// 重置密码记录为安全事件，来源信息取自请求上下文
+	events := svcCtx.SecurityEventModel.(*memorySecurityEventModel).events
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, uint64(1), events[0].UserID)
+		assert.Equal(t, model.SecurityEventPasswordReset, events[0].EventType)
+		assert.Equal(t, "203.0.113.7", events[0].IP)
+		assert.Equal(t, "fp1", events[0].DeviceFingerprint)
+	}
+
+	// 重置后用户的全部会话失效，其他用户不受影响
+	assert.NotContains(t, sessions.sessions, "s1")
+	assert.NotContains(t, sessions.sessions, "s2")
+	assert.Contains(t, sessions.sessions, "s3")
+	notice, _ := mails.Last("alice@example.com")
+	assert.Equal(t, "您的密码已重置", notice.Subject)
+
+	// 令牌只能使用一次
+	_, err = NewResetPasswordLogic(ctx, svcCtx).ResetPassword(&types.ResetPasswordRequest{Token: token, NewPassword: "another123"})
+	assert.Equal(t, model.ErrInvalidEmailToken, err)
+	mockUser.AssertExpectations(t)
+}
+
+func TestForgotPassword_DoesNotRevealAccounts(t *testing.T) {
+	disabled := &model.User{ID: 2, Email: "frozen@example.com", Status: model.UserStatusDisabled}
+	mockUser := &MockUserModel{}
+	mockUser.On("FindOneByEmail", mock.Anything, "nobody@example.com").Return(nil, model.ErrNotFound)
+	mockUser.On("FindOneByEmail", mock.Anything, "frozen@example.com").Return(disabled, nil)
+	svcCtx, _, mails := newEmailTestContext(mockUser)
+
+	for _, email := range []string{"nobody@example.com", "frozen@example.com"} {
+		resp, err := NewForgotPasswordLogic(context.Background(), svcCtx).ForgotPassword(&types.ForgotPasswordRequest{Email: email})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, resp.Code)
+	}
+	assert.Empty(t, mails.Messages())
+
+	_, err := NewForgotPasswordLogic(context.Background(), svcCtx).ForgotPassword(&types.ForgotPasswordRequest{Email: "not-an-email"})
+	assert.Equal(t, model.ErrInvalidEmail, err)
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"crypto-exchange/internal/logic/useremail"
+	"crypto-exchange/internal/onetime"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type VerifyEmailLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewVerifyEmailLogic(ctx context.Context, svcCtx *svc.ServiceContext) *VerifyEmailLogic {
+	return &VerifyEmailLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// VerifyEmail 使用验证邮件中的令牌验证邮箱，令牌只能使用一次
+func (l *VerifyEmailLogic) VerifyEmail(req *types.VerifyEmailRequest) (resp *types.BaseResponse, err error) {
+	userID, err := l.svcCtx.UserTokens.Consume(l.ctx, useremail.PurposeVerifyEmail, strings.TrimSpace(req.Token))
+	if err != nil {
+		if errors.Is(err, onetime.ErrTokenInvalid) {
+			return nil, model.ErrInvalidEmailToken
+		}
+		l.Errorf("Failed to consume email verification token: %v", err)
+		return nil, model.ErrInternalServer
+	}
+
+	if err := l.svcCtx.UserModel.MarkEmailVerified(l.ctx, userID); err != nil {
+		l.Errorf("Failed to mark email of user %d as verified: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	l.Infof("User %d verified email", userID)
+	return &types.BaseResponse{
+		Code:    0,
+		Message: "Email verified successfully",
+	}, nil
+}
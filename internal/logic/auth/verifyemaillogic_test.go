@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/mailer"
+	"crypto-exchange/internal/onetime"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// memoryTokenStore 模拟一次性令牌存储：每个用户每种用途只保留最近一次生成的令牌
+type memoryTokenStore struct {
+	tokens   map[string]uint64 // 用途:令牌 -> 用户ID
+	latest   map[string]string // 用途:用户ID -> 最近一次生成的令牌
+	throttle bool              // 为true时模拟发送过于频繁
+	seq      int
+}
+
+func newMemoryTokenStore() *memoryTokenStore {
+	return &memoryTokenStore{tokens: map[string]uint64{}, latest: map[string]string{}}
+}
+
+func (m *memoryTokenStore) Issue(ctx context.Context, purpose string, userID uint64, ttl, interval time.Duration) (string, error) {
+	if m.throttle {
+		return "", onetime.ErrTokenIssueTooOften
+	}
+	userKey := fmt.Sprintf("%s:%d", purpose, userID)
+	if old, ok := m.latest[userKey]; ok {
+		delete(m.tokens, purpose+":"+old)
+	}
+	m.seq++
+	token := fmt.Sprintf("token-%d", m.seq)
+	m.tokens[purpose+":"+token] = userID
+	m.latest[userKey] = token
+	return token, nil
+}
+
+func (m *memoryTokenStore) Consume(ctx context.Context, purpose, token string) (uint64, error) {
+	userID, ok := m.tokens[purpose+":"+token]
+	if !ok {
+		return 0, onetime.ErrTokenInvalid
+	}
+	delete(m.tokens, purpose+":"+token)
+	return userID, nil
+}
+
+// tokenFromMail 从邮件正文的链接中取出令牌
+// memorySecurityEventModel 保存写入的安全事件
+type memorySecurityEventModel struct {
+	model.SecurityEventModel
+	events []*model.SecurityEvent
+}
+
+func (m *memorySecurityEventModel) Insert(ctx context.Context, data *model.SecurityEvent) (sql.Result, error) {
+	m.events = append(m.events, data)
+	return nil, nil
+}
+
+func tokenFromMail(t *testing.T, msg mailer.Message) string {
+	link := regexp.MustCompile(`https?://\S+`).FindString(msg.Body)
+	u, err := url.Parse(link)
+	if !assert.NoError(t, err) {
+		return ""
+	}
+	return u.Query().Get("token")
+}
+
+func newEmailTestContext(mockUser *MockUserModel) (*svc.ServiceContext, *memoryTokenStore, *mailer.MemoryMailer) {
+	tokens := newMemoryTokenStore()
+	mails := mailer.NewMemoryMailer()
+	svcCtx := &svc.ServiceContext{
+		UserModel:          mockUser,
+		UserTokens:         tokens,
+		Mailer:             mails,
+		SecurityEventModel: &memorySecurityEventModel{},
+	}
+	svcCtx.Config.Email.VerifyURL = "https://exchange.example.com/verify-email"
+	svcCtx.Config.Email.ResetURL = "https://exchange.example.com/reset-password?lang=zh"
+	svcCtx.Config.Email.VerifyTokenTTL = 86400
+	svcCtx.Config.Email.ResetTokenTTL = 1800
+	return svcCtx, tokens, mails
+}
+
+func TestVerifyEmail(t *testing.T) {
+	user := &model.User{ID: 1, Email: "alice@example.com", Nickname: "alice", Status: model.UserStatusActive}
+	mockUser := &MockUserModel{}
+	mockUser.On("FindOne", mock.Anything, uint64(1)).Return(user, nil)
+	mockUser.On("MarkEmailVerified", mock.Anything, uint64(1)).Return(nil).Once()
+	svcCtx, _, mails := newEmailTestContext(mockUser)
+	ctx := authctx.NewUserContext(1)
+
+	// 第一次发送的链接在重新发送后失效
+	_, err := NewResendVerificationLogic(ctx, svcCtx).ResendVerification()
+	assert.NoError(t, err)
+	first, _ := mails.Last("alice@example.com")
+	_, err = NewResendVerificationLogic(ctx, svcCtx).ResendVerification()
+	assert.NoError(t, err)
+	second, ok := mails.Last("alice@example.com")
+	assert.True(t, ok)
+	assert.Contains(t, second.Body, "https://exchange.example.com/verify-email?token=")
+	assert.Contains(t, second.Body, "24小时")
+
+	_, err = NewVerifyEmailLogic(context.Background(), svcCtx).VerifyEmail(&types.VerifyEmailRequest{Token: tokenFromMail(t, first)})
+	assert.Equal(t, model.ErrInvalidEmailToken, err)
+
+	resp, err := NewVerifyEmailLogic(context.Background(), svcCtx).VerifyEmail(&types.VerifyEmailRequest{Token: tokenFromMail(t, second)})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resp.Code)
+
+	// 令牌只能使用一次
+	_, err = NewVerifyEmailLogic(context.Background(), svcCtx).VerifyEmail(&types.VerifyEmailRequest{Token: tokenFromMail(t, second)})
+	assert.Equal(t, model.ErrInvalidEmailToken, err)
+	mockUser.AssertExpectations(t)
+}
+
+func TestResendVerification_Rejections(t *testing.T) {
+	verified := &model.User{ID: 2, Email: "bob@example.com", Status: model.UserStatusActive,
+		EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true}}
+	pending := &model.User{ID: 3, Email: "carol@example.com", Status: model.UserStatusActive}
+	mockUser := &MockUserModel{}
+	mockUser.On("FindOne", mock.Anything, uint64(2)).Return(verified, nil)
+	mockUser.On("FindOne", mock.Anything, uint64(3)).Return(pending, nil)
+	svcCtx, tokens, mails := newEmailTestContext(mockUser)
+
+	_, err := NewResendVerificationLogic(authctx.NewUserContext(2), svcCtx).ResendVerification()
+	assert.Equal(t, model.ErrEmailAlreadyVerified, err)
+
+	tokens.throttle = true
+	_, err = NewResendVerificationLogic(authctx.NewUserContext(3), svcCtx).ResendVerification()
+	assert.Equal(t, model.ErrEmailSendTooFrequently, err)
+	assert.Empty(t, mails.Messages())
+
+	_, err = NewResendVerificationLogic(context.Background(), svcCtx).ResendVerification()
+	assert.Equal(t, model.ErrUnauthorized, err)
+}
@@ -0,0 +1,118 @@
+// Package deposit 链上充值：为用户分配充值地址，扫描链上入账转账，
+// 达到网络要求的确认数后入账，并在链重组移除转账时撤销未确认记录或扣回已入账金额。
+package deposit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// allocateRetries 并发分配冲突（同一用户重复请求或派生序号被占用）时的重试次数
+const allocateRetries = 3
+
+// AddressAllocator 充值地址分配器：优先从预生成的地址池分配，地址池为空时按派生序号生成
+type AddressAllocator struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// NewAddressAllocator 创建充值地址分配器
+func NewAddressAllocator(ctx context.Context, svcCtx *svc.ServiceContext) *AddressAllocator {
+	return &AddressAllocator{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Allocate 获取用户在指定币种和网络上的充值地址，没有时分配一个新地址
+// 返回的网络配置用于告知用户最小充值金额和所需确认数
+func (a *AddressAllocator) Allocate(userID uint64, code, network string) (*model.DepositAddress, *model.CurrencyNetwork, error) {
+	c, n, err := a.svcCtx.CurrencyRegistry.Resolve(a.ctx, code, network)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.Status != model.CurrencyStatusEnabled {
+		return nil, nil, model.ErrCurrencyDisabled
+	}
+	if !c.DepositEnabled || !n.DepositEnabled {
+		return nil, nil, model.ErrDepositDisabled
+	}
+
+	var lastErr error
+	for i := 0; i < allocateRetries; i++ {
+		existing, err := a.svcCtx.DepositAddressModel.FindByUserAndCurrency(a.ctx, userID, c.Code, n.Network)
+		if err == nil {
+			return existing, n, nil
+		}
+		if !errors.Is(err, model.ErrNotFound) {
+			return nil, nil, err
+		}
+
+		address, err := a.allocate(userID, c.Code, n.Network)
+		if err == nil {
+			a.Infof("Allocated deposit address %s on %s to user %d for %s", address.Address, n.Network, userID, c.Code)
+			return address, n, nil
+		}
+		// 插入冲突时重新查询：可能是同一用户的并发请求已分配成功
+		lastErr = err
+	}
+
+	a.Errorf("Failed to allocate deposit address on %s for user %d: %v", n.Network, userID, lastErr)
+	return nil, nil, lastErr
+}
+
+// allocate 从地址池分配或按下一个派生序号生成地址
+func (a *AddressAllocator) allocate(userID uint64, code, network string) (*model.DepositAddress, error) {
+	claimed, err := a.svcCtx.DepositAddressModel.ClaimFromPool(a.ctx, userID, code, network)
+	if err == nil {
+		return claimed, nil
+	}
+	if !errors.Is(err, model.ErrNotFound) {
+		return nil, fmt.Errorf("failed to claim address from pool: %w", err)
+	}
+
+	adapter, err := a.svcCtx.ChainAdapters.Get(network)
+	if err != nil {
+		return nil, err
+	}
+
+	maxIndex, err := a.svcCtx.DepositAddressModel.MaxDerivationIndex(a.ctx, network)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get derivation index: %w", err)
+	}
+	index := maxIndex + 1
+
+	addr, err := adapter.DeriveAddress(a.ctx, uint64(index))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive address: %w", err)
+	}
+
+	now := time.Now()
+	address := &model.DepositAddress{
+		UserID:          userID,
+		Currency:        code,
+		Network:         network,
+		Address:         addr,
+		DerivationIndex: index,
+		CreatedAt:       now,
+		AssignedAt:      sql.NullTime{Time: now, Valid: true},
+	}
+	result, err := a.svcCtx.DepositAddressModel.Insert(a.ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save derived address: %w", err)
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		address.ID = uint64(id)
+	}
+	return address, nil
+}
@@ -0,0 +1,393 @@
+package deposit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"crypto-exchange/internal/chain"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+// Watcher 充值扫描任务
+// 每轮从上次扫描高度减去重组窗口处重新扫描，更新确认数、入账达到确认数的充值，
+// 并把窗口内已记录但链上不再存在的充值视为被链重组移除；已入账的充值确认数回落到要求以下时扣回入账，重新等待确认
+type Watcher struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// NewWatcher 创建充值扫描任务
+func NewWatcher(ctx context.Context, svcCtx *svc.ServiceContext) *Watcher {
+	return &Watcher{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RunOnce 扫描所有开放充值的网络，单个网络扫描失败时记录日志并继续扫描其他网络
+func (w *Watcher) RunOnce() error {
+	networks, err := w.depositNetworks()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(networks))
+	for network := range networks {
+		names = append(names, network)
+	}
+	sort.Strings(names)
+
+	for _, network := range names {
+		if err := w.scanNetwork(network, networks[network]); err != nil {
+			w.Errorf("Failed to scan deposits on %s: %v", network, err)
+		}
+	}
+	return nil
+}
+
+// depositNetworks 按网络汇总开放充值的币种配置：网络代码 -> 币种代码 -> 网络配置
+func (w *Watcher) depositNetworks() (map[string]map[string]*model.CurrencyNetwork, error) {
+	currencies, err := w.svcCtx.CurrencyRegistry.List(w.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	networks := make(map[string]map[string]*model.CurrencyNetwork)
+	for _, c := range currencies {
+		if c.Status != model.CurrencyStatusEnabled || !c.DepositEnabled {
+			continue
+		}
+		list, err := w.svcCtx.CurrencyRegistry.Networks(w.ctx, c.Code)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range list {
+			if !n.DepositEnabled {
+				continue
+			}
+			if networks[n.Network] == nil {
+				networks[n.Network] = make(map[string]*model.CurrencyNetwork)
+			}
+			networks[n.Network][c.Code] = n
+		}
+	}
+	return networks, nil
+}
+
+// scanNetwork 扫描单个网络
+func (w *Watcher) scanNetwork(network string, configs map[string]*model.CurrencyNetwork) error {
+	adapter, err := w.svcCtx.ChainAdapters.Get(network)
+	if err != nil {
+		return err
+	}
+
+	latest, err := adapter.LatestHeight(w.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get latest height: %w", err)
+	}
+
+	// 重组窗口至少覆盖该网络要求的最大确认数，保证待确认的充值每轮都会被重新扫描
+	window := w.svcCtx.Config.Chain.ReorgWindow
+	for _, n := range configs {
+		if n.Confirmations > window {
+			window = n.Confirmations
+		}
+	}
+
+	var fromHeight int64 = 1
+	cursor, err := w.svcCtx.ChainScanCursorModel.FindOne(w.ctx, network)
+	if err == nil {
+		fromHeight = cursor.Height - window + 1
+		if fromHeight < 1 {
+			fromHeight = 1
+		}
+	} else if !errors.Is(err, model.ErrNotFound) {
+		return fmt.Errorf("failed to get scan cursor: %w", err)
+	}
+
+	transfers, err := adapter.Transfers(w.ctx, fromHeight)
+	if err != nil {
+		return fmt.Errorf("failed to get transfers: %w", err)
+	}
+
+	seen := make(map[string]bool, len(transfers))
+	for _, transfer := range transfers {
+		n, ok := configs[transfer.Currency]
+		if !ok {
+			continue
+		}
+		address, err := w.svcCtx.DepositAddressModel.FindByNetworkAndAddress(w.ctx, network, transfer.Address)
+		if errors.Is(err, model.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find deposit address %s: %w", transfer.Address, err)
+		}
+
+		transactionID := DepositTransactionID(network, transfer)
+		seen[transactionID] = true
+		if err := w.processTransfer(address.UserID, transactionID, transfer, n); err != nil {
+			w.Errorf("Failed to process deposit %s (tx %s) on %s: %v", transactionID, transfer.TxHash, network, err)
+		}
+	}
+
+	recorded, err := w.svcCtx.AssetTransactionModel.FindChainDepositsSince(w.ctx, network, fromHeight)
+	if err != nil {
+		return fmt.Errorf("failed to get recorded deposits: %w", err)
+	}
+	for _, tx := range recorded {
+		if seen[tx.TransactionID] {
+			continue
+		}
+		if err := w.handleRemoved(tx); err != nil {
+			w.Errorf("Failed to handle reorganized deposit %s (tx %s) on %s: %v", tx.TransactionID, tx.TxHash, network, err)
+		}
+	}
+
+	return w.svcCtx.ChainScanCursorModel.Upsert(w.ctx, network, latest)
+}
+
+// processTransfer 记录或更新一笔入账转账，达到确认数时入账
+func (w *Watcher) processTransfer(userID uint64, transactionID string, transfer *chain.Transfer, n *model.CurrencyNetwork) error {
+	tx, err := w.svcCtx.AssetTransactionModel.FindByTransactionID(w.ctx, transactionID)
+	if errors.Is(err, model.ErrNotFound) {
+		tx, err = w.record(userID, transactionID, transfer, n)
+		if err != nil || tx.Status != model.AssetTransactionStatusPending {
+			return err
+		}
+	} else if err != nil {
+		return err
+	} else {
+		switch tx.Status {
+		case model.AssetTransactionStatusCancelled, model.AssetTransactionStatusReverted:
+			// 被链重组移除后又重新打包，回到待确认
+			if err := w.svcCtx.AssetTransactionModel.UpdateStatus(w.ctx, tx.ID, tx.Status, model.AssetTransactionStatusPending, "", "re-included after chain reorganization"); err != nil {
+				return err
+			}
+			tx.Status = model.AssetTransactionStatusPending
+		case model.AssetTransactionStatusPending, model.AssetTransactionStatusSuccess:
+		default:
+			return nil
+		}
+
+		if tx.BlockHeight != transfer.BlockHeight || tx.Confirmations != transfer.Confirmations {
+			if err := w.svcCtx.AssetTransactionModel.UpdateConfirmations(w.ctx, tx.ID, transfer.BlockHeight, transfer.Confirmations); err != nil {
+				return err
+			}
+		}
+		if tx.Status == model.AssetTransactionStatusSuccess {
+			if transfer.Confirmations >= requiredConfirmations(n) {
+				return nil
+			}
+			// 重组后转账被打包到新的区块，确认数回落到要求以下：扣回入账并回到待确认，重新达到确认数后再入账
+			return w.reverse(tx, model.AssetTransactionStatusPending, "confirmations dropped after chain reorganization")
+		}
+	}
+
+	if transfer.Confirmations < requiredConfirmations(n) {
+		return nil
+	}
+	return w.credit(tx)
+}
+
+// record 新建待确认的充值记录，低于最小充值金额的转账记为失败，不入账
+func (w *Watcher) record(userID uint64, transactionID string, transfer *chain.Transfer, n *model.CurrencyNetwork) (*model.AssetTransaction, error) {
+	amount, err := decimal.NewFromString(transfer.Amount)
+	if err != nil || !amount.IsPositive() {
+		return nil, fmt.Errorf("invalid transfer amount: %s", transfer.Amount)
+	}
+
+	now := time.Now()
+	tx := &model.AssetTransaction{
+		UserID:        userID,
+		TransactionID: transactionID,
+		Currency:      transfer.Currency,
+		Network:       n.Network,
+		Type:          model.AssetTransactionTypeDeposit,
+		Amount:        amount.String(),
+		Fee:           "0",
+		Status:        model.AssetTransactionStatusPending,
+		Address:       transfer.Address,
+		TxHash:        transfer.TxHash,
+		BlockHeight:   transfer.BlockHeight,
+		Confirmations: transfer.Confirmations,
+		Remark:        fmt.Sprintf("Deposit %s %s via %s", amount.String(), transfer.Currency, n.Network),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if minDeposit, err := decimal.NewFromString(n.MinDeposit); err == nil && amount.LessThan(minDeposit) {
+		tx.Status = model.AssetTransactionStatusFailed
+		tx.Remark = fmt.Sprintf("amount below minimum deposit %s", minDeposit.String())
+	}
+
+	result, err := w.svcCtx.AssetTransactionModel.Insert(w.ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record deposit: %w", err)
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		tx.ID = uint64(id)
+	}
+
+	w.Infof("Detected deposit %s: %s %s to user %d, tx %s, %d confirmations", transactionID,
+		tx.Amount, tx.Currency, userID, transfer.TxHash, transfer.Confirmations)
+	return tx, nil
+}
+
+// credit 入账：待确认 -> 成功，金额加到可用余额并记账
+func (w *Watcher) credit(tx *model.AssetTransaction) error {
+	amount, err := decimal.NewFromString(tx.Amount)
+	if err != nil {
+		return fmt.Errorf("invalid deposit amount: %s", tx.Amount)
+	}
+
+	err = w.svcCtx.BalanceModel.Trans(w.ctx, func(ctx context.Context, session sqlx.Session) error {
+		transactions := w.svcCtx.AssetTransactionModel.WithSession(session)
+		ledger := w.svcCtx.LedgerEntryModel.WithSession(session)
+
+		if err := transactions.UpdateStatus(ctx, tx.ID, model.AssetTransactionStatusPending, model.AssetTransactionStatusSuccess, "", ""); err != nil {
+			return err
+		}
+		if err := w.adjustAvailable(ctx, session, tx.UserID, tx.Currency, amount); err != nil {
+			return err
+		}
+
+		journal := model.NewLedgerJournal(model.LedgerBizDeposit, tx.TransactionID)
+		journal.Transfer(tx.Currency, amount.String(), model.SystemExternal(), model.UserAvailable(tx.UserID), "deposit")
+		return ledger.InsertJournal(ctx, journal)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to credit deposit: %w", err)
+	}
+
+	tx.Status = model.AssetTransactionStatusSuccess
+	w.Infof("Credited deposit %s: %s %s to user %d", tx.TransactionID, tx.Amount, tx.Currency, tx.UserID)
+	return nil
+}
+
+// handleRemoved 处理被链重组移除的充值：未入账的取消，已入账的扣回可用余额
+func (w *Watcher) handleRemoved(tx *model.AssetTransaction) error {
+	const remark = "removed by chain reorganization"
+
+	if tx.Status == model.AssetTransactionStatusPending {
+		w.Infof("Deposit %s (tx %s) dropped before confirmation", tx.TransactionID, tx.TxHash)
+		return w.svcCtx.AssetTransactionModel.UpdateStatus(w.ctx, tx.ID, model.AssetTransactionStatusPending, model.AssetTransactionStatusCancelled, "", remark)
+	}
+
+	return w.reverse(tx, model.AssetTransactionStatusReverted, remark)
+}
+
+// reverse 扣回已入账的充值：成功 -> toStatus，从可用余额中扣除金额并记账
+func (w *Watcher) reverse(tx *model.AssetTransaction, toStatus int64, remark string) error {
+	amount, err := decimal.NewFromString(tx.Amount)
+	if err != nil {
+		return fmt.Errorf("invalid deposit amount: %s", tx.Amount)
+	}
+
+	err = w.svcCtx.BalanceModel.Trans(w.ctx, func(ctx context.Context, session sqlx.Session) error {
+		transactions := w.svcCtx.AssetTransactionModel.WithSession(session)
+		ledger := w.svcCtx.LedgerEntryModel.WithSession(session)
+
+		if err := transactions.UpdateStatus(ctx, tx.ID, model.AssetTransactionStatusSuccess, toStatus, "", remark); err != nil {
+			return err
+		}
+		if err := w.adjustAvailable(ctx, session, tx.UserID, tx.Currency, amount.Neg()); err != nil {
+			return err
+		}
+
+		journal := model.NewLedgerJournal(model.LedgerBizDeposit, tx.TransactionID)
+		journal.Transfer(tx.Currency, amount.String(), model.UserAvailable(tx.UserID), model.SystemExternal(), "deposit reversal")
+		return ledger.InsertJournal(ctx, journal)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revert deposit: %w", err)
+	}
+
+	tx.Status = toStatus
+	w.Errorf("Reverted credited deposit %s: %s %s from user %d, tx %s: %s",
+		tx.TransactionID, tx.Amount, tx.Currency, tx.UserID, tx.TxHash, remark)
+	return nil
+}
+
+// adjustAvailable 在事务中锁定并调整可用余额，余额记录不存在时创建
+// 扣回已入账充值时用户可能已经使用了这部分资金，可用余额允许变为负数，由对账和人工处理
+func (w *Watcher) adjustAvailable(ctx context.Context, session sqlx.Session, userID uint64, currency string, delta decimal.Decimal) error {
+	balances := w.svcCtx.BalanceModel.WithSession(session)
+	balance, err := balances.FindByUserIDAndCurrencyForUpdate(ctx, userID, currency)
+	if errors.Is(err, model.ErrNotFound) {
+		_, err = balances.Insert(ctx, &model.Balance{
+			UserID:    userID,
+			Currency:  currency,
+			Available: delta.String(),
+			Frozen:    "0",
+			UpdatedAt: time.Now(),
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	available, err := decimal.NewFromString(balance.Available)
+	if err != nil {
+		return fmt.Errorf("invalid available balance: %s", balance.Available)
+	}
+	newAvailable := available.Add(delta)
+	if newAvailable.IsNegative() {
+		w.Errorf("Available balance of user %d %s becomes negative: %s", userID, currency, newAvailable.String())
+	}
+	return balances.UpdateBalance(ctx, userID, currency, newAvailable.String(), balance.Frozen)
+}
+
+// requiredConfirmations 网络要求的入账确认数，至少为1
+func requiredConfirmations(n *model.CurrencyNetwork) int64 {
+	if n.Confirmations < 1 {
+		return 1
+	}
+	return n.Confirmations
+}
+
+// DepositTransactionID 由网络、交易哈希和转账序号生成确定的充值交易ID，重复扫描同一转账不会重复记录
+func DepositTransactionID(network string, transfer *chain.Transfer) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%d", network, transfer.TxHash, transfer.Index)))
+	return "DEP_" + hex.EncodeToString(sum[:16])
+}
+
+// StartWatcher 启动定时充值扫描任务，interval<=0时不启动，返回停止函数
+func StartWatcher(svcCtx *svc.ServiceContext, interval time.Duration) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	if interval <= 0 {
+		return cancel
+	}
+
+	watcher := NewWatcher(ctx, svcCtx)
+	threading.GoSafe(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := watcher.RunOnce(); err != nil {
+					logx.Errorf("Failed to scan deposits: %v", err)
+				}
+			}
+		}
+	})
+
+	return cancel
+}
@@ -0,0 +1,410 @@
+package deposit
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/chain"
+	"crypto-exchange/internal/config"
+	"crypto-exchange/internal/currency"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// insertResult 模拟插入结果，返回自增ID
+type insertResult int64
+
+func (r insertResult) LastInsertId() (int64, error) { return int64(r), nil }
+func (r insertResult) RowsAffected() (int64, error) { return 1, nil }
+
+// memoryTransactionStore 内存中的充值记录
+type memoryTransactionStore struct {
+	model.AssetTransactionModel
+	txs []*model.AssetTransaction
+}
+
+func (m *memoryTransactionStore) Insert(ctx context.Context, data *model.AssetTransaction) (sql.Result, error) {
+	copied := *data
+	copied.ID = uint64(len(m.txs) + 1)
+	m.txs = append(m.txs, &copied)
+	return insertResult(copied.ID), nil
+}
+
+func (m *memoryTransactionStore) FindByTransactionID(ctx context.Context, transactionID string) (*model.AssetTransaction, error) {
+	for _, tx := range m.txs {
+		if tx.TransactionID == transactionID {
+			copied := *tx
+			return &copied, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *memoryTransactionStore) FindChainDepositsSince(ctx context.Context, network string, fromHeight int64) ([]*model.AssetTransaction, error) {
+	var resp []*model.AssetTransaction
+	for _, tx := range m.txs {
+		if tx.Network == network && tx.BlockHeight >= fromHeight && tx.BlockHeight > 0 &&
+			(tx.Status == model.AssetTransactionStatusPending || tx.Status == model.AssetTransactionStatusSuccess) {
+			copied := *tx
+			resp = append(resp, &copied)
+		}
+	}
+	return resp, nil
+}
+
+func (m *memoryTransactionStore) UpdateStatus(ctx context.Context, id uint64, fromStatus, toStatus int64, txHash, remark string) error {
+	tx := m.txs[id-1]
+	if tx.Status != fromStatus {
+		return model.ErrWithdrawalStatusChanged
+	}
+	tx.Status = toStatus
+	if remark != "" {
+		tx.Remark = remark
+	}
+	return nil
+}
+
+func (m *memoryTransactionStore) UpdateConfirmations(ctx context.Context, id uint64, blockHeight, confirmations int64) error {
+	m.txs[id-1].BlockHeight = blockHeight
+	m.txs[id-1].Confirmations = confirmations
+	return nil
+}
+
+func (m *memoryTransactionStore) WithSession(session sqlx.Session) model.AssetTransactionModel {
+	return m
+}
+
+// memoryBalanceStore 内存中的余额，key为币种
+type memoryBalanceStore struct {
+	model.BalanceModel
+	balances map[string]*model.Balance
+}
+
+func (m *memoryBalanceStore) FindByUserIDAndCurrency(ctx context.Context, userID uint64, currency string) (*model.Balance, error) {
+	balance, ok := m.balances[currency]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	copied := *balance
+	return &copied, nil
+}
+
+func (m *memoryBalanceStore) FindByUserIDAndCurrencyForUpdate(ctx context.Context, userID uint64, currency string) (*model.Balance, error) {
+	return m.FindByUserIDAndCurrency(ctx, userID, currency)
+}
+
+func (m *memoryBalanceStore) Insert(ctx context.Context, data *model.Balance) (sql.Result, error) {
+	copied := *data
+	m.balances[data.Currency] = &copied
+	return insertResult(len(m.balances)), nil
+}
+
+func (m *memoryBalanceStore) UpdateBalance(ctx context.Context, userID uint64, currency string, available, frozen string) error {
+	m.balances[currency].Available = available
+	m.balances[currency].Frozen = frozen
+	return nil
+}
+
+func (m *memoryBalanceStore) Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	return fn(ctx, nil)
+}
+
+func (m *memoryBalanceStore) WithSession(session sqlx.Session) model.BalanceModel {
+	return m
+}
+
+// memoryAddressStore 内存中的充值地址
+type memoryAddressStore struct {
+	model.DepositAddressModel
+	addresses []*model.DepositAddress
+}
+
+func (m *memoryAddressStore) Insert(ctx context.Context, data *model.DepositAddress) (sql.Result, error) {
+	copied := *data
+	copied.ID = uint64(len(m.addresses) + 1)
+	m.addresses = append(m.addresses, &copied)
+	return insertResult(copied.ID), nil
+}
+
+func (m *memoryAddressStore) FindByUserAndCurrency(ctx context.Context, userID uint64, currency, network string) (*model.DepositAddress, error) {
+	for _, a := range m.addresses {
+		if a.UserID == userID && a.Currency == currency && a.Network == network {
+			copied := *a
+			return &copied, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *memoryAddressStore) FindByNetworkAndAddress(ctx context.Context, network, address string) (*model.DepositAddress, error) {
+	for _, a := range m.addresses {
+		if a.UserID > 0 && a.Network == network && a.Address == address {
+			copied := *a
+			return &copied, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *memoryAddressStore) ClaimFromPool(ctx context.Context, userID uint64, currency, network string) (*model.DepositAddress, error) {
+	for _, a := range m.addresses {
+		if a.UserID == 0 && a.Network == network {
+			a.UserID = userID
+			a.Currency = currency
+			copied := *a
+			return &copied, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *memoryAddressStore) MaxDerivationIndex(ctx context.Context, network string) (int64, error) {
+	var index int64 = -1
+	for _, a := range m.addresses {
+		if a.Network == network && a.DerivationIndex > index {
+			index = a.DerivationIndex
+		}
+	}
+	return index, nil
+}
+
+type memoryCursorStore struct {
+	model.ChainScanCursorModel
+	heights map[string]int64
+}
+
+func (m *memoryCursorStore) FindOne(ctx context.Context, network string) (*model.ChainScanCursor, error) {
+	height, ok := m.heights[network]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &model.ChainScanCursor{Network: network, Height: height}, nil
+}
+
+func (m *memoryCursorStore) Upsert(ctx context.Context, network string, height int64) error {
+	m.heights[network] = height
+	return nil
+}
+
+type mockLedgerEntryModel struct {
+	model.LedgerEntryModel
+	journals []*model.LedgerJournal
+}
+
+func (m *mockLedgerEntryModel) InsertJournal(ctx context.Context, journal *model.LedgerJournal) error {
+	if err := journal.Validate(); err != nil {
+		return err
+	}
+	m.journals = append(m.journals, journal)
+	return nil
+}
+
+func (m *mockLedgerEntryModel) WithSession(session sqlx.Session) model.LedgerEntryModel {
+	return m
+}
+
+type staticCurrencyModel struct {
+	model.CurrencyModel
+}
+
+func (m *staticCurrencyModel) FindAll(ctx context.Context) ([]*model.Currency, error) {
+	return []*model.Currency{{Code: "USDT", Precision: 6, Status: model.CurrencyStatusEnabled, DepositEnabled: true}}, nil
+}
+
+type staticNetworkModel struct {
+	model.CurrencyNetworkModel
+}
+
+func (m *staticNetworkModel) FindAll(ctx context.Context) ([]*model.CurrencyNetwork, error) {
+	return []*model.CurrencyNetwork{{
+		Currency: "USDT", Network: "ERC20", AddressType: model.AddressTypeEVM,
+		DepositEnabled: true, MinDeposit: "1", Confirmations: 3,
+	}}, nil
+}
+
+type testEnv struct {
+	svcCtx    *svc.ServiceContext
+	txs       *memoryTransactionStore
+	balances  *memoryBalanceStore
+	addresses *memoryAddressStore
+	ledger    *mockLedgerEntryModel
+	simulated *chain.SimulatedChain
+}
+
+func newTestEnv() *testEnv {
+	env := &testEnv{
+		txs:       &memoryTransactionStore{},
+		balances:  &memoryBalanceStore{balances: map[string]*model.Balance{}},
+		addresses: &memoryAddressStore{},
+		ledger:    &mockLedgerEntryModel{},
+		simulated: chain.NewSimulatedChain("ERC20", 0),
+	}
+	adapters := chain.NewRegistry(nil)
+	adapters.Register(env.simulated)
+
+	var c config.Config
+	c.Chain.ReorgWindow = 10
+	env.svcCtx = &svc.ServiceContext{
+		Config:                c,
+		AssetTransactionModel: env.txs,
+		BalanceModel:          env.balances,
+		LedgerEntryModel:      env.ledger,
+		DepositAddressModel:   env.addresses,
+		ChainScanCursorModel:  &memoryCursorStore{heights: map[string]int64{}},
+		CurrencyRegistry:      currency.NewRegistry(&staticCurrencyModel{}, &staticNetworkModel{}, time.Minute),
+		ChainAdapters:         adapters,
+	}
+	return env
+}
+
+func (e *testEnv) available() string {
+	balance, ok := e.balances.balances["USDT"]
+	if !ok {
+		return "0"
+	}
+	return decimal.RequireFromString(balance.Available).String()
+}
+
+func TestAddressAllocator_Allocate(t *testing.T) {
+	env := newTestEnv()
+	ctx := context.Background()
+	allocator := NewAddressAllocator(ctx, env.svcCtx)
+
+	// 地址池为空时按派生序号生成
+	first, n, err := allocator.Allocate(1, "USDT", "")
+	require.NoError(t, err)
+	assert.Equal(t, "ERC20", first.Network)
+	assert.Equal(t, int64(0), first.DerivationIndex)
+	assert.Equal(t, int64(3), n.Confirmations)
+	expected, _ := env.simulated.DeriveAddress(ctx, 0)
+	assert.Equal(t, expected, first.Address)
+
+	// 重复查询返回同一地址
+	again, _, err := allocator.Allocate(1, "USDT", "ERC20")
+	require.NoError(t, err)
+	assert.Equal(t, first.Address, again.Address)
+
+	// 地址池有可用地址时优先分配
+	env.addresses.addresses = append(env.addresses.addresses, &model.DepositAddress{
+		ID: 2, Network: "ERC20", Address: "0xpool", DerivationIndex: -1,
+	})
+	pooled, _, err := allocator.Allocate(2, "USDT", "ERC20")
+	require.NoError(t, err)
+	assert.Equal(t, "0xpool", pooled.Address)
+
+	other, _, err := allocator.Allocate(3, "USDT", "ERC20")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), other.DerivationIndex)
+
+	_, _, err = allocator.Allocate(1, "USDT", "TRC20")
+	assert.ErrorIs(t, err, model.ErrNetworkNotSupported)
+}
+
+func TestWatcher_CreditAfterConfirmations(t *testing.T) {
+	env := newTestEnv()
+	address, _, err := NewAddressAllocator(context.Background(), env.svcCtx).Allocate(1, "USDT", "ERC20")
+	require.NoError(t, err)
+	watcher := NewWatcher(context.Background(), env.svcCtx)
+
+	env.simulated.Deposit(address.Address, "USDT", "100")
+	env.simulated.Deposit("0xsomeoneelse", "USDT", "50")
+	env.simulated.Deposit(address.Address, "USDT", "0.5")
+	env.simulated.Mine(1)
+
+	// 检测到入账后记为待确认，不入账；低于最小充值金额的记为失败
+	require.NoError(t, watcher.RunOnce())
+	require.Len(t, env.txs.txs, 2)
+	tx := env.txs.txs[0]
+	assert.Equal(t, model.AssetTransactionStatusPending, tx.Status)
+	assert.Equal(t, int64(1), tx.Confirmations)
+	assert.NotEmpty(t, tx.TxHash)
+	assert.Equal(t, model.AssetTransactionStatusFailed, env.txs.txs[1].Status)
+	assert.Equal(t, "0", env.available())
+
+	env.simulated.Mine(1)
+	require.NoError(t, watcher.RunOnce())
+	assert.Equal(t, int64(2), tx.Confirmations)
+	assert.Equal(t, model.AssetTransactionStatusPending, tx.Status)
+
+	// 达到确认数后入账，重复扫描不会重复入账
+	env.simulated.Mine(1)
+	require.NoError(t, watcher.RunOnce())
+	require.NoError(t, watcher.RunOnce())
+	assert.Equal(t, model.AssetTransactionStatusSuccess, tx.Status)
+	assert.Equal(t, "100", env.available())
+	require.Len(t, env.ledger.journals, 1)
+	assert.Equal(t, model.LedgerBizDeposit, env.ledger.journals[0].BizType)
+	assert.Len(t, env.txs.txs, 2)
+}
+
+func TestWatcher_Reorg(t *testing.T) {
+	env := newTestEnv()
+	address, _, err := NewAddressAllocator(context.Background(), env.svcCtx).Allocate(1, "USDT", "ERC20")
+	require.NoError(t, err)
+	watcher := NewWatcher(context.Background(), env.svcCtx)
+
+	env.simulated.Deposit(address.Address, "USDT", "100")
+	env.simulated.Mine(3)
+	require.NoError(t, watcher.RunOnce())
+	credited := env.txs.txs[0]
+	assert.Equal(t, model.AssetTransactionStatusSuccess, credited.Status)
+	assert.Equal(t, "100", env.available())
+
+	env.simulated.Deposit(address.Address, "USDT", "20")
+	env.simulated.Mine(1)
+	require.NoError(t, watcher.RunOnce())
+	pending := env.txs.txs[1]
+	assert.Equal(t, model.AssetTransactionStatusPending, pending.Status)
+
+	// 重组移除两笔转账：未确认的取消，已入账的扣回
+	env.simulated.Reorg(4)
+	env.simulated.Mine(4)
+	require.NoError(t, watcher.RunOnce())
+	assert.Equal(t, model.AssetTransactionStatusCancelled, pending.Status)
+	assert.Equal(t, model.AssetTransactionStatusReverted, credited.Status)
+	assert.Equal(t, "0", env.available())
+	require.Len(t, env.ledger.journals, 2)
+	last := env.ledger.journals[1]
+	assert.Equal(t, uint64(1), last.Entries[0].UserID)
+	assert.Equal(t, model.LedgerAccountAvailable, last.Entries[0].Account)
+	assert.Equal(t, model.LedgerDirectionDebit, last.Entries[0].Direction)
+}
+
+func TestWatcher_ReorgConfirmationsDropped(t *testing.T) {
+	env := newTestEnv()
+	address, _, err := NewAddressAllocator(context.Background(), env.svcCtx).Allocate(1, "USDT", "ERC20")
+	require.NoError(t, err)
+	watcher := NewWatcher(context.Background(), env.svcCtx)
+
+	env.simulated.Deposit(address.Address, "USDT", "100")
+	env.simulated.Mine(3)
+	require.NoError(t, watcher.RunOnce())
+	tx := env.txs.txs[0]
+	assert.Equal(t, model.AssetTransactionStatusSuccess, tx.Status)
+	assert.Equal(t, "100", env.available())
+
+	// 重组后转账被重新打包，仍在链上但确认数不足：扣回入账，回到待确认
+	env.simulated.ReorgKeepDeposits(3)
+	env.simulated.Mine(1)
+	require.NoError(t, watcher.RunOnce())
+	assert.Equal(t, model.AssetTransactionStatusPending, tx.Status)
+	assert.Equal(t, int64(1), tx.Confirmations)
+	assert.Equal(t, "0", env.available())
+	require.Len(t, env.ledger.journals, 2)
+	assert.Equal(t, model.LedgerAccountAvailable, env.ledger.journals[1].Entries[0].Account)
+	assert.Equal(t, model.LedgerDirectionDebit, env.ledger.journals[1].Entries[0].Direction)
+
+	// 重新达到确认数后再次入账
+	env.simulated.Mine(2)
+	require.NoError(t, watcher.RunOnce())
+	assert.Equal(t, model.AssetTransactionStatusSuccess, tx.Status)
+	assert.Equal(t, "100", env.available())
+	assert.Len(t, env.ledger.journals, 3)
+}
@@ -0,0 +1,53 @@
+package kyc
+
+import (
+	"strings"
+	"time"
+
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+)
+
+// ConvertSubmission 将认证申请转换为响应格式，证件文件列表无法解析时返回错误
+func ConvertSubmission(s *model.KycSubmission) (*types.KycSubmission, error) {
+	docs, err := s.DocumentList()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &types.KycSubmission{
+		ID:             s.ID,
+		UserID:         s.UserID,
+		Level:          s.Level,
+		FullName:       s.FullName,
+		Country:        s.Country,
+		DocumentType:   s.DocumentType,
+		DocumentNumber: s.DocumentNumber,
+		Documents:      make([]types.KycDocument, 0, len(docs)),
+		Status:         s.Status,
+		ReviewerID:     s.ReviewerID,
+		ReviewReason:   s.ReviewReason,
+		CreatedAt:      s.CreatedAt.Format(time.RFC3339),
+	}
+	if s.ReviewedAt.Valid {
+		resp.ReviewedAt = s.ReviewedAt.Time.Format(time.RFC3339)
+	}
+	for i, doc := range docs {
+		resp.Documents = append(resp.Documents, types.KycDocument{
+			Index:       int64(i),
+			FileName:    doc.FileName,
+			ContentType: doc.ContentType,
+			Size:        doc.Size,
+		})
+	}
+	return resp, nil
+}
+
+// maskDocumentNumber 只保留证件号码末4位
+func maskDocumentNumber(number string) string {
+	runes := []rune(number)
+	if len(runes) <= 4 {
+		return strings.Repeat("*", len(runes))
+	}
+	return strings.Repeat("*", len(runes)-4) + string(runes[len(runes)-4:])
+}
@@ -0,0 +1,61 @@
+package kyc
+
+import (
+	"context"
+	"errors"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetKycStatusLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetKycStatusLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetKycStatusLogic {
+	return &GetKycStatusLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetKycStatus 查询当前认证等级和最近一次提交的申请，证件号码只显示末4位
+func (l *GetKycStatusLogic) GetKycStatus() (resp *types.KycStatusResponse, err error) {
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	user, err := l.svcCtx.UserModel.FindOne(l.ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrUserNotFound
+		}
+		l.Errorf("Failed to find user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+	resp = &types.KycStatusResponse{Level: user.VerificationLevel}
+
+	latest, err := l.svcCtx.KycSubmissionModel.FindLatestByUserID(l.ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return resp, nil
+		}
+		l.Errorf("Failed to find kyc submission of user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+	resp.Submission, err = ConvertSubmission(latest)
+	if err != nil {
+		l.Errorf("Invalid documents of kyc submission %d: %v", latest.ID, err)
+		return nil, model.ErrInternalServer
+	}
+	resp.Submission.DocumentNumber = maskDocumentNumber(resp.Submission.DocumentNumber)
+	return resp, nil
+}
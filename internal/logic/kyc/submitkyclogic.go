@@ -0,0 +1,216 @@
+package kyc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/google/uuid"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 允许上传的证件文件类型，按文件内容识别，不信任客户端声明的类型
+var allowedContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"application/pdf": true,
+}
+
+var countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+type SubmitKycLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSubmitKycLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SubmitKycLogic {
+	return &SubmitKycLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// SubmitKyc 提交身份认证申请，只能申请比当前高一级的认证等级，同时只能有一个待审核的申请
+// 证件文件先写入文件存储再创建申请记录，创建失败时删除已写入的文件
+func (l *SubmitKycLogic) SubmitKyc(req *types.SubmitKycRequest, files []*multipart.FileHeader) (resp *types.KycSubmission, err error) {
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	user, err := l.svcCtx.UserModel.FindOne(l.ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrUserNotFound
+		}
+		l.Errorf("Failed to find user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+	if req.Level != user.VerificationLevel+1 || req.Level > model.UserVerificationAdvanced {
+		return nil, model.ErrInvalidVerificationLevel
+	}
+	if err := l.validateRequest(req); err != nil {
+		return nil, err
+	}
+	if err := l.validateFiles(files); err != nil {
+		return nil, err
+	}
+
+	// 提前检查待审核的申请，避免写入无用的文件；并发提交由数据库唯一索引保证
+	latest, err := l.svcCtx.KycSubmissionModel.FindLatestByUserID(l.ctx, userID)
+	if err != nil && !errors.Is(err, model.ErrNotFound) {
+		l.Errorf("Failed to find kyc submission of user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+	if latest != nil && latest.Status == model.KycStatusPending {
+		return nil, model.ErrKycSubmissionPending
+	}
+
+	docs, err := l.storeFiles(userID, files)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	submission := &model.KycSubmission{
+		UserID:         userID,
+		Level:          req.Level,
+		FullName:       req.FullName,
+		Country:        req.Country,
+		DocumentType:   req.DocumentType,
+		DocumentNumber: req.DocumentNumber,
+		Status:         model.KycStatusPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := submission.SetDocuments(docs); err != nil {
+		l.Errorf("Failed to encode kyc documents for user %d: %v", userID, err)
+		l.deleteFiles(docs)
+		return nil, model.ErrInternalServer
+	}
+	submission.ID, err = l.svcCtx.KycSubmissionModel.Create(l.ctx, submission)
+	if err != nil {
+		l.deleteFiles(docs)
+		if errors.Is(err, model.ErrKycSubmissionPending) {
+			return nil, err
+		}
+		l.Errorf("Failed to create kyc submission for user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	l.Infof("User %d submitted kyc submission %d for level %d with %d documents", userID, submission.ID, req.Level, len(docs))
+	resp, err = ConvertSubmission(submission)
+	if err != nil {
+		l.Errorf("Invalid documents of kyc submission %d: %v", submission.ID, err)
+		return nil, model.ErrInternalServer
+	}
+	resp.DocumentNumber = maskDocumentNumber(resp.DocumentNumber)
+	return resp, nil
+}
+
+// validateRequest 校验并规范化申请资料
+func (l *SubmitKycLogic) validateRequest(req *types.SubmitKycRequest) error {
+	req.FullName = strings.TrimSpace(req.FullName)
+	req.Country = strings.ToUpper(strings.TrimSpace(req.Country))
+	req.DocumentType = strings.TrimSpace(req.DocumentType)
+	req.DocumentNumber = strings.TrimSpace(req.DocumentNumber)
+
+	if req.FullName == "" || utf8.RuneCountInString(req.FullName) > 100 {
+		return model.ErrInvalidParams
+	}
+	if !countryPattern.MatchString(req.Country) {
+		return model.ErrInvalidParams
+	}
+	if !model.IsValidKycDocumentType(req.DocumentType) {
+		return model.ErrInvalidParams
+	}
+	if req.DocumentNumber == "" || len(req.DocumentNumber) > 64 {
+		return model.ErrInvalidParams
+	}
+	return nil
+}
+
+// validateFiles 校验证件文件数量和大小
+func (l *SubmitKycLogic) validateFiles(files []*multipart.FileHeader) error {
+	cfg := l.svcCtx.Config.Kyc
+	if len(files) == 0 || len(files) > cfg.MaxFiles {
+		return model.ErrInvalidKycDocument
+	}
+	for _, file := range files {
+		if file.Size <= 0 || file.Size > cfg.MaxFileSize {
+			return model.ErrInvalidKycDocument
+		}
+	}
+	return nil
+}
+
+// storeFiles 识别文件类型并写入文件存储，任一文件失败时删除已写入的文件
+func (l *SubmitKycLogic) storeFiles(userID uint64, files []*multipart.FileHeader) ([]model.KycDocument, error) {
+	prefix := fmt.Sprintf("kyc/%d/%s", userID, strings.ReplaceAll(uuid.New().String(), "-", ""))
+	docs := make([]model.KycDocument, 0, len(files))
+	for i, file := range files {
+		doc, err := l.storeFile(fmt.Sprintf("%s/%d", prefix, i), file)
+		if err != nil {
+			l.deleteFiles(docs)
+			return nil, err
+		}
+		docs = append(docs, *doc)
+	}
+	return docs, nil
+}
+
+func (l *SubmitKycLogic) storeFile(key string, file *multipart.FileHeader) (*model.KycDocument, error) {
+	f, err := file.Open()
+	if err != nil {
+		l.Errorf("Failed to open uploaded file %q: %v", file.Filename, err)
+		return nil, model.ErrInvalidKycDocument
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, model.ErrInvalidKycDocument
+	}
+	contentType := http.DetectContentType(head[:n])
+	if !allowedContentTypes[contentType] {
+		return nil, model.ErrInvalidKycDocument
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, model.ErrInternalServer
+	}
+
+	if err := l.svcCtx.KycDocuments.Put(l.ctx, key, f); err != nil {
+		l.Errorf("Failed to store kyc document %s: %v", key, err)
+		return nil, model.ErrInternalServer
+	}
+	return &model.KycDocument{
+		Key:         key,
+		FileName:    file.Filename,
+		ContentType: contentType,
+		Size:        file.Size,
+	}, nil
+}
+
+// deleteFiles 删除已写入的文件，删除失败只记录日志
+func (l *SubmitKycLogic) deleteFiles(docs []model.KycDocument) {
+	for _, doc := range docs {
+		if err := l.svcCtx.KycDocuments.Delete(l.ctx, doc.Key); err != nil {
+			l.Errorf("Failed to delete kyc document %s: %v", doc.Key, err)
+		}
+	}
+}
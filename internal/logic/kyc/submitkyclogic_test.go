@@ -0,0 +1,156 @@
+package kyc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"crypto-exchange/internal/blobstore"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+type staticUserModel struct {
+	model.UserModel
+	user *model.User
+}
+
+func (m *staticUserModel) FindOne(ctx context.Context, id uint64) (*model.User, error) {
+	return m.user, nil
+}
+
+type memoryKycSubmissionModel struct {
+	model.KycSubmissionModel
+	submissions []*model.KycSubmission
+}
+
+func (m *memoryKycSubmissionModel) Create(ctx context.Context, data *model.KycSubmission) (uint64, error) {
+	for _, s := range m.submissions {
+		if s.UserID == data.UserID && s.Status == model.KycStatusPending {
+			return 0, model.ErrKycSubmissionPending
+		}
+	}
+	data.ID = uint64(len(m.submissions) + 1)
+	m.submissions = append(m.submissions, data)
+	return data.ID, nil
+}
+
+func (m *memoryKycSubmissionModel) FindLatestByUserID(ctx context.Context, userID uint64) (*model.KycSubmission, error) {
+	for i := len(m.submissions) - 1; i >= 0; i-- {
+		if m.submissions[i].UserID == userID {
+			return m.submissions[i], nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+// uploadFiles 构造multipart请求并解析出上传的文件
+func uploadFiles(t *testing.T, contents ...[]byte) []*multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for i, content := range contents {
+		part, err := writer.CreateFormFile("documents", "doc"+string(rune('a'+i)))
+		assert.NoError(t, err)
+		part.Write(content)
+	}
+	assert.NoError(t, writer.Close())
+
+	r := httptest.NewRequest("POST", "/api/v1/kyc/submissions", &body)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	assert.NoError(t, r.ParseMultipartForm(1<<20))
+	return r.MultipartForm.File["documents"]
+}
+
+func newKycTestContext(t *testing.T, user *model.User) (*svc.ServiceContext, *memoryKycSubmissionModel, blobstore.Store) {
+	store, err := blobstore.NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	submissions := &memoryKycSubmissionModel{}
+	svcCtx := &svc.ServiceContext{
+		UserModel:          &staticUserModel{user: user},
+		KycSubmissionModel: submissions,
+		KycDocuments:       store,
+	}
+	svcCtx.Config.Kyc.MaxFiles = 2
+	svcCtx.Config.Kyc.MaxFileSize = 1024
+	return svcCtx, submissions, store
+}
+
+func validKycRequest() *types.SubmitKycRequest {
+	return &types.SubmitKycRequest{
+		Level:          model.UserVerificationBasic,
+		FullName:       " Alice Zhang ",
+		Country:        "cn",
+		DocumentType:   model.KycDocumentPassport,
+		DocumentNumber: "E12345678",
+	}
+}
+
+func TestSubmitKyc(t *testing.T) {
+	svcCtx, submissions, store := newKycTestContext(t, &model.User{ID: 1, Status: model.UserStatusActive})
+	ctx := context.WithValue(context.Background(), "userId", json.Number("1"))
+
+	resp, err := NewSubmitKycLogic(ctx, svcCtx).SubmitKyc(validKycRequest(), uploadFiles(t, pngHeader, []byte("%PDF-1.4\n")))
+	assert.NoError(t, err)
+	assert.Equal(t, model.KycStatusPending, resp.Status)
+	assert.Equal(t, "Alice Zhang", resp.FullName)
+	assert.Equal(t, "CN", resp.Country)
+	assert.Equal(t, "*****5678", resp.DocumentNumber)
+	if assert.Len(t, resp.Documents, 2) {
+		assert.Equal(t, "image/png", resp.Documents[0].ContentType)
+		assert.Equal(t, "application/pdf", resp.Documents[1].ContentType)
+	}
+
+	// 证件文件写入文件存储，申请中保存完整的证件号码
+	docs, err := submissions.submissions[0].DocumentList()
+	assert.NoError(t, err)
+	r, err := store.Open(ctx, docs[0].Key)
+	if assert.NoError(t, err) {
+		data, _ := io.ReadAll(r)
+		r.Close()
+		assert.Equal(t, pngHeader, data)
+	}
+	assert.Equal(t, "E12345678", submissions.submissions[0].DocumentNumber)
+
+	// 待审核期间不能再次提交
+	_, err = NewSubmitKycLogic(ctx, svcCtx).SubmitKyc(validKycRequest(), uploadFiles(t, pngHeader))
+	assert.Equal(t, model.ErrKycSubmissionPending, err)
+}
+
+func TestSubmitKyc_Invalid(t *testing.T) {
+	svcCtx, submissions, _ := newKycTestContext(t, &model.User{ID: 1, Status: model.UserStatusActive})
+	ctx := context.WithValue(context.Background(), "userId", json.Number("1"))
+
+	// 只能申请比当前高一级的等级
+	req := validKycRequest()
+	req.Level = model.UserVerificationAdvanced
+	_, err := NewSubmitKycLogic(ctx, svcCtx).SubmitKyc(req, uploadFiles(t, pngHeader))
+	assert.Equal(t, model.ErrInvalidVerificationLevel, err)
+
+	req = validKycRequest()
+	req.DocumentType = "library_card"
+	_, err = NewSubmitKycLogic(ctx, svcCtx).SubmitKyc(req, uploadFiles(t, pngHeader))
+	assert.Equal(t, model.ErrInvalidParams, err)
+
+	// 文件类型按内容识别
+	_, err = NewSubmitKycLogic(ctx, svcCtx).SubmitKyc(validKycRequest(), uploadFiles(t, []byte("<html><body>hi</body></html>")))
+	assert.Equal(t, model.ErrInvalidKycDocument, err)
+
+	// 文件数量和大小限制
+	_, err = NewSubmitKycLogic(ctx, svcCtx).SubmitKyc(validKycRequest(), nil)
+	assert.Equal(t, model.ErrInvalidKycDocument, err)
+	_, err = NewSubmitKycLogic(ctx, svcCtx).SubmitKyc(validKycRequest(), uploadFiles(t, pngHeader, pngHeader, pngHeader))
+	assert.Equal(t, model.ErrInvalidKycDocument, err)
+	_, err = NewSubmitKycLogic(ctx, svcCtx).SubmitKyc(validKycRequest(), uploadFiles(t, append(pngHeader, make([]byte, 2048)...)))
+	assert.Equal(t, model.ErrInvalidKycDocument, err)
+
+	assert.Empty(t, submissions.submissions)
+}
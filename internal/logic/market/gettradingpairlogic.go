@@ -43,15 +43,20 @@ func (l *GetTradingPairLogic) GetTradingPair(symbol string) (resp *types.Trading
 
 	// 转换为响应格式
 	return &types.TradingPair{
-		ID:            tradingPair.ID,
-		Symbol:        tradingPair.Symbol,
-		BaseCurrency:  tradingPair.BaseCurrency,
-		QuoteCurrency: tradingPair.QuoteCurrency,
-		MinAmount:     tradingPair.MinAmount,
-		MaxAmount:     tradingPair.MaxAmount,
-		PriceScale:    tradingPair.PriceScale,
-		AmountScale:   tradingPair.AmountScale,
-		Status:        tradingPair.Status,
-		CreatedAt:     tradingPair.CreatedAt.Format("2006-01-02 15:04:05"),
+		ID:                   tradingPair.ID,
+		Symbol:               tradingPair.Symbol,
+		BaseCurrency:         tradingPair.BaseCurrency,
+		QuoteCurrency:        tradingPair.QuoteCurrency,
+		MinAmount:            tradingPair.MinAmount,
+		MaxAmount:            tradingPair.MaxAmount,
+		PriceScale:           tradingPair.PriceScale,
+		AmountScale:          tradingPair.AmountScale,
+		TickSize:             tradingPair.TickSize,
+		StepSize:             tradingPair.StepSize,
+		MinNotional:          tradingPair.MinNotional,
+		MaxOpenOrders:        tradingPair.MaxOpenOrders,
+		MinVerificationLevel: tradingPair.MinVerificationLevel,
+		Status:               tradingPair.Status,
+		CreatedAt:            tradingPair.CreatedAt.Format("2006-01-02 15:04:05"),
 	}, nil
 }
@@ -35,16 +35,21 @@ func (l *GetTradingPairsLogic) GetTradingPairs() (resp *types.TradingPairListRes
 	var pairs []types.TradingPair
 	for _, pair := range tradingPairs {
 		pairs = append(pairs, types.TradingPair{
-			ID:            pair.ID,
-			Symbol:        pair.Symbol,
-			BaseCurrency:  pair.BaseCurrency,
-			QuoteCurrency: pair.QuoteCurrency,
-			MinAmount:     pair.MinAmount,
-			MaxAmount:     pair.MaxAmount,
-			PriceScale:    pair.PriceScale,
-			AmountScale:   pair.AmountScale,
-			Status:        pair.Status,
-			CreatedAt:     pair.CreatedAt.Format("2006-01-02 15:04:05"),
+			ID:                   pair.ID,
+			Symbol:               pair.Symbol,
+			BaseCurrency:         pair.BaseCurrency,
+			QuoteCurrency:        pair.QuoteCurrency,
+			MinAmount:            pair.MinAmount,
+			MaxAmount:            pair.MaxAmount,
+			PriceScale:           pair.PriceScale,
+			AmountScale:          pair.AmountScale,
+			TickSize:             pair.TickSize,
+			StepSize:             pair.StepSize,
+			MinNotional:          pair.MinNotional,
+			MaxOpenOrders:        pair.MaxOpenOrders,
+			MinVerificationLevel: pair.MinVerificationLevel,
+			Status:               pair.Status,
+			CreatedAt:            pair.CreatedAt.Format("2006-01-02 15:04:05"),
 		})
 	}
 
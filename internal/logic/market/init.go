@@ -2,10 +2,12 @@ package market
 
 import (
 	"context"
+	"time"
 
 	"crypto-exchange/internal/svc"
 
 	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/threading"
 )
 
 // InitializeTradingPairs 初始化交易对数据
@@ -29,4 +31,36 @@ func InitializeTradingPairs(ctx context.Context, svcCtx *svc.ServiceContext) err
 func GetTradingPairStats(ctx context.Context, svcCtx *svc.ServiceContext) (map[string]interface{}, error) {
 	manager := NewTradingPairManager(ctx, svcCtx)
 	return manager.GetTradingPairStats()
-}
\ No newline at end of file
+}
+
+// StartTradingPairStatusScheduler 启动交易对状态排期任务
+// 启动时先将数据库中的交易对状态同步到撮合引擎，之后按固定间隔执行到期的状态变更，返回停止函数
+func StartTradingPairStatusScheduler(svcCtx *svc.ServiceContext, interval time.Duration) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	manager := NewTradingPairManager(ctx, svcCtx)
+
+	if err := manager.SyncEngineTradingStatus(); err != nil {
+		logx.Errorf("Failed to sync trading pair status to matching engine: %v", err)
+	}
+
+	threading.GoSafe(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				executed, err := manager.RunDueStatusChanges()
+				if err != nil {
+					logx.Errorf("Failed to run due trading pair status changes: %v", err)
+				} else if executed > 0 {
+					logx.Infof("Executed %d scheduled trading pair status changes", executed)
+				}
+			}
+		}
+	})
+
+	return cancel
+}
@@ -0,0 +1,372 @@
+package market
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"crypto-exchange/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// allowedStatusTransitions 交易对状态迁移规则：当前状态 -> 允许迁移到的目标状态
+// 已下架为终态，任何状态都不能再回到预上线
+var allowedStatusTransitions = map[int64][]int64{
+	model.TradingPairStatusPreTrading: {
+		model.TradingPairStatusTrading,
+		model.TradingPairStatusPostOnly,
+		model.TradingPairStatusHalted,
+		model.TradingPairStatusDelisted,
+	},
+	model.TradingPairStatusTrading: {
+		model.TradingPairStatusHalted,
+		model.TradingPairStatusCancelOnly,
+		model.TradingPairStatusPostOnly,
+		model.TradingPairStatusDelisted,
+	},
+	model.TradingPairStatusHalted: {
+		model.TradingPairStatusTrading,
+		model.TradingPairStatusCancelOnly,
+		model.TradingPairStatusPostOnly,
+		model.TradingPairStatusDelisted,
+	},
+	model.TradingPairStatusCancelOnly: {
+		model.TradingPairStatusTrading,
+		model.TradingPairStatusHalted,
+		model.TradingPairStatusPostOnly,
+		model.TradingPairStatusDelisted,
+	},
+	model.TradingPairStatusPostOnly: {
+		model.TradingPairStatusTrading,
+		model.TradingPairStatusHalted,
+		model.TradingPairStatusCancelOnly,
+		model.TradingPairStatusDelisted,
+	},
+}
+
+// ValidateStatusTransition 验证交易对状态迁移是否合法
+func (m *TradingPairManager) ValidateStatusTransition(fromStatus, toStatus int64) error {
+	for _, allowed := range allowedStatusTransitions[fromStatus] {
+		if allowed == toStatus {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s -> %s", model.ErrInvalidStatusTransition,
+		model.TradingPairStatusText(fromStatus), model.TradingPairStatusText(toStatus))
+}
+
+// ScheduleStatusChange 排期交易对状态变更
+// effectiveAt为零值或早于当前时间时立即执行，否则写入待执行记录，由排期任务到期执行
+func (m *TradingPairManager) ScheduleStatusChange(symbol string, toStatus int64, effectiveAt time.Time, operatorID uint64, reason string) (*model.TradingPairStatusChange, error) {
+	if err := m.validator.ValidateStatus(toStatus); err != nil {
+		return nil, err
+	}
+
+	pair, err := m.GetTradingPairBySymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	// 提前校验迁移规则，避免写入注定失败的排期
+	if err := m.ValidateStatusTransition(pair.Status, toStatus); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	change := &model.TradingPairStatusChange{
+		Symbol:      pair.Symbol,
+		FromStatus:  pair.Status,
+		ToStatus:    toStatus,
+		EffectiveAt: effectiveAt,
+		State:       model.StatusChangeStatePending,
+		OperatorID:  operatorID,
+		Reason:      reason,
+		CreatedAt:   now,
+	}
+
+	if effectiveAt.IsZero() || !effectiveAt.After(now) {
+		change.EffectiveAt = now
+		if err := m.executeStatusChange(pair, change); err != nil {
+			return nil, err
+		}
+		return change, nil
+	}
+
+	result, err := m.svcCtx.TradingPairStatusChangeModel.Insert(m.ctx, change)
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule status change: %w", err)
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		change.ID = uint64(id)
+	}
+
+	m.Logger.Infof("Scheduled trading pair %s status change to %s at %s by operator %d",
+		symbol, model.TradingPairStatusText(toStatus), effectiveAt.Format(time.RFC3339), operatorID)
+	return change, nil
+}
+
+// CancelScheduledStatusChange 取消尚未执行的状态变更排期
+func (m *TradingPairManager) CancelScheduledStatusChange(id uint64, operatorID uint64) error {
+	change, err := m.svcCtx.TradingPairStatusChangeModel.FindOne(m.ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return fmt.Errorf("status change not found: %d", id)
+		}
+		return fmt.Errorf("failed to get status change: %w", err)
+	}
+
+	if change.State != model.StatusChangeStatePending {
+		return fmt.Errorf("status change %d is no longer pending", id)
+	}
+
+	remark := fmt.Sprintf("canceled by operator %d", operatorID)
+	if err := m.svcCtx.TradingPairStatusChangeModel.UpdateState(m.ctx, id, model.StatusChangeStateCanceled, change.FromStatus, remark); err != nil {
+		return fmt.Errorf("failed to cancel status change: %w", err)
+	}
+
+	m.Logger.Infof("Canceled scheduled status change %d of %s by operator %d", id, change.Symbol, operatorID)
+	return nil
+}
+
+// RunDueStatusChanges 执行所有已到生效时间的状态变更排期，返回成功执行的数量
+// 单条排期执行失败时记录为执行失败并继续处理后续排期
+func (m *TradingPairManager) RunDueStatusChanges() (int, error) {
+	changes, err := m.svcCtx.TradingPairStatusChangeModel.FindDuePending(m.ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get due status changes: %w", err)
+	}
+
+	executed := 0
+	for _, change := range changes {
+		pair, err := m.svcCtx.TradingPairModel.FindBySymbol(m.ctx, change.Symbol)
+		if err == nil {
+			err = m.executeStatusChange(pair, change)
+		}
+
+		if err != nil {
+			m.Logger.Errorf("Failed to execute status change %d of %s: %v", change.ID, change.Symbol, err)
+			fromStatus := change.FromStatus
+			if pair != nil {
+				fromStatus = pair.Status
+			}
+			if updateErr := m.svcCtx.TradingPairStatusChangeModel.UpdateState(m.ctx, change.ID, model.StatusChangeStateFailed, fromStatus, err.Error()); updateErr != nil {
+				m.Logger.Errorf("Failed to mark status change %d as failed: %v", change.ID, updateErr)
+			}
+			continue
+		}
+
+		executed++
+	}
+
+	return executed, nil
+}
+
+// GetStatusChanges 分页查询交易对状态变更记录
+func (m *TradingPairManager) GetStatusChanges(symbol string, page, size int64) ([]*model.TradingPairStatusChange, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = 20
+	}
+	if size > 100 {
+		size = 100
+	}
+
+	total, err := m.svcCtx.TradingPairStatusChangeModel.CountBySymbol(m.ctx, symbol)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count status changes: %w", err)
+	}
+
+	changes, err := m.svcCtx.TradingPairStatusChangeModel.FindBySymbol(m.ctx, symbol, size, (page-1)*size)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get status changes: %w", err)
+	}
+
+	return changes, total, nil
+}
+
+// SyncEngineTradingStatus 将数据库中的交易对状态同步到撮合引擎，用于服务启动时恢复引擎状态
+func (m *TradingPairManager) SyncEngineTradingStatus() error {
+	pairs, err := m.svcCtx.TradingPairModel.FindAll(m.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get trading pairs: %w", err)
+	}
+
+	for _, pair := range pairs {
+		m.svcCtx.MatchingEngine.SetTradingStatus(pair.Symbol, pair.Status)
+	}
+
+	return nil
+}
+
+// executeStatusChange 执行状态变更：更新交易对、写入审计记录，下架时批量撤单并解冻资产
+func (m *TradingPairManager) executeStatusChange(pair *model.TradingPair, change *model.TradingPairStatusChange) error {
+	fromStatus := pair.Status
+	if err := m.ValidateStatusTransition(fromStatus, change.ToStatus); err != nil {
+		return err
+	}
+
+	// 下架前先让撮合引擎停止接收新订单，避免撤单过程中产生新的成交
+	delisting := change.ToStatus == model.TradingPairStatusDelisted
+	if delisting {
+		m.svcCtx.MatchingEngine.SetTradingStatus(pair.Symbol, model.TradingPairStatusDelisted)
+	}
+
+	remark := ""
+	err := m.svcCtx.TradingPairStatusChangeModel.Trans(m.ctx, func(ctx context.Context, session sqlx.Session) error {
+		pairs := m.svcCtx.TradingPairModel.WithSession(session)
+		changes := m.svcCtx.TradingPairStatusChangeModel.WithSession(session)
+
+		if delisting {
+			canceled, err := m.cancelOpenOrders(ctx, session, pair)
+			if err != nil {
+				return err
+			}
+			remark = fmt.Sprintf("canceled %d open orders", canceled)
+		}
+
+		pair.Status = change.ToStatus
+		if err := pairs.Update(ctx, pair); err != nil {
+			return fmt.Errorf("failed to update trading pair status: %w", err)
+		}
+
+		// 立即执行的变更直接写入已执行记录，排期变更更新原记录
+		if change.ID == 0 {
+			change.FromStatus = fromStatus
+			change.State = model.StatusChangeStateExecuted
+			change.Remark = remark
+			change.ExecutedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			if _, err := changes.Insert(ctx, change); err != nil {
+				return fmt.Errorf("failed to record status change: %w", err)
+			}
+			return nil
+		}
+
+		return changes.UpdateState(ctx, change.ID, model.StatusChangeStateExecuted, fromStatus, remark)
+	})
+	if err != nil {
+		pair.Status = fromStatus
+		m.svcCtx.MatchingEngine.SetTradingStatus(pair.Symbol, fromStatus)
+		return err
+	}
+
+	m.svcCtx.MatchingEngine.SetTradingStatus(pair.Symbol, change.ToStatus)
+	if delisting {
+		m.svcCtx.MatchingEngine.CancelAllOrders(pair.Symbol)
+	}
+
+	m.Logger.Infof("Trading pair %s status changed from %s to %s by operator %d, reason: %s",
+		pair.Symbol, model.TradingPairStatusText(fromStatus), model.TradingPairStatusText(change.ToStatus), change.OperatorID, change.Reason)
+	return nil
+}
+
+// unfreezeKey 批量解冻时的用户-币种聚合键
+type unfreezeKey struct {
+	userID   uint64
+	currency string
+}
+
+// cancelOpenOrders 批量撤销交易对下的所有挂单，并按用户和币种聚合后解冻资产
+func (m *TradingPairManager) cancelOpenOrders(ctx context.Context, session sqlx.Session, pair *model.TradingPair) (int64, error) {
+	orders := m.svcCtx.OrderModel.WithSession(session)
+	balances := m.svcCtx.BalanceModel.WithSession(session)
+	ledger := m.svcCtx.LedgerEntryModel.WithSession(session)
+
+	openOrders, err := orders.FindOpenBySymbol(ctx, pair.Symbol)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	// 聚合每个用户每个币种需要解冻的数量
+	unfreezeAmounts := make(map[unfreezeKey]decimal.Decimal)
+	journals := make([]*model.LedgerJournal, 0, len(openOrders))
+	for _, order := range openOrders {
+		currency, amount, err := RemainingFrozenAmount(order, pair)
+		if err != nil {
+			return 0, fmt.Errorf("failed to calculate frozen amount of order %d: %w", order.ID, err)
+		}
+		if amount.IsPositive() {
+			key := unfreezeKey{userID: order.UserID, currency: currency}
+			unfreezeAmounts[key] = unfreezeAmounts[key].Add(amount)
+
+			// 记账：每个订单单独生成解冻凭证，便于按订单追溯
+			journal := model.NewLedgerJournal(model.LedgerBizUnfreeze, strconv.FormatUint(order.ID, 10))
+			journal.Transfer(currency, amount.String(), model.UserFrozen(order.UserID), model.UserAvailable(order.UserID), "pair status change")
+			journals = append(journals, journal)
+		}
+	}
+
+	canceled, err := orders.CancelOpenBySymbol(ctx, pair.Symbol)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cancel open orders: %w", err)
+	}
+
+	// 按用户ID和币种排序，保证批量解冻的加锁顺序一致
+	keys := make([]unfreezeKey, 0, len(unfreezeAmounts))
+	for key := range unfreezeAmounts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].userID != keys[j].userID {
+			return keys[i].userID < keys[j].userID
+		}
+		return keys[i].currency < keys[j].currency
+	})
+
+	for _, key := range keys {
+		amount := unfreezeAmounts[key].String()
+		if err := balances.UnfreezeBalance(ctx, key.userID, key.currency, amount); err != nil {
+			return 0, fmt.Errorf("failed to unfreeze %s %s for user %d: %w", amount, key.currency, key.userID, err)
+		}
+	}
+
+	for _, journal := range journals {
+		if err := ledger.InsertJournal(ctx, journal); err != nil {
+			return 0, fmt.Errorf("failed to record ledger for order %s: %w", journal.BizID, err)
+		}
+	}
+
+	m.Logger.Infof("Canceled %d open orders of %s and unfroze balances of %d accounts", canceled, pair.Symbol, len(keys))
+	return canceled, nil
+}
+
+// RemainingFrozenAmount 计算订单剩余未成交部分仍处于冻结状态的币种和数量
+func RemainingFrozenAmount(order *model.Order, pair *model.TradingPair) (string, decimal.Decimal, error) {
+	orderAmount, err := decimal.NewFromString(order.Amount)
+	if err != nil {
+		return "", decimal.Zero, model.ErrInvalidAmount
+	}
+
+	filledAmount, err := decimal.NewFromString(order.FilledAmount)
+	if err != nil {
+		return "", decimal.Zero, model.ErrInvalidAmount
+	}
+
+	remaining := orderAmount.Sub(filledAmount)
+	if remaining.LessThanOrEqual(decimal.Zero) {
+		return "", decimal.Zero, nil
+	}
+
+	if order.Side == 2 { // 卖单冻结基础币种
+		return pair.BaseCurrency, remaining, nil
+	}
+
+	// 买单冻结计价币种：限价单为剩余数量*价格，市价单Amount本身即计价币种数量
+	if order.Type == 1 {
+		price, err := decimal.NewFromString(order.Price)
+		if err != nil {
+			return "", decimal.Zero, errors.New("invalid price format")
+		}
+		return pair.QuoteCurrency, remaining.Mul(price), nil
+	}
+
+	return pair.QuoteCurrency, remaining, nil
+}
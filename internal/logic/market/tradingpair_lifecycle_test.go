@@ -0,0 +1,346 @@
+package market
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/matching"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// MockTradingPairStatusChangeModel 模拟交易对状态变更记录模型
+type MockTradingPairStatusChangeModel struct {
+	mock.Mock
+}
+
+func (m *MockTradingPairStatusChangeModel) Insert(ctx context.Context, data *model.TradingPairStatusChange) (sql.Result, error) {
+	args := m.Called(ctx, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *MockTradingPairStatusChangeModel) FindOne(ctx context.Context, id uint64) (*model.TradingPairStatusChange, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TradingPairStatusChange), args.Error(1)
+}
+
+func (m *MockTradingPairStatusChangeModel) Update(ctx context.Context, data *model.TradingPairStatusChange) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockTradingPairStatusChangeModel) Delete(ctx context.Context, id uint64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTradingPairStatusChangeModel) FindBySymbol(ctx context.Context, symbol string, limit, offset int64) ([]*model.TradingPairStatusChange, error) {
+	args := m.Called(ctx, symbol, limit, offset)
+	return args.Get(0).([]*model.TradingPairStatusChange), args.Error(1)
+}
+
+func (m *MockTradingPairStatusChangeModel) CountBySymbol(ctx context.Context, symbol string) (int64, error) {
+	args := m.Called(ctx, symbol)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTradingPairStatusChangeModel) FindDuePending(ctx context.Context, now time.Time) ([]*model.TradingPairStatusChange, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]*model.TradingPairStatusChange), args.Error(1)
+}
+
+func (m *MockTradingPairStatusChangeModel) UpdateState(ctx context.Context, id uint64, state int64, fromStatus int64, remark string) error {
+	args := m.Called(ctx, id, state, fromStatus, remark)
+	return args.Error(0)
+}
+
+func (m *MockTradingPairStatusChangeModel) Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	return fn(ctx, nil)
+}
+
+func (m *MockTradingPairStatusChangeModel) WithSession(session sqlx.Session) model.TradingPairStatusChangeModel {
+	return m
+}
+
+// MockLifecycleOrderModel 模拟订单模型，只实现下架批量撤单用到的方法
+type MockLifecycleOrderModel struct {
+	model.OrderModel
+	mock.Mock
+}
+
+func (m *MockLifecycleOrderModel) FindOpenBySymbol(ctx context.Context, symbol string) ([]*model.Order, error) {
+	args := m.Called(ctx, symbol)
+	return args.Get(0).([]*model.Order), args.Error(1)
+}
+
+func (m *MockLifecycleOrderModel) CancelOpenBySymbol(ctx context.Context, symbol string) (int64, error) {
+	args := m.Called(ctx, symbol)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockLifecycleOrderModel) WithSession(session sqlx.Session) model.OrderModel {
+	return m
+}
+
+// MockLifecycleBalanceModel 模拟余额模型，只实现解冻方法
+type MockLifecycleBalanceModel struct {
+	model.BalanceModel
+	mock.Mock
+}
+
+func (m *MockLifecycleBalanceModel) UnfreezeBalance(ctx context.Context, userID uint64, currency string, amount string) error {
+	args := m.Called(ctx, userID, currency, amount)
+	return args.Error(0)
+}
+
+func (m *MockLifecycleBalanceModel) WithSession(session sqlx.Session) model.BalanceModel {
+	return m
+}
+
+// MockLifecycleLedgerEntryModel 模拟账本模型，只实现写入凭证方法
+type MockLifecycleLedgerEntryModel struct {
+	model.LedgerEntryModel
+	mock.Mock
+}
+
+func (m *MockLifecycleLedgerEntryModel) InsertJournal(ctx context.Context, journal *model.LedgerJournal) error {
+	args := m.Called(ctx, journal)
+	return args.Error(0)
+}
+
+func (m *MockLifecycleLedgerEntryModel) WithSession(session sqlx.Session) model.LedgerEntryModel {
+	return m
+}
+
+func TestTradingPairManager_ValidateStatusTransition(t *testing.T) {
+	manager := NewTradingPairManager(context.Background(), createTestServiceContext())
+
+	tests := []struct {
+		name    string
+		from    int64
+		to      int64
+		wantErr bool
+	}{
+		{"pre-trading to trading", model.TradingPairStatusPreTrading, model.TradingPairStatusTrading, false},
+		{"trading to cancel-only", model.TradingPairStatusTrading, model.TradingPairStatusCancelOnly, false},
+		{"halted to post-only", model.TradingPairStatusHalted, model.TradingPairStatusPostOnly, false},
+		{"post-only to delisted", model.TradingPairStatusPostOnly, model.TradingPairStatusDelisted, false},
+		{"trading to trading", model.TradingPairStatusTrading, model.TradingPairStatusTrading, true},
+		{"trading back to pre-trading", model.TradingPairStatusTrading, model.TradingPairStatusPreTrading, true},
+		{"delisted is terminal", model.TradingPairStatusDelisted, model.TradingPairStatusTrading, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := manager.ValidateStatusTransition(tt.from, tt.to)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, model.ErrInvalidStatusTransition)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTradingPairManager_ScheduleStatusChange_Future(t *testing.T) {
+	ctx := context.Background()
+	svcCtx := createTestServiceContext()
+	mockPairModel := &MockTradingPairModel{}
+	mockChangeModel := &MockTradingPairStatusChangeModel{}
+	svcCtx.TradingPairModel = mockPairModel
+	svcCtx.TradingPairStatusChangeModel = mockChangeModel
+
+	manager := NewTradingPairManager(ctx, svcCtx)
+
+	pair := &model.TradingPair{ID: 1, Symbol: "BTC/USDT", Status: model.TradingPairStatusPreTrading}
+	effectiveAt := time.Now().Add(time.Hour)
+
+	mockPairModel.On("FindBySymbol", ctx, "BTC/USDT").Return(pair, nil)
+	mockChangeModel.On("Insert", ctx, mock.MatchedBy(func(c *model.TradingPairStatusChange) bool {
+		return c.State == model.StatusChangeStatePending &&
+			c.FromStatus == model.TradingPairStatusPreTrading &&
+			c.ToStatus == model.TradingPairStatusTrading &&
+			c.EffectiveAt.Equal(effectiveAt) &&
+			c.OperatorID == 7
+	})).Return(&MockSqlResult{lastInsertId: 11}, nil)
+
+	change, err := manager.ScheduleStatusChange("BTC/USDT", model.TradingPairStatusTrading, effectiveAt, 7, "listing")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), change.ID)
+	// 排期未到期，交易对状态不变，也不会更新交易对
+	assert.Equal(t, model.TradingPairStatusPreTrading, pair.Status)
+	mockPairModel.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockChangeModel.AssertExpectations(t)
+}
+
+func TestTradingPairManager_ScheduleStatusChange_Delist(t *testing.T) {
+	ctx := context.Background()
+	svcCtx := createTestServiceContext()
+	mockPairModel := &MockTradingPairModel{}
+	mockChangeModel := &MockTradingPairStatusChangeModel{}
+	mockOrderModel := &MockLifecycleOrderModel{}
+	mockBalanceModel := &MockLifecycleBalanceModel{}
+	mockLedgerModel := &MockLifecycleLedgerEntryModel{}
+	engine := matching.NewMatchingEngine()
+	svcCtx.TradingPairModel = mockPairModel
+	svcCtx.TradingPairStatusChangeModel = mockChangeModel
+	svcCtx.OrderModel = mockOrderModel
+	svcCtx.BalanceModel = mockBalanceModel
+	svcCtx.LedgerEntryModel = mockLedgerModel
+	svcCtx.MatchingEngine = engine
+
+	manager := NewTradingPairManager(ctx, svcCtx)
+
+	pair := &model.TradingPair{
+		ID:            1,
+		Symbol:        "BTC/USDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		Status:        model.TradingPairStatusTrading,
+	}
+
+	// 撮合引擎中的挂单
+	restingOrder := &model.Order{ID: 1, UserID: 1, Symbol: "BTC/USDT", Type: 1, Side: 1, Price: "100", Amount: "2", FilledAmount: "0", Status: 1}
+	_, err := engine.ProcessOrder(restingOrder)
+	assert.NoError(t, err)
+
+	openOrders := []*model.Order{
+		{ID: 1, UserID: 1, Symbol: "BTC/USDT", Type: 1, Side: 1, Price: "100", Amount: "2", FilledAmount: "0", Status: 1},
+		{ID: 2, UserID: 1, Symbol: "BTC/USDT", Type: 1, Side: 1, Price: "200", Amount: "1", FilledAmount: "0.5", Status: 2},
+		{ID: 3, UserID: 2, Symbol: "BTC/USDT", Type: 1, Side: 2, Price: "300", Amount: "1.5", FilledAmount: "0", Status: 1},
+	}
+
+	mockPairModel.On("FindBySymbol", ctx, "BTC/USDT").Return(pair, nil)
+	mockOrderModel.On("FindOpenBySymbol", ctx, "BTC/USDT").Return(openOrders, nil)
+	mockOrderModel.On("CancelOpenBySymbol", ctx, "BTC/USDT").Return(int64(3), nil)
+	// 同一用户同一币种的冻结金额合并解冻：2*100 + 0.5*200 = 300
+	mockBalanceModel.On("UnfreezeBalance", ctx, uint64(1), "USDT", "300").Return(nil).Once()
+	mockBalanceModel.On("UnfreezeBalance", ctx, uint64(2), "BTC", "1.5").Return(nil).Once()
+	// 每个订单单独记账：冻结 -> 可用
+	for _, order := range openOrders {
+		orderID := strconv.FormatUint(order.ID, 10)
+		mockLedgerModel.On("InsertJournal", ctx, mock.MatchedBy(func(j *model.LedgerJournal) bool {
+			return j.BizType == model.LedgerBizUnfreeze && j.BizID == orderID && j.Validate() == nil
+		})).Return(nil).Once()
+	}
+	mockPairModel.On("Update", ctx, mock.MatchedBy(func(p *model.TradingPair) bool {
+		return p.Status == model.TradingPairStatusDelisted
+	})).Return(nil)
+	mockChangeModel.On("Insert", ctx, mock.MatchedBy(func(c *model.TradingPairStatusChange) bool {
+		return c.State == model.StatusChangeStateExecuted &&
+			c.FromStatus == model.TradingPairStatusTrading &&
+			c.Remark == "canceled 3 open orders"
+	})).Return(&MockSqlResult{lastInsertId: 1}, nil)
+
+	_, err = manager.ScheduleStatusChange("BTC/USDT", model.TradingPairStatusDelisted, time.Time{}, 7, "delist")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.TradingPairStatusDelisted, pair.Status)
+	assert.Equal(t, model.TradingPairStatusDelisted, engine.GetTradingStatus("BTC/USDT"))
+	assert.Empty(t, engine.GetOrderBook("BTC/USDT").Orders())
+	mockOrderModel.AssertExpectations(t)
+	mockBalanceModel.AssertExpectations(t)
+	mockLedgerModel.AssertExpectations(t)
+	mockChangeModel.AssertExpectations(t)
+}
+
+func TestTradingPairManager_ScheduleStatusChange_RollbackEngineStatus(t *testing.T) {
+	ctx := context.Background()
+	svcCtx := createTestServiceContext()
+	mockPairModel := &MockTradingPairModel{}
+	mockChangeModel := &MockTradingPairStatusChangeModel{}
+	mockOrderModel := &MockLifecycleOrderModel{}
+	engine := matching.NewMatchingEngine()
+	svcCtx.TradingPairModel = mockPairModel
+	svcCtx.TradingPairStatusChangeModel = mockChangeModel
+	svcCtx.OrderModel = mockOrderModel
+	svcCtx.BalanceModel = &MockLifecycleBalanceModel{}
+	svcCtx.LedgerEntryModel = &MockLifecycleLedgerEntryModel{}
+	svcCtx.MatchingEngine = engine
+
+	manager := NewTradingPairManager(ctx, svcCtx)
+
+	pair := &model.TradingPair{ID: 1, Symbol: "BTC/USDT", BaseCurrency: "BTC", QuoteCurrency: "USDT", Status: model.TradingPairStatusCancelOnly}
+	engine.SetTradingStatus("BTC/USDT", model.TradingPairStatusCancelOnly)
+
+	mockPairModel.On("FindBySymbol", ctx, "BTC/USDT").Return(pair, nil)
+	mockOrderModel.On("FindOpenBySymbol", ctx, "BTC/USDT").Return([]*model.Order{}, nil)
+	mockOrderModel.On("CancelOpenBySymbol", ctx, "BTC/USDT").Return(int64(0), errors.New("db down"))
+
+	_, err := manager.ScheduleStatusChange("BTC/USDT", model.TradingPairStatusDelisted, time.Time{}, 7, "delist")
+
+	assert.Error(t, err)
+	assert.Equal(t, model.TradingPairStatusCancelOnly, pair.Status)
+	assert.Equal(t, model.TradingPairStatusCancelOnly, engine.GetTradingStatus("BTC/USDT"))
+	mockPairModel.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestTradingPairManager_RunDueStatusChanges(t *testing.T) {
+	ctx := context.Background()
+	svcCtx := createTestServiceContext()
+	mockPairModel := &MockTradingPairModel{}
+	mockChangeModel := &MockTradingPairStatusChangeModel{}
+	engine := matching.NewMatchingEngine()
+	svcCtx.TradingPairModel = mockPairModel
+	svcCtx.TradingPairStatusChangeModel = mockChangeModel
+	svcCtx.MatchingEngine = engine
+
+	manager := NewTradingPairManager(ctx, svcCtx)
+
+	btcPair := &model.TradingPair{ID: 1, Symbol: "BTC/USDT", Status: model.TradingPairStatusPreTrading}
+	ethPair := &model.TradingPair{ID: 2, Symbol: "ETH/USDT", Status: model.TradingPairStatusDelisted}
+	changes := []*model.TradingPairStatusChange{
+		{ID: 1, Symbol: "BTC/USDT", FromStatus: model.TradingPairStatusPreTrading, ToStatus: model.TradingPairStatusTrading, State: model.StatusChangeStatePending},
+		{ID: 2, Symbol: "ETH/USDT", FromStatus: model.TradingPairStatusTrading, ToStatus: model.TradingPairStatusTrading, State: model.StatusChangeStatePending},
+	}
+
+	mockChangeModel.On("FindDuePending", ctx, mock.Anything).Return(changes, nil)
+	mockPairModel.On("FindBySymbol", ctx, "BTC/USDT").Return(btcPair, nil)
+	mockPairModel.On("FindBySymbol", ctx, "ETH/USDT").Return(ethPair, nil)
+	mockPairModel.On("Update", ctx, btcPair).Return(nil)
+	mockChangeModel.On("UpdateState", ctx, uint64(1), model.StatusChangeStateExecuted, model.TradingPairStatusPreTrading, "").Return(nil)
+	// 已下架的交易对不能再恢复交易，排期标记为执行失败
+	mockChangeModel.On("UpdateState", ctx, uint64(2), model.StatusChangeStateFailed, model.TradingPairStatusDelisted, mock.Anything).Return(nil)
+
+	executed, err := manager.RunDueStatusChanges()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, executed)
+	assert.Equal(t, model.TradingPairStatusTrading, engine.GetTradingStatus("BTC/USDT"))
+	mockChangeModel.AssertExpectations(t)
+}
+
+func TestTradingPairManager_CancelScheduledStatusChange(t *testing.T) {
+	ctx := context.Background()
+	svcCtx := createTestServiceContext()
+	mockChangeModel := &MockTradingPairStatusChangeModel{}
+	svcCtx.TradingPairStatusChangeModel = mockChangeModel
+
+	manager := NewTradingPairManager(ctx, svcCtx)
+
+	mockChangeModel.On("FindOne", ctx, uint64(1)).Return(&model.TradingPairStatusChange{ID: 1, Symbol: "BTC/USDT", FromStatus: 1, State: model.StatusChangeStatePending}, nil)
+	mockChangeModel.On("FindOne", ctx, uint64(2)).Return(&model.TradingPairStatusChange{ID: 2, Symbol: "BTC/USDT", State: model.StatusChangeStateExecuted}, nil)
+	mockChangeModel.On("UpdateState", ctx, uint64(1), model.StatusChangeStateCanceled, int64(1), "canceled by operator 7").Return(nil)
+
+	assert.NoError(t, manager.CancelScheduledStatusChange(1, 7))
+
+	err := manager.CancelScheduledStatusChange(2, 7)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no longer pending")
+
+	mockChangeModel.AssertExpectations(t)
+}
@@ -41,6 +41,10 @@ func (m *TradingPairManager) InitializeDefaultTradingPairs() error {
 			MaxAmount:     "1000",
 			PriceScale:    2,
 			AmountScale:   8,
+			TickSize:      "0.01",
+			StepSize:      "0.00000001",
+			MinNotional:   "10",
+			MaxOpenOrders: 200,
 			Status:        1,
 			CreatedAt:     time.Now(),
 		},
@@ -52,6 +56,10 @@ func (m *TradingPairManager) InitializeDefaultTradingPairs() error {
 			MaxAmount:     "10000",
 			PriceScale:    2,
 			AmountScale:   6,
+			TickSize:      "0.01",
+			StepSize:      "0.000001",
+			MinNotional:   "10",
+			MaxOpenOrders: 200,
 			Status:        1,
 			CreatedAt:     time.Now(),
 		},
@@ -63,6 +71,10 @@ func (m *TradingPairManager) InitializeDefaultTradingPairs() error {
 			MaxAmount:     "100000",
 			PriceScale:    2,
 			AmountScale:   4,
+			TickSize:      "0.01",
+			StepSize:      "0.0001",
+			MinNotional:   "5",
+			MaxOpenOrders: 200,
 			Status:        1,
 			CreatedAt:     time.Now(),
 		},
@@ -74,6 +86,10 @@ func (m *TradingPairManager) InitializeDefaultTradingPairs() error {
 			MaxAmount:     "1000000",
 			PriceScale:    4,
 			AmountScale:   2,
+			TickSize:      "0.0001",
+			StepSize:      "0.01",
+			MinNotional:   "5",
+			MaxOpenOrders: 200,
 			Status:        1,
 			CreatedAt:     time.Now(),
 		},
@@ -85,6 +101,10 @@ func (m *TradingPairManager) InitializeDefaultTradingPairs() error {
 			MaxAmount:     "50000",
 			PriceScale:    3,
 			AmountScale:   3,
+			TickSize:      "0.001",
+			StepSize:      "0.001",
+			MinNotional:   "5",
+			MaxOpenOrders: 200,
 			Status:        1,
 			CreatedAt:     time.Now(),
 		},
@@ -122,6 +142,13 @@ func (m *TradingPairManager) CreateTradingPair(pair *model.TradingPair) error {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
+	// 基础币种和计价币种必须已在币种注册表中登记且处于启用状态
+	for _, code := range []string{pair.BaseCurrency, pair.QuoteCurrency} {
+		if err := m.svcCtx.CurrencyRegistry.ValidateTradable(m.ctx, code); err != nil {
+			return fmt.Errorf("validation failed: currency %s: %w", code, err)
+		}
+	}
+
 	// 检查交易对是否已存在
 	existing, err := m.svcCtx.TradingPairModel.FindBySymbol(m.ctx, pair.Symbol)
 	if err != nil && err != model.ErrNotFound {
@@ -134,48 +161,25 @@ func (m *TradingPairManager) CreateTradingPair(pair *model.TradingPair) error {
 
 	// 设置创建时间
 	pair.CreatedAt = time.Now()
+	normalizeFilters(pair)
 
 	// 插入数据库
-	_, err = m.svcCtx.TradingPairModel.Insert(m.ctx, pair)
+	result, err := m.svcCtx.TradingPairModel.Insert(m.ctx, pair)
 	if err != nil {
 		return fmt.Errorf("failed to create trading pair: %w", err)
 	}
+	if id, err := result.LastInsertId(); err == nil {
+		pair.ID = uint64(id)
+	}
 
 	m.Logger.Infof("Created trading pair: %s", pair.Symbol)
 	return nil
 }
 
-// UpdateTradingPairStatus 更新交易对状态
+// UpdateTradingPairStatus 立即更新交易对状态，状态迁移会经过校验并写入审计记录
 func (m *TradingPairManager) UpdateTradingPairStatus(symbol string, status int64) error {
-	// 验证状态
-	if err := m.validator.ValidateStatus(status); err != nil {
-		return err
-	}
-
-	// 获取现有交易对
-	pair, err := m.svcCtx.TradingPairModel.FindBySymbol(m.ctx, symbol)
-	if err != nil {
-		if err == model.ErrNotFound {
-			return fmt.Errorf("trading pair not found: %s", symbol)
-		}
-		return fmt.Errorf("failed to get trading pair: %w", err)
-	}
-
-	// 更新状态
-	pair.Status = status
-
-	// 保存到数据库
-	err = m.svcCtx.TradingPairModel.Update(m.ctx, pair)
-	if err != nil {
-		return fmt.Errorf("failed to update trading pair status: %w", err)
-	}
-
-	statusText := "disabled"
-	if status == 1 {
-		statusText = "active"
-	}
-	m.Logger.Infof("Updated trading pair %s status to %s", symbol, statusText)
-	return nil
+	_, err := m.ScheduleStatusChange(symbol, status, time.Time{}, 0, "")
+	return err
 }
 
 // GetActiveTradingPairs 获取所有活跃的交易对
@@ -212,9 +216,9 @@ func (m *TradingPairManager) ValidateOrderForTradingPair(symbol, amount, price s
 		return err
 	}
 
-	// 检查交易对是否可用
-	if pair.Status != 1 {
-		return fmt.Errorf("trading pair %s is currently disabled", symbol)
+	// 检查交易对当前状态是否接受该类型的订单
+	if err := model.CheckTradingPairOrderStatus(pair.Status, orderType); err != nil {
+		return fmt.Errorf("trading pair %s is currently %s: %w", symbol, model.TradingPairStatusText(pair.Status), err)
 	}
 
 	// 验证订单数量
@@ -222,16 +226,49 @@ func (m *TradingPairManager) ValidateOrderForTradingPair(symbol, amount, price s
 		return err
 	}
 
-	// 如果是限价单，验证价格精度
+	// 如果是限价单，验证价格精度和交易对过滤器
 	if orderType == 1 && price != "" {
 		if err := m.validator.ValidateOrderPrice(price, pair.PriceScale); err != nil {
 			return err
 		}
+
+		if err := m.validator.ValidateOrderFilters(pair, orderType, 1, amount, price); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateTradingPair 更新交易对的交易参数（数量限制、精度和过滤器），状态变更需通过ScheduleStatusChange
+func (m *TradingPairManager) UpdateTradingPair(pair *model.TradingPair) error {
+	if err := m.validateTradingPair(pair); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
 	}
+	normalizeFilters(pair)
 
+	if err := m.svcCtx.TradingPairModel.Update(m.ctx, pair); err != nil {
+		return fmt.Errorf("failed to update trading pair: %w", err)
+	}
+
+	m.Logger.Infof("Updated trading pair %s: min_amount=%s, max_amount=%s, tick_size=%s, step_size=%s, min_notional=%s, max_open_orders=%d, min_verification_level=%d",
+		pair.Symbol, pair.MinAmount, pair.MaxAmount, pair.TickSize, pair.StepSize, pair.MinNotional, pair.MaxOpenOrders, pair.MinVerificationLevel)
 	return nil
 }
 
+// normalizeFilters 未配置的过滤器统一存储为0，表示不限制
+func normalizeFilters(pair *model.TradingPair) {
+	if pair.TickSize == "" {
+		pair.TickSize = "0"
+	}
+	if pair.StepSize == "" {
+		pair.StepSize = "0"
+	}
+	if pair.MinNotional == "" {
+		pair.MinNotional = "0"
+	}
+}
+
 // validateTradingPair 验证交易对数据的完整性
 func (m *TradingPairManager) validateTradingPair(pair *model.TradingPair) error {
 	// 验证交易对符号
@@ -262,6 +299,27 @@ func (m *TradingPairManager) validateTradingPair(pair *model.TradingPair) error
 		return err
 	}
 
+	// 验证过滤器设置
+	if err := m.validator.ValidateTickSize(pair.TickSize, pair.PriceScale); err != nil {
+		return err
+	}
+
+	if err := m.validator.ValidateStepSize(pair.StepSize, pair.AmountScale); err != nil {
+		return err
+	}
+
+	if err := m.validator.ValidateFilterValue(pair.MinNotional, "min_notional"); err != nil {
+		return err
+	}
+
+	if err := m.validator.ValidateMaxOpenOrders(pair.MaxOpenOrders); err != nil {
+		return err
+	}
+
+	if err := m.validator.ValidateMinVerificationLevel(pair.MinVerificationLevel); err != nil {
+		return err
+	}
+
 	// 验证状态
 	if err := m.validator.ValidateStatus(pair.Status); err != nil {
 		return err
@@ -7,12 +7,15 @@ import (
 	"time"
 
 	"crypto-exchange/internal/config"
+	"crypto-exchange/internal/currency"
+	"crypto-exchange/internal/matching"
 	"crypto-exchange/internal/svc"
 	"crypto-exchange/model"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/zeromicro/go-zero/core/stores/redis"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
 
 // MockSqlResult 模拟SQL结果
@@ -75,7 +78,43 @@ func (m *MockTradingPairModel) FindActivePairs(ctx context.Context) ([]*model.Tr
 	return args.Get(0).([]*model.TradingPair), args.Error(1)
 }
 
+func (m *MockTradingPairModel) FindAll(ctx context.Context) ([]*model.TradingPair, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*model.TradingPair), args.Error(1)
+}
+
+func (m *MockTradingPairModel) WithSession(session sqlx.Session) model.TradingPairModel {
+	return m
+}
+
+// MockCurrencyModel 模拟币种模型，FindAll返回固定的币种配置
+type MockCurrencyModel struct {
+	model.CurrencyModel
+	currencies []*model.Currency
+}
+
+func (m *MockCurrencyModel) FindAll(ctx context.Context) ([]*model.Currency, error) {
+	return m.currencies, nil
+}
+
+// MockCurrencyNetworkModel 交易对校验不涉及网络配置，FindAll返回空列表
+type MockCurrencyNetworkModel struct {
+	model.CurrencyNetworkModel
+}
+
+func (m *MockCurrencyNetworkModel) FindAll(ctx context.Context) ([]*model.CurrencyNetwork, error) {
+	return nil, nil
+}
+
 func createTestServiceContext() *svc.ServiceContext {
+	currencyModel := &MockCurrencyModel{currencies: []*model.Currency{
+		{Code: "BTC", Status: model.CurrencyStatusEnabled},
+		{Code: "ETH", Status: model.CurrencyStatusEnabled},
+		{Code: "LTC", Status: model.CurrencyStatusEnabled},
+		{Code: "USDT", Status: model.CurrencyStatusEnabled},
+		{Code: "XRP", Status: model.CurrencyStatusDisabled},
+	}}
+
 	return &svc.ServiceContext{
 		Config: config.Config{
 			Redis: redis.RedisConf{
@@ -83,6 +122,7 @@ func createTestServiceContext() *svc.ServiceContext {
 				Type: "node",
 			},
 		},
+		CurrencyRegistry: currency.NewRegistry(currencyModel, &MockCurrencyNetworkModel{}, time.Minute),
 	}
 }
 
@@ -265,11 +305,25 @@ func TestTradingPairManager_ValidateOrderForTradingPair(t *testing.T) {
 			orderType: 1,
 			mockSetup: func() {
 				disabledPair := *testPair
-				disabledPair.Status = 2 // disabled
+				disabledPair.Status = 2 // halted
 				mockModel.On("FindBySymbol", ctx, "BTC/USDT").Return(&disabledPair, nil)
 			},
 			wantErr: true,
-			errMsg:  "is currently disabled",
+			errMsg:  "is currently halted",
+		},
+		{
+			name:      "market order on post-only trading pair",
+			symbol:    "BTC/USDT",
+			amount:    "0.1",
+			price:     "",
+			orderType: 2,
+			mockSetup: func() {
+				postOnlyPair := *testPair
+				postOnlyPair.Status = model.TradingPairStatusPostOnly
+				mockModel.On("FindBySymbol", ctx, "BTC/USDT").Return(&postOnlyPair, nil)
+			},
+			wantErr: true,
+			errMsg:  "is currently post-only",
 		},
 	}
 
@@ -301,7 +355,10 @@ func TestTradingPairManager_UpdateTradingPairStatus(t *testing.T) {
 	ctx := context.Background()
 	svcCtx := createTestServiceContext()
 	mockModel := &MockTradingPairModel{}
+	mockChangeModel := &MockTradingPairStatusChangeModel{}
 	svcCtx.TradingPairModel = mockModel
+	svcCtx.TradingPairStatusChangeModel = mockChangeModel
+	svcCtx.MatchingEngine = matching.NewMatchingEngine()
 
 	manager := NewTradingPairManager(ctx, svcCtx)
 
@@ -327,7 +384,7 @@ func TestTradingPairManager_UpdateTradingPairStatus(t *testing.T) {
 		errMsg    string
 	}{
 		{
-			name:   "valid status update to disabled",
+			name:   "valid status update to halted",
 			symbol: "BTC/USDT",
 			status: 2,
 			mockSetup: func() {
@@ -335,13 +392,14 @@ func TestTradingPairManager_UpdateTradingPairStatus(t *testing.T) {
 				updatedPair := *testPair
 				updatedPair.Status = 2
 				mockModel.On("Update", ctx, &updatedPair).Return(nil)
+				mockChangeModel.On("Insert", ctx, mock.AnythingOfType("*model.TradingPairStatusChange")).Return(&MockSqlResult{lastInsertId: 1}, nil)
 			},
 			wantErr: false,
 		},
 		{
 			name:   "invalid status",
 			symbol: "BTC/USDT",
-			status: 3,
+			status: 9,
 			mockSetup: func() {
 				// No mock setup needed as validation fails first
 			},
@@ -472,6 +530,38 @@ func TestTradingPairManager_CreateTradingPair(t *testing.T) {
 			wantErr: true,
 			errMsg:  "validation failed",
 		},
+		{
+			name: "unregistered currency",
+			pair: &model.TradingPair{
+				Symbol:        "DOGE/USDT",
+				BaseCurrency:  "DOGE",
+				QuoteCurrency: "USDT",
+				MinAmount:     "1",
+				MaxAmount:     "10000",
+				PriceScale:    4,
+				AmountScale:   2,
+				Status:        1,
+			},
+			mockSetup: func() {},
+			wantErr:   true,
+			errMsg:    "currency not found",
+		},
+		{
+			name: "disabled currency",
+			pair: &model.TradingPair{
+				Symbol:        "XRP/USDT",
+				BaseCurrency:  "XRP",
+				QuoteCurrency: "USDT",
+				MinAmount:     "1",
+				MaxAmount:     "10000",
+				PriceScale:    4,
+				AmountScale:   2,
+				Status:        1,
+			},
+			mockSetup: func() {},
+			wantErr:   true,
+			errMsg:    "currency is disabled",
+		},
 		{
 			name: "trading pair already exists",
 			pair: &model.TradingPair{
@@ -5,6 +5,8 @@ import (
 	"regexp"
 	"strings"
 
+	"crypto-exchange/model"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -110,9 +112,9 @@ func (v *TradingPairValidator) ValidateScale(scale int64, fieldName string) erro
 
 // ValidateStatus 验证交易对状态
 func (v *TradingPairValidator) ValidateStatus(status int64) error {
-	// 状态：1-正常交易，2-禁用交易
-	if status != 1 && status != 2 {
-		return fmt.Errorf("invalid status, must be 1 (active) or 2 (disabled)")
+	// 状态：1-正常交易，2-暂停交易，3-预上线，4-仅可撤单，5-仅挂单，6-已下架
+	if !model.IsValidTradingPairStatus(status) {
+		return fmt.Errorf("invalid status, must be between 1 (trading) and 6 (delisted)")
 	}
 
 	return nil
@@ -167,4 +169,169 @@ func (v *TradingPairValidator) ValidateOrderPrice(price string, priceScale int64
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+// ValidateFilterValue 验证交易对过滤器配置值（最小变动单位、最小下单金额），空值或0表示不限制
+func (v *TradingPairValidator) ValidateFilterValue(value string, fieldName string) error {
+	if value == "" {
+		return nil
+	}
+
+	valueDecimal, err := decimal.NewFromString(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s format: %s", fieldName, value)
+	}
+
+	if valueDecimal.IsNegative() {
+		return fmt.Errorf("%s cannot be negative", fieldName)
+	}
+
+	if valueDecimal.Exponent() < -18 {
+		return fmt.Errorf("%s precision cannot exceed 18 decimal places", fieldName)
+	}
+
+	return nil
+}
+
+// ValidateTickSize 验证价格最小变动单位，其精度不能超过交易对的价格精度
+func (v *TradingPairValidator) ValidateTickSize(tickSize string, priceScale int64) error {
+	if err := v.ValidateFilterValue(tickSize, "tick_size"); err != nil {
+		return err
+	}
+
+	tick, enabled := filterDecimal(tickSize)
+	if enabled && tick.Exponent() < -int32(priceScale) {
+		return fmt.Errorf("tick_size precision exceeds price_scale %d", priceScale)
+	}
+
+	return nil
+}
+
+// ValidateStepSize 验证数量最小变动单位，其精度不能超过交易对的数量精度
+func (v *TradingPairValidator) ValidateStepSize(stepSize string, amountScale int64) error {
+	if err := v.ValidateFilterValue(stepSize, "step_size"); err != nil {
+		return err
+	}
+
+	step, enabled := filterDecimal(stepSize)
+	if enabled && step.Exponent() < -int32(amountScale) {
+		return fmt.Errorf("step_size precision exceeds amount_scale %d", amountScale)
+	}
+
+	return nil
+}
+
+// ValidateMaxOpenOrders 验证单用户最大挂单数量，0表示不限制
+func (v *TradingPairValidator) ValidateMaxOpenOrders(maxOpenOrders int64) error {
+	if maxOpenOrders < 0 {
+		return fmt.Errorf("max_open_orders cannot be negative")
+	}
+
+	return nil
+}
+
+// ValidateMinVerificationLevel 验证下单所需的最低认证等级，0表示不限制
+func (v *TradingPairValidator) ValidateMinVerificationLevel(level int64) error {
+	if level < model.UserVerificationUnverified || level > model.UserVerificationAdvanced {
+		return fmt.Errorf("min_verification_level must be between %d and %d", model.UserVerificationUnverified, model.UserVerificationAdvanced)
+	}
+
+	return nil
+}
+
+// ValidateOrderTickSize 验证订单价格是否为价格最小变动单位的整数倍
+func (v *TradingPairValidator) ValidateOrderTickSize(price, tickSize string) error {
+	tick, enabled := filterDecimal(tickSize)
+	if !enabled {
+		return nil
+	}
+
+	priceDecimal, err := decimal.NewFromString(price)
+	if err != nil {
+		return fmt.Errorf("invalid price format: %s", price)
+	}
+
+	if !priceDecimal.Mod(tick).IsZero() {
+		return fmt.Errorf("%w: price %s, tick size %s", model.ErrPriceTickSize, price, tickSize)
+	}
+
+	return nil
+}
+
+// ValidateOrderStepSize 验证订单数量是否为数量最小变动单位的整数倍
+func (v *TradingPairValidator) ValidateOrderStepSize(amount, stepSize string) error {
+	step, enabled := filterDecimal(stepSize)
+	if !enabled {
+		return nil
+	}
+
+	amountDecimal, err := decimal.NewFromString(amount)
+	if err != nil {
+		return fmt.Errorf("invalid order amount format: %s", amount)
+	}
+
+	if !amountDecimal.Mod(step).IsZero() {
+		return fmt.Errorf("%w: amount %s, step size %s", model.ErrAmountStepSize, amount, stepSize)
+	}
+
+	return nil
+}
+
+// ValidateOrderNotional 验证订单金额（价格×数量）是否达到最小下单金额
+func (v *TradingPairValidator) ValidateOrderNotional(notional decimal.Decimal, minNotional string) error {
+	minDecimal, enabled := filterDecimal(minNotional)
+	if !enabled {
+		return nil
+	}
+
+	if notional.LessThan(minDecimal) {
+		return fmt.Errorf("%w: notional %s, minimum %s", model.ErrMinNotional, notional.String(), minNotional)
+	}
+
+	return nil
+}
+
+// ValidateOrderFilters 按交易对的过滤器配置验证订单
+// 限价单校验价格步长、数量步长和最小下单金额；市价买单的数量为计价币种金额，只校验最小下单金额；
+// 市价卖单下单时无法确定成交价格，只校验数量步长
+func (v *TradingPairValidator) ValidateOrderFilters(pair *model.TradingPair, orderType, side int64, amount, price string) error {
+	amountDecimal, err := decimal.NewFromString(amount)
+	if err != nil {
+		return fmt.Errorf("invalid order amount format: %s", amount)
+	}
+
+	if orderType == 2 {
+		if side == 1 {
+			return v.ValidateOrderNotional(amountDecimal, pair.MinNotional)
+		}
+		return v.ValidateOrderStepSize(amount, pair.StepSize)
+	}
+
+	if err := v.ValidateOrderTickSize(price, pair.TickSize); err != nil {
+		return err
+	}
+
+	if err := v.ValidateOrderStepSize(amount, pair.StepSize); err != nil {
+		return err
+	}
+
+	priceDecimal, err := decimal.NewFromString(price)
+	if err != nil {
+		return fmt.Errorf("invalid price format: %s", price)
+	}
+
+	return v.ValidateOrderNotional(priceDecimal.Mul(amountDecimal), pair.MinNotional)
+}
+
+// filterDecimal 解析过滤器配置值，空值、格式错误或不大于0时视为未启用
+func filterDecimal(value string) (decimal.Decimal, bool) {
+	if value == "" {
+		return decimal.Zero, false
+	}
+
+	valueDecimal, err := decimal.NewFromString(value)
+	if err != nil || !valueDecimal.IsPositive() {
+		return decimal.Zero, false
+	}
+
+	return valueDecimal, true
+}
@@ -3,6 +3,8 @@ package market
 import (
 	"testing"
 
+	"crypto-exchange/model"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -361,13 +363,18 @@ func TestTradingPairValidator_ValidateStatus(t *testing.T) {
 			name:    "invalid status 0",
 			status:  0,
 			wantErr: true,
-			errMsg:  "invalid status, must be 1 (active) or 2 (disabled)",
+			errMsg:  "invalid status, must be between 1 (trading) and 6 (delisted)",
 		},
 		{
-			name:    "invalid status 3",
-			status:  3,
+			name:    "valid status delisted",
+			status:  6,
+			wantErr: false,
+		},
+		{
+			name:    "invalid status 7",
+			status:  7,
 			wantErr: true,
-			errMsg:  "invalid status, must be 1 (active) or 2 (disabled)",
+			errMsg:  "invalid status, must be between 1 (trading) and 6 (delisted)",
 		},
 	}
 
@@ -525,4 +532,106 @@ func TestTradingPairValidator_ValidateOrderPrice(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestTradingPairValidator_ValidateTickAndStepSize(t *testing.T) {
+	validator := NewTradingPairValidator()
+
+	tests := []struct {
+		name    string
+		check   func() error
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "tick size disabled",
+			check:   func() error { return validator.ValidateTickSize("0", 2) },
+			wantErr: false,
+		},
+		{
+			name:    "valid tick size",
+			check:   func() error { return validator.ValidateTickSize("0.5", 2) },
+			wantErr: false,
+		},
+		{
+			name:    "tick size finer than price scale",
+			check:   func() error { return validator.ValidateTickSize("0.001", 2) },
+			wantErr: true,
+			errMsg:  "tick_size precision exceeds price_scale 2",
+		},
+		{
+			name:    "negative step size",
+			check:   func() error { return validator.ValidateStepSize("-0.1", 8) },
+			wantErr: true,
+			errMsg:  "step_size cannot be negative",
+		},
+		{
+			name:    "invalid min notional",
+			check:   func() error { return validator.ValidateFilterValue("abc", "min_notional") },
+			wantErr: true,
+			errMsg:  "invalid min_notional format",
+		},
+		{
+			name:    "negative max open orders",
+			check:   func() error { return validator.ValidateMaxOpenOrders(-1) },
+			wantErr: true,
+			errMsg:  "max_open_orders cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check()
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errMsg != "" {
+					assert.Contains(t, err.Error(), tt.errMsg)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTradingPairValidator_ValidateOrderFilters(t *testing.T) {
+	validator := NewTradingPairValidator()
+
+	pair := &model.TradingPair{
+		Symbol:      "BTC/USDT",
+		TickSize:    "0.5",
+		StepSize:    "0.001",
+		MinNotional: "10",
+	}
+
+	tests := []struct {
+		name      string
+		orderType int64
+		side      int64
+		amount    string
+		price     string
+		wantErr   error
+	}{
+		{"valid limit order", 1, 1, "0.002", "50000.5", nil},
+		{"price not multiple of tick size", 1, 1, "0.002", "50000.3", model.ErrPriceTickSize},
+		{"amount not multiple of step size", 1, 2, "0.0015", "50000", model.ErrAmountStepSize},
+		{"limit order below min notional", 1, 1, "0.001", "5000", model.ErrMinNotional},
+		{"market buy below min notional", 2, 1, "9.99", "", model.ErrMinNotional},
+		{"market buy ignores step size", 2, 1, "10.0005", "", nil},
+		{"market sell checks step size", 2, 2, "0.0015", "", model.ErrAmountStepSize},
+		{"market sell valid", 2, 2, "0.001", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateOrderFilters(pair, tt.orderType, tt.side, tt.amount, tt.price)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	// 未配置过滤器时不做限制
+	assert.NoError(t, validator.ValidateOrderFilters(&model.TradingPair{}, 1, 1, "0.0000001", "0.123"))
+}
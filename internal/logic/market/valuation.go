@@ -0,0 +1,104 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// Valuator 按最新成交价将币种金额折算为参考币种，用于按统一计价校验限额
+// 优先使用 币种/参考币种 交易对的最新成交价，其次使用 参考币种/币种 交易对价格的倒数；
+// 锚定币种按1:1折算。同一个Valuator内缓存已查询的价格，适合在一次校验中使用。
+type Valuator struct {
+	logx.Logger
+	ctx       context.Context
+	svcCtx    *svc.ServiceContext
+	reference string
+	pegged    []string
+	prices    map[string]decimal.Decimal
+}
+
+// NewValuator 创建以reference为参考币种的估值器，pegged为与参考币种1:1折算的币种
+func NewValuator(ctx context.Context, svcCtx *svc.ServiceContext, reference string, pegged []string) *Valuator {
+	return &Valuator{
+		Logger:    logx.WithContext(ctx),
+		ctx:       ctx,
+		svcCtx:    svcCtx,
+		reference: strings.ToUpper(reference),
+		pegged:    pegged,
+		prices:    make(map[string]decimal.Decimal),
+	}
+}
+
+// Reference 返回参考币种
+func (v *Valuator) Reference() string {
+	return v.reference
+}
+
+// Value 将amount个currency折算为参考币种，无法获取价格时返回ErrPriceUnavailable
+func (v *Valuator) Value(currency string, amount decimal.Decimal) (decimal.Decimal, error) {
+	price, ok := v.prices[currency]
+	if !ok {
+		var err error
+		price, err = v.price(currency)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		v.prices[currency] = price
+	}
+	return amount.Mul(price), nil
+}
+
+// price 查询1个currency对应的参考币种价格
+func (v *Valuator) price(currency string) (decimal.Decimal, error) {
+	if currency == v.reference {
+		return decimal.NewFromInt(1), nil
+	}
+	for _, pegged := range v.pegged {
+		if strings.EqualFold(pegged, currency) {
+			return decimal.NewFromInt(1), nil
+		}
+	}
+
+	price, err := v.lastPrice(currency + "/" + v.reference)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if price.IsPositive() {
+		return price, nil
+	}
+
+	price, err = v.lastPrice(v.reference + "/" + currency)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if price.IsPositive() {
+		return decimal.NewFromInt(1).DivRound(price, 18), nil
+	}
+
+	return decimal.Zero, fmt.Errorf("%w: no %s price for %s", model.ErrPriceUnavailable, v.reference, currency)
+}
+
+// lastPrice 查询交易对的最新成交价，行情不存在或价格无效时返回0
+func (v *Valuator) lastPrice(symbol string) (decimal.Decimal, error) {
+	ticker, err := v.svcCtx.TickerModel.FindBySymbol(v.ctx, symbol)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return decimal.Zero, nil
+		}
+		return decimal.Zero, err
+	}
+	price, err := decimal.NewFromString(ticker.LastPrice)
+	if err != nil {
+		v.Errorf("Invalid last price for %s: %s", symbol, ticker.LastPrice)
+		return decimal.Zero, nil
+	}
+	return price, nil
+}
@@ -0,0 +1,412 @@
+package reconciliation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"crypto-exchange/internal/logic/market"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+// accountKey 对账账户：用户+币种
+type accountKey struct {
+	userID   uint64
+	currency string
+}
+
+// accountState 单个账户的期望值与实际值
+type accountState struct {
+	expectedTotal  decimal.Decimal
+	expectedFrozen decimal.Decimal
+	actualTotal    decimal.Decimal
+	actualFrozen   decimal.Decimal
+}
+
+// Reconciler 余额对账器
+// 根据充提记录、成交记录和未完成订单重新计算每个用户每个币种的期望余额，并与balances表比对
+type Reconciler struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// NewReconciler 创建余额对账器
+func NewReconciler(ctx context.Context, svcCtx *svc.ServiceContext) *Reconciler {
+	return &Reconciler{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Run 执行一次对账，生成对账报告和差异明细；autoFreeze为true时冻结连续两次对账中同一账户都不平的用户
+func (r *Reconciler) Run(autoFreeze bool) (*model.ReconciliationReport, []*model.BalanceDiscrepancy, error) {
+	tolerance, err := decimal.NewFromString(r.svcCtx.Config.Reconciliation.Tolerance)
+	if err != nil || tolerance.IsNegative() {
+		return nil, nil, fmt.Errorf("invalid reconciliation tolerance: %s", r.svcCtx.Config.Reconciliation.Tolerance)
+	}
+
+	report := &model.ReconciliationReport{
+		Status:     model.ReconciliationStatusRunning,
+		AutoFreeze: autoFreeze,
+		StartedAt:  time.Now(),
+	}
+	result, err := r.svcCtx.ReconciliationReportModel.Insert(r.ctx, report)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create reconciliation report: %w", err)
+	}
+	reportID, err := result.LastInsertId()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get reconciliation report ID: %w", err)
+	}
+	report.ID = uint64(reportID)
+
+	discrepancies, err := r.reconcile(report, tolerance)
+	if err != nil {
+		report.Status = model.ReconciliationStatusFailed
+		report.Remark = err.Error()
+	} else {
+		report.Status = model.ReconciliationStatusCompleted
+	}
+	report.FinishedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	if updateErr := r.svcCtx.ReconciliationReportModel.Update(r.ctx, report); updateErr != nil {
+		r.Errorf("Failed to update reconciliation report %d: %v", report.ID, updateErr)
+	}
+	if err != nil {
+		return report, nil, err
+	}
+
+	r.Infof("Reconciliation report %d: checked %d accounts, found %d discrepancies, froze %d users",
+		report.ID, report.CheckedAccounts, report.DiscrepancyCount, report.FrozenUsers)
+	return report, discrepancies, nil
+}
+
+// snapshot 对账使用的数据，在同一个可重复读事务中读取，保证来自同一时间点
+type snapshot struct {
+	assetFlows    []*model.BalanceFlow
+	withdrawHolds []*model.BalanceFlow
+	tradeFlows    []*model.BalanceFlow
+	openOrders    []*model.Order
+	pairs         []*model.TradingPair
+	balances      []*model.Balance
+}
+
+// loadSnapshot 在可重复读事务中读取充提记录、成交记录、未完成订单和余额，
+// 避免读取期间新的成交或充提只出现在部分查询结果中而被误报为差异
+func (r *Reconciler) loadSnapshot() (*snapshot, error) {
+	data := &snapshot{}
+	err := r.svcCtx.BalanceModel.TransRepeatableRead(r.ctx, func(ctx context.Context, session sqlx.Session) error {
+		transactions := r.svcCtx.AssetTransactionModel.WithSession(session)
+		orders := r.svcCtx.OrderModel.WithSession(session)
+		var err error
+
+		data.assetFlows, err = transactions.SumNetFlowByUserAndCurrency(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to sum asset transactions: %w", err)
+		}
+
+		data.withdrawHolds, err = transactions.SumInFlightWithdrawalsByUserAndCurrency(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to sum in-flight withdrawals: %w", err)
+		}
+
+		data.tradeFlows, err = r.svcCtx.TradeModel.WithSession(session).SumNetFlowByUserAndCurrency(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to sum trades: %w", err)
+		}
+
+		for _, status := range []int64{1, 2} { // 1-待成交，2-部分成交
+			open, err := orders.FindByStatus(ctx, status)
+			if err != nil {
+				return fmt.Errorf("failed to get open orders: %w", err)
+			}
+			data.openOrders = append(data.openOrders, open...)
+		}
+
+		data.pairs, err = r.svcCtx.TradingPairModel.WithSession(session).FindAll(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get trading pairs: %w", err)
+		}
+
+		data.balances, err = r.svcCtx.BalanceModel.WithSession(session).FindAll(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get balances: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// reconcile 加载数据并比对，写入差异明细
+func (r *Reconciler) reconcile(report *model.ReconciliationReport, tolerance decimal.Decimal) ([]*model.BalanceDiscrepancy, error) {
+	data, err := r.loadSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := buildAccountStates(data.assetFlows, data.tradeFlows, data.withdrawHolds, data.openOrders, data.pairs, data.balances)
+	if err != nil {
+		return nil, err
+	}
+
+	discrepancies := findDiscrepancies(accounts, tolerance)
+	report.CheckedAccounts = int64(len(accounts))
+	report.DiscrepancyCount = int64(len(discrepancies))
+
+	if report.AutoFreeze {
+		frozenUsers, err := r.freezeUsers(report.ID, discrepancies)
+		if err != nil {
+			return nil, err
+		}
+		report.FrozenUsers = frozenUsers
+	}
+
+	now := time.Now()
+	for _, discrepancy := range discrepancies {
+		discrepancy.ReportID = report.ID
+		discrepancy.CreatedAt = now
+		if _, err := r.svcCtx.BalanceDiscrepancyModel.Insert(r.ctx, discrepancy); err != nil {
+			return nil, fmt.Errorf("failed to save discrepancy of user %d %s: %w", discrepancy.UserID, discrepancy.Currency, err)
+		}
+	}
+
+	return discrepancies, nil
+}
+
+// freezeUsers 对存在不平账户的用户施加冻结限制（记录审计日志，可由管理员解除），返回本次新冻结的用户数量
+// 只冻结上一次已完成的对账中同一账户也不平的用户，单次对账发现的差异需要下一次对账确认
+func (r *Reconciler) freezeUsers(reportID uint64, discrepancies []*model.BalanceDiscrepancy) (int64, error) {
+	confirmed, err := r.confirmedAccounts(reportID)
+	if err != nil {
+		return 0, err
+	}
+
+	var frozen int64
+	handled := make(map[uint64]bool)
+	for _, discrepancy := range discrepancies {
+		if !confirmed[accountKey{userID: discrepancy.UserID, currency: discrepancy.Currency}] {
+			r.Infof("Discrepancy of user %d %s awaits confirmation by the next reconciliation", discrepancy.UserID, discrepancy.Currency)
+			continue
+		}
+		if !handled[discrepancy.UserID] {
+			handled[discrepancy.UserID] = true
+
+			if _, err := r.svcCtx.UserModel.FindOne(r.ctx, discrepancy.UserID); err != nil {
+				if errors.Is(err, model.ErrNotFound) {
+					r.Errorf("User %d with unbalanced account not found", discrepancy.UserID)
+					continue
+				}
+				return 0, fmt.Errorf("failed to get user %d: %w", discrepancy.UserID, err)
+			}
+
+			now := time.Now()
+			active, err := r.svcCtx.UserRestrictionModel.FindActiveByUserID(r.ctx, discrepancy.UserID, now)
+			if err != nil {
+				return 0, fmt.Errorf("failed to get restrictions of user %d: %w", discrepancy.UserID, err)
+			}
+			if !hasRestriction(active, model.RestrictionFreeze) {
+				reason := fmt.Sprintf("unbalanced account found by reconciliation report %d", reportID)
+				err := r.svcCtx.UserRestrictionModel.Apply(r.ctx, &model.UserRestriction{
+					UserID:    discrepancy.UserID,
+					Type:      model.RestrictionFreeze,
+					Reason:    reason,
+					CreatedAt: now,
+					UpdatedAt: now,
+				}, &model.RestrictionAuditLog{
+					UserID:    discrepancy.UserID,
+					Type:      model.RestrictionFreeze,
+					Action:    model.RestrictionAuditActionApply,
+					Reason:    reason,
+					CreatedAt: now,
+				})
+				if err != nil {
+					return 0, fmt.Errorf("failed to freeze user %d: %w", discrepancy.UserID, err)
+				}
+				frozen++
+				r.Infof("User %d frozen by reconciliation report %d", discrepancy.UserID, reportID)
+
+				// 冻结已经生效，注销会话失败只记录日志
+				if err := r.svcCtx.Sessions.RevokeAll(r.ctx, discrepancy.UserID); err != nil {
+					r.Errorf("Failed to revoke sessions of frozen user %d: %v", discrepancy.UserID, err)
+				}
+			}
+		}
+		discrepancy.UserFrozen = true
+	}
+
+	return frozen, nil
+}
+
+// confirmedAccounts 返回上一次已完成的对账中不平的账户，没有上一次对账时返回空集合
+func (r *Reconciler) confirmedAccounts(reportID uint64) (map[accountKey]bool, error) {
+	confirmed := make(map[accountKey]bool)
+	previous, err := r.svcCtx.ReconciliationReportModel.FindPreviousCompleted(r.ctx, reportID)
+	if errors.Is(err, model.ErrNotFound) {
+		return confirmed, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get previous reconciliation report: %w", err)
+	}
+
+	discrepancies, err := r.svcCtx.BalanceDiscrepancyModel.FindByReportID(r.ctx, previous.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get discrepancies of report %d: %w", previous.ID, err)
+	}
+	for _, discrepancy := range discrepancies {
+		confirmed[accountKey{userID: discrepancy.UserID, currency: discrepancy.Currency}] = true
+	}
+	return confirmed, nil
+}
+
+// hasRestriction 判断限制列表中是否包含指定类型
+func hasRestriction(restrictions []*model.UserRestriction, restrictionType string) bool {
+	for _, r := range restrictions {
+		if r.Type == restrictionType {
+			return true
+		}
+	}
+	return false
+}
+
+// buildAccountStates 汇总每个账户的期望余额和实际余额
+// 期望总余额 = 充值 - 已确认提现(含手续费) ± 成交；期望冻结余额 = 未完成订单剩余部分的冻结数量 + 处理中提现的冻结金额
+func buildAccountStates(assetFlows, tradeFlows, withdrawHolds []*model.BalanceFlow, openOrders []*model.Order, pairs []*model.TradingPair, balances []*model.Balance) (map[accountKey]*accountState, error) {
+	accounts := make(map[accountKey]*accountState)
+	account := func(userID uint64, currency string) *accountState {
+		key := accountKey{userID: userID, currency: currency}
+		state, ok := accounts[key]
+		if !ok {
+			state = &accountState{}
+			accounts[key] = state
+		}
+		return state
+	}
+
+	for _, flows := range [][]*model.BalanceFlow{assetFlows, tradeFlows} {
+		for _, flow := range flows {
+			amount, err := decimal.NewFromString(flow.Amount)
+			if err != nil {
+				return nil, fmt.Errorf("invalid flow amount of user %d %s: %s", flow.UserID, flow.Currency, flow.Amount)
+			}
+			state := account(flow.UserID, flow.Currency)
+			state.expectedTotal = state.expectedTotal.Add(amount)
+		}
+	}
+
+	for _, hold := range withdrawHolds {
+		amount, err := decimal.NewFromString(hold.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid withdrawal hold amount of user %d %s: %s", hold.UserID, hold.Currency, hold.Amount)
+		}
+		state := account(hold.UserID, hold.Currency)
+		state.expectedFrozen = state.expectedFrozen.Add(amount)
+	}
+
+	pairMap := make(map[string]*model.TradingPair, len(pairs))
+	for _, pair := range pairs {
+		pairMap[pair.Symbol] = pair
+	}
+	for _, order := range openOrders {
+		pair, ok := pairMap[order.Symbol]
+		if !ok {
+			return nil, fmt.Errorf("trading pair %s of order %d not found", order.Symbol, order.ID)
+		}
+		currency, amount, err := market.RemainingFrozenAmount(order, pair)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate frozen amount of order %d: %w", order.ID, err)
+		}
+		if amount.IsPositive() {
+			state := account(order.UserID, currency)
+			state.expectedFrozen = state.expectedFrozen.Add(amount)
+		}
+	}
+
+	for _, balance := range balances {
+		available, err := decimal.NewFromString(balance.Available)
+		if err != nil {
+			return nil, fmt.Errorf("invalid available balance of user %d %s: %s", balance.UserID, balance.Currency, balance.Available)
+		}
+		frozen, err := decimal.NewFromString(balance.Frozen)
+		if err != nil {
+			return nil, fmt.Errorf("invalid frozen balance of user %d %s: %s", balance.UserID, balance.Currency, balance.Frozen)
+		}
+		state := account(balance.UserID, balance.Currency)
+		state.actualTotal = state.actualTotal.Add(available).Add(frozen)
+		state.actualFrozen = state.actualFrozen.Add(frozen)
+	}
+
+	return accounts, nil
+}
+
+// findDiscrepancies 找出总余额或冻结余额差额超过容差的账户，按用户ID和币种排序
+func findDiscrepancies(accounts map[accountKey]*accountState, tolerance decimal.Decimal) []*model.BalanceDiscrepancy {
+	keys := make([]accountKey, 0, len(accounts))
+	for key, state := range accounts {
+		if state.expectedTotal.Sub(state.actualTotal).Abs().GreaterThan(tolerance) ||
+			state.expectedFrozen.Sub(state.actualFrozen).Abs().GreaterThan(tolerance) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].userID != keys[j].userID {
+			return keys[i].userID < keys[j].userID
+		}
+		return keys[i].currency < keys[j].currency
+	})
+
+	discrepancies := make([]*model.BalanceDiscrepancy, 0, len(keys))
+	for _, key := range keys {
+		state := accounts[key]
+		discrepancies = append(discrepancies, &model.BalanceDiscrepancy{
+			UserID:         key.userID,
+			Currency:       key.currency,
+			ExpectedTotal:  state.expectedTotal.String(),
+			ActualTotal:    state.actualTotal.String(),
+			ExpectedFrozen: state.expectedFrozen.String(),
+			ActualFrozen:   state.actualFrozen.String(),
+		})
+	}
+
+	return discrepancies
+}
+
+// StartReconciliationScheduler 启动定时对账任务，interval<=0时不启动，返回停止函数
+func StartReconciliationScheduler(svcCtx *svc.ServiceContext, interval time.Duration, autoFreeze bool) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	if interval <= 0 {
+		return cancel
+	}
+
+	reconciler := NewReconciler(ctx, svcCtx)
+	threading.GoSafe(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, _, err := reconciler.Run(autoFreeze); err != nil {
+					logx.Errorf("Failed to run balance reconciliation: %v", err)
+				}
+			}
+		}
+	})
+
+	return cancel
+}
@@ -0,0 +1,414 @@
+package reconciliation
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/config"
+	"crypto-exchange/internal/session"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+type mockSqlResult struct {
+	lastInsertId int64
+}
+
+func (m *mockSqlResult) LastInsertId() (int64, error) {
+	return m.lastInsertId, nil
+}
+
+func (m *mockSqlResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+// 以下mock只实现对账用到的方法
+
+type mockAssetTransactionModel struct {
+	model.AssetTransactionModel
+	mock.Mock
+}
+
+func (m *mockAssetTransactionModel) SumNetFlowByUserAndCurrency(ctx context.Context) ([]*model.BalanceFlow, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*model.BalanceFlow), args.Error(1)
+}
+
+func (m *mockAssetTransactionModel) WithSession(session sqlx.Session) model.AssetTransactionModel {
+	return m
+}
+
+func (m *mockAssetTransactionModel) SumInFlightWithdrawalsByUserAndCurrency(ctx context.Context) ([]*model.BalanceFlow, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*model.BalanceFlow), args.Error(1)
+}
+
+type mockTradeModel struct {
+	model.TradeModel
+	mock.Mock
+}
+
+func (m *mockTradeModel) SumNetFlowByUserAndCurrency(ctx context.Context) ([]*model.BalanceFlow, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*model.BalanceFlow), args.Error(1)
+}
+
+func (m *mockTradeModel) WithSession(session sqlx.Session) model.TradeModel {
+	return m
+}
+
+type mockOrderModel struct {
+	model.OrderModel
+	mock.Mock
+}
+
+func (m *mockOrderModel) FindByStatus(ctx context.Context, status int64) ([]*model.Order, error) {
+	args := m.Called(ctx, status)
+	return args.Get(0).([]*model.Order), args.Error(1)
+}
+
+func (m *mockOrderModel) WithSession(session sqlx.Session) model.OrderModel {
+	return m
+}
+
+type mockTradingPairModel struct {
+	model.TradingPairModel
+	mock.Mock
+}
+
+func (m *mockTradingPairModel) FindAll(ctx context.Context) ([]*model.TradingPair, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*model.TradingPair), args.Error(1)
+}
+
+func (m *mockTradingPairModel) WithSession(session sqlx.Session) model.TradingPairModel {
+	return m
+}
+
+type mockBalanceModel struct {
+	model.BalanceModel
+	mock.Mock
+}
+
+func (m *mockBalanceModel) FindAll(ctx context.Context) ([]*model.Balance, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*model.Balance), args.Error(1)
+}
+
+func (m *mockBalanceModel) TransRepeatableRead(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	m.Called(ctx)
+	return fn(ctx, nil)
+}
+
+func (m *mockBalanceModel) WithSession(session sqlx.Session) model.BalanceModel {
+	return m
+}
+
+type mockUserModel struct {
+	model.UserModel
+	mock.Mock
+}
+
+func (m *mockUserModel) FindOne(ctx context.Context, id uint64) (*model.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+type memoryRestrictionModel struct {
+	model.UserRestrictionModel
+	restrictions []*model.UserRestriction
+	audits       []*model.RestrictionAuditLog
+}
+
+func (m *memoryRestrictionModel) FindActiveByUserID(ctx context.Context, userID uint64, now time.Time) ([]*model.UserRestriction, error) {
+	var active []*model.UserRestriction
+	for _, r := range m.restrictions {
+		if r.UserID == userID {
+			active = append(active, r)
+		}
+	}
+	return active, nil
+}
+
+func (m *memoryRestrictionModel) Apply(ctx context.Context, data *model.UserRestriction, audit *model.RestrictionAuditLog) error {
+	m.restrictions = append(m.restrictions, data)
+	m.audits = append(m.audits, audit)
+	return nil
+}
+
+type memorySessionStore struct {
+	session.Store
+	revoked []uint64
+}
+
+func (s *memorySessionStore) RevokeAll(ctx context.Context, userID uint64) error {
+	s.revoked = append(s.revoked, userID)
+	return nil
+}
+
+type mockReportModel struct {
+	model.ReconciliationReportModel
+	mock.Mock
+}
+
+func (m *mockReportModel) Insert(ctx context.Context, data *model.ReconciliationReport) (sql.Result, error) {
+	args := m.Called(ctx, data)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *mockReportModel) Update(ctx context.Context, data *model.ReconciliationReport) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *mockReportModel) FindPreviousCompleted(ctx context.Context, beforeID uint64) (*model.ReconciliationReport, error) {
+	args := m.Called(ctx, beforeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ReconciliationReport), args.Error(1)
+}
+
+type mockDiscrepancyModel struct {
+	model.BalanceDiscrepancyModel
+	mock.Mock
+}
+
+func (m *mockDiscrepancyModel) Insert(ctx context.Context, data *model.BalanceDiscrepancy) (sql.Result, error) {
+	args := m.Called(ctx, data)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *mockDiscrepancyModel) FindByReportID(ctx context.Context, reportID uint64) ([]*model.BalanceDiscrepancy, error) {
+	args := m.Called(ctx, reportID)
+	return args.Get(0).([]*model.BalanceDiscrepancy), args.Error(1)
+}
+
+var testPairs = []*model.TradingPair{
+	{Symbol: "BTC/USDT", BaseCurrency: "BTC", QuoteCurrency: "USDT"},
+}
+
+func TestBuildAccountStates(t *testing.T) {
+	assetFlows := []*model.BalanceFlow{
+		{UserID: 1, Currency: "USDT", Amount: "1000"},
+		{UserID: 2, Currency: "BTC", Amount: "2"},
+	}
+	tradeFlows := []*model.BalanceFlow{
+		{UserID: 1, Currency: "USDT", Amount: "-100"},
+		{UserID: 1, Currency: "BTC", Amount: "0.01"},
+		{UserID: 2, Currency: "BTC", Amount: "-0.01"},
+		{UserID: 2, Currency: "USDT", Amount: "100"},
+	}
+	// 用户2有一笔待审核的提现，金额和手续费仍在冻结余额中
+	withdrawHolds := []*model.BalanceFlow{
+		{UserID: 2, Currency: "USDT", Amount: "30"},
+	}
+	openOrders := []*model.Order{
+		{ID: 1, UserID: 1, Symbol: "BTC/USDT", Type: 1, Side: 1, Price: "10000", Amount: "0.05", FilledAmount: "0.01"},
+		{ID: 2, UserID: 2, Symbol: "BTC/USDT", Type: 1, Side: 2, Price: "12000", Amount: "1", FilledAmount: "0"},
+	}
+	balances := []*model.Balance{
+		{UserID: 1, Currency: "USDT", Available: "500", Frozen: "400"},
+		{UserID: 1, Currency: "BTC", Available: "0.01", Frozen: "0"},
+		{UserID: 2, Currency: "BTC", Available: "0.99", Frozen: "1"},
+		{UserID: 2, Currency: "USDT", Available: "70", Frozen: "30"},
+	}
+
+	accounts, err := buildAccountStates(assetFlows, tradeFlows, withdrawHolds, openOrders, testPairs, balances)
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 4)
+
+	usdt := accounts[accountKey{userID: 1, currency: "USDT"}]
+	assert.Equal(t, "900", usdt.expectedTotal.String())
+	assert.Equal(t, "400", usdt.expectedFrozen.String())
+	assert.Equal(t, "900", usdt.actualTotal.String())
+	assert.Equal(t, "400", usdt.actualFrozen.String())
+
+	pending := accounts[accountKey{userID: 2, currency: "USDT"}]
+	assert.Equal(t, "100", pending.expectedTotal.String())
+	assert.Equal(t, "30", pending.expectedFrozen.String())
+
+	assert.Empty(t, findDiscrepancies(accounts, decimal.Zero))
+}
+
+func TestBuildAccountStates_UnknownPair(t *testing.T) {
+	openOrders := []*model.Order{
+		{ID: 1, UserID: 1, Symbol: "DOGE/USDT", Type: 1, Side: 1, Price: "1", Amount: "1", FilledAmount: "0"},
+	}
+
+	_, err := buildAccountStates(nil, nil, nil, openOrders, testPairs, nil)
+	assert.Error(t, err)
+}
+
+func TestFindDiscrepancies(t *testing.T) {
+	accounts := map[accountKey]*accountState{
+		{userID: 2, currency: "BTC"}: {
+			expectedTotal: decimal.RequireFromString("1"),
+			actualTotal:   decimal.RequireFromString("1.5"),
+		},
+		{userID: 1, currency: "USDT"}: {
+			expectedTotal:  decimal.RequireFromString("100"),
+			actualTotal:    decimal.RequireFromString("100"),
+			expectedFrozen: decimal.RequireFromString("10"),
+			actualFrozen:   decimal.RequireFromString("20"),
+		},
+		{userID: 1, currency: "BTC"}: {
+			expectedTotal: decimal.RequireFromString("1"),
+			actualTotal:   decimal.RequireFromString("1.000000001"),
+		},
+	}
+
+	discrepancies := findDiscrepancies(accounts, decimal.Zero)
+	assert.Len(t, discrepancies, 3)
+	assert.Equal(t, uint64(1), discrepancies[0].UserID)
+	assert.Equal(t, "BTC", discrepancies[0].Currency)
+	assert.Equal(t, "USDT", discrepancies[1].Currency)
+	assert.Equal(t, "20", discrepancies[1].ActualFrozen)
+	assert.Equal(t, uint64(2), discrepancies[2].UserID)
+
+	// 容差内的差额不报告
+	discrepancies = findDiscrepancies(accounts, decimal.RequireFromString("0.00001"))
+	assert.Len(t, discrepancies, 2)
+}
+
+// reconcilerFixture 用户1的USDT余额凭空多出200，用户2的账户平衡
+type reconcilerFixture struct {
+	svcCtx           *svc.ServiceContext
+	balanceModel     *mockBalanceModel
+	userModel        *mockUserModel
+	restrictionModel *memoryRestrictionModel
+	sessions         *memorySessionStore
+	reportModel      *mockReportModel
+	discrepancyModel *mockDiscrepancyModel
+}
+
+func newReconcilerFixture(ctx context.Context) *reconcilerFixture {
+	assetModel := &mockAssetTransactionModel{}
+	tradeModel := &mockTradeModel{}
+	orderModel := &mockOrderModel{}
+	pairModel := &mockTradingPairModel{}
+	f := &reconcilerFixture{
+		balanceModel:     &mockBalanceModel{},
+		userModel:        &mockUserModel{},
+		restrictionModel: &memoryRestrictionModel{},
+		sessions:         &memorySessionStore{},
+		reportModel:      &mockReportModel{},
+		discrepancyModel: &mockDiscrepancyModel{},
+	}
+
+	var c config.Config
+	c.Reconciliation.Tolerance = "0"
+	f.svcCtx = &svc.ServiceContext{
+		Config:                    c,
+		AssetTransactionModel:     assetModel,
+		TradeModel:                tradeModel,
+		OrderModel:                orderModel,
+		TradingPairModel:          pairModel,
+		BalanceModel:              f.balanceModel,
+		UserModel:                 f.userModel,
+		UserRestrictionModel:      f.restrictionModel,
+		Sessions:                  f.sessions,
+		ReconciliationReportModel: f.reportModel,
+		BalanceDiscrepancyModel:   f.discrepancyModel,
+	}
+
+	assetModel.On("SumNetFlowByUserAndCurrency", ctx).Return([]*model.BalanceFlow{
+		{UserID: 1, Currency: "USDT", Amount: "1000"},
+		{UserID: 2, Currency: "USDT", Amount: "50"},
+	}, nil)
+	assetModel.On("SumInFlightWithdrawalsByUserAndCurrency", ctx).Return([]*model.BalanceFlow{}, nil)
+	tradeModel.On("SumNetFlowByUserAndCurrency", ctx).Return([]*model.BalanceFlow{}, nil)
+	orderModel.On("FindByStatus", ctx, int64(1)).Return([]*model.Order{}, nil)
+	orderModel.On("FindByStatus", ctx, int64(2)).Return([]*model.Order{}, nil)
+	pairModel.On("FindAll", ctx).Return(testPairs, nil)
+	f.balanceModel.On("TransRepeatableRead", ctx).Return(nil)
+	f.balanceModel.On("FindAll", ctx).Return([]*model.Balance{
+		{UserID: 1, Currency: "USDT", Available: "1200", Frozen: "0"}, // 凭空多出200
+		{UserID: 2, Currency: "USDT", Available: "50", Frozen: "0"},
+	}, nil)
+
+	f.reportModel.On("Insert", ctx, mock.AnythingOfType("*model.ReconciliationReport")).Return(&mockSqlResult{lastInsertId: 9}, nil)
+	f.reportModel.On("Update", ctx, mock.MatchedBy(func(r *model.ReconciliationReport) bool {
+		return r.ID == 9 && r.Status == model.ReconciliationStatusCompleted && r.FinishedAt.Valid
+	})).Return(nil)
+	return f
+}
+
+func TestReconciler_Run_AutoFreeze(t *testing.T) {
+	ctx := context.Background()
+	f := newReconcilerFixture(ctx)
+
+	// 上一次对账已发现同一账户不平，本次确认后冻结
+	f.reportModel.On("FindPreviousCompleted", ctx, uint64(9)).Return(&model.ReconciliationReport{ID: 8, Status: model.ReconciliationStatusCompleted}, nil)
+	f.discrepancyModel.On("FindByReportID", ctx, uint64(8)).Return([]*model.BalanceDiscrepancy{
+		{ReportID: 8, UserID: 1, Currency: "USDT", ExpectedTotal: "1000", ActualTotal: "1200"},
+	}, nil)
+	f.userModel.On("FindOne", ctx, uint64(1)).Return(&model.User{ID: 1, Status: model.UserStatusActive}, nil)
+	f.discrepancyModel.On("Insert", ctx, mock.MatchedBy(func(d *model.BalanceDiscrepancy) bool {
+		return d.ReportID == 9 && d.UserID == 1 && d.ExpectedTotal == "1000" && d.ActualTotal == "1200" && d.UserFrozen
+	})).Return(&mockSqlResult{}, nil).Once()
+
+	report, discrepancies, err := NewReconciler(ctx, f.svcCtx).Run(true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), report.CheckedAccounts)
+	assert.Equal(t, int64(1), report.DiscrepancyCount)
+	assert.Equal(t, int64(1), report.FrozenUsers)
+	assert.Len(t, discrepancies, 1)
+	// 通过冻结限制冻结用户并记录审计日志，不修改用户状态
+	if assert.Len(t, f.restrictionModel.restrictions, 1) {
+		assert.Equal(t, uint64(1), f.restrictionModel.restrictions[0].UserID)
+		assert.Equal(t, model.RestrictionFreeze, f.restrictionModel.restrictions[0].Type)
+	}
+	if assert.Len(t, f.restrictionModel.audits, 1) {
+		assert.Equal(t, model.RestrictionAuditActionApply, f.restrictionModel.audits[0].Action)
+		assert.Equal(t, "unbalanced account found by reconciliation report 9", f.restrictionModel.audits[0].Reason)
+	}
+	assert.Equal(t, []uint64{1}, f.sessions.revoked)
+	// 全部数据在同一个可重复读事务中读取
+	f.balanceModel.AssertNumberOfCalls(t, "TransRepeatableRead", 1)
+	f.userModel.AssertExpectations(t)
+	f.reportModel.AssertExpectations(t)
+	f.discrepancyModel.AssertExpectations(t)
+}
+
+func TestReconciler_Run_AutoFreezeAwaitsConfirmation(t *testing.T) {
+	ctx := context.Background()
+	f := newReconcilerFixture(ctx)
+
+	// 上一次对账中该账户平衡，本次发现的差异只记录，不冻结用户
+	f.reportModel.On("FindPreviousCompleted", ctx, uint64(9)).Return(&model.ReconciliationReport{ID: 8, Status: model.ReconciliationStatusCompleted}, nil)
+	f.discrepancyModel.On("FindByReportID", ctx, uint64(8)).Return([]*model.BalanceDiscrepancy{}, nil)
+	f.discrepancyModel.On("Insert", ctx, mock.MatchedBy(func(d *model.BalanceDiscrepancy) bool {
+		return d.ReportID == 9 && d.UserID == 1 && !d.UserFrozen
+	})).Return(&mockSqlResult{}, nil).Once()
+
+	report, _, err := NewReconciler(ctx, f.svcCtx).Run(true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), report.DiscrepancyCount)
+	assert.Equal(t, int64(0), report.FrozenUsers)
+	assert.Empty(t, f.restrictionModel.restrictions)
+	assert.Empty(t, f.sessions.revoked)
+	f.userModel.AssertNotCalled(t, "FindOne", mock.Anything, mock.Anything)
+	f.discrepancyModel.AssertExpectations(t)
+
+	// 没有上一次对账时同样不冻结
+	f = newReconcilerFixture(ctx)
+	f.reportModel.On("FindPreviousCompleted", ctx, uint64(9)).Return(nil, model.ErrNotFound)
+	f.discrepancyModel.On("Insert", ctx, mock.Anything).Return(&mockSqlResult{}, nil)
+	report, _, err = NewReconciler(ctx, f.svcCtx).Run(true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), report.FrozenUsers)
+	assert.Empty(t, f.restrictionModel.restrictions)
+}
@@ -0,0 +1,104 @@
+package reserves
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetReserveProofLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetReserveProofLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetReserveProofLogic {
+	return &GetReserveProofLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetReserveProof 返回当前用户在指定币种最新快照中的叶子和包含证明
+func (l *GetReserveProofLogic) GetReserveProof(req *types.ReserveProofRequest) (resp *types.ReserveProofResponse, err error) {
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		l.Errorf("Failed to get user ID from context: %v", err)
+		return nil, model.ErrUnauthorized
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if currency == "" {
+		return nil, model.ErrInvalidParams
+	}
+
+	snapshot, err := l.svcCtx.ReserveSnapshotModel.FindLatestByCurrency(l.ctx, currency)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrReserveSnapshotNotFound
+		}
+		l.Errorf("Failed to get proof of reserves snapshot for %s: %v", currency, err)
+		return nil, model.ErrInternalServer
+	}
+
+	leaf, err := l.svcCtx.ReserveSnapshotLeafModel.FindBySnapshotIDAndUserID(l.ctx, snapshot.ID, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrReserveProofNotFound
+		}
+		l.Errorf("Failed to get proof of reserves leaf for user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	// 从保存的叶子重建求和树，并确认与公布的根一致
+	leaves, err := l.svcCtx.ReserveSnapshotLeafModel.FindBySnapshotID(l.ctx, snapshot.ID)
+	if err != nil {
+		l.Errorf("Failed to get leaves of snapshot %d: %v", snapshot.ID, err)
+		return nil, model.ErrInternalServer
+	}
+	tree, err := BuildTree(leaves)
+	if err != nil {
+		l.Errorf("Failed to rebuild merkle sum tree of snapshot %d: %v", snapshot.ID, err)
+		return nil, model.ErrInternalServer
+	}
+	if tree.Root().Hash != snapshot.RootHash {
+		l.Errorf("Rebuilt root of snapshot %d does not match published root", snapshot.ID)
+		return nil, model.ErrInternalServer
+	}
+
+	path, err := tree.ProofPath(int(leaf.LeafIndex))
+	if err != nil {
+		l.Errorf("Failed to build proof path for leaf %d of snapshot %d: %v", leaf.LeafIndex, snapshot.ID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	resp = &types.ReserveProofResponse{
+		SnapshotID:       snapshot.ID,
+		Currency:         snapshot.Currency,
+		RootHash:         snapshot.RootHash,
+		TotalLiabilities: snapshot.TotalLiabilities,
+		LeafIndex:        leaf.LeafIndex,
+		UserHash:         leaf.UserHash,
+		Salt:             leaf.Salt,
+		Balance:          leaf.Balance,
+		Path:             make([]types.ReserveProofNode, 0, len(path)),
+		CreatedAt:        snapshot.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	for _, node := range path {
+		resp.Path = append(resp.Path, types.ReserveProofNode{
+			Hash:     node.Hash,
+			Sum:      node.Sum,
+			Position: node.Position,
+		})
+	}
+
+	return resp, nil
+}
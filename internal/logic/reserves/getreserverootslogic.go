@@ -0,0 +1,54 @@
+package reserves
+
+import (
+	"context"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetReserveRootsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetReserveRootsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetReserveRootsLogic {
+	return &GetReserveRootsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetReserveRoots 公开最近一批快照的根哈希和各币种负债总额
+func (l *GetReserveRootsLogic) GetReserveRoots() (resp *types.ReserveRootsResponse, err error) {
+	snapshots, err := l.svcCtx.ReserveSnapshotModel.FindLatestBatch(l.ctx)
+	if err != nil {
+		l.Errorf("Failed to get latest proof of reserves snapshots: %v", err)
+		return nil, model.ErrInternalServer
+	}
+	if len(snapshots) == 0 {
+		return nil, model.ErrReserveSnapshotNotFound
+	}
+
+	resp = &types.ReserveRootsResponse{
+		BatchID:   snapshots[0].BatchID,
+		CreatedAt: snapshots[0].CreatedAt.Format("2006-01-02 15:04:05"),
+		Roots:     make([]types.ReserveRoot, 0, len(snapshots)),
+	}
+	for _, snapshot := range snapshots {
+		resp.Roots = append(resp.Roots, types.ReserveRoot{
+			SnapshotID:       snapshot.ID,
+			Currency:         snapshot.Currency,
+			RootHash:         snapshot.RootHash,
+			TotalLiabilities: snapshot.TotalLiabilities,
+			LeafCount:        snapshot.LeafCount,
+		})
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,158 @@
+package reserves
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"crypto-exchange/internal/por"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+type mockSqlResult struct {
+	lastInsertId int64
+}
+
+func (m *mockSqlResult) LastInsertId() (int64, error) {
+	return m.lastInsertId, nil
+}
+
+func (m *mockSqlResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+type mockBalanceModel struct {
+	model.BalanceModel
+	mock.Mock
+}
+
+func (m *mockBalanceModel) FindAll(ctx context.Context) ([]*model.Balance, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*model.Balance), args.Error(1)
+}
+
+func (m *mockBalanceModel) TransRepeatableRead(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	m.Called(ctx)
+	return fn(ctx, nil)
+}
+
+func (m *mockBalanceModel) WithSession(session sqlx.Session) model.BalanceModel {
+	return m
+}
+
+// memorySnapshotStore 内存中的快照存储，同时实现快照和叶子模型用到的方法
+type memorySnapshotStore struct {
+	model.ReserveSnapshotModel
+	snapshots []*model.ReserveSnapshot
+}
+
+func (m *memorySnapshotStore) Insert(ctx context.Context, data *model.ReserveSnapshot) (sql.Result, error) {
+	m.snapshots = append(m.snapshots, data)
+	return &mockSqlResult{lastInsertId: int64(len(m.snapshots))}, nil
+}
+
+func (m *memorySnapshotStore) FindLatestByCurrency(ctx context.Context, currency string) (*model.ReserveSnapshot, error) {
+	for i := len(m.snapshots) - 1; i >= 0; i-- {
+		if m.snapshots[i].Currency == currency {
+			return m.snapshots[i], nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *memorySnapshotStore) WithSession(session sqlx.Session) model.ReserveSnapshotModel {
+	return m
+}
+
+type memoryLeafStore struct {
+	model.ReserveSnapshotLeafModel
+	leaves []*model.ReserveSnapshotLeaf
+}
+
+func (m *memoryLeafStore) Insert(ctx context.Context, data *model.ReserveSnapshotLeaf) (sql.Result, error) {
+	m.leaves = append(m.leaves, data)
+	return &mockSqlResult{lastInsertId: int64(len(m.leaves))}, nil
+}
+
+func (m *memoryLeafStore) FindBySnapshotID(ctx context.Context, snapshotID uint64) ([]*model.ReserveSnapshotLeaf, error) {
+	var resp []*model.ReserveSnapshotLeaf
+	for _, leaf := range m.leaves {
+		if leaf.SnapshotID == snapshotID {
+			resp = append(resp, leaf)
+		}
+	}
+	return resp, nil
+}
+
+func (m *memoryLeafStore) FindBySnapshotIDAndUserID(ctx context.Context, snapshotID, userID uint64) (*model.ReserveSnapshotLeaf, error) {
+	for _, leaf := range m.leaves {
+		if leaf.SnapshotID == snapshotID && leaf.UserID == userID {
+			return leaf, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *memoryLeafStore) WithSession(session sqlx.Session) model.ReserveSnapshotLeafModel {
+	return m
+}
+
+func TestSnapshotAndProof(t *testing.T) {
+	balanceModel := &mockBalanceModel{}
+	snapshotStore := &memorySnapshotStore{}
+	leafStore := &memoryLeafStore{}
+	svcCtx := &svc.ServiceContext{
+		BalanceModel:             balanceModel,
+		ReserveSnapshotModel:     snapshotStore,
+		ReserveSnapshotLeafModel: leafStore,
+	}
+
+	balanceModel.On("TransRepeatableRead", mock.Anything).Return()
+	balanceModel.On("FindAll", mock.Anything).Return([]*model.Balance{
+		{UserID: 3, Currency: "BTC", Available: "0.5", Frozen: "0.25"},
+		{UserID: 1, Currency: "BTC", Available: "1", Frozen: "0"},
+		{UserID: 2, Currency: "BTC", Available: "0", Frozen: "0"}, // 零余额不进入快照
+		{UserID: 1, Currency: "USDT", Available: "100", Frozen: "20"},
+		{UserID: 2, Currency: "USDT", Available: "5", Frozen: "0"},
+		{UserID: 4, Currency: "USDT", Available: "-1", Frozen: "0"}, // 负余额被跳过
+	}, nil)
+
+	snapshots, err := NewSnapshotJob(context.Background(), svcCtx).Run()
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 2)
+	assert.Equal(t, "BTC", snapshots[0].Currency)
+	assert.Equal(t, "1.75", snapshots[0].TotalLiabilities)
+	assert.Equal(t, int64(2), snapshots[0].LeafCount)
+	assert.Equal(t, "USDT", snapshots[1].Currency)
+	assert.Equal(t, "125", snapshots[1].TotalLiabilities)
+	assert.Equal(t, snapshots[0].BatchID, snapshots[1].BatchID)
+	balanceModel.AssertExpectations(t)
+
+	// 用户3获取BTC证明，并按离线验证工具的方式验证
+	ctx := context.WithValue(context.Background(), "userId", float64(3))
+	resp, err := NewGetReserveProofLogic(ctx, svcCtx).GetReserveProof(&types.ReserveProofRequest{Currency: "btc"})
+	assert.NoError(t, err)
+	assert.Equal(t, "0.75", resp.Balance)
+
+	data, err := json.Marshal(resp)
+	assert.NoError(t, err)
+	var proof por.Proof
+	assert.NoError(t, json.Unmarshal(data, &proof))
+	assert.NoError(t, por.VerifyProof(&proof, 3))
+	assert.Error(t, por.VerifyProof(&proof, 1))
+
+	// 没有余额的用户没有叶子
+	ctx = context.WithValue(context.Background(), "userId", float64(2))
+	_, err = NewGetReserveProofLogic(ctx, svcCtx).GetReserveProof(&types.ReserveProofRequest{Currency: "BTC"})
+	assert.ErrorIs(t, err, model.ErrReserveProofNotFound)
+
+	_, err = NewGetReserveProofLogic(ctx, svcCtx).GetReserveProof(&types.ReserveProofRequest{Currency: "ETH"})
+	assert.ErrorIs(t, err, model.ErrReserveSnapshotNotFound)
+}
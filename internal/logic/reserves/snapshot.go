@@ -0,0 +1,199 @@
+package reserves
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"crypto-exchange/internal/por"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+// SnapshotJob 储备金证明快照任务
+// 按币种汇总所有用户余额(available+frozen)，构建Merkle求和树并保存根哈希、负债总额和全部叶子
+type SnapshotJob struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// NewSnapshotJob 创建储备金证明快照任务
+func NewSnapshotJob(ctx context.Context, svcCtx *svc.ServiceContext) *SnapshotJob {
+	return &SnapshotJob{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Run 生成一批快照，每个有余额的币种一条，返回生成的快照
+// 余额读取和快照写入在同一个可重复读事务中完成，所有币种的负债都来自同一时间点
+func (j *SnapshotJob) Run() ([]*model.ReserveSnapshot, error) {
+	batchID := uuid.New().String()
+	now := time.Now()
+	var snapshots []*model.ReserveSnapshot
+
+	err := j.svcCtx.BalanceModel.TransRepeatableRead(j.ctx, func(ctx context.Context, session sqlx.Session) error {
+		balances, err := j.svcCtx.BalanceModel.WithSession(session).FindAll(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get balances: %w", err)
+		}
+
+		leavesByCurrency, err := j.buildLeaves(balances)
+		if err != nil {
+			return err
+		}
+
+		currencies := make([]string, 0, len(leavesByCurrency))
+		for currency := range leavesByCurrency {
+			currencies = append(currencies, currency)
+		}
+		sort.Strings(currencies)
+
+		snapshotModel := j.svcCtx.ReserveSnapshotModel.WithSession(session)
+		leafModel := j.svcCtx.ReserveSnapshotLeafModel.WithSession(session)
+		snapshots = make([]*model.ReserveSnapshot, 0, len(currencies))
+		for _, currency := range currencies {
+			snapshot, err := j.saveSnapshot(ctx, snapshotModel, leafModel, batchID, currency, leavesByCurrency[currency], now)
+			if err != nil {
+				return err
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	j.Infof("Proof of reserves batch %s created with %d currencies", batchID, len(snapshots))
+	return snapshots, nil
+}
+
+// buildLeaves 按币种生成叶子，叶子按用户ID排序，每个叶子使用独立的随机盐值；零余额不进入快照
+func (j *SnapshotJob) buildLeaves(balances []*model.Balance) (map[string][]*model.ReserveSnapshotLeaf, error) {
+	sort.Slice(balances, func(a, b int) bool {
+		if balances[a].Currency != balances[b].Currency {
+			return balances[a].Currency < balances[b].Currency
+		}
+		return balances[a].UserID < balances[b].UserID
+	})
+
+	leavesByCurrency := make(map[string][]*model.ReserveSnapshotLeaf)
+	for _, balance := range balances {
+		available, err := decimal.NewFromString(balance.Available)
+		if err != nil {
+			return nil, fmt.Errorf("invalid available balance of user %d %s: %s", balance.UserID, balance.Currency, balance.Available)
+		}
+		frozen, err := decimal.NewFromString(balance.Frozen)
+		if err != nil {
+			return nil, fmt.Errorf("invalid frozen balance of user %d %s: %s", balance.UserID, balance.Currency, balance.Frozen)
+		}
+
+		total := available.Add(frozen)
+		if total.IsZero() {
+			continue
+		}
+		if total.IsNegative() {
+			// 负余额无法放入求和树，需要通过对账任务排查
+			j.Errorf("Skip negative balance of user %d %s in proof of reserves: %s", balance.UserID, balance.Currency, total.String())
+			continue
+		}
+
+		salt, err := por.NewSalt()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %w", err)
+		}
+
+		leaves := leavesByCurrency[balance.Currency]
+		leavesByCurrency[balance.Currency] = append(leaves, &model.ReserveSnapshotLeaf{
+			LeafIndex: int64(len(leaves)),
+			UserID:    balance.UserID,
+			UserHash:  por.HashUserID(balance.UserID, salt),
+			Salt:      salt,
+			Balance:   total.String(),
+		})
+	}
+
+	return leavesByCurrency, nil
+}
+
+// saveSnapshot 构建单个币种的求和树，通过事务内的模型保存快照和叶子
+func (j *SnapshotJob) saveSnapshot(ctx context.Context, snapshotModel model.ReserveSnapshotModel, leafModel model.ReserveSnapshotLeafModel, batchID, currency string, leaves []*model.ReserveSnapshotLeaf, now time.Time) (*model.ReserveSnapshot, error) {
+	tree, err := BuildTree(leaves)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build merkle sum tree for %s: %w", currency, err)
+	}
+	root := tree.Root()
+
+	snapshot := &model.ReserveSnapshot{
+		BatchID:          batchID,
+		Currency:         currency,
+		RootHash:         root.Hash,
+		TotalLiabilities: root.Sum.String(),
+		LeafCount:        int64(len(leaves)),
+		CreatedAt:        now,
+	}
+	result, err := snapshotModel.Insert(ctx, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save snapshot for %s: %w", currency, err)
+	}
+	snapshotID, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	snapshot.ID = uint64(snapshotID)
+
+	for _, leaf := range leaves {
+		leaf.SnapshotID = snapshot.ID
+		if _, err := leafModel.Insert(ctx, leaf); err != nil {
+			return nil, fmt.Errorf("failed to save snapshot leaf of user %d %s: %w", leaf.UserID, currency, err)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// BuildTree 使用快照叶子构建Merkle求和树，叶子需按叶子序号排列
+func BuildTree(leaves []*model.ReserveSnapshotLeaf) (*por.Tree, error) {
+	input := make([]por.Leaf, 0, len(leaves))
+	for _, leaf := range leaves {
+		input = append(input, por.Leaf{UserHash: leaf.UserHash, Balance: leaf.Balance})
+	}
+	return por.BuildTree(input)
+}
+
+// StartSnapshotScheduler 启动定时储备金证明快照任务，interval<=0时不启动，返回停止函数
+func StartSnapshotScheduler(svcCtx *svc.ServiceContext, interval time.Duration) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	if interval <= 0 {
+		return cancel
+	}
+
+	job := NewSnapshotJob(ctx, svcCtx)
+	threading.GoSafe(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := job.Run(); err != nil {
+					logx.Errorf("Failed to create proof of reserves snapshot: %v", err)
+				}
+			}
+		}
+	})
+
+	return cancel
+}
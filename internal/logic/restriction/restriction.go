@@ -0,0 +1,53 @@
+// Package restriction 账户限制的校验。
+//
+// 合规管控通过 user_restrictions 表对账户施加禁止登录、禁止下单、禁止提现或冻结账户的限制，
+// 冻结账户包含全部限制；限制可以设置到期时间，过期后自动失效。被禁用或注销的用户视为受全部限制，
+// 保证已签发的JWT和API密钥在用户状态变更后也不能继续交易和提现。
+package restriction
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// Check 校验用户当前是否受restrictionType类型的限制，被禁用或注销的用户返回ErrUserDisabled
+func Check(ctx context.Context, svcCtx *svc.ServiceContext, user *model.User, restrictionType string) error {
+	if user.Status != model.UserStatusActive {
+		return model.ErrUserDisabled
+	}
+	return CheckRestrictions(ctx, svcCtx, user.ID, restrictionType)
+}
+
+// CheckUserID 按用户ID查询用户后校验，用于调用方没有加载用户信息的场景
+func CheckUserID(ctx context.Context, svcCtx *svc.ServiceContext, userID uint64, restrictionType string) error {
+	user, err := svcCtx.UserModel.FindOne(ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return model.ErrUserNotFound
+		}
+		logx.WithContext(ctx).Errorf("Failed to find user %d: %v", userID, err)
+		return model.ErrInternalServer
+	}
+	return Check(ctx, svcCtx, user, restrictionType)
+}
+
+// CheckRestrictions 只校验账户限制，不校验用户状态，受限时返回*model.AccountRestrictedError
+// 查询限制失败时拒绝操作，避免存储故障时绕过合规限制
+func CheckRestrictions(ctx context.Context, svcCtx *svc.ServiceContext, userID uint64, restrictionType string) error {
+	restrictions, err := svcCtx.UserRestrictionModel.FindActiveByUserID(ctx, userID, time.Now())
+	if err != nil {
+		logx.WithContext(ctx).Errorf("Failed to find restrictions of user %d: %v", userID, err)
+		return model.ErrInternalServer
+	}
+	if restricted := model.MatchRestriction(restrictions, restrictionType); restricted != nil {
+		logx.WithContext(ctx).Infof("User %d denied by %s restriction when checking %s", userID, restricted.Type, restrictionType)
+		return restricted
+	}
+	return nil
+}
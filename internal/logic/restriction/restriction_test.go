@@ -0,0 +1,71 @@
+package restriction
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryRestrictionModel struct {
+	model.UserRestrictionModel
+	restrictions []*model.UserRestriction
+	err          error
+}
+
+func (m *memoryRestrictionModel) FindActiveByUserID(ctx context.Context, userID uint64, now time.Time) ([]*model.UserRestriction, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var active []*model.UserRestriction
+	for _, r := range m.restrictions {
+		if r.UserID == userID && (!r.ExpiresAt.Valid || r.ExpiresAt.Time.After(now)) {
+			active = append(active, r)
+		}
+	}
+	return active, nil
+}
+
+func TestCheck(t *testing.T) {
+	until := time.Now().Add(time.Hour)
+	restrictions := &memoryRestrictionModel{restrictions: []*model.UserRestriction{
+		{UserID: 1, Type: model.RestrictionWithdraw, ExpiresAt: sql.NullTime{Time: until, Valid: true}},
+		{UserID: 1, Type: model.RestrictionTrading, ExpiresAt: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true}},
+		{UserID: 2, Type: model.RestrictionFreeze},
+	}}
+	svcCtx := &svc.ServiceContext{UserRestrictionModel: restrictions}
+	ctx := context.Background()
+	active := &model.User{ID: 1, Status: model.UserStatusActive}
+
+	err := Check(ctx, svcCtx, active, model.RestrictionWithdraw)
+	assert.ErrorIs(t, err, model.ErrAccountRestricted)
+	var restricted *model.AccountRestrictedError
+	if assert.True(t, errors.As(err, &restricted)) {
+		assert.Equal(t, model.RestrictionWithdraw, restricted.Type)
+		assert.True(t, restricted.ExpiresAt.Equal(until))
+	}
+
+	// 已过期的限制不生效，其它类型的限制互不影响
+	assert.NoError(t, Check(ctx, svcCtx, active, model.RestrictionTrading))
+	assert.NoError(t, Check(ctx, svcCtx, active, model.RestrictionLogin))
+
+	// 冻结账户包含全部限制
+	frozen := &model.User{ID: 2, Status: model.UserStatusActive}
+	for _, restrictionType := range []string{model.RestrictionLogin, model.RestrictionTrading, model.RestrictionWithdraw} {
+		assert.ErrorIs(t, Check(ctx, svcCtx, frozen, restrictionType), model.ErrAccountRestricted)
+	}
+
+	// 被禁用的用户视为受全部限制
+	disabled := &model.User{ID: 3, Status: model.UserStatusDisabled}
+	assert.ErrorIs(t, Check(ctx, svcCtx, disabled, model.RestrictionTrading), model.ErrUserDisabled)
+
+	// 查询限制失败时拒绝操作
+	restrictions.err = errors.New("connection refused")
+	assert.ErrorIs(t, Check(ctx, svcCtx, active, model.RestrictionLogin), model.ErrInternalServer)
+}
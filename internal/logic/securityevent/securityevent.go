@@ -0,0 +1,85 @@
+// Package securityevent 账户安全事件记录：登录成功和失败、重置密码、两步验证、API密钥和提现地址变更。
+//
+// 事件的来源IP、User-Agent和设备指纹取自ClientInfo中间件写入上下文的请求来源信息。
+// 记录失败不影响业务操作，只记录日志。
+package securityevent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"crypto-exchange/internal/clientinfo"
+	"crypto-exchange/internal/notify"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// maxDetailLength 事件详情最大长度（字节），详情中可能包含用户输入的内容
+const maxDetailLength = 256
+
+// Record 记录用户的安全事件，来源信息取自请求上下文
+func Record(ctx context.Context, svcCtx *svc.ServiceContext, userID uint64, eventType, detail string) {
+	info := clientinfo.FromContext(ctx)
+	if len(detail) > maxDetailLength {
+		detail = detail[:maxDetailLength]
+	}
+	_, err := svcCtx.SecurityEventModel.Insert(ctx, &model.SecurityEvent{
+		UserID:            userID,
+		EventType:         eventType,
+		IP:                info.IP,
+		UserAgent:         info.UserAgent,
+		DeviceFingerprint: info.DeviceFingerprint,
+		Detail:            detail,
+		CreatedAt:         time.Now(),
+	})
+	if err != nil {
+		logx.WithContext(ctx).Errorf("Failed to record security event %s of user %d: %v", eventType, userID, err)
+	}
+}
+
+// RecordLogin 记录登录成功事件，统计窗口内未使用过的设备或IP登录时发送新设备登录提醒
+// 用户首次登录不提醒；查询登录历史或发送提醒失败只记录日志。detail为登录方式，如密码登录、两步验证登录
+func RecordLogin(ctx context.Context, svcCtx *svc.ServiceContext, user *model.User, detail string) {
+	cfg := svcCtx.Config.Security
+	if cfg.NewDeviceAlert {
+		alertNewDevice(ctx, svcCtx, user, time.Now().Add(-time.Duration(cfg.KnownDeviceDays)*24*time.Hour))
+	}
+	Record(ctx, svcCtx, user.ID, model.SecurityEventLoginSuccess, detail)
+}
+
+func alertNewDevice(ctx context.Context, svcCtx *svc.ServiceContext, user *model.User, since time.Time) {
+	logger := logx.WithContext(ctx)
+	info := clientinfo.FromContext(ctx)
+	history, err := svcCtx.SecurityEventModel.FindLoginHistory(ctx, user.ID, info.DeviceFingerprint, info.IP, since)
+	if err != nil {
+		logger.Errorf("Failed to find login history of user %d: %v", user.ID, err)
+		return
+	}
+	if history.Total == 0 || (history.DeviceSeen && history.IPSeen) {
+		return
+	}
+
+	reason := "新设备"
+	switch {
+	case history.DeviceSeen:
+		reason = "新IP地址"
+	case !history.IPSeen:
+		reason = "新设备和新IP地址"
+	}
+	err = svcCtx.Notifier.Notify(ctx, notify.Alert{
+		Type:    notify.AlertNewDeviceLogin,
+		UserID:  user.ID,
+		Email:   user.Email,
+		Subject: "新设备登录提醒",
+		Body: fmt.Sprintf("您好 %s：\n\n您的账户于%s从%s登录。\n\nIP地址：%s\n设备：%s\n\n如果这不是您本人的操作，请立即重置密码并在登录会话管理中注销其他会话。\n",
+			user.Nickname, time.Now().Format("2006-01-02 15:04:05"), reason, info.IP, info.UserAgent),
+	})
+	if err != nil {
+		logger.Errorf("Failed to send new device login alert to user %d: %v", user.ID, err)
+		return
+	}
+	logger.Infof("Sent new device login alert to user %d from %s", user.ID, info.IP)
+}
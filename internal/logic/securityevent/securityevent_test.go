@@ -0,0 +1,124 @@
+package securityevent
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/clientinfo"
+	"crypto-exchange/internal/notify"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memorySecurityEventModel 按已写入的登录成功事件计算登录历史
+type memorySecurityEventModel struct {
+	model.SecurityEventModel
+	events []*model.SecurityEvent
+}
+
+func (m *memorySecurityEventModel) Insert(ctx context.Context, data *model.SecurityEvent) (sql.Result, error) {
+	m.events = append(m.events, data)
+	return nil, nil
+}
+
+func (m *memorySecurityEventModel) FindLoginHistory(ctx context.Context, userID uint64, fingerprint, ip string, since time.Time) (*model.LoginHistory, error) {
+	history := &model.LoginHistory{}
+	for _, e := range m.events {
+		if e.UserID != userID || e.EventType != model.SecurityEventLoginSuccess {
+			continue
+		}
+		history.Total++
+		if e.CreatedAt.Before(since) {
+			continue
+		}
+		history.DeviceSeen = history.DeviceSeen || e.DeviceFingerprint == fingerprint
+		history.IPSeen = history.IPSeen || e.IP == ip
+	}
+	return history, nil
+}
+
+func newTestServiceContext() (*svc.ServiceContext, *memorySecurityEventModel, *notify.MemoryNotifier) {
+	events := &memorySecurityEventModel{}
+	notifier := notify.NewMemoryNotifier()
+	svcCtx := &svc.ServiceContext{SecurityEventModel: events, Notifier: notifier}
+	svcCtx.Config.Security.NewDeviceAlert = true
+	svcCtx.Config.Security.KnownDeviceDays = 90
+	return svcCtx, events, notifier
+}
+
+func clientContext(ip, fingerprint string) context.Context {
+	return clientinfo.WithInfo(context.Background(), clientinfo.Info{IP: ip, UserAgent: "test-agent", DeviceFingerprint: fingerprint})
+}
+
+func TestRecord(t *testing.T) {
+	svcCtx, events, _ := newTestServiceContext()
+
+	Record(clientContext("203.0.113.7", "fp1"), svcCtx, 1, model.SecurityEventApiKeyCreated, strings.Repeat("x", 1000))
+	if assert.Len(t, events.events, 1) {
+		e := events.events[0]
+		assert.Equal(t, uint64(1), e.UserID)
+		assert.Equal(t, model.SecurityEventApiKeyCreated, e.EventType)
+		assert.Equal(t, "203.0.113.7", e.IP)
+		assert.Equal(t, "test-agent", e.UserAgent)
+		assert.Equal(t, "fp1", e.DeviceFingerprint)
+		assert.Len(t, e.Detail, maxDetailLength)
+	}
+}
+
+func TestRecordLogin_NewDeviceAlert(t *testing.T) {
+	svcCtx, events, notifier := newTestServiceContext()
+	user := &model.User{ID: 1, Email: "alice@example.com", Nickname: "alice"}
+
+	// 首次登录不提醒
+	RecordLogin(clientContext("203.0.113.7", "fp1"), svcCtx, user, "password")
+	assert.Empty(t, notifier.Alerts())
+
+	// 已知设备和IP不提醒
+	RecordLogin(clientContext("203.0.113.7", "fp1"), svcCtx, user, "password")
+	assert.Empty(t, notifier.Alerts())
+
+	// 已知设备从新IP登录
+	RecordLogin(clientContext("198.51.100.1", "fp1"), svcCtx, user, "password")
+	if assert.Len(t, notifier.Alerts(), 1) {
+		alert := notifier.Alerts()[0]
+		assert.Equal(t, notify.AlertNewDeviceLogin, alert.Type)
+		assert.Equal(t, "alice@example.com", alert.Email)
+		assert.Contains(t, alert.Body, "新IP地址")
+		assert.Contains(t, alert.Body, "198.51.100.1")
+	}
+
+	// 新设备从新IP登录
+	RecordLogin(clientContext("192.0.2.1", "fp2"), svcCtx, user, "password")
+	if assert.Len(t, notifier.Alerts(), 2) {
+		assert.Contains(t, notifier.Alerts()[1].Body, "新设备和新IP地址")
+	}
+
+	// 超出统计窗口的登录记录不再视为已知
+	for _, e := range events.events {
+		e.CreatedAt = e.CreatedAt.AddDate(0, 0, -91)
+	}
+	RecordLogin(clientContext("203.0.113.7", "fp1"), svcCtx, user, "password")
+	assert.Len(t, notifier.Alerts(), 3)
+
+	assert.Len(t, events.events, 5)
+	for _, e := range events.events {
+		assert.Equal(t, model.SecurityEventLoginSuccess, e.EventType)
+		assert.Equal(t, "password", e.Detail)
+	}
+}
+
+func TestRecordLogin_AlertDisabled(t *testing.T) {
+	svcCtx, events, notifier := newTestServiceContext()
+	svcCtx.Config.Security.NewDeviceAlert = false
+	user := &model.User{ID: 1, Email: "alice@example.com"}
+
+	RecordLogin(clientContext("203.0.113.7", "fp1"), svcCtx, user, "password")
+	RecordLogin(clientContext("198.51.100.1", "fp2"), svcCtx, user, "password")
+	assert.Empty(t, notifier.Alerts())
+	assert.Len(t, events.events, 2)
+}
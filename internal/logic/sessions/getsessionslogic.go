@@ -0,0 +1,56 @@
+package sessions
+
+import (
+	"context"
+	"time"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetSessionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetSessionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetSessionsLogic {
+	return &GetSessionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetSessions 查询当前用户的有效登录会话，按登录时间倒序
+func (l *GetSessionsLogic) GetSessions() (resp *types.SessionListResponse, err error) {
+	principal, err := authctx.FromContext(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+	userID := principal.UserID
+
+	sessions, err := l.svcCtx.Sessions.List(l.ctx, userID)
+	if err != nil {
+		l.Errorf("Failed to list sessions of user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	list := make([]types.Session, 0, len(sessions))
+	for _, sess := range sessions {
+		list = append(list, types.Session{
+			ID:         sess.ID,
+			UserAgent:  sess.UserAgent,
+			CreatedAt:  time.Unix(sess.CreatedAt, 0).Format(time.RFC3339),
+			LastUsedAt: time.Unix(sess.LastUsedAt, 0).Format(time.RFC3339),
+			ExpiresAt:  time.Unix(sess.ExpiresAt, 0).Format(time.RFC3339),
+			Current:    sess.ID == principal.SessionID,
+		})
+	}
+
+	return &types.SessionListResponse{Sessions: list}, nil
+}
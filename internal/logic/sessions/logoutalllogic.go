@@ -0,0 +1,45 @@
+package sessions
+
+import (
+	"context"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type LogoutAllLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewLogoutAllLogic(ctx context.Context, svcCtx *svc.ServiceContext) *LogoutAllLogic {
+	return &LogoutAllLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// LogoutAll 注销当前用户在所有设备上的会话，包括当前会话
+func (l *LogoutAllLogic) LogoutAll() (resp *types.BaseResponse, err error) {
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	if err := l.svcCtx.Sessions.RevokeAll(l.ctx, userID); err != nil {
+		l.Errorf("Failed to revoke all sessions of user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	l.Infof("User %d logged out of all sessions", userID)
+	return &types.BaseResponse{
+		Code:    0,
+		Message: "Logged out of all sessions successfully",
+	}, nil
+}
@@ -0,0 +1,47 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/session"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type LogoutLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewLogoutLogic(ctx context.Context, svcCtx *svc.ServiceContext) *LogoutLogic {
+	return &LogoutLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Logout 注销当前会话
+func (l *LogoutLogic) Logout() (resp *types.BaseResponse, err error) {
+	principal, err := authctx.FromContext(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+	userID := principal.UserID
+
+	if err := l.svcCtx.Sessions.Revoke(l.ctx, userID, principal.SessionID); err != nil && !errors.Is(err, session.ErrSessionNotFound) {
+		l.Errorf("Failed to revoke session %s of user %d: %v", principal.SessionID, userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	return &types.BaseResponse{
+		Code:    0,
+		Message: "Logged out successfully",
+	}, nil
+}
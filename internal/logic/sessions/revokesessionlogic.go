@@ -0,0 +1,50 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/session"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RevokeSessionLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRevokeSessionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RevokeSessionLogic {
+	return &RevokeSessionLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RevokeSession 注销当前用户的指定会话，该会话的访问令牌和刷新令牌立即失效
+func (l *RevokeSessionLogic) RevokeSession(req *types.RevokeSessionRequest) (resp *types.BaseResponse, err error) {
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	if err := l.svcCtx.Sessions.Revoke(l.ctx, userID, req.ID); err != nil {
+		if errors.Is(err, session.ErrSessionNotFound) {
+			return nil, model.ErrSessionNotFound
+		}
+		l.Errorf("Failed to revoke session %s of user %d: %v", req.ID, userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	l.Infof("User %d revoked session %s", userID, req.ID)
+	return &types.BaseResponse{
+		Code:    0,
+		Message: "Session revoked successfully",
+	}, nil
+}
@@ -0,0 +1,102 @@
+package sessions
+
+import (
+	"context"
+	"testing"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/session"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memorySessionStore 只覆盖会话接口用到的方法
+type memorySessionStore struct {
+	session.Store
+	sessions map[string]*session.Session
+}
+
+func (m *memorySessionStore) List(ctx context.Context, userID uint64) ([]*session.Session, error) {
+	var resp []*session.Session
+	for _, id := range []string{"s3", "s2", "s1"} {
+		if sess, ok := m.sessions[id]; ok && sess.UserID == userID {
+			resp = append(resp, sess)
+		}
+	}
+	return resp, nil
+}
+
+func (m *memorySessionStore) Revoke(ctx context.Context, userID uint64, sessionID string) error {
+	sess, ok := m.sessions[sessionID]
+	if !ok || sess.UserID != userID {
+		return session.ErrSessionNotFound
+	}
+	delete(m.sessions, sessionID)
+	return nil
+}
+
+func (m *memorySessionStore) RevokeAll(ctx context.Context, userID uint64) error {
+	for id, sess := range m.sessions {
+		if sess.UserID == userID {
+			delete(m.sessions, id)
+		}
+	}
+	return nil
+}
+
+func newTestServiceContext() (*svc.ServiceContext, *memorySessionStore) {
+	store := &memorySessionStore{sessions: map[string]*session.Session{
+		"s1": {ID: "s1", UserID: 1, UserAgent: "laptop", CreatedAt: 1700000000},
+		"s2": {ID: "s2", UserID: 1, UserAgent: "phone", CreatedAt: 1700000100},
+		"s3": {ID: "s3", UserID: 2, UserAgent: "other", CreatedAt: 1700000200},
+	}}
+	return &svc.ServiceContext{Sessions: store}, store
+}
+
+func sessionContext(userID uint64, sessionID string) context.Context {
+	return authctx.WithPrincipal(context.Background(), &authctx.Principal{
+		UserID:    userID,
+		Method:    authctx.MethodJWT,
+		SessionID: sessionID,
+	})
+}
+
+func TestGetSessions(t *testing.T) {
+	svcCtx, _ := newTestServiceContext()
+
+	resp, err := NewGetSessionsLogic(sessionContext(1, "s1"), svcCtx).GetSessions()
+	assert.NoError(t, err)
+	if assert.Len(t, resp.Sessions, 2) {
+		assert.Equal(t, "s2", resp.Sessions[0].ID)
+		assert.False(t, resp.Sessions[0].Current)
+		assert.True(t, resp.Sessions[1].Current)
+	}
+}
+
+func TestRevokeSessionAndLogout(t *testing.T) {
+	svcCtx, store := newTestServiceContext()
+	ctx := sessionContext(1, "s1")
+
+	// 不能注销其他用户的会话
+	_, err := NewRevokeSessionLogic(ctx, svcCtx).RevokeSession(&types.RevokeSessionRequest{ID: "s3"})
+	assert.ErrorIs(t, err, model.ErrSessionNotFound)
+
+	_, err = NewRevokeSessionLogic(ctx, svcCtx).RevokeSession(&types.RevokeSessionRequest{ID: "s2"})
+	assert.NoError(t, err)
+	assert.NotContains(t, store.sessions, "s2")
+
+	_, err = NewLogoutLogic(ctx, svcCtx).Logout()
+	assert.NoError(t, err)
+	assert.NotContains(t, store.sessions, "s1")
+
+	// 重复退出不报错
+	_, err = NewLogoutLogic(ctx, svcCtx).Logout()
+	assert.NoError(t, err)
+
+	_, err = NewLogoutAllLogic(sessionContext(2, "s3"), svcCtx).LogoutAll()
+	assert.NoError(t, err)
+	assert.Empty(t, store.sessions)
+}
@@ -0,0 +1,59 @@
+package subaccount
+
+import (
+	"context"
+	"strings"
+
+	"crypto-exchange/internal/logic/auth"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateSubAccountLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateSubAccountLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateSubAccountLogic {
+	return &CreateSubAccountLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CreateSubAccount 为当前用户创建子账户，子账户有独立的登录凭证、余额和订单
+func (l *CreateSubAccountLogic) CreateSubAccount(req *types.CreateSubAccountRequest) (resp *types.SubAccount, err error) {
+	// 1. 获取母账户
+	master, err := currentMaster(l.ctx, l.svcCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 检查子账户数量上限，已禁用的子账户同样计入
+	count, err := l.svcCtx.UserModel.CountByParentID(l.ctx, master.ID)
+	if err != nil {
+		l.Errorf("Failed to count sub-accounts of user %d: %v", master.ID, err)
+		return nil, model.ErrInternalServer
+	}
+	if count >= l.svcCtx.Config.SubAccount.MaxPerMaster {
+		return nil, model.ErrSubAccountLimitExceeded
+	}
+
+	// 3. 按注册规则创建子账户
+	sub, err := auth.NewRegisterLogic(l.ctx, l.svcCtx).CreateSubAccount(&types.RegisterRequest{
+		Email:    strings.TrimSpace(req.Email),
+		Password: req.Password,
+		Nickname: strings.TrimSpace(req.Nickname),
+	}, master.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	subAccount := toSubAccount(sub)
+	return &subAccount, nil
+}
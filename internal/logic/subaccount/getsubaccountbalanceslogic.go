@@ -0,0 +1,113 @@
+package subaccount
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetSubAccountBalancesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetSubAccountBalancesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetSubAccountBalancesLogic {
+	return &GetSubAccountBalancesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// balanceTotal 单个币种的汇总余额
+type balanceTotal struct {
+	available decimal.Decimal
+	frozen    decimal.Decimal
+	updatedAt time.Time
+}
+
+// GetSubAccountBalances 查询母账户和全部子账户的余额明细，并按币种汇总
+func (l *GetSubAccountBalancesLogic) GetSubAccountBalances() (resp *types.SubAccountBalancesResponse, err error) {
+	master, err := currentMaster(l.ctx, l.svcCtx)
+	if err != nil {
+		return nil, err
+	}
+	subs, err := l.svcCtx.UserModel.FindByParentID(l.ctx, master.ID)
+	if err != nil {
+		l.Errorf("Failed to find sub-accounts of user %d: %v", master.ID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	totals := make(map[string]*balanceTotal)
+	accounts := make([]types.AccountBalances, 0, len(subs)+1)
+	for _, user := range append([]*model.User{master}, subs...) {
+		balances, err := l.svcCtx.BalanceModel.FindByUserID(l.ctx, user.ID)
+		if err != nil {
+			l.Errorf("Failed to find balances for user %d: %v", user.ID, err)
+			return nil, model.ErrInternalServer
+		}
+
+		account := types.AccountBalances{
+			UserID:   user.ID,
+			Email:    user.Email,
+			IsMaster: user.ID == master.ID,
+			Balances: []types.Balance{},
+		}
+		for _, balance := range balances {
+			available, err := decimal.NewFromString(balance.Available)
+			if err != nil {
+				l.Errorf("Invalid available balance for user %d, currency %s: %s", user.ID, balance.Currency, balance.Available)
+				continue
+			}
+			frozen, err := decimal.NewFromString(balance.Frozen)
+			if err != nil {
+				l.Errorf("Invalid frozen balance for user %d, currency %s: %s", user.ID, balance.Currency, balance.Frozen)
+				continue
+			}
+
+			account.Balances = append(account.Balances, types.Balance{
+				Currency:  balance.Currency,
+				Available: balance.Available,
+				Frozen:    balance.Frozen,
+				UpdatedAt: balance.UpdatedAt.Format("2006-01-02 15:04:05"),
+			})
+
+			total, ok := totals[balance.Currency]
+			if !ok {
+				total = &balanceTotal{}
+				totals[balance.Currency] = total
+			}
+			total.available = total.available.Add(available)
+			total.frozen = total.frozen.Add(frozen)
+			if balance.UpdatedAt.After(total.updatedAt) {
+				total.updatedAt = balance.UpdatedAt
+			}
+		}
+		accounts = append(accounts, account)
+	}
+
+	resp = &types.SubAccountBalancesResponse{
+		Totals:   make([]types.Balance, 0, len(totals)),
+		Accounts: accounts,
+	}
+	for currency, total := range totals {
+		resp.Totals = append(resp.Totals, types.Balance{
+			Currency:  currency,
+			Available: total.available.String(),
+			Frozen:    total.frozen.String(),
+			UpdatedAt: total.updatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	sort.Slice(resp.Totals, func(i, j int) bool {
+		return resp.Totals[i].Currency < resp.Totals[j].Currency
+	})
+	return resp, nil
+}
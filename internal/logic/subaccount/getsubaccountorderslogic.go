@@ -0,0 +1,94 @@
+package subaccount
+
+import (
+	"context"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetSubAccountOrdersLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetSubAccountOrdersLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetSubAccountOrdersLogic {
+	return &GetSubAccountOrdersLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetSubAccountOrders 分页查询母账户和全部子账户的订单，指定子账户时只查询该子账户
+func (l *GetSubAccountOrdersLogic) GetSubAccountOrders(req *types.SubAccountOrdersRequest) (resp *types.OrderListResponse, err error) {
+	master, err := currentMaster(l.ctx, l.svcCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 确定查询范围
+	var userIDs []uint64
+	if req.SubUserID != 0 {
+		sub, err := findSubAccount(l.ctx, l.svcCtx, master.ID, req.SubUserID)
+		if err != nil {
+			return nil, err
+		}
+		userIDs = []uint64{sub.ID}
+	} else {
+		subs, err := l.svcCtx.UserModel.FindByParentID(l.ctx, master.ID)
+		if err != nil {
+			l.Errorf("Failed to find sub-accounts of user %d: %v", master.ID, err)
+			return nil, model.ErrInternalServer
+		}
+		userIDs = append(userIDs, master.ID)
+		for _, sub := range subs {
+			userIDs = append(userIDs, sub.ID)
+		}
+	}
+
+	// 设置默认分页参数
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	size := req.Size
+	if size <= 0 {
+		size = 20
+	}
+
+	orders, total, err := l.svcCtx.OrderModel.FindByUserIDsWithPagination(l.ctx, userIDs, req.Symbol, req.Status, page, size)
+	if err != nil {
+		l.Errorf("Failed to find orders of user %d and sub-accounts: %v", master.ID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	orderList := make([]types.Order, 0, len(orders))
+	for _, order := range orders {
+		orderList = append(orderList, types.Order{
+			ID:           order.ID,
+			UserID:       order.UserID,
+			Symbol:       order.Symbol,
+			Type:         order.Type,
+			Side:         order.Side,
+			Amount:       order.Amount,
+			Price:        order.Price,
+			FilledAmount: order.FilledAmount,
+			Status:       order.Status,
+			CreatedAt:    order.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:    order.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &types.OrderListResponse{
+		Orders: orderList,
+		Total:  total,
+		Page:   page,
+		Size:   size,
+	}, nil
+}
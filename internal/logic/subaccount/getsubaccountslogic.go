@@ -0,0 +1,45 @@
+package subaccount
+
+import (
+	"context"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetSubAccountsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetSubAccountsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetSubAccountsLogic {
+	return &GetSubAccountsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetSubAccounts 查询当前用户的全部子账户
+func (l *GetSubAccountsLogic) GetSubAccounts() (resp *types.SubAccountListResponse, err error) {
+	master, err := currentMaster(l.ctx, l.svcCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	subs, err := l.svcCtx.UserModel.FindByParentID(l.ctx, master.ID)
+	if err != nil {
+		l.Errorf("Failed to find sub-accounts of user %d: %v", master.ID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	subAccounts := make([]types.SubAccount, 0, len(subs))
+	for _, sub := range subs {
+		subAccounts = append(subAccounts, toSubAccount(sub))
+	}
+	return &types.SubAccountListResponse{SubAccounts: subAccounts}, nil
+}
@@ -0,0 +1,60 @@
+package subaccount
+
+import (
+	"context"
+	"errors"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+)
+
+// currentMaster 获取当前登录用户作为母账户，子账户不能再管理子账户
+func currentMaster(ctx context.Context, svcCtx *svc.ServiceContext) (*model.User, error) {
+	userID, err := authctx.UserID(ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	master, err := svcCtx.UserModel.FindOne(ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrUserNotFound
+		}
+		return nil, err
+	}
+	if master.Status != model.UserStatusActive {
+		return nil, model.ErrUserDisabled
+	}
+	if master.ParentID != 0 {
+		return nil, model.ErrSubAccountNotAllowed
+	}
+	return master, nil
+}
+
+// findSubAccount 查找母账户下的子账户，不属于该母账户的用户视为不存在
+func findSubAccount(ctx context.Context, svcCtx *svc.ServiceContext, masterID, subUserID uint64) (*model.User, error) {
+	sub, err := svcCtx.UserModel.FindOne(ctx, subUserID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrSubAccountNotFound
+		}
+		return nil, err
+	}
+	if sub.ParentID != masterID {
+		return nil, model.ErrSubAccountNotFound
+	}
+	return sub, nil
+}
+
+// toSubAccount 转换为子账户响应格式
+func toSubAccount(user *model.User) types.SubAccount {
+	return types.SubAccount{
+		ID:        user.ID,
+		Email:     user.Email,
+		Nickname:  user.Nickname,
+		Status:    user.Status,
+		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
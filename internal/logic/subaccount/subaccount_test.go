@@ -0,0 +1,458 @@
+package subaccount
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/config"
+	"crypto-exchange/internal/currency"
+	"crypto-exchange/internal/session"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// 以下内存实现只覆盖子账户逻辑用到的方法
+
+type memoryUserModel struct {
+	model.UserModel
+	users map[uint64]*model.User
+}
+
+func (m *memoryUserModel) FindOne(ctx context.Context, id uint64) (*model.User, error) {
+	user, ok := m.users[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (m *memoryUserModel) FindByParentID(ctx context.Context, parentID uint64) ([]*model.User, error) {
+	var resp []*model.User
+	for id := uint64(1); id <= uint64(len(m.users)); id++ {
+		if user, ok := m.users[id]; ok && user.ParentID == parentID {
+			resp = append(resp, user)
+		}
+	}
+	return resp, nil
+}
+
+func (m *memoryUserModel) CountByParentID(ctx context.Context, parentID uint64) (int64, error) {
+	subs, _ := m.FindByParentID(ctx, parentID)
+	return int64(len(subs)), nil
+}
+
+func (m *memoryUserModel) UpdateStatus(ctx context.Context, id uint64, status int64) error {
+	m.users[id].Status = status
+	return nil
+}
+
+type memoryBalanceModel struct {
+	model.BalanceModel
+	balances map[uint64]map[string]*model.Balance
+}
+
+func (m *memoryBalanceModel) FindByUserID(ctx context.Context, userID uint64) ([]*model.Balance, error) {
+	var resp []*model.Balance
+	for _, balance := range m.balances[userID] {
+		resp = append(resp, balance)
+	}
+	return resp, nil
+}
+
+func (m *memoryBalanceModel) FindByUserIDAndCurrency(ctx context.Context, userID uint64, currency string) (*model.Balance, error) {
+	balance, ok := m.balances[userID][currency]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return balance, nil
+}
+
+// AddAvailable 与数据库实现一致：没有余额记录时创建，结果为负时返回余额不足
+func (m *memoryBalanceModel) AddAvailable(ctx context.Context, userID uint64, currency string, amount string) error {
+	delta, err := decimal.NewFromString(amount)
+	if err != nil {
+		return err
+	}
+	balance, ok := m.balances[userID][currency]
+	if !ok {
+		if delta.IsNegative() {
+			return model.ErrNotFound
+		}
+		if m.balances[userID] == nil {
+			m.balances[userID] = make(map[string]*model.Balance)
+		}
+		balance = &model.Balance{UserID: userID, Currency: currency, Available: "0", Frozen: "0"}
+		m.balances[userID][currency] = balance
+	}
+	available := decimal.RequireFromString(balance.Available).Add(delta)
+	if available.IsNegative() {
+		return model.ErrInsufficientBalance
+	}
+	balance.Available = available.String()
+	return nil
+}
+
+func (m *memoryBalanceModel) Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	return fn(ctx, nil)
+}
+
+func (m *memoryBalanceModel) WithSession(session sqlx.Session) model.BalanceModel {
+	return m
+}
+
+type memoryAssetTransactionModel struct {
+	model.AssetTransactionModel
+	records []*model.AssetTransaction
+}
+
+func (m *memoryAssetTransactionModel) Insert(ctx context.Context, data *model.AssetTransaction) (sql.Result, error) {
+	m.records = append(m.records, data)
+	return nil, nil
+}
+
+func (m *memoryAssetTransactionModel) WithSession(session sqlx.Session) model.AssetTransactionModel {
+	return m
+}
+
+type memoryLedgerEntryModel struct {
+	model.LedgerEntryModel
+	journals []*model.LedgerJournal
+}
+
+func (m *memoryLedgerEntryModel) InsertJournal(ctx context.Context, journal *model.LedgerJournal) error {
+	if err := journal.Validate(); err != nil {
+		return err
+	}
+	m.journals = append(m.journals, journal)
+	return nil
+}
+
+func (m *memoryLedgerEntryModel) WithSession(session sqlx.Session) model.LedgerEntryModel {
+	return m
+}
+
+type staticCurrencyModel struct {
+	model.CurrencyModel
+}
+
+func (m *staticCurrencyModel) FindAll(ctx context.Context) ([]*model.Currency, error) {
+	return []*model.Currency{{Code: "USDT", Name: "Tether", Precision: 8, Status: model.CurrencyStatusEnabled}}, nil
+}
+
+type staticCurrencyNetworkModel struct {
+	model.CurrencyNetworkModel
+}
+
+func (m *staticCurrencyNetworkModel) FindAll(ctx context.Context) ([]*model.CurrencyNetwork, error) {
+	return nil, nil
+}
+
+type subAccountFixture struct {
+	svcCtx       *svc.ServiceContext
+	users        *memoryUserModel
+	balances     *memoryBalanceModel
+	txs          *memoryAssetTransactionModel
+	ledger       *memoryLedgerEntryModel
+	restrictions *memoryRestrictionModel
+	sessions     *memorySessionStore
+	apiKeys      *memoryApiKeyModel
+	events       *memorySecurityEventModel
+}
+
+// newSubAccountFixture 用户1为母账户，用户2、3为其子账户，用户4为其他用户的子账户
+func newSubAccountFixture() *subAccountFixture {
+	now := time.Now()
+	f := &subAccountFixture{
+		users: &memoryUserModel{users: map[uint64]*model.User{
+			1: {ID: 1, Email: "master@example.com", Status: model.UserStatusActive},
+			2: {ID: 2, Email: "grid@example.com", Status: model.UserStatusActive, ParentID: 1},
+			3: {ID: 3, Email: "arb@example.com", Status: model.UserStatusDisabled, ParentID: 1},
+			4: {ID: 4, Email: "other@example.com", Status: model.UserStatusActive, ParentID: 5},
+			5: {ID: 5, Email: "other-master@example.com", Status: model.UserStatusActive},
+		}},
+		balances: &memoryBalanceModel{balances: map[uint64]map[string]*model.Balance{
+			1: {"USDT": {UserID: 1, Currency: "USDT", Available: "1000", Frozen: "0", UpdatedAt: now}},
+			2: {"USDT": {UserID: 2, Currency: "USDT", Available: "50", Frozen: "10", UpdatedAt: now},
+				"BTC": {UserID: 2, Currency: "BTC", Available: "0.5", Frozen: "0", UpdatedAt: now}},
+		}},
+		txs:          &memoryAssetTransactionModel{},
+		ledger:       &memoryLedgerEntryModel{},
+		restrictions: &memoryRestrictionModel{},
+		sessions:     &memorySessionStore{},
+		apiKeys:      &memoryApiKeyModel{active: map[uint64]int64{2: 2}},
+		events:       &memorySecurityEventModel{},
+	}
+
+	var c config.Config
+	c.SubAccount.MaxPerMaster = 2
+	f.svcCtx = &svc.ServiceContext{
+		UserRestrictionModel:  f.restrictions,
+		Config:                c,
+		UserModel:             f.users,
+		BalanceModel:          f.balances,
+		AssetTransactionModel: f.txs,
+		LedgerEntryModel:      f.ledger,
+		Sessions:              f.sessions,
+		ApiKeyModel:           f.apiKeys,
+		SecurityEventModel:    f.events,
+		OrderModel:            &emptyOrderModel{},
+		CurrencyRegistry:      currency.NewRegistry(&staticCurrencyModel{}, &staticCurrencyNetworkModel{}, time.Minute),
+	}
+	return f
+}
+
+func userContext(userID uint64) context.Context {
+	return context.WithValue(context.Background(), "userId", float64(userID))
+}
+
+func TestCreateSubAccount_Restrictions(t *testing.T) {
+	f := newSubAccountFixture()
+
+	// 子账户不能创建子账户
+	_, err := NewCreateSubAccountLogic(userContext(2), f.svcCtx).CreateSubAccount(&types.CreateSubAccountRequest{
+		Email: "nested@example.com", Password: "secret123",
+	})
+	assert.ErrorIs(t, err, model.ErrSubAccountNotAllowed)
+
+	// 已禁用的子账户同样计入数量上限
+	_, err = NewCreateSubAccountLogic(userContext(1), f.svcCtx).CreateSubAccount(&types.CreateSubAccountRequest{
+		Email: "third@example.com", Password: "secret123",
+	})
+	assert.ErrorIs(t, err, model.ErrSubAccountLimitExceeded)
+}
+
+func TestSubAccountTransfer(t *testing.T) {
+	f := newSubAccountFixture()
+	logic := NewSubAccountTransferLogic(userContext(1), f.svcCtx)
+
+	resp, err := logic.SubAccountTransfer(&types.SubAccountTransferRequest{
+		SubUserID: 2, Direction: TransferDirectionToSub, Currency: "usdt", Amount: "100",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), resp.FromUserID)
+	assert.Equal(t, uint64(2), resp.ToUserID)
+	assert.Equal(t, "900", f.balances.balances[1]["USDT"].Available)
+	assert.Equal(t, "150", f.balances.balances[2]["USDT"].Available)
+	if assert.Len(t, f.txs.records, 2) {
+		assert.Equal(t, model.AssetTransactionTypeSubAccountOut, f.txs.records[0].Type)
+		assert.Equal(t, model.AssetTransactionTypeSubAccountIn, f.txs.records[1].Type)
+	}
+	assert.Len(t, f.ledger.journals, 1)
+
+	// 已禁用的子账户可以把余额转回母账户，但不能再转入
+	f.balances.balances[3] = map[string]*model.Balance{"USDT": {UserID: 3, Currency: "USDT", Available: "20", Frozen: "0"}}
+	_, err = logic.SubAccountTransfer(&types.SubAccountTransferRequest{
+		SubUserID: 3, Direction: TransferDirectionToMaster, Currency: "USDT", Amount: "20",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "920", f.balances.balances[1]["USDT"].Available)
+	_, err = logic.SubAccountTransfer(&types.SubAccountTransferRequest{
+		SubUserID: 3, Direction: TransferDirectionToSub, Currency: "USDT", Amount: "1",
+	})
+	assert.ErrorIs(t, err, model.ErrSubAccountDisabled)
+	assert.Equal(t, "0", f.balances.balances[3]["USDT"].Available)
+
+	// 已删除的子账户同样不能转入
+	f.users.users[2].Status = model.UserStatusDeleted
+	_, err = logic.SubAccountTransfer(&types.SubAccountTransferRequest{
+		SubUserID: 2, Direction: TransferDirectionToSub, Currency: "USDT", Amount: "1",
+	})
+	assert.ErrorIs(t, err, model.ErrSubAccountDeleted)
+	f.users.users[2].Status = model.UserStatusActive
+
+	// 不能操作其他母账户的子账户
+	_, err = logic.SubAccountTransfer(&types.SubAccountTransferRequest{
+		SubUserID: 4, Direction: TransferDirectionToSub, Currency: "USDT", Amount: "1",
+	})
+	assert.ErrorIs(t, err, model.ErrSubAccountNotFound)
+
+	_, err = logic.SubAccountTransfer(&types.SubAccountTransferRequest{
+		SubUserID: 2, Direction: 3, Currency: "USDT", Amount: "1",
+	})
+	assert.ErrorIs(t, err, model.ErrInvalidTransferDirection)
+
+	_, err = logic.SubAccountTransfer(&types.SubAccountTransferRequest{
+		SubUserID: 2, Direction: TransferDirectionToMaster, Currency: "USDT", Amount: "1000",
+	})
+	assert.ErrorIs(t, err, model.ErrInsufficientBalance)
+}
+
+func TestGetSubAccountBalances(t *testing.T) {
+	f := newSubAccountFixture()
+
+	resp, err := NewGetSubAccountBalancesLogic(userContext(1), f.svcCtx).GetSubAccountBalances()
+	assert.NoError(t, err)
+
+	// 母账户在前，子账户按创建顺序排列
+	if assert.Len(t, resp.Accounts, 3) {
+		assert.True(t, resp.Accounts[0].IsMaster)
+		assert.Equal(t, uint64(2), resp.Accounts[1].UserID)
+		assert.Empty(t, resp.Accounts[2].Balances)
+	}
+	if assert.Len(t, resp.Totals, 2) {
+		assert.Equal(t, "BTC", resp.Totals[0].Currency)
+		assert.Equal(t, "USDT", resp.Totals[1].Currency)
+		assert.Equal(t, "1050", resp.Totals[1].Available)
+		assert.Equal(t, "10", resp.Totals[1].Frozen)
+	}
+}
+
+func TestUpdateSubAccountStatus(t *testing.T) {
+	f := newSubAccountFixture()
+	logic := NewUpdateSubAccountStatusLogic(userContext(1), f.svcCtx)
+
+	resp, err := logic.UpdateSubAccountStatus(&types.UpdateSubAccountStatusRequest{ID: 2, Status: model.UserStatusDisabled})
+	assert.NoError(t, err)
+	assert.Equal(t, model.UserStatusDisabled, resp.Status)
+	assert.Equal(t, model.UserStatusDisabled, f.users.users[2].Status)
+	// 禁用时注销会话、吊销API密钥并记录母账户的安全事件
+	assert.Equal(t, []uint64{2}, f.sessions.revoked)
+	assert.Zero(t, f.apiKeys.active[2])
+	if assert.Len(t, f.events.events, 1) {
+		assert.Equal(t, uint64(1), f.events.events[0].UserID)
+		assert.Equal(t, model.SecurityEventSubAccountStatusChanged, f.events.events[0].EventType)
+	}
+
+	_, err = logic.UpdateSubAccountStatus(&types.UpdateSubAccountStatusRequest{ID: 2, Status: 9})
+	assert.ErrorIs(t, err, model.ErrInvalidSubAccountStatus)
+
+	// 删除后不可恢复
+	_, err = logic.UpdateSubAccountStatus(&types.UpdateSubAccountStatusRequest{ID: 2, Status: model.UserStatusDeleted})
+	assert.NoError(t, err)
+	_, err = logic.UpdateSubAccountStatus(&types.UpdateSubAccountStatusRequest{ID: 2, Status: model.UserStatusActive})
+	assert.ErrorIs(t, err, model.ErrSubAccountDeleted)
+
+	_, err = logic.UpdateSubAccountStatus(&types.UpdateSubAccountStatusRequest{ID: 5, Status: model.UserStatusDisabled})
+	assert.ErrorIs(t, err, model.ErrSubAccountNotFound)
+}
+
+func TestFreezeSubAccount(t *testing.T) {
+	f := newSubAccountFixture()
+	freeze := NewFreezeSubAccountLogic(userContext(1), f.svcCtx)
+	unfreeze := NewUnfreezeSubAccountLogic(userContext(1), f.svcCtx)
+
+	_, err := freeze.FreezeSubAccount(&types.FreezeSubAccountRequest{ID: 2})
+	assert.ErrorIs(t, err, model.ErrRestrictionReason)
+
+	// 冻结通过账户限制实现，注销会话但保留API密钥
+	resp, err := freeze.FreezeSubAccount(&types.FreezeSubAccountRequest{ID: 2, Reason: "key leaked"})
+	assert.NoError(t, err)
+	assert.Equal(t, model.UserStatusActive, resp.Status)
+	if assert.Len(t, f.restrictions.restrictions, 1) {
+		assert.Equal(t, model.RestrictionFreeze, f.restrictions.restrictions[0].Type)
+		assert.Equal(t, uint64(1), f.restrictions.restrictions[0].CreatedBy)
+	}
+	assert.Len(t, f.restrictions.audits, 1)
+	assert.Equal(t, []uint64{2}, f.sessions.revoked)
+	assert.Equal(t, int64(2), f.apiKeys.active[2])
+
+	_, err = unfreeze.UnfreezeSubAccount(&types.UnfreezeSubAccountRequest{ID: 2})
+	assert.NoError(t, err)
+	assert.Empty(t, f.restrictions.restrictions)
+	_, err = unfreeze.UnfreezeSubAccount(&types.UnfreezeSubAccountRequest{ID: 2})
+	assert.ErrorIs(t, err, model.ErrRestrictionNotFound)
+
+	// 管理员施加的冻结不被覆盖，母账户也不能解除
+	f.restrictions.restrictions = []*model.UserRestriction{{UserID: 2, Type: model.RestrictionFreeze, Reason: "compliance", CreatedBy: 99}}
+	_, err = freeze.FreezeSubAccount(&types.FreezeSubAccountRequest{ID: 2, Reason: "key leaked"})
+	assert.NoError(t, err)
+	assert.Equal(t, "compliance", f.restrictions.restrictions[0].Reason)
+	_, err = unfreeze.UnfreezeSubAccount(&types.UnfreezeSubAccountRequest{ID: 2})
+	assert.ErrorIs(t, err, model.ErrSubAccountFrozenByAdmin)
+
+	_, err = freeze.FreezeSubAccount(&types.FreezeSubAccountRequest{ID: 4, Reason: "key leaked"})
+	assert.ErrorIs(t, err, model.ErrSubAccountNotFound)
+}
+
+// memoryRestrictionModel 进程内账户限制，只实现子账户逻辑用到的方法
+type memoryRestrictionModel struct {
+	model.UserRestrictionModel
+	restrictions []*model.UserRestriction
+	audits       []*model.RestrictionAuditLog
+}
+
+func (m *memoryRestrictionModel) FindByUserID(ctx context.Context, userID uint64) ([]*model.UserRestriction, error) {
+	var resp []*model.UserRestriction
+	for _, r := range m.restrictions {
+		if r.UserID == userID {
+			resp = append(resp, r)
+		}
+	}
+	return resp, nil
+}
+
+func (m *memoryRestrictionModel) Apply(ctx context.Context, data *model.UserRestriction, audit *model.RestrictionAuditLog) error {
+	m.restrictions = append(m.restrictions, data)
+	m.audits = append(m.audits, audit)
+	return nil
+}
+
+func (m *memoryRestrictionModel) Lift(ctx context.Context, userID uint64, restrictionType string, audit *model.RestrictionAuditLog) error {
+	for i, r := range m.restrictions {
+		if r.UserID == userID && r.Type == restrictionType {
+			m.restrictions = append(m.restrictions[:i], m.restrictions[i+1:]...)
+			m.audits = append(m.audits, audit)
+			return nil
+		}
+	}
+	return model.ErrRestrictionNotFound
+}
+
+func (m *memoryRestrictionModel) FindActiveByUserID(ctx context.Context, userID uint64, now time.Time) ([]*model.UserRestriction, error) {
+	var resp []*model.UserRestriction
+	for _, r := range m.restrictions {
+		if r.UserID == userID && (!r.ExpiresAt.Valid || r.ExpiresAt.Time.After(now)) {
+			resp = append(resp, r)
+		}
+	}
+	return resp, nil
+}
+
+type memorySessionStore struct {
+	session.Store
+	revoked []uint64
+}
+
+func (m *memorySessionStore) RevokeAll(ctx context.Context, userID uint64) error {
+	m.revoked = append(m.revoked, userID)
+	return nil
+}
+
+// memoryApiKeyModel 按用户记录有效API密钥的数量
+type memoryApiKeyModel struct {
+	model.ApiKeyModel
+	active map[uint64]int64
+}
+
+func (m *memoryApiKeyModel) RevokeAllByUserID(ctx context.Context, userID uint64) (int64, error) {
+	revoked := m.active[userID]
+	delete(m.active, userID)
+	return revoked, nil
+}
+
+type memorySecurityEventModel struct {
+	model.SecurityEventModel
+	events []*model.SecurityEvent
+}
+
+func (m *memorySecurityEventModel) Insert(ctx context.Context, data *model.SecurityEvent) (sql.Result, error) {
+	m.events = append(m.events, data)
+	return nil, nil
+}
+
+// emptyOrderModel 没有任何挂单
+type emptyOrderModel struct {
+	model.OrderModel
+}
+
+func (m *emptyOrderModel) FindByUserIDAndStatus(ctx context.Context, userID uint64, status int64) ([]*model.Order, error) {
+	return nil, nil
+}
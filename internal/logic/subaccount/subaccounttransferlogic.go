@@ -0,0 +1,110 @@
+package subaccount
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"crypto-exchange/internal/logic/asset"
+	"crypto-exchange/internal/logic/restriction"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 母子账户划转方向
+const (
+	TransferDirectionToSub    int64 = 1 // 母账户转入子账户
+	TransferDirectionToMaster int64 = 2 // 子账户转回母账户
+)
+
+type SubAccountTransferLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSubAccountTransferLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SubAccountTransferLogic {
+	return &SubAccountTransferLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// SubAccountTransfer 母账户与子账户之间划转可用余额，实时到账，不计入站内转账限额
+// 已禁用或已删除的子账户仍可将余额转回母账户，但不能再向其转入
+func (l *SubAccountTransferLogic) SubAccountTransfer(req *types.SubAccountTransferRequest) (resp *types.TransferResponse, err error) {
+	// 1. 获取母账户和子账户
+	master, err := currentMaster(l.ctx, l.svcCtx)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := findSubAccount(l.ctx, l.svcCtx, master.ID, req.SubUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 验证划转参数
+	req.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil || !amount.IsPositive() {
+		return nil, model.ErrInvalidAmount
+	}
+	if _, err := l.svcCtx.CurrencyRegistry.ValidateTransfer(l.ctx, req.Currency, amount); err != nil {
+		return nil, err
+	}
+
+	// 3. 确定划转方向
+	var fromUserID, toUserID uint64
+	switch req.Direction {
+	case TransferDirectionToSub:
+		switch sub.Status {
+		case model.UserStatusDisabled:
+			return nil, model.ErrSubAccountDisabled
+		case model.UserStatusDeleted:
+			return nil, model.ErrSubAccountDeleted
+		}
+		fromUserID, toUserID = master.ID, sub.ID
+	case TransferDirectionToMaster:
+		fromUserID, toUserID = sub.ID, master.ID
+	default:
+		return nil, model.ErrInvalidTransferDirection
+	}
+
+	// 被限制提现的账户不能转出，避免通过母子账户划转绕过限制
+	if err := restriction.CheckRestrictions(l.ctx, l.svcCtx, fromUserID, model.RestrictionWithdraw); err != nil {
+		return nil, err
+	}
+
+	// 4. 执行划转
+	transferID := fmt.Sprintf("SUB_%s", strings.ReplaceAll(uuid.New().String(), "-", ""))
+	now, err := asset.NewTransferLogic(l.ctx, l.svcCtx).Execute(&asset.InternalTransfer{
+		TransferID: transferID,
+		FromUserID: fromUserID,
+		ToUserID:   toUserID,
+		Currency:   req.Currency,
+		Amount:     amount,
+		OutType:    model.AssetTransactionTypeSubAccountOut,
+		InType:     model.AssetTransactionTypeSubAccountIn,
+		Remark:     strings.TrimSpace(req.Remark),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.Infof("Sub-account transfer %s: user %d sent %s %s to user %d", transferID, fromUserID, req.Amount, req.Currency, toUserID)
+	return &types.TransferResponse{
+		TransferID: transferID,
+		Currency:   req.Currency,
+		Amount:     req.Amount,
+		FromUserID: fromUserID,
+		ToUserID:   toUserID,
+		Status:     model.AssetTransactionStatusSuccess,
+		CreatedAt:  now.Format("2006-01-02 15:04:05"),
+	}, nil
+}
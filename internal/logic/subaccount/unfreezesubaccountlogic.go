@@ -0,0 +1,84 @@
+package subaccount
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"crypto-exchange/internal/logic/securityevent"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UnfreezeSubAccountLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUnfreezeSubAccountLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UnfreezeSubAccountLogic {
+	return &UnfreezeSubAccountLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// UnfreezeSubAccount 解除母账户对子账户的冻结，管理员施加的冻结只能由管理员解除
+// 冻结期间注销的会话和撤销的挂单不会恢复
+func (l *UnfreezeSubAccountLogic) UnfreezeSubAccount(req *types.UnfreezeSubAccountRequest) (resp *types.SubAccount, err error) {
+	master, err := currentMaster(l.ctx, l.svcCtx)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := findSubAccount(l.ctx, l.svcCtx, master.ID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	restrictions, err := l.svcCtx.UserRestrictionModel.FindByUserID(l.ctx, sub.ID)
+	if err != nil {
+		l.Errorf("Failed to get restrictions of sub-account %d: %v", sub.ID, err)
+		return nil, model.ErrInternalServer
+	}
+	var freeze *model.UserRestriction
+	for _, r := range restrictions {
+		if r.Type == model.RestrictionFreeze {
+			freeze = r
+			break
+		}
+	}
+	if freeze == nil {
+		return nil, model.ErrRestrictionNotFound
+	}
+	if freeze.CreatedBy != master.ID {
+		return nil, model.ErrSubAccountFrozenByAdmin
+	}
+
+	err = l.svcCtx.UserRestrictionModel.Lift(l.ctx, sub.ID, model.RestrictionFreeze, &model.RestrictionAuditLog{
+		UserID:     sub.ID,
+		Type:       model.RestrictionFreeze,
+		Action:     model.RestrictionAuditActionLift,
+		Reason:     "lifted by master account",
+		OperatorID: master.ID,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, model.ErrRestrictionNotFound) {
+			return nil, err
+		}
+		l.Errorf("Failed to unfreeze sub-account %d: %v", sub.ID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	l.Infof("User %d unfroze sub-account %d", master.ID, sub.ID)
+	securityevent.Record(l.ctx, l.svcCtx, master.ID, model.SecurityEventSubAccountUnfrozen,
+		fmt.Sprintf("sub-account %d unfrozen", sub.ID))
+
+	subAccount := toSubAccount(sub)
+	return &subAccount, nil
+}
@@ -0,0 +1,90 @@
+package subaccount
+
+import (
+	"context"
+	"fmt"
+
+	"crypto-exchange/internal/logic/securityevent"
+	"crypto-exchange/internal/logic/trading"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateSubAccountStatusLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateSubAccountStatusLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateSubAccountStatusLogic {
+	return &UpdateSubAccountStatusLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// UpdateSubAccountStatus 禁用、恢复或删除子账户
+// 禁用和删除后子账户无法登录，同时注销其全部登录会话、吊销全部API密钥并撤销全部挂单，删除不可恢复；
+// 恢复后子账户需重新登录和创建API密钥。子账户的余额仍可由母账户划转回母账户
+func (l *UpdateSubAccountStatusLogic) UpdateSubAccountStatus(req *types.UpdateSubAccountStatusRequest) (resp *types.SubAccount, err error) {
+	switch req.Status {
+	case model.UserStatusActive, model.UserStatusDisabled, model.UserStatusDeleted:
+	default:
+		return nil, model.ErrInvalidSubAccountStatus
+	}
+
+	master, err := currentMaster(l.ctx, l.svcCtx)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := findSubAccount(l.ctx, l.svcCtx, master.ID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Status == model.UserStatusDeleted {
+		return nil, model.ErrSubAccountDeleted
+	}
+
+	if sub.Status != req.Status {
+		if err := l.svcCtx.UserModel.UpdateStatus(l.ctx, sub.ID, req.Status); err != nil {
+			l.Errorf("Failed to update status of sub-account %d: %v", sub.ID, err)
+			return nil, model.ErrInternalServer
+		}
+		l.Infof("User %d changed status of sub-account %d from %d to %d", master.ID, sub.ID, sub.Status, req.Status)
+		securityevent.Record(l.ctx, l.svcCtx, master.ID, model.SecurityEventSubAccountStatusChanged,
+			fmt.Sprintf("sub-account %d status changed from %d to %d", sub.ID, sub.Status, req.Status))
+		sub.Status = req.Status
+	}
+
+	// 状态未变化时也重新执行，母账户可以通过重复禁用重试之前失败的步骤
+	if req.Status != model.UserStatusActive {
+		l.revokeAccess(sub.ID)
+	}
+
+	subAccount := toSubAccount(sub)
+	return &subAccount, nil
+}
+
+// revokeAccess 注销子账户的全部登录会话、吊销API密钥并撤销挂单
+// 禁用状态已经生效，这些步骤失败只记录日志
+func (l *UpdateSubAccountStatusLogic) revokeAccess(subUserID uint64) {
+	if err := l.svcCtx.Sessions.RevokeAll(l.ctx, subUserID); err != nil {
+		l.Errorf("Failed to revoke sessions of disabled sub-account %d: %v", subUserID, err)
+	}
+
+	revoked, err := l.svcCtx.ApiKeyModel.RevokeAllByUserID(l.ctx, subUserID)
+	if err != nil {
+		l.Errorf("Failed to revoke API keys of disabled sub-account %d: %v", subUserID, err)
+	} else if revoked > 0 {
+		l.Infof("Revoked %d API keys of disabled sub-account %d", revoked, subUserID)
+	}
+
+	canceled, err := trading.CancelUserOpenOrders(l.ctx, l.svcCtx, subUserID, "sub-account disabled")
+	if err != nil {
+		l.Errorf("Failed to cancel open orders of disabled sub-account %d after %d canceled: %v", subUserID, canceled, err)
+	}
+}
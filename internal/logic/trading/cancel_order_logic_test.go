@@ -24,6 +24,7 @@ func TestCancelOrderLogic_CancelOrder_Success(t *testing.T) {
 		OrderModel:       mockOrderModel,
 		TradingPairModel: mockTradingPairModel,
 		BalanceModel:     mockBalanceModel,
+		LedgerEntryModel: newMockLedgerEntryModel(),
 	}
 
 	logic := &CancelOrderLogic{
@@ -81,6 +82,59 @@ func TestCancelOrderLogic_CancelOrder_Success(t *testing.T) {
 	mockBalanceModel.AssertExpectations(t)
 }
 
+// 暂停交易的交易对仍允许撤单，已下架的交易对禁止撤单
+func TestCancelOrderLogic_CancelOrder_TradingPairStatus(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "userId", "1")
+	order := &model.Order{
+		ID:           1,
+		UserID:       1,
+		Symbol:       "BTC/USDT",
+		Type:         1,
+		Side:         1,
+		Amount:       "1.00000000",
+		Price:        "50000.00",
+		FilledAmount: "0",
+		Status:       1,
+	}
+
+	mockOrderModel := &mockOrderModel{}
+	mockTradingPairModel := &mockTradingPairModel{}
+	mockBalanceModel := &mockBalanceModel{}
+	svcCtx := &svc.ServiceContext{
+		OrderModel:       mockOrderModel,
+		TradingPairModel: mockTradingPairModel,
+		BalanceModel:     mockBalanceModel,
+		LedgerEntryModel: newMockLedgerEntryModel(),
+	}
+	mockOrderModel.On("FindOne", mock.Anything, uint64(1)).Return(order, nil)
+	mockTradingPairModel.On("FindBySymbol", mock.Anything, "BTC/USDT").Return(&model.TradingPair{
+		Symbol:        "BTC/USDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		Status:        model.TradingPairStatusHalted,
+	}, nil).Once()
+	mockOrderModel.On("Trans", mock.Anything, mock.Anything).Return(nil)
+	mockOrderModel.On("UpdateStatus", mock.Anything, uint64(1), int64(4)).Return(nil)
+	mockBalanceModel.On("UnfreezeBalance", mock.Anything, uint64(1), "USDT", "50000").Return(nil)
+
+	resp, err := NewCancelOrderLogic(ctx, svcCtx).CancelOrder(&types.CancelOrderRequest{OrderID: 1})
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	mockOrderModel.AssertExpectations(t)
+	mockBalanceModel.AssertExpectations(t)
+
+	mockTradingPairModel.On("FindBySymbol", mock.Anything, "BTC/USDT").Return(&model.TradingPair{
+		Symbol:        "BTC/USDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		Status:        model.TradingPairStatusDelisted,
+	}, nil).Once()
+	resp, err = NewCancelOrderLogic(ctx, svcCtx).CancelOrder(&types.CancelOrderRequest{OrderID: 1})
+	assert.Equal(t, model.ErrCancelNotAllowed, err)
+	assert.Nil(t, resp)
+	mockOrderModel.AssertNumberOfCalls(t, "UpdateStatus", 1)
+}
+
 func TestCancelOrderLogic_CancelOrder_OrderNotFound(t *testing.T) {
 	mockOrderModel := &mockOrderModel{}
 
@@ -240,6 +294,7 @@ func TestCancelOrderLogic_CancelOrder_SellOrder(t *testing.T) {
 		OrderModel:       mockOrderModel,
 		TradingPairModel: mockTradingPairModel,
 		BalanceModel:     mockBalanceModel,
+		LedgerEntryModel: newMockLedgerEntryModel(),
 	}
 
 	logic := &CancelOrderLogic{
@@ -302,6 +357,7 @@ func TestCancelOrderLogic_CancelOrder_MarketOrder(t *testing.T) {
 		OrderModel:       mockOrderModel,
 		TradingPairModel: mockTradingPairModel,
 		BalanceModel:     mockBalanceModel,
+		LedgerEntryModel: newMockLedgerEntryModel(),
 	}
 
 	logic := &CancelOrderLogic{
@@ -363,6 +419,7 @@ func TestCancelOrderLogic_CancelOrder_FullyFilledNoUnfreeze(t *testing.T) {
 		OrderModel:       mockOrderModel,
 		TradingPairModel: mockTradingPairModel,
 		BalanceModel:     mockBalanceModel,
+		LedgerEntryModel: newMockLedgerEntryModel(),
 	}
 
 	logic := &CancelOrderLogic{
@@ -0,0 +1,80 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"crypto-exchange/internal/logic/market"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// CancelUserOpenOrders 撤销用户的全部挂单并解冻资产，用于冻结账户等合规操作，返回撤销的订单数
+// 调用前应已禁止用户下单；不受交易对撤单状态限制，先从撮合引擎移除订单再更新数据库，避免撤单过程中继续成交
+func CancelUserOpenOrders(ctx context.Context, svcCtx *svc.ServiceContext, userID uint64, remark string) (int64, error) {
+	logger := logx.WithContext(ctx)
+
+	var orders []*model.Order
+	for _, status := range []int64{1, 2} { // 待成交、部分成交
+		found, err := svcCtx.OrderModel.FindByUserIDAndStatus(ctx, userID, status)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get open orders: %w", err)
+		}
+		orders = append(orders, found...)
+	}
+
+	pairs := make(map[string]*model.TradingPair)
+	var canceled int64
+	for _, order := range orders {
+		pair, ok := pairs[order.Symbol]
+		if !ok {
+			var err error
+			pair, err = svcCtx.TradingPairModel.FindBySymbol(ctx, order.Symbol)
+			if err != nil {
+				return canceled, fmt.Errorf("failed to get trading pair %s: %w", order.Symbol, err)
+			}
+			pairs[order.Symbol] = pair
+		}
+
+		if err := svcCtx.MatchingEngine.CancelOrder(order); err != nil {
+			logger.Infof("Order %d not removed from matching engine: %v", order.ID, err)
+		}
+
+		currency, amount, err := market.RemainingFrozenAmount(order, pair)
+		if err != nil {
+			return canceled, fmt.Errorf("failed to calculate frozen amount of order %d: %w", order.ID, err)
+		}
+
+		err = svcCtx.OrderModel.Trans(ctx, func(ctx context.Context, session sqlx.Session) error {
+			orders := svcCtx.OrderModel.WithSession(session)
+			balances := svcCtx.BalanceModel.WithSession(session)
+			ledger := svcCtx.LedgerEntryModel.WithSession(session)
+
+			if err := orders.UpdateStatus(ctx, order.ID, 4); err != nil {
+				return err
+			}
+			if !amount.IsPositive() {
+				return nil
+			}
+			if err := balances.UnfreezeBalance(ctx, userID, currency, amount.String()); err != nil {
+				return err
+			}
+
+			// 记账：用户冻结余额 -> 可用余额
+			journal := model.NewLedgerJournal(model.LedgerBizUnfreeze, strconv.FormatUint(order.ID, 10))
+			journal.Transfer(currency, amount.String(), model.UserFrozen(userID), model.UserAvailable(userID), remark)
+			return ledger.InsertJournal(ctx, journal)
+		})
+		if err != nil {
+			return canceled, fmt.Errorf("failed to cancel order %d: %w", order.ID, err)
+		}
+		canceled++
+	}
+
+	logger.Infof("Canceled %d open orders of user %d: %s", canceled, userID, remark)
+	return canceled, nil
+}
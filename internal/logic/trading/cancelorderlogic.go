@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strconv"
 
+	"crypto-exchange/internal/authctx"
 	"crypto-exchange/internal/svc"
 	"crypto-exchange/internal/types"
 	"crypto-exchange/model"
@@ -30,9 +31,9 @@ func NewCancelOrderLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Cance
 
 func (l *CancelOrderLogic) CancelOrder(req *types.CancelOrderRequest) (resp *types.BaseResponse, err error) {
 	// 从JWT中获取用户ID
-	userID, err := l.getUserIDFromContext()
+	userID, err := authctx.UserID(l.ctx)
 	if err != nil {
-		return nil, err
+		return nil, model.ErrUnauthorized
 	}
 
 	// 查询订单信息
@@ -64,6 +65,11 @@ func (l *CancelOrderLogic) CancelOrder(req *types.CancelOrderRequest) (resp *typ
 		return nil, err
 	}
 
+	// 已下架的交易对不允许撤单
+	if !model.TradingPairAcceptsCancels(tradingPair.Status) {
+		return nil, model.ErrCancelNotAllowed
+	}
+
 	// 计算需要解冻的资产
 	unfreezeCurrency, unfreezeAmount, err := l.calculateUnfreezeAmount(order, tradingPair)
 	if err != nil {
@@ -72,14 +78,25 @@ func (l *CancelOrderLogic) CancelOrder(req *types.CancelOrderRequest) (resp *typ
 
 	// 使用事务确保原子性
 	err = l.svcCtx.OrderModel.Trans(l.ctx, func(ctx context.Context, session sqlx.Session) error {
+		orders := l.svcCtx.OrderModel.WithSession(session)
+		balances := l.svcCtx.BalanceModel.WithSession(session)
+		ledger := l.svcCtx.LedgerEntryModel.WithSession(session)
+
 		// 更新订单状态为已取消
-		if err := l.svcCtx.OrderModel.UpdateStatus(ctx, order.ID, 4); err != nil {
+		if err := orders.UpdateStatus(ctx, order.ID, 4); err != nil {
 			return err
 		}
 
 		// 解冻用户余额
 		if unfreezeAmount != "0" {
-			if err := l.svcCtx.BalanceModel.UnfreezeBalance(ctx, userID, unfreezeCurrency, unfreezeAmount); err != nil {
+			if err := balances.UnfreezeBalance(ctx, userID, unfreezeCurrency, unfreezeAmount); err != nil {
+				return err
+			}
+
+			// 记账：用户冻结余额 -> 可用余额
+			journal := model.NewLedgerJournal(model.LedgerBizUnfreeze, strconv.FormatUint(order.ID, 10))
+			journal.Transfer(unfreezeCurrency, unfreezeAmount, model.UserFrozen(userID), model.UserAvailable(userID), "order canceled")
+			if err := ledger.InsertJournal(ctx, journal); err != nil {
 				return err
 			}
 		}
@@ -99,23 +116,6 @@ func (l *CancelOrderLogic) CancelOrder(req *types.CancelOrderRequest) (resp *typ
 	return resp, nil
 }
 
-// getUserIDFromContext 从上下文中获取用户ID
-func (l *CancelOrderLogic) getUserIDFromContext() (uint64, error) {
-	// 这里应该从JWT中间件设置的上下文中获取用户ID
-	// 为了演示，这里返回一个固定值，实际应用中需要从JWT token中解析
-	userIDStr := l.ctx.Value("userId")
-	if userIDStr == nil {
-		return 0, model.ErrUnauthorized
-	}
-
-	userID, err := strconv.ParseUint(userIDStr.(string), 10, 64)
-	if err != nil {
-		return 0, model.ErrUnauthorized
-	}
-
-	return userID, nil
-}
-
 // calculateUnfreezeAmount 计算需要解冻的资产数量
 func (l *CancelOrderLogic) calculateUnfreezeAmount(order *model.Order, tradingPair *model.TradingPair) (currency string, amount string, err error) {
 	// 计算剩余未成交数量
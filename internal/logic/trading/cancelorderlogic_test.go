@@ -310,6 +310,7 @@ func TestCancelOrderLogic_CancelOrder(t *testing.T) {
 				OrderModel:       mockOrderModel,
 				TradingPairModel: mockTradingPairModel,
 				BalanceModel:     mockBalanceModel,
+				LedgerEntryModel: newMockLedgerEntryModel(),
 			}
 
 			// 创建带用户ID的上下文
@@ -495,6 +496,7 @@ func TestCancelOrderLogic_Integration(t *testing.T) {
 		OrderModel:       mockOrderModel,
 		TradingPairModel: mockTradingPairModel,
 		BalanceModel:     mockBalanceModel,
+		LedgerEntryModel: newMockLedgerEntryModel(),
 	}
 
 	// 创建带用户ID的上下文
@@ -6,6 +6,10 @@ import (
 	"strconv"
 	"time"
 
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/market"
+	"crypto-exchange/internal/logic/restriction"
+	"crypto-exchange/internal/logic/useremail"
 	"crypto-exchange/internal/svc"
 	"crypto-exchange/internal/types"
 	"crypto-exchange/model"
@@ -31,8 +35,17 @@ func NewCreateOrderLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Creat
 
 func (l *CreateOrderLogic) CreateOrder(req *types.CreateOrderRequest) (resp *types.Order, err error) {
 	// 从JWT中获取用户ID（这里假设已经通过中间件设置）
-	userID, err := l.getUserIDFromContext()
+	userID, err := authctx.UserID(l.ctx)
 	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	// 未验证邮箱或被限制交易的用户不能下单
+	user, err := useremail.Require(l.ctx, l.svcCtx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := restriction.Check(l.ctx, l.svcCtx, user, model.RestrictionTrading); err != nil {
 		return nil, err
 	}
 
@@ -45,8 +58,16 @@ func (l *CreateOrderLogic) CreateOrder(req *types.CreateOrderRequest) (resp *typ
 		return nil, err
 	}
 
-	if tradingPair.Status != 1 {
-		return nil, model.ErrTradingPairDisabled
+	// 检查交易对当前状态是否接受该类型的订单
+	if err := model.CheckTradingPairOrderStatus(tradingPair.Status, req.Type); err != nil {
+		return nil, err
+	}
+
+	// 交易对要求的最低认证等级
+	if user.VerificationLevel < tradingPair.MinVerificationLevel {
+		l.Infof("Order rejected for user %d on %s: verification level %d below required %d",
+			userID, tradingPair.Symbol, user.VerificationLevel, tradingPair.MinVerificationLevel)
+		return nil, model.ErrVerificationLevelTooLow
 	}
 
 	// 验证订单参数
@@ -54,6 +75,11 @@ func (l *CreateOrderLogic) CreateOrder(req *types.CreateOrderRequest) (resp *typ
 		return nil, err
 	}
 
+	// 验证交易对过滤器（价格步长、数量步长、最小下单金额）
+	if err := market.NewTradingPairValidator().ValidateOrderFilters(tradingPair, req.Type, req.Side, req.Amount, req.Price); err != nil {
+		return nil, err
+	}
+
 	// 计算需要冻结的资产
 	freezeCurrency, freezeAmount, err := l.calculateFreezeAmount(req, tradingPair)
 	if err != nil {
@@ -63,8 +89,17 @@ func (l *CreateOrderLogic) CreateOrder(req *types.CreateOrderRequest) (resp *typ
 	// 使用事务确保原子性
 	var order *model.Order
 	err = l.svcCtx.BalanceModel.Trans(l.ctx, func(ctx context.Context, session sqlx.Session) error {
+		orders := l.svcCtx.OrderModel.WithSession(session)
+		balances := l.svcCtx.BalanceModel.WithSession(session)
+		ledger := l.svcCtx.LedgerEntryModel.WithSession(session)
+
+		// 检查用户在该交易对的挂单数量是否已达上限，与插入订单在同一事务中完成
+		if err := checkOpenOrderLimit(ctx, orders, userID, tradingPair); err != nil {
+			return err
+		}
+
 		// 冻结用户余额
-		if err := l.svcCtx.BalanceModel.FreezeBalance(ctx, userID, freezeCurrency, freezeAmount); err != nil {
+		if err := balances.FreezeBalance(ctx, userID, freezeCurrency, freezeAmount); err != nil {
 			return err
 		}
 
@@ -83,7 +118,7 @@ func (l *CreateOrderLogic) CreateOrder(req *types.CreateOrderRequest) (resp *typ
 			UpdatedAt:    now,
 		}
 
-		result, err := l.svcCtx.OrderModel.Insert(ctx, order)
+		result, err := orders.Insert(ctx, order)
 		if err != nil {
 			return err
 		}
@@ -95,7 +130,10 @@ func (l *CreateOrderLogic) CreateOrder(req *types.CreateOrderRequest) (resp *typ
 		}
 		order.ID = uint64(orderID)
 
-		return nil
+		// 记账：用户可用余额 -> 冻结余额
+		journal := model.NewLedgerJournal(model.LedgerBizFreeze, strconv.FormatUint(order.ID, 10))
+		journal.Transfer(freezeCurrency, freezeAmount, model.UserAvailable(userID), model.UserFrozen(userID), "order freeze")
+		return ledger.InsertJournal(ctx, journal)
 	})
 
 	if err != nil {
@@ -105,6 +143,15 @@ func (l *CreateOrderLogic) CreateOrder(req *types.CreateOrderRequest) (resp *typ
 	// 创建订单成功后，调用撮合引擎进行撮合处理
 	matchingService := NewMatchingService(l.ctx, l.svcCtx)
 	if err := matchingService.ProcessOrderWithMatching(order); err != nil {
+		// 撮合引擎因交易对状态拒绝订单（如仅挂单状态下会立即成交），撤销订单并解冻资产
+		if isOrderRejectedByEngine(err) {
+			if rejectErr := l.rejectOrder(order, freezeCurrency, freezeAmount); rejectErr != nil {
+				l.Errorf("Failed to reject order %d: %v", order.ID, rejectErr)
+				return nil, rejectErr
+			}
+			return nil, err
+		}
+
 		l.Errorf("Failed to process order with matching engine: %v", err)
 		// 撮合失败不影响订单创建，只记录错误日志
 		// 在生产环境中可能需要更精细的错误处理策略
@@ -131,21 +178,53 @@ func (l *CreateOrderLogic) CreateOrder(req *types.CreateOrderRequest) (resp *typ
 	return resp, nil
 }
 
-// getUserIDFromContext 从上下文中获取用户ID
-func (l *CreateOrderLogic) getUserIDFromContext() (uint64, error) {
-	// 这里应该从JWT中间件设置的上下文中获取用户ID
-	// 为了演示，这里返回一个固定值，实际应用中需要从JWT token中解析
-	userIDStr := l.ctx.Value("userId")
-	if userIDStr == nil {
-		return 0, model.ErrUnauthorized
+// checkOpenOrderLimit 在下单事务中锁定用户并检查其在交易对下的挂单数量是否已达到上限
+func checkOpenOrderLimit(ctx context.Context, orders model.OrderModel, userID uint64, tradingPair *model.TradingPair) error {
+	if tradingPair.MaxOpenOrders <= 0 {
+		return nil
 	}
 
-	userID, err := strconv.ParseUint(userIDStr.(string), 10, 64)
+	openOrders, err := orders.CountOpenByUserAndSymbolForUpdate(ctx, userID, tradingPair.Symbol)
 	if err != nil {
-		return 0, model.ErrUnauthorized
+		return err
+	}
+
+	if openOrders >= tradingPair.MaxOpenOrders {
+		return model.ErrTooManyOpenOrders
 	}
 
-	return userID, nil
+	return nil
+}
+
+// isOrderRejectedByEngine 判断撮合引擎返回的错误是否为交易对状态导致的拒单
+func isOrderRejectedByEngine(err error) bool {
+	return errors.Is(err, model.ErrOrderWouldMatch) ||
+		errors.Is(err, model.ErrTradingPairPostOnly) ||
+		errors.Is(err, model.ErrTradingPairNotOpen) ||
+		errors.Is(err, model.ErrTradingPairCancelOnly) ||
+		errors.Is(err, model.ErrTradingPairDelisted) ||
+		errors.Is(err, model.ErrTradingPairDisabled)
+}
+
+// rejectOrder 撤销被撮合引擎拒绝的订单，并解冻下单时冻结的资产
+func (l *CreateOrderLogic) rejectOrder(order *model.Order, freezeCurrency, freezeAmount string) error {
+	return l.svcCtx.OrderModel.Trans(l.ctx, func(ctx context.Context, session sqlx.Session) error {
+		orders := l.svcCtx.OrderModel.WithSession(session)
+		balances := l.svcCtx.BalanceModel.WithSession(session)
+		ledger := l.svcCtx.LedgerEntryModel.WithSession(session)
+
+		if err := orders.UpdateStatus(ctx, order.ID, 4); err != nil {
+			return err
+		}
+		if err := balances.UnfreezeBalance(ctx, order.UserID, freezeCurrency, freezeAmount); err != nil {
+			return err
+		}
+
+		// 记账：用户冻结余额 -> 可用余额
+		journal := model.NewLedgerJournal(model.LedgerBizUnfreeze, strconv.FormatUint(order.ID, 10))
+		journal.Transfer(freezeCurrency, freezeAmount, model.UserFrozen(order.UserID), model.UserAvailable(order.UserID), "order rejected")
+		return ledger.InsertJournal(ctx, journal)
+	})
 }
 
 // validateOrderRequest 验证订单请求参数
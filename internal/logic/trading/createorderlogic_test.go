@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"crypto-exchange/internal/matching"
 	"crypto-exchange/internal/svc"
@@ -100,6 +101,14 @@ func (m *mockOrderModel) FindByUserIDWithPagination(ctx context.Context, userID
 	return args.Get(0).([]*model.Order), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *mockOrderModel) FindByUserIDsWithPagination(ctx context.Context, userIDs []uint64, symbol string, status int64, page, size int64) ([]*model.Order, int64, error) {
+	args := m.Called(ctx, userIDs, symbol, status, page, size)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*model.Order), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *mockOrderModel) UpdateStatus(ctx context.Context, id uint64, status int64) error {
 	args := m.Called(ctx, id, status)
 	return args.Error(0)
@@ -110,12 +119,34 @@ func (m *mockOrderModel) UpdateFilledAmount(ctx context.Context, id uint64, fill
 	return args.Error(0)
 }
 
+func (m *mockOrderModel) FindOpenBySymbol(ctx context.Context, symbol string) ([]*model.Order, error) {
+	args := m.Called(ctx, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Order), args.Error(1)
+}
+
+func (m *mockOrderModel) CountOpenByUserAndSymbolForUpdate(ctx context.Context, userID uint64, symbol string) (int64, error) {
+	args := m.Called(ctx, userID, symbol)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockOrderModel) CancelOpenBySymbol(ctx context.Context, symbol string) (int64, error) {
+	args := m.Called(ctx, symbol)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *mockOrderModel) Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
 	m.Called(ctx, fn)
 	// 执行事务函数进行测试
 	return fn(ctx, nil)
 }
 
+func (m *mockOrderModel) WithSession(session sqlx.Session) model.OrderModel {
+	return m
+}
+
 // Mock撮合引擎
 type mockMatchingEngine struct {
 	mock.Mock
@@ -144,6 +175,18 @@ func (m *mockMatchingEngine) GetOrderBookSnapshot(symbol string) map[string]inte
 	return args.Get(0).(map[string]interface{})
 }
 
+func (m *mockMatchingEngine) SetTradingStatus(symbol string, status int64) {
+	m.Called(symbol, status)
+}
+
+func (m *mockMatchingEngine) CancelAllOrders(symbol string) []*model.Order {
+	args := m.Called(symbol)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).([]*model.Order)
+}
+
 type mockTradingPairModel struct {
 	mock.Mock
 }
@@ -189,6 +232,15 @@ func (m *mockTradingPairModel) FindActivePairs(ctx context.Context) ([]*model.Tr
 	return args.Get(0).([]*model.TradingPair), args.Error(1)
 }
 
+func (m *mockTradingPairModel) FindAll(ctx context.Context) ([]*model.TradingPair, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*model.TradingPair), args.Error(1)
+}
+
+func (m *mockTradingPairModel) WithSession(session sqlx.Session) model.TradingPairModel {
+	return m
+}
+
 type mockBalanceModel struct {
 	mock.Mock
 }
@@ -229,6 +281,14 @@ func (m *mockBalanceModel) FindByUserIDAndCurrency(ctx context.Context, userID u
 	return args.Get(0).(*model.Balance), args.Error(1)
 }
 
+func (m *mockBalanceModel) FindByUserIDAndCurrencyForUpdate(ctx context.Context, userID uint64, currency string) (*model.Balance, error) {
+	args := m.Called(ctx, userID, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Balance), args.Error(1)
+}
+
 func (m *mockBalanceModel) UpdateBalance(ctx context.Context, userID uint64, currency string, available, frozen string) error {
 	args := m.Called(ctx, userID, currency, available, frozen)
 	return args.Error(0)
@@ -244,12 +304,111 @@ func (m *mockBalanceModel) UnfreezeBalance(ctx context.Context, userID uint64, c
 	return args.Error(0)
 }
 
+func (m *mockBalanceModel) AddAvailable(ctx context.Context, userID uint64, currency string, amount string) error {
+	args := m.Called(ctx, userID, currency, amount)
+	return args.Error(0)
+}
+
+func (m *mockBalanceModel) DeductFrozen(ctx context.Context, userID uint64, currency string, amount string) error {
+	args := m.Called(ctx, userID, currency, amount)
+	return args.Error(0)
+}
+
+func (m *mockBalanceModel) FindAll(ctx context.Context) ([]*model.Balance, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*model.Balance), args.Error(1)
+}
+
+func (m *mockBalanceModel) CountByCurrency(ctx context.Context, currency string) (int64, error) {
+	args := m.Called(ctx, currency)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *mockBalanceModel) Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
 	_ = m.Called(ctx, fn)
 	// 执行事务函数进行测试
 	return fn(ctx, nil)
 }
 
+func (m *mockBalanceModel) TransRepeatableRead(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	return fn(ctx, nil)
+}
+
+func (m *mockBalanceModel) WithSession(session sqlx.Session) model.BalanceModel {
+	return m
+}
+
+// staticUserModel 按用户ID返回固定的用户，未登记的用户视为已验证邮箱的正常用户
+type staticUserModel struct {
+	model.UserModel
+	users map[uint64]*model.User
+}
+
+func (m *staticUserModel) FindOne(ctx context.Context, id uint64) (*model.User, error) {
+	if user, ok := m.users[id]; ok {
+		return user, nil
+	}
+	return &model.User{ID: id, Status: model.UserStatusActive, EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true}}, nil
+}
+
+type mockLedgerEntryModel struct {
+	mock.Mock
+}
+
+// newMockLedgerEntryModel 创建账本模型mock，默认接受所有记账凭证
+func newMockLedgerEntryModel() *mockLedgerEntryModel {
+	m := &mockLedgerEntryModel{}
+	m.On("InsertJournal", mock.Anything, mock.Anything).Return(nil)
+	return m
+}
+
+func (m *mockLedgerEntryModel) Insert(ctx context.Context, data *model.LedgerEntry) (sql.Result, error) {
+	args := m.Called(ctx, data)
+	return args.Get(0).(sql.Result), args.Error(1)
+}
+
+func (m *mockLedgerEntryModel) FindOne(ctx context.Context, id uint64) (*model.LedgerEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.LedgerEntry), args.Error(1)
+}
+
+func (m *mockLedgerEntryModel) Update(ctx context.Context, data *model.LedgerEntry) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *mockLedgerEntryModel) Delete(ctx context.Context, id uint64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockLedgerEntryModel) InsertJournal(ctx context.Context, journal *model.LedgerJournal) error {
+	args := m.Called(ctx, journal)
+	return args.Error(0)
+}
+
+func (m *mockLedgerEntryModel) FindByJournalID(ctx context.Context, journalID string) ([]*model.LedgerEntry, error) {
+	args := m.Called(ctx, journalID)
+	return args.Get(0).([]*model.LedgerEntry), args.Error(1)
+}
+
+func (m *mockLedgerEntryModel) FindByUserIDWithPagination(ctx context.Context, userID uint64, currency string, page, size int64) ([]*model.LedgerEntry, int64, error) {
+	args := m.Called(ctx, userID, currency, page, size)
+	return args.Get(0).([]*model.LedgerEntry), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *mockLedgerEntryModel) Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	_ = m.Called(ctx, fn)
+	return fn(ctx, nil)
+}
+
+func (m *mockLedgerEntryModel) WithSession(session sqlx.Session) model.LedgerEntryModel {
+	return m
+}
+
 type mockSqlResult struct {
 	lastInsertId int64
 }
@@ -271,9 +430,12 @@ func TestCreateOrderLogic_CreateOrder_Success(t *testing.T) {
 
 	ctx := context.WithValue(context.Background(), "userId", "1")
 	svcCtx := &svc.ServiceContext{
+		UserRestrictionModel: &memoryRestrictionModel{},
+		UserModel:        &staticUserModel{},
 		OrderModel:       mockOrderModel,
 		TradingPairModel: mockTradingPairModel,
 		BalanceModel:     mockBalanceModel,
+		LedgerEntryModel: newMockLedgerEntryModel(),
 		MatchingEngine:   mockMatchingEngine,
 	}
 
@@ -342,11 +504,105 @@ func TestCreateOrderLogic_CreateOrder_Success(t *testing.T) {
 	mockMatchingEngine.AssertExpectations(t)
 }
 
+func TestCreateOrderLogic_CreateOrder_EmailNotVerified(t *testing.T) {
+	mockTradingPairModel := &mockTradingPairModel{}
+
+	ctx := context.WithValue(context.Background(), "userId", "1")
+	svcCtx := &svc.ServiceContext{
+		UserModel:        &staticUserModel{users: map[uint64]*model.User{1: {ID: 1, Status: model.UserStatusActive}}},
+		TradingPairModel: mockTradingPairModel,
+	}
+
+	resp, err := NewCreateOrderLogic(ctx, svcCtx).CreateOrder(&types.CreateOrderRequest{
+		Symbol: "BTC/USDT",
+		Type:   1,
+		Side:   1,
+		Amount: "1.00000000",
+		Price:  "50000.00",
+	})
+
+	// 未验证邮箱时不查询交易对、不冻结资产
+	assert.Nil(t, resp)
+	assert.Equal(t, model.ErrEmailNotVerified, err)
+	mockTradingPairModel.AssertNotCalled(t, "FindBySymbol", mock.Anything, mock.Anything)
+}
+
+func TestCreateOrderLogic_CreateOrder_TradingRestricted(t *testing.T) {
+	mockTradingPairModel := &mockTradingPairModel{}
+
+	ctx := context.WithValue(context.Background(), "userId", "1")
+	svcCtx := &svc.ServiceContext{
+		UserRestrictionModel: &memoryRestrictionModel{restrictions: []*model.UserRestriction{{UserID: 1, Type: model.RestrictionTrading}}},
+		UserModel: &staticUserModel{users: map[uint64]*model.User{1: {
+			ID:              1,
+			Status:          model.UserStatusActive,
+			EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}}},
+		TradingPairModel: mockTradingPairModel,
+	}
+
+	resp, err := NewCreateOrderLogic(ctx, svcCtx).CreateOrder(&types.CreateOrderRequest{
+		Symbol: "BTC/USDT",
+		Type:   1,
+		Side:   1,
+		Amount: "1.00000000",
+		Price:  "50000.00",
+	})
+
+	// 被禁止交易的用户不能下单
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, model.ErrAccountRestricted)
+	mockTradingPairModel.AssertNotCalled(t, "FindBySymbol", mock.Anything, mock.Anything)
+}
+
+func TestCreateOrderLogic_CreateOrder_VerificationLevelTooLow(t *testing.T) {
+	mockTradingPairModel := &mockTradingPairModel{}
+	mockBalanceModel := &mockBalanceModel{}
+
+	ctx := context.WithValue(context.Background(), "userId", "1")
+	svcCtx := &svc.ServiceContext{
+		UserRestrictionModel: &memoryRestrictionModel{},
+		UserModel: &staticUserModel{users: map[uint64]*model.User{1: {
+			ID:                1,
+			Status:            model.UserStatusActive,
+			VerificationLevel: model.UserVerificationBasic,
+			EmailVerifiedAt:   sql.NullTime{Time: time.Now(), Valid: true},
+		}}},
+		TradingPairModel: mockTradingPairModel,
+		BalanceModel:     mockBalanceModel,
+	}
+
+	mockTradingPairModel.On("FindBySymbol", mock.Anything, "BTC/USDT").Return(&model.TradingPair{
+		Symbol:               "BTC/USDT",
+		BaseCurrency:         "BTC",
+		QuoteCurrency:        "USDT",
+		MinAmount:            "0.001",
+		MaxAmount:            "1000",
+		Status:               model.TradingPairStatusTrading,
+		MinVerificationLevel: model.UserVerificationAdvanced,
+	}, nil)
+
+	resp, err := NewCreateOrderLogic(ctx, svcCtx).CreateOrder(&types.CreateOrderRequest{
+		Symbol: "BTC/USDT",
+		Type:   1,
+		Side:   1,
+		Amount: "1.00000000",
+		Price:  "50000.00",
+	})
+
+	// 认证等级不足时不冻结资产
+	assert.Nil(t, resp)
+	assert.Equal(t, model.ErrVerificationLevelTooLow, err)
+	mockBalanceModel.AssertNotCalled(t, "FindByUserIDAndCurrency", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestCreateOrderLogic_CreateOrder_TradingPairNotFound(t *testing.T) {
 	mockTradingPairModel := &mockTradingPairModel{}
 
 	ctx := context.WithValue(context.Background(), "userId", "1")
 	svcCtx := &svc.ServiceContext{
+		UserRestrictionModel: &memoryRestrictionModel{},
+		UserModel:        &staticUserModel{},
 		TradingPairModel: mockTradingPairModel,
 	}
 
@@ -383,6 +639,8 @@ func TestCreateOrderLogic_CreateOrder_TradingPairDisabled(t *testing.T) {
 
 	ctx := context.WithValue(context.Background(), "userId", "1")
 	svcCtx := &svc.ServiceContext{
+		UserRestrictionModel: &memoryRestrictionModel{},
+		UserModel:        &staticUserModel{},
 		TradingPairModel: mockTradingPairModel,
 	}
 
@@ -418,11 +676,152 @@ func TestCreateOrderLogic_CreateOrder_TradingPairDisabled(t *testing.T) {
 	mockTradingPairModel.AssertExpectations(t)
 }
 
+func TestCreateOrderLogic_CreateOrder_PostOnlyWouldMatch(t *testing.T) {
+	mockOrderModel := &mockOrderModel{}
+	mockTradingPairModel := &mockTradingPairModel{}
+	mockBalanceModel := &mockBalanceModel{}
+	mockMatchingEngine := &mockMatchingEngine{}
+
+	ctx := context.WithValue(context.Background(), "userId", "1")
+	svcCtx := &svc.ServiceContext{
+		UserRestrictionModel: &memoryRestrictionModel{},
+		UserModel:        &staticUserModel{},
+		OrderModel:       mockOrderModel,
+		TradingPairModel: mockTradingPairModel,
+		BalanceModel:     mockBalanceModel,
+		LedgerEntryModel: newMockLedgerEntryModel(),
+		MatchingEngine:   mockMatchingEngine,
+	}
+
+	logic := &CreateOrderLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+
+	// 设置mock预期 - 交易对处于仅挂单状态，撮合引擎因订单会立即成交而拒单
+	tradingPair := &model.TradingPair{
+		ID:            1,
+		Symbol:        "BTC/USDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		MinAmount:     "0.001",
+		MaxAmount:     "1000",
+		PriceScale:    2,
+		AmountScale:   8,
+		Status:        model.TradingPairStatusPostOnly,
+	}
+
+	mockTradingPairModel.On("FindBySymbol", mock.Anything, "BTC/USDT").Return(tradingPair, nil)
+	mockBalanceModel.On("Trans", mock.Anything, mock.AnythingOfType("func(context.Context, sqlx.Session) error")).Return(nil)
+	mockBalanceModel.On("FreezeBalance", mock.Anything, uint64(1), "USDT", "50000").Return(nil)
+	mockOrderModel.On("Insert", mock.Anything, mock.AnythingOfType("*model.Order")).Return(&mockSqlResult{lastInsertId: 123}, nil)
+	mockMatchingEngine.On("ProcessOrder", mock.AnythingOfType("*model.Order")).Return(nil, model.ErrOrderWouldMatch)
+	// 拒单后撤销订单并解冻资产
+	mockOrderModel.On("Trans", mock.Anything, mock.AnythingOfType("func(context.Context, sqlx.Session) error")).Return(nil)
+	mockOrderModel.On("UpdateStatus", mock.Anything, uint64(123), int64(4)).Return(nil)
+	mockBalanceModel.On("UnfreezeBalance", mock.Anything, uint64(1), "USDT", "50000").Return(nil)
+
+	req := &types.CreateOrderRequest{
+		Symbol: "BTC/USDT",
+		Type:   1,
+		Side:   1,
+		Amount: "1.00000000",
+		Price:  "50000.00",
+	}
+
+	// 执行测试
+	resp, err := logic.CreateOrder(req)
+
+	// 验证结果
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Equal(t, model.ErrOrderWouldMatch, err)
+
+	mockTradingPairModel.AssertExpectations(t)
+	mockBalanceModel.AssertExpectations(t)
+	mockOrderModel.AssertExpectations(t)
+	mockMatchingEngine.AssertExpectations(t)
+}
+
+func TestCreateOrderLogic_CreateOrder_TradingPairFilters(t *testing.T) {
+	tradingPair := &model.TradingPair{
+		ID:            1,
+		Symbol:        "BTC/USDT",
+		BaseCurrency:  "BTC",
+		QuoteCurrency: "USDT",
+		MinAmount:     "0.001",
+		MaxAmount:     "1000",
+		PriceScale:    2,
+		AmountScale:   8,
+		TickSize:      "0.5",
+		StepSize:      "0.001",
+		MinNotional:   "10",
+		MaxOpenOrders: 2,
+		Status:        1,
+	}
+
+	tests := []struct {
+		name       string
+		amount     string
+		price      string
+		openOrders int64
+		wantErr    error
+	}{
+		{"price not multiple of tick size", "1.000", "50000.25", 0, model.ErrPriceTickSize},
+		{"amount not multiple of step size", "1.0005", "50000.50", 0, model.ErrAmountStepSize},
+		{"notional below minimum", "0.001", "5000.00", 0, model.ErrMinNotional},
+		{"too many open orders", "1.000", "50000.50", 2, model.ErrTooManyOpenOrders},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOrderModel := &mockOrderModel{}
+			mockTradingPairModel := &mockTradingPairModel{}
+			mockBalanceModel := &mockBalanceModel{}
+
+			ctx := context.WithValue(context.Background(), "userId", "1")
+			svcCtx := &svc.ServiceContext{
+				UserRestrictionModel: &memoryRestrictionModel{},
+				UserModel:        &staticUserModel{},
+				OrderModel:       mockOrderModel,
+				BalanceModel:     mockBalanceModel,
+				LedgerEntryModel: newMockLedgerEntryModel(),
+				TradingPairModel: mockTradingPairModel,
+			}
+
+			logic := &CreateOrderLogic{
+				Logger: logx.WithContext(ctx),
+				ctx:    ctx,
+				svcCtx: svcCtx,
+			}
+
+			mockTradingPairModel.On("FindBySymbol", mock.Anything, "BTC/USDT").Return(tradingPair, nil)
+			mockBalanceModel.On("Trans", mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockOrderModel.On("CountOpenByUserAndSymbolForUpdate", mock.Anything, uint64(1), "BTC/USDT").Return(tt.openOrders, nil).Maybe()
+
+			resp, err := logic.CreateOrder(&types.CreateOrderRequest{
+				Symbol: "BTC/USDT",
+				Type:   1,
+				Side:   1,
+				Amount: tt.amount,
+				Price:  tt.price,
+			})
+
+			assert.Nil(t, resp)
+			assert.ErrorIs(t, err, tt.wantErr)
+			mockTradingPairModel.AssertExpectations(t)
+		})
+	}
+}
+
 func TestCreateOrderLogic_CreateOrder_InvalidAmount(t *testing.T) {
 	mockTradingPairModel := &mockTradingPairModel{}
 
 	ctx := context.WithValue(context.Background(), "userId", "1")
 	svcCtx := &svc.ServiceContext{
+		UserRestrictionModel: &memoryRestrictionModel{},
+		UserModel:        &staticUserModel{},
 		TradingPairModel: mockTradingPairModel,
 	}
 
@@ -470,9 +869,12 @@ func TestCreateOrderLogic_CreateOrder_InsufficientBalance(t *testing.T) {
 
 	ctx := context.WithValue(context.Background(), "userId", "1")
 	svcCtx := &svc.ServiceContext{
+		UserRestrictionModel: &memoryRestrictionModel{},
+		UserModel:        &staticUserModel{},
 		OrderModel:       mockOrderModel,
 		TradingPairModel: mockTradingPairModel,
 		BalanceModel:     mockBalanceModel,
+		LedgerEntryModel: newMockLedgerEntryModel(),
 	}
 
 	logic := &CreateOrderLogic{
@@ -520,12 +922,17 @@ func TestCreateOrderLogic_CreateOrder_MarketBuyOrder(t *testing.T) {
 	mockOrderModel := &mockOrderModel{}
 	mockTradingPairModel := &mockTradingPairModel{}
 	mockBalanceModel := &mockBalanceModel{}
+	mockMatchingEngine := &mockMatchingEngine{}
 
 	ctx := context.WithValue(context.Background(), "userId", "1")
 	svcCtx := &svc.ServiceContext{
+		UserRestrictionModel: &memoryRestrictionModel{},
+		UserModel:        &staticUserModel{},
 		OrderModel:       mockOrderModel,
 		TradingPairModel: mockTradingPairModel,
 		BalanceModel:     mockBalanceModel,
+		LedgerEntryModel: newMockLedgerEntryModel(),
+		MatchingEngine:   mockMatchingEngine,
 	}
 
 	logic := &CreateOrderLogic{
@@ -549,6 +956,8 @@ func TestCreateOrderLogic_CreateOrder_MarketBuyOrder(t *testing.T) {
 	mockBalanceModel.On("Trans", mock.Anything, mock.AnythingOfType("func(context.Context, sqlx.Session) error")).Return(nil)
 	mockBalanceModel.On("FreezeBalance", mock.Anything, uint64(1), "USDT", "1000").Return(nil)
 	mockOrderModel.On("Insert", mock.Anything, mock.AnythingOfType("*model.Order")).Return(&mockSqlResult{lastInsertId: 124}, nil)
+	mockOrderModel.On("Update", mock.Anything, mock.AnythingOfType("*model.Order")).Return(nil)
+	mockMatchingEngine.On("ProcessOrder", mock.AnythingOfType("*model.Order")).Return(&matching.MatchResult{}, nil)
 
 	// 市价买单 - Amount表示要花费的USDT数量
 	req := &types.CreateOrderRequest{
@@ -569,22 +978,31 @@ func TestCreateOrderLogic_CreateOrder_MarketBuyOrder(t *testing.T) {
 	assert.Equal(t, int64(2), resp.Type)
 	assert.Equal(t, int64(1), resp.Side)
 	assert.Equal(t, "1000", resp.Amount)
+	assert.Empty(t, resp.Price)
+	assert.Equal(t, int64(1), resp.Status)
 
+	// 市价买单按花费金额冻结计价币种
 	mockTradingPairModel.AssertExpectations(t)
 	mockBalanceModel.AssertExpectations(t)
 	mockOrderModel.AssertExpectations(t)
+	mockMatchingEngine.AssertExpectations(t)
 }
 
 func TestCreateOrderLogic_CreateOrder_SellOrder(t *testing.T) {
 	mockOrderModel := &mockOrderModel{}
 	mockTradingPairModel := &mockTradingPairModel{}
 	mockBalanceModel := &mockBalanceModel{}
+	mockMatchingEngine := &mockMatchingEngine{}
 
 	ctx := context.WithValue(context.Background(), "userId", "1")
 	svcCtx := &svc.ServiceContext{
+		UserRestrictionModel: &memoryRestrictionModel{},
+		UserModel:        &staticUserModel{},
 		OrderModel:       mockOrderModel,
 		TradingPairModel: mockTradingPairModel,
 		BalanceModel:     mockBalanceModel,
+		LedgerEntryModel: newMockLedgerEntryModel(),
+		MatchingEngine:   mockMatchingEngine,
 	}
 
 	logic := &CreateOrderLogic{
@@ -608,6 +1026,8 @@ func TestCreateOrderLogic_CreateOrder_SellOrder(t *testing.T) {
 	mockBalanceModel.On("Trans", mock.Anything, mock.AnythingOfType("func(context.Context, sqlx.Session) error")).Return(nil)
 	mockBalanceModel.On("FreezeBalance", mock.Anything, uint64(1), "BTC", "1").Return(nil)
 	mockOrderModel.On("Insert", mock.Anything, mock.AnythingOfType("*model.Order")).Return(&mockSqlResult{lastInsertId: 125}, nil)
+	mockOrderModel.On("Update", mock.Anything, mock.AnythingOfType("*model.Order")).Return(nil)
+	mockMatchingEngine.On("ProcessOrder", mock.AnythingOfType("*model.Order")).Return(&matching.MatchResult{}, nil)
 
 	// 卖出订单 - 冻结基础币种
 	req := &types.CreateOrderRequest{
@@ -626,9 +1046,28 @@ func TestCreateOrderLogic_CreateOrder_SellOrder(t *testing.T) {
 	assert.NotNil(t, resp)
 	assert.Equal(t, uint64(125), resp.ID)
 	assert.Equal(t, int64(2), resp.Side)
+	assert.Equal(t, "1.00000000", resp.Amount)
+	assert.Equal(t, "50000.00", resp.Price)
 
+	// 卖单按数量冻结基础币种
 	mockTradingPairModel.AssertExpectations(t)
 	mockBalanceModel.AssertExpectations(t)
 	mockOrderModel.AssertExpectations(t)
+	mockMatchingEngine.AssertExpectations(t)
 }
 
+// memoryRestrictionModel 进程内账户限制，只实现限制校验用到的查询
+type memoryRestrictionModel struct {
+	model.UserRestrictionModel
+	restrictions []*model.UserRestriction
+}
+
+func (m *memoryRestrictionModel) FindActiveByUserID(ctx context.Context, userID uint64, now time.Time) ([]*model.UserRestriction, error) {
+	var resp []*model.UserRestriction
+	for _, r := range m.restrictions {
+		if r.UserID == userID && (!r.ExpiresAt.Valid || r.ExpiresAt.Time.After(now)) {
+			resp = append(resp, r)
+		}
+	}
+	return resp, nil
+}
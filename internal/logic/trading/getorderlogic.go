@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"crypto-exchange/internal/authctx"
 	"crypto-exchange/internal/svc"
 	"crypto-exchange/internal/types"
 	"crypto-exchange/model"
@@ -29,9 +30,9 @@ func NewGetOrderLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetOrder
 
 func (l *GetOrderLogic) GetOrder(orderID string) (resp *types.Order, err error) {
 	// 从JWT中获取用户ID
-	userID, err := l.getUserIDFromContext()
+	userID, err := authctx.UserID(l.ctx)
 	if err != nil {
-		return nil, err
+		return nil, model.ErrUnauthorized
 	}
 
 	// 转换订单ID
@@ -71,20 +72,3 @@ func (l *GetOrderLogic) GetOrder(orderID string) (resp *types.Order, err error)
 
 	return resp, nil
 }
-
-// getUserIDFromContext 从上下文中获取用户ID
-func (l *GetOrderLogic) getUserIDFromContext() (uint64, error) {
-	// 这里应该从JWT中间件设置的上下文中获取用户ID
-	// 为了演示，这里返回一个固定值，实际应用中需要从JWT token中解析
-	userIDStr := l.ctx.Value("userId")
-	if userIDStr == nil {
-		return 0, model.ErrUnauthorized
-	}
-
-	userID, err := strconv.ParseUint(userIDStr.(string), 10, 64)
-	if err != nil {
-		return 0, model.ErrUnauthorized
-	}
-
-	return userID, nil
-}
@@ -3,6 +3,7 @@ package trading
 import (
 	"context"
 	"errors"
+	"sort"
 	"strconv"
 
 	"crypto-exchange/internal/matching"
@@ -67,7 +68,7 @@ func (ms *MatchingService) executeTrades(matchResult *matching.MatchResult) erro
 	return ms.svcCtx.BalanceModel.Trans(ms.ctx, func(ctx context.Context, session sqlx.Session) error {
 		// 1. 创建所有成交记录
 		for _, trade := range matchResult.Trades {
-			if err := ms.createTradeRecord(ctx, trade); err != nil {
+			if err := ms.createTradeRecord(ctx, session, trade); err != nil {
 				ms.logger.Errorf("Failed to create trade record: %v", err)
 				return err
 			}
@@ -75,20 +76,24 @@ func (ms *MatchingService) executeTrades(matchResult *matching.MatchResult) erro
 
 		// 2. 更新所有订单状态
 		for _, order := range matchResult.UpdatedOrders {
-			if err := ms.updateOrderInDB(ctx, order); err != nil {
+			if err := ms.updateOrderInDB(ctx, session, order); err != nil {
 				ms.logger.Errorf("Failed to update order status: %v", err)
 				return err
 			}
 		}
 
 		// 3. 处理余额更新
-		if err := ms.updateUserBalances(ctx, matchResult.Trades); err != nil {
+		if err := ms.updateUserBalances(ctx, session, matchResult.Trades); err != nil {
 			ms.logger.Errorf("Failed to update user balances: %v", err)
 			return err
 		}
+		if err := ms.recordTradeLedger(ctx, session, matchResult.Trades); err != nil {
+			ms.logger.Errorf("Failed to record trade ledger: %v", err)
+			return err
+		}
 
 		// 4. 解冻已完全成交订单的余额
-		if err := ms.unfreezeCompletedOrderBalances(ctx, matchResult.FilledOrders); err != nil {
+		if err := ms.unfreezeCompletedOrderBalances(ctx, session, matchResult.FilledOrders); err != nil {
 			ms.logger.Errorf("Failed to unfreeze balances: %v", err)
 			return err
 		}
@@ -99,11 +104,14 @@ func (ms *MatchingService) executeTrades(matchResult *matching.MatchResult) erro
 }
 
 // createTradeRecord 创建成交记录
-func (ms *MatchingService) createTradeRecord(ctx context.Context, trade *model.Trade) error {
-	_, err := ms.svcCtx.TradeModel.Insert(ctx, trade)
+func (ms *MatchingService) createTradeRecord(ctx context.Context, session sqlx.Session, trade *model.Trade) error {
+	result, err := ms.svcCtx.TradeModel.WithSession(session).Insert(ctx, trade)
 	if err != nil {
 		return err
 	}
+	if tradeID, err := result.LastInsertId(); err == nil {
+		trade.ID = uint64(tradeID)
+	}
 
 	ms.logger.Infof("Trade record created: %s %s@%s between user %d and %d",
 		trade.Symbol, trade.Amount, trade.Price, trade.BuyUserID, trade.SellUserID)
@@ -111,8 +119,8 @@ func (ms *MatchingService) createTradeRecord(ctx context.Context, trade *model.T
 }
 
 // updateOrderInDB 更新订单状态到数据库
-func (ms *MatchingService) updateOrderInDB(ctx context.Context, order *model.Order) error {
-	err := ms.svcCtx.OrderModel.Update(ctx, order)
+func (ms *MatchingService) updateOrderInDB(ctx context.Context, session sqlx.Session, order *model.Order) error {
+	err := ms.svcCtx.OrderModel.WithSession(session).Update(ctx, order)
 	if err != nil {
 		return err
 	}
@@ -128,7 +136,7 @@ func (ms *MatchingService) updateOrderStatus(order *model.Order) error {
 }
 
 // updateUserBalances 更新用户余额
-func (ms *MatchingService) updateUserBalances(ctx context.Context, trades []*model.Trade) error {
+func (ms *MatchingService) updateUserBalances(ctx context.Context, session sqlx.Session, trades []*model.Trade) error {
 	// 聚合同一用户的余额变动
 	balanceChanges := make(map[string]decimal.Decimal) // userID_currency -> amount
 
@@ -158,10 +166,38 @@ func (ms *MatchingService) updateUserBalances(ctx context.Context, trades []*mod
 		balanceChanges[sellerQuoteKey] = balanceChanges[sellerQuoteKey].Add(totalValue)
 	}
 
-	// 应用余额变动
-	for userCurrencyKey, amount := range balanceChanges {
+	// 按固定顺序应用余额变动，保证并发成交时的加锁顺序一致
+	keys := make([]string, 0, len(balanceChanges))
+	for userCurrencyKey := range balanceChanges {
+		keys = append(keys, userCurrencyKey)
+	}
+	sort.Strings(keys)
+	for _, userCurrencyKey := range keys {
 		userID, currency := ms.parseUserCurrencyKey(userCurrencyKey)
-		if err := ms.applyBalanceChange(ctx, userID, currency, amount); err != nil {
+		if err := ms.applyBalanceChange(ctx, session, userID, currency, balanceChanges[userCurrencyKey]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordTradeLedger 为每笔成交记账：计价币种从买方划转给卖方，基础币种从卖方划转给买方
+func (ms *MatchingService) recordTradeLedger(ctx context.Context, session sqlx.Session, trades []*model.Trade) error {
+	for _, trade := range trades {
+		tradePrice, _ := decimal.NewFromString(trade.Price)
+		tradeAmount, _ := decimal.NewFromString(trade.Amount)
+		totalValue := tradePrice.Mul(tradeAmount)
+
+		baseCurrency, quoteCurrency, err := ms.parseSymbol(trade.Symbol)
+		if err != nil {
+			return err
+		}
+
+		journal := model.NewLedgerJournal(model.LedgerBizTrade, strconv.FormatUint(trade.ID, 10))
+		journal.Transfer(quoteCurrency, totalValue.String(), model.UserAvailable(trade.BuyUserID), model.UserAvailable(trade.SellUserID), "trade settlement")
+		journal.Transfer(baseCurrency, tradeAmount.String(), model.UserAvailable(trade.SellUserID), model.UserAvailable(trade.BuyUserID), "trade settlement")
+		if err := ms.svcCtx.LedgerEntryModel.WithSession(session).InsertJournal(ctx, journal); err != nil {
 			return err
 		}
 	}
@@ -170,7 +206,7 @@ func (ms *MatchingService) updateUserBalances(ctx context.Context, trades []*mod
 }
 
 // unfreezeCompletedOrderBalances 解冻已完全成交订单的余额
-func (ms *MatchingService) unfreezeCompletedOrderBalances(ctx context.Context, filledOrders []*model.Order) error {
+func (ms *MatchingService) unfreezeCompletedOrderBalances(ctx context.Context, session sqlx.Session, filledOrders []*model.Order) error {
 	for _, order := range filledOrders {
 		if order.Status != 3 { // 只处理完全成交的订单
 			continue
@@ -197,50 +233,32 @@ func (ms *MatchingService) unfreezeCompletedOrderBalances(ctx context.Context, f
 		}
 
 		// 执行解冻操作
-		err = ms.svcCtx.BalanceModel.UnfreezeBalance(ctx, order.UserID, currency, unfreezeAmount.String())
+		err = ms.svcCtx.BalanceModel.WithSession(session).UnfreezeBalance(ctx, order.UserID, currency, unfreezeAmount.String())
 		if err != nil {
 			ms.logger.Errorf("Failed to unfreeze balance for order %d: %v", order.ID, err)
 			return err
 		}
 
+		// 记账：用户冻结余额 -> 可用余额
+		journal := model.NewLedgerJournal(model.LedgerBizUnfreeze, strconv.FormatUint(order.ID, 10))
+		journal.Transfer(currency, unfreezeAmount.String(), model.UserFrozen(order.UserID), model.UserAvailable(order.UserID), "order filled")
+		if err := ms.svcCtx.LedgerEntryModel.WithSession(session).InsertJournal(ctx, journal); err != nil {
+			return err
+		}
+
 		ms.logger.Infof("Unfroze %s %s for completed order %d", unfreezeAmount.String(), currency, order.ID)
 	}
 
 	return nil
 }
 
-// applyBalanceChange 应用余额变动
-func (ms *MatchingService) applyBalanceChange(ctx context.Context, userID uint64, currency string, amount decimal.Decimal) error {
-	if amount.IsZero() {
-		return nil
-	}
-
-	// 查找用户余额记录
-	balance, err := ms.svcCtx.BalanceModel.FindByUserIDAndCurrency(ctx, userID, currency)
-	if err != nil {
-		if errors.Is(err, model.ErrNotFound) {
-			// 余额记录不存在，创建新记录
-			newBalance := &model.Balance{
-				UserID:    userID,
-				Currency:  currency,
-				Available: amount.String(),
-				Frozen:    "0",
-			}
-			_, err = ms.svcCtx.BalanceModel.Insert(ctx, newBalance)
-			return err
-		}
-		return err
-	}
-
-	// 更新现有余额
-	currentAvailable, _ := decimal.NewFromString(balance.Available)
-	newAvailable := currentAvailable.Add(amount)
-	
-	if newAvailable.LessThan(decimal.Zero) {
+// applyBalanceChange 应用余额变动，在事务中锁定余额行后增减可用余额
+func (ms *MatchingService) applyBalanceChange(ctx context.Context, session sqlx.Session, userID uint64, currency string, amount decimal.Decimal) error {
+	err := ms.svcCtx.BalanceModel.WithSession(session).AddAvailable(ctx, userID, currency, amount.String())
+	if errors.Is(err, model.ErrInsufficientBalance) {
 		return errors.New("insufficient balance after trade execution")
 	}
-
-	return ms.svcCtx.BalanceModel.UpdateBalance(ctx, userID, currency, newAvailable.String(), balance.Frozen)
+	return err
 }
 
 // parseSymbol 解析交易对符号，返回基础币种和计价币种
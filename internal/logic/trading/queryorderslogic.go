@@ -2,9 +2,9 @@ package trading
 
 import (
 	"context"
-	"strconv"
 	"time"
 
+	"crypto-exchange/internal/authctx"
 	"crypto-exchange/internal/svc"
 	"crypto-exchange/internal/types"
 	"crypto-exchange/model"
@@ -28,9 +28,9 @@ func NewQueryOrdersLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Query
 
 func (l *QueryOrdersLogic) QueryOrders(req *types.QueryOrdersRequest) (resp *types.OrderListResponse, err error) {
 	// 从JWT中获取用户ID
-	userID, err := l.getUserIDFromContext()
+	userID, err := authctx.UserID(l.ctx)
 	if err != nil {
-		return nil, err
+		return nil, model.ErrUnauthorized
 	}
 
 	// 设置默认分页参数
@@ -78,20 +78,3 @@ func (l *QueryOrdersLogic) QueryOrders(req *types.QueryOrdersRequest) (resp *typ
 
 	return resp, nil
 }
-
-// getUserIDFromContext 从上下文中获取用户ID
-func (l *QueryOrdersLogic) getUserIDFromContext() (uint64, error) {
-	// 这里应该从JWT中间件设置的上下文中获取用户ID
-	// 为了演示，这里返回一个固定值，实际应用中需要从JWT token中解析
-	userIDStr := l.ctx.Value("userId")
-	if userIDStr == nil {
-		return 0, model.ErrUnauthorized
-	}
-
-	userID, err := strconv.ParseUint(userIDStr.(string), 10, 64)
-	if err != nil {
-		return 0, model.ErrUnauthorized
-	}
-
-	return userID, nil
-}
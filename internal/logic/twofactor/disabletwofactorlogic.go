@@ -0,0 +1,48 @@
+package twofactor
+
+import (
+	"context"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/securityevent"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DisableTwoFactorLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDisableTwoFactorLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DisableTwoFactorLogic {
+	return &DisableTwoFactorLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// DisableTwoFactor 关闭两步验证，需要提供验证码或恢复码
+func (l *DisableTwoFactorLogic) DisableTwoFactor(req *types.TwoFactorCodeRequest) (resp *types.TwoFactorStatusResponse, err error) {
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	if err := Verify(l.ctx, l.svcCtx, userID, req.Code, true); err != nil {
+		return nil, err
+	}
+
+	if err := l.svcCtx.UserTotpModel.DeleteByUserID(l.ctx, userID); err != nil {
+		l.Errorf("Failed to disable two-factor for user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	securityevent.Record(l.ctx, l.svcCtx, userID, model.SecurityEventTwoFactorDisabled, "")
+	l.Infof("User %d disabled two-factor authentication", userID)
+	return &types.TwoFactorStatusResponse{Enabled: false}, nil
+}
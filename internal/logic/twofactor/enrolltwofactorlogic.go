@@ -0,0 +1,102 @@
+package twofactor
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/totp"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type EnrollTwoFactorLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewEnrollTwoFactorLogic(ctx context.Context, svcCtx *svc.ServiceContext) *EnrollTwoFactorLogic {
+	return &EnrollTwoFactorLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// EnrollTwoFactor 生成新的TOTP密钥和恢复码，验证首个验证码后才正式启用
+// 重复调用会替换尚未验证的密钥；已启用时需要先关闭
+func (l *EnrollTwoFactorLogic) EnrollTwoFactor() (resp *types.TwoFactorEnrollResponse, err error) {
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	user, err := l.svcCtx.UserModel.FindOne(l.ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	record, err := l.svcCtx.UserTotpModel.FindOneByUserID(l.ctx, userID)
+	if err != nil && !errors.Is(err, model.ErrNotFound) {
+		l.Errorf("Failed to find two-factor settings of user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+	if record != nil {
+		if record.Status == model.UserTotpStatusEnabled {
+			return nil, model.ErrTwoFactorAlreadyEnabled
+		}
+		if err := l.svcCtx.UserTotpModel.DeleteByUserID(l.ctx, userID); err != nil {
+			l.Errorf("Failed to delete pending two-factor enrollment of user %d: %v", userID, err)
+			return nil, model.ErrInternalServer
+		}
+	}
+
+	// 生成密钥和恢复码，密钥加密保存，恢复码只保存摘要
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		l.Errorf("Failed to generate two-factor secret: %v", err)
+		return nil, model.ErrInternalServer
+	}
+	encrypted, err := l.svcCtx.TOTP.Encrypt(secret)
+	if err != nil {
+		l.Errorf("Failed to encrypt two-factor secret: %v", err)
+		return nil, model.ErrInternalServer
+	}
+	recoveryCodes, err := totp.GenerateRecoveryCodes(l.svcCtx.Config.TwoFactor.RecoveryCodes)
+	if err != nil {
+		l.Errorf("Failed to generate recovery codes: %v", err)
+		return nil, model.ErrInternalServer
+	}
+	hashes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		hashes = append(hashes, totp.HashRecoveryCode(code))
+	}
+
+	now := l.svcCtx.TOTP.Now()
+	_, err = l.svcCtx.UserTotpModel.Insert(l.ctx, &model.UserTotp{
+		UserID:          userID,
+		SecretEncrypted: encrypted,
+		RecoveryCodes:   strings.Join(hashes, ","),
+		Status:          model.UserTotpStatusPending,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	})
+	if err != nil {
+		l.Errorf("Failed to insert two-factor enrollment of user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	return &types.TwoFactorEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(l.svcCtx.TOTP.Issuer, user.Email, secret),
+		RecoveryCodes:   recoveryCodes,
+	}, nil
+}
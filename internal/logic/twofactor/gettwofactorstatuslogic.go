@@ -0,0 +1,52 @@
+package twofactor
+
+import (
+	"context"
+	"errors"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetTwoFactorStatusLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetTwoFactorStatusLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetTwoFactorStatusLogic {
+	return &GetTwoFactorStatusLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetTwoFactorStatus 查询两步验证是否已启用及剩余恢复码数量
+func (l *GetTwoFactorStatusLogic) GetTwoFactorStatus() (resp *types.TwoFactorStatusResponse, err error) {
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	record, err := l.svcCtx.UserTotpModel.FindOneByUserID(l.ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return &types.TwoFactorStatusResponse{}, nil
+		}
+		l.Errorf("Failed to find two-factor settings of user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+	if record.Status != model.UserTotpStatusEnabled {
+		return &types.TwoFactorStatusResponse{}, nil
+	}
+
+	return &types.TwoFactorStatusResponse{
+		Enabled:                true,
+		RecoveryCodesRemaining: int64(len(record.RecoveryCodeHashes())),
+	}, nil
+}
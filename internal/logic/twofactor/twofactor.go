@@ -0,0 +1,107 @@
+package twofactor
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/totp"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// IsEnabled 判断用户是否已启用两步验证
+func IsEnabled(ctx context.Context, svcCtx *svc.ServiceContext, userID uint64) (bool, error) {
+	record, err := svcCtx.UserTotpModel.FindOneByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return record.Status == model.UserTotpStatusEnabled, nil
+}
+
+// Require 已启用两步验证的用户必须提供新的TOTP验证码（不接受恢复码），未启用时直接通过
+// 用于提现、创建API密钥等敏感操作
+func Require(ctx context.Context, svcCtx *svc.ServiceContext, userID uint64, code string) error {
+	record, err := svcCtx.UserTotpModel.FindOneByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil
+		}
+		logx.WithContext(ctx).Errorf("Failed to find two-factor settings of user %d: %v", userID, err)
+		return model.ErrInternalServer
+	}
+	if record.Status != model.UserTotpStatusEnabled {
+		return nil
+	}
+	return verify(ctx, svcCtx, record, code, false)
+}
+
+// Verify 校验已启用两步验证用户的验证码，allowRecovery为true时也接受恢复码
+// 用于两步登录和关闭两步验证
+func Verify(ctx context.Context, svcCtx *svc.ServiceContext, userID uint64, code string, allowRecovery bool) error {
+	record, err := svcCtx.UserTotpModel.FindOneByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return model.ErrTwoFactorNotEnabled
+		}
+		logx.WithContext(ctx).Errorf("Failed to find two-factor settings of user %d: %v", userID, err)
+		return model.ErrInternalServer
+	}
+	if record.Status != model.UserTotpStatusEnabled {
+		return model.ErrTwoFactorNotEnabled
+	}
+	return verify(ctx, svcCtx, record, code, allowRecovery)
+}
+
+// verify 校验验证码并标记为已使用，TOTP验证码的时间步和恢复码都只能使用一次
+func verify(ctx context.Context, svcCtx *svc.ServiceContext, record *model.UserTotp, code string, allowRecovery bool) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return model.ErrTwoFactorCodeRequired
+	}
+
+	step, ok, err := svcCtx.TOTP.Verify(record.SecretEncrypted, code, record.LastUsedStep)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("Failed to decrypt two-factor secret of user %d: %v", record.UserID, err)
+		return model.ErrInternalServer
+	}
+	if ok {
+		used, err := svcCtx.UserTotpModel.UseStep(ctx, record.ID, step)
+		if err != nil {
+			logx.WithContext(ctx).Errorf("Failed to record two-factor step of user %d: %v", record.UserID, err)
+			return model.ErrInternalServer
+		}
+		if !used {
+			return model.ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	if allowRecovery {
+		hash := totp.HashRecoveryCode(code)
+		hashes := record.RecoveryCodeHashes()
+		for i, h := range hashes {
+			if h != hash {
+				continue
+			}
+			remaining := append(append([]string{}, hashes[:i]...), hashes[i+1:]...)
+			used, err := svcCtx.UserTotpModel.UseRecoveryCode(ctx, record.ID, record.RecoveryCodes, strings.Join(remaining, ","))
+			if err != nil {
+				logx.WithContext(ctx).Errorf("Failed to consume recovery code of user %d: %v", record.UserID, err)
+				return model.ErrInternalServer
+			}
+			if !used {
+				return model.ErrInvalidTwoFactorCode
+			}
+			logx.WithContext(ctx).Infof("User %d used a recovery code, %d remaining", record.UserID, len(remaining))
+			return nil
+		}
+	}
+
+	return model.ErrInvalidTwoFactorCode
+}
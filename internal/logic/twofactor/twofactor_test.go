@@ -0,0 +1,186 @@
+package twofactor
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/config"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/totp"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 以下内存实现只覆盖两步验证逻辑用到的方法
+
+type memoryUserModel struct {
+	model.UserModel
+}
+
+func (m *memoryUserModel) FindOne(ctx context.Context, id uint64) (*model.User, error) {
+	return &model.User{ID: id, Email: "alice@example.com", Status: model.UserStatusActive}, nil
+}
+
+type memoryUserTotpModel struct {
+	model.UserTotpModel
+	records map[uint64]*model.UserTotp
+}
+
+func (m *memoryUserTotpModel) Insert(ctx context.Context, data *model.UserTotp) (sql.Result, error) {
+	data.ID = data.UserID
+	m.records[data.UserID] = data
+	return nil, nil
+}
+
+func (m *memoryUserTotpModel) FindOneByUserID(ctx context.Context, userID uint64) (*model.UserTotp, error) {
+	record, ok := m.records[userID]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	copied := *record
+	return &copied, nil
+}
+
+func (m *memoryUserTotpModel) Enable(ctx context.Context, id uint64, step int64, enabledAt time.Time) error {
+	record := m.records[id]
+	record.Status, record.LastUsedStep = model.UserTotpStatusEnabled, step
+	return nil
+}
+
+func (m *memoryUserTotpModel) UseStep(ctx context.Context, id uint64, step int64) (bool, error) {
+	record := m.records[id]
+	if step <= record.LastUsedStep {
+		return false, nil
+	}
+	record.LastUsedStep = step
+	return true, nil
+}
+
+func (m *memoryUserTotpModel) UseRecoveryCode(ctx context.Context, id uint64, oldCodes, newCodes string) (bool, error) {
+	record := m.records[id]
+	if record.RecoveryCodes != oldCodes {
+		return false, nil
+	}
+	record.RecoveryCodes = newCodes
+	return true, nil
+}
+
+func (m *memoryUserTotpModel) DeleteByUserID(ctx context.Context, userID uint64) error {
+	delete(m.records, userID)
+	return nil
+}
+
+type memorySecurityEventModel struct {
+	model.SecurityEventModel
+	events []*model.SecurityEvent
+}
+
+func (m *memorySecurityEventModel) Insert(ctx context.Context, data *model.SecurityEvent) (sql.Result, error) {
+	m.events = append(m.events, data)
+	return nil, nil
+}
+
+type fixture struct {
+	svcCtx  *svc.ServiceContext
+	records *memoryUserTotpModel
+	events  *memorySecurityEventModel
+	now     time.Time
+}
+
+// newFixture 使用固定时钟，测试中通过修改f.now推进时间
+func newFixture() *fixture {
+	f := &fixture{
+		records: &memoryUserTotpModel{records: make(map[uint64]*model.UserTotp)},
+		events:  &memorySecurityEventModel{},
+		now:     time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	authenticator := totp.NewAuthenticator("CryptoExchange", "test-encryption-key", 1)
+	authenticator.Now = func() time.Time { return f.now }
+
+	var c config.Config
+	c.TwoFactor.RecoveryCodes = 3
+	f.svcCtx = &svc.ServiceContext{
+		Config:             c,
+		UserModel:          &memoryUserModel{},
+		UserTotpModel:      f.records,
+		TOTP:               authenticator,
+		SecurityEventModel: f.events,
+	}
+	return f
+}
+
+func (f *fixture) code(t *testing.T, secret string) string {
+	code, err := totp.CodeAt(secret, totp.Step(f.now))
+	assert.NoError(t, err)
+	return code
+}
+
+func TestTwoFactorLifecycle(t *testing.T) {
+	f := newFixture()
+	ctx := authctx.NewUserContext(1)
+
+	// 未启用时敏感操作不需要验证码
+	assert.NoError(t, Require(ctx, f.svcCtx, 1, ""))
+
+	enrolled, err := NewEnrollTwoFactorLogic(ctx, f.svcCtx).EnrollTwoFactor()
+	assert.NoError(t, err)
+	assert.Len(t, enrolled.RecoveryCodes, 3)
+	assert.Contains(t, enrolled.ProvisioningURI, "otpauth://totp/")
+	assert.NotContains(t, f.records.records[1].SecretEncrypted, enrolled.Secret)
+
+	// 注册后尚未验证，仍不要求验证码
+	assert.NoError(t, Require(ctx, f.svcCtx, 1, ""))
+
+	_, err = NewVerifyTwoFactorLogic(ctx, f.svcCtx).VerifyTwoFactor(&types.TwoFactorCodeRequest{Code: "000000"})
+	assert.ErrorIs(t, err, model.ErrInvalidTwoFactorCode)
+	status, err := NewVerifyTwoFactorLogic(ctx, f.svcCtx).VerifyTwoFactor(&types.TwoFactorCodeRequest{Code: f.code(t, enrolled.Secret)})
+	assert.NoError(t, err)
+	assert.True(t, status.Enabled)
+
+	_, err = NewEnrollTwoFactorLogic(ctx, f.svcCtx).EnrollTwoFactor()
+	assert.ErrorIs(t, err, model.ErrTwoFactorAlreadyEnabled)
+
+	// 启用后必须提供验证码，启用时使用的验证码不能再次使用
+	assert.ErrorIs(t, Require(ctx, f.svcCtx, 1, ""), model.ErrTwoFactorCodeRequired)
+	assert.ErrorIs(t, Require(ctx, f.svcCtx, 1, f.code(t, enrolled.Secret)), model.ErrInvalidTwoFactorCode)
+
+	f.now = f.now.Add(totp.Period * time.Second)
+	assert.NoError(t, Require(ctx, f.svcCtx, 1, f.code(t, enrolled.Secret)))
+
+	// 敏感操作不接受恢复码
+	assert.ErrorIs(t, Require(ctx, f.svcCtx, 1, enrolled.RecoveryCodes[0]), model.ErrInvalidTwoFactorCode)
+
+	// 恢复码只能使用一次
+	assert.NoError(t, Verify(ctx, f.svcCtx, 1, enrolled.RecoveryCodes[0], true))
+	assert.ErrorIs(t, Verify(ctx, f.svcCtx, 1, enrolled.RecoveryCodes[0], true), model.ErrInvalidTwoFactorCode)
+
+	status, err = NewGetTwoFactorStatusLogic(ctx, f.svcCtx).GetTwoFactorStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), status.RecoveryCodesRemaining)
+
+	status, err = NewDisableTwoFactorLogic(ctx, f.svcCtx).DisableTwoFactor(&types.TwoFactorCodeRequest{Code: enrolled.RecoveryCodes[1]})
+	assert.NoError(t, err)
+	assert.False(t, status.Enabled)
+	assert.NoError(t, Require(ctx, f.svcCtx, 1, ""))
+
+	// 启用和关闭都记录为安全事件
+	if assert.Len(t, f.events.events, 2) {
+		assert.Equal(t, model.SecurityEventTwoFactorEnabled, f.events.events[0].EventType)
+		assert.Equal(t, model.SecurityEventTwoFactorDisabled, f.events.events[1].EventType)
+	}
+}
+
+func TestVerifyTwoFactor_NotEnrolled(t *testing.T) {
+	f := newFixture()
+
+	_, err := NewVerifyTwoFactorLogic(authctx.NewUserContext(1), f.svcCtx).VerifyTwoFactor(&types.TwoFactorCodeRequest{Code: "123456"})
+	assert.ErrorIs(t, err, model.ErrTwoFactorNotEnrolled)
+
+	_, err = NewDisableTwoFactorLogic(authctx.NewUserContext(1), f.svcCtx).DisableTwoFactor(&types.TwoFactorCodeRequest{Code: "123456"})
+	assert.ErrorIs(t, err, model.ErrTwoFactorNotEnabled)
+}
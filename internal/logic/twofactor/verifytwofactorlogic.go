@@ -0,0 +1,72 @@
+package twofactor
+
+import (
+	"context"
+	"errors"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/logic/securityevent"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type VerifyTwoFactorLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewVerifyTwoFactorLogic(ctx context.Context, svcCtx *svc.ServiceContext) *VerifyTwoFactorLogic {
+	return &VerifyTwoFactorLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// VerifyTwoFactor 验证认证器App生成的首个验证码，通过后启用两步验证
+func (l *VerifyTwoFactorLogic) VerifyTwoFactor(req *types.TwoFactorCodeRequest) (resp *types.TwoFactorStatusResponse, err error) {
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	record, err := l.svcCtx.UserTotpModel.FindOneByUserID(l.ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrTwoFactorNotEnrolled
+		}
+		l.Errorf("Failed to find two-factor settings of user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+	if record.Status == model.UserTotpStatusEnabled {
+		return nil, model.ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok, err := l.svcCtx.TOTP.Verify(record.SecretEncrypted, req.Code, record.LastUsedStep)
+	if err != nil {
+		l.Errorf("Failed to decrypt two-factor secret of user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+	if !ok {
+		return nil, model.ErrInvalidTwoFactorCode
+	}
+
+	if err := l.svcCtx.UserTotpModel.Enable(l.ctx, record.ID, step, l.svcCtx.TOTP.Now()); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrTwoFactorNotEnrolled
+		}
+		l.Errorf("Failed to enable two-factor for user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	securityevent.Record(l.ctx, l.svcCtx, userID, model.SecurityEventTwoFactorEnabled, "")
+	l.Infof("User %d enabled two-factor authentication", userID)
+	return &types.TwoFactorStatusResponse{
+		Enabled:                true,
+		RecoveryCodesRemaining: int64(len(record.RecoveryCodeHashes())),
+	}, nil
+}
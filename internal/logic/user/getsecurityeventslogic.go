@@ -0,0 +1,73 @@
+package user
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type GetSecurityEventsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGetSecurityEventsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GetSecurityEventsLogic {
+	return &GetSecurityEventsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// GetSecurityEvents 分页查询当前用户的账户安全事件，按时间倒序
+func (l *GetSecurityEventsLogic) GetSecurityEvents(req *types.SecurityEventListRequest) (resp *types.SecurityEventListResponse, err error) {
+	userID, err := authctx.UserID(l.ctx)
+	if err != nil {
+		return nil, model.ErrUnauthorized
+	}
+
+	page := req.Page
+	if page <= 0 {
+		page = 1
+	}
+	size := req.Size
+	if size <= 0 {
+		size = 20
+	}
+	if size > 100 {
+		size = 100
+	}
+
+	events, total, err := l.svcCtx.SecurityEventModel.FindByUserID(l.ctx, userID, strings.TrimSpace(req.Type), page, size)
+	if err != nil {
+		l.Errorf("Failed to find security events of user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+
+	resp = &types.SecurityEventListResponse{
+		Events: make([]types.SecurityEvent, 0, len(events)),
+		Total:  total,
+		Page:   page,
+		Size:   size,
+	}
+	for _, e := range events {
+		resp.Events = append(resp.Events, types.SecurityEvent{
+			ID:                e.ID,
+			EventType:         e.EventType,
+			IP:                e.IP,
+			UserAgent:         e.UserAgent,
+			DeviceFingerprint: e.DeviceFingerprint,
+			Detail:            e.Detail,
+			CreatedAt:         e.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
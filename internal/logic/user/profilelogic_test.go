@@ -11,7 +11,6 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-	"github.com/zeromicro/go-zero/core/logx"
 	"github.com/zeromicro/go-zero/core/stores/redis"
 )
 
@@ -51,6 +50,36 @@ func (m *MockUserModel) Delete(ctx context.Context, id uint64) error {
 	return args.Error(0)
 }
 
+func (m *MockUserModel) UpdateWithdrawWhitelistOnly(ctx context.Context, id uint64, whitelistOnly bool, until sql.NullTime) error {
+	args := m.Called(ctx, id, whitelistOnly, until)
+	return args.Error(0)
+}
+
+func (m *MockUserModel) UpdatePassword(ctx context.Context, id uint64, password string) error {
+	args := m.Called(ctx, id, password)
+	return args.Error(0)
+}
+
+func (m *MockUserModel) UpdateStatus(ctx context.Context, id uint64, status int64) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+func (m *MockUserModel) MarkEmailVerified(ctx context.Context, id uint64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserModel) FindByParentID(ctx context.Context, parentID uint64) ([]*model.User, error) {
+	args := m.Called(ctx, parentID)
+	return args.Get(0).([]*model.User), args.Error(1)
+}
+
+func (m *MockUserModel) CountByParentID(ctx context.Context, parentID uint64) (int64, error) {
+	args := m.Called(ctx, parentID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // MockResult 模拟SQL结果
 type MockResult struct {
 	mock.Mock
@@ -220,83 +249,3 @@ func TestProfileLogic_Profile(t *testing.T) {
 		})
 	}
 }
-
-func TestProfileLogic_getUserIDFromContext(t *testing.T) {
-	tests := []struct {
-		name    string
-		userID  interface{}
-		want    uint64
-		wantErr bool
-	}{
-		{
-			name:    "float64类型",
-			userID:  float64(123),
-			want:    123,
-			wantErr: false,
-		},
-		{
-			name:    "uint64类型",
-			userID:  uint64(456),
-			want:    456,
-			wantErr: false,
-		},
-		{
-			name:    "int64类型",
-			userID:  int64(789),
-			want:    789,
-			wantErr: false,
-		},
-		{
-			name:    "int类型",
-			userID:  int(101112),
-			want:    101112,
-			wantErr: false,
-		},
-		{
-			name:    "nil值",
-			userID:  nil,
-			want:    0,
-			wantErr: true,
-		},
-		{
-			name:    "string类型（无效）",
-			userID:  "123",
-			want:    0,
-			wantErr: true,
-		},
-		{
-			name:    "bool类型（无效）",
-			userID:  true,
-			want:    0,
-			wantErr: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// 创建带有用户ID的上下文
-			ctx := context.Background()
-			if tt.userID != nil {
-				ctx = context.WithValue(ctx, "userId", tt.userID)
-			}
-
-			// 创建逻辑实例
-			logic := &ProfileLogic{
-				Logger: logx.WithContext(ctx),
-				ctx:    ctx,
-			}
-
-			// 执行测试
-			got, err := logic.getUserIDFromContext()
-
-			// 验证结果
-			if tt.wantErr {
-				assert.Error(t, err)
-				assert.Equal(t, uint64(0), got)
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, tt.want, got)
-			}
-		})
-	}
-}
\ No newline at end of file
@@ -0,0 +1,137 @@
+package useremail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"crypto-exchange/internal/mailer"
+	"crypto-exchange/internal/onetime"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 一次性令牌用途
+const (
+	PurposeVerifyEmail   = "verify_email"
+	PurposeResetPassword = "reset_password"
+)
+
+// Require 未验证邮箱的用户不能交易和提现，校验通过时返回用户信息供后续校验使用
+func Require(ctx context.Context, svcCtx *svc.ServiceContext, userID uint64) (*model.User, error) {
+	user, err := svcCtx.UserModel.FindOne(ctx, userID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrUserNotFound
+		}
+		logx.WithContext(ctx).Errorf("Failed to find user %d: %v", userID, err)
+		return nil, model.ErrInternalServer
+	}
+	if !user.EmailVerified() {
+		return nil, model.ErrEmailNotVerified
+	}
+	return user, nil
+}
+
+// SendVerification 生成邮箱验证令牌并发送验证邮件，用户之前未使用的验证链接随之失效
+func SendVerification(ctx context.Context, svcCtx *svc.ServiceContext, user *model.User) error {
+	cfg := svcCtx.Config.Email
+	token, err := issue(ctx, svcCtx, PurposeVerifyEmail, user.ID, cfg.VerifyTokenTTL)
+	if err != nil {
+		return err
+	}
+	return send(ctx, svcCtx, mailer.Message{
+		To:      user.Email,
+		Subject: "请验证您的邮箱",
+		Body: fmt.Sprintf("您好 %s：\n\n请在%s内打开以下链接完成邮箱验证，验证后即可交易和提现：\n\n%s\n\n如果这不是您本人的操作，请忽略本邮件。\n",
+			user.Nickname, formatTTL(cfg.VerifyTokenTTL), Link(cfg.VerifyURL, token)),
+	})
+}
+
+// SendPasswordReset 生成重置密码令牌并发送重置邮件
+func SendPasswordReset(ctx context.Context, svcCtx *svc.ServiceContext, user *model.User) error {
+	cfg := svcCtx.Config.Email
+	token, err := issue(ctx, svcCtx, PurposeResetPassword, user.ID, cfg.ResetTokenTTL)
+	if err != nil {
+		return err
+	}
+	return send(ctx, svcCtx, mailer.Message{
+		To:      user.Email,
+		Subject: "重置密码",
+		Body: fmt.Sprintf("您好 %s：\n\n我们收到了重置您账户密码的请求，请在%s内打开以下链接设置新密码：\n\n%s\n\n重置密码后所有设备上的登录都将失效。如果这不是您本人的操作，请忽略本邮件，您的密码不会被修改。\n",
+			user.Nickname, formatTTL(cfg.ResetTokenTTL), Link(cfg.ResetURL, token)),
+	})
+}
+
+// SendWithdrawConfirmCode 发送提现确认码邮件，确认码只出现在邮件正文中，不写入日志
+func SendWithdrawConfirmCode(ctx context.Context, svcCtx *svc.ServiceContext, user *model.User, tx *model.AssetTransaction, code string, ttl int64) error {
+	return send(ctx, svcCtx, mailer.Message{
+		To:      user.Email,
+		Subject: "提现确认码",
+		Body: fmt.Sprintf("您好 %s：\n\n您正在提现 %s %s（%s网络）到地址：\n%s\n\n确认码：%s\n\n请在%s内输入确认码完成提现，逾期提现将自动取消。如果这不是您本人的操作，请不要泄露确认码，并立即修改密码或联系客服冻结账户。\n",
+			user.Nickname, tx.Amount, tx.Currency, tx.Network, tx.Address, code, formatTTL(ttl)),
+	})
+}
+
+// SendPasswordChanged 通知用户密码已被重置，发送失败只记录日志
+func SendPasswordChanged(ctx context.Context, svcCtx *svc.ServiceContext, user *model.User) {
+	err := svcCtx.Mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "您的密码已重置",
+		Body: fmt.Sprintf("您好 %s：\n\n您的账户密码已于%s重置，所有设备上的登录均已失效。如果这不是您本人的操作，请立即联系客服冻结账户。\n",
+			user.Nickname, time.Now().Format("2006-01-02 15:04:05")),
+	})
+	if err != nil {
+		logx.WithContext(ctx).Errorf("Failed to send password changed notice to user %d: %v", user.ID, err)
+	}
+}
+
+// Link 在页面地址后附加token查询参数
+func Link(base, token string) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return base + "?token=" + url.QueryEscape(token)
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func issue(ctx context.Context, svcCtx *svc.ServiceContext, purpose string, userID uint64, ttl int64) (string, error) {
+	interval := time.Duration(svcCtx.Config.Email.ResendInterval) * time.Second
+	token, err := svcCtx.UserTokens.Issue(ctx, purpose, userID, time.Duration(ttl)*time.Second, interval)
+	if err != nil {
+		if errors.Is(err, onetime.ErrTokenIssueTooOften) {
+			return "", model.ErrEmailSendTooFrequently
+		}
+		logx.WithContext(ctx).Errorf("Failed to issue %s token for user %d: %v", purpose, userID, err)
+		return "", model.ErrInternalServer
+	}
+	return token, nil
+}
+
+func send(ctx context.Context, svcCtx *svc.ServiceContext, msg mailer.Message) error {
+	if err := svcCtx.Mailer.Send(ctx, msg); err != nil {
+		logx.WithContext(ctx).Errorf("Failed to send mail %q: %v", msg.Subject, err)
+		return model.ErrInternalServer
+	}
+	return nil
+}
+
+// formatTTL 将有效期格式化为邮件中显示的时长
+func formatTTL(seconds int64) string {
+	d := time.Duration(seconds) * time.Second
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%d小时", d/time.Hour)
+	case d >= time.Minute && d%time.Minute == 0:
+		return fmt.Sprintf("%d分钟", d/time.Minute)
+	default:
+		return fmt.Sprintf("%d秒", seconds)
+	}
+}
@@ -0,0 +1,202 @@
+package withdrawal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"crypto-exchange/internal/chain"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+// dispatchBatchSize 每轮处理的提现数量上限
+const dispatchBatchSize = 100
+
+// Dispatcher 提现广播与链上状态跟踪：广播已审核的提现，再按链上状态和网络要求的确认数确认或标记失败
+type Dispatcher struct {
+	logx.Logger
+	ctx      context.Context
+	svcCtx   *svc.ServiceContext
+	workflow *Workflow
+}
+
+// NewDispatcher 创建提现广播任务
+func NewDispatcher(ctx context.Context, svcCtx *svc.ServiceContext) *Dispatcher {
+	return &Dispatcher{
+		Logger:   logx.WithContext(ctx),
+		ctx:      ctx,
+		svcCtx:   svcCtx,
+		workflow: NewWorkflow(ctx, svcCtx),
+	}
+}
+
+// RunOnce 执行一轮过期取消、广播和状态跟踪，单笔提现处理失败时记录日志并在下一轮重试
+func (d *Dispatcher) RunOnce() error {
+	if err := d.expireUnconfirmed(); err != nil {
+		return err
+	}
+	if err := d.broadcastApproved(); err != nil {
+		return err
+	}
+	return d.trackBroadcasting()
+}
+
+// expireUnconfirmed 取消确认码已过期仍未确认的提现，冻结金额退回可用余额
+func (d *Dispatcher) expireUnconfirmed() error {
+	ttl := time.Duration(d.svcCtx.Config.Withdraw.ConfirmTTL) * time.Second
+	if ttl <= 0 {
+		return nil
+	}
+
+	withdrawals, err := d.svcCtx.AssetTransactionModel.FindByTypeAndStatus(d.ctx,
+		model.AssetTransactionTypeWithdraw, model.AssetTransactionStatusUnconfirmed, dispatchBatchSize, 0)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(-ttl)
+	for _, tx := range withdrawals {
+		// 按创建时间排序，之后的提现都未过期
+		if tx.CreatedAt.After(deadline) {
+			break
+		}
+		if _, err := d.workflow.Cancel(tx.TransactionID, SystemOperator, "confirmation expired"); err != nil {
+			d.Errorf("Failed to cancel unconfirmed withdrawal %s: %v", tx.TransactionID, err)
+		}
+	}
+
+	return nil
+}
+
+// broadcastApproved 广播已审核的提现；被链拒绝的提现标记为失败并退回冻结金额
+func (d *Dispatcher) broadcastApproved() error {
+	withdrawals, err := d.svcCtx.AssetTransactionModel.FindByTypeAndStatus(d.ctx,
+		model.AssetTransactionTypeWithdraw, model.AssetTransactionStatusApproved, dispatchBatchSize, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range withdrawals {
+		adapter, err := d.svcCtx.ChainAdapters.Get(tx.Network)
+		if err != nil {
+			d.Errorf("Cannot broadcast withdrawal %s: %v", tx.TransactionID, err)
+			continue
+		}
+
+		txHash, err := adapter.Broadcast(d.ctx, &chain.BroadcastRequest{
+			Reference: tx.TransactionID,
+			Currency:  tx.Currency,
+			Address:   tx.Address,
+			Amount:    tx.Amount,
+		})
+		if errors.Is(err, chain.ErrBroadcastRejected) {
+			if _, err := d.workflow.Fail(tx.TransactionID, SystemOperator, err.Error()); err != nil {
+				d.Errorf("Failed to mark rejected withdrawal %s as failed: %v", tx.TransactionID, err)
+			}
+			continue
+		}
+		if err != nil {
+			d.Errorf("Failed to broadcast withdrawal %s, will retry: %v", tx.TransactionID, err)
+			continue
+		}
+
+		// 适配器按业务引用去重，这里失败时下一轮重新广播会得到同一个交易哈希
+		if _, err := d.workflow.MarkBroadcasting(tx.TransactionID, SystemOperator, txHash); err != nil {
+			d.Errorf("Failed to record broadcast of withdrawal %s (tx %s): %v", tx.TransactionID, txHash, err)
+		}
+	}
+
+	return nil
+}
+
+// trackBroadcasting 跟踪已广播提现的链上状态
+func (d *Dispatcher) trackBroadcasting() error {
+	withdrawals, err := d.svcCtx.AssetTransactionModel.FindByTypeAndStatus(d.ctx,
+		model.AssetTransactionTypeWithdraw, model.AssetTransactionStatusBroadcasting, dispatchBatchSize, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range withdrawals {
+		adapter, err := d.svcCtx.ChainAdapters.Get(tx.Network)
+		if err != nil {
+			d.Errorf("Cannot track withdrawal %s: %v", tx.TransactionID, err)
+			continue
+		}
+
+		info, err := adapter.Transaction(d.ctx, tx.TxHash)
+		if errors.Is(err, chain.ErrTxNotFound) {
+			// 交易可能被节点丢弃，资金是否转出无法确定，需人工核实后处理
+			d.Errorf("Withdrawal %s tx %s not found on chain, manual check required", tx.TransactionID, tx.TxHash)
+			continue
+		}
+		if err != nil {
+			d.Errorf("Failed to get chain status of withdrawal %s: %v", tx.TransactionID, err)
+			continue
+		}
+
+		switch info.Status {
+		case chain.TxStatusFailed:
+			if _, err := d.workflow.Fail(tx.TransactionID, SystemOperator, "transaction failed on chain"); err != nil {
+				d.Errorf("Failed to mark withdrawal %s as failed: %v", tx.TransactionID, err)
+			}
+		case chain.TxStatusConfirmed:
+			required, err := d.requiredConfirmations(tx)
+			if err != nil {
+				d.Errorf("Failed to get confirmations required by withdrawal %s: %v", tx.TransactionID, err)
+				continue
+			}
+			if info.Confirmations < required {
+				continue
+			}
+			if _, err := d.workflow.Confirm(tx.TransactionID, SystemOperator); err != nil {
+				d.Errorf("Failed to confirm withdrawal %s: %v", tx.TransactionID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// requiredConfirmations 提现网络要求的确认数，至少为1
+func (d *Dispatcher) requiredConfirmations(tx *model.AssetTransaction) (int64, error) {
+	_, network, err := d.svcCtx.CurrencyRegistry.Resolve(d.ctx, tx.Currency, tx.Network)
+	if err != nil {
+		return 0, err
+	}
+	if network.Confirmations < 1 {
+		return 1, nil
+	}
+	return network.Confirmations, nil
+}
+
+// StartDispatcher 启动定时提现广播任务，interval<=0时不启动，返回停止函数
+func StartDispatcher(svcCtx *svc.ServiceContext, interval time.Duration) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	if interval <= 0 {
+		return cancel
+	}
+
+	dispatcher := NewDispatcher(ctx, svcCtx)
+	threading.GoSafe(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := dispatcher.RunOnce(); err != nil {
+					logx.Errorf("Failed to dispatch withdrawals: %v", err)
+				}
+			}
+		}
+	})
+
+	return cancel
+}
@@ -0,0 +1,219 @@
+package withdrawal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/chain"
+	"crypto-exchange/internal/config"
+	"crypto-exchange/internal/currency"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// memoryTransactionStore 内存中的提现记录，实现状态流转和广播任务用到的方法
+type memoryTransactionStore struct {
+	model.AssetTransactionModel
+	txs []*model.AssetTransaction
+}
+
+func (m *memoryTransactionStore) FindByTransactionID(ctx context.Context, transactionID string) (*model.AssetTransaction, error) {
+	for _, tx := range m.txs {
+		if tx.TransactionID == transactionID {
+			copied := *tx
+			return &copied, nil
+		}
+	}
+	return nil, model.ErrNotFound
+}
+
+func (m *memoryTransactionStore) FindByTypeAndStatus(ctx context.Context, transactionType, status int64, limit, offset int64) ([]*model.AssetTransaction, error) {
+	var resp []*model.AssetTransaction
+	for _, tx := range m.txs {
+		if tx.Type == transactionType && tx.Status == status {
+			copied := *tx
+			resp = append(resp, &copied)
+		}
+	}
+	return resp, nil
+}
+
+func (m *memoryTransactionStore) UpdateStatus(ctx context.Context, id uint64, fromStatus, toStatus int64, txHash, remark string) error {
+	for _, tx := range m.txs {
+		if tx.ID == id {
+			if tx.Status != fromStatus {
+				return model.ErrWithdrawalStatusChanged
+			}
+			tx.Status = toStatus
+			if txHash != "" {
+				tx.TxHash = txHash
+			}
+			if remark != "" {
+				tx.Remark = remark
+			}
+			return nil
+		}
+	}
+	return model.ErrWithdrawalStatusChanged
+}
+
+func (m *memoryTransactionStore) WithSession(session sqlx.Session) model.AssetTransactionModel {
+	return m
+}
+
+// memoryBalanceStore 内存中的余额，key为币种
+type memoryBalanceStore struct {
+	model.BalanceModel
+	balances map[string]*model.Balance
+}
+
+func (m *memoryBalanceStore) DeductFrozen(ctx context.Context, userID uint64, currency string, amount string) error {
+	balance := m.balances[currency]
+	frozen := decimal.RequireFromString(balance.Frozen)
+	value := decimal.RequireFromString(amount)
+	if frozen.LessThan(value) {
+		return model.ErrInsufficientFrozenBalance
+	}
+	balance.Frozen = frozen.Sub(value).String()
+	return nil
+}
+
+func (m *memoryBalanceStore) UnfreezeBalance(ctx context.Context, userID uint64, currency string, amount string) error {
+	balance := m.balances[currency]
+	value := decimal.RequireFromString(amount)
+	balance.Available = decimal.RequireFromString(balance.Available).Add(value).String()
+	balance.Frozen = decimal.RequireFromString(balance.Frozen).Sub(value).String()
+	return nil
+}
+
+func (m *memoryBalanceStore) Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	return fn(ctx, nil)
+}
+
+func (m *memoryBalanceStore) WithSession(session sqlx.Session) model.BalanceModel {
+	return m
+}
+
+type staticCurrencyModel struct {
+	model.CurrencyModel
+}
+
+func (m *staticCurrencyModel) FindAll(ctx context.Context) ([]*model.Currency, error) {
+	return []*model.Currency{{Code: "USDT", Precision: 6, Status: model.CurrencyStatusEnabled}}, nil
+}
+
+type staticNetworkModel struct {
+	model.CurrencyNetworkModel
+}
+
+func (m *staticNetworkModel) FindAll(ctx context.Context) ([]*model.CurrencyNetwork, error) {
+	return []*model.CurrencyNetwork{{Currency: "USDT", Network: "ERC20", AddressType: model.AddressTypeEVM, Confirmations: 3}}, nil
+}
+
+func newDispatcherEnv(withdrawals ...*model.AssetTransaction) (*Dispatcher, *memoryTransactionStore, *memoryBalanceStore, *chain.SimulatedChain) {
+	txStore := &memoryTransactionStore{txs: withdrawals}
+	balanceStore := &memoryBalanceStore{balances: map[string]*model.Balance{
+		"USDT": {UserID: 1, Currency: "USDT", Available: "0", Frozen: "202"},
+	}}
+	simulated := chain.NewSimulatedChain("ERC20", 0)
+	adapters := chain.NewRegistry(nil)
+	adapters.Register(simulated)
+
+	var c config.Config
+	c.Withdraw.ConfirmTTL = 900
+	svcCtx := &svc.ServiceContext{
+		Config:                  c,
+		AssetTransactionModel:   txStore,
+		BalanceModel:            balanceStore,
+		LedgerEntryModel:        &mockLedgerEntryModel{},
+		WithdrawalAuditLogModel: &mockAuditLogModel{},
+		CurrencyRegistry:        currency.NewRegistry(&staticCurrencyModel{}, &staticNetworkModel{}, time.Minute),
+		ChainAdapters:           adapters,
+	}
+	return NewDispatcher(context.Background(), svcCtx), txStore, balanceStore, simulated
+}
+
+func newApprovedWithdrawal(id uint64, transactionID string) *model.AssetTransaction {
+	tx := newWithdrawal(model.AssetTransactionStatusApproved)
+	tx.ID = id
+	tx.TransactionID = transactionID
+	tx.Address = "0x742d35Cc6634C0532925a3b844Bc454e4438f44e"
+	return tx
+}
+
+func TestDispatcher_BroadcastAndConfirm(t *testing.T) {
+	dispatcher, txStore, balances, simulated := newDispatcherEnv(newApprovedWithdrawal(1, "WTH_1"))
+
+	// 第一轮：广播
+	assert.NoError(t, dispatcher.RunOnce())
+	tx := txStore.txs[0]
+	assert.Equal(t, model.AssetTransactionStatusBroadcasting, tx.Status)
+	assert.NotEmpty(t, tx.TxHash)
+
+	// 确认数不足时保持广播中
+	simulated.Mine(2)
+	assert.NoError(t, dispatcher.RunOnce())
+	assert.Equal(t, model.AssetTransactionStatusBroadcasting, tx.Status)
+
+	simulated.Mine(1)
+	assert.NoError(t, dispatcher.RunOnce())
+	assert.Equal(t, model.AssetTransactionStatusSuccess, tx.Status)
+	assert.Equal(t, "101", balances.balances["USDT"].Frozen)
+	assert.Equal(t, "0", balances.balances["USDT"].Available)
+}
+
+func TestDispatcher_RejectedAndFailed(t *testing.T) {
+	dispatcher, txStore, balances, simulated := newDispatcherEnv(
+		newApprovedWithdrawal(1, "WTH_1"),
+		newApprovedWithdrawal(2, "WTH_2"),
+	)
+
+	// 第一笔被链拒绝，直接失败并退回
+	simulated.RejectNextBroadcasts(1)
+	assert.NoError(t, dispatcher.RunOnce())
+	assert.Equal(t, model.AssetTransactionStatusFailed, txStore.txs[0].Status)
+	assert.Equal(t, model.AssetTransactionStatusBroadcasting, txStore.txs[1].Status)
+	assert.Equal(t, "101", balances.balances["USDT"].Available)
+
+	// 第二笔打包后执行失败，同样退回
+	simulated.FailTransaction(txStore.txs[1].TxHash)
+	simulated.Mine(1)
+	assert.NoError(t, dispatcher.RunOnce())
+	assert.Equal(t, model.AssetTransactionStatusFailed, txStore.txs[1].Status)
+	assert.Equal(t, "202", balances.balances["USDT"].Available)
+	assert.Equal(t, "0", balances.balances["USDT"].Frozen)
+}
+
+func TestDispatcher_UnsupportedNetwork(t *testing.T) {
+	tx := newApprovedWithdrawal(1, "WTH_1")
+	tx.Network = "TRC20"
+	dispatcher, txStore, _, _ := newDispatcherEnv(tx)
+
+	// 没有适配器的网络保持已审核，等待配置后重试
+	assert.NoError(t, dispatcher.RunOnce())
+	assert.Equal(t, model.AssetTransactionStatusApproved, txStore.txs[0].Status)
+}
+
+func TestDispatcher_ExpireUnconfirmed(t *testing.T) {
+	expired := newWithdrawal(model.AssetTransactionStatusUnconfirmed)
+	expired.ID = 1
+	expired.TransactionID = "WTH_1"
+	expired.CreatedAt = time.Now().Add(-time.Hour)
+	fresh := newWithdrawal(model.AssetTransactionStatusUnconfirmed)
+	fresh.ID = 2
+	fresh.TransactionID = "WTH_2"
+	fresh.CreatedAt = time.Now()
+	dispatcher, txStore, balances, _ := newDispatcherEnv(expired, fresh)
+
+	// 确认码过期的提现取消并退回，未过期的保持等待确认
+	assert.NoError(t, dispatcher.RunOnce())
+	assert.Equal(t, model.AssetTransactionStatusCancelled, txStore.txs[0].Status)
+	assert.Equal(t, model.AssetTransactionStatusUnconfirmed, txStore.txs[1].Status)
+	assert.Equal(t, "101", balances.balances["USDT"].Available)
+	assert.Equal(t, "101", balances.balances["USDT"].Frozen)
+}
@@ -0,0 +1,181 @@
+package withdrawal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"crypto-exchange/internal/config"
+	"crypto-exchange/internal/logic/market"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// LimitChecker 提现限额和频率校验
+// 按用户认证等级对应的档位，限制24小时滚动窗口内的累计提现金额（按参考币种的最新成交价估值）
+// 以及1小时、24小时内的提现次数；认证等级低于最低要求或修改密码后的锁定期内禁止提现。
+// 被拒绝时返回 *model.WithdrawLimitError，携带机器可读的拒绝原因代码。
+type LimitChecker struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+	now    func() time.Time
+}
+
+// NewLimitChecker 创建提现限额校验器
+func NewLimitChecker(ctx context.Context, svcCtx *svc.ServiceContext) *LimitChecker {
+	return &LimitChecker{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+		now:    time.Now,
+	}
+}
+
+// Check 校验用户本次提现amount个currency是否超出限额或触发频率规则，在提现事务之前预检，尽早拒绝超限请求
+func (c *LimitChecker) Check(userID uint64, currency string, amount decimal.Decimal) error {
+	return c.check(c.ctx, c.svcCtx.AssetTransactionModel.FindWithdrawalsSince, userID, currency, amount)
+}
+
+// CheckLocked 在提现事务中重新校验，transactions为绑定事务session的模型
+// 先锁定用户行再统计窗口内的提现，同一用户的并发提现在锁上排队，须在插入提现记录之前调用
+func (c *LimitChecker) CheckLocked(ctx context.Context, transactions model.AssetTransactionModel, userID uint64, currency string, amount decimal.Decimal) error {
+	return c.check(ctx, transactions.FindWithdrawalsSinceForUpdate, userID, currency, amount)
+}
+
+// check 使用findWithdrawals查询窗口内的有效提现并校验限额和频率规则
+func (c *LimitChecker) check(ctx context.Context, findWithdrawals func(context.Context, uint64, time.Time) ([]*model.AssetTransaction, error), userID uint64, currency string, amount decimal.Decimal) error {
+	limits := c.svcCtx.Config.WithdrawLimit
+	if len(limits.Tiers) == 0 && limits.PasswordChangeLock <= 0 && limits.MinLevel <= 0 {
+		return nil
+	}
+
+	user, err := c.svcCtx.UserModel.FindOne(ctx, userID)
+	if err != nil {
+		return err
+	}
+	now := c.now()
+
+	// 认证等级低于最低要求时禁止提现
+	if user.VerificationLevel < limits.MinLevel {
+		return &model.WithdrawLimitError{
+			Reason: model.WithdrawLimitReasonVerification,
+			Detail: fmt.Sprintf("verification level %d or above is required to withdraw", limits.MinLevel),
+		}
+	}
+
+	// 修改密码后的锁定期内禁止提现，防止盗号后立即转出资产
+	if limits.PasswordChangeLock > 0 && user.PasswordChangedAt.Valid {
+		unlockAt := user.PasswordChangedAt.Time.Add(time.Duration(limits.PasswordChangeLock) * time.Second)
+		if now.Before(unlockAt) {
+			return &model.WithdrawLimitError{
+				Reason: model.WithdrawLimitReasonPasswordChanged,
+				Detail: fmt.Sprintf("withdrawals are locked until %s after password change", unlockAt.Format("2006-01-02 15:04:05")),
+			}
+		}
+	}
+
+	tier, ok := tierForLevel(limits.Tiers, user.VerificationLevel)
+	if !ok {
+		return nil
+	}
+
+	recent, err := findWithdrawals(ctx, userID, now.Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+
+	// 次数限制：本次提现计入窗口内
+	if tier.DailyCount > 0 && len(recent)+1 > tier.DailyCount {
+		return &model.WithdrawLimitError{
+			Reason: model.WithdrawLimitReasonDailyCount,
+			Detail: fmt.Sprintf("at most %d withdrawals per 24 hours", tier.DailyCount),
+		}
+	}
+	if tier.HourlyCount > 0 {
+		hourAgo := now.Add(-time.Hour)
+		count := 1
+		for _, tx := range recent {
+			if !tx.CreatedAt.Before(hourAgo) {
+				count++
+			}
+		}
+		if count > tier.HourlyCount {
+			return &model.WithdrawLimitError{
+				Reason: model.WithdrawLimitReasonHourlyCount,
+				Detail: fmt.Sprintf("at most %d withdrawals per hour", tier.HourlyCount),
+			}
+		}
+	}
+
+	// 金额限制：窗口内已提现金额和本次金额均按参考币种的最新价格估值
+	dailyAmount, err := decimal.NewFromString(tier.DailyAmount)
+	if err != nil {
+		c.Errorf("Invalid daily withdraw amount %q for level %d", tier.DailyAmount, tier.Level)
+		return model.ErrInternalServer
+	}
+	if dailyAmount.LessThanOrEqual(decimal.Zero) {
+		return nil
+	}
+
+	valuator := market.NewValuator(ctx, c.svcCtx, limits.ReferenceCurrency, limits.PeggedCurrencies)
+	used := decimal.Zero
+	for _, tx := range recent {
+		txAmount, err := decimal.NewFromString(tx.Amount)
+		if err != nil {
+			c.Errorf("Invalid amount format for withdraw %s: %s", tx.TransactionID, tx.Amount)
+			return model.ErrInternalServer
+		}
+		value, err := c.valuate(valuator, tx.Currency, txAmount)
+		if err != nil {
+			return err
+		}
+		used = used.Add(value)
+	}
+	value, err := c.valuate(valuator, currency, amount)
+	if err != nil {
+		return err
+	}
+
+	if used.Add(value).GreaterThan(dailyAmount) {
+		remaining := decimal.Max(dailyAmount.Sub(used), decimal.Zero)
+		return &model.WithdrawLimitError{
+			Reason: model.WithdrawLimitReasonDailyAmount,
+			Detail: fmt.Sprintf("24h limit is %s %s, remaining %s %s",
+				dailyAmount.String(), valuator.Reference(), remaining.String(), valuator.Reference()),
+		}
+	}
+	return nil
+}
+
+// valuate 将金额折算为参考币种，无法估值时拒绝提现，避免限额被绕过
+func (c *LimitChecker) valuate(valuator *market.Valuator, currency string, amount decimal.Decimal) (decimal.Decimal, error) {
+	value, err := valuator.Value(currency, amount)
+	if errors.Is(err, model.ErrPriceUnavailable) {
+		return decimal.Zero, &model.WithdrawLimitError{
+			Reason: model.WithdrawLimitReasonPriceUnavailable,
+			Detail: err.Error(),
+		}
+	}
+	return value, err
+}
+
+// tierForLevel 返回认证等级对应的限额档位，没有完全匹配的档位时使用不高于该等级的最高档位
+func tierForLevel(tiers []config.WithdrawTier, level int64) (config.WithdrawTier, bool) {
+	var best config.WithdrawTier
+	found := false
+	for _, tier := range tiers {
+		if tier.Level > level {
+			continue
+		}
+		if !found || tier.Level > best.Level {
+			best = tier
+			found = true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,187 @@
+package withdrawal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/config"
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticUserModel struct {
+	model.UserModel
+	user *model.User
+}
+
+func (m *staticUserModel) FindOne(ctx context.Context, id uint64) (*model.User, error) {
+	return m.user, nil
+}
+
+type recentWithdrawalStore struct {
+	model.AssetTransactionModel
+	withdrawals []*model.AssetTransaction
+	locked      bool
+}
+
+func (m *recentWithdrawalStore) FindWithdrawalsSince(ctx context.Context, userID uint64, since time.Time) ([]*model.AssetTransaction, error) {
+	var resp []*model.AssetTransaction
+	for _, tx := range m.withdrawals {
+		if !tx.CreatedAt.Before(since) {
+			resp = append(resp, tx)
+		}
+	}
+	return resp, nil
+}
+
+func (m *recentWithdrawalStore) FindWithdrawalsSinceForUpdate(ctx context.Context, userID uint64, since time.Time) ([]*model.AssetTransaction, error) {
+	m.locked = true
+	return m.FindWithdrawalsSince(ctx, userID, since)
+}
+
+type staticTickerModel struct {
+	model.TickerModel
+	prices map[string]string
+}
+
+func (m *staticTickerModel) FindBySymbol(ctx context.Context, symbol string) (*model.Ticker, error) {
+	price, ok := m.prices[symbol]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return &model.Ticker{Symbol: symbol, LastPrice: price}, nil
+}
+
+var limitTestNow = time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+func newLimitChecker(user *model.User, recent ...*model.AssetTransaction) *LimitChecker {
+	var c config.Config
+	c.WithdrawLimit.ReferenceCurrency = "USDT"
+	c.WithdrawLimit.PeggedCurrencies = []string{"USDC"}
+	c.WithdrawLimit.PasswordChangeLock = 86400
+	c.WithdrawLimit.Tiers = []config.WithdrawTier{
+		{Level: model.UserVerificationUnverified, DailyAmount: "1000", DailyCount: 5, HourlyCount: 2},
+		{Level: model.UserVerificationAdvanced, DailyAmount: "100000", DailyCount: 50, HourlyCount: 10},
+	}
+
+	checker := NewLimitChecker(context.Background(), &svc.ServiceContext{
+		Config:                c,
+		UserModel:             &staticUserModel{user: user},
+		AssetTransactionModel: &recentWithdrawalStore{withdrawals: recent},
+		TickerModel: &staticTickerModel{prices: map[string]string{
+			"BTC/USDT": "50000",
+			"USDT/EUR": "0.5",
+		}},
+	})
+	checker.now = func() time.Time { return limitTestNow }
+	return checker
+}
+
+func recentWithdrawal(currency, amount string, age time.Duration) *model.AssetTransaction {
+	return &model.AssetTransaction{
+		TransactionID: "WTH_" + currency + amount,
+		Currency:      currency,
+		Type:          model.AssetTransactionTypeWithdraw,
+		Amount:        amount,
+		CreatedAt:     limitTestNow.Add(-age),
+	}
+}
+
+func assertLimitReason(t *testing.T, err error, reason string) {
+	t.Helper()
+	assert.ErrorIs(t, err, model.ErrWithdrawLimitExceeded)
+	var limitErr *model.WithdrawLimitError
+	if assert.True(t, errors.As(err, &limitErr)) {
+		assert.Equal(t, reason, limitErr.Reason)
+	}
+}
+
+func TestLimitChecker_DailyAmount(t *testing.T) {
+	// 0.01 BTC = 500 USDT，加上窗口内的 400 USDT，未超过1000
+	checker := newLimitChecker(&model.User{ID: 1}, recentWithdrawal("USDT", "400", 3*time.Hour))
+	assert.NoError(t, checker.Check(1, "BTC", decimal.RequireFromString("0.01")))
+
+	// 0.013 BTC = 650 USDT，累计1050超过限额
+	err := checker.Check(1, "BTC", decimal.RequireFromString("0.013"))
+	assertLimitReason(t, err, model.WithdrawLimitReasonDailyAmount)
+
+	// 超过24小时的提现不计入窗口
+	checker = newLimitChecker(&model.User{ID: 1}, recentWithdrawal("USDT", "900", 25*time.Hour))
+	assert.NoError(t, checker.Check(1, "USDT", decimal.RequireFromString("900")))
+}
+
+func TestLimitChecker_TierByLevel(t *testing.T) {
+	// 基础认证没有单独的档位，使用未认证档位
+	checker := newLimitChecker(&model.User{ID: 1, VerificationLevel: model.UserVerificationBasic})
+	assertLimitReason(t, checker.Check(1, "USDT", decimal.RequireFromString("1001")), model.WithdrawLimitReasonDailyAmount)
+
+	checker = newLimitChecker(&model.User{ID: 1, VerificationLevel: model.UserVerificationAdvanced})
+	assert.NoError(t, checker.Check(1, "USDT", decimal.RequireFromString("1001")))
+}
+
+func TestLimitChecker_Valuation(t *testing.T) {
+	// 锚定币种按1:1估值，参考币种/币种 交易对按价格倒数估值：400 EUR = 800 USDT
+	checker := newLimitChecker(&model.User{ID: 1}, recentWithdrawal("USDC", "150", time.Hour*2))
+	assert.NoError(t, checker.Check(1, "EUR", decimal.RequireFromString("400")))
+	assertLimitReason(t, checker.Check(1, "EUR", decimal.RequireFromString("450")), model.WithdrawLimitReasonDailyAmount)
+
+	// 无法估值时拒绝
+	assertLimitReason(t, checker.Check(1, "DOGE", decimal.RequireFromString("1")), model.WithdrawLimitReasonPriceUnavailable)
+}
+
+func TestLimitChecker_Velocity(t *testing.T) {
+	checker := newLimitChecker(&model.User{ID: 1},
+		recentWithdrawal("USDT", "1", 10*time.Minute),
+		recentWithdrawal("USDT", "1", 30*time.Minute),
+	)
+	assertLimitReason(t, checker.Check(1, "USDT", decimal.RequireFromString("1")), model.WithdrawLimitReasonHourlyCount)
+
+	checker = newLimitChecker(&model.User{ID: 1},
+		recentWithdrawal("USDT", "1", 2*time.Hour),
+		recentWithdrawal("USDT", "1", 3*time.Hour),
+		recentWithdrawal("USDT", "1", 4*time.Hour),
+		recentWithdrawal("USDT", "1", 5*time.Hour),
+		recentWithdrawal("USDT", "1", 6*time.Hour),
+	)
+	assertLimitReason(t, checker.Check(1, "USDT", decimal.RequireFromString("1")), model.WithdrawLimitReasonDailyCount)
+}
+
+func TestLimitChecker_PasswordChangeLock(t *testing.T) {
+	changedAt := sql.NullTime{Time: limitTestNow.Add(-time.Hour), Valid: true}
+	checker := newLimitChecker(&model.User{ID: 1, VerificationLevel: model.UserVerificationAdvanced, PasswordChangedAt: changedAt})
+	assertLimitReason(t, checker.Check(1, "USDT", decimal.RequireFromString("1")), model.WithdrawLimitReasonPasswordChanged)
+
+	changedAt.Time = limitTestNow.Add(-25 * time.Hour)
+	checker = newLimitChecker(&model.User{ID: 1, VerificationLevel: model.UserVerificationAdvanced, PasswordChangedAt: changedAt})
+	assert.NoError(t, checker.Check(1, "USDT", decimal.RequireFromString("1")))
+}
+
+func TestLimitChecker_MinLevel(t *testing.T) {
+	checker := newLimitChecker(&model.User{ID: 1, VerificationLevel: model.UserVerificationUnverified})
+	checker.svcCtx.Config.WithdrawLimit.MinLevel = model.UserVerificationBasic
+	assertLimitReason(t, checker.Check(1, "USDT", decimal.RequireFromString("1")), model.WithdrawLimitReasonVerification)
+
+	checker = newLimitChecker(&model.User{ID: 1, VerificationLevel: model.UserVerificationAdvanced})
+	checker.svcCtx.Config.WithdrawLimit.MinLevel = model.UserVerificationBasic
+	assert.NoError(t, checker.Check(1, "USDT", decimal.RequireFromString("1")))
+}
+
+func TestLimitChecker_CheckLocked(t *testing.T) {
+	// 预检时窗口内只有400 USDT，并发提现的600 USDT在事务中锁定用户行后才可见
+	checker := newLimitChecker(&model.User{ID: 1}, recentWithdrawal("USDT", "400", time.Hour))
+	assert.NoError(t, checker.Check(1, "USDT", decimal.RequireFromString("500")))
+
+	transactions := &recentWithdrawalStore{withdrawals: []*model.AssetTransaction{
+		recentWithdrawal("USDT", "400", time.Hour),
+		recentWithdrawal("USDT", "600", 2*time.Hour),
+	}}
+	err := checker.CheckLocked(context.Background(), transactions, 1, "USDT", decimal.RequireFromString("500"))
+	assertLimitReason(t, err, model.WithdrawLimitReasonDailyAmount)
+	assert.True(t, transactions.locked)
+}
@@ -0,0 +1,220 @@
+// Package withdrawal 提现状态机：提现申请时金额从可用余额转入冻结余额，
+// 用户提交确认码后进入审核，之后按 待审核 -> 已审核 -> 广播中 -> 已确认/失败 流转，
+// 待用户确认时可取消，待审核时可被拒绝。
+// 每次状态流转的余额变动、记账和审核日志在同一个数据库事务中完成。
+package withdrawal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// SystemOperator 系统任务（如链上确认回调）的操作人ID
+const SystemOperator uint64 = 0
+
+// ConfirmPurpose 提现确认码的用途标识，业务标识为提现交易ID
+const ConfirmPurpose = "withdraw"
+
+// allowedTransitions 提现状态迁移规则：当前状态 -> 允许迁移到的目标状态
+// 已确认、失败、已拒绝和已取消为终态
+var allowedTransitions = map[int64][]int64{
+	model.AssetTransactionStatusUnconfirmed: {
+		model.AssetTransactionStatusPending,
+		model.AssetTransactionStatusCancelled,
+	},
+	model.AssetTransactionStatusPending: {
+		model.AssetTransactionStatusApproved,
+		model.AssetTransactionStatusRejected,
+	},
+	model.AssetTransactionStatusApproved: {
+		model.AssetTransactionStatusBroadcasting,
+		model.AssetTransactionStatusFailed,
+	},
+	model.AssetTransactionStatusBroadcasting: {
+		model.AssetTransactionStatusSuccess,
+		model.AssetTransactionStatusFailed,
+	},
+}
+
+// ValidateTransition 验证提现状态迁移是否合法
+func ValidateTransition(fromStatus, toStatus int64) error {
+	for _, allowed := range allowedTransitions[fromStatus] {
+		if allowed == toStatus {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s -> %s", model.ErrInvalidWithdrawalTransition,
+		model.WithdrawalStatusText(fromStatus), model.WithdrawalStatusText(toStatus))
+}
+
+// Workflow 提现状态流转
+type Workflow struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+// NewWorkflow 创建提现状态流转处理器
+func NewWorkflow(ctx context.Context, svcCtx *svc.ServiceContext) *Workflow {
+	return &Workflow{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// SubmitForReview 用户确认提现后进入待审核
+func (w *Workflow) SubmitForReview(transactionID string, operatorID uint64) (*model.AssetTransaction, error) {
+	return w.transition(transactionID, model.AssetTransactionStatusPending, operatorID, "", "confirmed by user")
+}
+
+// Cancel 取消等待用户确认的提现（确认码过期或校验失败次数过多），冻结的金额和手续费退回可用余额
+func (w *Workflow) Cancel(transactionID string, operatorID uint64, reason string) (*model.AssetTransaction, error) {
+	return w.transition(transactionID, model.AssetTransactionStatusCancelled, operatorID, "", reason)
+}
+
+// Approve 审核通过待审核的提现，冻结金额保持不变，等待广播
+func (w *Workflow) Approve(transactionID string, operatorID uint64, remark string) (*model.AssetTransaction, error) {
+	return w.transition(transactionID, model.AssetTransactionStatusApproved, operatorID, "", remark)
+}
+
+// Reject 拒绝待审核的提现，冻结的金额和手续费退回可用余额
+func (w *Workflow) Reject(transactionID string, operatorID uint64, reason string) (*model.AssetTransaction, error) {
+	return w.transition(transactionID, model.AssetTransactionStatusRejected, operatorID, "", reason)
+}
+
+// MarkBroadcasting 记录已审核的提现已广播到链上
+func (w *Workflow) MarkBroadcasting(transactionID string, operatorID uint64, txHash string) (*model.AssetTransaction, error) {
+	if txHash == "" {
+		return nil, errors.New("tx hash is required for broadcasting withdrawal")
+	}
+	return w.transition(transactionID, model.AssetTransactionStatusBroadcasting, operatorID, txHash, "")
+}
+
+// Confirm 确认提现已在链上完成，从冻结余额中扣除提现金额和手续费
+func (w *Workflow) Confirm(transactionID string, operatorID uint64) (*model.AssetTransaction, error) {
+	return w.transition(transactionID, model.AssetTransactionStatusSuccess, operatorID, "", "")
+}
+
+// Fail 标记提现失败（广播失败或链上执行失败），冻结的金额和手续费退回可用余额
+func (w *Workflow) Fail(transactionID string, operatorID uint64, reason string) (*model.AssetTransaction, error) {
+	return w.transition(transactionID, model.AssetTransactionStatusFailed, operatorID, "", reason)
+}
+
+// transition 在一个事务中完成状态更新、余额变动、记账和审核日志，所有写入都通过事务会话执行
+// 状态更新带有原状态条件，并发处理同一笔提现时只有一个请求会成功
+func (w *Workflow) transition(transactionID string, toStatus int64, operatorID uint64, txHash, remark string) (*model.AssetTransaction, error) {
+	tx, err := w.svcCtx.AssetTransactionModel.FindByTransactionID(w.ctx, transactionID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrWithdrawalNotFound
+		}
+		return nil, fmt.Errorf("failed to get withdrawal: %w", err)
+	}
+	if tx.Type != model.AssetTransactionTypeWithdraw {
+		return nil, model.ErrWithdrawalNotFound
+	}
+
+	fromStatus := tx.Status
+	if err := ValidateTransition(fromStatus, toStatus); err != nil {
+		return nil, err
+	}
+
+	err = w.svcCtx.BalanceModel.Trans(w.ctx, func(ctx context.Context, session sqlx.Session) error {
+		transactions := w.svcCtx.AssetTransactionModel.WithSession(session)
+		auditLogs := w.svcCtx.WithdrawalAuditLogModel.WithSession(session)
+
+		if err := transactions.UpdateStatus(ctx, tx.ID, fromStatus, toStatus, txHash, remark); err != nil {
+			return err
+		}
+
+		if err := w.applyBalanceEffect(ctx, session, tx, toStatus); err != nil {
+			return err
+		}
+
+		_, err := auditLogs.Insert(ctx, &model.WithdrawalAuditLog{
+			TransactionID: tx.TransactionID,
+			FromStatus:    fromStatus,
+			ToStatus:      toStatus,
+			OperatorID:    operatorID,
+			TxHash:        txHash,
+			Remark:        remark,
+			CreatedAt:     time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record withdrawal audit log: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		w.Errorf("Failed to move withdrawal %s from %s to %s: %v", transactionID,
+			model.WithdrawalStatusText(fromStatus), model.WithdrawalStatusText(toStatus), err)
+		return nil, err
+	}
+
+	tx.Status = toStatus
+	if txHash != "" {
+		tx.TxHash = txHash
+	}
+	if remark != "" {
+		tx.Remark = remark
+	}
+
+	w.Infof("Withdrawal %s moved from %s to %s by operator %d", transactionID,
+		model.WithdrawalStatusText(fromStatus), model.WithdrawalStatusText(toStatus), operatorID)
+	return tx, nil
+}
+
+// applyBalanceEffect 按目标状态处理冻结余额并记账
+// 拒绝、取消或失败：冻结金额退回可用余额；确认：冻结金额转出到外部科目，手续费转入手续费收入科目
+func (w *Workflow) applyBalanceEffect(ctx context.Context, session sqlx.Session, tx *model.AssetTransaction, toStatus int64) error {
+	balances := w.svcCtx.BalanceModel.WithSession(session)
+	ledger := w.svcCtx.LedgerEntryModel.WithSession(session)
+
+	amount, err := decimal.NewFromString(tx.Amount)
+	if err != nil {
+		return fmt.Errorf("invalid withdrawal amount: %s", tx.Amount)
+	}
+	fee, err := decimal.NewFromString(tx.Fee)
+	if err != nil {
+		return fmt.Errorf("invalid withdrawal fee: %s", tx.Fee)
+	}
+	total := amount.Add(fee)
+
+	journal := model.NewLedgerJournal(model.LedgerBizWithdraw, tx.TransactionID)
+
+	switch toStatus {
+	case model.AssetTransactionStatusRejected, model.AssetTransactionStatusCancelled, model.AssetTransactionStatusFailed:
+		if err := balances.UnfreezeBalance(ctx, tx.UserID, tx.Currency, total.String()); err != nil {
+			return fmt.Errorf("failed to refund withdrawal: %w", err)
+		}
+		journal.Transfer(tx.Currency, total.String(), model.UserFrozen(tx.UserID), model.UserAvailable(tx.UserID), "withdraw refund")
+
+	case model.AssetTransactionStatusSuccess:
+		// 按差额扣减冻结余额，冻结余额不足时整个事务回滚
+		if err := balances.DeductFrozen(ctx, tx.UserID, tx.Currency, total.String()); err != nil {
+			return fmt.Errorf("failed to settle withdrawal: %w", err)
+		}
+		journal.Transfer(tx.Currency, amount.String(), model.UserFrozen(tx.UserID), model.SystemExternal(), "withdraw")
+		journal.Transfer(tx.Currency, fee.String(), model.UserFrozen(tx.UserID), model.SystemFee(), "withdraw fee")
+
+	default:
+		// 用户确认、审核通过和广播只改变状态，金额仍冻结
+		return nil
+	}
+
+	if err := ledger.InsertJournal(ctx, journal); err != nil {
+		return fmt.Errorf("failed to record ledger: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+package withdrawal
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"crypto-exchange/internal/svc"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// 以下mock只实现提现状态流转用到的方法
+
+type mockAssetTransactionModel struct {
+	model.AssetTransactionModel
+	mock.Mock
+}
+
+func (m *mockAssetTransactionModel) FindByTransactionID(ctx context.Context, transactionID string) (*model.AssetTransaction, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AssetTransaction), args.Error(1)
+}
+
+func (m *mockAssetTransactionModel) UpdateStatus(ctx context.Context, id uint64, fromStatus, toStatus int64, txHash, remark string) error {
+	args := m.Called(ctx, id, fromStatus, toStatus, txHash, remark)
+	return args.Error(0)
+}
+
+func (m *mockAssetTransactionModel) WithSession(session sqlx.Session) model.AssetTransactionModel {
+	return m
+}
+
+type mockBalanceModel struct {
+	model.BalanceModel
+	mock.Mock
+}
+
+func (m *mockBalanceModel) DeductFrozen(ctx context.Context, userID uint64, currency string, amount string) error {
+	args := m.Called(ctx, userID, currency, amount)
+	return args.Error(0)
+}
+
+func (m *mockBalanceModel) UnfreezeBalance(ctx context.Context, userID uint64, currency string, amount string) error {
+	args := m.Called(ctx, userID, currency, amount)
+	return args.Error(0)
+}
+
+func (m *mockBalanceModel) Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	return fn(ctx, nil)
+}
+
+func (m *mockBalanceModel) WithSession(session sqlx.Session) model.BalanceModel {
+	return m
+}
+
+type mockLedgerEntryModel struct {
+	model.LedgerEntryModel
+	journals []*model.LedgerJournal
+}
+
+func (m *mockLedgerEntryModel) InsertJournal(ctx context.Context, journal *model.LedgerJournal) error {
+	if err := journal.Validate(); err != nil {
+		return err
+	}
+	m.journals = append(m.journals, journal)
+	return nil
+}
+
+func (m *mockLedgerEntryModel) WithSession(session sqlx.Session) model.LedgerEntryModel {
+	return m
+}
+
+type mockAuditLogModel struct {
+	model.WithdrawalAuditLogModel
+	logs []*model.WithdrawalAuditLog
+}
+
+func (m *mockAuditLogModel) Insert(ctx context.Context, data *model.WithdrawalAuditLog) (sql.Result, error) {
+	m.logs = append(m.logs, data)
+	return nil, nil
+}
+
+func (m *mockAuditLogModel) WithSession(session sqlx.Session) model.WithdrawalAuditLogModel {
+	return m
+}
+
+type testEnv struct {
+	txModel      *mockAssetTransactionModel
+	balanceModel *mockBalanceModel
+	ledgerModel  *mockLedgerEntryModel
+	auditModel   *mockAuditLogModel
+	workflow     *Workflow
+}
+
+func newTestEnv(tx *model.AssetTransaction) *testEnv {
+	env := &testEnv{
+		txModel:      &mockAssetTransactionModel{},
+		balanceModel: &mockBalanceModel{},
+		ledgerModel:  &mockLedgerEntryModel{},
+		auditModel:   &mockAuditLogModel{},
+	}
+	env.txModel.On("FindByTransactionID", mock.Anything, tx.TransactionID).Return(tx, nil)
+	env.workflow = NewWorkflow(context.Background(), &svc.ServiceContext{
+		AssetTransactionModel:   env.txModel,
+		BalanceModel:            env.balanceModel,
+		LedgerEntryModel:        env.ledgerModel,
+		WithdrawalAuditLogModel: env.auditModel,
+	})
+	return env
+}
+
+func newWithdrawal(status int64) *model.AssetTransaction {
+	return &model.AssetTransaction{
+		ID:            7,
+		UserID:        1,
+		TransactionID: "WTH_1",
+		Currency:      "USDT",
+		Network:       "ERC20",
+		Type:          model.AssetTransactionTypeWithdraw,
+		Amount:        "100",
+		Fee:           "1",
+		Status:        status,
+	}
+}
+
+func TestValidateTransition(t *testing.T) {
+	tests := []struct {
+		from, to int64
+		valid    bool
+	}{
+		{model.AssetTransactionStatusUnconfirmed, model.AssetTransactionStatusPending, true},
+		{model.AssetTransactionStatusUnconfirmed, model.AssetTransactionStatusCancelled, true},
+		{model.AssetTransactionStatusUnconfirmed, model.AssetTransactionStatusApproved, false},
+		{model.AssetTransactionStatusPending, model.AssetTransactionStatusCancelled, false},
+		{model.AssetTransactionStatusPending, model.AssetTransactionStatusApproved, true},
+		{model.AssetTransactionStatusPending, model.AssetTransactionStatusRejected, true},
+		{model.AssetTransactionStatusApproved, model.AssetTransactionStatusBroadcasting, true},
+		{model.AssetTransactionStatusApproved, model.AssetTransactionStatusFailed, true},
+		{model.AssetTransactionStatusBroadcasting, model.AssetTransactionStatusSuccess, true},
+		{model.AssetTransactionStatusBroadcasting, model.AssetTransactionStatusFailed, true},
+		{model.AssetTransactionStatusPending, model.AssetTransactionStatusBroadcasting, false},
+		{model.AssetTransactionStatusPending, model.AssetTransactionStatusSuccess, false},
+		{model.AssetTransactionStatusApproved, model.AssetTransactionStatusRejected, false},
+		{model.AssetTransactionStatusRejected, model.AssetTransactionStatusApproved, false},
+		{model.AssetTransactionStatusSuccess, model.AssetTransactionStatusFailed, false},
+		{model.AssetTransactionStatusFailed, model.AssetTransactionStatusBroadcasting, false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateTransition(tt.from, tt.to)
+		if tt.valid {
+			assert.NoError(t, err, "%s -> %s", model.WithdrawalStatusText(tt.from), model.WithdrawalStatusText(tt.to))
+		} else {
+			assert.ErrorIs(t, err, model.ErrInvalidWithdrawalTransition, "%s -> %s", model.WithdrawalStatusText(tt.from), model.WithdrawalStatusText(tt.to))
+		}
+	}
+}
+
+func TestWorkflow_Approve(t *testing.T) {
+	env := newTestEnv(newWithdrawal(model.AssetTransactionStatusPending))
+	env.txModel.On("UpdateStatus", mock.Anything, uint64(7), model.AssetTransactionStatusPending, model.AssetTransactionStatusApproved, "", "ok").Return(nil)
+
+	tx, err := env.workflow.Approve("WTH_1", 99, "ok")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AssetTransactionStatusApproved, tx.Status)
+	// 审核通过不改变余额
+	env.balanceModel.AssertNotCalled(t, "UnfreezeBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Empty(t, env.ledgerModel.journals)
+	assert.Len(t, env.auditModel.logs, 1)
+	assert.Equal(t, uint64(99), env.auditModel.logs[0].OperatorID)
+	assert.Equal(t, model.AssetTransactionStatusPending, env.auditModel.logs[0].FromStatus)
+}
+
+func TestWorkflow_Reject(t *testing.T) {
+	env := newTestEnv(newWithdrawal(model.AssetTransactionStatusPending))
+	env.txModel.On("UpdateStatus", mock.Anything, uint64(7), model.AssetTransactionStatusPending, model.AssetTransactionStatusRejected, "", "suspicious address").Return(nil)
+	env.balanceModel.On("UnfreezeBalance", mock.Anything, uint64(1), "USDT", "101").Return(nil)
+
+	tx, err := env.workflow.Reject("WTH_1", 99, "suspicious address")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AssetTransactionStatusRejected, tx.Status)
+	env.balanceModel.AssertExpectations(t)
+
+	// 冻结 -> 可用，金额含手续费
+	assert.Len(t, env.ledgerModel.journals, 1)
+	entries := env.ledgerModel.journals[0].Entries
+	assert.Len(t, entries, 2)
+	assert.Equal(t, model.LedgerAccountFrozen, entries[0].Account)
+	assert.Equal(t, model.LedgerAccountAvailable, entries[1].Account)
+	assert.Equal(t, "101", entries[1].Amount)
+	assert.Len(t, env.auditModel.logs, 1)
+}
+
+func TestWorkflow_SubmitForReview(t *testing.T) {
+	env := newTestEnv(newWithdrawal(model.AssetTransactionStatusUnconfirmed))
+	env.txModel.On("UpdateStatus", mock.Anything, uint64(7), model.AssetTransactionStatusUnconfirmed, model.AssetTransactionStatusPending, "", "confirmed by user").Return(nil)
+
+	tx, err := env.workflow.SubmitForReview("WTH_1", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AssetTransactionStatusPending, tx.Status)
+	// 用户确认不改变余额
+	assert.Empty(t, env.ledgerModel.journals)
+	assert.Equal(t, uint64(1), env.auditModel.logs[0].OperatorID)
+}
+
+func TestWorkflow_Cancel(t *testing.T) {
+	env := newTestEnv(newWithdrawal(model.AssetTransactionStatusUnconfirmed))
+	env.txModel.On("UpdateStatus", mock.Anything, uint64(7), model.AssetTransactionStatusUnconfirmed, model.AssetTransactionStatusCancelled, "", "confirmation expired").Return(nil)
+	env.balanceModel.On("UnfreezeBalance", mock.Anything, uint64(1), "USDT", "101").Return(nil)
+
+	tx, err := env.workflow.Cancel("WTH_1", SystemOperator, "confirmation expired")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AssetTransactionStatusCancelled, tx.Status)
+	env.balanceModel.AssertExpectations(t)
+	assert.Len(t, env.ledgerModel.journals, 1)
+	assert.Equal(t, model.LedgerAccountAvailable, env.ledgerModel.journals[0].Entries[1].Account)
+}
+
+func TestWorkflow_Confirm(t *testing.T) {
+	env := newTestEnv(newWithdrawal(model.AssetTransactionStatusBroadcasting))
+	env.txModel.On("UpdateStatus", mock.Anything, uint64(7), model.AssetTransactionStatusBroadcasting, model.AssetTransactionStatusSuccess, "", "").Return(nil)
+	env.balanceModel.On("DeductFrozen", mock.Anything, uint64(1), "USDT", "101").Return(nil)
+
+	tx, err := env.workflow.Confirm("WTH_1", SystemOperator)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AssetTransactionStatusSuccess, tx.Status)
+	env.balanceModel.AssertExpectations(t)
+
+	// 冻结 -> 外部（提现金额）和 冻结 -> 手续费收入
+	assert.Len(t, env.ledgerModel.journals, 1)
+	entries := env.ledgerModel.journals[0].Entries
+	assert.Len(t, entries, 4)
+	assert.Equal(t, model.LedgerAccountExternal, entries[1].Account)
+	assert.Equal(t, "100", entries[1].Amount)
+	assert.Equal(t, model.LedgerAccountFee, entries[3].Account)
+	assert.Equal(t, "1", entries[3].Amount)
+}
+
+func TestWorkflow_Confirm_InsufficientFrozen(t *testing.T) {
+	env := newTestEnv(newWithdrawal(model.AssetTransactionStatusBroadcasting))
+	env.txModel.On("UpdateStatus", mock.Anything, uint64(7), model.AssetTransactionStatusBroadcasting, model.AssetTransactionStatusSuccess, "", "").Return(nil)
+	env.balanceModel.On("DeductFrozen", mock.Anything, uint64(1), "USDT", "101").Return(model.ErrInsufficientFrozenBalance)
+
+	_, err := env.workflow.Confirm("WTH_1", SystemOperator)
+
+	assert.ErrorIs(t, err, model.ErrInsufficientFrozenBalance)
+	assert.Empty(t, env.ledgerModel.journals)
+	assert.Empty(t, env.auditModel.logs)
+}
+
+func TestWorkflow_Broadcast_Fail(t *testing.T) {
+	env := newTestEnv(newWithdrawal(model.AssetTransactionStatusApproved))
+
+	_, err := env.workflow.MarkBroadcasting("WTH_1", SystemOperator, "")
+	assert.Error(t, err)
+
+	env.txModel.On("UpdateStatus", mock.Anything, uint64(7), model.AssetTransactionStatusApproved, model.AssetTransactionStatusBroadcasting, "0xabc", "").Return(nil)
+	tx, err := env.workflow.MarkBroadcasting("WTH_1", SystemOperator, "0xabc")
+	assert.NoError(t, err)
+	assert.Equal(t, "0xabc", tx.TxHash)
+
+	// 广播后失败，冻结金额退回
+	env.txModel.On("UpdateStatus", mock.Anything, uint64(7), model.AssetTransactionStatusBroadcasting, model.AssetTransactionStatusFailed, "", "reverted").Return(nil)
+	env.balanceModel.On("UnfreezeBalance", mock.Anything, uint64(1), "USDT", "101").Return(nil)
+	tx, err = env.workflow.Fail("WTH_1", SystemOperator, "reverted")
+	assert.NoError(t, err)
+	assert.Equal(t, model.AssetTransactionStatusFailed, tx.Status)
+	assert.Len(t, env.auditModel.logs, 2)
+}
+
+func TestWorkflow_InvalidTransition(t *testing.T) {
+	env := newTestEnv(newWithdrawal(model.AssetTransactionStatusRejected))
+
+	_, err := env.workflow.Approve("WTH_1", 99, "")
+
+	assert.ErrorIs(t, err, model.ErrInvalidWithdrawalTransition)
+	env.txModel.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWorkflow_ConcurrentReview(t *testing.T) {
+	env := newTestEnv(newWithdrawal(model.AssetTransactionStatusPending))
+	// 另一个管理员已先处理了这笔提现
+	env.txModel.On("UpdateStatus", mock.Anything, uint64(7), model.AssetTransactionStatusPending, model.AssetTransactionStatusRejected, "", "dup").Return(model.ErrWithdrawalStatusChanged)
+
+	_, err := env.workflow.Reject("WTH_1", 99, "dup")
+
+	assert.ErrorIs(t, err, model.ErrWithdrawalStatusChanged)
+	env.balanceModel.AssertNotCalled(t, "UnfreezeBalance", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Empty(t, env.auditModel.logs)
+}
+
+func TestWorkflow_NotWithdrawal(t *testing.T) {
+	deposit := newWithdrawal(model.AssetTransactionStatusPending)
+	deposit.Type = model.AssetTransactionTypeDeposit
+	env := newTestEnv(deposit)
+
+	_, err := env.workflow.Approve("WTH_1", 99, "")
+	assert.ErrorIs(t, err, model.ErrWithdrawalNotFound)
+
+	env.txModel.On("FindByTransactionID", mock.Anything, "WTH_X").Return(nil, model.ErrNotFound)
+	_, err = env.workflow.Approve("WTH_X", 99, "")
+	assert.ErrorIs(t, err, model.ErrWithdrawalNotFound)
+}
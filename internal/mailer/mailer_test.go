@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildMessage(t *testing.T) {
+	now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	data, err := buildMessage("noreply@example.com", Message{
+		To:      "alice@example.com",
+		Subject: "验证邮箱",
+		Body:    "第一行\n第二行",
+	}, now)
+	assert.NoError(t, err)
+
+	text := string(data)
+	assert.Contains(t, text, "From: noreply@example.com\r\n")
+	assert.Contains(t, text, "To: alice@example.com\r\n")
+	assert.Contains(t, text, "Subject: =?UTF-8?q?")
+	assert.Contains(t, text, "Date: Mon, 01 Jan 2024 08:00:00 +0000\r\n")
+	assert.True(t, strings.HasSuffix(text, "\r\n\r\n第一行\r\n第二行"))
+
+	// 拒绝包含换行的头部，防止邮件头注入
+	_, err = buildMessage("noreply@example.com", Message{To: "a@example.com\r\nBcc: b@example.com"}, now)
+	assert.Equal(t, errInvalidHeader, err)
+}
+
+func TestMemoryMailer(t *testing.T) {
+	m := NewMemoryMailer()
+	assert.NoError(t, m.Send(context.Background(), Message{To: "a@example.com", Subject: "1"}))
+	assert.NoError(t, m.Send(context.Background(), Message{To: "b@example.com", Subject: "2"}))
+	assert.NoError(t, m.Send(context.Background(), Message{To: "a@example.com", Subject: "3"}))
+
+	assert.Len(t, m.Messages(), 3)
+	last, ok := m.Last("a@example.com")
+	assert.True(t, ok)
+	assert.Equal(t, "3", last.Subject)
+	_, ok = m.Last("c@example.com")
+	assert.False(t, ok)
+}
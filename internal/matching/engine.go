@@ -25,11 +25,14 @@ type Engine interface {
 	CancelOrder(order *model.Order) error
 	GetMarketDepth(symbol string, depth int) ([]PriceLevel, []PriceLevel)
 	GetOrderBookSnapshot(symbol string) map[string]interface{}
+	SetTradingStatus(symbol string, status int64)
+	CancelAllOrders(symbol string) []*model.Order
 }
 
 // MatchingEngine 撮合引擎实现
 type MatchingEngine struct {
 	orderBooks map[string]*OrderBook // 交易对符号 -> 订单簿
+	statuses   map[string]int64      // 交易对符号 -> 交易对状态，未设置时视为正常交易
 	mutex      sync.RWMutex          // 读写锁，保证并发安全
 	logger     logx.Logger
 }
@@ -38,6 +41,7 @@ type MatchingEngine struct {
 func NewMatchingEngine() *MatchingEngine {
 	return &MatchingEngine{
 		orderBooks: make(map[string]*OrderBook),
+		statuses:   make(map[string]int64),
 		logger:     logx.WithContext(context.Background()),
 	}
 }
@@ -67,7 +71,19 @@ func (me *MatchingEngine) ProcessOrder(order *model.Order) (*MatchResult, error)
 		return nil, errors.New("order cannot be nil")
 	}
 
+	// 检查交易对状态是否允许撮合该订单
+	status := me.GetTradingStatus(order.Symbol)
+	if err := model.CheckTradingPairOrderStatus(status, order.Type); err != nil {
+		return nil, err
+	}
+
 	orderBook := me.GetOrderBook(order.Symbol)
+
+	// 仅挂单状态下不撮合，会立即成交的订单直接拒绝，不进入订单簿
+	if status == model.TradingPairStatusPostOnly {
+		return me.processPostOnlyOrder(order, orderBook)
+	}
+
 	result := &MatchResult{
 		Trades:        make([]*model.Trade, 0),
 		UpdatedOrders: make([]*model.Order, 0),
@@ -273,8 +289,63 @@ func (me *MatchingEngine) updateCurrentOrder(order *model.Order, remainingAmount
 	}
 }
 
+// SetTradingStatus 设置交易对状态，由交易对管理器在状态变更时调用
+func (me *MatchingEngine) SetTradingStatus(symbol string, status int64) {
+	me.mutex.Lock()
+	me.statuses[symbol] = status
+	me.mutex.Unlock()
+
+	me.logger.Infof("Trading status of %s set to %s", symbol, model.TradingPairStatusText(status))
+}
+
+// GetTradingStatus 获取交易对状态，未设置时返回正常交易
+func (me *MatchingEngine) GetTradingStatus(symbol string) int64 {
+	me.mutex.RLock()
+	defer me.mutex.RUnlock()
+
+	if status, exists := me.statuses[symbol]; exists {
+		return status
+	}
+	return model.TradingPairStatusTrading
+}
+
+// processPostOnlyOrder 处理仅挂单状态下的限价单
+// 是否会立即成交的检查与加入订单簿在订单簿的同一把锁内完成，避免检查后对手盘变化导致挂单与对手盘交叉
+func (me *MatchingEngine) processPostOnlyOrder(order *model.Order, orderBook *OrderBook) (*MatchResult, error) {
+	if !orderBook.AddPostOnlyOrder(order) {
+		return nil, model.ErrOrderWouldMatch
+	}
+
+	order.Status = 1 // 未成交，等待撮合
+	me.logger.Infof("Post-only order %d added to order book", order.ID)
+	return &MatchResult{
+		Trades:        make([]*model.Trade, 0),
+		UpdatedOrders: []*model.Order{order},
+		FilledOrders:  make([]*model.Order, 0),
+	}, nil
+}
+
+// CancelAllOrders 撤销交易对订单簿中的所有挂单，返回被撤销的订单
+// 用于交易对下架等需要批量清空订单簿的场景，不受交易对状态限制
+func (me *MatchingEngine) CancelAllOrders(symbol string) []*model.Order {
+	orderBook := me.GetOrderBook(symbol)
+	orders := orderBook.Orders()
+	orderBook.Clear()
+
+	for _, order := range orders {
+		order.Status = 4 // 已取消
+	}
+
+	me.logger.Infof("Cancelled all %d orders of %s", len(orders), symbol)
+	return orders
+}
+
 // CancelOrder 取消订单
 func (me *MatchingEngine) CancelOrder(order *model.Order) error {
+	if !model.TradingPairAcceptsCancels(me.GetTradingStatus(order.Symbol)) {
+		return model.ErrCancelNotAllowed
+	}
+
 	orderBook := me.GetOrderBook(order.Symbol)
 	orderBook.RemoveOrder(order)
 	
@@ -1,6 +1,7 @@
 package matching
 
 import (
+	"sync"
 	"testing"
 	"time"
 
@@ -381,4 +382,131 @@ func TestMatchingEngine_GetOrderBookSnapshot(t *testing.T) {
 	assert.Equal(t, "1", snapshot["best_bid_amount"])
 	assert.Equal(t, "51000", snapshot["best_ask"])
 	assert.Equal(t, "0.5", snapshot["best_ask_amount"])
-} 
\ No newline at end of file
+}
+
+func TestMatchingEngine_TradingStatus(t *testing.T) {
+	engine := NewMatchingEngine()
+
+	newOrder := func(id uint64, orderType, side int64, price string) *model.Order {
+		return &model.Order{
+			ID:           id,
+			UserID:       id,
+			Symbol:       "BTC/USDT",
+			Type:         orderType,
+			Side:         side,
+			Amount:       "1.0",
+			Price:        price,
+			FilledAmount: "0",
+			Status:       1,
+			CreatedAt:    time.Now(),
+		}
+	}
+
+	// 未设置状态时默认正常交易
+	assert.Equal(t, model.TradingPairStatusTrading, engine.GetTradingStatus("BTC/USDT"))
+	sellOrder := newOrder(1, 1, 2, "50000.0")
+	_, err := engine.ProcessOrder(sellOrder)
+	assert.NoError(t, err)
+
+	// 预上线和仅可撤单状态拒绝新订单，但仅可撤单允许撤单
+	engine.SetTradingStatus("BTC/USDT", model.TradingPairStatusPreTrading)
+	_, err = engine.ProcessOrder(newOrder(2, 1, 1, "49000.0"))
+	assert.Equal(t, model.ErrTradingPairNotOpen, err)
+
+	engine.SetTradingStatus("BTC/USDT", model.TradingPairStatusCancelOnly)
+	_, err = engine.ProcessOrder(newOrder(3, 1, 1, "49000.0"))
+	assert.Equal(t, model.ErrTradingPairCancelOnly, err)
+
+	// 仅挂单状态：拒绝市价单和会立即成交的限价单，接受不成交的限价单
+	engine.SetTradingStatus("BTC/USDT", model.TradingPairStatusPostOnly)
+	_, err = engine.ProcessOrder(newOrder(4, 2, 1, ""))
+	assert.Equal(t, model.ErrTradingPairPostOnly, err)
+
+	_, err = engine.ProcessOrder(newOrder(5, 1, 1, "50000.0"))
+	assert.Equal(t, model.ErrOrderWouldMatch, err)
+
+	buyOrder := newOrder(6, 1, 1, "49000.0")
+	result, err := engine.ProcessOrder(buyOrder)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(result.Trades))
+
+	// 暂停交易时禁止下单，但允许撤单取回冻结资产
+	engine.SetTradingStatus("BTC/USDT", model.TradingPairStatusHalted)
+	_, err = engine.ProcessOrder(newOrder(7, 1, 1, "49000.0"))
+	assert.Equal(t, model.ErrTradingPairDisabled, err)
+
+	bids, asks := engine.GetMarketDepth("BTC/USDT", 10)
+	assert.Equal(t, 1, len(bids))
+	assert.Equal(t, 1, len(asks))
+
+	assert.NoError(t, engine.CancelOrder(sellOrder))
+	assert.Equal(t, int64(4), sellOrder.Status)
+	_, asks = engine.GetMarketDepth("BTC/USDT", 10)
+	assert.Equal(t, 0, len(asks))
+
+	// 已下架的交易对禁止撤单
+	engine.SetTradingStatus("BTC/USDT", model.TradingPairStatusDelisted)
+	assert.Equal(t, model.ErrCancelNotAllowed, engine.CancelOrder(buyOrder))
+}
+
+func TestMatchingEngine_PostOnlyConcurrent(t *testing.T) {
+	engine := NewMatchingEngine()
+	engine.SetTradingStatus("BTC/USDT", model.TradingPairStatusPostOnly)
+
+	// 同一价格的买单和卖单并发提交，只有一方能进入订单簿，订单簿不会出现交叉
+	var wg sync.WaitGroup
+	for i := 1; i <= 100; i++ {
+		wg.Add(1)
+		go func(id uint64) {
+			defer wg.Done()
+			_, _ = engine.ProcessOrder(&model.Order{
+				ID:           id,
+				UserID:       id,
+				Symbol:       "BTC/USDT",
+				Type:         1,
+				Side:         int64(id%2 + 1),
+				Amount:       "1",
+				Price:        "50000",
+				FilledAmount: "0",
+				CreatedAt:    time.Now(),
+			})
+		}(uint64(i))
+	}
+	wg.Wait()
+
+	bids, asks := engine.GetMarketDepth("BTC/USDT", 10)
+	assert.False(t, len(bids) > 0 && len(asks) > 0, "order book is crossed")
+}
+
+func TestMatchingEngine_CancelAllOrders(t *testing.T) {
+	engine := NewMatchingEngine()
+	orderBook := engine.GetOrderBook("BTC/USDT")
+
+	for i, side := range []int64{1, 1, 2} {
+		orderBook.AddOrder(&model.Order{
+			ID:           uint64(i + 1),
+			UserID:       1,
+			Symbol:       "BTC/USDT",
+			Type:         1,
+			Side:         side,
+			Amount:       "1.0",
+			Price:        []string{"49000.0", "48000.0", "51000.0"}[i],
+			FilledAmount: "0",
+			Status:       1,
+			CreatedAt:    time.Now(),
+		})
+	}
+
+	// 下架状态下也可以批量清空订单簿
+	engine.SetTradingStatus("BTC/USDT", model.TradingPairStatusDelisted)
+	orders := engine.CancelAllOrders("BTC/USDT")
+
+	assert.Equal(t, 3, len(orders))
+	for _, order := range orders {
+		assert.Equal(t, int64(4), order.Status)
+	}
+
+	bids, asks := engine.GetMarketDepth("BTC/USDT", 10)
+	assert.Equal(t, 0, len(bids))
+	assert.Equal(t, 0, len(asks))
+}
@@ -86,6 +86,32 @@ func (ob *OrderBook) AddOrder(order *model.Order) {
 	ob.mutex.Lock()
 	defer ob.mutex.Unlock()
 
+	ob.addOrder(order)
+}
+
+// AddPostOnlyOrder 在同一把锁内检查限价单是否会与对手盘立即成交，不会成交时加入订单簿并返回true
+// 检查和插入之间不会被其它订单穿插；会成交时返回false，订单簿不变
+func (ob *OrderBook) AddPostOnlyOrder(order *model.Order) bool {
+	ob.mutex.Lock()
+	defer ob.mutex.Unlock()
+
+	price, _ := decimal.NewFromString(order.Price)
+	if order.Side == 1 { // 买单，检查最优卖价
+		if len(ob.AskPrices) > 0 && ob.AskPrices[0].LessThanOrEqual(price) {
+			return false
+		}
+	} else { // 卖单，检查最优买价
+		if len(ob.BidPrices) > 0 && ob.BidPrices[0].GreaterThanOrEqual(price) {
+			return false
+		}
+	}
+
+	ob.addOrder(order)
+	return true
+}
+
+// addOrder 添加订单到订单簿，调用方需持有写锁
+func (ob *OrderBook) addOrder(order *model.Order) {
 	price, _ := decimal.NewFromString(order.Price)
 	priceStr := price.String()
 
@@ -244,6 +270,26 @@ func (ob *OrderBook) UpdateOrderAmount(order *model.Order, newAmount decimal.Dec
 	ob.LastUpdate = time.Now()
 }
 
+// Orders 返回订单簿中的所有挂单，买盘在前、卖盘在后，各自按价格优先、时间优先排序
+func (ob *OrderBook) Orders() []*model.Order {
+	ob.mutex.RLock()
+	defer ob.mutex.RUnlock()
+
+	orders := make([]*model.Order, 0)
+	for _, price := range ob.BidPrices {
+		for e := ob.Bids[price.String()].Orders.Front(); e != nil; e = e.Next() {
+			orders = append(orders, e.Value.(*model.Order))
+		}
+	}
+	for _, price := range ob.AskPrices {
+		for e := ob.Asks[price.String()].Orders.Front(); e != nil; e = e.Next() {
+			orders = append(orders, e.Value.(*model.Order))
+		}
+	}
+
+	return orders
+}
+
 // Clear 清空订单簿
 func (ob *OrderBook) Clear() {
 	ob.mutex.Lock()
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/rbac"
+	"crypto-exchange/internal/types"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// adminPathPrefix 管理接口路由前缀
+const adminPathPrefix = "/api/v1/admin"
+
+// AdminAuthMiddleware 管理接口鉴权，在JWT校验之后执行
+// 先按JWT中的roles声明快速拒绝没有权限的请求，再从数据库重新加载角色，保证撤销角色后立即生效
+type AdminAuthMiddleware struct {
+	roles model.UserRoleModel
+}
+
+// NewAdminAuthMiddleware 创建管理接口鉴权中间件
+func NewAdminAuthMiddleware(roles model.UserRoleModel) *AdminAuthMiddleware {
+	return &AdminAuthMiddleware{roles: roles}
+}
+
+func (m *AdminAuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		principal, err := authctx.FromContext(ctx)
+		if err != nil {
+			writeAuthError(r, w, http.StatusUnauthorized, model.ErrUnauthorized)
+			return
+		}
+		userID := principal.UserID
+
+		permission, registered := rbac.RequiredPermission(r.Method, strings.TrimPrefix(r.URL.Path, adminPathPrefix))
+		allowed := func(roles []string) bool {
+			// 未登记权限的管理接口只允许超级管理员访问
+			if !registered {
+				return contains(roles, model.RoleSuperAdmin)
+			}
+			return rbac.HasPermission(roles, permission)
+		}
+
+		if !allowed(principal.Roles) {
+			writeAuthError(r, w, http.StatusForbidden, model.ErrAdminPermissionDenied)
+			return
+		}
+
+		roles, err := m.roles.FindRolesByUserID(ctx, userID)
+		if err != nil {
+			logx.WithContext(ctx).Errorf("Failed to load roles of user %d: %v", userID, err)
+			writeAuthError(r, w, http.StatusInternalServerError, model.ErrInternalServer)
+			return
+		}
+		if !allowed(roles) {
+			writeAuthError(r, w, http.StatusForbidden, model.ErrAdminPermissionDenied)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func contains(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+func writeAuthError(r *http.Request, w http.ResponseWriter, status int, err error) {
+	httpx.WriteJsonCtx(r.Context(), w, status, &types.BaseResponse{Code: status, Message: err.Error()})
+}
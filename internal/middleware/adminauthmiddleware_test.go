@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryUserRoleModel struct {
+	model.UserRoleModel
+	roles map[uint64][]string
+}
+
+func (m *memoryUserRoleModel) FindRolesByUserID(ctx context.Context, userID uint64) ([]string, error) {
+	return m.roles[userID], nil
+}
+
+// adminRequest 模拟JWT中间件写入的claims
+func adminRequest(method, path string, userID string, claimRoles ...string) *http.Request {
+	r := httptest.NewRequest(method, path, nil)
+	roles := make([]interface{}, 0, len(claimRoles))
+	for _, role := range claimRoles {
+		roles = append(roles, role)
+	}
+	ctx := context.WithValue(r.Context(), "userId", json.Number(userID))
+	ctx = context.WithValue(ctx, "roles", roles)
+	return r.WithContext(ctx)
+}
+
+func TestAdminAuth(t *testing.T) {
+	m := NewAdminAuthMiddleware(&memoryUserRoleModel{roles: map[uint64][]string{
+		1: {model.RoleSuperAdmin},
+		2: {model.RoleFinance},
+		3: {model.RoleOperator},
+	}})
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		code int
+	}{
+		{"财务审核提现", adminRequest(http.MethodPost, "/api/v1/admin/withdrawals/W1/approve", "2", model.RoleFinance), http.StatusOK},
+		{"财务不能修改交易对", adminRequest(http.MethodPut, "/api/v1/admin/trading-pairs/BTC_USDT", "2", model.RoleFinance), http.StatusForbidden},
+		{"运营修改交易对", adminRequest(http.MethodPost, "/api/v1/admin/trading-pairs", "3", model.RoleOperator), http.StatusOK},
+		{"普通用户", adminRequest(http.MethodGet, "/api/v1/admin/trading-pairs", "4"), http.StatusForbidden},
+		{"角色已撤销但JWT未过期", adminRequest(http.MethodGet, "/api/v1/admin/trading-pairs", "4", model.RoleSuperAdmin), http.StatusForbidden},
+		{"超级管理员管理角色", adminRequest(http.MethodPost, "/api/v1/admin/users/2/roles", "1", model.RoleSuperAdmin), http.StatusOK},
+		{"运营不能管理角色", adminRequest(http.MethodPost, "/api/v1/admin/users/2/roles", "3", model.RoleOperator), http.StatusForbidden},
+		{"未登记权限的接口只允许超级管理员", adminRequest(http.MethodGet, "/api/v1/admin/unknown", "3", model.RoleOperator), http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.Handle(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})(w, tt.req)
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+
+	// 没有用户ID时返回401
+	w := httptest.NewRecorder()
+	m.Handle(func(w http.ResponseWriter, r *http.Request) {})(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/trading-pairs", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"crypto-exchange/internal/apikey"
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/clientinfo"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/rest/handler"
+)
+
+// API密钥签名请求头
+const (
+	HeaderApiKey    = "X-API-KEY"       // 密钥ID
+	HeaderTimestamp = "X-API-TIMESTAMP" // 毫秒时间戳
+	HeaderSignature = "X-API-SIGNATURE" // 请求签名，见 apikey 包说明
+)
+
+const (
+	maxSignedBodyBytes   = 1 << 20         // 参与签名的请求体最大长度
+	lastUsedUpdatePeriod = time.Minute     // 最近使用时间的最小更新间隔，避免每个请求都写库
+	nonceKeyPrefix       = "apikey:nonce:" // 已使用签名的Redis键前缀
+)
+
+// NonceStore 记录接收窗口内已使用的签名，防止请求被原样重放
+// *redis.Redis 实现了该接口
+type NonceStore interface {
+	SetnxExCtx(ctx context.Context, key, value string, seconds int) (bool, error)
+}
+
+// SecretCipher 解密保存在数据库中的API密钥
+// *totp.Authenticator 实现了该接口
+type SecretCipher interface {
+	Decrypt(encrypted string) (string, error)
+}
+
+// ApiKeyAuthMiddleware 用户接口认证：带 X-API-KEY 请求头时按API密钥校验HMAC签名，否则按JWT校验
+// 两种方式都可以通过 authctx.FromContext 获取当前用户，业务逻辑无需区分认证方式
+type ApiKeyAuthMiddleware struct {
+	apiKeys           model.ApiKeyModel
+	users             model.UserModel
+	restrictions      model.UserRestrictionModel
+	nonces            NonceStore
+	secrets           SecretCipher
+	recvWindow        time.Duration
+	trustForwardedFor bool
+	jwt               func(http.Handler) http.Handler
+	now               func() time.Time
+}
+
+// NewApiKeyAuthMiddleware 创建认证中间件
+// accessSecret为JWT签名密钥，recvWindow为请求时间戳与服务器时间允许的最大偏差
+func NewApiKeyAuthMiddleware(accessSecret string, recvWindow time.Duration, trustForwardedFor bool,
+	apiKeys model.ApiKeyModel, users model.UserModel, restrictions model.UserRestrictionModel, nonces NonceStore, secrets SecretCipher) *ApiKeyAuthMiddleware {
+	return &ApiKeyAuthMiddleware{
+		apiKeys:           apiKeys,
+		users:             users,
+		restrictions:      restrictions,
+		nonces:            nonces,
+		secrets:           secrets,
+		recvWindow:        recvWindow,
+		trustForwardedFor: trustForwardedFor,
+		jwt:               handler.Authorize(accessSecret),
+		now:               time.Now,
+	}
+}
+
+func (m *ApiKeyAuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keyID := r.Header.Get(HeaderApiKey)
+		if keyID == "" {
+			m.jwt(next).ServeHTTP(w, r)
+			return
+		}
+
+		ctx, err := m.authenticate(r, keyID)
+		if err != nil {
+			logx.WithContext(r.Context()).Infof("API key %s rejected for %s %s: %v", keyID, r.Method, r.URL.Path, err)
+			status := http.StatusUnauthorized
+			if errors.Is(err, model.ErrApiKeyPermissionDenied) || errors.Is(err, model.ErrApiKeyIPNotAllowed) {
+				status = http.StatusForbidden
+			}
+			writeAuthError(r, w, status, err)
+			return
+		}
+
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// authenticate 校验API密钥请求，返回写入用户信息后的上下文
+func (m *ApiKeyAuthMiddleware) authenticate(r *http.Request, keyID string) (context.Context, error) {
+	ctx := r.Context()
+
+	// 1. 校验时间戳是否在接收窗口内
+	timestamp := r.Header.Get(HeaderTimestamp)
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, model.ErrApiKeyTimestampInvalid
+	}
+	if skew := m.now().Sub(time.UnixMilli(ts)); skew > m.recvWindow || skew < -m.recvWindow {
+		return nil, model.ErrApiKeyTimestampInvalid
+	}
+
+	// 2. 查找密钥并检查状态和有效期
+	key, err := m.apiKeys.FindOneByKeyID(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrApiKeyInvalid
+		}
+		return nil, err
+	}
+	if key.Status != model.ApiKeyStatusActive {
+		return nil, model.ErrApiKeyInvalid
+	}
+	if key.ExpiresAt.Valid && !m.now().Before(key.ExpiresAt.Time) {
+		return nil, model.ErrApiKeyExpired
+	}
+
+	// 3. 校验签名，请求体读取后放回供后续处理
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSignedBodyBytes+1))
+	if err != nil {
+		return nil, model.ErrApiKeySignatureInvalid
+	}
+	if len(body) > maxSignedBodyBytes {
+		return nil, model.ErrApiKeySignatureInvalid
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	secret, err := m.secrets.Decrypt(key.SecretEncrypted)
+	if err != nil {
+		return nil, err
+	}
+	signature := r.Header.Get(HeaderSignature)
+	if !apikey.Verify(secret, timestamp, r.Method, r.URL.RequestURI(), body, signature) {
+		return nil, model.ErrApiKeySignatureInvalid
+	}
+
+	// 4. 校验来源IP和权限
+	if !ipAllowed(key.IPWhitelistEntries(), clientinfo.ClientIP(r, m.trustForwardedFor)) {
+		return nil, model.ErrApiKeyIPNotAllowed
+	}
+	permission, ok := requiredPermission(r)
+	if !ok || !key.HasPermission(permission) {
+		return nil, model.ErrApiKeyPermissionDenied
+	}
+
+	// 5. 同一签名在接收窗口内只能使用一次
+	ttl := int((2*m.recvWindow + time.Second - 1) / time.Second)
+	fresh, err := m.nonces.SetnxExCtx(ctx, nonceKeyPrefix+key.KeyID+":"+strings.ToLower(signature), timestamp, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		return nil, model.ErrApiKeyReplayed
+	}
+
+	// 6. 密钥所属用户必须处于正常状态，且未被禁止登录或冻结
+	user, err := m.users.FindOne(ctx, key.UserID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, model.ErrApiKeyInvalid
+		}
+		return nil, err
+	}
+	if user.Status != model.UserStatusActive {
+		return nil, model.ErrUserDisabled
+	}
+	// 与 restriction.Check 使用相同的匹配规则，restriction 包依赖 svc，这里直接查询以免循环引用
+	restrictions, err := m.restrictions.FindActiveByUserID(ctx, user.ID, m.now())
+	if err != nil {
+		return nil, err
+	}
+	if restricted := model.MatchRestriction(restrictions, model.RestrictionLogin); restricted != nil {
+		return nil, restricted
+	}
+
+	m.touch(ctx, key)
+
+	return authctx.WithPrincipal(ctx, &authctx.Principal{
+		UserID:   user.ID,
+		Email:    user.Email,
+		Method:   authctx.MethodApiKey,
+		ApiKeyID: key.KeyID,
+		Scopes:   key.PermissionList(),
+	}), nil
+}
+
+// touch 更新密钥的最近使用时间，失败不影响请求
+func (m *ApiKeyAuthMiddleware) touch(ctx context.Context, key *model.ApiKey) {
+	now := m.now()
+	if key.LastUsedAt.Valid && now.Sub(key.LastUsedAt.Time) < lastUsedUpdatePeriod {
+		return
+	}
+	if err := m.apiKeys.UpdateLastUsed(ctx, key.ID, now); err != nil {
+		logx.WithContext(ctx).Errorf("Failed to update last used time of api key %s: %v", key.KeyID, err)
+	}
+}
+
+// requiredPermission 返回请求所需的API密钥权限
+// 查询请求需要read权限，交易接口需要trade权限，资产接口的写操作（提现、转账、地址簿等）需要withdraw权限，
+// 其他写操作（如创建子账户）不允许通过API密钥调用
+func requiredPermission(r *http.Request) (string, bool) {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return model.ApiKeyPermissionRead, true
+	}
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/api/v1/trading/"):
+		return model.ApiKeyPermissionTrade, true
+	case strings.HasPrefix(r.URL.Path, "/api/v1/asset/"):
+		return model.ApiKeyPermissionWithdraw, true
+	default:
+		return "", false
+	}
+}
+
+// ipAllowed 判断IP是否在白名单中，白名单为空时不限制
+func ipAllowed(whitelist []string, ip string) bool {
+	if len(whitelist) == 0 {
+		return true
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, entry := range whitelist {
+		if strings.Contains(entry, "/") {
+			if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(addr) {
+				return true
+			}
+		} else if allowed := net.ParseIP(entry); allowed != nil && allowed.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,294 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/apikey"
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/totp"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryApiKeyModel struct {
+	model.ApiKeyModel
+	keys map[string]*model.ApiKey
+}
+
+func (m *memoryApiKeyModel) FindOneByKeyID(ctx context.Context, keyID string) (*model.ApiKey, error) {
+	key, ok := m.keys[keyID]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return key, nil
+}
+
+func (m *memoryApiKeyModel) UpdateLastUsed(ctx context.Context, id uint64, lastUsedAt time.Time) error {
+	for _, key := range m.keys {
+		if key.ID == id {
+			key.LastUsedAt = sql.NullTime{Time: lastUsedAt, Valid: true}
+		}
+	}
+	return nil
+}
+
+type memoryUserModel struct {
+	model.UserModel
+	users map[uint64]*model.User
+}
+
+func (m *memoryUserModel) FindOne(ctx context.Context, id uint64) (*model.User, error) {
+	user, ok := m.users[id]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return user, nil
+}
+
+type memoryRestrictionModel struct {
+	model.UserRestrictionModel
+	restrictions []*model.UserRestriction
+}
+
+func (m *memoryRestrictionModel) FindActiveByUserID(ctx context.Context, userID uint64, now time.Time) ([]*model.UserRestriction, error) {
+	var resp []*model.UserRestriction
+	for _, r := range m.restrictions {
+		if r.UserID == userID && (!r.ExpiresAt.Valid || r.ExpiresAt.Time.After(now)) {
+			resp = append(resp, r)
+		}
+	}
+	return resp, nil
+}
+
+type memoryNonceStore map[string]string
+
+func (s memoryNonceStore) SetnxExCtx(ctx context.Context, key, value string, seconds int) (bool, error) {
+	if _, ok := s[key]; ok {
+		return false, nil
+	}
+	s[key] = value
+	return true, nil
+}
+
+const testSecret = "test-secret"
+
+var testNow = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var testCipher = totp.NewAuthenticator("Exchange", "test-encryption-key", 1)
+
+func newTestMiddleware() (*ApiKeyAuthMiddleware, *memoryApiKeyModel) {
+	encrypted, _ := testCipher.Encrypt(testSecret)
+	keys := &memoryApiKeyModel{keys: map[string]*model.ApiKey{
+		"trader":   {ID: 1, UserID: 1, KeyID: "trader", SecretEncrypted: encrypted, Permissions: "read,trade", Status: model.ApiKeyStatusActive},
+		"readonly": {ID: 2, UserID: 1, KeyID: "readonly", SecretEncrypted: encrypted, Permissions: "read", IPWhitelist: "10.0.0.0/8", Status: model.ApiKeyStatusActive},
+		"revoked":  {ID: 3, UserID: 1, KeyID: "revoked", SecretEncrypted: encrypted, Permissions: "read", Status: model.ApiKeyStatusRevoked},
+		"expired": {ID: 4, UserID: 1, KeyID: "expired", SecretEncrypted: encrypted, Permissions: "read", Status: model.ApiKeyStatusActive,
+			ExpiresAt: sql.NullTime{Time: testNow.Add(-time.Second), Valid: true}},
+		"disabled": {ID: 5, UserID: 2, KeyID: "disabled", SecretEncrypted: encrypted, Permissions: "read", Status: model.ApiKeyStatusActive},
+		"banned":   {ID: 6, UserID: 3, KeyID: "banned", SecretEncrypted: encrypted, Permissions: "read", Status: model.ApiKeyStatusActive},
+	}}
+	users := &memoryUserModel{users: map[uint64]*model.User{
+		1: {ID: 1, Email: "bot@example.com", Status: model.UserStatusActive},
+		2: {ID: 2, Email: "frozen@example.com", Status: model.UserStatusDisabled},
+		3: {ID: 3, Email: "banned@example.com", Status: model.UserStatusActive},
+	}}
+	restrictions := &memoryRestrictionModel{restrictions: []*model.UserRestriction{{UserID: 3, Type: model.RestrictionLogin}}}
+	m := NewApiKeyAuthMiddleware("jwt-secret", 5*time.Second, false, keys, users, restrictions, memoryNonceStore{}, testCipher)
+	m.now = func() time.Time { return testNow }
+	return m, keys
+}
+
+func signedRequest(keyID, method, target, body string, at time.Time) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.RemoteAddr = "192.168.1.10:52000"
+	timestamp := strconv.FormatInt(at.UnixMilli(), 10)
+	r.Header.Set(HeaderApiKey, keyID)
+	r.Header.Set(HeaderTimestamp, timestamp)
+	r.Header.Set(HeaderSignature, apikey.Sign(testSecret, timestamp, method, r.URL.RequestURI(), []byte(body)))
+	return r
+}
+
+func serve(m *ApiKeyAuthMiddleware, r *http.Request) (*httptest.ResponseRecorder, *http.Request) {
+	var got *http.Request
+	w := httptest.NewRecorder()
+	m.Handle(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.WriteHeader(http.StatusOK)
+	})(w, r)
+	return w, got
+}
+
+func TestApiKeyAuth_SignedRequest(t *testing.T) {
+	m, keys := newTestMiddleware()
+	body := `{"symbol":"BTC/USDT","type":1,"side":1,"amount":"1","price":"100"}`
+
+	w, got := serve(m, signedRequest("trader", http.MethodPost, "/api/v1/trading/orders", body, testNow.Add(-time.Second)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.NotNil(t, got) {
+		principal, err := authctx.FromContext(got.Context())
+		if assert.NoError(t, err) {
+			assert.Equal(t, uint64(1), principal.UserID)
+			assert.Equal(t, authctx.MethodApiKey, principal.Method)
+			assert.Equal(t, "trader", principal.ApiKeyID)
+			assert.True(t, principal.HasScope(model.ApiKeyPermissionTrade))
+		}
+		// 请求体读取后需要放回供handler解析
+		restored, _ := io.ReadAll(got.Body)
+		assert.Equal(t, body, string(restored))
+	}
+	assert.True(t, keys.keys["trader"].LastUsedAt.Valid)
+
+	// 同一请求原样重放
+	w, _ = serve(m, signedRequest("trader", http.MethodPost, "/api/v1/trading/orders", body, testNow.Add(-time.Second)))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), model.ErrApiKeyReplayed.Error())
+}
+
+func TestApiKeyAuth_Rejections(t *testing.T) {
+	tests := []struct {
+		name    string
+		request func() *http.Request
+		code    int
+		err     error
+	}{
+		{
+			name: "时间戳超出接收窗口",
+			request: func() *http.Request {
+				return signedRequest("trader", http.MethodGet, "/api/v1/asset/balances", "", testNow.Add(-6*time.Second))
+			},
+			code: http.StatusUnauthorized,
+			err:  model.ErrApiKeyTimestampInvalid,
+		},
+		{
+			name: "签名不匹配",
+			request: func() *http.Request {
+				r := signedRequest("trader", http.MethodGet, "/api/v1/asset/balances", "", testNow)
+				r.URL.RawQuery = "currency=BTC"
+				return r
+			},
+			code: http.StatusUnauthorized,
+			err:  model.ErrApiKeySignatureInvalid,
+		},
+		{
+			name: "使用数据库中保存的密文签名",
+			request: func() *http.Request {
+				r := signedRequest("trader", http.MethodGet, "/api/v1/asset/balances", "", testNow)
+				encrypted, _ := testCipher.Encrypt(testSecret)
+				r.Header.Set(HeaderSignature, apikey.Sign(encrypted, r.Header.Get(HeaderTimestamp), r.Method, r.URL.RequestURI(), nil))
+				return r
+			},
+			code: http.StatusUnauthorized,
+			err:  model.ErrApiKeySignatureInvalid,
+		},
+		{
+			name: "密钥不存在",
+			request: func() *http.Request {
+				return signedRequest("unknown", http.MethodGet, "/api/v1/asset/balances", "", testNow)
+			},
+			code: http.StatusUnauthorized,
+			err:  model.ErrApiKeyInvalid,
+		},
+		{
+			name: "密钥已删除",
+			request: func() *http.Request {
+				return signedRequest("revoked", http.MethodGet, "/api/v1/asset/balances", "", testNow)
+			},
+			code: http.StatusUnauthorized,
+			err:  model.ErrApiKeyInvalid,
+		},
+		{
+			name: "密钥已过期",
+			request: func() *http.Request {
+				return signedRequest("expired", http.MethodGet, "/api/v1/asset/balances", "", testNow)
+			},
+			code: http.StatusUnauthorized,
+			err:  model.ErrApiKeyExpired,
+		},
+		{
+			name: "用户已禁用",
+			request: func() *http.Request {
+				return signedRequest("disabled", http.MethodGet, "/api/v1/asset/balances", "", testNow)
+			},
+			code: http.StatusUnauthorized,
+			err:  model.ErrUserDisabled,
+		},
+		{
+			name: "用户被禁止登录",
+			request: func() *http.Request {
+				return signedRequest("banned", http.MethodGet, "/api/v1/asset/balances", "", testNow)
+			},
+			code: http.StatusUnauthorized,
+			err:  model.ErrAccountRestricted,
+		},
+		{
+			name: "IP不在白名单",
+			request: func() *http.Request {
+				return signedRequest("readonly", http.MethodGet, "/api/v1/asset/balances", "", testNow)
+			},
+			code: http.StatusForbidden,
+			err:  model.ErrApiKeyIPNotAllowed,
+		},
+		{
+			name: "缺少交易权限",
+			request: func() *http.Request {
+				r := signedRequest("readonly", http.MethodDelete, "/api/v1/trading/orders", `{"order_id":1}`, testNow)
+				r.RemoteAddr = "10.1.2.3:52000"
+				return r
+			},
+			code: http.StatusForbidden,
+			err:  model.ErrApiKeyPermissionDenied,
+		},
+		{
+			name: "缺少提现权限",
+			request: func() *http.Request {
+				return signedRequest("trader", http.MethodPost, "/api/v1/asset/withdraw", `{}`, testNow)
+			},
+			code: http.StatusForbidden,
+			err:  model.ErrApiKeyPermissionDenied,
+		},
+		{
+			name: "账户管理类写操作不允许使用API密钥",
+			request: func() *http.Request {
+				return signedRequest("trader", http.MethodPost, "/api/v1/sub-accounts/", `{}`, testNow)
+			},
+			code: http.StatusForbidden,
+			err:  model.ErrApiKeyPermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestMiddleware()
+			w, got := serve(m, tt.request())
+			assert.Nil(t, got)
+			assert.Equal(t, tt.code, w.Code)
+			assert.Contains(t, w.Body.String(), tt.err.Error())
+		})
+	}
+}
+
+func TestApiKeyAuth_FallbackToJWT(t *testing.T) {
+	m, _ := newTestMiddleware()
+
+	// 没有API密钥请求头时按JWT校验
+	w, got := serve(m, httptest.NewRequest(http.MethodGet, "/api/v1/asset/balances", nil))
+	assert.Nil(t, got)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestIPAllowed(t *testing.T) {
+	assert.True(t, ipAllowed(nil, "1.2.3.4"))
+	assert.True(t, ipAllowed([]string{"1.2.3.4"}, "1.2.3.4"))
+	assert.True(t, ipAllowed([]string{"10.0.0.0/8", "2001:db8::/32"}, "2001:db8::1"))
+	assert.False(t, ipAllowed([]string{"10.0.0.0/8"}, "11.0.0.1"))
+	assert.False(t, ipAllowed([]string{"1.2.3.4"}, "not-an-ip"))
+}
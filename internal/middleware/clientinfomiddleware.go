@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+
+	"crypto-exchange/internal/clientinfo"
+)
+
+// ClientInfoMiddleware 解析请求来源IP、User-Agent和设备指纹并写入上下文，作为全局中间件注册
+type ClientInfoMiddleware struct {
+	trustForwardedFor bool
+}
+
+// NewClientInfoMiddleware 创建来源信息中间件，trustForwardedFor为true时按X-Forwarded-For取客户端IP
+func NewClientInfoMiddleware(trustForwardedFor bool) *ClientInfoMiddleware {
+	return &ClientInfoMiddleware{trustForwardedFor: trustForwardedFor}
+}
+
+func (m *ClientInfoMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		info := clientinfo.FromRequest(r, m.trustForwardedFor)
+		next(w, r.WithContext(clientinfo.WithInfo(r.Context(), info)))
+	}
+}
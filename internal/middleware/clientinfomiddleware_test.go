@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"crypto-exchange/internal/clientinfo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientInfo(t *testing.T) {
+	var got clientinfo.Info
+	next := func(w http.ResponseWriter, r *http.Request) {
+		got = clientinfo.FromContext(r.Context())
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+	r.RemoteAddr = "10.0.0.1:12345"
+	r.Header.Set("User-Agent", "test-agent")
+	r.Header.Set("X-Forwarded-For", "203.0.113.7")
+
+	NewClientInfoMiddleware(false).Handle(next)(httptest.NewRecorder(), r)
+	assert.Equal(t, "10.0.0.1", got.IP)
+	assert.Equal(t, "test-agent", got.UserAgent)
+	assert.NotEmpty(t, got.DeviceFingerprint)
+
+	NewClientInfoMiddleware(true).Handle(next)(httptest.NewRecorder(), r)
+	assert.Equal(t, "203.0.113.7", got.IP)
+}
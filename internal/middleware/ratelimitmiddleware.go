@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/clientinfo"
+	"crypto-exchange/internal/ratelimit"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 限流响应头
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"     // 窗口内的权重额度
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining" // 剩余额度
+	HeaderRateLimitReset     = "X-RateLimit-Reset"     // 距当前窗口结束的秒数
+	HeaderRateLimitWeight    = "X-RateLimit-Weight"    // 本次请求的权重
+	HeaderOrderLimitSecond   = "X-Order-Remaining-1s"  // 本秒内剩余下单次数
+	HeaderOrderLimitDay      = "X-Order-Remaining-1d"  // 24小时内剩余下单次数
+)
+
+const (
+	orderPath          = "/api/v1/trading/orders" // 下单接口，单独限制下单频率
+	maxWeightBodyBytes = 1 << 20                  // 读取请求体中权重参数的最大长度
+)
+
+// RateLimitQuota 限流额度，0表示不限制
+type RateLimitQuota struct {
+	User            int64 // 每个用户窗口内的权重额度
+	ApiKey          int64 // 每个API密钥窗口内的权重额度
+	IP              int64 // 未登录请求每个IP窗口内的权重额度
+	OrdersPerSecond int64 // 每个用户每秒最多下单数量
+	OrdersPerDay    int64 // 每个用户24小时内最多下单数量
+}
+
+// RateLimitMiddleware 按用户、API密钥和IP限流，在认证中间件之后执行
+// 每个请求按权重规则计数：API密钥请求同时扣减密钥和所属用户的额度，登录用户扣减用户额度，未登录请求扣减IP额度。
+// 下单接口另外按用户限制每秒和每天的下单数量。响应头中返回剩余额度最少的限流键的额度信息。
+// 限流存储出错时放行请求，只记录日志
+type RateLimitMiddleware struct {
+	limiter ratelimit.Limiter
+	window  time.Duration
+	quota   RateLimitQuota
+	rules   []ratelimit.Rule
+}
+
+// bucket 一个限流键及其额度
+type bucket struct {
+	key   string
+	limit int64
+}
+
+// NewRateLimitMiddleware 创建限流中间件，window为权重额度的滑动窗口长度，不足1秒时按1秒计算
+func NewRateLimitMiddleware(limiter ratelimit.Limiter, window time.Duration, quota RateLimitQuota, rules []ratelimit.Rule) *RateLimitMiddleware {
+	if window < time.Second {
+		window = time.Second
+	}
+	return &RateLimitMiddleware{
+		limiter: limiter,
+		window:  window,
+		quota:   quota,
+		rules:   rules,
+	}
+}
+
+func (m *RateLimitMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 未登录的请求按IP限流
+		principal, err := authctx.FromContext(r.Context())
+		if err != nil {
+			principal = nil
+		}
+
+		weight := m.weight(r)
+		w.Header().Set(HeaderRateLimitWeight, strconv.FormatInt(weight, 10))
+		if !m.take(w, r, m.buckets(r, principal), weight, m.window, model.ErrRateLimitExceeded, writeQuotaHeaders) {
+			return
+		}
+
+		if principal != nil && r.Method == http.MethodPost && r.URL.Path == orderPath {
+			if !m.take(w, r, []bucket{{key: "orders:1s:user:" + strconv.FormatUint(principal.UserID, 10), limit: m.quota.OrdersPerSecond}},
+				1, time.Second, model.ErrOrderRateLimitExceeded, orderHeader(HeaderOrderLimitSecond)) {
+				return
+			}
+			if !m.take(w, r, []bucket{{key: "orders:1d:user:" + strconv.FormatUint(principal.UserID, 10), limit: m.quota.OrdersPerDay}},
+				1, 24*time.Hour, model.ErrOrderRateLimitExceeded, orderHeader(HeaderOrderLimitDay)) {
+				return
+			}
+		}
+
+		next(w, r)
+	}
+}
+
+// take 依次扣减各限流键的额度，写入剩余额度最少的键的响应头；任一键超出额度时返回429并返回false
+func (m *RateLimitMiddleware) take(w http.ResponseWriter, r *http.Request, buckets []bucket, weight int64, window time.Duration,
+	denyErr error, writeHeaders func(http.ResponseWriter, ratelimit.Result)) bool {
+	var tightest *ratelimit.Result
+	for _, b := range buckets {
+		if b.limit <= 0 {
+			continue
+		}
+		result, err := m.limiter.Take(r.Context(), b.key, weight, b.limit, window)
+		if err != nil {
+			logx.WithContext(r.Context()).Errorf("Failed to take rate limit %s: %v", b.key, err)
+			continue
+		}
+		if !result.Allowed {
+			writeHeaders(w, result)
+			w.Header().Set("Retry-After", strconv.FormatInt(resetSeconds(result.Reset), 10))
+			logx.WithContext(r.Context()).Infof("Rate limit %s exceeded for %s %s", b.key, r.Method, r.URL.Path)
+			writeAuthError(r, w, http.StatusTooManyRequests, denyErr)
+			return false
+		}
+		if tightest == nil || result.Remaining < tightest.Remaining {
+			tightest = &result
+		}
+	}
+	if tightest != nil {
+		writeHeaders(w, *tightest)
+	}
+	return true
+}
+
+// buckets 返回请求需要扣减额度的限流键
+func (m *RateLimitMiddleware) buckets(r *http.Request, principal *authctx.Principal) []bucket {
+	if principal == nil {
+		ip := clientinfo.FromContext(r.Context()).IP
+		if ip == "" {
+			ip = clientinfo.ClientIP(r, false)
+		}
+		return []bucket{{key: "ip:" + ip, limit: m.quota.IP}}
+	}
+
+	user := bucket{key: "user:" + strconv.FormatUint(principal.UserID, 10), limit: m.quota.User}
+	if principal.Method == authctx.MethodApiKey {
+		return []bucket{{key: "apikey:" + principal.ApiKeyID, limit: m.quota.ApiKey}, user}
+	}
+	return []bucket{user}
+}
+
+// weight 按第一条匹配的规则计算请求权重，未匹配时为1
+func (m *RateLimitMiddleware) weight(r *http.Request) int64 {
+	for _, rule := range m.rules {
+		if !rule.Match(r.Method, r.URL.Path) {
+			continue
+		}
+		var value int64
+		if rule.Param != "" {
+			value = paramValue(r, rule.Param)
+		}
+		return rule.WeightOf(value)
+	}
+	return 1
+}
+
+// paramValue 读取整数请求参数，先查询字符串，再读取JSON请求体（读取后放回供后续处理），无法解析时返回0
+func paramValue(r *http.Request, name string) int64 {
+	if v := r.URL.Query().Get(name); v != "" {
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+	if r.Body == nil || r.ContentLength == 0 || !strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		return 0
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWeightBodyBytes))
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	if err != nil {
+		return 0
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return 0
+	}
+	raw := strings.Trim(string(fields[name]), `"`)
+	n, _ := strconv.ParseInt(raw, 10, 64)
+	return n
+}
+
+func writeQuotaHeaders(w http.ResponseWriter, result ratelimit.Result) {
+	w.Header().Set(HeaderRateLimitLimit, strconv.FormatInt(result.Limit, 10))
+	w.Header().Set(HeaderRateLimitRemaining, strconv.FormatInt(result.Remaining, 10))
+	w.Header().Set(HeaderRateLimitReset, strconv.FormatInt(resetSeconds(result.Reset), 10))
+}
+
+func orderHeader(name string) func(http.ResponseWriter, ratelimit.Result) {
+	return func(w http.ResponseWriter, result ratelimit.Result) {
+		w.Header().Set(name, strconv.FormatInt(result.Remaining, 10))
+	}
+}
+
+// resetSeconds 将剩余时间向上取整为秒
+func resetSeconds(d time.Duration) int64 {
+	return int64((d + time.Second - 1) / time.Second)
+}
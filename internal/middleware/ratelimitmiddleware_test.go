@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitWeightedByParam(t *testing.T) {
+	m := NewRateLimitMiddleware(ratelimit.NewMemoryLimiter(nil), time.Minute, RateLimitQuota{IP: 20},
+		[]ratelimit.Rule{{Path: "/api/v1/market/depth", Weight: 1, Param: "depth", Step: 100}})
+	var body string
+	next := func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/market/depth", strings.NewReader(`{"symbol":"BTC/USDT","depth":1000}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.RemoteAddr = "10.0.0.1:1234"
+	w := httptest.NewRecorder()
+	m.Handle(next)(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"symbol":"BTC/USDT","depth":1000}`, body, "request body must be restored")
+	assert.Equal(t, "10", w.Header().Get(HeaderRateLimitWeight))
+	assert.Equal(t, "20", w.Header().Get(HeaderRateLimitLimit))
+	assert.Equal(t, "10", w.Header().Get(HeaderRateLimitRemaining))
+
+	r = httptest.NewRequest(http.MethodGet, "/api/v1/market/depth?depth=1100", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	w = httptest.NewRecorder()
+	m.Handle(next)(w, r)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// 其他IP有独立额度
+	r = httptest.NewRequest(http.MethodGet, "/api/v1/market/depth?depth=1100", nil)
+	r.RemoteAddr = "10.0.0.2:1234"
+	w = httptest.NewRecorder()
+	m.Handle(next)(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimitApiKeySharesUserQuota(t *testing.T) {
+	m := NewRateLimitMiddleware(ratelimit.NewMemoryLimiter(nil), time.Minute, RateLimitQuota{User: 3, ApiKey: 10}, nil)
+	next := func(w http.ResponseWriter, r *http.Request) {}
+	do := func(p *authctx.Principal) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/asset/balances", nil)
+		r = r.WithContext(authctx.WithPrincipal(context.Background(), p))
+		w := httptest.NewRecorder()
+		m.Handle(next)(w, r)
+		return w
+	}
+
+	apiKey := &authctx.Principal{UserID: 1, Method: authctx.MethodApiKey, ApiKeyID: "ak"}
+	jwt := &authctx.Principal{UserID: 1, Method: authctx.MethodJWT}
+	w := do(apiKey)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get(HeaderRateLimitRemaining), "the tighter user quota is reported")
+	assert.Equal(t, http.StatusOK, do(jwt).Code)
+	assert.Equal(t, http.StatusOK, do(apiKey).Code)
+	assert.Equal(t, http.StatusTooManyRequests, do(jwt).Code)
+	assert.Equal(t, http.StatusTooManyRequests, do(apiKey).Code)
+	assert.Equal(t, http.StatusOK, do(&authctx.Principal{UserID: 2, Method: authctx.MethodJWT}).Code)
+}
+
+func TestRateLimitOrdersPerSecond(t *testing.T) {
+	m := NewRateLimitMiddleware(ratelimit.NewMemoryLimiter(nil), time.Minute, RateLimitQuota{User: 100, OrdersPerSecond: 2, OrdersPerDay: 100}, nil)
+	calls := 0
+	next := func(w http.ResponseWriter, r *http.Request) { calls++ }
+	do := func(method string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, orderPath, nil)
+		r = r.WithContext(authctx.WithPrincipal(context.Background(), &authctx.Principal{UserID: 1, Method: authctx.MethodJWT}))
+		w := httptest.NewRecorder()
+		m.Handle(next)(w, r)
+		return w
+	}
+
+	w := do(http.MethodPost)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get(HeaderOrderLimitSecond))
+	assert.Equal(t, "99", w.Header().Get(HeaderOrderLimitDay))
+	assert.Equal(t, http.StatusOK, do(http.MethodPost).Code)
+
+	w = do(http.MethodPost)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), "order rate limit")
+
+	// 查询订单不受下单频率限制
+	assert.Equal(t, http.StatusOK, do(http.MethodGet).Code)
+	assert.Equal(t, 3, calls)
+}
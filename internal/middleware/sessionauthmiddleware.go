@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/session"
+	"crypto-exchange/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// SessionAuthMiddleware 校验访问令牌所属的登录会话是否仍然有效，在JWT校验之后执行
+// 退出登录或刷新令牌被重复使用后会话被删除，尚未过期的访问令牌随之失效；API密钥认证的请求不受影响
+type SessionAuthMiddleware struct {
+	sessions session.Store
+}
+
+// NewSessionAuthMiddleware 创建会话校验中间件
+func NewSessionAuthMiddleware(sessions session.Store) *SessionAuthMiddleware {
+	return &SessionAuthMiddleware{sessions: sessions}
+}
+
+func (m *SessionAuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		principal, err := authctx.FromContext(ctx)
+		if err != nil {
+			writeAuthError(r, w, http.StatusUnauthorized, model.ErrUnauthorized)
+			return
+		}
+		if principal.Method == authctx.MethodApiKey {
+			next(w, r)
+			return
+		}
+
+		// 没有会话ID的令牌（如旧版本签发的令牌）一律视为已注销
+		sessionID := principal.SessionID
+		if sessionID == "" {
+			writeAuthError(r, w, http.StatusUnauthorized, model.ErrSessionRevoked)
+			return
+		}
+
+		ok, err := m.sessions.Exists(ctx, sessionID)
+		if err != nil {
+			logx.WithContext(ctx).Errorf("Failed to check session %s: %v", sessionID, err)
+			writeAuthError(r, w, http.StatusInternalServerError, model.ErrInternalServer)
+			return
+		}
+		if !ok {
+			writeAuthError(r, w, http.StatusUnauthorized, model.ErrSessionRevoked)
+			return
+		}
+
+		// JWT声明只解析一次，后续中间件和业务逻辑直接使用解析后的身份
+		next(w, r.WithContext(authctx.WithPrincipal(ctx, principal)))
+	}
+}
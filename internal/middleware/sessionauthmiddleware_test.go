@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"crypto-exchange/internal/authctx"
+	"crypto-exchange/internal/session"
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memorySessionStore struct {
+	session.Store
+	active map[string]bool
+}
+
+func (m *memorySessionStore) Exists(ctx context.Context, sessionID string) (bool, error) {
+	return m.active[sessionID], nil
+}
+
+func TestSessionAuth(t *testing.T) {
+	m := NewSessionAuthMiddleware(&memorySessionStore{active: map[string]bool{"s1": true}})
+
+	request := func(ctx context.Context) *http.Request {
+		return httptest.NewRequest(http.MethodGet, "/api/v1/user/profile", nil).WithContext(ctx)
+	}
+	claims := func(sessionID string) context.Context {
+		c := map[string]interface{}{authctx.ClaimUserID: json.Number("1")}
+		if sessionID != "" {
+			c[authctx.ClaimSessionID] = sessionID
+		}
+		return authctx.NewClaimsContext(c)
+	}
+
+	tests := []struct {
+		name string
+		req  *http.Request
+		code int
+	}{
+		{"会话有效", request(claims("s1")), http.StatusOK},
+		{"会话已注销", request(claims("s2")), http.StatusUnauthorized},
+		{"令牌中没有会话ID", request(claims("")), http.StatusUnauthorized},
+		{"未认证", request(context.Background()), http.StatusUnauthorized},
+		{"API密钥认证", request(authctx.NewApiKeyContext(1, "key", model.ApiKeyPermissionRead)), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			m.Handle(func(w http.ResponseWriter, r *http.Request) {
+				// 通过校验后上下文中携带解析好的身份
+				_, err := authctx.FromContext(r.Context())
+				assert.NoError(t, err)
+				w.WriteHeader(http.StatusOK)
+			})(w, tt.req)
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Package notify 用户安全提醒：业务代码只依赖Notifier接口，默认通过邮件发送，测试使用内存实现。
+// 后续接入短信、App推送等渠道时实现Notifier接口即可，调用方无需修改。
+package notify
+
+import (
+	"context"
+	"sync"
+
+	"crypto-exchange/internal/mailer"
+)
+
+// 提醒类型
+const (
+	AlertNewDeviceLogin = "new_device_login" // 新设备或新IP登录
+)
+
+// Alert 发给用户的安全提醒
+type Alert struct {
+	Type    string // 提醒类型
+	UserID  uint64 // 用户ID
+	Email   string // 用户邮箱
+	Subject string // 标题
+	Body    string // 正文
+}
+
+// Notifier 安全提醒发送接口
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// MailNotifier 通过邮件发送提醒
+type MailNotifier struct {
+	mailer mailer.Mailer
+}
+
+// NewMailNotifier 创建邮件提醒发送器
+func NewMailNotifier(m mailer.Mailer) *MailNotifier {
+	return &MailNotifier{mailer: m}
+}
+
+// Notify 发送提醒邮件到用户邮箱
+func (n *MailNotifier) Notify(ctx context.Context, alert Alert) error {
+	return n.mailer.Send(ctx, mailer.Message{
+		To:      alert.Email,
+		Subject: alert.Subject,
+		Body:    alert.Body,
+	})
+}
+
+// MemoryNotifier 将提醒保存在内存中，用于测试
+type MemoryNotifier struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+// NewMemoryNotifier 创建内存提醒发送器
+func NewMemoryNotifier() *MemoryNotifier {
+	return &MemoryNotifier{}
+}
+
+// Notify 保存提醒
+func (n *MemoryNotifier) Notify(ctx context.Context, alert Alert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+// Alerts 返回已发送的全部提醒
+func (n *MemoryNotifier) Alerts() []Alert {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Alert(nil), n.alerts...)
+}
@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"crypto-exchange/internal/mailer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMailNotifier(t *testing.T) {
+	m := mailer.NewMemoryMailer()
+	n := NewMailNotifier(m)
+
+	err := n.Notify(context.Background(), Alert{
+		Type:    AlertNewDeviceLogin,
+		UserID:  1,
+		Email:   "alice@example.com",
+		Subject: "新设备登录提醒",
+		Body:    "body",
+	})
+	assert.NoError(t, err)
+
+	msg, ok := m.Last("alice@example.com")
+	assert.True(t, ok)
+	assert.Equal(t, "新设备登录提醒", msg.Subject)
+	assert.Equal(t, "body", msg.Body)
+}
+
+func TestMemoryNotifier(t *testing.T) {
+	n := NewMemoryNotifier()
+	assert.NoError(t, n.Notify(context.Background(), Alert{Type: AlertNewDeviceLogin, UserID: 1}))
+	assert.NoError(t, n.Notify(context.Background(), Alert{Type: AlertNewDeviceLogin, UserID: 2}))
+
+	alerts := n.Alerts()
+	assert.Len(t, alerts, 2)
+	assert.Equal(t, uint64(2), alerts[1].UserID)
+}
@@ -0,0 +1,146 @@
+// Package onetime 一次性确认码：服务端生成、只保存哈希、限时有效、校验成功后立即失效，
+// 校验失败次数超过上限时确认码作废，用于提现确认等需要用户二次确认的操作；
+// 以及通过邮件链接发送的一次性令牌，用于邮箱验证和重置密码。
+package onetime
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+var (
+	ErrCodeInvalid       = errors.New("confirmation code is invalid")
+	ErrCodeExpired       = errors.New("confirmation code has expired or been used")
+	ErrTooManyAttempts   = errors.New("too many invalid confirmation attempts")
+	errInvalidCodeDigits = errors.New("confirmation code digits must be between 4 and 12")
+)
+
+// Store 一次性确认码存储
+// purpose区分用途（如withdraw），subject为用途内的业务标识（如提现交易ID）
+type Store interface {
+	// Issue 生成新的确认码，覆盖同一业务标识下未使用的旧确认码
+	Issue(ctx context.Context, purpose, subject string, ttl time.Duration) (string, error)
+	// Verify 校验确认码，成功后确认码立即失效
+	Verify(ctx context.Context, purpose, subject, code string) error
+	// Revoke 作废确认码
+	Revoke(ctx context.Context, purpose, subject string) error
+}
+
+// RedisStore 基于Redis的确认码存储，Redis中只保存确认码的SHA-256哈希
+type RedisStore struct {
+	rds         *redis.Redis
+	digits      int
+	maxAttempts int64
+}
+
+// NewRedisStore 创建基于Redis的确认码存储
+// digits为确认码位数，maxAttempts为单个确认码允许的最大校验失败次数
+func NewRedisStore(rds *redis.Redis, digits, maxAttempts int) *RedisStore {
+	return &RedisStore{
+		rds:         rds,
+		digits:      digits,
+		maxAttempts: int64(maxAttempts),
+	}
+}
+
+// Issue 生成数字确认码
+func (s *RedisStore) Issue(ctx context.Context, purpose, subject string, ttl time.Duration) (string, error) {
+	code, err := GenerateCode(s.digits)
+	if err != nil {
+		return "", err
+	}
+
+	seconds := int(ttl / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	if _, err := s.rds.DelCtx(ctx, attemptsKey(purpose, subject)); err != nil {
+		return "", err
+	}
+	if err := s.rds.SetexCtx(ctx, codeKey(purpose, subject), hashCode(code), seconds); err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// Verify 校验确认码
+func (s *RedisStore) Verify(ctx context.Context, purpose, subject, code string) error {
+	key := codeKey(purpose, subject)
+	stored, err := s.rds.GetCtx(ctx, key)
+	if err != nil {
+		return err
+	}
+	if stored == "" {
+		return ErrCodeExpired
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(hashCode(code))) != 1 {
+		attempts, err := s.rds.IncrCtx(ctx, attemptsKey(purpose, subject))
+		if err != nil {
+			return err
+		}
+		if attempts == 1 {
+			// 失败计数与确认码同时过期
+			if ttl, err := s.rds.TtlCtx(ctx, key); err == nil && ttl > 0 {
+				_ = s.rds.ExpireCtx(ctx, attemptsKey(purpose, subject), ttl)
+			}
+		}
+		if attempts >= s.maxAttempts {
+			_ = s.Revoke(ctx, purpose, subject)
+			return ErrTooManyAttempts
+		}
+		return ErrCodeInvalid
+	}
+
+	// 删除成功的请求才算使用了确认码，并发提交同一确认码时只有一个请求通过
+	deleted, err := s.rds.DelCtx(ctx, key)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrCodeExpired
+	}
+	_, _ = s.rds.DelCtx(ctx, attemptsKey(purpose, subject))
+	return nil
+}
+
+// Revoke 作废确认码
+func (s *RedisStore) Revoke(ctx context.Context, purpose, subject string) error {
+	_, err := s.rds.DelCtx(ctx, codeKey(purpose, subject), attemptsKey(purpose, subject))
+	return err
+}
+
+// GenerateCode 使用加密安全的随机数生成指定位数的数字确认码
+func GenerateCode(digits int) (string, error) {
+	if digits < 4 || digits > 12 {
+		return "", errInvalidCodeDigits
+	}
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", digits, n), nil
+}
+
+func codeKey(purpose, subject string) string {
+	return fmt.Sprintf("onetime:%s:%s", purpose, subject)
+}
+
+func attemptsKey(purpose, subject string) string {
+	return fmt.Sprintf("onetime:%s:%s:attempts", purpose, subject)
+}
+
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,49 @@
+package onetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateCode(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		code, err := GenerateCode(6)
+		assert.NoError(t, err)
+		assert.Len(t, code, 6)
+		assert.Regexp(t, `^[0-9]{6}$`, code)
+		seen[code] = true
+	}
+	assert.Greater(t, len(seen), 1)
+
+	_, err := GenerateCode(3)
+	assert.Error(t, err)
+	_, err = GenerateCode(13)
+	assert.Error(t, err)
+}
+
+func TestHashCode(t *testing.T) {
+	// Redis中只保存哈希，相同确认码哈希一致，不同确认码哈希不同
+	assert.Equal(t, hashCode("123456"), hashCode("123456"))
+	assert.NotEqual(t, hashCode("123456"), hashCode("123457"))
+	assert.NotContains(t, hashCode("123456"), "123456")
+	assert.Equal(t, "onetime:withdraw:WTH_1", codeKey("withdraw", "WTH_1"))
+}
+
+func TestGenerateToken(t *testing.T) {
+	token, err := GenerateToken()
+	assert.NoError(t, err)
+	assert.Regexp(t, `^[0-9a-f]{64}$`, token)
+
+	other, err := GenerateToken()
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, other)
+
+	// 按令牌哈希索引，Redis中不出现令牌明文
+	assert.NotContains(t, tokenKey("verify_email", hashCode(token)), token)
+	assert.Equal(t, "onetime:token:reset_password:user:7", tokenUserKey("reset_password", 7))
+	assert.Equal(t, 2, ceilSeconds(1500*time.Millisecond))
+	assert.Equal(t, 1, ceilSeconds(0))
+}
@@ -0,0 +1,124 @@
+package onetime
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+var (
+	ErrTokenInvalid       = errors.New("token is invalid, expired or has been used")
+	ErrTokenIssueTooOften = errors.New("token was issued recently, please try again later")
+)
+
+// TokenStore 一次性令牌存储，用于通过邮件链接发送的邮箱验证、重置密码等令牌
+// 与确认码不同，令牌本身携带足够的熵，凭令牌即可找到所属用户，无需提供业务标识
+type TokenStore interface {
+	// Issue 为用户生成新令牌，同一用途下用户未使用的旧令牌立即失效
+	// interval大于0时，距离上次生成不足interval返回ErrTokenIssueTooOften，用于限制邮件发送频率
+	Issue(ctx context.Context, purpose string, userID uint64, ttl, interval time.Duration) (string, error)
+	// Consume 校验并消耗令牌，返回令牌所属用户ID，令牌只能使用一次
+	Consume(ctx context.Context, purpose, token string) (uint64, error)
+}
+
+// RedisTokenStore 基于Redis的一次性令牌存储，Redis中只保存令牌的SHA-256哈希
+type RedisTokenStore struct {
+	rds *redis.Redis
+}
+
+// NewRedisTokenStore 创建基于Redis的一次性令牌存储
+func NewRedisTokenStore(rds *redis.Redis) *RedisTokenStore {
+	return &RedisTokenStore{rds: rds}
+}
+
+// Issue 生成256位随机令牌
+func (s *RedisTokenStore) Issue(ctx context.Context, purpose string, userID uint64, ttl, interval time.Duration) (string, error) {
+	if interval > 0 {
+		ok, err := s.rds.SetnxExCtx(ctx, tokenThrottleKey(purpose, userID), "1", ceilSeconds(interval))
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "", ErrTokenIssueTooOften
+		}
+	}
+
+	token, err := GenerateToken()
+	if err != nil {
+		return "", err
+	}
+	hash := hashCode(token)
+	seconds := ceilSeconds(ttl)
+
+	// 作废旧令牌，用户只保留最近一次生成的令牌
+	if old, err := s.rds.GetCtx(ctx, tokenUserKey(purpose, userID)); err != nil {
+		return "", err
+	} else if old != "" {
+		if _, err := s.rds.DelCtx(ctx, tokenKey(purpose, old)); err != nil {
+			return "", err
+		}
+	}
+	if err := s.rds.SetexCtx(ctx, tokenKey(purpose, hash), strconv.FormatUint(userID, 10), seconds); err != nil {
+		return "", err
+	}
+	if err := s.rds.SetexCtx(ctx, tokenUserKey(purpose, userID), hash, seconds); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Consume 使用GETDEL读取并删除令牌，并发提交同一令牌时只有一个请求成功
+func (s *RedisTokenStore) Consume(ctx context.Context, purpose, token string) (uint64, error) {
+	if token == "" {
+		return 0, ErrTokenInvalid
+	}
+	hash := hashCode(token)
+	value, err := s.rds.GetDelCtx(ctx, tokenKey(purpose, hash))
+	if err != nil {
+		return 0, err
+	}
+	if value == "" {
+		return 0, ErrTokenInvalid
+	}
+	userID, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, ErrTokenInvalid
+	}
+	_, _ = s.rds.DelCtx(ctx, tokenUserKey(purpose, userID))
+	return userID, nil
+}
+
+// GenerateToken 生成32字节的随机令牌，十六进制编码
+func GenerateToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func ceilSeconds(d time.Duration) int {
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
+
+func tokenKey(purpose, hash string) string {
+	return fmt.Sprintf("onetime:token:%s:%s", purpose, hash)
+}
+
+func tokenUserKey(purpose string, userID uint64) string {
+	return fmt.Sprintf("onetime:token:%s:user:%d", purpose, userID)
+}
+
+func tokenThrottleKey(purpose string, userID uint64) string {
+	return fmt.Sprintf("onetime:token:%s:user:%d:throttle", purpose, userID)
+}
@@ -0,0 +1,211 @@
+// Package por 实现储备金证明（Proof of Reserves）使用的Merkle求和树
+// 叶子节点包含加盐哈希后的用户ID和用户余额，每个内部节点同时承诺子节点哈希和余额之和，
+// 根节点的和即为该币种的用户负债总额。本包不依赖数据库和服务上下文，可单独用于离线验证。
+package por
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// 证明路径中兄弟节点的位置
+const (
+	PositionLeft  = "left"  // 兄弟节点在左侧
+	PositionRight = "right" // 兄弟节点在右侧
+)
+
+var (
+	ErrEmptyTree       = errors.New("merkle sum tree has no leaves")
+	ErrNegativeBalance = errors.New("leaf balance cannot be negative")
+	ErrLeafNotFound    = errors.New("leaf not found in merkle sum tree")
+	ErrProofMismatch   = errors.New("proof does not match the published root")
+)
+
+// Node Merkle求和树节点
+type Node struct {
+	Hash string          // 节点哈希，十六进制
+	Sum  decimal.Decimal // 子树余额之和
+}
+
+// Leaf 叶子节点输入
+type Leaf struct {
+	UserHash string // 加盐哈希后的用户ID
+	Balance  string // 用户余额
+}
+
+// ProofNode 证明路径上的兄弟节点
+type ProofNode struct {
+	Hash     string `json:"hash"`     // 兄弟节点哈希
+	Sum      string `json:"sum"`      // 兄弟节点余额之和
+	Position string `json:"position"` // 兄弟节点位置：left/right
+}
+
+// Proof 单个用户的包含证明，可序列化为JSON交给用户离线验证
+type Proof struct {
+	Currency         string      `json:"currency"`          // 币种代码
+	RootHash         string      `json:"root_hash"`         // 公布的根哈希
+	TotalLiabilities string      `json:"total_liabilities"` // 公布的用户负债总额
+	LeafIndex        int64       `json:"leaf_index"`        // 叶子序号
+	UserHash         string      `json:"user_hash"`         // 加盐哈希后的用户ID
+	Salt             string      `json:"salt"`              // 用户专属盐值
+	Balance          string      `json:"balance"`           // 用户余额
+	Path             []ProofNode `json:"path"`              // 从叶子到根的兄弟节点
+}
+
+// Tree Merkle求和树，levels[0]为叶子层，最后一层为根
+type Tree struct {
+	levels [][]Node
+}
+
+// NewSalt 生成随机盐值
+func NewSalt() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// HashUserID 计算加盐后的用户ID哈希：sha256("<userID>:<salt>")
+func HashUserID(userID uint64, salt string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", userID, salt)))
+	return hex.EncodeToString(sum[:])
+}
+
+// LeafNode 计算叶子节点：hash = sha256("leaf:<userHash>:<balance>")
+func LeafNode(userHash string, balance string) (Node, error) {
+	amount, err := decimal.NewFromString(balance)
+	if err != nil {
+		return Node{}, fmt.Errorf("invalid leaf balance: %s", balance)
+	}
+	if amount.IsNegative() {
+		return Node{}, ErrNegativeBalance
+	}
+
+	sum := sha256.Sum256([]byte("leaf:" + userHash + ":" + amount.String()))
+	return Node{Hash: hex.EncodeToString(sum[:]), Sum: amount}, nil
+}
+
+// ParentNode 计算父节点：hash = sha256("node:<leftHash>:<leftSum>:<rightHash>:<rightSum>")，sum = leftSum + rightSum
+func ParentNode(left, right Node) Node {
+	data := "node:" + left.Hash + ":" + left.Sum.String() + ":" + right.Hash + ":" + right.Sum.String()
+	sum := sha256.Sum256([]byte(data))
+	return Node{Hash: hex.EncodeToString(sum[:]), Sum: left.Sum.Add(right.Sum)}
+}
+
+// emptyNode 奇数节点的补位节点，余额为0，避免复制节点导致总额翻倍
+func emptyNode() Node {
+	sum := sha256.Sum256([]byte("empty"))
+	return Node{Hash: hex.EncodeToString(sum[:]), Sum: decimal.Zero}
+}
+
+// BuildTree 根据叶子构建Merkle求和树，叶子顺序即为叶子序号
+func BuildTree(leaves []Leaf) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, ErrEmptyTree
+	}
+
+	level := make([]Node, 0, len(leaves))
+	for _, leaf := range leaves {
+		node, err := LeafNode(leaf.UserHash, leaf.Balance)
+		if err != nil {
+			return nil, err
+		}
+		level = append(level, node)
+	}
+
+	tree := &Tree{levels: [][]Node{level}}
+	for len(level) > 1 {
+		next := make([]Node, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			right := emptyNode()
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			next = append(next, ParentNode(level[i], right))
+		}
+		tree.levels = append(tree.levels, next)
+		level = next
+	}
+
+	return tree, nil
+}
+
+// Root 返回根节点
+func (t *Tree) Root() Node {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// ProofPath 返回指定叶子到根的兄弟节点路径
+func (t *Tree) ProofPath(index int) ([]ProofNode, error) {
+	if index < 0 || index >= len(t.levels[0]) {
+		return nil, ErrLeafNotFound
+	}
+
+	path := make([]ProofNode, 0, len(t.levels)-1)
+	for _, level := range t.levels[:len(t.levels)-1] {
+		var sibling Node
+		var position string
+		if index%2 == 0 {
+			sibling = emptyNode()
+			if index+1 < len(level) {
+				sibling = level[index+1]
+			}
+			position = PositionRight
+		} else {
+			sibling = level[index-1]
+			position = PositionLeft
+		}
+		path = append(path, ProofNode{Hash: sibling.Hash, Sum: sibling.Sum.String(), Position: position})
+		index /= 2
+	}
+
+	return path, nil
+}
+
+// VerifyProof 离线验证包含证明：校验用户ID哈希、逐层计算到根，并比对根哈希和负债总额
+func VerifyProof(proof *Proof, userID uint64) error {
+	if HashUserID(userID, proof.Salt) != proof.UserHash {
+		return fmt.Errorf("%w: user hash does not match user ID and salt", ErrProofMismatch)
+	}
+
+	node, err := LeafNode(proof.UserHash, proof.Balance)
+	if err != nil {
+		return err
+	}
+
+	for i, step := range proof.Path {
+		sum, err := decimal.NewFromString(step.Sum)
+		if err != nil {
+			return fmt.Errorf("invalid sum at proof step %d: %s", i, step.Sum)
+		}
+		if sum.IsNegative() {
+			return fmt.Errorf("negative sum at proof step %d: %w", i, ErrNegativeBalance)
+		}
+
+		sibling := Node{Hash: step.Hash, Sum: sum}
+		switch step.Position {
+		case PositionLeft:
+			node = ParentNode(sibling, node)
+		case PositionRight:
+			node = ParentNode(node, sibling)
+		default:
+			return fmt.Errorf("invalid position at proof step %d: %s", i, step.Position)
+		}
+	}
+
+	total, err := decimal.NewFromString(proof.TotalLiabilities)
+	if err != nil {
+		return fmt.Errorf("invalid total liabilities: %s", proof.TotalLiabilities)
+	}
+	if node.Hash != proof.RootHash || !node.Sum.Equal(total) {
+		return ErrProofMismatch
+	}
+
+	return nil
+}
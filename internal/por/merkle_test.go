@@ -0,0 +1,105 @@
+package por
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestTree(t *testing.T, balances []string) (*Tree, []Leaf) {
+	leaves := make([]Leaf, 0, len(balances))
+	for i, balance := range balances {
+		leaves = append(leaves, Leaf{UserHash: HashUserID(uint64(i+1), "salt"+strconv.Itoa(i)), Balance: balance})
+	}
+	tree, err := BuildTree(leaves)
+	assert.NoError(t, err)
+	return tree, leaves
+}
+
+func TestBuildTree_RootSum(t *testing.T) {
+	tree, _ := buildTestTree(t, []string{"1.5", "2", "0.25", "10", "3"})
+	assert.Equal(t, "16.75", tree.Root().Sum.String())
+
+	single, _ := buildTestTree(t, []string{"7"})
+	assert.Equal(t, "7", single.Root().Sum.String())
+
+	_, err := BuildTree(nil)
+	assert.ErrorIs(t, err, ErrEmptyTree)
+
+	_, err = BuildTree([]Leaf{{UserHash: "x", Balance: "-1"}})
+	assert.ErrorIs(t, err, ErrNegativeBalance)
+}
+
+func TestVerifyProof(t *testing.T) {
+	balances := []string{"1.5", "2", "0.25", "10", "3"}
+	tree, leaves := buildTestTree(t, balances)
+	root := tree.Root()
+
+	for i := range leaves {
+		path, err := tree.ProofPath(i)
+		assert.NoError(t, err)
+
+		proof := &Proof{
+			Currency:         "BTC",
+			RootHash:         root.Hash,
+			TotalLiabilities: root.Sum.String(),
+			LeafIndex:        int64(i),
+			UserHash:         leaves[i].UserHash,
+			Salt:             "salt" + strconv.Itoa(i),
+			Balance:          leaves[i].Balance,
+			Path:             path,
+		}
+
+		// 经过JSON往返后仍可验证
+		data, err := json.Marshal(proof)
+		assert.NoError(t, err)
+		var decoded Proof
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.NoError(t, VerifyProof(&decoded, uint64(i+1)))
+
+		// 错误的用户ID
+		assert.ErrorIs(t, VerifyProof(proof, uint64(i+100)), ErrProofMismatch)
+	}
+
+	_, err := tree.ProofPath(len(leaves))
+	assert.ErrorIs(t, err, ErrLeafNotFound)
+}
+
+func TestVerifyProof_Tampered(t *testing.T) {
+	tree, leaves := buildTestTree(t, []string{"1", "2", "3", "4"})
+	root := tree.Root()
+	path, err := tree.ProofPath(1)
+	assert.NoError(t, err)
+
+	newProof := func() *Proof {
+		nodes := make([]ProofNode, len(path))
+		copy(nodes, path)
+		return &Proof{
+			RootHash:         root.Hash,
+			TotalLiabilities: root.Sum.String(),
+			UserHash:         leaves[1].UserHash,
+			Salt:             "salt1",
+			Balance:          leaves[1].Balance,
+			Path:             nodes,
+		}
+	}
+
+	proof := newProof()
+	proof.Balance = "3"
+	assert.ErrorIs(t, VerifyProof(proof, 2), ErrProofMismatch)
+
+	// 兄弟节点少报余额会导致根的和不一致
+	proof = newProof()
+	proof.Path[1].Sum = "0"
+	assert.ErrorIs(t, VerifyProof(proof, 2), ErrProofMismatch)
+
+	proof = newProof()
+	proof.TotalLiabilities = "9"
+	assert.ErrorIs(t, VerifyProof(proof, 2), ErrProofMismatch)
+
+	proof = newProof()
+	proof.Path[0].Sum = "-1"
+	assert.ErrorIs(t, VerifyProof(proof, 2), ErrNegativeBalance)
+}
@@ -0,0 +1,163 @@
+// Package ratelimit 按权重计数的滑动窗口限流。
+//
+// 每个限流键按窗口长度切分为固定窗口计数，当前用量按滑动窗口近似计算：
+// 上一窗口用量 × 上一窗口仍处于滑动窗口内的比例 + 当前窗口用量，避免固定窗口边界处出现两倍突发。
+// 每次请求按权重计数，超过额度的请求不计入用量。
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+var errUnexpectedReply = errors.New("unexpected rate limit script reply")
+
+// Rule 接口权重规则，未匹配任何规则的请求权重为1
+type Rule struct {
+	Method string `json:",optional"` // 请求方法，为空时匹配全部方法
+	Path   string // 请求路径，以*结尾时按前缀匹配
+	Weight int64  `json:",default=1"` // 权重
+	Param  string `json:",optional"`  // 按请求参数调整权重的参数名，如depth、limit
+	Step   int64  `json:",optional"`  // 参数值每Step计一次Weight，如Step为100时depth=1000的权重为10×Weight
+}
+
+// Match 判断规则是否匹配请求
+func (r Rule) Match(method, path string) bool {
+	if r.Method != "" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(r.Path, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+	return path == r.Path
+}
+
+// WeightOf 计算请求权重，value为Param参数的值，未提供时为0
+func (r Rule) WeightOf(value int64) int64 {
+	weight := r.Weight
+	if weight <= 0 {
+		weight = 1
+	}
+	if r.Param == "" || r.Step <= 0 || value <= r.Step {
+		return weight
+	}
+	return weight * ((value + r.Step - 1) / r.Step)
+}
+
+// Result 一次限流判断的结果
+type Result struct {
+	Allowed   bool          // 是否放行
+	Limit     int64         // 窗口内的额度
+	Remaining int64         // 剩余额度
+	Reset     time.Duration // 距当前窗口结束的时间
+}
+
+// Limiter 限流器
+type Limiter interface {
+	// Take 为key扣减weight的额度，limit为窗口内的额度
+	Take(ctx context.Context, key string, weight, limit int64, window time.Duration) (Result, error)
+}
+
+// takeScript 按滑动窗口计算用量，额度足够时累加当前窗口用量
+// KEYS[1]为当前窗口，KEYS[2]为上一窗口；返回 {是否放行, 扣减后的用量}
+const takeScript = `
+local weight = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local window = tonumber(ARGV[3])
+local elapsed = tonumber(ARGV[4])
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local previous = tonumber(redis.call('GET', KEYS[2]) or '0')
+local used = math.floor(previous * (window - elapsed) / window) + current
+if used + weight > limit then
+	return {0, used}
+end
+redis.call('INCRBY', KEYS[1], weight)
+redis.call('PEXPIRE', KEYS[1], window * 2)
+return {1, used + weight}
+`
+
+// RedisLimiter 基于Redis的限流器，多个实例共享额度
+type RedisLimiter struct {
+	rds *redis.Redis
+	now func() time.Time
+}
+
+// NewRedisLimiter 创建基于Redis的限流器
+func NewRedisLimiter(rds *redis.Redis) *RedisLimiter {
+	return &RedisLimiter{rds: rds, now: time.Now}
+}
+
+// Take 扣减额度
+func (l *RedisLimiter) Take(ctx context.Context, key string, weight, limit int64, window time.Duration) (Result, error) {
+	index, elapsed := position(l.now(), window)
+	// 两个窗口的键使用相同的hash tag，保证在Redis集群中位于同一槽位
+	keys := []string{windowKey(key, index), windowKey(key, index-1)}
+	val, err := l.rds.EvalCtx(ctx, takeScript, keys, weight, limit, window.Milliseconds(), elapsed.Milliseconds())
+	if err != nil {
+		return Result{}, err
+	}
+	ret, ok := val.([]interface{})
+	if !ok || len(ret) != 2 {
+		return Result{}, errUnexpectedReply
+	}
+	allowed, _ := ret[0].(int64)
+	used, _ := ret[1].(int64)
+	return newResult(allowed == 1, used, limit, window-elapsed), nil
+}
+
+// MemoryLimiter 进程内限流器，用于测试
+type MemoryLimiter struct {
+	mu     sync.Mutex
+	counts map[string]int64
+	now    func() time.Time
+}
+
+// NewMemoryLimiter 创建进程内限流器，now为nil时使用time.Now
+func NewMemoryLimiter(now func() time.Time) *MemoryLimiter {
+	if now == nil {
+		now = time.Now
+	}
+	return &MemoryLimiter{counts: make(map[string]int64), now: now}
+}
+
+// Take 扣减额度，与RedisLimiter使用相同的算法
+func (l *MemoryLimiter) Take(ctx context.Context, key string, weight, limit int64, window time.Duration) (Result, error) {
+	index, elapsed := position(l.now(), window)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current := l.counts[windowKey(key, index)]
+	previous := l.counts[windowKey(key, index-1)]
+	size := window.Milliseconds()
+	used := previous*(size-elapsed.Milliseconds())/size + current
+	if used+weight > limit {
+		return newResult(false, used, limit, window-elapsed), nil
+	}
+	l.counts[windowKey(key, index)] = current + weight
+	return newResult(true, used+weight, limit, window-elapsed), nil
+}
+
+// position 返回时间所在的窗口序号和已经过的时长，精确到毫秒
+func position(now time.Time, window time.Duration) (int64, time.Duration) {
+	ms := now.UnixMilli()
+	size := window.Milliseconds()
+	return ms / size, time.Duration(ms%size) * time.Millisecond
+}
+
+func windowKey(key string, index int64) string {
+	return "ratelimit:{" + key + "}:" + strconv.FormatInt(index, 10)
+}
+
+func newResult(allowed bool, used, limit int64, reset time.Duration) Result {
+	remaining := limit - used
+	if remaining < 0 {
+		remaining = 0
+	}
+	return Result{Allowed: allowed, Limit: limit, Remaining: remaining, Reset: reset}
+}
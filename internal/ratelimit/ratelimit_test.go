@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleMatch(t *testing.T) {
+	exact := Rule{Path: "/api/v1/market/depth"}
+	assert.True(t, exact.Match("GET", "/api/v1/market/depth"))
+	assert.False(t, exact.Match("GET", "/api/v1/market/depth/x"))
+
+	prefix := Rule{Method: "GET", Path: "/api/v1/trading/orders*"}
+	assert.True(t, prefix.Match("get", "/api/v1/trading/orders/123"))
+	assert.False(t, prefix.Match("POST", "/api/v1/trading/orders"))
+}
+
+func TestRuleWeightOf(t *testing.T) {
+	depth := Rule{Weight: 1, Param: "depth", Step: 100}
+	assert.Equal(t, int64(1), depth.WeightOf(0))
+	assert.Equal(t, int64(1), depth.WeightOf(100))
+	assert.Equal(t, int64(2), depth.WeightOf(101))
+	assert.Equal(t, int64(10), depth.WeightOf(1000))
+
+	assert.Equal(t, int64(40), Rule{Weight: 40}.WeightOf(1000))
+	assert.Equal(t, int64(1), Rule{}.WeightOf(0))
+}
+
+func TestMemoryLimiterSlidingWindow(t *testing.T) {
+	now := time.UnixMilli(60_000) // 窗口起点
+	limiter := NewMemoryLimiter(func() time.Time { return now })
+	ctx := context.Background()
+
+	res, err := limiter.Take(ctx, "user:1", 6, 10, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, int64(4), res.Remaining)
+	assert.Equal(t, time.Minute, res.Reset)
+
+	// 超出额度的请求不计入用量
+	res, err = limiter.Take(ctx, "user:1", 5, 10, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, int64(4), res.Remaining)
+
+	res, err = limiter.Take(ctx, "user:1", 4, 10, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, int64(0), res.Remaining)
+
+	// 其他键互不影响
+	res, err = limiter.Take(ctx, "user:2", 10, 10, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	// 下一窗口过去一半时，上一窗口的用量按一半计算
+	now = now.Add(90 * time.Second)
+	res, err = limiter.Take(ctx, "user:1", 5, 10, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, int64(0), res.Remaining)
+	assert.Equal(t, 30*time.Second, res.Reset)
+
+	// 两个窗口之后额度完全恢复
+	now = now.Add(2 * time.Minute)
+	res, err = limiter.Take(ctx, "user:1", 10, 10, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+}
+
+func TestWindowKey(t *testing.T) {
+	assert.Equal(t, "ratelimit:{apikey:ak}:7", windowKey("apikey:ak", 7))
+}
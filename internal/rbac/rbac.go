@@ -0,0 +1,129 @@
+// Package rbac 管理后台的角色权限定义。
+//
+// 角色保存在 user_roles 表中，登录时写入JWT的 roles 声明；每个管理接口按请求方法和路径
+// 对应一个权限，角色拥有的权限在本包中静态定义。
+package rbac
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"crypto-exchange/model"
+)
+
+// 管理后台权限
+const (
+	PermTradingPairRead     = "trading_pair:read"     // 查询交易对及状态排期
+	PermTradingPairWrite    = "trading_pair:write"    // 创建、修改交易对，变更交易对状态
+	PermCurrencyRead        = "currency:read"         // 查询币种和网络配置
+	PermCurrencyWrite       = "currency:write"        // 修改币种和网络配置
+	PermWithdrawalRead      = "withdrawal:read"       // 查询提现及审核日志
+	PermWithdrawalReview    = "withdrawal:review"     // 审核通过或拒绝提现
+	PermDepositAddressWrite = "deposit_address:write" // 导入充值地址池
+	PermRoleRead            = "role:read"             // 查询用户角色和角色变更记录
+	PermRoleManage          = "role:manage"           // 授予和撤销角色
+	PermKycRead             = "kyc:read"              // 查询身份认证申请、证件文件和认证等级变更记录
+	PermKycReview           = "kyc:review"            // 审核身份认证申请，调整用户认证等级
+	PermRestrictionRead     = "restriction:read"      // 查询账户限制和限制变更记录
+	PermRestrictionManage   = "restriction:manage"    // 施加和解除账户限制
+)
+
+var allPermissions = []string{
+	PermTradingPairRead, PermTradingPairWrite,
+	PermCurrencyRead, PermCurrencyWrite,
+	PermWithdrawalRead, PermWithdrawalReview,
+	PermDepositAddressWrite,
+	PermRoleRead, PermRoleManage,
+	PermKycRead, PermKycReview,
+	PermRestrictionRead, PermRestrictionManage,
+}
+
+// rolePermissions 各角色拥有的权限
+var rolePermissions = map[string][]string{
+	model.RoleSuperAdmin: allPermissions,
+	model.RoleOperator: {
+		PermTradingPairRead, PermTradingPairWrite,
+		PermCurrencyRead, PermCurrencyWrite,
+		PermDepositAddressWrite,
+	},
+	model.RoleFinance: {
+		PermCurrencyRead,
+		PermWithdrawalRead, PermWithdrawalReview,
+	},
+	model.RoleCompliance: {
+		PermKycRead, PermKycReview,
+		PermRestrictionRead, PermRestrictionManage,
+	},
+	model.RoleAuditor: {
+		PermTradingPairRead, PermCurrencyRead, PermWithdrawalRead, PermRoleRead, PermKycRead, PermRestrictionRead,
+	},
+}
+
+// routePermission 管理接口路径前缀（去掉 /api/v1/admin）对应的读写权限
+type routePermission struct {
+	prefix string
+	read   string // GET请求所需权限
+	write  string // 其他请求所需权限
+}
+
+var routePermissions = []routePermission{
+	{prefix: "/trading-pairs", read: PermTradingPairRead, write: PermTradingPairWrite},
+	{prefix: "/currencies", read: PermCurrencyRead, write: PermCurrencyWrite},
+	{prefix: "/withdrawals", read: PermWithdrawalRead, write: PermWithdrawalReview},
+	{prefix: "/deposit-address-pool", write: PermDepositAddressWrite},
+	{prefix: "/users", read: PermRoleRead, write: PermRoleManage},
+	{prefix: "/role-audit-logs", read: PermRoleRead},
+	{prefix: "/kyc", read: PermKycRead, write: PermKycReview},
+	{prefix: "/restrictions", read: PermRestrictionRead, write: PermRestrictionManage},
+}
+
+// IsValidRole 判断是否为已定义的角色
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Permissions 返回角色集合拥有的全部权限，按名称排序，未定义的角色被忽略
+func Permissions(roles []string) []string {
+	set := make(map[string]bool)
+	for _, role := range roles {
+		for _, perm := range rolePermissions[role] {
+			set[perm] = true
+		}
+	}
+	resp := make([]string, 0, len(set))
+	for perm := range set {
+		resp = append(resp, perm)
+	}
+	sort.Strings(resp)
+	return resp
+}
+
+// HasPermission 判断角色集合是否拥有指定权限
+func HasPermission(roles []string, permission string) bool {
+	for _, role := range roles {
+		for _, perm := range rolePermissions[role] {
+			if perm == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RequiredPermission 返回管理接口所需的权限，path为去掉 /api/v1/admin 前缀后的路径
+// 未登记的接口返回false，只有超级管理员可以访问
+func RequiredPermission(method, path string) (string, bool) {
+	for _, rp := range routePermissions {
+		if path != rp.prefix && !strings.HasPrefix(path, rp.prefix+"/") {
+			continue
+		}
+		perm := rp.write
+		if method == http.MethodGet || method == http.MethodHead {
+			perm = rp.read
+		}
+		return perm, perm != ""
+	}
+	return "", false
+}
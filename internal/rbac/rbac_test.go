@@ -0,0 +1,60 @@
+package rbac
+
+import (
+	"net/http"
+	"testing"
+
+	"crypto-exchange/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequiredPermission(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   string
+		ok     bool
+	}{
+		{http.MethodGet, "/trading-pairs", PermTradingPairRead, true},
+		{http.MethodGet, "/trading-pairs/stats", PermTradingPairRead, true},
+		{http.MethodPost, "/trading-pairs/BTC_USDT/status", PermTradingPairWrite, true},
+		{http.MethodDelete, "/currencies/BTC/networks/BTC", PermCurrencyWrite, true},
+		{http.MethodPost, "/withdrawals/W123/approve", PermWithdrawalReview, true},
+		{http.MethodGet, "/withdrawals/W123/audit-logs", PermWithdrawalRead, true},
+		{http.MethodPost, "/deposit-address-pool", PermDepositAddressWrite, true},
+		{http.MethodGet, "/deposit-address-pool", "", false},
+		{http.MethodPost, "/users/7/roles", PermRoleManage, true},
+		{http.MethodGet, "/role-audit-logs", PermRoleRead, true},
+		{http.MethodGet, "/kyc/submissions", PermKycRead, true},
+		{http.MethodPost, "/kyc/submissions/3/approve", PermKycReview, true},
+		{http.MethodPost, "/kyc/users/7/level", PermKycReview, true},
+		{http.MethodGet, "/restrictions/users/7", PermRestrictionRead, true},
+		{http.MethodDelete, "/restrictions/users/7/freeze", PermRestrictionManage, true},
+		{http.MethodGet, "/restrictions/audit-logs", PermRestrictionRead, true},
+		{http.MethodGet, "/trading-pairs-export", "", false},
+		{http.MethodGet, "/unknown", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := RequiredPermission(tt.method, tt.path)
+		assert.Equal(t, tt.ok, ok, "%s %s", tt.method, tt.path)
+		assert.Equal(t, tt.want, got, "%s %s", tt.method, tt.path)
+	}
+}
+
+func TestHasPermission(t *testing.T) {
+	assert.True(t, HasPermission([]string{model.RoleSuperAdmin}, PermRoleManage))
+	assert.True(t, HasPermission([]string{model.RoleAuditor, model.RoleFinance}, PermWithdrawalReview))
+	assert.False(t, HasPermission([]string{model.RoleOperator}, PermWithdrawalReview))
+	assert.False(t, HasPermission([]string{model.RoleAuditor}, PermTradingPairWrite))
+	assert.True(t, HasPermission([]string{model.RoleCompliance}, PermKycReview))
+	assert.False(t, HasPermission([]string{model.RoleAuditor}, PermKycReview))
+	assert.True(t, HasPermission([]string{model.RoleCompliance}, PermRestrictionManage))
+	assert.False(t, HasPermission([]string{model.RoleAuditor}, PermRestrictionManage))
+	assert.False(t, HasPermission([]string{"unknown"}, PermTradingPairRead))
+	assert.False(t, HasPermission(nil, PermTradingPairRead))
+
+	assert.Equal(t, []string{PermCurrencyRead, PermKycRead, PermRestrictionRead, PermRoleRead, PermTradingPairRead, PermWithdrawalRead}, Permissions([]string{model.RoleAuditor}))
+	assert.Len(t, Permissions([]string{model.RoleAuditor, model.RoleFinance}), 7)
+}
@@ -0,0 +1,332 @@
+// Package session 登录会话和刷新令牌。
+//
+// 每次登录创建一个会话，会话ID写入访问令牌（JWT）的sid声明（见 authctx.ClaimSessionID），刷新令牌格式为 sessionID.secret。
+// 刷新令牌每次使用后轮换，Redis中保存当前刷新令牌的SHA-256哈希，以及该会话已轮换的旧令牌哈希（令牌族）；
+// 已轮换的旧令牌再次出现说明令牌可能已泄露，此时整个会话立即失效；从未签发过的令牌只会被拒绝，不影响会话。
+// 会话删除后，携带该sid的访问令牌也随之失效。
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/redis"
+)
+
+var (
+	ErrSessionNotFound     = errors.New("session not found or revoked")
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+)
+
+const (
+	sessionIDBytes = 16 // 会话ID随机字节数
+	secretBytes    = 32 // 刷新令牌随机字节数
+)
+
+// Session 登录会话
+type Session struct {
+	ID         string `json:"id"`           // 会话ID
+	UserID     uint64 `json:"user_id"`      // 用户ID
+	UserAgent  string `json:"user_agent"`   // 登录时的User-Agent
+	CreatedAt  int64  `json:"created_at"`   // 登录时间（Unix秒）
+	LastUsedAt int64  `json:"last_used_at"` // 最近一次刷新时间（Unix秒）
+	ExpiresAt  int64  `json:"expires_at"`   // 刷新令牌过期时间（Unix秒）
+}
+
+// Store 会话存储
+type Store interface {
+	// Create 创建会话，返回会话和刷新令牌
+	Create(ctx context.Context, userID uint64, userAgent string) (*Session, string, error)
+	// Refresh 校验并轮换刷新令牌，返回会话和新的刷新令牌；已轮换的旧令牌被重复使用时会话失效并返回ErrRefreshTokenReused，
+	// 未签发过的令牌返回ErrRefreshTokenInvalid
+	Refresh(ctx context.Context, refreshToken, userAgent string) (*Session, string, error)
+	// Exists 判断会话是否有效，用于校验访问令牌
+	Exists(ctx context.Context, sessionID string) (bool, error)
+	// List 查询用户的有效会话，按登录时间倒序
+	List(ctx context.Context, userID uint64) ([]*Session, error)
+	// Revoke 注销用户的指定会话，会话不存在或不属于该用户时返回ErrSessionNotFound
+	Revoke(ctx context.Context, userID uint64, sessionID string) error
+	// RevokeAll 注销用户的全部会话
+	RevokeAll(ctx context.Context, userID uint64) error
+}
+
+// rotateScript 比较并替换刷新令牌哈希，将被替换的哈希加入令牌族，同时延长会话有效期
+// 返回1表示成功，0表示会话不存在，-1表示令牌已被轮换过（旧令牌被重复使用），-2表示令牌从未签发过
+const rotateScript = `
+local current = redis.call('GET', KEYS[1])
+if not current then
+	return 0
+end
+if current ~= ARGV[1] then
+	if redis.call('SISMEMBER', KEYS[3], ARGV[1]) == 1 then
+		return -1
+	end
+	return -2
+end
+redis.call('SET', KEYS[1], ARGV[2], 'EX', ARGV[4])
+redis.call('SET', KEYS[2], ARGV[3], 'EX', ARGV[4])
+redis.call('SADD', KEYS[3], ARGV[1])
+redis.call('EXPIRE', KEYS[3], ARGV[4])
+return 1
+`
+
+// RedisStore 基于Redis的会话存储
+type RedisStore struct {
+	rds           *redis.Redis
+	refreshExpire time.Duration
+	now           func() time.Time
+}
+
+// NewRedisStore 创建基于Redis的会话存储，refreshExpire为刷新令牌有效期，每次刷新后重新计算
+func NewRedisStore(rds *redis.Redis, refreshExpire time.Duration) *RedisStore {
+	return &RedisStore{
+		rds:           rds,
+		refreshExpire: refreshExpire,
+		now:           time.Now,
+	}
+}
+
+// Create 创建会话
+func (s *RedisStore) Create(ctx context.Context, userID uint64, userAgent string) (*Session, string, error) {
+	sessionID, err := randomHex(sessionIDBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	token, secret, err := newRefreshToken(sessionID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := s.now()
+	sess := &Session{
+		ID:         sessionID,
+		UserID:     userID,
+		UserAgent:  userAgent,
+		CreatedAt:  now.Unix(),
+		LastUsedAt: now.Unix(),
+		ExpiresAt:  now.Add(s.refreshExpire).Unix(),
+	}
+	data, err := json.Marshal(sess)
+	if err != nil {
+		return nil, "", err
+	}
+
+	seconds := s.ttlSeconds()
+	if err := s.rds.SetexCtx(ctx, sessionKey(sessionID), string(data), seconds); err != nil {
+		return nil, "", err
+	}
+	if err := s.rds.SetexCtx(ctx, refreshKey(sessionID), hashSecret(secret), seconds); err != nil {
+		return nil, "", err
+	}
+	if _, err := s.rds.SaddCtx(ctx, userSessionsKey(userID), sessionID); err != nil {
+		return nil, "", err
+	}
+	if err := s.rds.ExpireCtx(ctx, userSessionsKey(userID), seconds); err != nil {
+		return nil, "", err
+	}
+	return sess, token, nil
+}
+
+// Refresh 轮换刷新令牌
+func (s *RedisStore) Refresh(ctx context.Context, refreshToken, userAgent string) (*Session, string, error) {
+	sessionID, secret, ok := parseRefreshToken(refreshToken)
+	if !ok {
+		return nil, "", ErrRefreshTokenInvalid
+	}
+	sess, err := s.get(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil, "", ErrRefreshTokenInvalid
+		}
+		return nil, "", err
+	}
+
+	newToken, newSecret, err := newRefreshToken(sessionID)
+	if err != nil {
+		return nil, "", err
+	}
+	now := s.now()
+	sess.LastUsedAt = now.Unix()
+	sess.ExpiresAt = now.Add(s.refreshExpire).Unix()
+	if userAgent != "" {
+		sess.UserAgent = userAgent
+	}
+	data, err := json.Marshal(sess)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result, err := s.rds.EvalCtx(ctx, rotateScript, []string{refreshKey(sessionID), sessionKey(sessionID), rotatedKey(sessionID)},
+		hashSecret(secret), hashSecret(newSecret), string(data), s.ttlSeconds())
+	if err != nil {
+		return nil, "", err
+	}
+	switch result {
+	case int64(1):
+		_ = s.rds.ExpireCtx(ctx, userSessionsKey(sess.UserID), s.ttlSeconds())
+		return sess, newToken, nil
+	case int64(-1):
+		// 已轮换的旧令牌被重复使用，令牌可能已泄露，注销整个会话
+		if err := s.Revoke(ctx, sess.UserID, sessionID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return nil, "", err
+		}
+		return nil, "", ErrRefreshTokenReused
+	default:
+		return nil, "", ErrRefreshTokenInvalid
+	}
+}
+
+// Exists 判断会话是否有效
+func (s *RedisStore) Exists(ctx context.Context, sessionID string) (bool, error) {
+	return s.rds.ExistsCtx(ctx, sessionKey(sessionID))
+}
+
+// List 查询用户的有效会话，顺带清理已过期的会话ID
+func (s *RedisStore) List(ctx context.Context, userID uint64) ([]*Session, error) {
+	ids, err := s.rds.SmembersCtx(ctx, userSessionsKey(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*Session, 0, len(ids))
+	var expired []any
+	for _, id := range ids {
+		sess, err := s.get(ctx, id)
+		if err != nil {
+			if errors.Is(err, ErrSessionNotFound) {
+				expired = append(expired, id)
+				continue
+			}
+			return nil, err
+		}
+		sessions = append(sessions, sess)
+	}
+	if len(expired) > 0 {
+		_, _ = s.rds.SremCtx(ctx, userSessionsKey(userID), expired...)
+	}
+
+	sortSessions(sessions)
+	return sessions, nil
+}
+
+// Revoke 注销指定会话
+func (s *RedisStore) Revoke(ctx context.Context, userID uint64, sessionID string) error {
+	sess, err := s.get(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if sess.UserID != userID {
+		return ErrSessionNotFound
+	}
+	if _, err := s.rds.DelCtx(ctx, sessionKey(sessionID), refreshKey(sessionID), rotatedKey(sessionID)); err != nil {
+		return err
+	}
+	_, err = s.rds.SremCtx(ctx, userSessionsKey(userID), sessionID)
+	return err
+}
+
+// RevokeAll 注销用户的全部会话
+func (s *RedisStore) RevokeAll(ctx context.Context, userID uint64) error {
+	ids, err := s.rds.SmembersCtx(ctx, userSessionsKey(userID))
+	if err != nil {
+		return err
+	}
+	keys := []string{userSessionsKey(userID)}
+	for _, id := range ids {
+		keys = append(keys, sessionKey(id), refreshKey(id), rotatedKey(id))
+	}
+	_, err = s.rds.DelCtx(ctx, keys...)
+	return err
+}
+
+func (s *RedisStore) get(ctx context.Context, sessionID string) (*Session, error) {
+	data, err := s.rds.GetCtx(ctx, sessionKey(sessionID))
+	if err != nil {
+		return nil, err
+	}
+	if data == "" {
+		return nil, ErrSessionNotFound
+	}
+	var sess Session
+	if err := json.Unmarshal([]byte(data), &sess); err != nil {
+		return nil, err
+	}
+	return &sess, nil
+}
+
+func (s *RedisStore) ttlSeconds() int {
+	seconds := int(s.refreshExpire / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
+
+// sortSessions 按登录时间倒序排列会话
+func sortSessions(sessions []*Session) {
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].CreatedAt != sessions[j].CreatedAt {
+			return sessions[i].CreatedAt > sessions[j].CreatedAt
+		}
+		return sessions[i].ID < sessions[j].ID
+	})
+}
+
+// newRefreshToken 生成会话的刷新令牌
+func newRefreshToken(sessionID string) (token, secret string, err error) {
+	secret, err = randomHex(secretBytes)
+	if err != nil {
+		return "", "", err
+	}
+	return sessionID + "." + secret, secret, nil
+}
+
+// parseRefreshToken 拆分刷新令牌中的会话ID和随机部分
+func parseRefreshToken(token string) (sessionID, secret string, ok bool) {
+	sessionID, secret, ok = strings.Cut(token, ".")
+	if !ok || len(sessionID) != 2*sessionIDBytes || len(secret) != 2*secretBytes {
+		return "", "", false
+	}
+	if _, err := hex.DecodeString(sessionID); err != nil {
+		return "", "", false
+	}
+	return sessionID, secret, true
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func sessionKey(sessionID string) string {
+	return fmt.Sprintf("session:%s", sessionID)
+}
+
+func refreshKey(sessionID string) string {
+	return fmt.Sprintf("session:%s:refresh", sessionID)
+}
+
+func rotatedKey(sessionID string) string {
+	return fmt.Sprintf("session:%s:rotated", sessionID)
+}
+
+func userSessionsKey(userID uint64) string {
+	return fmt.Sprintf("session:user:%d", userID)
+}
@@ -0,0 +1,35 @@
+package session
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshToken(t *testing.T) {
+	token, secret, err := newRefreshToken("0123456789abcdef0123456789abcdef")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, "0123456789abcdef0123456789abcdef."))
+
+	sessionID, parsed, ok := parseRefreshToken(token)
+	assert.True(t, ok)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef", sessionID)
+	assert.Equal(t, secret, parsed)
+
+	// Redis中只保存哈希
+	assert.NotContains(t, hashSecret(secret), secret)
+	assert.Equal(t, hashSecret(secret), hashSecret(parsed))
+
+	for _, invalid := range []string{"", "abc", sessionID, sessionID + ".short", "zz" + sessionID[2:] + "." + secret} {
+		_, _, ok := parseRefreshToken(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
+
+func TestSortSessions(t *testing.T) {
+	sessions := []*Session{{ID: "a", CreatedAt: 1}, {ID: "c", CreatedAt: 2}, {ID: "b", CreatedAt: 2}}
+	sortSessions(sessions)
+	assert.Equal(t, []string{"b", "c", "a"}, []string{sessions[0].ID, sessions[1].ID, sessions[2].ID})
+	assert.Equal(t, "session:user:7", userSessionsKey(7))
+}
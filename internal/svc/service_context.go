@@ -1,12 +1,25 @@
 package svc
 
 import (
+	"time"
+
+	"crypto-exchange/internal/blobstore"
+	"crypto-exchange/internal/chain"
 	"crypto-exchange/internal/config"
+	"crypto-exchange/internal/currency"
+	"crypto-exchange/internal/mailer"
 	"crypto-exchange/internal/matching"
+	"crypto-exchange/internal/middleware"
+	"crypto-exchange/internal/notify"
+	"crypto-exchange/internal/onetime"
+	"crypto-exchange/internal/ratelimit"
+	"crypto-exchange/internal/session"
+	"crypto-exchange/internal/totp"
 	"crypto-exchange/model"
 
 	"github.com/zeromicro/go-zero/core/stores/redis"
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"github.com/zeromicro/go-zero/rest"
 
 	// Import PostgreSQL driver
 	_ "github.com/lib/pq"
@@ -20,25 +33,115 @@ type ServiceContext struct {
 	OrderModel             model.OrderModel
 	TradeModel             model.TradeModel
 	TradingPairModel       model.TradingPairModel
+	TradingPairStatusChangeModel model.TradingPairStatusChangeModel
 	TickerModel            model.TickerModel
 	KlineModel             model.KlineModel
+	LedgerEntryModel       model.LedgerEntryModel
+	ReconciliationReportModel model.ReconciliationReportModel
+	BalanceDiscrepancyModel   model.BalanceDiscrepancyModel
+	ReserveSnapshotModel      model.ReserveSnapshotModel
+	ReserveSnapshotLeafModel  model.ReserveSnapshotLeafModel
+	CurrencyModel             model.CurrencyModel
+	CurrencyNetworkModel      model.CurrencyNetworkModel
+	CurrencyRegistry          *currency.Registry // 币种注册表，带进程内缓存
+	WithdrawalAuditLogModel   model.WithdrawalAuditLogModel
+	ChainAdapters             *chain.Registry // 网络代码 -> 区块链适配器
+	DepositAddressModel       model.DepositAddressModel
+	ChainScanCursorModel      model.ChainScanCursorModel
+	WithdrawalAddressModel    model.WithdrawalAddressModel
+	ConfirmCodes              onetime.Store // 一次性确认码，如提现确认码
+	ApiKeyModel               model.ApiKeyModel
+	ApiKeyAuth                rest.Middleware // 用户接口认证，支持JWT和API密钥签名
+	UserRoleModel             model.UserRoleModel
+	RoleAuditLogModel         model.RoleAuditLogModel
+	AdminAuth                 rest.Middleware // 管理接口按角色权限鉴权
+	UserTotpModel             model.UserTotpModel
+	TOTP                      *totp.Authenticator // 两步验证密钥加解密和验证码校验
+	Sessions                  session.Store       // 登录会话和刷新令牌
+	SessionAuth               rest.Middleware     // 校验JWT所属会话是否已注销
+	UserTokens                onetime.TokenStore  // 邮箱验证、重置密码等邮件链接中的一次性令牌
+	Mailer                    mailer.Mailer       // 邮件发送
+	KycSubmissionModel        model.KycSubmissionModel
+	VerificationLevelChangeModel model.VerificationLevelChangeModel
+	KycDocuments              blobstore.Store // 身份认证证件文件存储
+	SecurityEventModel        model.SecurityEventModel
+	UserRestrictionModel      model.UserRestrictionModel
+	Notifier                  notify.Notifier // 新设备登录等安全提醒
+	ClientInfo                rest.Middleware // 全局中间件，解析请求来源IP、User-Agent和设备指纹
+	RateLimit                 rest.Middleware // 按用户、API密钥和IP限流，需放在认证中间件之后
 	RedisClient            *redis.Redis
 	MatchingEngine         matching.Engine  // 使用接口避免循环引用
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
 	conn := sqlx.NewSqlConn("postgres", c.DataSource)
+	currencyModel := model.NewCurrencyModel(conn)
+	currencyNetworkModel := model.NewCurrencyNetworkModel(conn)
+	redisClient := redis.MustNewRedis(c.Redis)
+	userModel := model.NewUserModel(conn)
+	apiKeyModel := model.NewApiKeyModel(conn)
+	userRestrictionModel := model.NewUserRestrictionModel(conn)
+	userRoleModel := model.NewUserRoleModel(conn)
+	authenticator := totp.NewAuthenticator(c.TwoFactor.Issuer, c.TwoFactor.EncryptionKey, c.TwoFactor.Skew)
+	mail := mailer.NewSMTPMailer(c.Email.SMTP.Host, c.Email.SMTP.Port, c.Email.SMTP.Username, c.Email.SMTP.Password, c.Email.SMTP.From)
+	sessions := session.NewRedisStore(redisClient, time.Duration(c.Session.RefreshExpire)*time.Second)
+	var chainFactory chain.Factory
+	if c.Chain.Simulated {
+		chainFactory = chain.SimulatedFactory(time.Duration(c.Chain.BlockTime) * time.Second)
+	}
 	return &ServiceContext{
 		Config:                 c,
-		UserModel:              model.NewUserModel(conn),
+		UserModel:              userModel,
 		BalanceModel:           model.NewBalanceModel(conn),
 		AssetTransactionModel:  model.NewAssetTransactionModel(conn),
 		OrderModel:             model.NewOrderModel(conn),
 		TradeModel:             model.NewTradeModel(conn),
 		TradingPairModel:       model.NewTradingPairModel(conn),
+		TradingPairStatusChangeModel: model.NewTradingPairStatusChangeModel(conn),
 		TickerModel:            model.NewTickerModel(conn),
 		KlineModel:             model.NewKlineModel(conn),
-		RedisClient:            redis.MustNewRedis(c.Redis),
+		LedgerEntryModel:       model.NewLedgerEntryModel(conn),
+		ReconciliationReportModel: model.NewReconciliationReportModel(conn),
+		BalanceDiscrepancyModel:   model.NewBalanceDiscrepancyModel(conn),
+		ReserveSnapshotModel:      model.NewReserveSnapshotModel(conn),
+		ReserveSnapshotLeafModel:  model.NewReserveSnapshotLeafModel(conn),
+		CurrencyModel:             currencyModel,
+		CurrencyNetworkModel:      currencyNetworkModel,
+		CurrencyRegistry:          currency.NewRegistry(currencyModel, currencyNetworkModel, time.Duration(c.Currency.CacheTTL)*time.Second),
+		WithdrawalAuditLogModel:   model.NewWithdrawalAuditLogModel(conn),
+		ChainAdapters:             chain.NewRegistry(chainFactory),
+		DepositAddressModel:       model.NewDepositAddressModel(conn),
+		ChainScanCursorModel:      model.NewChainScanCursorModel(conn),
+		WithdrawalAddressModel:    model.NewWithdrawalAddressModel(conn),
+		ConfirmCodes:              onetime.NewRedisStore(redisClient, c.Withdraw.ConfirmCodeDigits, c.Withdraw.ConfirmMaxAttempts),
+		ApiKeyModel:               apiKeyModel,
+		ApiKeyAuth: middleware.NewApiKeyAuthMiddleware(c.Auth.AccessSecret, time.Duration(c.ApiKey.RecvWindow)*time.Millisecond,
+			c.ApiKey.TrustForwardedFor, apiKeyModel, userModel, userRestrictionModel, redisClient, authenticator).Handle,
+		UserRoleModel:             userRoleModel,
+		RoleAuditLogModel:         model.NewRoleAuditLogModel(conn),
+		AdminAuth:                 middleware.NewAdminAuthMiddleware(userRoleModel).Handle,
+		UserTotpModel:             model.NewUserTotpModel(conn),
+		TOTP:                      authenticator,
+		Sessions:                  sessions,
+		SessionAuth:               middleware.NewSessionAuthMiddleware(sessions).Handle,
+		UserTokens:                onetime.NewRedisTokenStore(redisClient),
+		Mailer:                    mail,
+		KycSubmissionModel:        model.NewKycSubmissionModel(conn),
+		VerificationLevelChangeModel: model.NewVerificationLevelChangeModel(conn),
+		KycDocuments:              blobstore.MustNewStore(c.Kyc.Storage),
+		SecurityEventModel:        model.NewSecurityEventModel(conn),
+		UserRestrictionModel:      userRestrictionModel,
+		Notifier:                  notify.NewMailNotifier(mail),
+		ClientInfo:                middleware.NewClientInfoMiddleware(c.ApiKey.TrustForwardedFor).Handle,
+		RateLimit: middleware.NewRateLimitMiddleware(ratelimit.NewRedisLimiter(redisClient), time.Duration(c.RateLimit.Seconds)*time.Second,
+			middleware.RateLimitQuota{
+				User:            int64(c.RateLimit.Quota),
+				ApiKey:          int64(c.RateLimit.ApiKeyQuota),
+				IP:              int64(c.RateLimit.IPQuota),
+				OrdersPerSecond: int64(c.RateLimit.OrdersPerSecond),
+				OrdersPerDay:    int64(c.RateLimit.OrdersPerDay),
+			}, c.RateLimit.Weights).Handle,
+		RedisClient:            redisClient,
 		MatchingEngine:         matching.NewMatchingEngine(),  // 初始化撮合引擎
 	}
 }
@@ -0,0 +1,82 @@
+package totp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"time"
+)
+
+var errCiphertextTooShort = errors.New("totp: ciphertext too short")
+
+// Authenticator 两步验证器，负责密钥的加解密和验证码校验
+// 密钥使用AES-256-GCM加密保存，加密密钥为配置的EncryptionKey的SHA-256摘要
+type Authenticator struct {
+	Issuer string           // 认证器App中显示的发行方名称
+	Skew   int              // 允许的时钟偏差（步长数）
+	Now    func() time.Time // 时钟，测试时可以替换为固定时间
+	aead   cipher.AEAD
+}
+
+// NewAuthenticator 创建两步验证器
+func NewAuthenticator(issuer, encryptionKey string, skew int) *Authenticator {
+	key := sha256.Sum256([]byte(encryptionKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		panic(err) // 32字节密钥不会出错
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err)
+	}
+	return &Authenticator{
+		Issuer: issuer,
+		Skew:   skew,
+		Now:    time.Now,
+		aead:   aead,
+	}
+}
+
+// Encrypt 加密密钥，返回base64(nonce|密文)
+func (a *Authenticator) Encrypt(secret string) (string, error) {
+	nonce := make([]byte, a.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := a.aead.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密Encrypt的结果
+func (a *Authenticator) Decrypt(encrypted string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < a.aead.NonceSize() {
+		return "", errCiphertextTooShort
+	}
+	nonce, ciphertext := data[:a.aead.NonceSize()], data[a.aead.NonceSize():]
+	plaintext, err := a.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Verify 校验验证码，只接受晚于lastUsedStep的时间步，防止同一验证码被重复使用
+// 校验通过时返回匹配的时间步，调用方需要将其保存为新的lastUsedStep
+func (a *Authenticator) Verify(encryptedSecret, code string, lastUsedStep int64) (int64, bool, error) {
+	secret, err := a.Decrypt(encryptedSecret)
+	if err != nil {
+		return 0, false, err
+	}
+	step, ok := Match(secret, code, a.Now(), a.Skew)
+	if !ok || step <= lastUsedStep {
+		return 0, false, nil
+	}
+	return step, true, nil
+}
@@ -0,0 +1,94 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret RFC 6238附录B中SHA1测试向量使用的密钥
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCodeAt_RFC6238Vectors(t *testing.T) {
+	// RFC给出的是8位验证码，6位验证码为其后6位
+	vectors := map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	}
+	for unix, want := range vectors {
+		code, err := CodeAt(rfcSecret, Step(time.Unix(unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, want[2:], code, "T=%d", unix)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current, _ := CodeAt(rfcSecret, Step(now))
+	previous, _ := CodeAt(rfcSecret, Step(now)-1)
+	old, _ := CodeAt(rfcSecret, Step(now)-2)
+
+	step, ok := Match(rfcSecret, current, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now), step)
+
+	step, ok = Match(rfcSecret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now)-1, step)
+
+	_, ok = Match(rfcSecret, old, now, 1)
+	assert.False(t, ok)
+	_, ok = Match(rfcSecret, "12345", now, 1)
+	assert.False(t, ok)
+}
+
+func TestAuthenticator_Verify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	a := NewAuthenticator("Exchange", "test-key", 1)
+	a.Now = func() time.Time { return now }
+
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+	encrypted, err := a.Encrypt(secret)
+	assert.NoError(t, err)
+	assert.NotContains(t, encrypted, secret)
+
+	decrypted, err := a.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, secret, decrypted)
+
+	// 其他加密密钥无法解密
+	_, err = NewAuthenticator("Exchange", "other-key", 1).Decrypt(encrypted)
+	assert.Error(t, err)
+
+	code, _ := CodeAt(secret, Step(now))
+	step, ok, err := a.Verify(encrypted, code, 0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// 已使用的时间步不能再次使用
+	_, ok, err = a.Verify(encrypted, code, step)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestProvisioningURIAndRecoveryCodes(t *testing.T) {
+	uri := ProvisioningURI("Crypto Exchange", "alice@example.com", "JBSWY3DPEHPK3PXP")
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Crypto%20Exchange:alice@example.com?"))
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=Crypto+Exchange")
+
+	codes, err := GenerateRecoveryCodes(10)
+	assert.NoError(t, err)
+	assert.Len(t, codes, 10)
+	assert.Len(t, codes[0], 11)
+	assert.Equal(t, HashRecoveryCode(codes[0]), HashRecoveryCode(" "+strings.ToUpper(codes[0])))
+	assert.NotEqual(t, HashRecoveryCode(codes[0]), HashRecoveryCode(codes[1]))
+}
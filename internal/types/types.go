@@ -13,20 +13,90 @@ type RegisterRequest struct {
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Email     string `json:"email" validate:"required,email"`
+	Password  string `json:"password" validate:"required"`
+	UserAgent string `header:"User-Agent,optional"` // 客户端标识，记录在登录会话中
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token             string `json:"token"`
+	RefreshToken      string `json:"refresh_token,omitempty"`  // 刷新令牌，每次使用后轮换，旧令牌重复使用会注销整个会话
+	ExpiresIn         int64  `json:"expires_in,omitempty"`     // 访问令牌有效期（秒）
+	TwoFactorRequired bool   `json:"two_factor_required"`      // 是否需要两步验证，为true时token为空，需携带pre_auth_token调用两步登录接口
+	PreAuthToken      string `json:"pre_auth_token,omitempty"` // 两步验证预认证token，短期有效
+	User              User   `json:"user"`
+}
+
+type LoginTwoFactorRequest struct {
+	PreAuthToken string `json:"pre_auth_token"`        // 登录接口返回的预认证token
+	Code         string `json:"code"`                  // 认证器App生成的验证码或恢复码
+	UserAgent    string `header:"User-Agent,optional"` // 客户端标识，记录在登录会话中
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`         // 刷新令牌
+	UserAgent    string `header:"User-Agent,optional"` // 客户端标识
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"` // 验证邮件中的令牌
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"` // 注册邮箱
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`        // 重置密码邮件中的令牌
+	NewPassword string `json:"new_password"` // 新密码
+}
+
+type Session struct {
+	ID         string `json:"id"`           // 会话ID
+	UserAgent  string `json:"user_agent"`   // 客户端标识
+	CreatedAt  string `json:"created_at"`   // 登录时间
+	LastUsedAt string `json:"last_used_at"` // 最近一次刷新时间
+	ExpiresAt  string `json:"expires_at"`   // 刷新令牌过期时间
+	Current    bool   `json:"current"`      // 是否为当前请求所属的会话
+}
+
+type SessionListResponse struct {
+	Sessions []Session `json:"sessions"`
+}
+
+type RevokeSessionRequest struct {
+	ID string `path:"id"` // 会话ID
 }
 
 type User struct {
-	ID       uint64 `json:"id"`
-	Email    string `json:"email"`
-	Nickname string `json:"nickname"`
-	Status   int64  `json:"status"`
+	ID            uint64 `json:"id"`
+	Email         string `json:"email"`
+	Nickname      string `json:"nickname"`
+	Status        int64  `json:"status"`
+	EmailVerified bool   `json:"email_verified"` // 邮箱是否已验证，未验证时不能交易和提现
+}
+
+type SecurityEvent struct {
+	ID                uint64 `json:"id"`                 // 事件ID
+	EventType         string `json:"event_type"`         // 事件类型
+	IP                string `json:"ip"`                 // 客户端IP
+	UserAgent         string `json:"user_agent"`         // 客户端标识
+	DeviceFingerprint string `json:"device_fingerprint"` // 设备指纹
+	Detail            string `json:"detail"`             // 事件详情
+	CreatedAt         string `json:"created_at"`         // 发生时间
+}
+
+type SecurityEventListRequest struct {
+	Type string `form:"type,optional"` // 事件类型（可选），为空时查询全部类型
+	Page int64  `form:"page,optional"` // 页码，默认1
+	Size int64  `form:"size,optional"` // 每页大小，默认20，最大100
+}
+
+type SecurityEventListResponse struct {
+	Events []SecurityEvent `json:"events"` // 安全事件，按时间倒序
+	Total  int64           `json:"total"`  // 总数量
+	Page   int64           `json:"page"`   // 当前页码
+	Size   int64           `json:"size"`   // 每页大小
 }
 
 type CreateOrderRequest struct {
@@ -70,16 +140,21 @@ type OrderListResponse struct {
 }
 
 type TradingPair struct {
-	ID            uint64 `json:"id"`             // 交易对ID
-	Symbol        string `json:"symbol"`         // 交易对符号
-	BaseCurrency  string `json:"base_currency"`  // 基础币种
-	QuoteCurrency string `json:"quote_currency"` // 计价币种
-	MinAmount     string `json:"min_amount"`     // 最小交易数量
-	MaxAmount     string `json:"max_amount"`     // 最大交易数量
-	PriceScale    int64  `json:"price_scale"`    // 价格精度
-	AmountScale   int64  `json:"amount_scale"`   // 数量精度
-	Status        int64  `json:"status"`         // 状态：1-正常，2-禁用
-	CreatedAt     string `json:"created_at"`     // 创建时间
+	ID                   uint64 `json:"id"`                     // 交易对ID
+	Symbol               string `json:"symbol"`                 // 交易对符号
+	BaseCurrency         string `json:"base_currency"`          // 基础币种
+	QuoteCurrency        string `json:"quote_currency"`         // 计价币种
+	MinAmount            string `json:"min_amount"`             // 最小交易数量
+	MaxAmount            string `json:"max_amount"`             // 最大交易数量
+	PriceScale           int64  `json:"price_scale"`            // 价格精度
+	AmountScale          int64  `json:"amount_scale"`           // 数量精度
+	TickSize             string `json:"tick_size"`              // 价格最小变动单位，0表示不限制
+	StepSize             string `json:"step_size"`              // 数量最小变动单位，0表示不限制
+	MinNotional          string `json:"min_notional"`           // 最小下单金额（价格×数量），0表示不限制
+	MaxOpenOrders        int64  `json:"max_open_orders"`        // 单用户最大挂单数量，0表示不限制
+	MinVerificationLevel int64  `json:"min_verification_level"` // 下单所需最低认证等级，0表示不限制
+	Status               int64  `json:"status"`                 // 状态：1-正常交易，2-暂停交易，3-预上线，4-仅可撤单，5-仅挂单，6-已下架
+	CreatedAt            string `json:"created_at"`             // 创建时间
 }
 
 type TradingPairListResponse struct {
@@ -174,38 +249,90 @@ type BalanceResponse struct {
 	Balances []Balance `json:"balances"` // 用户所有币种余额列表
 }
 
-type DepositRequest struct {
-	Currency string `json:"currency" validate:"required"` // 币种代码
-	Amount   string `json:"amount" validate:"required"`   // 充值金额
-}
-
-type DepositResponse struct {
-	TransactionID string `json:"transaction_id"` // 充值交易ID
-	Currency      string `json:"currency"`       // 币种代码
-	Amount        string `json:"amount"`         // 充值金额
-	Status        int64  `json:"status"`         // 充值状态：1-处理中，2-成功，3-失败
-	CreatedAt     string `json:"created_at"`     // 创建时间
-}
-
 type WithdrawRequest struct {
 	Currency string `json:"currency" validate:"required"` // 币种代码
+	Network  string `json:"network,optional"`             // 提现网络，如ERC20、TRC20；币种只支持一个网络时可为空
 	Amount   string `json:"amount" validate:"required"`   // 提现金额
 	Address  string `json:"address" validate:"required"`  // 提现地址
+	TotpCode string `json:"totp_code,optional"`           // 两步验证码，已启用两步验证时必填
 }
 
 type WithdrawResponse struct {
+	TransactionID    string `json:"transaction_id"`     // 提现交易ID
+	Currency         string `json:"currency"`           // 币种代码
+	Network          string `json:"network"`            // 网络
+	Amount           string `json:"amount"`             // 提现金额
+	Address          string `json:"address"`            // 提现地址
+	Fee              string `json:"fee"`                // 提现手续费
+	Status           int64  `json:"status"`             // 提现状态：9-待确认，1-待审核，5-已审核，6-广播中，2-成功，3-失败，4-已取消，7-已拒绝
+	ConfirmExpiresAt string `json:"confirm_expires_at"` // 确认码过期时间，过期未确认的提现自动取消
+	CreatedAt        string `json:"created_at"`         // 创建时间
+}
+
+type ConfirmWithdrawRequest struct {
 	TransactionID string `json:"transaction_id"` // 提现交易ID
-	Currency      string `json:"currency"`       // 币种代码
-	Amount        string `json:"amount"`         // 提现金额
-	Address       string `json:"address"`        // 提现地址
-	Fee           string `json:"fee"`            // 提现手续费
-	Status        int64  `json:"status"`         // 提现状态：1-待审核，2-处理中，3-成功，4-失败
-	CreatedAt     string `json:"created_at"`     // 创建时间
+	Code          string `json:"code"`           // 提现确认码
+}
+
+type WithdrawAddress struct {
+	ID          uint64 `json:"id"`           // 地址ID
+	Network     string `json:"network"`      // 网络
+	Address     string `json:"address"`      // 提现地址
+	Label       string `json:"label"`        // 地址备注
+	Available   bool   `json:"available"`    // 冷却期是否已结束
+	AvailableAt string `json:"available_at"` // 冷却期结束时间
+	CreatedAt   string `json:"created_at"`   // 添加时间
+}
+
+type WithdrawAddressListResponse struct {
+	WhitelistOnly  bool              `json:"whitelist_only"`  // 是否只允许提现到地址簿中的地址
+	WhitelistUntil string            `json:"whitelist_until"` // 关闭白名单的冷却截止时间，没有冷却时为空
+	Addresses      []WithdrawAddress `json:"addresses"`       // 地址簿，按添加时间倒序
+}
+
+type AddWithdrawAddressRequest struct {
+	Network string `json:"network"`        // 网络代码
+	Address string `json:"address"`        // 提现地址
+	Label   string `json:"label,optional"` // 地址备注
+}
+
+type DeleteWithdrawAddressRequest struct {
+	ID uint64 `path:"id"` // 地址ID
+}
+
+type WithdrawSettingsRequest struct {
+	WhitelistOnly bool   `json:"whitelist_only"`     // 是否只允许提现到地址簿中的地址
+	TotpCode      string `json:"totp_code,optional"` // 两步验证码，已启用两步验证的用户关闭白名单时必填
+}
+
+type WithdrawSettingsResponse struct {
+	WhitelistOnly  bool   `json:"whitelist_only"`  // 是否只允许提现到地址簿中的地址
+	WhitelistUntil string `json:"whitelist_until"` // 关闭白名单的冷却截止时间，冷却期内仍只能提现到地址簿中的地址，没有冷却时为空
+}
+
+type TransferRequest struct {
+	Currency         string `json:"currency"`                    // 币种代码
+	Amount           string `json:"amount"`                      // 转账金额
+	ToUserID         uint64 `json:"to_user_id,optional"`         // 收款用户ID，与收款邮箱二选一
+	ToEmail          string `json:"to_email,optional"`           // 收款用户邮箱，与收款用户ID二选一
+	ClientTransferID string `json:"client_transfer_id,optional"` // 客户端幂等键，相同幂等键重复提交时返回首次转账结果
+	Remark           string `json:"remark,optional"`             // 转账备注
+	TotpCode         string `json:"totp_code,optional"`          // 两步验证码，已启用两步验证时必填
+}
+
+type TransferResponse struct {
+	TransferID string `json:"transfer_id"`  // 转账ID
+	Currency   string `json:"currency"`     // 币种代码
+	Amount     string `json:"amount"`       // 转账金额
+	FromUserID uint64 `json:"from_user_id"` // 付款用户ID
+	ToUserID   uint64 `json:"to_user_id"`   // 收款用户ID
+	Status     int64  `json:"status"`       // 转账状态：2-成功
+	CreatedAt  string `json:"created_at"`   // 创建时间
 }
 
 type AssetHistoryRequest struct {
 	Currency string `json:"currency,omitempty"` // 币种代码（可选）
-	Type     int64  `json:"type,omitempty"`     // 记录类型：1-充值，2-提现（可选）
+	Type     int64  `json:"type,omitempty"`     // 记录类型：1-充值，2-提现，3-转账转出，4-转账转入，5-子账户划出，6-子账户划入（可选）
 	Page     int64  `json:"page,omitempty"`     // 页码，默认1
 	Size     int64  `json:"size,omitempty"`     // 每页大小，默认20
 }
@@ -213,7 +340,8 @@ type AssetHistoryRequest struct {
 type AssetTransaction struct {
 	ID            string `json:"id"`             // 交易记录ID
 	Currency      string `json:"currency"`       // 币种代码
-	Type          int64  `json:"type"`           // 记录类型：1-充值，2-提现
+	Network       string `json:"network"`        // 网络
+	Type          int64  `json:"type"`           // 记录类型：1-充值，2-提现，3-转账转出，4-转账转入，5-子账户划出，6-子账户划入
 	Amount        string `json:"amount"`         // 交易金额
 	Fee           string `json:"fee"`            // 手续费
 	Status        int64  `json:"status"`         // 交易状态
@@ -230,6 +358,138 @@ type AssetHistoryResponse struct {
 	Size         int64              `json:"size"`         // 每页大小
 }
 
+type AccountStatementRequest struct {
+	Currency string `form:"currency,optional"` // 币种代码（可选）
+	Page     int64  `form:"page,optional"`     // 页码，默认1
+	Size     int64  `form:"size,optional"`     // 每页大小，默认20，最大100
+}
+
+type LedgerEntry struct {
+	ID        uint64 `json:"id"`         // 分录ID
+	JournalID string `json:"journal_id"` // 记账凭证ID
+	Currency  string `json:"currency"`   // 币种代码
+	Account   int64  `json:"account"`    // 科目：1-可用余额，2-冻结余额
+	Direction int64  `json:"direction"`  // 方向：1-借方（减少），2-贷方（增加）
+	Amount    string `json:"amount"`     // 金额
+	BizType   int64  `json:"biz_type"`   // 业务类型：1-充值，2-提现，3-冻结，4-解冻，5-成交结算
+	BizID     string `json:"biz_id"`     // 业务ID
+	Remark    string `json:"remark"`     // 备注
+	CreatedAt string `json:"created_at"` // 记账时间
+}
+
+type AccountStatementResponse struct {
+	Entries []LedgerEntry `json:"entries"` // 分录列表
+	Total   int64         `json:"total"`   // 总数量
+	Page    int64         `json:"page"`    // 当前页码
+	Size    int64         `json:"size"`    // 每页大小
+}
+
+type DepositAddressRequest struct {
+	Currency string `form:"currency"`         // 币种代码
+	Network  string `form:"network,optional"` // 充值网络；币种只支持一个网络时可为空
+}
+
+type DepositAddressResponse struct {
+	Currency      string `json:"currency"`      // 币种代码
+	Network       string `json:"network"`       // 网络
+	Address       string `json:"address"`       // 充值地址
+	MinDeposit    string `json:"min_deposit"`   // 最小充值金额，低于该金额的转账不入账
+	Confirmations int64  `json:"confirmations"` // 入账所需确认数
+}
+
+type CreateSubAccountRequest struct {
+	Email    string `json:"email"`             // 子账户登录邮箱
+	Password string `json:"password"`          // 子账户登录密码
+	Nickname string `json:"nickname,optional"` // 子账户昵称，如策略名称
+}
+
+type SubAccount struct {
+	ID        uint64 `json:"id"`         // 子账户用户ID
+	Email     string `json:"email"`      // 子账户登录邮箱
+	Nickname  string `json:"nickname"`   // 子账户昵称
+	Status    int64  `json:"status"`     // 状态：1-正常，2-已禁用，3-已删除
+	CreatedAt string `json:"created_at"` // 创建时间
+}
+
+type SubAccountListResponse struct {
+	SubAccounts []SubAccount `json:"sub_accounts"` // 子账户列表，按创建顺序排列
+}
+
+type UpdateSubAccountStatusRequest struct {
+	ID     uint64 `path:"id"`     // 子账户用户ID
+	Status int64  `json:"status"` // 目标状态：1-正常，2-禁用（可恢复），3-删除（不可恢复）
+}
+
+type FreezeSubAccountRequest struct {
+	ID               uint64 `path:"id"`                          // 子账户用户ID
+	Reason           string `json:"reason"`                      // 冻结原因
+	CancelOpenOrders bool   `json:"cancel_open_orders,optional"` // 是否撤销子账户的全部挂单
+}
+
+type UnfreezeSubAccountRequest struct {
+	ID uint64 `path:"id"` // 子账户用户ID
+}
+
+type SubAccountTransferRequest struct {
+	SubUserID uint64 `json:"sub_user_id"`     // 子账户用户ID
+	Direction int64  `json:"direction"`       // 划转方向：1-母账户转入子账户，2-子账户转回母账户
+	Currency  string `json:"currency"`        // 币种代码
+	Amount    string `json:"amount"`          // 划转金额
+	Remark    string `json:"remark,optional"` // 划转备注
+}
+
+type AccountBalances struct {
+	UserID   uint64    `json:"user_id"`   // 用户ID
+	Email    string    `json:"email"`     // 登录邮箱
+	IsMaster bool      `json:"is_master"` // 是否为母账户
+	Balances []Balance `json:"balances"`  // 各币种余额
+}
+
+type SubAccountBalancesResponse struct {
+	Totals   []Balance         `json:"totals"`   // 母账户和全部子账户按币种汇总的余额
+	Accounts []AccountBalances `json:"accounts"` // 各账户的余额明细，母账户在前
+}
+
+type SubAccountOrdersRequest struct {
+	SubUserID uint64 `form:"sub_user_id,optional"` // 只查询指定子账户（可选），为空时查询母账户和全部子账户
+	Symbol    string `form:"symbol,optional"`      // 交易对符号（可选）
+	Status    int64  `form:"status,optional"`      // 订单状态（可选）
+	Page      int64  `form:"page,optional"`        // 页码，默认1
+	Size      int64  `form:"size,optional"`        // 每页大小，默认20
+}
+
+type CreateApiKeyRequest struct {
+	Label       string   `json:"label,optional"`        // 备注
+	Permissions []string `json:"permissions"`           // 权限：read-查询，trade-交易，withdraw-提现和转账
+	IPWhitelist []string `json:"ip_whitelist,optional"` // IP白名单，支持IP或CIDR，为空表示不限制
+	ExpiresIn   int64    `json:"expires_in,optional"`   // 有效期（秒），为0表示永不过期
+	TotpCode    string   `json:"totp_code,optional"`    // 两步验证码，已启用两步验证时必填
+}
+
+type ApiKey struct {
+	ID          uint64   `json:"id"`           // 主键
+	KeyID       string   `json:"key_id"`       // 密钥ID，请求时放在X-API-KEY请求头中
+	Label       string   `json:"label"`        // 备注
+	Permissions []string `json:"permissions"`  // 权限列表
+	IPWhitelist []string `json:"ip_whitelist"` // IP白名单
+	ExpiresAt   string   `json:"expires_at"`   // 过期时间，为空表示永不过期
+	LastUsedAt  string   `json:"last_used_at"` // 最近一次使用时间
+	CreatedAt   string   `json:"created_at"`   // 创建时间
+}
+
+type CreateApiKeyResponse struct {
+	ApiKey ApiKey `json:"api_key"` // 密钥信息
+	Secret string `json:"secret"`  // 明文密钥，仅在创建时返回一次
+}
+
+type ApiKeyListResponse struct {
+	ApiKeys []ApiKey `json:"api_keys"` // 有效的API密钥，按创建时间倒序
+}
+
+type DeleteApiKeyRequest struct {
+	ID uint64 `path:"id"` // 主键
+}
+
 type CreateTradingPairRequest struct {
 	Symbol        string `json:"symbol" validate:"required"`         // 交易对符号，如BTC/USDT
 	BaseCurrency  string `json:"base_currency" validate:"required"`  // 基础币种
@@ -241,11 +501,17 @@ type CreateTradingPairRequest struct {
 }
 
 type UpdateTradingPairRequest struct {
-	MinAmount   string `json:"min_amount,omitempty"`   // 最小交易数量
-	MaxAmount   string `json:"max_amount,omitempty"`   // 最大交易数量
-	PriceScale  int64  `json:"price_scale,omitempty"`  // 价格精度
-	AmountScale int64  `json:"amount_scale,omitempty"` // 数量精度
-	Status      int64  `json:"status,omitempty"`       // 状态：1-正常，2-禁用
+	Symbol               string `path:"symbol"`                          // 交易对符号
+	MinAmount            string `json:"min_amount,optional"`             // 最小交易数量
+	MaxAmount            string `json:"max_amount,optional"`             // 最大交易数量
+	PriceScale           int64  `json:"price_scale,optional"`            // 价格精度
+	AmountScale          int64  `json:"amount_scale,optional"`           // 数量精度
+	TickSize             string `json:"tick_size,optional"`              // 价格最小变动单位，0表示取消限制
+	StepSize             string `json:"step_size,optional"`              // 数量最小变动单位，0表示取消限制
+	MinNotional          string `json:"min_notional,optional"`           // 最小下单金额，0表示取消限制
+	MaxOpenOrders        int64  `json:"max_open_orders,optional"`        // 单用户最大挂单数量，-1表示取消限制
+	MinVerificationLevel int64  `json:"min_verification_level,optional"` // 下单所需最低认证等级，-1表示取消限制
+	Status               int64  `json:"status,optional"`                 // 状态：1-正常交易，2-暂停交易，3-预上线，4-仅可撤单，5-仅挂单，6-已下架
 }
 
 type TradingPairStatsResponse struct {
@@ -255,3 +521,445 @@ type TradingPairStatsResponse struct {
 	SupportedBaseCurrencies  []string `json:"supported_base_currencies"`  // 支持的基础币种
 	SupportedQuoteCurrencies []string `json:"supported_quote_currencies"` // 支持的计价币种
 }
+
+type ChangeTradingPairStatusRequest struct {
+	Symbol      string `path:"symbol"`                     // 交易对符号
+	Status      int64  `json:"status" validate:"required"` // 目标状态：1-正常交易，2-暂停交易，3-预上线，4-仅可撤单，5-仅挂单，6-已下架
+	EffectiveAt int64  `json:"effective_at,optional"`      // 生效时间（Unix秒），为空或早于当前时间时立即生效
+	Reason      string `json:"reason,optional"`            // 变更原因
+}
+
+type TradingPairStatusChange struct {
+	ID          uint64 `json:"id"`           // 记录ID
+	Symbol      string `json:"symbol"`       // 交易对符号
+	FromStatus  int64  `json:"from_status"`  // 变更前状态
+	ToStatus    int64  `json:"to_status"`    // 目标状态
+	EffectiveAt string `json:"effective_at"` // 计划生效时间
+	State       int64  `json:"state"`        // 执行状态：1-待执行，2-已执行，3-已取消，4-执行失败
+	OperatorID  uint64 `json:"operator_id"`  // 操作人用户ID
+	Reason      string `json:"reason"`       // 变更原因
+	Remark      string `json:"remark"`       // 执行备注
+	CreatedAt   string `json:"created_at"`   // 创建时间
+	ExecutedAt  string `json:"executed_at"`  // 实际执行时间
+}
+
+type TradingPairStatusChangeListRequest struct {
+	Symbol string `path:"symbol"`        // 交易对符号
+	Page   int64  `form:"page,optional"` // 页码，默认1
+	Size   int64  `form:"size,optional"` // 每页大小，默认20
+}
+
+type TradingPairStatusChangeListResponse struct {
+	Changes []TradingPairStatusChange `json:"changes"` // 状态变更记录列表
+	Total   int64                     `json:"total"`   // 总数量
+	Page    int64                     `json:"page"`    // 当前页码
+	Size    int64                     `json:"size"`    // 每页大小
+}
+
+type CancelTradingPairStatusChangeRequest struct {
+	ID uint64 `path:"id"` // 状态变更记录ID
+}
+
+type Currency struct {
+	Code            string            `json:"code"`             // 币种代码
+	Name            string            `json:"name"`             // 币种名称
+	Precision       int64             `json:"precision"`        // 金额精度
+	DepositEnabled  bool              `json:"deposit_enabled"`  // 是否开放充值（总开关）
+	WithdrawEnabled bool              `json:"withdraw_enabled"` // 是否开放提现（总开关）
+	MaxDeposit      string            `json:"max_deposit"`      // 单笔最大充值金额，0表示不限制
+	MaxWithdraw     string            `json:"max_withdraw"`     // 单笔最大提现金额，0表示不限制
+	Networks        []CurrencyNetwork `json:"networks"`         // 支持的网络
+	Status          int64             `json:"status"`           // 状态：1-启用，2-停用
+	CreatedAt       string            `json:"created_at"`       // 创建时间
+	UpdatedAt       string            `json:"updated_at"`       // 更新时间
+}
+
+type CurrencyNetwork struct {
+	Currency         string `json:"currency"`           // 币种代码
+	Network          string `json:"network"`            // 网络代码，如ERC20
+	AddressType      string `json:"address_type"`       // 地址格式：bitcoin、evm、tron
+	DepositEnabled   bool   `json:"deposit_enabled"`    // 是否开放充值
+	WithdrawEnabled  bool   `json:"withdraw_enabled"`   // 是否开放提现
+	WithdrawFeeFixed string `json:"withdraw_fee_fixed"` // 提现固定手续费
+	WithdrawFeeRate  string `json:"withdraw_fee_rate"`  // 提现比例手续费
+	MinDeposit       string `json:"min_deposit"`        // 最小充值金额
+	MinWithdraw      string `json:"min_withdraw"`       // 最小提现金额
+	Confirmations    int64  `json:"confirmations"`      // 充值所需区块确认数
+	CreatedAt        string `json:"created_at"`         // 创建时间
+	UpdatedAt        string `json:"updated_at"`         // 更新时间
+}
+
+type CurrencyListResponse struct {
+	Currencies []Currency `json:"currencies"` // 币种列表
+}
+
+type CreateCurrencyRequest struct {
+	Code            string `json:"code"`                  // 币种代码，2-10位大写字母
+	Name            string `json:"name"`                  // 币种名称
+	Precision       int64  `json:"precision"`             // 金额精度，0-18
+	DepositEnabled  bool   `json:"deposit_enabled"`       // 是否开放充值
+	WithdrawEnabled bool   `json:"withdraw_enabled"`      // 是否开放提现
+	MaxDeposit      string `json:"max_deposit,optional"`  // 单笔最大充值金额，默认0表示不限制
+	MaxWithdraw     string `json:"max_withdraw,optional"` // 单笔最大提现金额，默认0表示不限制
+	Status          int64  `json:"status,optional"`       // 状态：1-启用，2-停用，默认1
+}
+
+type UpdateCurrencyRequest struct {
+	Code            string `path:"code"`                  // 币种代码
+	Name            string `json:"name"`                  // 币种名称
+	Precision       int64  `json:"precision"`             // 金额精度，0-18
+	DepositEnabled  bool   `json:"deposit_enabled"`       // 是否开放充值
+	WithdrawEnabled bool   `json:"withdraw_enabled"`      // 是否开放提现
+	MaxDeposit      string `json:"max_deposit,optional"`  // 单笔最大充值金额，默认0表示不限制
+	MaxWithdraw     string `json:"max_withdraw,optional"` // 单笔最大提现金额，默认0表示不限制
+	Status          int64  `json:"status,optional"`       // 状态：1-启用，2-停用，为空时保持不变
+}
+
+type DeleteCurrencyRequest struct {
+	Code string `path:"code"` // 币种代码
+}
+
+type CreateCurrencyNetworkRequest struct {
+	Code             string `path:"code"`                        // 币种代码
+	Network          string `json:"network"`                     // 网络代码，2-20位大写字母或数字
+	AddressType      string `json:"address_type"`                // 地址格式：bitcoin、evm、tron
+	DepositEnabled   bool   `json:"deposit_enabled"`             // 是否开放充值
+	WithdrawEnabled  bool   `json:"withdraw_enabled"`            // 是否开放提现
+	WithdrawFeeFixed string `json:"withdraw_fee_fixed,optional"` // 提现固定手续费，默认0
+	WithdrawFeeRate  string `json:"withdraw_fee_rate,optional"`  // 提现比例手续费，默认0
+	MinDeposit       string `json:"min_deposit,optional"`        // 最小充值金额，默认0
+	MinWithdraw      string `json:"min_withdraw,optional"`       // 最小提现金额，默认0
+	Confirmations    int64  `json:"confirmations,optional"`      // 充值所需区块确认数
+}
+
+type UpdateCurrencyNetworkRequest struct {
+	Code             string `path:"code"`                        // 币种代码
+	Network          string `path:"network"`                     // 网络代码
+	AddressType      string `json:"address_type"`                // 地址格式：bitcoin、evm、tron
+	DepositEnabled   bool   `json:"deposit_enabled"`             // 是否开放充值
+	WithdrawEnabled  bool   `json:"withdraw_enabled"`            // 是否开放提现
+	WithdrawFeeFixed string `json:"withdraw_fee_fixed,optional"` // 提现固定手续费，默认0
+	WithdrawFeeRate  string `json:"withdraw_fee_rate,optional"`  // 提现比例手续费，默认0
+	MinDeposit       string `json:"min_deposit,optional"`        // 最小充值金额，默认0
+	MinWithdraw      string `json:"min_withdraw,optional"`       // 最小提现金额，默认0
+	Confirmations    int64  `json:"confirmations,optional"`      // 充值所需区块确认数
+}
+
+type DeleteCurrencyNetworkRequest struct {
+	Code    string `path:"code"`    // 币种代码
+	Network string `path:"network"` // 网络代码
+}
+
+type Withdrawal struct {
+	TransactionID string `json:"transaction_id"` // 交易ID
+	UserID        uint64 `json:"user_id"`        // 用户ID
+	Currency      string `json:"currency"`       // 币种代码
+	Network       string `json:"network"`        // 提现网络
+	Amount        string `json:"amount"`         // 提现金额
+	Fee           string `json:"fee"`            // 手续费
+	Address       string `json:"address"`        // 提现地址
+	Status        int64  `json:"status"`         // 状态：1-待审核，2-已确认，3-失败，5-已审核，6-广播中，7-已拒绝
+	StatusText    string `json:"status_text"`    // 状态描述
+	TxHash        string `json:"tx_hash"`        // 区块链交易哈希
+	Remark        string `json:"remark"`         // 备注
+	CreatedAt     string `json:"created_at"`     // 创建时间
+	UpdatedAt     string `json:"updated_at"`     // 更新时间
+}
+
+type WithdrawalListRequest struct {
+	Status int64 `form:"status,optional"` // 状态，默认1-待审核
+	Page   int64 `form:"page,optional"`   // 页码，默认1
+	Size   int64 `form:"size,optional"`   // 每页大小，默认20
+}
+
+type WithdrawalListResponse struct {
+	Withdrawals []Withdrawal `json:"withdrawals"` // 提现列表
+	Total       int64        `json:"total"`       // 总数量
+	Page        int64        `json:"page"`        // 当前页码
+	Size        int64        `json:"size"`        // 每页大小
+}
+
+type ReviewWithdrawalRequest struct {
+	TransactionID string `path:"transaction_id"`  // 交易ID
+	Remark        string `json:"remark,optional"` // 审核备注，拒绝时为拒绝原因（必填）
+}
+
+type WithdrawalAuditLog struct {
+	FromStatus int64  `json:"from_status"` // 变更前状态，创建时为0
+	ToStatus   int64  `json:"to_status"`   // 变更后状态
+	OperatorID uint64 `json:"operator_id"` // 操作人用户ID，系统任务为0
+	TxHash     string `json:"tx_hash"`     // 区块链交易哈希
+	Remark     string `json:"remark"`      // 备注
+	CreatedAt  string `json:"created_at"`  // 记录时间
+}
+
+type WithdrawalAuditLogRequest struct {
+	TransactionID string `path:"transaction_id"` // 交易ID
+}
+
+type WithdrawalAuditLogResponse struct {
+	Withdrawal Withdrawal           `json:"withdrawal"` // 提现信息
+	Logs       []WithdrawalAuditLog `json:"logs"`       // 状态流转记录，按时间先后排序
+}
+
+type ImportDepositAddressesRequest struct {
+	Network   string   `json:"network"`   // 网络代码
+	Addresses []string `json:"addresses"` // 预生成的充值地址，需符合网络的地址格式
+}
+
+type ImportDepositAddressesResponse struct {
+	Network       string `json:"network"`        // 网络代码
+	Imported      int64  `json:"imported"`       // 本次导入数量
+	PoolAvailable int64  `json:"pool_available"` // 地址池中未分配的地址数量
+}
+
+type UserRolesRequest struct {
+	UserID uint64 `path:"id"` // 用户ID
+}
+
+type UserRolesResponse struct {
+	UserID      uint64   `json:"user_id"`     // 用户ID
+	Roles       []string `json:"roles"`       // 角色列表
+	Permissions []string `json:"permissions"` // 角色对应的全部权限
+}
+
+type GrantRoleRequest struct {
+	UserID uint64 `path:"id"`              // 用户ID
+	Role   string `json:"role"`            // 角色：super_admin-超级管理员，operator-运营，finance-财务，compliance-合规，auditor-审计
+	Reason string `json:"reason,optional"` // 变更原因
+}
+
+type RevokeRoleRequest struct {
+	UserID uint64 `path:"id"`              // 用户ID
+	Role   string `path:"role"`            // 角色
+	Reason string `form:"reason,optional"` // 变更原因
+}
+
+type RoleAuditLog struct {
+	ID         uint64 `json:"id"`          // 记录ID
+	UserID     uint64 `json:"user_id"`     // 被变更角色的用户ID
+	Role       string `json:"role"`        // 角色
+	Action     int64  `json:"action"`      // 操作：1-授予，2-撤销
+	OperatorID uint64 `json:"operator_id"` // 操作人用户ID，命令行操作时为0
+	Reason     string `json:"reason"`      // 变更原因
+	CreatedAt  string `json:"created_at"`  // 记录时间
+}
+
+type RoleAuditLogRequest struct {
+	UserID uint64 `form:"user_id,optional"` // 用户ID（可选），为空时查询全部用户
+	Page   int64  `form:"page,optional"`    // 页码，默认1
+	Size   int64  `form:"size,optional"`    // 每页大小，默认20
+}
+
+type RoleAuditLogListResponse struct {
+	Logs  []RoleAuditLog `json:"logs"`  // 变更记录，按时间倒序
+	Total int64          `json:"total"` // 总数量
+	Page  int64          `json:"page"`  // 当前页码
+	Size  int64          `json:"size"`  // 每页大小
+}
+
+type SubmitKycRequest struct {
+	Level          int64  `form:"level"`           // 申请的认证等级：1-基础认证，2-高级认证，只能申请比当前高一级的等级
+	FullName       string `form:"full_name"`       // 证件上的姓名
+	Country        string `form:"country"`         // 国家或地区代码（ISO 3166-1 alpha-2）
+	DocumentType   string `form:"document_type"`   // 证件类型：id_card-身份证，passport-护照，driver_license-驾驶证
+	DocumentNumber string `form:"document_number"` // 证件号码
+}
+
+type KycDocument struct {
+	Index       int64  `json:"index"`        // 文件序号，从0开始
+	FileName    string `json:"file_name"`    // 上传时的文件名
+	ContentType string `json:"content_type"` // 文件类型
+	Size        int64  `json:"size"`         // 文件大小（字节）
+}
+
+type KycSubmission struct {
+	ID             uint64        `json:"id"`              // 申请ID
+	UserID         uint64        `json:"user_id"`         // 用户ID
+	Level          int64         `json:"level"`           // 申请的认证等级
+	FullName       string        `json:"full_name"`       // 证件上的姓名
+	Country        string        `json:"country"`         // 国家或地区代码
+	DocumentType   string        `json:"document_type"`   // 证件类型
+	DocumentNumber string        `json:"document_number"` // 证件号码，用户查询时只显示末4位
+	Documents      []KycDocument `json:"documents"`       // 证件文件
+	Status         int64         `json:"status"`          // 状态：1-待审核，2-已通过，3-已拒绝
+	ReviewerID     uint64        `json:"reviewer_id"`     // 审核人用户ID，未审核时为0
+	ReviewReason   string        `json:"review_reason"`   // 审核意见
+	ReviewedAt     string        `json:"reviewed_at"`     // 审核时间，未审核时为空
+	CreatedAt      string        `json:"created_at"`      // 提交时间
+}
+
+type KycStatusResponse struct {
+	Level      int64          `json:"level"`                // 当前认证等级：0-未认证，1-基础认证，2-高级认证
+	Submission *KycSubmission `json:"submission,omitempty"` // 最近一次提交的申请，从未提交时为空
+}
+
+type KycSubmissionListRequest struct {
+	Status int64 `form:"status,optional"` // 状态（可选），默认1-待审核
+	Page   int64 `form:"page,optional"`   // 页码，默认1
+	Size   int64 `form:"size,optional"`   // 每页大小，默认20
+}
+
+type KycSubmissionListResponse struct {
+	Submissions []KycSubmission `json:"submissions"` // 申请列表，按提交时间先后排序
+	Total       int64           `json:"total"`       // 总数量
+	Page        int64           `json:"page"`        // 当前页码
+	Size        int64           `json:"size"`        // 每页大小
+}
+
+type KycSubmissionRequest struct {
+	ID uint64 `path:"id"` // 申请ID
+}
+
+type KycDocumentRequest struct {
+	ID    uint64 `path:"id"`    // 申请ID
+	Index int64  `path:"index"` // 文件序号
+}
+
+type KycDocumentResponse struct {
+	FileName    string `json:"file_name"`    // 上传时的文件名
+	ContentType string `json:"content_type"` // 文件类型
+	Content     string `json:"content"`      // 文件内容（base64编码）
+}
+
+type ReviewKycRequest struct {
+	ID     uint64 `path:"id"`              // 申请ID
+	Reason string `json:"reason,optional"` // 审核意见，拒绝时必填
+}
+
+type SetVerificationLevelRequest struct {
+	UserID uint64 `path:"id"`     // 用户ID
+	Level  int64  `json:"level"`  // 新的认证等级：0-未认证，1-基础认证，2-高级认证
+	Reason string `json:"reason"` // 调整原因
+}
+
+type VerificationLevelChange struct {
+	ID           uint64 `json:"id"`            // 记录ID
+	UserID       uint64 `json:"user_id"`       // 用户ID
+	FromLevel    int64  `json:"from_level"`    // 变更前等级
+	ToLevel      int64  `json:"to_level"`      // 变更后等级
+	SubmissionID uint64 `json:"submission_id"` // 审核通过的认证申请ID，管理员直接调整时为0
+	ReviewerID   uint64 `json:"reviewer_id"`   // 审核人或操作人用户ID
+	Reason       string `json:"reason"`        // 变更原因
+	CreatedAt    string `json:"created_at"`    // 记录时间
+}
+
+type VerificationLevelChangesRequest struct {
+	UserID uint64 `path:"id"` // 用户ID
+}
+
+type VerificationLevelChangeListResponse struct {
+	UserID  uint64                    `json:"user_id"` // 用户ID
+	Level   int64                     `json:"level"`   // 当前认证等级
+	Changes []VerificationLevelChange `json:"changes"` // 变更记录，按时间倒序
+}
+
+type UserRestriction struct {
+	Type      string `json:"type"`       // 限制类型：login-禁止登录，trading-禁止下单，withdraw-禁止提现和转出，freeze-冻结账户（包含全部限制）
+	Reason    string `json:"reason"`     // 限制原因
+	ExpiresAt string `json:"expires_at"` // 到期时间，为空表示直到解除
+	Active    bool   `json:"active"`     // 是否生效，过期后不再生效
+	CreatedBy uint64 `json:"created_by"` // 最后施加或更新限制的操作人用户ID
+	CreatedAt string `json:"created_at"` // 施加时间
+	UpdatedAt string `json:"updated_at"` // 最后更新时间
+}
+
+type UserRestrictionsRequest struct {
+	UserID uint64 `path:"id"` // 用户ID
+}
+
+type UserRestrictionsResponse struct {
+	UserID         uint64            `json:"user_id"`         // 用户ID
+	Restrictions   []UserRestriction `json:"restrictions"`    // 限制列表，包括已过期未解除的
+	CanceledOrders int64             `json:"canceled_orders"` // 本次操作撤销的挂单数量
+}
+
+type ApplyRestrictionRequest struct {
+	UserID           uint64 `path:"id"`                          // 用户ID
+	Type             string `json:"type"`                        // 限制类型：login、trading、withdraw、freeze
+	Reason           string `json:"reason"`                      // 限制原因
+	ExpiresAt        int64  `json:"expires_at,optional"`         // 到期时间（Unix秒），为空时直到解除
+	CancelOpenOrders bool   `json:"cancel_open_orders,optional"` // 是否撤销用户的全部挂单，仅冻结账户和禁止交易时有效
+}
+
+type LiftRestrictionRequest struct {
+	UserID uint64 `path:"id"`              // 用户ID
+	Type   string `path:"type"`            // 限制类型
+	Reason string `form:"reason,optional"` // 解除原因
+}
+
+type RestrictionAuditLog struct {
+	ID         uint64 `json:"id"`          // 记录ID
+	UserID     uint64 `json:"user_id"`     // 被限制的用户ID
+	Type       string `json:"type"`        // 限制类型
+	Action     int64  `json:"action"`      // 操作：1-施加或更新，2-解除
+	Reason     string `json:"reason"`      // 变更原因
+	ExpiresAt  string `json:"expires_at"`  // 施加时设置的到期时间，为空表示直到解除
+	OperatorID uint64 `json:"operator_id"` // 操作人用户ID
+	CreatedAt  string `json:"created_at"`  // 记录时间
+}
+
+type RestrictionAuditLogRequest struct {
+	UserID uint64 `form:"user_id,optional"` // 用户ID（可选），为空时查询全部用户
+	Page   int64  `form:"page,optional"`    // 页码，默认1
+	Size   int64  `form:"size,optional"`    // 每页大小，默认20
+}
+
+type RestrictionAuditLogListResponse struct {
+	Logs  []RestrictionAuditLog `json:"logs"`  // 变更记录，按时间倒序
+	Total int64                 `json:"total"` // 总数量
+	Page  int64                 `json:"page"`  // 当前页码
+	Size  int64                 `json:"size"`  // 每页大小
+}
+
+type ReserveRoot struct {
+	SnapshotID       uint64 `json:"snapshot_id"`       // 快照ID
+	Currency         string `json:"currency"`          // 币种代码
+	RootHash         string `json:"root_hash"`         // Merkle求和树根哈希
+	TotalLiabilities string `json:"total_liabilities"` // 用户负债总额
+	LeafCount        int64  `json:"leaf_count"`        // 叶子数量
+}
+
+type ReserveRootsResponse struct {
+	BatchID   string        `json:"batch_id"`   // 快照批次ID
+	CreatedAt string        `json:"created_at"` // 快照时间
+	Roots     []ReserveRoot `json:"roots"`      // 各币种的根
+}
+
+type ReserveProofRequest struct {
+	Currency string `form:"currency"` // 币种代码
+}
+
+type ReserveProofNode struct {
+	Hash     string `json:"hash"`     // 兄弟节点哈希
+	Sum      string `json:"sum"`      // 兄弟节点余额之和
+	Position string `json:"position"` // 兄弟节点位置：left/right
+}
+
+type ReserveProofResponse struct {
+	SnapshotID       uint64             `json:"snapshot_id"`       // 快照ID
+	Currency         string             `json:"currency"`          // 币种代码
+	RootHash         string             `json:"root_hash"`         // 公布的根哈希
+	TotalLiabilities string             `json:"total_liabilities"` // 公布的用户负债总额
+	LeafIndex        int64              `json:"leaf_index"`        // 叶子序号
+	UserHash         string             `json:"user_hash"`         // 加盐哈希后的用户ID
+	Salt             string             `json:"salt"`              // 用户专属盐值
+	Balance          string             `json:"balance"`           // 快照时的用户余额
+	Path             []ReserveProofNode `json:"path"`              // 从叶子到根的兄弟节点
+	CreatedAt        string             `json:"created_at"`        // 快照时间
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret          string   `json:"secret"`           // Base32编码的TOTP密钥，用于手动录入
+	ProvisioningURI string   `json:"provisioning_uri"` // otpauth://链接，用于生成二维码
+	RecoveryCodes   []string `json:"recovery_codes"`   // 恢复码，仅在注册时返回一次
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"` // 验证码，关闭两步验证时也可使用恢复码
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled                bool  `json:"enabled"`                  // 是否已启用
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"` // 剩余恢复码数量
+}
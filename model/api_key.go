@@ -0,0 +1,201 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ ApiKeyModel = (*customApiKeyModel)(nil)
+
+// API密钥状态 / API Key Status
+const (
+	ApiKeyStatusActive  int64 = 1 // 有效
+	ApiKeyStatusRevoked int64 = 2 // 已删除（吊销）
+)
+
+// API密钥权限 / API Key Permissions
+const (
+	ApiKeyPermissionRead     = "read"     // 查询账户、余额、订单等
+	ApiKeyPermissionTrade    = "trade"    // 下单、撤单
+	ApiKeyPermissionWithdraw = "withdraw" // 提现、转账等资金操作
+)
+
+type (
+	// ApiKeyModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customApiKeyModel.
+	ApiKeyModel interface {
+		apiKeyModel
+		// 自定义方法
+		FindOneByKeyID(ctx context.Context, keyID string) (*ApiKey, error)
+		FindByUserID(ctx context.Context, userID uint64) ([]*ApiKey, error)
+		CountActiveByUserID(ctx context.Context, userID uint64) (int64, error)
+		RevokeByUser(ctx context.Context, userID, id uint64) error
+		RevokeAllByUserID(ctx context.Context, userID uint64) (int64, error)
+		UpdateLastUsed(ctx context.Context, id uint64, lastUsedAt time.Time) error
+	}
+
+	customApiKeyModel struct {
+		*defaultApiKeyModel
+	}
+
+	// ApiKey 用户API密钥，用于程序化交易
+	// 密钥加密保存，明文密钥仅在创建时返回一次
+	ApiKey struct {
+		ID              uint64       `db:"id"`               // 主键
+		UserID          uint64       `db:"user_id"`          // 所属用户ID
+		KeyID           string       `db:"key_id"`           // 公开的密钥ID，请求时放在请求头中
+		SecretEncrypted string       `db:"secret_encrypted"` // 加密后的密钥，校验签名时解密
+		Label           string       `db:"label"`            // 备注
+		Permissions     string       `db:"permissions"`      // 权限列表，逗号分隔：read,trade,withdraw
+		IPWhitelist     string       `db:"ip_whitelist"`     // IP白名单，逗号分隔的IP或CIDR，为空表示不限制
+		Status          int64        `db:"status"`           // 状态：1-有效，2-已删除
+		ExpiresAt       sql.NullTime `db:"expires_at"`       // 过期时间，为空表示永不过期
+		LastUsedAt      sql.NullTime `db:"last_used_at"`     // 最近一次使用时间
+		CreatedAt       time.Time    `db:"created_at"`       // 创建时间
+		UpdatedAt       time.Time    `db:"updated_at"`       // 更新时间
+	}
+
+	apiKeyModel interface {
+		Insert(ctx context.Context, data *ApiKey) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*ApiKey, error)
+	}
+
+	defaultApiKeyModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// HasPermission 判断密钥是否具有指定权限
+func (k *ApiKey) HasPermission(permission string) bool {
+	for _, p := range k.PermissionList() {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionList 返回权限列表
+func (k *ApiKey) PermissionList() []string {
+	return splitList(k.Permissions)
+}
+
+// IPWhitelistEntries 返回IP白名单条目
+func (k *ApiKey) IPWhitelistEntries() []string {
+	return splitList(k.IPWhitelist)
+}
+
+// splitList 拆分逗号分隔的列表，忽略空白条目
+func splitList(value string) []string {
+	var resp []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			resp = append(resp, item)
+		}
+	}
+	return resp
+}
+
+// NewApiKeyModel returns a model for the database table.
+func NewApiKeyModel(conn sqlx.SqlConn) ApiKeyModel {
+	return &customApiKeyModel{
+		defaultApiKeyModel: newApiKeyModel(conn),
+	}
+}
+
+func newApiKeyModel(conn sqlx.SqlConn) *defaultApiKeyModel {
+	return &defaultApiKeyModel{
+		conn:  conn,
+		table: "api_keys",
+	}
+}
+
+func (m *defaultApiKeyModel) Insert(ctx context.Context, data *ApiKey) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (user_id, key_id, secret_encrypted, label, permissions, ip_whitelist, status, expires_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.UserID, data.KeyID, data.SecretEncrypted, data.Label, data.Permissions, data.IPWhitelist, data.Status, data.ExpiresAt, data.CreatedAt, data.UpdatedAt)
+	return ret, err
+}
+
+func (m *defaultApiKeyModel) FindOne(ctx context.Context, id uint64) (*ApiKey, error) {
+	query := `SELECT id, user_id, key_id, secret_encrypted, label, permissions, ip_whitelist, status, expires_at, last_used_at, created_at, updated_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp ApiKey
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindOneByKeyID 按公开的密钥ID查询
+func (m *customApiKeyModel) FindOneByKeyID(ctx context.Context, keyID string) (*ApiKey, error) {
+	query := `SELECT id, user_id, key_id, secret_encrypted, label, permissions, ip_whitelist, status, expires_at, last_used_at, created_at, updated_at FROM ` + m.table + ` WHERE key_id = $1 LIMIT 1`
+	var resp ApiKey
+	err := m.conn.QueryRowCtx(ctx, &resp, query, keyID)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindByUserID 查询用户的有效API密钥，按创建时间倒序
+func (m *customApiKeyModel) FindByUserID(ctx context.Context, userID uint64) ([]*ApiKey, error) {
+	query := `SELECT id, user_id, key_id, secret_encrypted, label, permissions, ip_whitelist, status, expires_at, last_used_at, created_at, updated_at FROM ` + m.table + ` WHERE user_id = $1 AND status = $2 ORDER BY created_at DESC, id DESC`
+	var resp []*ApiKey
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, userID, ApiKeyStatusActive)
+	return resp, err
+}
+
+// CountActiveByUserID 统计用户的有效API密钥数量
+func (m *customApiKeyModel) CountActiveByUserID(ctx context.Context, userID uint64) (int64, error) {
+	query := `SELECT COUNT(*) FROM ` + m.table + ` WHERE user_id = $1 AND status = $2`
+	var count int64
+	err := m.conn.QueryRowCtx(ctx, &count, query, userID, ApiKeyStatusActive)
+	return count, err
+}
+
+// RevokeByUser 删除（吊销）用户的API密钥，密钥不属于该用户或已删除时返回ErrNotFound
+func (m *customApiKeyModel) RevokeByUser(ctx context.Context, userID, id uint64) error {
+	query := `UPDATE ` + m.table + ` SET status = $1, updated_at = $2 WHERE id = $3 AND user_id = $4 AND status = $5`
+	result, err := m.conn.ExecCtx(ctx, query, ApiKeyStatusRevoked, time.Now(), id, userID, ApiKeyStatusActive)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RevokeAllByUserID 吊销用户的全部有效API密钥，返回吊销的数量
+func (m *customApiKeyModel) RevokeAllByUserID(ctx context.Context, userID uint64) (int64, error) {
+	query := `UPDATE ` + m.table + ` SET status = $1, updated_at = $2 WHERE user_id = $3 AND status = $4`
+	result, err := m.conn.ExecCtx(ctx, query, ApiKeyStatusRevoked, time.Now(), userID, ApiKeyStatusActive)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// UpdateLastUsed 记录最近一次使用时间
+func (m *customApiKeyModel) UpdateLastUsed(ctx context.Context, id uint64, lastUsedAt time.Time) error {
+	query := `UPDATE ` + m.table + ` SET last_used_at = $1 WHERE id = $2`
+	_, err := m.conn.ExecCtx(ctx, query, lastUsedAt, id)
+	return err
+}
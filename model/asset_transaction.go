@@ -10,6 +10,30 @@ import (
 
 var _ AssetTransactionModel = (*customAssetTransactionModel)(nil)
 
+// 资产交易类型 / Asset Transaction Type
+const (
+	AssetTransactionTypeDeposit       int64 = 1 // 充值
+	AssetTransactionTypeWithdraw      int64 = 2 // 提现
+	AssetTransactionTypeTransferOut   int64 = 3 // 站内转账转出
+	AssetTransactionTypeTransferIn    int64 = 4 // 站内转账转入
+	AssetTransactionTypeSubAccountOut int64 = 5 // 母子账户划转转出
+	AssetTransactionTypeSubAccountIn  int64 = 6 // 母子账户划转转入
+)
+
+// 资产交易状态 / Asset Transaction Status
+// 链上充值按 待确认(1) -> 成功(2) 流转，未入账时被链重组移除为已取消(4)，入账后被移除为已回滚(8)；提现按 待用户确认 -> 待审核 -> 已审核 -> 广播中 -> 已确认/失败 流转，待用户确认时可取消，待审核时可被拒绝
+const (
+	AssetTransactionStatusPending      int64 = 1 // 待处理（提现：待审核，金额已冻结）
+	AssetTransactionStatusSuccess      int64 = 2 // 成功（提现：链上已确认，冻结金额已扣除）
+	AssetTransactionStatusFailed       int64 = 3 // 失败（提现：冻结金额已退回）
+	AssetTransactionStatusCancelled    int64 = 4 // 已取消
+	AssetTransactionStatusApproved     int64 = 5 // 提现已审核通过，等待广播
+	AssetTransactionStatusBroadcasting int64 = 6 // 提现已广播，等待链上确认
+	AssetTransactionStatusRejected     int64 = 7 // 提现审核被拒绝，冻结金额已退回
+	AssetTransactionStatusReverted     int64 = 8 // 充值入账后因链重组被回滚，入账金额已扣回
+	AssetTransactionStatusUnconfirmed  int64 = 9 // 提现等待用户提交确认码，金额已冻结
+)
+
 type (
 	// AssetTransactionModel is an interface to be customized, add more methods here,
 	// and implement the added methods in customAssetTransactionModel.
@@ -21,6 +45,18 @@ type (
 		FindByUserIDAndType(ctx context.Context, userID uint64, transactionType int64, limit, offset int64) ([]*AssetTransaction, error)
 		CountByUserID(ctx context.Context, userID uint64) (int64, error)
 		CountByUserIDAndType(ctx context.Context, userID uint64, transactionType int64) (int64, error)
+		SumNetFlowByUserAndCurrency(ctx context.Context) ([]*BalanceFlow, error)
+		SumInFlightWithdrawalsByUserAndCurrency(ctx context.Context) ([]*BalanceFlow, error)
+		FindByTypeAndStatus(ctx context.Context, transactionType, status int64, limit, offset int64) ([]*AssetTransaction, error)
+		CountByTypeAndStatus(ctx context.Context, transactionType, status int64) (int64, error)
+		UpdateStatus(ctx context.Context, id uint64, fromStatus, toStatus int64, txHash, remark string) error
+		FindChainDepositsSince(ctx context.Context, network string, fromHeight int64) ([]*AssetTransaction, error)
+		UpdateConfirmations(ctx context.Context, id uint64, blockHeight, confirmations int64) error
+		FindWithdrawalsSince(ctx context.Context, userID uint64, since time.Time) ([]*AssetTransaction, error)
+		FindWithdrawalsSinceForUpdate(ctx context.Context, userID uint64, since time.Time) ([]*AssetTransaction, error)
+		FindByUserIDAndTypeSince(ctx context.Context, userID uint64, transactionType int64, since time.Time) ([]*AssetTransaction, error)
+		FindByUserIDAndTypeSinceForUpdate(ctx context.Context, userID uint64, transactionType int64, since time.Time) ([]*AssetTransaction, error)
+		WithSession(session sqlx.Session) AssetTransactionModel
 	}
 
 	customAssetTransactionModel struct {
@@ -29,19 +65,23 @@ type (
 
 	// AssetTransaction 资产交易记录模型
 	AssetTransaction struct {
-		ID            uint64    `db:"id"`             // 交易记录ID，主键
-		UserID        uint64    `db:"user_id"`        // 用户ID，关联users表
-		TransactionID string    `db:"transaction_id"` // 交易ID，唯一标识
-		Currency      string    `db:"currency"`       // 币种代码，如BTC、ETH、USDT等
-		Type          int64     `db:"type"`           // 交易类型：1-充值，2-提现
-		Amount        string    `db:"amount"`         // 交易金额，使用string存储decimal避免精度问题
-		Fee           string    `db:"fee"`            // 手续费，使用string存储decimal避免精度问题
-		Status        int64     `db:"status"`         // 交易状态：1-待处理，2-成功，3-失败，4-已取消
-		Address       string    `db:"address"`        // 地址（提现时有值，充值时可为空）
-		TxHash        string    `db:"tx_hash"`        // 区块链交易哈希（可选）
-		Remark        string    `db:"remark"`         // 备注信息
-		CreatedAt     time.Time `db:"created_at"`     // 创建时间
-		UpdatedAt     time.Time `db:"updated_at"`     // 更新时间
+		ID             uint64    `db:"id"`              // 交易记录ID，主键
+		UserID         uint64    `db:"user_id"`         // 用户ID，关联users表
+		TransactionID  string    `db:"transaction_id"`  // 交易ID，唯一标识
+		Currency       string    `db:"currency"`        // 币种代码，如BTC、ETH、USDT等
+		Network        string    `db:"network"`         // 充提网络，如BTC、ERC20、TRC20
+		Type           int64     `db:"type"`            // 交易类型：1-充值，2-提现，3-转账转出，4-转账转入
+		Amount         string    `db:"amount"`          // 交易金额，使用string存储decimal避免精度问题
+		Fee            string    `db:"fee"`             // 手续费，使用string存储decimal避免精度问题
+		Status         int64     `db:"status"`          // 交易状态：1-待处理，2-成功，3-失败，4-已取消，5-已审核，6-广播中，7-已拒绝，8-已回滚
+		Address        string    `db:"address"`         // 地址（提现时有值，充值时可为空）
+		TxHash         string    `db:"tx_hash"`         // 区块链交易哈希（可选）
+		BlockHeight    int64     `db:"block_height"`    // 链上充值所在区块高度，手动充值和提现为0
+		Confirmations  int64     `db:"confirmations"`   // 链上充值最近一次扫描时的确认数
+		CounterpartyID uint64    `db:"counterparty_id"` // 站内转账和母子账户划转的对方用户ID，充提为0
+		Remark         string    `db:"remark"`          // 备注信息
+		CreatedAt      time.Time `db:"created_at"`      // 创建时间
+		UpdatedAt      time.Time `db:"updated_at"`      // 更新时间
 	}
 
 	assetTransactionModel interface {
@@ -72,13 +112,13 @@ func newAssetTransactionModel(conn sqlx.SqlConn) *defaultAssetTransactionModel {
 }
 
 func (m *defaultAssetTransactionModel) Insert(ctx context.Context, data *AssetTransaction) (sql.Result, error) {
-	query := `INSERT INTO ` + m.table + ` (user_id, transaction_id, currency, type, amount, fee, status, address, tx_hash, remark, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
-	ret, err := m.conn.ExecCtx(ctx, query, data.UserID, data.TransactionID, data.Currency, data.Type, data.Amount, data.Fee, data.Status, data.Address, data.TxHash, data.Remark, data.CreatedAt, data.UpdatedAt)
+	query := `INSERT INTO ` + m.table + ` (user_id, transaction_id, currency, network, type, amount, fee, status, address, tx_hash, block_height, confirmations, counterparty_id, remark, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.UserID, data.TransactionID, data.Currency, data.Network, data.Type, data.Amount, data.Fee, data.Status, data.Address, data.TxHash, data.BlockHeight, data.Confirmations, data.CounterpartyID, data.Remark, data.CreatedAt, data.UpdatedAt)
 	return ret, err
 }
 
 func (m *defaultAssetTransactionModel) FindOne(ctx context.Context, id uint64) (*AssetTransaction, error) {
-	query := `SELECT id, user_id, transaction_id, currency, type, amount, fee, status, address, tx_hash, remark, created_at, updated_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	query := `SELECT id, user_id, transaction_id, currency, network, type, amount, fee, status, address, tx_hash, block_height, confirmations, counterparty_id, remark, created_at, updated_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
 	var resp AssetTransaction
 	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
 	switch err {
@@ -92,7 +132,7 @@ func (m *defaultAssetTransactionModel) FindOne(ctx context.Context, id uint64) (
 }
 
 func (m *customAssetTransactionModel) FindByTransactionID(ctx context.Context, transactionID string) (*AssetTransaction, error) {
-	query := `SELECT id, user_id, transaction_id, currency, type, amount, fee, status, address, tx_hash, remark, created_at, updated_at FROM ` + m.table + ` WHERE transaction_id = $1 LIMIT 1`
+	query := `SELECT id, user_id, transaction_id, currency, network, type, amount, fee, status, address, tx_hash, block_height, confirmations, counterparty_id, remark, created_at, updated_at FROM ` + m.table + ` WHERE transaction_id = $1 LIMIT 1`
 	var resp AssetTransaction
 	err := m.conn.QueryRowCtx(ctx, &resp, query, transactionID)
 	switch err {
@@ -106,14 +146,14 @@ func (m *customAssetTransactionModel) FindByTransactionID(ctx context.Context, t
 }
 
 func (m *customAssetTransactionModel) FindByUserID(ctx context.Context, userID uint64, limit, offset int64) ([]*AssetTransaction, error) {
-	query := `SELECT id, user_id, transaction_id, currency, type, amount, fee, status, address, tx_hash, remark, created_at, updated_at FROM ` + m.table + ` WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+	query := `SELECT id, user_id, transaction_id, currency, network, type, amount, fee, status, address, tx_hash, block_height, confirmations, counterparty_id, remark, created_at, updated_at FROM ` + m.table + ` WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
 	var resp []*AssetTransaction
 	err := m.conn.QueryRowsCtx(ctx, &resp, query, userID, limit, offset)
 	return resp, err
 }
 
 func (m *customAssetTransactionModel) FindByUserIDAndType(ctx context.Context, userID uint64, transactionType int64, limit, offset int64) ([]*AssetTransaction, error) {
-	query := `SELECT id, user_id, transaction_id, currency, type, amount, fee, status, address, tx_hash, remark, created_at, updated_at FROM ` + m.table + ` WHERE user_id = $1 AND type = $2 ORDER BY created_at DESC LIMIT $3 OFFSET $4`
+	query := `SELECT id, user_id, transaction_id, currency, network, type, amount, fee, status, address, tx_hash, block_height, confirmations, counterparty_id, remark, created_at, updated_at FROM ` + m.table + ` WHERE user_id = $1 AND type = $2 ORDER BY created_at DESC LIMIT $3 OFFSET $4`
 	var resp []*AssetTransaction
 	err := m.conn.QueryRowsCtx(ctx, &resp, query, userID, transactionType, limit, offset)
 	return resp, err
@@ -133,9 +173,59 @@ func (m *customAssetTransactionModel) CountByUserIDAndType(ctx context.Context,
 	return count, err
 }
 
+// SumNetFlowByUserAndCurrency 按用户和币种汇总充提和站内转账净流入：成功的充值和转入（含母子账户划入）计入，已确认的提现（含手续费）和转出扣除
+// 处理中的提现金额仍在用户冻结余额中，失败、拒绝或取消的提现已退回，均不参与计算
+func (m *customAssetTransactionModel) SumNetFlowByUserAndCurrency(ctx context.Context) ([]*BalanceFlow, error) {
+	query := `SELECT user_id, currency, SUM(CASE WHEN type IN (1, 4, 6) THEN amount ELSE -(amount + fee) END)::text AS amount FROM ` + m.table + ` WHERE status = 2 GROUP BY user_id, currency`
+	var resp []*BalanceFlow
+	err := m.conn.QueryRowsCtx(ctx, &resp, query)
+	return resp, err
+}
+
+// SumInFlightWithdrawalsByUserAndCurrency 按用户和币种汇总处理中（待用户确认、待审核、已审核、广播中）提现的冻结金额（含手续费）
+func (m *customAssetTransactionModel) SumInFlightWithdrawalsByUserAndCurrency(ctx context.Context) ([]*BalanceFlow, error) {
+	query := `SELECT user_id, currency, SUM(amount + fee)::text AS amount FROM ` + m.table + ` WHERE type = 2 AND status IN (1, 5, 6, 9) GROUP BY user_id, currency`
+	var resp []*BalanceFlow
+	err := m.conn.QueryRowsCtx(ctx, &resp, query)
+	return resp, err
+}
+
+// FindByTypeAndStatus 按类型和状态查询交易记录，按创建时间先后排序，用于审核队列
+func (m *customAssetTransactionModel) FindByTypeAndStatus(ctx context.Context, transactionType, status int64, limit, offset int64) ([]*AssetTransaction, error) {
+	query := `SELECT id, user_id, transaction_id, currency, network, type, amount, fee, status, address, tx_hash, block_height, confirmations, counterparty_id, remark, created_at, updated_at FROM ` + m.table + ` WHERE type = $1 AND status = $2 ORDER BY created_at ASC, id ASC LIMIT $3 OFFSET $4`
+	var resp []*AssetTransaction
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, transactionType, status, limit, offset)
+	return resp, err
+}
+
+func (m *customAssetTransactionModel) CountByTypeAndStatus(ctx context.Context, transactionType, status int64) (int64, error) {
+	query := `SELECT COUNT(*) FROM ` + m.table + ` WHERE type = $1 AND status = $2`
+	var count int64
+	err := m.conn.QueryRowCtx(ctx, &count, query, transactionType, status)
+	return count, err
+}
+
+// UpdateStatus 仅当交易记录仍处于fromStatus时更新状态，txHash或remark为空时保留原值
+// 记录状态已被其他请求修改时返回ErrWithdrawalStatusChanged，防止并发审核重复处理余额
+func (m *customAssetTransactionModel) UpdateStatus(ctx context.Context, id uint64, fromStatus, toStatus int64, txHash, remark string) error {
+	query := `UPDATE ` + m.table + ` SET status = $1, tx_hash = COALESCE(NULLIF($2, ''), tx_hash), remark = COALESCE(NULLIF($3, ''), remark), updated_at = $4 WHERE id = $5 AND status = $6`
+	ret, err := m.conn.ExecCtx(ctx, query, toStatus, txHash, remark, time.Now(), id, fromStatus)
+	if err != nil {
+		return err
+	}
+	rows, err := ret.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrWithdrawalStatusChanged
+	}
+	return nil
+}
+
 func (m *defaultAssetTransactionModel) Update(ctx context.Context, data *AssetTransaction) error {
-	query := `UPDATE ` + m.table + ` SET user_id = $1, transaction_id = $2, currency = $3, type = $4, amount = $5, fee = $6, status = $7, address = $8, tx_hash = $9, remark = $10, updated_at = $11 WHERE id = $12`
-	_, err := m.conn.ExecCtx(ctx, query, data.UserID, data.TransactionID, data.Currency, data.Type, data.Amount, data.Fee, data.Status, data.Address, data.TxHash, data.Remark, data.UpdatedAt, data.ID)
+	query := `UPDATE ` + m.table + ` SET user_id = $1, transaction_id = $2, currency = $3, network = $4, type = $5, amount = $6, fee = $7, status = $8, address = $9, tx_hash = $10, block_height = $11, confirmations = $12, remark = $13, updated_at = $14 WHERE id = $15`
+	_, err := m.conn.ExecCtx(ctx, query, data.UserID, data.TransactionID, data.Currency, data.Network, data.Type, data.Amount, data.Fee, data.Status, data.Address, data.TxHash, data.BlockHeight, data.Confirmations, data.Remark, data.UpdatedAt, data.ID)
 	return err
 }
 
@@ -143,4 +233,84 @@ func (m *defaultAssetTransactionModel) Delete(ctx context.Context, id uint64) er
 	query := `DELETE FROM ` + m.table + ` WHERE id = $1`
 	_, err := m.conn.ExecCtx(ctx, query, id)
 	return err
-}
\ No newline at end of file
+}
+
+// FindChainDepositsSince 查询网络上区块高度不低于fromHeight、待确认或已入账的链上充值，用于比对链重组
+func (m *customAssetTransactionModel) FindChainDepositsSince(ctx context.Context, network string, fromHeight int64) ([]*AssetTransaction, error) {
+	query := `SELECT id, user_id, transaction_id, currency, network, type, amount, fee, status, address, tx_hash, block_height, confirmations, counterparty_id, remark, created_at, updated_at FROM ` + m.table + ` WHERE type = 1 AND network = $1 AND block_height >= $2 AND block_height > 0 AND status IN (1, 2) ORDER BY block_height ASC, id ASC`
+	var resp []*AssetTransaction
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, network, fromHeight)
+	return resp, err
+}
+
+// UpdateConfirmations 更新链上充值的所在区块和确认数
+func (m *customAssetTransactionModel) UpdateConfirmations(ctx context.Context, id uint64, blockHeight, confirmations int64) error {
+	query := `UPDATE ` + m.table + ` SET block_height = $1, confirmations = $2, updated_at = $3 WHERE id = $4`
+	_, err := m.conn.ExecCtx(ctx, query, blockHeight, confirmations, time.Now(), id)
+	return err
+}
+
+// FindWithdrawalsSince 查询用户自since以来发起的、未退回冻结金额（未失败、拒绝或取消）的提现，用于限额和频率校验
+func (m *customAssetTransactionModel) FindWithdrawalsSince(ctx context.Context, userID uint64, since time.Time) ([]*AssetTransaction, error) {
+	query := `SELECT id, user_id, transaction_id, currency, network, type, amount, fee, status, address, tx_hash, block_height, confirmations, counterparty_id, remark, created_at, updated_at FROM ` + m.table + ` WHERE user_id = $1 AND type = 2 AND status IN (1, 2, 5, 6, 9) AND created_at >= $2 ORDER BY created_at ASC, id ASC`
+	var resp []*AssetTransaction
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, userID, since)
+	return resp, err
+}
+
+// FindWithdrawalsSinceForUpdate 锁定用户行后查询用户自since以来的有效提现
+// 需在提现事务中调用，同一用户的并发提现在锁上排队，保证限额检查与插入提现记录之间不会被其它提现穿插
+func (m *customAssetTransactionModel) FindWithdrawalsSinceForUpdate(ctx context.Context, userID uint64, since time.Time) ([]*AssetTransaction, error) {
+	var locked uint64
+	if err := m.conn.QueryRowCtx(ctx, &locked, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+		return nil, err
+	}
+	return m.FindWithdrawalsSince(ctx, userID, since)
+}
+
+// FindByUserIDAndTypeSince 查询用户自since以来指定类型的交易记录，按创建时间先后排序
+func (m *customAssetTransactionModel) FindByUserIDAndTypeSince(ctx context.Context, userID uint64, transactionType int64, since time.Time) ([]*AssetTransaction, error) {
+	query := `SELECT id, user_id, transaction_id, currency, network, type, amount, fee, status, address, tx_hash, block_height, confirmations, counterparty_id, remark, created_at, updated_at FROM ` + m.table + ` WHERE user_id = $1 AND type = $2 AND created_at >= $3 ORDER BY created_at ASC, id ASC`
+	var resp []*AssetTransaction
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, userID, transactionType, since)
+	return resp, err
+}
+
+// FindByUserIDAndTypeSinceForUpdate 锁定用户行后查询用户自since以来指定类型的交易记录
+// 需在事务中调用，同一用户的并发请求在锁上排队，保证限额检查与插入交易记录之间不会被其它请求穿插
+func (m *customAssetTransactionModel) FindByUserIDAndTypeSinceForUpdate(ctx context.Context, userID uint64, transactionType int64, since time.Time) ([]*AssetTransaction, error) {
+	var locked uint64
+	if err := m.conn.QueryRowCtx(ctx, &locked, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+		return nil, err
+	}
+	return m.FindByUserIDAndTypeSince(ctx, userID, transactionType, since)
+}
+
+// WithdrawalStatusText 返回提现状态的英文描述，用于日志和错误信息
+func WithdrawalStatusText(status int64) string {
+	switch status {
+	case AssetTransactionStatusPending:
+		return "pending-review"
+	case AssetTransactionStatusSuccess:
+		return "confirmed"
+	case AssetTransactionStatusFailed:
+		return "failed"
+	case AssetTransactionStatusCancelled:
+		return "cancelled"
+	case AssetTransactionStatusApproved:
+		return "approved"
+	case AssetTransactionStatusBroadcasting:
+		return "broadcasting"
+	case AssetTransactionStatusRejected:
+		return "rejected"
+	case AssetTransactionStatusUnconfirmed:
+		return "awaiting-confirmation"
+	default:
+		return "unknown"
+	}
+}
+
+// WithSession 返回在事务session中执行读写的资产交易模型
+func (m *customAssetTransactionModel) WithSession(session sqlx.Session) AssetTransactionModel {
+	return NewAssetTransactionModel(sqlx.NewSqlConnFromSession(session))
+}
@@ -20,10 +20,17 @@ type (
 		// 自定义方法
 		FindByUserID(ctx context.Context, userID uint64) ([]*Balance, error)
 		FindByUserIDAndCurrency(ctx context.Context, userID uint64, currency string) (*Balance, error)
+		FindByUserIDAndCurrencyForUpdate(ctx context.Context, userID uint64, currency string) (*Balance, error)
 		UpdateBalance(ctx context.Context, userID uint64, currency string, available, frozen string) error
 		FreezeBalance(ctx context.Context, userID uint64, currency string, amount string) error
 		UnfreezeBalance(ctx context.Context, userID uint64, currency string, amount string) error
+		AddAvailable(ctx context.Context, userID uint64, currency string, amount string) error
+		DeductFrozen(ctx context.Context, userID uint64, currency string, amount string) error
+		FindAll(ctx context.Context) ([]*Balance, error)
+		CountByCurrency(ctx context.Context, currency string) (int64, error)
 		Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error
+		TransRepeatableRead(ctx context.Context, fn func(context.Context, sqlx.Session) error) error
+		WithSession(session sqlx.Session) BalanceModel
 	}
 
 	customBalanceModel struct {
@@ -40,6 +47,13 @@ type (
 		UpdatedAt time.Time `db:"updated_at"` // 余额最后更新时间
 	}
 
+	// BalanceFlow 按用户和币种聚合的资金净流入，用于对账
+	BalanceFlow struct {
+		UserID   uint64 `db:"user_id"`  // 用户ID
+		Currency string `db:"currency"` // 币种代码
+		Amount   string `db:"amount"`   // 净流入金额，可以为负数
+	}
+
 	balanceModel interface {
 		Insert(ctx context.Context, data *Balance) (sql.Result, error)
 		FindOne(ctx context.Context, id uint64) (*Balance, error)
@@ -108,6 +122,22 @@ func (m *customBalanceModel) FindByUserIDAndCurrency(ctx context.Context, userID
 	}
 }
 
+// FindByUserIDAndCurrencyForUpdate 查询并锁定用户的币种余额，在事务中调用时行锁持续到事务结束，
+// 保证并发的余额读改写串行执行，不会相互覆盖
+func (m *customBalanceModel) FindByUserIDAndCurrencyForUpdate(ctx context.Context, userID uint64, currency string) (*Balance, error) {
+	query := `SELECT id, user_id, currency, available, frozen, updated_at FROM ` + m.table + ` WHERE user_id = $1 AND currency = $2 LIMIT 1 FOR UPDATE`
+	var resp Balance
+	err := m.conn.QueryRowCtx(ctx, &resp, query, userID, currency)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
 func (m *customBalanceModel) UpdateBalance(ctx context.Context, userID uint64, currency string, available, frozen string) error {
 	query := `UPDATE ` + m.table + ` SET available = $1, frozen = $2, updated_at = $3 WHERE user_id = $4 AND currency = $5`
 	_, err := m.conn.ExecCtx(ctx, query, available, frozen, time.Now(), userID, currency)
@@ -137,8 +167,8 @@ func (m *customBalanceModel) FreezeBalance(ctx context.Context, userID uint64, c
 		return errors.New("freeze amount must be positive")
 	}
 
-	// 查询当前余额
-	balance, err := m.FindByUserIDAndCurrency(ctx, userID, currency)
+	// 查询并锁定当前余额
+	balance, err := m.FindByUserIDAndCurrencyForUpdate(ctx, userID, currency)
 	if err != nil {
 		return err
 	}
@@ -177,8 +207,8 @@ func (m *customBalanceModel) UnfreezeBalance(ctx context.Context, userID uint64,
 		return errors.New("unfreeze amount must be positive")
 	}
 
-	// 查询当前余额
-	balance, err := m.FindByUserIDAndCurrency(ctx, userID, currency)
+	// 查询并锁定当前余额
+	balance, err := m.FindByUserIDAndCurrencyForUpdate(ctx, userID, currency)
 	if err != nil {
 		return err
 	}
@@ -206,6 +236,101 @@ func (m *customBalanceModel) UnfreezeBalance(ctx context.Context, userID uint64,
 	return m.UpdateBalance(ctx, userID, currency, newAvailable.String(), newFrozen.String())
 }
 
+// DeductFrozen 从冻结余额中扣除指定金额（资产转出平台），以条件更新在数据库中原子地完成扣减，
+// 冻结余额不足时不做修改并返回ErrInsufficientFrozenBalance
+func (m *customBalanceModel) DeductFrozen(ctx context.Context, userID uint64, currency string, amount string) error {
+	deductAmount, err := decimal.NewFromString(amount)
+	if err != nil {
+		return errors.New("invalid deduct amount format")
+	}
+	if deductAmount.LessThanOrEqual(decimal.Zero) {
+		return errors.New("deduct amount must be positive")
+	}
+
+	query := `UPDATE ` + m.table + ` SET frozen = (frozen::numeric - $1::numeric)::text, updated_at = $2 WHERE user_id = $3 AND currency = $4 AND frozen::numeric >= $1::numeric`
+	ret, err := m.conn.ExecCtx(ctx, query, deductAmount.String(), time.Now(), userID, currency)
+	if err != nil {
+		return err
+	}
+	affected, err := ret.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrInsufficientFrozenBalance
+	}
+	return nil
+}
+
+// AddAvailable 增加用户可用余额，amount为负数时扣减，扣减后可用余额不能小于0
+// 增加余额时记录不存在则先创建，创建与加锁分开执行，避免并发创建同一记录时唯一约束冲突
+func (m *customBalanceModel) AddAvailable(ctx context.Context, userID uint64, currency string, amount string) error {
+	delta, err := decimal.NewFromString(amount)
+	if err != nil {
+		return errors.New("invalid amount format")
+	}
+	if delta.IsZero() {
+		return nil
+	}
+
+	balance, err := m.FindByUserIDAndCurrencyForUpdate(ctx, userID, currency)
+	if errors.Is(err, ErrNotFound) && delta.IsPositive() {
+		query := `INSERT INTO ` + m.table + ` (user_id, currency, available, frozen, updated_at) VALUES ($1, $2, '0', '0', $3) ON CONFLICT (user_id, currency) DO NOTHING`
+		if _, err := m.conn.ExecCtx(ctx, query, userID, currency, time.Now()); err != nil {
+			return err
+		}
+		balance, err = m.FindByUserIDAndCurrencyForUpdate(ctx, userID, currency)
+	}
+	if err != nil {
+		return err
+	}
+
+	currentAvailable, err := decimal.NewFromString(balance.Available)
+	if err != nil {
+		return errors.New("invalid current available balance format")
+	}
+
+	newAvailable := currentAvailable.Add(delta)
+	if newAvailable.IsNegative() {
+		return ErrInsufficientBalance
+	}
+
+	return m.UpdateBalance(ctx, userID, currency, newAvailable.String(), balance.Frozen)
+}
+
+// FindAll 查询全部余额记录，按用户ID和币种排序，供对账使用
+func (m *customBalanceModel) FindAll(ctx context.Context) ([]*Balance, error) {
+	query := `SELECT id, user_id, currency, available, frozen, updated_at FROM ` + m.table + ` ORDER BY user_id, currency`
+	var resp []*Balance
+	err := m.conn.QueryRowsCtx(ctx, &resp, query)
+	return resp, err
+}
+
+// CountByCurrency 统计指定币种的余额记录数量
+func (m *customBalanceModel) CountByCurrency(ctx context.Context, currency string) (int64, error) {
+	query := `SELECT COUNT(*) FROM ` + m.table + ` WHERE currency = $1`
+	var count int64
+	err := m.conn.QueryRowCtx(ctx, &count, query, currency)
+	return count, err
+}
+
 func (m *customBalanceModel) Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
 	return m.conn.TransactCtx(ctx, fn)
-}
\ No newline at end of file
+}
+
+// TransRepeatableRead 在可重复读隔离级别的事务中执行fn，fn中通过session的全部查询读取同一时间点的快照，
+// 供对账、储备金快照等需要跨表一致数据的任务使用
+func (m *customBalanceModel) TransRepeatableRead(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		// 隔离级别必须在事务的第一条查询之前设置
+		if _, err := session.ExecCtx(ctx, `SET TRANSACTION ISOLATION LEVEL REPEATABLE READ`); err != nil {
+			return err
+		}
+		return fn(ctx, session)
+	})
+}
+
+// WithSession 返回在事务session中执行读写的模型，Trans回调中的余额变动必须通过它执行，才能与记账和业务记录在同一事务中提交
+func (m *customBalanceModel) WithSession(session sqlx.Session) BalanceModel {
+	return NewBalanceModel(sqlx.NewSqlConnFromSession(session))
+}
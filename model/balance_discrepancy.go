@@ -0,0 +1,104 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ BalanceDiscrepancyModel = (*customBalanceDiscrepancyModel)(nil)
+
+type (
+	// BalanceDiscrepancyModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customBalanceDiscrepancyModel.
+	BalanceDiscrepancyModel interface {
+		balanceDiscrepancyModel
+		// 自定义方法
+		FindByReportID(ctx context.Context, reportID uint64) ([]*BalanceDiscrepancy, error)
+	}
+
+	customBalanceDiscrepancyModel struct {
+		*defaultBalanceDiscrepancyModel
+	}
+
+	// BalanceDiscrepancy 对账差异明细，记录单个用户单个币种的期望余额与实际余额
+	BalanceDiscrepancy struct {
+		ID             uint64    `db:"id"`              // 记录ID，主键
+		ReportID       uint64    `db:"report_id"`       // 对账报告ID，关联reconciliation_reports表
+		UserID         uint64    `db:"user_id"`         // 用户ID
+		Currency       string    `db:"currency"`        // 币种代码
+		ExpectedTotal  string    `db:"expected_total"`  // 期望总余额：充值-提现±成交
+		ActualTotal    string    `db:"actual_total"`    // 实际总余额：available+frozen
+		ExpectedFrozen string    `db:"expected_frozen"` // 期望冻结余额：未完成订单剩余冻结
+		ActualFrozen   string    `db:"actual_frozen"`   // 实际冻结余额
+		UserFrozen     bool      `db:"user_frozen"`     // 是否已自动冻结该用户
+		CreatedAt      time.Time `db:"created_at"`      // 创建时间
+	}
+
+	balanceDiscrepancyModel interface {
+		Insert(ctx context.Context, data *BalanceDiscrepancy) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*BalanceDiscrepancy, error)
+		Update(ctx context.Context, data *BalanceDiscrepancy) error
+		Delete(ctx context.Context, id uint64) error
+	}
+
+	defaultBalanceDiscrepancyModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewBalanceDiscrepancyModel returns a model for the database table.
+func NewBalanceDiscrepancyModel(conn sqlx.SqlConn) BalanceDiscrepancyModel {
+	return &customBalanceDiscrepancyModel{
+		defaultBalanceDiscrepancyModel: newBalanceDiscrepancyModel(conn),
+	}
+}
+
+func newBalanceDiscrepancyModel(conn sqlx.SqlConn) *defaultBalanceDiscrepancyModel {
+	return &defaultBalanceDiscrepancyModel{
+		conn:  conn,
+		table: "balance_discrepancies",
+	}
+}
+
+func (m *defaultBalanceDiscrepancyModel) Insert(ctx context.Context, data *BalanceDiscrepancy) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (report_id, user_id, currency, expected_total, actual_total, expected_frozen, actual_frozen, user_frozen, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.ReportID, data.UserID, data.Currency, data.ExpectedTotal, data.ActualTotal, data.ExpectedFrozen, data.ActualFrozen, data.UserFrozen, data.CreatedAt)
+	return ret, err
+}
+
+func (m *defaultBalanceDiscrepancyModel) FindOne(ctx context.Context, id uint64) (*BalanceDiscrepancy, error) {
+	query := `SELECT id, report_id, user_id, currency, expected_total, actual_total, expected_frozen, actual_frozen, user_frozen, created_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp BalanceDiscrepancy
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *customBalanceDiscrepancyModel) FindByReportID(ctx context.Context, reportID uint64) ([]*BalanceDiscrepancy, error) {
+	query := `SELECT id, report_id, user_id, currency, expected_total, actual_total, expected_frozen, actual_frozen, user_frozen, created_at FROM ` + m.table + ` WHERE report_id = $1 ORDER BY user_id, currency`
+	var resp []*BalanceDiscrepancy
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, reportID)
+	return resp, err
+}
+
+func (m *defaultBalanceDiscrepancyModel) Update(ctx context.Context, data *BalanceDiscrepancy) error {
+	query := `UPDATE ` + m.table + ` SET report_id = $1, user_id = $2, currency = $3, expected_total = $4, actual_total = $5, expected_frozen = $6, actual_frozen = $7, user_frozen = $8 WHERE id = $9`
+	_, err := m.conn.ExecCtx(ctx, query, data.ReportID, data.UserID, data.Currency, data.ExpectedTotal, data.ActualTotal, data.ExpectedFrozen, data.ActualFrozen, data.UserFrozen, data.ID)
+	return err
+}
+
+func (m *defaultBalanceDiscrepancyModel) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM ` + m.table + ` WHERE id = $1`
+	_, err := m.conn.ExecCtx(ctx, query, id)
+	return err
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ ChainScanCursorModel = (*customChainScanCursorModel)(nil)
+
+type (
+	// ChainScanCursorModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customChainScanCursorModel.
+	ChainScanCursorModel interface {
+		chainScanCursorModel
+		// 自定义方法
+		Upsert(ctx context.Context, network string, height int64) error
+	}
+
+	customChainScanCursorModel struct {
+		*defaultChainScanCursorModel
+	}
+
+	// ChainScanCursor 充值扫描进度，每个网络一条记录
+	ChainScanCursor struct {
+		Network   string    `db:"network"`    // 网络代码，主键
+		Height    int64     `db:"height"`     // 最近一次扫描时的最新区块高度
+		UpdatedAt time.Time `db:"updated_at"` // 更新时间
+	}
+
+	chainScanCursorModel interface {
+		FindOne(ctx context.Context, network string) (*ChainScanCursor, error)
+	}
+
+	defaultChainScanCursorModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewChainScanCursorModel returns a model for the database table.
+func NewChainScanCursorModel(conn sqlx.SqlConn) ChainScanCursorModel {
+	return &customChainScanCursorModel{
+		defaultChainScanCursorModel: newChainScanCursorModel(conn),
+	}
+}
+
+func newChainScanCursorModel(conn sqlx.SqlConn) *defaultChainScanCursorModel {
+	return &defaultChainScanCursorModel{
+		conn:  conn,
+		table: "chain_scan_cursors",
+	}
+}
+
+func (m *defaultChainScanCursorModel) FindOne(ctx context.Context, network string) (*ChainScanCursor, error) {
+	query := `SELECT network, height, updated_at FROM ` + m.table + ` WHERE network = $1 LIMIT 1`
+	var resp ChainScanCursor
+	err := m.conn.QueryRowCtx(ctx, &resp, query, network)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// Upsert 保存网络的扫描进度
+func (m *customChainScanCursorModel) Upsert(ctx context.Context, network string, height int64) error {
+	query := `INSERT INTO ` + m.table + ` (network, height, updated_at) VALUES ($1, $2, $3) ON CONFLICT (network) DO UPDATE SET height = EXCLUDED.height, updated_at = EXCLUDED.updated_at`
+	_, err := m.conn.ExecCtx(ctx, query, network, height, time.Now())
+	return err
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ CurrencyModel = (*customCurrencyModel)(nil)
+
+// 币种状态 / Currency Status
+const (
+	CurrencyStatusEnabled  int64 = 1 // 启用
+	CurrencyStatusDisabled int64 = 2 // 停用：不可充提，也不能用于新建交易对
+)
+
+type (
+	// CurrencyModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customCurrencyModel.
+	CurrencyModel interface {
+		currencyModel
+		// 自定义方法
+		FindAll(ctx context.Context) ([]*Currency, error)
+	}
+
+	customCurrencyModel struct {
+		*defaultCurrencyModel
+	}
+
+	// Currency 币种配置模型，充值、提现和交易对创建都以此为准
+	// 手续费、最小金额、确认数和地址格式按网络配置，见CurrencyNetwork
+	Currency struct {
+		Code            string    `db:"code"`             // 币种代码，主键，如BTC
+		Name            string    `db:"name"`             // 币种名称
+		Precision       int64     `db:"precision"`        // 金额精度，小数位数
+		DepositEnabled  bool      `db:"deposit_enabled"`  // 是否开放充值（总开关，各网络另有开关）
+		WithdrawEnabled bool      `db:"withdraw_enabled"` // 是否开放提现（总开关，各网络另有开关）
+		MaxDeposit      string    `db:"max_deposit"`      // 单笔最大充值金额，0表示不限制
+		MaxWithdraw     string    `db:"max_withdraw"`     // 单笔最大提现金额，0表示不限制
+		Status          int64     `db:"status"`           // 状态：1-启用，2-停用
+		CreatedAt       time.Time `db:"created_at"`       // 创建时间
+		UpdatedAt       time.Time `db:"updated_at"`       // 更新时间
+	}
+
+	currencyModel interface {
+		Insert(ctx context.Context, data *Currency) (sql.Result, error)
+		FindOne(ctx context.Context, code string) (*Currency, error)
+		Update(ctx context.Context, data *Currency) error
+		Delete(ctx context.Context, code string) error
+	}
+
+	defaultCurrencyModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewCurrencyModel returns a model for the database table.
+func NewCurrencyModel(conn sqlx.SqlConn) CurrencyModel {
+	return &customCurrencyModel{
+		defaultCurrencyModel: newCurrencyModel(conn),
+	}
+}
+
+func newCurrencyModel(conn sqlx.SqlConn) *defaultCurrencyModel {
+	return &defaultCurrencyModel{
+		conn:  conn,
+		table: "currencies",
+	}
+}
+
+func (m *defaultCurrencyModel) Insert(ctx context.Context, data *Currency) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (code, name, precision, deposit_enabled, withdraw_enabled, max_deposit, max_withdraw, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.Code, data.Name, data.Precision, data.DepositEnabled, data.WithdrawEnabled, data.MaxDeposit, data.MaxWithdraw, data.Status, data.CreatedAt, data.UpdatedAt)
+	return ret, err
+}
+
+func (m *defaultCurrencyModel) FindOne(ctx context.Context, code string) (*Currency, error) {
+	query := `SELECT code, name, precision, deposit_enabled, withdraw_enabled, max_deposit, max_withdraw, status, created_at, updated_at FROM ` + m.table + ` WHERE code = $1 LIMIT 1`
+	var resp Currency
+	err := m.conn.QueryRowCtx(ctx, &resp, query, code)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindAll 查询全部币种（包括停用的），按币种代码排序
+func (m *customCurrencyModel) FindAll(ctx context.Context) ([]*Currency, error) {
+	query := `SELECT code, name, precision, deposit_enabled, withdraw_enabled, max_deposit, max_withdraw, status, created_at, updated_at FROM ` + m.table + ` ORDER BY code ASC`
+	var resp []*Currency
+	err := m.conn.QueryRowsCtx(ctx, &resp, query)
+	return resp, err
+}
+
+func (m *defaultCurrencyModel) Update(ctx context.Context, data *Currency) error {
+	query := `UPDATE ` + m.table + ` SET name = $1, precision = $2, deposit_enabled = $3, withdraw_enabled = $4, max_deposit = $5, max_withdraw = $6, status = $7, updated_at = $8 WHERE code = $9`
+	_, err := m.conn.ExecCtx(ctx, query, data.Name, data.Precision, data.DepositEnabled, data.WithdrawEnabled, data.MaxDeposit, data.MaxWithdraw, data.Status, data.UpdatedAt, data.Code)
+	return err
+}
+
+func (m *defaultCurrencyModel) Delete(ctx context.Context, code string) error {
+	query := `DELETE FROM ` + m.table + ` WHERE code = $1`
+	_, err := m.conn.ExecCtx(ctx, query, code)
+	return err
+}
@@ -0,0 +1,160 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ CurrencyNetworkModel = (*customCurrencyNetworkModel)(nil)
+
+// 地址格式 / Address Types
+const (
+	AddressTypeBitcoin = "bitcoin" // 比特币地址：Base58Check（1/3开头）或bech32（bc1开头）
+	AddressTypeEVM     = "evm"     // 以太坊兼容链地址：0x开头的40位十六进制
+	AddressTypeTron    = "tron"    // 波场地址：T开头的Base58Check
+)
+
+type (
+	// CurrencyNetworkModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customCurrencyNetworkModel.
+	CurrencyNetworkModel interface {
+		currencyNetworkModel
+		// 自定义方法
+		FindAll(ctx context.Context) ([]*CurrencyNetwork, error)
+		FindByCurrency(ctx context.Context, currency string) ([]*CurrencyNetwork, error)
+		FindByCurrencyAndNetwork(ctx context.Context, currency, network string) (*CurrencyNetwork, error)
+		DeleteByCurrency(ctx context.Context, currency string) error
+	}
+
+	customCurrencyNetworkModel struct {
+		*defaultCurrencyNetworkModel
+	}
+
+	// CurrencyNetwork 币种在某个网络上的充提配置，如USDT在ERC20和TRC20上的手续费、最小金额和确认数各不相同
+	CurrencyNetwork struct {
+		ID               uint64    `db:"id"`                 // 记录ID，主键
+		Currency         string    `db:"currency"`           // 币种代码，关联currencies表
+		Network          string    `db:"network"`            // 网络代码，如ERC20、TRC20
+		AddressType      string    `db:"address_type"`       // 地址格式：bitcoin/evm/tron
+		DepositEnabled   bool      `db:"deposit_enabled"`    // 该网络是否开放充值
+		WithdrawEnabled  bool      `db:"withdraw_enabled"`   // 该网络是否开放提现
+		WithdrawFeeFixed string    `db:"withdraw_fee_fixed"` // 提现固定手续费
+		WithdrawFeeRate  string    `db:"withdraw_fee_rate"`  // 提现比例手续费，如0.001表示0.1%
+		MinDeposit       string    `db:"min_deposit"`        // 最小充值金额
+		MinWithdraw      string    `db:"min_withdraw"`       // 最小提现金额
+		Confirmations    int64     `db:"confirmations"`      // 充值所需区块确认数
+		CreatedAt        time.Time `db:"created_at"`         // 创建时间
+		UpdatedAt        time.Time `db:"updated_at"`         // 更新时间
+	}
+
+	currencyNetworkModel interface {
+		Insert(ctx context.Context, data *CurrencyNetwork) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*CurrencyNetwork, error)
+		Update(ctx context.Context, data *CurrencyNetwork) error
+		Delete(ctx context.Context, id uint64) error
+	}
+
+	defaultCurrencyNetworkModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewCurrencyNetworkModel returns a model for the database table.
+func NewCurrencyNetworkModel(conn sqlx.SqlConn) CurrencyNetworkModel {
+	return &customCurrencyNetworkModel{
+		defaultCurrencyNetworkModel: newCurrencyNetworkModel(conn),
+	}
+}
+
+func newCurrencyNetworkModel(conn sqlx.SqlConn) *defaultCurrencyNetworkModel {
+	return &defaultCurrencyNetworkModel{
+		conn:  conn,
+		table: "currency_networks",
+	}
+}
+
+func (m *defaultCurrencyNetworkModel) Insert(ctx context.Context, data *CurrencyNetwork) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (currency, network, address_type, deposit_enabled, withdraw_enabled, withdraw_fee_fixed, withdraw_fee_rate, min_deposit, min_withdraw, confirmations, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.Currency, data.Network, data.AddressType, data.DepositEnabled, data.WithdrawEnabled, data.WithdrawFeeFixed, data.WithdrawFeeRate, data.MinDeposit, data.MinWithdraw, data.Confirmations, data.CreatedAt, data.UpdatedAt)
+	return ret, err
+}
+
+func (m *defaultCurrencyNetworkModel) FindOne(ctx context.Context, id uint64) (*CurrencyNetwork, error) {
+	query := `SELECT id, currency, network, address_type, deposit_enabled, withdraw_enabled, withdraw_fee_fixed, withdraw_fee_rate, min_deposit, min_withdraw, confirmations, created_at, updated_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp CurrencyNetwork
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindAll 查询全部币种网络配置，按币种和网络排序
+func (m *customCurrencyNetworkModel) FindAll(ctx context.Context) ([]*CurrencyNetwork, error) {
+	query := `SELECT id, currency, network, address_type, deposit_enabled, withdraw_enabled, withdraw_fee_fixed, withdraw_fee_rate, min_deposit, min_withdraw, confirmations, created_at, updated_at FROM ` + m.table + ` ORDER BY currency ASC, network ASC`
+	var resp []*CurrencyNetwork
+	err := m.conn.QueryRowsCtx(ctx, &resp, query)
+	return resp, err
+}
+
+// FindByCurrency 查询币种支持的全部网络
+func (m *customCurrencyNetworkModel) FindByCurrency(ctx context.Context, currency string) ([]*CurrencyNetwork, error) {
+	query := `SELECT id, currency, network, address_type, deposit_enabled, withdraw_enabled, withdraw_fee_fixed, withdraw_fee_rate, min_deposit, min_withdraw, confirmations, created_at, updated_at FROM ` + m.table + ` WHERE currency = $1 ORDER BY network ASC`
+	var resp []*CurrencyNetwork
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, currency)
+	return resp, err
+}
+
+func (m *customCurrencyNetworkModel) FindByCurrencyAndNetwork(ctx context.Context, currency, network string) (*CurrencyNetwork, error) {
+	query := `SELECT id, currency, network, address_type, deposit_enabled, withdraw_enabled, withdraw_fee_fixed, withdraw_fee_rate, min_deposit, min_withdraw, confirmations, created_at, updated_at FROM ` + m.table + ` WHERE currency = $1 AND network = $2 LIMIT 1`
+	var resp CurrencyNetwork
+	err := m.conn.QueryRowCtx(ctx, &resp, query, currency, network)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultCurrencyNetworkModel) Update(ctx context.Context, data *CurrencyNetwork) error {
+	query := `UPDATE ` + m.table + ` SET address_type = $1, deposit_enabled = $2, withdraw_enabled = $3, withdraw_fee_fixed = $4, withdraw_fee_rate = $5, min_deposit = $6, min_withdraw = $7, confirmations = $8, updated_at = $9 WHERE id = $10`
+	_, err := m.conn.ExecCtx(ctx, query, data.AddressType, data.DepositEnabled, data.WithdrawEnabled, data.WithdrawFeeFixed, data.WithdrawFeeRate, data.MinDeposit, data.MinWithdraw, data.Confirmations, data.UpdatedAt, data.ID)
+	return err
+}
+
+func (m *defaultCurrencyNetworkModel) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM ` + m.table + ` WHERE id = $1`
+	_, err := m.conn.ExecCtx(ctx, query, id)
+	return err
+}
+
+// DeleteByCurrency 删除币种的全部网络配置，删除币种时使用
+func (m *customCurrencyNetworkModel) DeleteByCurrency(ctx context.Context, currency string) error {
+	query := `DELETE FROM ` + m.table + ` WHERE currency = $1`
+	_, err := m.conn.ExecCtx(ctx, query, currency)
+	return err
+}
+
+// WithdrawFee 计算提现手续费：固定手续费 + 提现金额 × 比例手续费，按币种精度向上取整
+func (n *CurrencyNetwork) WithdrawFee(amount decimal.Decimal, precision int64) decimal.Decimal {
+	fee := decimal.Zero
+	if fixed, err := decimal.NewFromString(n.WithdrawFeeFixed); err == nil {
+		fee = fee.Add(fixed)
+	}
+	if rate, err := decimal.NewFromString(n.WithdrawFeeRate); err == nil {
+		fee = fee.Add(amount.Mul(rate))
+	}
+	return fee.RoundUp(int32(precision))
+}
@@ -0,0 +1,147 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ DepositAddressModel = (*customDepositAddressModel)(nil)
+
+type (
+	// DepositAddressModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customDepositAddressModel.
+	DepositAddressModel interface {
+		depositAddressModel
+		// 自定义方法
+		FindByUserAndCurrency(ctx context.Context, userID uint64, currency, network string) (*DepositAddress, error)
+		FindByNetworkAndAddress(ctx context.Context, network, address string) (*DepositAddress, error)
+		ClaimFromPool(ctx context.Context, userID uint64, currency, network string) (*DepositAddress, error)
+		MaxDerivationIndex(ctx context.Context, network string) (int64, error)
+		CountPoolAvailable(ctx context.Context, network string) (int64, error)
+	}
+
+	customDepositAddressModel struct {
+		*defaultDepositAddressModel
+	}
+
+	// DepositAddress 充值地址模型：每个用户每个币种每个网络一个地址
+	// 地址来自预生成的地址池（UserID为0表示未分配）或按派生序号由链适配器生成
+	DepositAddress struct {
+		ID              uint64       `db:"id"`               // 地址记录ID，主键
+		UserID          uint64       `db:"user_id"`          // 用户ID，0表示地址池中未分配的地址
+		Currency        string       `db:"currency"`         // 币种代码，未分配时为空
+		Network         string       `db:"network"`          // 网络代码
+		Address         string       `db:"address"`          // 链上地址
+		DerivationIndex int64        `db:"derivation_index"` // 派生序号，地址池导入的地址为-1
+		CreatedAt       time.Time    `db:"created_at"`       // 创建时间
+		AssignedAt      sql.NullTime `db:"assigned_at"`      // 分配给用户的时间，地址池中未分配时为空
+	}
+
+	depositAddressModel interface {
+		Insert(ctx context.Context, data *DepositAddress) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*DepositAddress, error)
+	}
+
+	defaultDepositAddressModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewDepositAddressModel returns a model for the database table.
+func NewDepositAddressModel(conn sqlx.SqlConn) DepositAddressModel {
+	return &customDepositAddressModel{
+		defaultDepositAddressModel: newDepositAddressModel(conn),
+	}
+}
+
+func newDepositAddressModel(conn sqlx.SqlConn) *defaultDepositAddressModel {
+	return &defaultDepositAddressModel{
+		conn:  conn,
+		table: "deposit_addresses",
+	}
+}
+
+func (m *defaultDepositAddressModel) Insert(ctx context.Context, data *DepositAddress) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (user_id, currency, network, address, derivation_index, created_at, assigned_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.UserID, data.Currency, data.Network, data.Address, data.DerivationIndex, data.CreatedAt, data.AssignedAt)
+	return ret, err
+}
+
+func (m *defaultDepositAddressModel) FindOne(ctx context.Context, id uint64) (*DepositAddress, error) {
+	query := `SELECT id, user_id, currency, network, address, derivation_index, created_at, assigned_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp DepositAddress
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *customDepositAddressModel) FindByUserAndCurrency(ctx context.Context, userID uint64, currency, network string) (*DepositAddress, error) {
+	query := `SELECT id, user_id, currency, network, address, derivation_index, created_at, assigned_at FROM ` + m.table + ` WHERE user_id = $1 AND currency = $2 AND network = $3 LIMIT 1`
+	var resp DepositAddress
+	err := m.conn.QueryRowCtx(ctx, &resp, query, userID, currency, network)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindByNetworkAndAddress 按链上地址查找已分配的充值地址，用于识别入账转账的归属用户
+func (m *customDepositAddressModel) FindByNetworkAndAddress(ctx context.Context, network, address string) (*DepositAddress, error) {
+	query := `SELECT id, user_id, currency, network, address, derivation_index, created_at, assigned_at FROM ` + m.table + ` WHERE network = $1 AND address = $2 AND user_id > 0 LIMIT 1`
+	var resp DepositAddress
+	err := m.conn.QueryRowCtx(ctx, &resp, query, network, address)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// ClaimFromPool 从地址池中取出一个未分配的地址分配给用户，地址池为空时返回ErrNotFound
+// 使用SKIP LOCKED保证并发分配时同一地址不会分给两个用户
+func (m *customDepositAddressModel) ClaimFromPool(ctx context.Context, userID uint64, currency, network string) (*DepositAddress, error) {
+	query := `UPDATE ` + m.table + ` SET user_id = $1, currency = $2, assigned_at = $3 WHERE id = (SELECT id FROM ` + m.table + ` WHERE network = $4 AND user_id = 0 ORDER BY id ASC LIMIT 1 FOR UPDATE SKIP LOCKED) RETURNING id, user_id, currency, network, address, derivation_index, created_at, assigned_at`
+	var resp DepositAddress
+	err := m.conn.QueryRowCtx(ctx, &resp, query, userID, currency, time.Now(), network)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// MaxDerivationIndex 查询网络已使用的最大派生序号，没有派生地址时返回-1
+func (m *customDepositAddressModel) MaxDerivationIndex(ctx context.Context, network string) (int64, error) {
+	query := `SELECT COALESCE(MAX(derivation_index), -1) FROM ` + m.table + ` WHERE network = $1`
+	var index int64
+	err := m.conn.QueryRowCtx(ctx, &index, query, network)
+	return index, err
+}
+
+// CountPoolAvailable 统计网络地址池中未分配的地址数量
+func (m *customDepositAddressModel) CountPoolAvailable(ctx context.Context, network string) (int64, error) {
+	query := `SELECT COUNT(*) FROM ` + m.table + ` WHERE network = $1 AND user_id = 0`
+	var count int64
+	err := m.conn.QueryRowCtx(ctx, &count, query, network)
+	return count, err
+}
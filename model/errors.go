@@ -1,6 +1,10 @@
 package model
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 // 通用错误 / Common Errors
 var (
@@ -22,10 +26,11 @@ var (
 
 // 资产相关错误 / Asset Related Errors
 var (
-	ErrInsufficientBalance = errors.New("insufficient balance")
-	ErrInvalidAmount       = errors.New("invalid amount")
-	ErrCurrencyNotFound    = errors.New("currency not found")
-	ErrBalanceNotFound     = errors.New("balance not found")
+	ErrInsufficientBalance       = errors.New("insufficient balance")
+	ErrInsufficientFrozenBalance = errors.New("insufficient frozen balance")
+	ErrInvalidAmount             = errors.New("invalid amount")
+	ErrCurrencyNotFound          = errors.New("currency not found")
+	ErrBalanceNotFound           = errors.New("balance not found")
 )
 
 // 交易相关错误 / Trading Related Errors
@@ -37,6 +42,18 @@ var (
 	ErrTradingPairDisabled = errors.New("trading pair is disabled")
 	ErrOrderAlreadyCanceled = errors.New("order already canceled")
 	ErrOrderAlreadyFilled   = errors.New("order already filled")
+	ErrTradingPairNotOpen    = errors.New("trading pair is not open for trading yet")
+	ErrTradingPairCancelOnly = errors.New("trading pair only accepts cancellations")
+	ErrTradingPairPostOnly   = errors.New("trading pair only accepts post-only limit orders")
+	ErrTradingPairDelisted   = errors.New("trading pair has been delisted")
+	ErrOrderWouldMatch       = errors.New("post-only order would immediately match")
+	ErrCancelNotAllowed      = errors.New("order cancellation is not allowed for current trading pair status")
+	ErrInvalidStatusTransition = errors.New("invalid trading pair status transition")
+	ErrPriceTickSize           = errors.New("price is not a multiple of tick size")
+	ErrAmountStepSize          = errors.New("amount is not a multiple of step size")
+	ErrMinNotional             = errors.New("order notional is below minimum")
+	ErrTooManyOpenOrders       = errors.New("too many open orders on trading pair")
+	ErrVerificationLevelTooLow = errors.New("verification level is too low to trade this pair")
 )
 
 // 市场数据相关错误 / Market Data Related Errors
@@ -44,4 +61,187 @@ var (
 	ErrInvalidTimeRange = errors.New("invalid time range")
 	ErrInvalidInterval  = errors.New("invalid interval")
 	ErrNoMarketData     = errors.New("no market data available")
-)
\ No newline at end of file
+)
+// 储备金证明相关错误 / Proof of Reserves Related Errors
+var (
+	ErrReserveSnapshotNotFound = errors.New("proof of reserves snapshot not found")
+	ErrReserveProofNotFound    = errors.New("no proof of reserves leaf for user in latest snapshot")
+)
+// 币种相关错误 / Currency Related Errors
+var (
+	ErrCurrencyExists      = errors.New("currency already exists")
+	ErrCurrencyDisabled    = errors.New("currency is disabled")
+	ErrCurrencyInUse       = errors.New("currency is in use by trading pairs or balances, disable it instead")
+	ErrDepositDisabled     = errors.New("deposit is disabled for currency")
+	ErrWithdrawDisabled    = errors.New("withdraw is disabled for currency")
+	ErrNetworkRequired     = errors.New("network is required for currency with multiple networks")
+	ErrNetworkNotSupported = errors.New("network is not supported for currency")
+	ErrNetworkExists       = errors.New("network already exists for currency")
+	ErrInvalidAddress      = errors.New("invalid address for network")
+)
+
+// 提现审核相关错误 / Withdrawal Review Related Errors
+var (
+	ErrWithdrawalNotFound          = errors.New("withdrawal not found")
+	ErrInvalidWithdrawalTransition = errors.New("invalid withdrawal status transition")
+	ErrWithdrawalStatusChanged     = errors.New("withdrawal status has been changed by another request")
+)
+
+// 提现地址及确认相关错误 / Withdrawal Address and Confirmation Related Errors
+var (
+	ErrWithdrawAddressNotFound       = errors.New("withdrawal address not found")
+	ErrWithdrawAddressExists         = errors.New("withdrawal address already exists")
+	ErrWithdrawAddressNotWhitelisted = errors.New("withdrawal address is not in the address book")
+	ErrWithdrawAddressCoolingDown    = errors.New("withdrawal address is still in cooldown period")
+	ErrWithdrawConfirmationInvalid   = errors.New("withdrawal confirmation code is invalid")
+	ErrWithdrawConfirmationExpired   = errors.New("withdrawal confirmation code has expired, withdrawal cancelled")
+)
+
+// 站内转账相关错误 / Internal Transfer Related Errors
+var (
+	ErrTransferToSelf              = errors.New("cannot transfer to yourself")
+	ErrTransferRecipientRequired   = errors.New("exactly one of recipient user ID or email is required")
+	ErrTransferIdempotencyConflict = errors.New("client transfer ID was already used with different parameters")
+	ErrTransferDailyAmountExceeded = errors.New("daily transfer amount limit exceeded")
+	ErrTransferDailyCountExceeded  = errors.New("daily transfer count limit exceeded")
+)
+
+// 子账户相关错误 / Sub-Account Related Errors
+var (
+	ErrSubAccountNotFound       = errors.New("sub-account not found")
+	ErrSubAccountNotAllowed     = errors.New("sub-accounts cannot manage sub-accounts")
+	ErrSubAccountLimitExceeded  = errors.New("sub-account limit exceeded")
+	ErrSubAccountDisabled       = errors.New("sub-account has been disabled")
+	ErrSubAccountDeleted        = errors.New("sub-account has been deleted")
+	ErrSubAccountFrozenByAdmin  = errors.New("sub-account was frozen by an administrator")
+	ErrInvalidSubAccountStatus  = errors.New("invalid sub-account status")
+	ErrInvalidTransferDirection = errors.New("invalid transfer direction")
+)
+
+// API密钥相关错误 / API Key Related Errors
+var (
+	ErrApiKeyNotFound          = errors.New("api key not found")
+	ErrApiKeyLimitExceeded     = errors.New("api key limit exceeded")
+	ErrInvalidApiKeyPermission = errors.New("invalid api key permission")
+	ErrInvalidIPWhitelist      = errors.New("invalid ip whitelist entry")
+	ErrApiKeyInvalid           = errors.New("api key is invalid or has been revoked")
+	ErrApiKeyExpired           = errors.New("api key has expired")
+	ErrApiKeySignatureInvalid  = errors.New("api signature is invalid")
+	ErrApiKeyTimestampInvalid  = errors.New("api request timestamp is outside the recv window")
+	ErrApiKeyReplayed          = errors.New("api request has already been processed")
+	ErrApiKeyIPNotAllowed      = errors.New("request ip is not in the api key whitelist")
+	ErrApiKeyPermissionDenied  = errors.New("api key does not have permission for this request")
+)
+
+// 两步验证相关错误 / Two-Factor Related Errors
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor enrollment not found")
+	ErrTwoFactorCodeRequired   = errors.New("two-factor code is required")
+	ErrInvalidTwoFactorCode    = errors.New("invalid or already used two-factor code")
+	ErrInvalidPreAuthToken     = errors.New("invalid or expired pre-auth token")
+)
+
+// 邮箱验证和重置密码相关错误 / Email Verification and Password Reset Related Errors
+var (
+	ErrEmailNotVerified       = errors.New("email address is not verified")
+	ErrEmailAlreadyVerified   = errors.New("email address is already verified")
+	ErrInvalidEmailToken      = errors.New("invalid or expired email token")
+	ErrEmailSendTooFrequently = errors.New("email was sent recently, please try again later")
+)
+
+// 身份认证（KYC）相关错误 / KYC Related Errors
+var (
+	ErrKycSubmissionNotFound    = errors.New("kyc submission not found")
+	ErrKycSubmissionPending     = errors.New("a kyc submission is already pending review")
+	ErrKycSubmissionReviewed    = errors.New("kyc submission has already been reviewed")
+	ErrInvalidVerificationLevel = errors.New("invalid verification level")
+	ErrInvalidKycDocument       = errors.New("kyc documents must be jpeg, png or pdf files within the size limit")
+	ErrKycDocumentNotFound      = errors.New("kyc document not found")
+	ErrCannotReviewOwnKyc       = errors.New("cannot review your own kyc submission or verification level")
+	ErrVerificationLevelChanged = errors.New("verification level was changed by another request")
+)
+
+// 登录会话相关错误 / Session Related Errors
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, session has been revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionRevoked      = errors.New("session has been revoked, please log in again")
+)
+
+// 角色权限相关错误 / Role Related Errors
+var (
+	ErrInvalidRole           = errors.New("invalid role")
+	ErrRoleAlreadyGranted    = errors.New("role already granted")
+	ErrRoleNotGranted        = errors.New("role not granted")
+	ErrCannotModifyOwnRoles  = errors.New("cannot modify your own roles")
+	ErrAdminPermissionDenied = errors.New("admin permission denied")
+)
+
+// 账户限制相关错误 / Account Restriction Related Errors
+var (
+	ErrAccountRestricted        = errors.New("account is restricted")
+	ErrInvalidRestrictionType   = errors.New("invalid restriction type")
+	ErrRestrictionNotFound      = errors.New("restriction not found")
+	ErrRestrictionReason        = errors.New("restriction reason is required")
+	ErrInvalidRestrictionExpiry = errors.New("restriction expiry must be in the future")
+	ErrCannotRestrictSelf       = errors.New("cannot restrict your own account")
+)
+
+// 限流相关错误 / Rate Limit Related Errors
+var (
+	ErrRateLimitExceeded      = errors.New("too many requests, rate limit exceeded")
+	ErrOrderRateLimitExceeded = errors.New("too many orders, order rate limit exceeded")
+)
+
+// 提现限额相关错误 / Withdrawal Limit Related Errors
+var (
+	ErrWithdrawLimitExceeded = errors.New("withdrawal limit exceeded")
+	ErrPriceUnavailable      = errors.New("reference price unavailable")
+)
+
+// 提现限额拒绝原因代码，供客户端按原因提示用户
+const (
+	WithdrawLimitReasonDailyAmount      = "DAILY_AMOUNT_EXCEEDED"     // 24小时内累计提现金额超过等级限额
+	WithdrawLimitReasonDailyCount       = "DAILY_COUNT_EXCEEDED"      // 24小时内提现次数超过等级限制
+	WithdrawLimitReasonHourlyCount      = "HOURLY_COUNT_EXCEEDED"     // 1小时内提现次数超过等级限制
+	WithdrawLimitReasonPasswordChanged  = "PASSWORD_RECENTLY_CHANGED" // 修改密码后的锁定期内禁止提现
+	WithdrawLimitReasonPriceUnavailable = "PRICE_UNAVAILABLE"         // 无法按参考币种估值，无法校验限额
+	WithdrawLimitReasonVerification     = "VERIFICATION_REQUIRED"     // 认证等级低于提现所需的最低等级
+)
+
+// WithdrawLimitError 提现被限额或频率规则拒绝，Reason为机器可读的拒绝原因代码
+// errors.Is(err, ErrWithdrawLimitExceeded) 可判断是否为限额拒绝
+type WithdrawLimitError struct {
+	Reason string
+	Detail string
+}
+
+func (e *WithdrawLimitError) Error() string {
+	return fmt.Sprintf("%s [%s]: %s", ErrWithdrawLimitExceeded.Error(), e.Reason, e.Detail)
+}
+
+func (e *WithdrawLimitError) Is(target error) bool {
+	return target == ErrWithdrawLimitExceeded
+}
+
+// AccountRestrictedError 操作因账户限制被拒绝，Type为生效的限制类型，ExpiresAt为零值时表示直到解除
+// errors.Is(err, ErrAccountRestricted) 可判断是否为账户限制；限制原因仅管理员可见，不返回给用户
+type AccountRestrictedError struct {
+	Type      string
+	ExpiresAt time.Time
+}
+
+func (e *AccountRestrictedError) Error() string {
+	until := "until further notice"
+	if !e.ExpiresAt.IsZero() {
+		until = "until " + e.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf("%s [%s]: %s", ErrAccountRestricted.Error(), e.Type, until)
+}
+
+func (e *AccountRestrictedError) Is(target error) bool {
+	return target == ErrAccountRestricted
+}
@@ -0,0 +1,256 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ KycSubmissionModel = (*customKycSubmissionModel)(nil)
+
+// 身份认证申请状态 / KYC Submission Status
+const (
+	KycStatusPending  int64 = 1 // 待审核
+	KycStatusApproved int64 = 2 // 已通过
+	KycStatusRejected int64 = 3 // 已拒绝
+)
+
+// 证件类型 / KYC Document Types
+const (
+	KycDocumentIDCard        = "id_card"        // 身份证
+	KycDocumentPassport      = "passport"       // 护照
+	KycDocumentDriverLicense = "driver_license" // 驾驶证
+)
+
+type (
+	// KycSubmissionModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customKycSubmissionModel.
+	KycSubmissionModel interface {
+		kycSubmissionModel
+		// 自定义方法
+		Create(ctx context.Context, data *KycSubmission) (uint64, error)
+		FindLatestByUserID(ctx context.Context, userID uint64) (*KycSubmission, error)
+		FindByStatus(ctx context.Context, status int64, page, size int64) ([]*KycSubmission, int64, error)
+		Approve(ctx context.Context, id, reviewerID uint64, reason string, reviewedAt time.Time) (*VerificationLevelChange, error)
+		Reject(ctx context.Context, id, reviewerID uint64, reason string, reviewedAt time.Time) error
+	}
+
+	customKycSubmissionModel struct {
+		*defaultKycSubmissionModel
+	}
+
+	// KycSubmission 用户提交的身份认证申请，证件文件保存在文件存储中，这里只记录文件信息
+	KycSubmission struct {
+		ID             uint64       `db:"id"`              // 主键
+		UserID         uint64       `db:"user_id"`         // 申请用户ID
+		Level          int64        `db:"level"`           // 申请的认证等级：1-基础认证，2-高级认证
+		FullName       string       `db:"full_name"`       // 证件上的姓名
+		Country        string       `db:"country"`         // 国家或地区代码（ISO 3166-1 alpha-2）
+		DocumentType   string       `db:"document_type"`   // 证件类型：id_card、passport、driver_license
+		DocumentNumber string       `db:"document_number"` // 证件号码
+		Documents      string       `db:"documents"`       // 证件文件列表（JSON）
+		Status         int64        `db:"status"`          // 状态：1-待审核，2-已通过，3-已拒绝
+		ReviewerID     uint64       `db:"reviewer_id"`     // 审核人用户ID，未审核时为0
+		ReviewReason   string       `db:"review_reason"`   // 审核意见
+		ReviewedAt     sql.NullTime `db:"reviewed_at"`     // 审核时间
+		CreatedAt      time.Time    `db:"created_at"`      // 提交时间
+		UpdatedAt      time.Time    `db:"updated_at"`      // 更新时间
+	}
+
+	// KycDocument 证件文件信息
+	KycDocument struct {
+		Key         string `json:"key"`          // 文件存储中的key
+		FileName    string `json:"file_name"`    // 上传时的文件名
+		ContentType string `json:"content_type"` // 文件类型
+		Size        int64  `json:"size"`         // 文件大小（字节）
+	}
+
+	kycSubmissionModel interface {
+		Insert(ctx context.Context, data *KycSubmission) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*KycSubmission, error)
+	}
+
+	defaultKycSubmissionModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// IsValidKycDocumentType 判断证件类型是否有效
+func IsValidKycDocumentType(documentType string) bool {
+	switch documentType {
+	case KycDocumentIDCard, KycDocumentPassport, KycDocumentDriverLicense:
+		return true
+	default:
+		return false
+	}
+}
+
+// DocumentList 解析证件文件列表
+func (s *KycSubmission) DocumentList() ([]KycDocument, error) {
+	var docs []KycDocument
+	if s.Documents == "" {
+		return docs, nil
+	}
+	err := json.Unmarshal([]byte(s.Documents), &docs)
+	return docs, err
+}
+
+// SetDocuments 设置证件文件列表
+func (s *KycSubmission) SetDocuments(docs []KycDocument) error {
+	data, err := json.Marshal(docs)
+	if err != nil {
+		return err
+	}
+	s.Documents = string(data)
+	return nil
+}
+
+// NewKycSubmissionModel returns a model for the database table.
+func NewKycSubmissionModel(conn sqlx.SqlConn) KycSubmissionModel {
+	return &customKycSubmissionModel{
+		defaultKycSubmissionModel: newKycSubmissionModel(conn),
+	}
+}
+
+func newKycSubmissionModel(conn sqlx.SqlConn) *defaultKycSubmissionModel {
+	return &defaultKycSubmissionModel{
+		conn:  conn,
+		table: "kyc_submissions",
+	}
+}
+
+const kycSubmissionFields = `id, user_id, level, full_name, country, document_type, document_number, documents, status, reviewer_id, review_reason, reviewed_at, created_at, updated_at`
+
+func (m *defaultKycSubmissionModel) Insert(ctx context.Context, data *KycSubmission) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (user_id, level, full_name, country, document_type, document_number, documents, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.UserID, data.Level, data.FullName, data.Country, data.DocumentType, data.DocumentNumber, data.Documents, data.Status, data.CreatedAt, data.UpdatedAt)
+	return ret, err
+}
+
+func (m *defaultKycSubmissionModel) FindOne(ctx context.Context, id uint64) (*KycSubmission, error) {
+	query := `SELECT ` + kycSubmissionFields + ` FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp KycSubmission
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// Create 创建待审核的认证申请并返回申请ID，用户已有待审核的申请时返回ErrKycSubmissionPending
+func (m *customKycSubmissionModel) Create(ctx context.Context, data *KycSubmission) (uint64, error) {
+	query := `INSERT INTO ` + m.table + ` (user_id, level, full_name, country, document_type, document_number, documents, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) ON CONFLICT (user_id) WHERE status = 1 DO NOTHING RETURNING id`
+	var id uint64
+	err := m.conn.QueryRowCtx(ctx, &id, query, data.UserID, data.Level, data.FullName, data.Country, data.DocumentType, data.DocumentNumber, data.Documents, KycStatusPending, data.CreatedAt, data.UpdatedAt)
+	switch err {
+	case nil:
+		return id, nil
+	case sqlx.ErrNotFound:
+		return 0, ErrKycSubmissionPending
+	default:
+		return 0, err
+	}
+}
+
+// FindLatestByUserID 查询用户最近一次提交的认证申请
+func (m *customKycSubmissionModel) FindLatestByUserID(ctx context.Context, userID uint64) (*KycSubmission, error) {
+	query := `SELECT ` + kycSubmissionFields + ` FROM ` + m.table + ` WHERE user_id = $1 ORDER BY id DESC LIMIT 1`
+	var resp KycSubmission
+	err := m.conn.QueryRowCtx(ctx, &resp, query, userID)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindByStatus 分页查询指定状态的认证申请，按提交时间先后排序，便于按队列顺序审核
+func (m *customKycSubmissionModel) FindByStatus(ctx context.Context, status int64, page, size int64) ([]*KycSubmission, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM ` + m.table + ` WHERE status = $1`
+	if err := m.conn.QueryRowCtx(ctx, &total, countQuery, status); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * size
+	query := `SELECT ` + kycSubmissionFields + ` FROM ` + m.table + ` WHERE status = $1 ORDER BY id ASC LIMIT $2 OFFSET $3`
+	var resp []*KycSubmission
+	if err := m.conn.QueryRowsCtx(ctx, &resp, query, status, size, offset); err != nil {
+		return nil, 0, err
+	}
+	return resp, total, nil
+}
+
+// Approve 审核通过认证申请，在同一事务中提升用户认证等级并写入等级变更记录
+// 用户当前等级已不低于申请等级时只更新申请状态，返回的变更记录为nil；申请已被审核时返回ErrKycSubmissionReviewed
+func (m *customKycSubmissionModel) Approve(ctx context.Context, id, reviewerID uint64, reason string, reviewedAt time.Time) (*VerificationLevelChange, error) {
+	var change *VerificationLevelChange
+	err := m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		submission, err := m.review(ctx, session, id, KycStatusApproved, reviewerID, reason, reviewedAt)
+		if err != nil {
+			return err
+		}
+
+		var current int64
+		query := `SELECT verification_level FROM users WHERE id = $1 FOR UPDATE`
+		if err := session.QueryRowCtx(ctx, &current, query, submission.UserID); err != nil {
+			return err
+		}
+		if current >= submission.Level {
+			return nil
+		}
+
+		query = `UPDATE users SET verification_level = $1, updated_at = $2 WHERE id = $3`
+		if _, err := session.ExecCtx(ctx, query, submission.Level, reviewedAt, submission.UserID); err != nil {
+			return err
+		}
+		change = &VerificationLevelChange{
+			UserID:       submission.UserID,
+			FromLevel:    current,
+			ToLevel:      submission.Level,
+			SubmissionID: submission.ID,
+			ReviewerID:   reviewerID,
+			Reason:       reason,
+			CreatedAt:    reviewedAt,
+		}
+		return insertVerificationLevelChange(ctx, session, change)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// Reject 拒绝认证申请，申请已被审核时返回ErrKycSubmissionReviewed
+func (m *customKycSubmissionModel) Reject(ctx context.Context, id, reviewerID uint64, reason string, reviewedAt time.Time) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		_, err := m.review(ctx, session, id, KycStatusRejected, reviewerID, reason, reviewedAt)
+		return err
+	})
+}
+
+// review 将待审核的申请更新为审核结果并返回更新后的申请
+func (m *customKycSubmissionModel) review(ctx context.Context, session sqlx.Session, id uint64, status int64, reviewerID uint64, reason string, reviewedAt time.Time) (*KycSubmission, error) {
+	query := `UPDATE ` + m.table + ` SET status = $1, reviewer_id = $2, review_reason = $3, reviewed_at = $4, updated_at = $4 WHERE id = $5 AND status = $6 RETURNING ` + kycSubmissionFields
+	var resp KycSubmission
+	err := session.QueryRowCtx(ctx, &resp, query, status, reviewerID, reason, reviewedAt, id, KycStatusPending)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrKycSubmissionReviewed
+	default:
+		return nil, err
+	}
+}
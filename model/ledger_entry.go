@@ -0,0 +1,301 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ LedgerEntryModel = (*customLedgerEntryModel)(nil)
+
+// 账本科目 / Ledger Account
+// 用户科目记录交易所对用户的负债，贷方增加、借方减少；系统科目为对应的对手方
+const (
+	LedgerAccountAvailable int64 = 1 // 用户可用余额
+	LedgerAccountFrozen    int64 = 2 // 用户冻结余额
+	LedgerAccountExternal  int64 = 3 // 系统外部科目：链上充提的对手方
+	LedgerAccountFee       int64 = 4 // 系统手续费收入
+)
+
+// 记账方向 / Entry Direction
+const (
+	LedgerDirectionDebit  int64 = 1 // 借方
+	LedgerDirectionCredit int64 = 2 // 贷方
+)
+
+// 业务类型 / Ledger Business Type
+const (
+	LedgerBizDeposit  int64 = 1 // 充值，业务ID为交易ID
+	LedgerBizWithdraw int64 = 2 // 提现（含手续费），业务ID为交易ID
+	LedgerBizFreeze   int64 = 3 // 下单冻结，业务ID为订单ID
+	LedgerBizUnfreeze int64 = 4 // 撤单、拒单或成交后解冻，业务ID为订单ID
+	LedgerBizTrade    int64 = 5 // 成交结算，业务ID为成交记录ID
+	LedgerBizTransfer int64 = 6 // 站内转账，业务ID为转账ID
+)
+
+type (
+	// LedgerEntryModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customLedgerEntryModel.
+	LedgerEntryModel interface {
+		ledgerEntryModel
+		// 自定义方法
+		InsertJournal(ctx context.Context, journal *LedgerJournal) error
+		FindByJournalID(ctx context.Context, journalID string) ([]*LedgerEntry, error)
+		// 分页查询方法
+		FindByUserIDWithPagination(ctx context.Context, userID uint64, currency string, page, size int64) ([]*LedgerEntry, int64, error)
+		Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error
+		WithSession(session sqlx.Session) LedgerEntryModel
+	}
+
+	customLedgerEntryModel struct {
+		*defaultLedgerEntryModel
+	}
+
+	// LedgerEntry 账本分录模型，只追加不修改；同一凭证下的分录借贷平衡
+	LedgerEntry struct {
+		ID        uint64    `db:"id"`         // 分录ID，主键
+		JournalID string    `db:"journal_id"` // 记账凭证ID，同一业务事件的分录共享
+		UserID    uint64    `db:"user_id"`    // 用户ID，系统科目为0
+		Currency  string    `db:"currency"`   // 币种代码
+		Account   int64     `db:"account"`    // 科目：1-用户可用，2-用户冻结，3-系统外部，4-手续费收入
+		Direction int64     `db:"direction"`  // 方向：1-借方，2-贷方
+		Amount    string    `db:"amount"`     // 金额，始终为正数
+		BizType   int64     `db:"biz_type"`   // 业务类型：1-充值，2-提现，3-冻结，4-解冻，5-成交结算，6-站内转账
+		BizID     string    `db:"biz_id"`     // 业务ID：订单ID、成交ID或交易ID
+		Remark    string    `db:"remark"`     // 备注
+		CreatedAt time.Time `db:"created_at"` // 记账时间
+	}
+
+	ledgerEntryModel interface {
+		Insert(ctx context.Context, data *LedgerEntry) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*LedgerEntry, error)
+		Update(ctx context.Context, data *LedgerEntry) error
+		Delete(ctx context.Context, id uint64) error
+	}
+
+	defaultLedgerEntryModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+
+	// LedgerJournal 记账凭证，一次业务事件产生的一组借贷平衡的分录
+	LedgerJournal struct {
+		JournalID string
+		BizType   int64
+		BizID     string
+		Entries   []*LedgerEntry
+	}
+
+	// LedgerAccountRef 记账科目引用
+	LedgerAccountRef struct {
+		UserID  uint64
+		Account int64
+	}
+)
+
+// UserAvailable 用户可用余额科目
+func UserAvailable(userID uint64) LedgerAccountRef {
+	return LedgerAccountRef{UserID: userID, Account: LedgerAccountAvailable}
+}
+
+// UserFrozen 用户冻结余额科目
+func UserFrozen(userID uint64) LedgerAccountRef {
+	return LedgerAccountRef{UserID: userID, Account: LedgerAccountFrozen}
+}
+
+// SystemExternal 系统外部科目
+func SystemExternal() LedgerAccountRef {
+	return LedgerAccountRef{Account: LedgerAccountExternal}
+}
+
+// SystemFee 系统手续费收入科目
+func SystemFee() LedgerAccountRef {
+	return LedgerAccountRef{Account: LedgerAccountFee}
+}
+
+// NewLedgerJournal 创建记账凭证
+func NewLedgerJournal(bizType int64, bizID string) *LedgerJournal {
+	return &LedgerJournal{
+		JournalID: uuid.New().String(),
+		BizType:   bizType,
+		BizID:     bizID,
+	}
+}
+
+// Transfer 记录一笔资金划转：借记from科目、贷记to科目，金额为0时忽略
+// 金额格式错误或为负数时仍会写入分录，由Validate统一拒绝
+func (j *LedgerJournal) Transfer(currency string, amount string, from, to LedgerAccountRef, remark string) {
+	if value, err := decimal.NewFromString(amount); err == nil && value.IsZero() {
+		return
+	}
+
+	j.Entries = append(j.Entries,
+		j.newEntry(currency, amount, from, LedgerDirectionDebit, remark),
+		j.newEntry(currency, amount, to, LedgerDirectionCredit, remark),
+	)
+}
+
+// Validate 校验凭证：分录金额必须为正数，且每个币种借贷平衡
+func (j *LedgerJournal) Validate() error {
+	if len(j.Entries) == 0 {
+		return errors.New("ledger journal has no entries")
+	}
+
+	sums := make(map[string]decimal.Decimal)
+	for _, entry := range j.Entries {
+		amount, err := decimal.NewFromString(entry.Amount)
+		if err != nil || !amount.IsPositive() {
+			return fmt.Errorf("invalid ledger entry amount: %s", entry.Amount)
+		}
+
+		switch entry.Direction {
+		case LedgerDirectionDebit:
+			sums[entry.Currency] = sums[entry.Currency].Add(amount)
+		case LedgerDirectionCredit:
+			sums[entry.Currency] = sums[entry.Currency].Sub(amount)
+		default:
+			return fmt.Errorf("invalid ledger entry direction: %d", entry.Direction)
+		}
+	}
+
+	for currency, sum := range sums {
+		if !sum.IsZero() {
+			return fmt.Errorf("ledger journal %s is unbalanced for %s: %s", j.JournalID, currency, sum.String())
+		}
+	}
+
+	return nil
+}
+
+func (j *LedgerJournal) newEntry(currency string, amount string, ref LedgerAccountRef, direction int64, remark string) *LedgerEntry {
+	return &LedgerEntry{
+		JournalID: j.JournalID,
+		UserID:    ref.UserID,
+		Currency:  currency,
+		Account:   ref.Account,
+		Direction: direction,
+		Amount:    amount,
+		BizType:   j.BizType,
+		BizID:     j.BizID,
+		Remark:    remark,
+	}
+}
+
+// NewLedgerEntryModel returns a model for the database table.
+func NewLedgerEntryModel(conn sqlx.SqlConn) LedgerEntryModel {
+	return &customLedgerEntryModel{
+		defaultLedgerEntryModel: newLedgerEntryModel(conn),
+	}
+}
+
+func newLedgerEntryModel(conn sqlx.SqlConn) *defaultLedgerEntryModel {
+	return &defaultLedgerEntryModel{
+		conn:  conn,
+		table: "ledger_entries",
+	}
+}
+
+func (m *defaultLedgerEntryModel) Insert(ctx context.Context, data *LedgerEntry) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (journal_id, user_id, currency, account, direction, amount, biz_type, biz_id, remark, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.JournalID, data.UserID, data.Currency, data.Account, data.Direction, data.Amount, data.BizType, data.BizID, data.Remark, data.CreatedAt)
+	return ret, err
+}
+
+func (m *defaultLedgerEntryModel) FindOne(ctx context.Context, id uint64) (*LedgerEntry, error) {
+	query := `SELECT id, journal_id, user_id, currency, account, direction, amount, biz_type, biz_id, remark, created_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp LedgerEntry
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// InsertJournal 校验凭证借贷平衡后写入全部分录，需在余额变动的同一事务中调用
+func (m *customLedgerEntryModel) InsertJournal(ctx context.Context, journal *LedgerJournal) error {
+	if err := journal.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, entry := range journal.Entries {
+		entry.CreatedAt = now
+		if _, err := m.Insert(ctx, entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *customLedgerEntryModel) FindByJournalID(ctx context.Context, journalID string) ([]*LedgerEntry, error) {
+	query := `SELECT id, journal_id, user_id, currency, account, direction, amount, biz_type, biz_id, remark, created_at FROM ` + m.table + ` WHERE journal_id = $1 ORDER BY id ASC`
+	var resp []*LedgerEntry
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, journalID)
+	return resp, err
+}
+
+// FindByUserIDWithPagination 分页查询用户的账本分录（对账单），按时间倒序
+func (m *customLedgerEntryModel) FindByUserIDWithPagination(ctx context.Context, userID uint64, currency string, page, size int64) ([]*LedgerEntry, int64, error) {
+	// 设置默认值
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = 20
+	}
+	if size > 100 {
+		size = 100 // 限制最大页面大小
+	}
+
+	whereClause := `WHERE user_id = $1`
+	args := []interface{}{userID}
+	if currency != "" {
+		whereClause += ` AND currency = $2`
+		args = append(args, currency)
+	}
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM ` + m.table + ` ` + whereClause
+	if err := m.conn.QueryRowCtx(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * size
+	query := fmt.Sprintf(`SELECT id, journal_id, user_id, currency, account, direction, amount, biz_type, biz_id, remark, created_at FROM %s %s ORDER BY id DESC LIMIT $%d OFFSET $%d`,
+		m.table, whereClause, len(args)+1, len(args)+2)
+	args = append(args, size, offset)
+
+	var resp []*LedgerEntry
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, args...)
+	return resp, total, err
+}
+
+// Update 账本只追加不修改，保留生成的方法签名以满足接口
+func (m *defaultLedgerEntryModel) Update(ctx context.Context, data *LedgerEntry) error {
+	return errors.New("ledger entries are append-only")
+}
+
+// Delete 账本只追加不删除，保留生成的方法签名以满足接口
+func (m *defaultLedgerEntryModel) Delete(ctx context.Context, id uint64) error {
+	return errors.New("ledger entries are append-only")
+}
+
+func (m *customLedgerEntryModel) Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	return m.conn.TransactCtx(ctx, fn)
+}
+
+// WithSession 返回在事务session中写入分录的模型，保证记账与余额变动一起提交或回滚
+func (m *customLedgerEntryModel) WithSession(session sqlx.Session) LedgerEntryModel {
+	return NewLedgerEntryModel(sqlx.NewSqlConnFromSession(session))
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerJournal_Transfer(t *testing.T) {
+	journal := NewLedgerJournal(LedgerBizWithdraw, "WTH_1")
+	journal.Transfer("BTC", "1.5", UserAvailable(1), SystemExternal(), "withdraw")
+	journal.Transfer("BTC", "0.0005", UserAvailable(1), SystemFee(), "withdraw fee")
+	// 金额为0的划转不产生分录
+	journal.Transfer("BTC", "0", UserAvailable(1), SystemFee(), "zero fee")
+
+	assert.Len(t, journal.Entries, 4)
+	assert.NoError(t, journal.Validate())
+
+	debit, credit := journal.Entries[0], journal.Entries[1]
+	assert.Equal(t, LedgerDirectionDebit, debit.Direction)
+	assert.Equal(t, LedgerAccountAvailable, debit.Account)
+	assert.Equal(t, uint64(1), debit.UserID)
+	assert.Equal(t, LedgerDirectionCredit, credit.Direction)
+	assert.Equal(t, LedgerAccountExternal, credit.Account)
+	assert.Equal(t, uint64(0), credit.UserID)
+	for _, entry := range journal.Entries {
+		assert.Equal(t, journal.JournalID, entry.JournalID)
+		assert.Equal(t, LedgerBizWithdraw, entry.BizType)
+		assert.Equal(t, "WTH_1", entry.BizID)
+	}
+}
+
+func TestLedgerJournal_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []*LedgerEntry
+		wantErr bool
+	}{
+		{
+			name: "multi-currency balanced",
+			entries: []*LedgerEntry{
+				{Currency: "USDT", Direction: LedgerDirectionDebit, Amount: "100"},
+				{Currency: "USDT", Direction: LedgerDirectionCredit, Amount: "100.00"},
+				{Currency: "BTC", Direction: LedgerDirectionDebit, Amount: "0.001"},
+				{Currency: "BTC", Direction: LedgerDirectionCredit, Amount: "0.001"},
+			},
+		},
+		{
+			name:    "no entries",
+			wantErr: true,
+		},
+		{
+			name: "unbalanced",
+			entries: []*LedgerEntry{
+				{Currency: "USDT", Direction: LedgerDirectionDebit, Amount: "100"},
+				{Currency: "USDT", Direction: LedgerDirectionCredit, Amount: "99.99"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "balanced total but across currencies",
+			entries: []*LedgerEntry{
+				{Currency: "USDT", Direction: LedgerDirectionDebit, Amount: "1"},
+				{Currency: "BTC", Direction: LedgerDirectionCredit, Amount: "1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative amount",
+			entries: []*LedgerEntry{
+				{Currency: "USDT", Direction: LedgerDirectionDebit, Amount: "-1"},
+				{Currency: "USDT", Direction: LedgerDirectionCredit, Amount: "-1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid direction",
+			entries: []*LedgerEntry{
+				{Currency: "USDT", Direction: 9, Amount: "1"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			journal := &LedgerJournal{JournalID: "test", Entries: tt.entries}
+			err := journal.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -3,8 +3,10 @@ package model
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 )
 
@@ -24,9 +26,15 @@ type (
 		FindBySymbolAndSideAndStatus(ctx context.Context, symbol string, side int64, status int64) ([]*Order, error)
 		// 分页查询方法
 		FindByUserIDWithPagination(ctx context.Context, userID uint64, symbol string, status int64, page, size int64) ([]*Order, int64, error)
+		FindByUserIDsWithPagination(ctx context.Context, userIDs []uint64, symbol string, status int64, page, size int64) ([]*Order, int64, error)
 		UpdateStatus(ctx context.Context, id uint64, status int64) error
 		UpdateFilledAmount(ctx context.Context, id uint64, filledAmount string) error
+		// 批量操作方法
+		FindOpenBySymbol(ctx context.Context, symbol string) ([]*Order, error)
+		CancelOpenBySymbol(ctx context.Context, symbol string) (int64, error)
+		CountOpenByUserAndSymbolForUpdate(ctx context.Context, userID uint64, symbol string) (int64, error)
 		Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error
+		WithSession(session sqlx.Session) OrderModel
 	}
 
 	customOrderModel struct {
@@ -149,6 +157,38 @@ func (m *customOrderModel) UpdateFilledAmount(ctx context.Context, id uint64, fi
 	return err
 }
 
+// FindOpenBySymbol 查询交易对下所有未完结的订单（待成交和部分成交）
+func (m *customOrderModel) FindOpenBySymbol(ctx context.Context, symbol string) ([]*Order, error) {
+	query := `SELECT id, user_id, symbol, type, side, amount, price, filled_amount, status, created_at, updated_at FROM ` + m.table + ` WHERE symbol = $1 AND status IN (1, 2) ORDER BY id ASC`
+	var resp []*Order
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, symbol)
+	return resp, err
+}
+
+// CountOpenByUserAndSymbolForUpdate 锁定用户记录后统计其在交易对下未完结的订单数量（待成交和部分成交）
+// 需在事务中调用，同一用户的并发下单在锁上排队，保证挂单上限检查与插入订单之间不会被其它下单穿插
+func (m *customOrderModel) CountOpenByUserAndSymbolForUpdate(ctx context.Context, userID uint64, symbol string) (int64, error) {
+	var locked uint64
+	if err := m.conn.QueryRowCtx(ctx, &locked, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+		return 0, err
+	}
+
+	query := `SELECT COUNT(*) FROM ` + m.table + ` WHERE user_id = $1 AND symbol = $2 AND status IN (1, 2)`
+	var count int64
+	err := m.conn.QueryRowCtx(ctx, &count, query, userID, symbol)
+	return count, err
+}
+
+// CancelOpenBySymbol 将交易对下所有未完结的订单批量置为已取消，返回受影响的订单数
+func (m *customOrderModel) CancelOpenBySymbol(ctx context.Context, symbol string) (int64, error) {
+	query := `UPDATE ` + m.table + ` SET status = 4, updated_at = $1 WHERE symbol = $2 AND status IN (1, 2)`
+	ret, err := m.conn.ExecCtx(ctx, query, time.Now(), symbol)
+	if err != nil {
+		return 0, err
+	}
+	return ret.RowsAffected()
+}
+
 func (m *defaultOrderModel) Update(ctx context.Context, data *Order) error {
 	query := `UPDATE ` + m.table + ` SET user_id = $1, symbol = $2, type = $3, side = $4, amount = $5, price = $6, filled_amount = $7, status = $8, updated_at = $9 WHERE id = $10`
 	_, err := m.conn.ExecCtx(ctx, query, data.UserID, data.Symbol, data.Type, data.Side, data.Amount, data.Price, data.FilledAmount, data.Status, data.UpdatedAt, data.ID)
@@ -165,6 +205,11 @@ func (m *customOrderModel) Trans(ctx context.Context, fn func(context.Context, s
 	return m.conn.TransactCtx(ctx, fn)
 }
 
+// WithSession 返回在事务session中执行读写的订单模型
+func (m *customOrderModel) WithSession(session sqlx.Session) OrderModel {
+	return NewOrderModel(sqlx.NewSqlConnFromSession(session))
+}
+
 // FindByUserIDWithPagination 分页查询用户订单
 func (m *customOrderModel) FindByUserIDWithPagination(ctx context.Context, userID uint64, symbol string, status int64, page, size int64) ([]*Order, int64, error) {
 	// 设置默认值
@@ -229,4 +274,51 @@ func (m *customOrderModel) FindByUserIDWithPagination(ctx context.Context, userI
 	}
 
 	return resp, total, nil
-}
\ No newline at end of file
+}
+
+// FindByUserIDsWithPagination 分页查询多个用户的订单，用于母账户汇总查看子账户订单
+func (m *customOrderModel) FindByUserIDsWithPagination(ctx context.Context, userIDs []uint64, symbol string, status int64, page, size int64) ([]*Order, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = 20
+	}
+	if size > 100 {
+		size = 100 // 限制最大页面大小
+	}
+	if len(userIDs) == 0 {
+		return nil, 0, nil
+	}
+
+	ids := make([]int64, 0, len(userIDs))
+	for _, id := range userIDs {
+		ids = append(ids, int64(id))
+	}
+	whereClause := "WHERE user_id = ANY($1)"
+	args := []interface{}{pq.Array(ids)}
+	if symbol != "" {
+		args = append(args, symbol)
+		whereClause += fmt.Sprintf(" AND symbol = $%d", len(args))
+	}
+	if status > 0 {
+		args = append(args, status)
+		whereClause += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+
+	countQuery := `SELECT COUNT(*) FROM ` + m.table + ` ` + whereClause
+	var total int64
+	if err := m.conn.QueryRowCtx(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	limitClause := fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	dataQuery := `SELECT id, user_id, symbol, type, side, amount, price, filled_amount, status, created_at, updated_at FROM ` + m.table + ` ` + whereClause + limitClause
+	args = append(args, size, (page-1)*size)
+
+	var resp []*Order
+	if err := m.conn.QueryRowsCtx(ctx, &resp, dataQuery, args...); err != nil {
+		return nil, 0, err
+	}
+	return resp, total, nil
+}
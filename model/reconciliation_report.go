@@ -0,0 +1,132 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ ReconciliationReportModel = (*customReconciliationReportModel)(nil)
+
+// 对账任务状态 / Reconciliation Report Status
+const (
+	ReconciliationStatusRunning   int64 = 1 // 执行中
+	ReconciliationStatusCompleted int64 = 2 // 已完成
+	ReconciliationStatusFailed    int64 = 3 // 执行失败
+)
+
+type (
+	// ReconciliationReportModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customReconciliationReportModel.
+	ReconciliationReportModel interface {
+		reconciliationReportModel
+		// 自定义方法
+		FindLatest(ctx context.Context, limit int64) ([]*ReconciliationReport, error)
+		FindPreviousCompleted(ctx context.Context, beforeID uint64) (*ReconciliationReport, error)
+		Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error
+	}
+
+	customReconciliationReportModel struct {
+		*defaultReconciliationReportModel
+	}
+
+	// ReconciliationReport 余额对账报告模型，每次对账任务生成一条
+	ReconciliationReport struct {
+		ID               uint64       `db:"id"`                // 报告ID，主键
+		Status           int64        `db:"status"`            // 任务状态：1-执行中，2-已完成，3-执行失败
+		CheckedAccounts  int64        `db:"checked_accounts"`  // 检查的用户币种账户数量
+		DiscrepancyCount int64        `db:"discrepancy_count"` // 不平账户数量
+		FrozenUsers      int64        `db:"frozen_users"`      // 自动冻结的用户数量
+		AutoFreeze       bool         `db:"auto_freeze"`       // 是否开启自动冻结
+		Remark           string       `db:"remark"`            // 备注，如失败原因
+		StartedAt        time.Time    `db:"started_at"`        // 开始时间
+		FinishedAt       sql.NullTime `db:"finished_at"`       // 结束时间
+	}
+
+	reconciliationReportModel interface {
+		Insert(ctx context.Context, data *ReconciliationReport) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*ReconciliationReport, error)
+		Update(ctx context.Context, data *ReconciliationReport) error
+		Delete(ctx context.Context, id uint64) error
+	}
+
+	defaultReconciliationReportModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewReconciliationReportModel returns a model for the database table.
+func NewReconciliationReportModel(conn sqlx.SqlConn) ReconciliationReportModel {
+	return &customReconciliationReportModel{
+		defaultReconciliationReportModel: newReconciliationReportModel(conn),
+	}
+}
+
+func newReconciliationReportModel(conn sqlx.SqlConn) *defaultReconciliationReportModel {
+	return &defaultReconciliationReportModel{
+		conn:  conn,
+		table: "reconciliation_reports",
+	}
+}
+
+func (m *defaultReconciliationReportModel) Insert(ctx context.Context, data *ReconciliationReport) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (status, checked_accounts, discrepancy_count, frozen_users, auto_freeze, remark, started_at, finished_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.Status, data.CheckedAccounts, data.DiscrepancyCount, data.FrozenUsers, data.AutoFreeze, data.Remark, data.StartedAt, data.FinishedAt)
+	return ret, err
+}
+
+func (m *defaultReconciliationReportModel) FindOne(ctx context.Context, id uint64) (*ReconciliationReport, error) {
+	query := `SELECT id, status, checked_accounts, discrepancy_count, frozen_users, auto_freeze, remark, started_at, finished_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp ReconciliationReport
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindLatest 查询最近的对账报告
+func (m *customReconciliationReportModel) FindLatest(ctx context.Context, limit int64) ([]*ReconciliationReport, error) {
+	query := `SELECT id, status, checked_accounts, discrepancy_count, frozen_users, auto_freeze, remark, started_at, finished_at FROM ` + m.table + ` ORDER BY id DESC LIMIT $1`
+	var resp []*ReconciliationReport
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, limit)
+	return resp, err
+}
+
+// FindPreviousCompleted 查询ID小于beforeID的最近一次已完成的对账报告
+func (m *customReconciliationReportModel) FindPreviousCompleted(ctx context.Context, beforeID uint64) (*ReconciliationReport, error) {
+	query := `SELECT id, status, checked_accounts, discrepancy_count, frozen_users, auto_freeze, remark, started_at, finished_at FROM ` + m.table + ` WHERE id < $1 AND status = $2 ORDER BY id DESC LIMIT 1`
+	var resp ReconciliationReport
+	err := m.conn.QueryRowCtx(ctx, &resp, query, beforeID, ReconciliationStatusCompleted)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultReconciliationReportModel) Update(ctx context.Context, data *ReconciliationReport) error {
+	query := `UPDATE ` + m.table + ` SET status = $1, checked_accounts = $2, discrepancy_count = $3, frozen_users = $4, auto_freeze = $5, remark = $6, finished_at = $7 WHERE id = $8`
+	_, err := m.conn.ExecCtx(ctx, query, data.Status, data.CheckedAccounts, data.DiscrepancyCount, data.FrozenUsers, data.AutoFreeze, data.Remark, data.FinishedAt, data.ID)
+	return err
+}
+
+func (m *defaultReconciliationReportModel) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM ` + m.table + ` WHERE id = $1`
+	_, err := m.conn.ExecCtx(ctx, query, id)
+	return err
+}
+
+func (m *customReconciliationReportModel) Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	return m.conn.TransactCtx(ctx, fn)
+}
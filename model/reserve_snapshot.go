@@ -0,0 +1,124 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ ReserveSnapshotModel = (*customReserveSnapshotModel)(nil)
+
+type (
+	// ReserveSnapshotModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customReserveSnapshotModel.
+	ReserveSnapshotModel interface {
+		reserveSnapshotModel
+		// 自定义方法
+		FindLatestBatch(ctx context.Context) ([]*ReserveSnapshot, error)
+		FindLatestByCurrency(ctx context.Context, currency string) (*ReserveSnapshot, error)
+		WithSession(session sqlx.Session) ReserveSnapshotModel
+	}
+
+	customReserveSnapshotModel struct {
+		*defaultReserveSnapshotModel
+	}
+
+	// ReserveSnapshot 储备金证明快照模型，每个币种一条，同一次任务生成的快照共享批次ID
+	ReserveSnapshot struct {
+		ID               uint64    `db:"id"`                // 快照ID，主键
+		BatchID          string    `db:"batch_id"`          // 批次ID
+		Currency         string    `db:"currency"`          // 币种代码
+		RootHash         string    `db:"root_hash"`         // Merkle求和树根哈希
+		TotalLiabilities string    `db:"total_liabilities"` // 用户负债总额，即根节点的和
+		LeafCount        int64     `db:"leaf_count"`        // 叶子数量
+		CreatedAt        time.Time `db:"created_at"`        // 快照时间
+	}
+
+	reserveSnapshotModel interface {
+		Insert(ctx context.Context, data *ReserveSnapshot) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*ReserveSnapshot, error)
+		Update(ctx context.Context, data *ReserveSnapshot) error
+		Delete(ctx context.Context, id uint64) error
+	}
+
+	defaultReserveSnapshotModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewReserveSnapshotModel returns a model for the database table.
+func NewReserveSnapshotModel(conn sqlx.SqlConn) ReserveSnapshotModel {
+	return &customReserveSnapshotModel{
+		defaultReserveSnapshotModel: newReserveSnapshotModel(conn),
+	}
+}
+
+func newReserveSnapshotModel(conn sqlx.SqlConn) *defaultReserveSnapshotModel {
+	return &defaultReserveSnapshotModel{
+		conn:  conn,
+		table: "reserve_snapshots",
+	}
+}
+
+func (m *defaultReserveSnapshotModel) Insert(ctx context.Context, data *ReserveSnapshot) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (batch_id, currency, root_hash, total_liabilities, leaf_count, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.BatchID, data.Currency, data.RootHash, data.TotalLiabilities, data.LeafCount, data.CreatedAt)
+	return ret, err
+}
+
+func (m *defaultReserveSnapshotModel) FindOne(ctx context.Context, id uint64) (*ReserveSnapshot, error) {
+	query := `SELECT id, batch_id, currency, root_hash, total_liabilities, leaf_count, created_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp ReserveSnapshot
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindLatestBatch 查询最近一次快照任务生成的所有币种快照
+func (m *customReserveSnapshotModel) FindLatestBatch(ctx context.Context) ([]*ReserveSnapshot, error) {
+	query := `SELECT id, batch_id, currency, root_hash, total_liabilities, leaf_count, created_at FROM ` + m.table + ` WHERE batch_id = (SELECT batch_id FROM ` + m.table + ` ORDER BY id DESC LIMIT 1) ORDER BY currency`
+	var resp []*ReserveSnapshot
+	err := m.conn.QueryRowsCtx(ctx, &resp, query)
+	return resp, err
+}
+
+// FindLatestByCurrency 查询指定币种的最新快照
+func (m *customReserveSnapshotModel) FindLatestByCurrency(ctx context.Context, currency string) (*ReserveSnapshot, error) {
+	query := `SELECT id, batch_id, currency, root_hash, total_liabilities, leaf_count, created_at FROM ` + m.table + ` WHERE currency = $1 ORDER BY id DESC LIMIT 1`
+	var resp ReserveSnapshot
+	err := m.conn.QueryRowCtx(ctx, &resp, query, currency)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultReserveSnapshotModel) Update(ctx context.Context, data *ReserveSnapshot) error {
+	query := `UPDATE ` + m.table + ` SET batch_id = $1, currency = $2, root_hash = $3, total_liabilities = $4, leaf_count = $5 WHERE id = $6`
+	_, err := m.conn.ExecCtx(ctx, query, data.BatchID, data.Currency, data.RootHash, data.TotalLiabilities, data.LeafCount, data.ID)
+	return err
+}
+
+func (m *defaultReserveSnapshotModel) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM ` + m.table + ` WHERE id = $1`
+	_, err := m.conn.ExecCtx(ctx, query, id)
+	return err
+}
+
+// WithSession 返回在事务session中执行读写的模型
+func (m *customReserveSnapshotModel) WithSession(session sqlx.Session) ReserveSnapshotModel {
+	return NewReserveSnapshotModel(sqlx.NewSqlConnFromSession(session))
+}
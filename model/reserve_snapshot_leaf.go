@@ -0,0 +1,122 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ ReserveSnapshotLeafModel = (*customReserveSnapshotLeafModel)(nil)
+
+type (
+	// ReserveSnapshotLeafModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customReserveSnapshotLeafModel.
+	ReserveSnapshotLeafModel interface {
+		reserveSnapshotLeafModel
+		// 自定义方法
+		FindBySnapshotID(ctx context.Context, snapshotID uint64) ([]*ReserveSnapshotLeaf, error)
+		FindBySnapshotIDAndUserID(ctx context.Context, snapshotID, userID uint64) (*ReserveSnapshotLeaf, error)
+		WithSession(session sqlx.Session) ReserveSnapshotLeafModel
+	}
+
+	customReserveSnapshotLeafModel struct {
+		*defaultReserveSnapshotLeafModel
+	}
+
+	// ReserveSnapshotLeaf 储备金证明快照叶子，保存构建Merkle求和树所需的全部数据
+	ReserveSnapshotLeaf struct {
+		ID         uint64 `db:"id"`          // 记录ID，主键
+		SnapshotID uint64 `db:"snapshot_id"` // 快照ID，关联reserve_snapshots表
+		LeafIndex  int64  `db:"leaf_index"`  // 叶子序号，从0开始
+		UserID     uint64 `db:"user_id"`     // 用户ID，不对外公开
+		UserHash   string `db:"user_hash"`   // 加盐哈希后的用户ID
+		Salt       string `db:"salt"`        // 用户专属盐值，仅返回给用户本人
+		Balance    string `db:"balance"`     // 快照时的用户余额：available+frozen
+	}
+
+	reserveSnapshotLeafModel interface {
+		Insert(ctx context.Context, data *ReserveSnapshotLeaf) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*ReserveSnapshotLeaf, error)
+		Update(ctx context.Context, data *ReserveSnapshotLeaf) error
+		Delete(ctx context.Context, id uint64) error
+	}
+
+	defaultReserveSnapshotLeafModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewReserveSnapshotLeafModel returns a model for the database table.
+func NewReserveSnapshotLeafModel(conn sqlx.SqlConn) ReserveSnapshotLeafModel {
+	return &customReserveSnapshotLeafModel{
+		defaultReserveSnapshotLeafModel: newReserveSnapshotLeafModel(conn),
+	}
+}
+
+func newReserveSnapshotLeafModel(conn sqlx.SqlConn) *defaultReserveSnapshotLeafModel {
+	return &defaultReserveSnapshotLeafModel{
+		conn:  conn,
+		table: "reserve_snapshot_leaves",
+	}
+}
+
+func (m *defaultReserveSnapshotLeafModel) Insert(ctx context.Context, data *ReserveSnapshotLeaf) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (snapshot_id, leaf_index, user_id, user_hash, salt, balance) VALUES ($1, $2, $3, $4, $5, $6)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.SnapshotID, data.LeafIndex, data.UserID, data.UserHash, data.Salt, data.Balance)
+	return ret, err
+}
+
+func (m *defaultReserveSnapshotLeafModel) FindOne(ctx context.Context, id uint64) (*ReserveSnapshotLeaf, error) {
+	query := `SELECT id, snapshot_id, leaf_index, user_id, user_hash, salt, balance FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp ReserveSnapshotLeaf
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindBySnapshotID 按叶子序号查询快照的全部叶子，用于重建Merkle求和树
+func (m *customReserveSnapshotLeafModel) FindBySnapshotID(ctx context.Context, snapshotID uint64) ([]*ReserveSnapshotLeaf, error) {
+	query := `SELECT id, snapshot_id, leaf_index, user_id, user_hash, salt, balance FROM ` + m.table + ` WHERE snapshot_id = $1 ORDER BY leaf_index ASC`
+	var resp []*ReserveSnapshotLeaf
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, snapshotID)
+	return resp, err
+}
+
+func (m *customReserveSnapshotLeafModel) FindBySnapshotIDAndUserID(ctx context.Context, snapshotID, userID uint64) (*ReserveSnapshotLeaf, error) {
+	query := `SELECT id, snapshot_id, leaf_index, user_id, user_hash, salt, balance FROM ` + m.table + ` WHERE snapshot_id = $1 AND user_id = $2 LIMIT 1`
+	var resp ReserveSnapshotLeaf
+	err := m.conn.QueryRowCtx(ctx, &resp, query, snapshotID, userID)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *defaultReserveSnapshotLeafModel) Update(ctx context.Context, data *ReserveSnapshotLeaf) error {
+	query := `UPDATE ` + m.table + ` SET snapshot_id = $1, leaf_index = $2, user_id = $3, user_hash = $4, salt = $5, balance = $6 WHERE id = $7`
+	_, err := m.conn.ExecCtx(ctx, query, data.SnapshotID, data.LeafIndex, data.UserID, data.UserHash, data.Salt, data.Balance, data.ID)
+	return err
+}
+
+func (m *defaultReserveSnapshotLeafModel) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM ` + m.table + ` WHERE id = $1`
+	_, err := m.conn.ExecCtx(ctx, query, id)
+	return err
+}
+
+// WithSession 返回在事务session中执行读写的模型
+func (m *customReserveSnapshotLeafModel) WithSession(session sqlx.Session) ReserveSnapshotLeafModel {
+	return NewReserveSnapshotLeafModel(sqlx.NewSqlConnFromSession(session))
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ RoleAuditLogModel = (*customRoleAuditLogModel)(nil)
+
+// 角色变更操作 / Role Audit Actions
+const (
+	RoleAuditActionGrant  int64 = 1 // 授予
+	RoleAuditActionRevoke int64 = 2 // 撤销
+)
+
+type (
+	// RoleAuditLogModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customRoleAuditLogModel.
+	RoleAuditLogModel interface {
+		roleAuditLogModel
+		// 自定义方法
+		FindWithPagination(ctx context.Context, userID uint64, page, size int64) ([]*RoleAuditLog, int64, error)
+	}
+
+	customRoleAuditLogModel struct {
+		*defaultRoleAuditLogModel
+	}
+
+	// RoleAuditLog 角色变更审计日志，由UserRoleModel在角色变更的同一事务中写入
+	RoleAuditLog struct {
+		ID         uint64    `db:"id"`          // 主键
+		UserID     uint64    `db:"user_id"`     // 被变更角色的用户ID
+		Role       string    `db:"role"`        // 角色
+		Action     int64     `db:"action"`      // 操作：1-授予，2-撤销
+		OperatorID uint64    `db:"operator_id"` // 操作人用户ID，命令行操作时为0
+		Reason     string    `db:"reason"`      // 变更原因
+		CreatedAt  time.Time `db:"created_at"`  // 记录时间
+	}
+
+	roleAuditLogModel interface {
+		Insert(ctx context.Context, data *RoleAuditLog) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*RoleAuditLog, error)
+	}
+
+	defaultRoleAuditLogModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewRoleAuditLogModel returns a model for the database table.
+func NewRoleAuditLogModel(conn sqlx.SqlConn) RoleAuditLogModel {
+	return &customRoleAuditLogModel{
+		defaultRoleAuditLogModel: newRoleAuditLogModel(conn),
+	}
+}
+
+func newRoleAuditLogModel(conn sqlx.SqlConn) *defaultRoleAuditLogModel {
+	return &defaultRoleAuditLogModel{
+		conn:  conn,
+		table: "role_audit_logs",
+	}
+}
+
+func (m *defaultRoleAuditLogModel) Insert(ctx context.Context, data *RoleAuditLog) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (user_id, role, action, operator_id, reason, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.UserID, data.Role, data.Action, data.OperatorID, data.Reason, data.CreatedAt)
+	return ret, err
+}
+
+func (m *defaultRoleAuditLogModel) FindOne(ctx context.Context, id uint64) (*RoleAuditLog, error) {
+	query := `SELECT id, user_id, role, action, operator_id, reason, created_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp RoleAuditLog
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindWithPagination 分页查询角色变更记录，按时间倒序，userID为0时查询全部用户
+func (m *customRoleAuditLogModel) FindWithPagination(ctx context.Context, userID uint64, page, size int64) ([]*RoleAuditLog, int64, error) {
+	where := ""
+	var args []interface{}
+	if userID != 0 {
+		args = append(args, userID)
+		where = ` WHERE user_id = $1`
+	}
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM ` + m.table + where
+	if err := m.conn.QueryRowCtx(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * size
+	query := fmt.Sprintf(`SELECT id, user_id, role, action, operator_id, reason, created_at FROM `+m.table+where+` ORDER BY id DESC LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	args = append(args, size, offset)
+	var resp []*RoleAuditLog
+	if err := m.conn.QueryRowsCtx(ctx, &resp, query, args...); err != nil {
+		return nil, 0, err
+	}
+	return resp, total, nil
+}
+
+// insertRoleAuditLog 在事务中写入角色变更记录
+func insertRoleAuditLog(ctx context.Context, session sqlx.Session, data *RoleAuditLog) error {
+	query := `INSERT INTO role_audit_logs (user_id, role, action, operator_id, reason, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := session.ExecCtx(ctx, query, data.UserID, data.Role, data.Action, data.OperatorID, data.Reason, data.CreatedAt)
+	return err
+}
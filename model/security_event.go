@@ -0,0 +1,148 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ SecurityEventModel = (*customSecurityEventModel)(nil)
+
+// 安全事件类型 / Security Event Types
+const (
+	SecurityEventLoginSuccess            = "login_success"              // 登录成功
+	SecurityEventLoginFailed             = "login_failed"               // 登录失败（密码或两步验证码错误等）
+	SecurityEventPasswordReset           = "password_reset"             // 重置密码
+	SecurityEventTwoFactorEnabled        = "2fa_enabled"                // 启用两步验证
+	SecurityEventTwoFactorDisabled       = "2fa_disabled"               // 关闭两步验证
+	SecurityEventApiKeyCreated           = "api_key_created"            // 创建API密钥
+	SecurityEventApiKeyDeleted           = "api_key_deleted"            // 删除API密钥
+	SecurityEventWithdrawAddressAdded    = "withdraw_address_added"     // 添加提现地址
+	SecurityEventWithdrawAddressDeleted  = "withdraw_address_deleted"   // 删除提现地址
+	SecurityEventWithdrawSettingsChanged = "withdraw_settings_changed"  // 修改提现安全设置（如白名单模式）
+	SecurityEventSubAccountStatusChanged = "sub_account_status_changed" // 禁用、恢复或删除子账户
+	SecurityEventSubAccountFrozen        = "sub_account_frozen"         // 冻结子账户
+	SecurityEventSubAccountUnfrozen      = "sub_account_unfrozen"       // 解除子账户冻结
+)
+
+type (
+	// SecurityEventModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customSecurityEventModel.
+	SecurityEventModel interface {
+		securityEventModel
+		// 自定义方法
+		FindByUserID(ctx context.Context, userID uint64, eventType string, page, size int64) ([]*SecurityEvent, int64, error)
+		FindLoginHistory(ctx context.Context, userID uint64, fingerprint, ip string, since time.Time) (*LoginHistory, error)
+	}
+
+	customSecurityEventModel struct {
+		*defaultSecurityEventModel
+	}
+
+	// SecurityEvent 账户安全事件，记录登录和安全设置变更的来源信息，只追加不修改
+	SecurityEvent struct {
+		ID                uint64    `db:"id"`                 // 主键
+		UserID            uint64    `db:"user_id"`            // 用户ID，登录邮箱不存在时为0
+		EventType         string    `db:"event_type"`         // 事件类型
+		IP                string    `db:"ip"`                 // 客户端IP
+		UserAgent         string    `db:"user_agent"`         // 客户端标识
+		DeviceFingerprint string    `db:"device_fingerprint"` // 设备指纹
+		Detail            string    `db:"detail"`             // 事件详情，如失败原因、API密钥ID、提现地址
+		CreatedAt         time.Time `db:"created_at"`         // 发生时间
+	}
+
+	// LoginHistory 用户历史登录中是否出现过指定的设备和IP
+	LoginHistory struct {
+		Total      int64 `db:"total"`       // 历史成功登录次数（不限时间）
+		DeviceSeen bool  `db:"device_seen"` // 统计窗口内是否使用该设备登录过
+		IPSeen     bool  `db:"ip_seen"`     // 统计窗口内是否从该IP登录过
+	}
+
+	securityEventModel interface {
+		Insert(ctx context.Context, data *SecurityEvent) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*SecurityEvent, error)
+	}
+
+	defaultSecurityEventModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewSecurityEventModel returns a model for the database table.
+func NewSecurityEventModel(conn sqlx.SqlConn) SecurityEventModel {
+	return &customSecurityEventModel{
+		defaultSecurityEventModel: newSecurityEventModel(conn),
+	}
+}
+
+func newSecurityEventModel(conn sqlx.SqlConn) *defaultSecurityEventModel {
+	return &defaultSecurityEventModel{
+		conn:  conn,
+		table: "security_events",
+	}
+}
+
+const securityEventFields = `id, user_id, event_type, ip, user_agent, device_fingerprint, detail, created_at`
+
+func (m *defaultSecurityEventModel) Insert(ctx context.Context, data *SecurityEvent) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (user_id, event_type, ip, user_agent, device_fingerprint, detail, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.UserID, data.EventType, data.IP, data.UserAgent, data.DeviceFingerprint, data.Detail, data.CreatedAt)
+	return ret, err
+}
+
+func (m *defaultSecurityEventModel) FindOne(ctx context.Context, id uint64) (*SecurityEvent, error) {
+	query := `SELECT ` + securityEventFields + ` FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp SecurityEvent
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindByUserID 分页查询用户的安全事件，按时间倒序，eventType为空时查询全部类型
+func (m *customSecurityEventModel) FindByUserID(ctx context.Context, userID uint64, eventType string, page, size int64) ([]*SecurityEvent, int64, error) {
+	where := ` WHERE user_id = $1`
+	args := []interface{}{userID}
+	if eventType != "" {
+		args = append(args, eventType)
+		where += ` AND event_type = $2`
+	}
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM ` + m.table + where
+	if err := m.conn.QueryRowCtx(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * size
+	query := fmt.Sprintf(`SELECT `+securityEventFields+` FROM `+m.table+where+` ORDER BY id DESC LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	args = append(args, size, offset)
+	var resp []*SecurityEvent
+	if err := m.conn.QueryRowsCtx(ctx, &resp, query, args...); err != nil {
+		return nil, 0, err
+	}
+	return resp, total, nil
+}
+
+// FindLoginHistory 统计用户的历史成功登录，以及since之后是否使用过指定设备指纹和IP登录
+// 需要在写入本次登录事件之前调用
+func (m *customSecurityEventModel) FindLoginHistory(ctx context.Context, userID uint64, fingerprint, ip string, since time.Time) (*LoginHistory, error) {
+	query := `SELECT COUNT(*) AS total,
+		COALESCE(BOOL_OR(device_fingerprint = $3) FILTER (WHERE created_at >= $5), false) AS device_seen,
+		COALESCE(BOOL_OR(ip = $4) FILTER (WHERE created_at >= $5), false) AS ip_seen
+		FROM ` + m.table + ` WHERE user_id = $1 AND event_type = $2`
+	var resp LoginHistory
+	if err := m.conn.QueryRowCtx(ctx, &resp, query, userID, SecurityEventLoginSuccess, fingerprint, ip, since); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
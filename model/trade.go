@@ -21,7 +21,9 @@ type (
 		FindByUserID(ctx context.Context, userID uint64) ([]*Trade, error)
 		FindByOrderID(ctx context.Context, orderID uint64) ([]*Trade, error)
 		FindByTimeRange(ctx context.Context, symbol string, startTime, endTime time.Time) ([]*Trade, error)
+		SumNetFlowByUserAndCurrency(ctx context.Context) ([]*BalanceFlow, error)
 		Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error
+		WithSession(session sqlx.Session) TradeModel
 	}
 
 	customTradeModel struct {
@@ -135,6 +137,28 @@ func (m *defaultTradeModel) Delete(ctx context.Context, id uint64) error {
 	return err
 }
 
+// SumNetFlowByUserAndCurrency 按用户和币种汇总成交带来的资金净流入
+// 买方增加基础币种、减少计价币种（数量*价格），卖方相反
+func (m *customTradeModel) SumNetFlowByUserAndCurrency(ctx context.Context) ([]*BalanceFlow, error) {
+	query := `SELECT user_id, currency, SUM(amount)::text AS amount FROM (
+		SELECT t.buy_user_id AS user_id, p.base_currency AS currency, t.amount::numeric AS amount FROM ` + m.table + ` t JOIN trading_pairs p ON p.symbol = t.symbol
+		UNION ALL
+		SELECT t.buy_user_id, p.quote_currency, -(t.amount::numeric * t.price::numeric) FROM ` + m.table + ` t JOIN trading_pairs p ON p.symbol = t.symbol
+		UNION ALL
+		SELECT t.sell_user_id, p.base_currency, -t.amount::numeric FROM ` + m.table + ` t JOIN trading_pairs p ON p.symbol = t.symbol
+		UNION ALL
+		SELECT t.sell_user_id, p.quote_currency, t.amount::numeric * t.price::numeric FROM ` + m.table + ` t JOIN trading_pairs p ON p.symbol = t.symbol
+	) flows GROUP BY user_id, currency`
+	var resp []*BalanceFlow
+	err := m.conn.QueryRowsCtx(ctx, &resp, query)
+	return resp, err
+}
+
 func (m *customTradeModel) Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
 	return m.conn.TransactCtx(ctx, fn)
-}
\ No newline at end of file
+}
+
+// WithSession 返回在事务session中写入成交记录的模型
+func (m *customTradeModel) WithSession(session sqlx.Session) TradeModel {
+	return NewTradeModel(sqlx.NewSqlConnFromSession(session))
+}
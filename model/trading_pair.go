@@ -10,6 +10,16 @@ import (
 
 var _ TradingPairModel = (*customTradingPairModel)(nil)
 
+// 交易对状态 / Trading Pair Status
+const (
+	TradingPairStatusTrading    int64 = 1 // 正常交易：允许下单、撤单和撮合
+	TradingPairStatusHalted     int64 = 2 // 暂停交易：禁止下单和撮合，允许撤单（原"禁用"状态）
+	TradingPairStatusPreTrading int64 = 3 // 预上线：对外展示但尚未开放下单
+	TradingPairStatusCancelOnly int64 = 4 // 仅可撤单：禁止下单，允许撤单
+	TradingPairStatusPostOnly   int64 = 5 // 仅挂单：只接受不会立即成交的限价单
+	TradingPairStatusDelisted   int64 = 6 // 已下架：终态，所有挂单已被撤销
+)
+
 type (
 	// TradingPairModel is an interface to be customized, add more methods here,
 	// and implement the added methods in customTradingPairModel.
@@ -19,6 +29,8 @@ type (
 		FindBySymbol(ctx context.Context, symbol string) (*TradingPair, error)
 		FindByStatus(ctx context.Context, status int64) ([]*TradingPair, error)
 		FindActivePairs(ctx context.Context) ([]*TradingPair, error)
+		FindAll(ctx context.Context) ([]*TradingPair, error)
+		WithSession(session sqlx.Session) TradingPairModel
 	}
 
 	customTradingPairModel struct {
@@ -27,16 +39,21 @@ type (
 
 	// TradingPair 交易对配置模型
 	TradingPair struct {
-		ID            uint64    `db:"id"`             // 交易对ID，主键
-		Symbol        string    `db:"symbol"`         // 交易对符号，格式：基础币种/计价币种，如BTC/USDT
-		BaseCurrency  string    `db:"base_currency"`  // 基础币种代码，交易的目标币种
-		QuoteCurrency string    `db:"quote_currency"` // 计价币种代码，用于定价的币种
-		MinAmount     string    `db:"min_amount"`     // 单笔交易最小数量限制
-		MaxAmount     string    `db:"max_amount"`     // 单笔交易最大数量限制
-		PriceScale    int64     `db:"price_scale"`    // 价格显示精度，小数点后位数
-		AmountScale   int64     `db:"amount_scale"`   // 数量显示精度，小数点后位数
-		Status        int64     `db:"status"`         // 交易对状态：1-正常交易，2-禁用交易
-		CreatedAt     time.Time `db:"created_at"`     // 交易对创建时间
+		ID                   uint64    `db:"id"`                     // 交易对ID，主键
+		Symbol               string    `db:"symbol"`                 // 交易对符号，格式：基础币种/计价币种，如BTC/USDT
+		BaseCurrency         string    `db:"base_currency"`          // 基础币种代码，交易的目标币种
+		QuoteCurrency        string    `db:"quote_currency"`         // 计价币种代码，用于定价的币种
+		MinAmount            string    `db:"min_amount"`             // 单笔交易最小数量限制
+		MaxAmount            string    `db:"max_amount"`             // 单笔交易最大数量限制
+		PriceScale           int64     `db:"price_scale"`            // 价格显示精度，小数点后位数
+		AmountScale          int64     `db:"amount_scale"`           // 数量显示精度，小数点后位数
+		TickSize             string    `db:"tick_size"`              // 价格最小变动单位，订单价格必须是其整数倍，0表示不限制
+		StepSize             string    `db:"step_size"`              // 数量最小变动单位，订单数量必须是其整数倍，0表示不限制
+		MinNotional          string    `db:"min_notional"`           // 最小下单金额（价格×数量），以计价币种计，0表示不限制
+		MaxOpenOrders        int64     `db:"max_open_orders"`        // 单个用户在该交易对的最大挂单数量，0表示不限制
+		MinVerificationLevel int64     `db:"min_verification_level"` // 下单所需的最低用户认证等级，0表示不限制
+		Status               int64     `db:"status"`                 // 交易对状态：1-正常交易，2-暂停交易，3-预上线，4-仅可撤单，5-仅挂单，6-已下架
+		CreatedAt            time.Time `db:"created_at"`             // 交易对创建时间
 	}
 
 	tradingPairModel interface {
@@ -67,13 +84,13 @@ func newTradingPairModel(conn sqlx.SqlConn) *defaultTradingPairModel {
 }
 
 func (m *defaultTradingPairModel) Insert(ctx context.Context, data *TradingPair) (sql.Result, error) {
-	query := `INSERT INTO ` + m.table + ` (symbol, base_currency, quote_currency, min_amount, max_amount, price_scale, amount_scale, status, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
-	ret, err := m.conn.ExecCtx(ctx, query, data.Symbol, data.BaseCurrency, data.QuoteCurrency, data.MinAmount, data.MaxAmount, data.PriceScale, data.AmountScale, data.Status, data.CreatedAt)
+	query := `INSERT INTO ` + m.table + ` (symbol, base_currency, quote_currency, min_amount, max_amount, price_scale, amount_scale, tick_size, step_size, min_notional, max_open_orders, min_verification_level, status, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.Symbol, data.BaseCurrency, data.QuoteCurrency, data.MinAmount, data.MaxAmount, data.PriceScale, data.AmountScale, data.TickSize, data.StepSize, data.MinNotional, data.MaxOpenOrders, data.MinVerificationLevel, data.Status, data.CreatedAt)
 	return ret, err
 }
 
 func (m *defaultTradingPairModel) FindOne(ctx context.Context, id uint64) (*TradingPair, error) {
-	query := `SELECT id, symbol, base_currency, quote_currency, min_amount, max_amount, price_scale, amount_scale, status, created_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	query := `SELECT id, symbol, base_currency, quote_currency, min_amount, max_amount, price_scale, amount_scale, tick_size, step_size, min_notional, max_open_orders, min_verification_level, status, created_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
 	var resp TradingPair
 	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
 	switch err {
@@ -87,7 +104,7 @@ func (m *defaultTradingPairModel) FindOne(ctx context.Context, id uint64) (*Trad
 }
 
 func (m *customTradingPairModel) FindBySymbol(ctx context.Context, symbol string) (*TradingPair, error) {
-	query := `SELECT id, symbol, base_currency, quote_currency, min_amount, max_amount, price_scale, amount_scale, status, created_at FROM ` + m.table + ` WHERE symbol = $1 LIMIT 1`
+	query := `SELECT id, symbol, base_currency, quote_currency, min_amount, max_amount, price_scale, amount_scale, tick_size, step_size, min_notional, max_open_orders, min_verification_level, status, created_at FROM ` + m.table + ` WHERE symbol = $1 LIMIT 1`
 	var resp TradingPair
 	err := m.conn.QueryRowCtx(ctx, &resp, query, symbol)
 	switch err {
@@ -101,22 +118,29 @@ func (m *customTradingPairModel) FindBySymbol(ctx context.Context, symbol string
 }
 
 func (m *customTradingPairModel) FindByStatus(ctx context.Context, status int64) ([]*TradingPair, error) {
-	query := `SELECT id, symbol, base_currency, quote_currency, min_amount, max_amount, price_scale, amount_scale, status, created_at FROM ` + m.table + ` WHERE status = $1`
+	query := `SELECT id, symbol, base_currency, quote_currency, min_amount, max_amount, price_scale, amount_scale, tick_size, step_size, min_notional, max_open_orders, min_verification_level, status, created_at FROM ` + m.table + ` WHERE status = $1`
 	var resp []*TradingPair
 	err := m.conn.QueryRowsCtx(ctx, &resp, query, status)
 	return resp, err
 }
 
 func (m *customTradingPairModel) FindActivePairs(ctx context.Context) ([]*TradingPair, error) {
-	query := `SELECT id, symbol, base_currency, quote_currency, min_amount, max_amount, price_scale, amount_scale, status, created_at FROM ` + m.table + ` WHERE status = 1`
+	query := `SELECT id, symbol, base_currency, quote_currency, min_amount, max_amount, price_scale, amount_scale, tick_size, step_size, min_notional, max_open_orders, min_verification_level, status, created_at FROM ` + m.table + ` WHERE status = 1`
+	var resp []*TradingPair
+	err := m.conn.QueryRowsCtx(ctx, &resp, query)
+	return resp, err
+}
+
+func (m *customTradingPairModel) FindAll(ctx context.Context) ([]*TradingPair, error) {
+	query := `SELECT id, symbol, base_currency, quote_currency, min_amount, max_amount, price_scale, amount_scale, tick_size, step_size, min_notional, max_open_orders, min_verification_level, status, created_at FROM ` + m.table + ` ORDER BY id ASC`
 	var resp []*TradingPair
 	err := m.conn.QueryRowsCtx(ctx, &resp, query)
 	return resp, err
 }
 
 func (m *defaultTradingPairModel) Update(ctx context.Context, data *TradingPair) error {
-	query := `UPDATE ` + m.table + ` SET symbol = $1, base_currency = $2, quote_currency = $3, min_amount = $4, max_amount = $5, price_scale = $6, amount_scale = $7, status = $8 WHERE id = $9`
-	_, err := m.conn.ExecCtx(ctx, query, data.Symbol, data.BaseCurrency, data.QuoteCurrency, data.MinAmount, data.MaxAmount, data.PriceScale, data.AmountScale, data.Status, data.ID)
+	query := `UPDATE ` + m.table + ` SET symbol = $1, base_currency = $2, quote_currency = $3, min_amount = $4, max_amount = $5, price_scale = $6, amount_scale = $7, tick_size = $8, step_size = $9, min_notional = $10, max_open_orders = $11, min_verification_level = $12, status = $13 WHERE id = $14`
+	_, err := m.conn.ExecCtx(ctx, query, data.Symbol, data.BaseCurrency, data.QuoteCurrency, data.MinAmount, data.MaxAmount, data.PriceScale, data.AmountScale, data.TickSize, data.StepSize, data.MinNotional, data.MaxOpenOrders, data.MinVerificationLevel, data.Status, data.ID)
 	return err
 }
 
@@ -124,4 +148,62 @@ func (m *defaultTradingPairModel) Delete(ctx context.Context, id uint64) error {
 	query := `DELETE FROM ` + m.table + ` WHERE id = $1`
 	_, err := m.conn.ExecCtx(ctx, query, id)
 	return err
-}
\ No newline at end of file
+}
+
+// IsValidTradingPairStatus 判断交易对状态值是否合法
+func IsValidTradingPairStatus(status int64) bool {
+	return status >= TradingPairStatusTrading && status <= TradingPairStatusDelisted
+}
+
+// TradingPairStatusText 返回交易对状态的英文描述，用于日志和错误信息
+func TradingPairStatusText(status int64) string {
+	switch status {
+	case TradingPairStatusTrading:
+		return "trading"
+	case TradingPairStatusHalted:
+		return "halted"
+	case TradingPairStatusPreTrading:
+		return "pre-trading"
+	case TradingPairStatusCancelOnly:
+		return "cancel-only"
+	case TradingPairStatusPostOnly:
+		return "post-only"
+	case TradingPairStatusDelisted:
+		return "delisted"
+	default:
+		return "unknown"
+	}
+}
+
+// CheckTradingPairOrderStatus 检查交易对当前状态是否允许提交指定类型的订单
+// orderType：1-限价单，2-市价单。仅挂单状态下是否会立即成交由撮合引擎判断
+func CheckTradingPairOrderStatus(status int64, orderType int64) error {
+	switch status {
+	case TradingPairStatusTrading:
+		return nil
+	case TradingPairStatusPostOnly:
+		if orderType != 1 {
+			return ErrTradingPairPostOnly
+		}
+		return nil
+	case TradingPairStatusPreTrading:
+		return ErrTradingPairNotOpen
+	case TradingPairStatusCancelOnly:
+		return ErrTradingPairCancelOnly
+	case TradingPairStatusDelisted:
+		return ErrTradingPairDelisted
+	default:
+		return ErrTradingPairDisabled
+	}
+}
+
+// TradingPairAcceptsCancels 判断交易对当前状态是否允许撤单
+// 暂停交易时用户仍需撤单取回冻结资产，只有已下架状态（挂单已全部撤销）禁止撤单
+func TradingPairAcceptsCancels(status int64) bool {
+	return status != TradingPairStatusDelisted
+}
+
+// WithSession 返回在事务session中执行读写的交易对模型
+func (m *customTradingPairModel) WithSession(session sqlx.Session) TradingPairModel {
+	return NewTradingPairModel(sqlx.NewSqlConnFromSession(session))
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ TradingPairStatusChangeModel = (*customTradingPairStatusChangeModel)(nil)
+
+// 状态变更记录执行状态 / Status Change State
+const (
+	StatusChangeStatePending  int64 = 1 // 待执行：已排期，等待生效时间
+	StatusChangeStateExecuted int64 = 2 // 已执行
+	StatusChangeStateCanceled int64 = 3 // 已取消
+	StatusChangeStateFailed   int64 = 4 // 执行失败
+)
+
+type (
+	// TradingPairStatusChangeModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customTradingPairStatusChangeModel.
+	TradingPairStatusChangeModel interface {
+		tradingPairStatusChangeModel
+		// 自定义方法
+		FindBySymbol(ctx context.Context, symbol string, limit, offset int64) ([]*TradingPairStatusChange, error)
+		CountBySymbol(ctx context.Context, symbol string) (int64, error)
+		FindDuePending(ctx context.Context, now time.Time) ([]*TradingPairStatusChange, error)
+		UpdateState(ctx context.Context, id uint64, state int64, fromStatus int64, remark string) error
+		Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error
+		WithSession(session sqlx.Session) TradingPairStatusChangeModel
+	}
+
+	customTradingPairStatusChangeModel struct {
+		*defaultTradingPairStatusChangeModel
+	}
+
+	// TradingPairStatusChange 交易对状态变更记录模型，同时作为排期任务和审计日志
+	TradingPairStatusChange struct {
+		ID          uint64       `db:"id"`           // 记录ID，主键
+		Symbol      string       `db:"symbol"`       // 交易对符号，如BTC/USDT
+		FromStatus  int64        `db:"from_status"`  // 变更前状态（排期时为创建时的状态，执行时更新为实际状态）
+		ToStatus    int64        `db:"to_status"`    // 目标状态
+		EffectiveAt time.Time    `db:"effective_at"` // 计划生效时间
+		State       int64        `db:"state"`        // 执行状态：1-待执行，2-已执行，3-已取消，4-执行失败
+		OperatorID  uint64       `db:"operator_id"`  // 操作人用户ID，系统任务为0
+		Reason      string       `db:"reason"`       // 变更原因
+		Remark      string       `db:"remark"`       // 执行备注，如失败原因、撤单数量
+		CreatedAt   time.Time    `db:"created_at"`   // 创建时间
+		ExecutedAt  sql.NullTime `db:"executed_at"`  // 实际执行时间
+	}
+
+	tradingPairStatusChangeModel interface {
+		Insert(ctx context.Context, data *TradingPairStatusChange) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*TradingPairStatusChange, error)
+		Update(ctx context.Context, data *TradingPairStatusChange) error
+		Delete(ctx context.Context, id uint64) error
+	}
+
+	defaultTradingPairStatusChangeModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewTradingPairStatusChangeModel returns a model for the database table.
+func NewTradingPairStatusChangeModel(conn sqlx.SqlConn) TradingPairStatusChangeModel {
+	return &customTradingPairStatusChangeModel{
+		defaultTradingPairStatusChangeModel: newTradingPairStatusChangeModel(conn),
+	}
+}
+
+func newTradingPairStatusChangeModel(conn sqlx.SqlConn) *defaultTradingPairStatusChangeModel {
+	return &defaultTradingPairStatusChangeModel{
+		conn:  conn,
+		table: "trading_pair_status_changes",
+	}
+}
+
+func (m *defaultTradingPairStatusChangeModel) Insert(ctx context.Context, data *TradingPairStatusChange) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (symbol, from_status, to_status, effective_at, state, operator_id, reason, remark, created_at, executed_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.Symbol, data.FromStatus, data.ToStatus, data.EffectiveAt, data.State, data.OperatorID, data.Reason, data.Remark, data.CreatedAt, data.ExecutedAt)
+	return ret, err
+}
+
+func (m *defaultTradingPairStatusChangeModel) FindOne(ctx context.Context, id uint64) (*TradingPairStatusChange, error) {
+	query := `SELECT id, symbol, from_status, to_status, effective_at, state, operator_id, reason, remark, created_at, executed_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp TradingPairStatusChange
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+func (m *customTradingPairStatusChangeModel) FindBySymbol(ctx context.Context, symbol string, limit, offset int64) ([]*TradingPairStatusChange, error) {
+	query := `SELECT id, symbol, from_status, to_status, effective_at, state, operator_id, reason, remark, created_at, executed_at FROM ` + m.table + ` WHERE symbol = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+	var resp []*TradingPairStatusChange
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, symbol, limit, offset)
+	return resp, err
+}
+
+func (m *customTradingPairStatusChangeModel) CountBySymbol(ctx context.Context, symbol string) (int64, error) {
+	query := `SELECT COUNT(*) FROM ` + m.table + ` WHERE symbol = $1`
+	var count int64
+	err := m.conn.QueryRowCtx(ctx, &count, query, symbol)
+	return count, err
+}
+
+// FindDuePending 查询已到生效时间但尚未执行的排期记录，按生效时间先后排序
+func (m *customTradingPairStatusChangeModel) FindDuePending(ctx context.Context, now time.Time) ([]*TradingPairStatusChange, error) {
+	query := `SELECT id, symbol, from_status, to_status, effective_at, state, operator_id, reason, remark, created_at, executed_at FROM ` + m.table + ` WHERE state = $1 AND effective_at <= $2 ORDER BY effective_at ASC, id ASC`
+	var resp []*TradingPairStatusChange
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, StatusChangeStatePending, now)
+	return resp, err
+}
+
+// UpdateState 更新排期记录的执行状态，同时记录实际的变更前状态和执行备注
+func (m *customTradingPairStatusChangeModel) UpdateState(ctx context.Context, id uint64, state int64, fromStatus int64, remark string) error {
+	query := `UPDATE ` + m.table + ` SET state = $1, from_status = $2, remark = $3, executed_at = $4 WHERE id = $5`
+	_, err := m.conn.ExecCtx(ctx, query, state, fromStatus, remark, time.Now(), id)
+	return err
+}
+
+func (m *defaultTradingPairStatusChangeModel) Update(ctx context.Context, data *TradingPairStatusChange) error {
+	query := `UPDATE ` + m.table + ` SET symbol = $1, from_status = $2, to_status = $3, effective_at = $4, state = $5, operator_id = $6, reason = $7, remark = $8, executed_at = $9 WHERE id = $10`
+	_, err := m.conn.ExecCtx(ctx, query, data.Symbol, data.FromStatus, data.ToStatus, data.EffectiveAt, data.State, data.OperatorID, data.Reason, data.Remark, data.ExecutedAt, data.ID)
+	return err
+}
+
+func (m *defaultTradingPairStatusChangeModel) Delete(ctx context.Context, id uint64) error {
+	query := `DELETE FROM ` + m.table + ` WHERE id = $1`
+	_, err := m.conn.ExecCtx(ctx, query, id)
+	return err
+}
+
+func (m *customTradingPairStatusChangeModel) Trans(ctx context.Context, fn func(context.Context, sqlx.Session) error) error {
+	return m.conn.TransactCtx(ctx, fn)
+}
+
+// WithSession 返回在事务session中执行读写的状态变更模型
+func (m *customTradingPairStatusChangeModel) WithSession(session sqlx.Session) TradingPairStatusChangeModel {
+	return NewTradingPairStatusChangeModel(sqlx.NewSqlConnFromSession(session))
+}
@@ -10,6 +10,30 @@ import (
 
 var _ UserModel = (*customUserModel)(nil)
 
+// 用户状态 / User Status
+const (
+	UserStatusActive   int64 = 1 // 正常
+	UserStatusDisabled int64 = 2 // 禁用，可以恢复
+	UserStatusDeleted  int64 = 3 // 删除（注销），不可恢复
+)
+
+// 用户认证等级 / User Verification Level
+const (
+	UserVerificationUnverified int64 = 0 // 未认证
+	UserVerificationBasic      int64 = 1 // 基础认证
+	UserVerificationAdvanced   int64 = 2 // 高级认证
+)
+
+// EmailVerified 邮箱是否已验证，未验证邮箱的用户不能交易和提现
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt.Valid
+}
+
+// WithdrawWhitelistEnforced 判断now时提现是否按白名单校验：已开启白名单，或关闭白名单后仍在冷却期内
+func (u *User) WithdrawWhitelistEnforced(now time.Time) bool {
+	return u.WithdrawWhitelistOnly || (u.WithdrawWhitelistUntil.Valid && now.Before(u.WithdrawWhitelistUntil.Time))
+}
+
 type (
 	// UserModel is an interface to be customized, add more methods here,
 	// and implement the added methods in customUserModel.
@@ -17,6 +41,13 @@ type (
 		userModel
 		// 自定义方法
 		FindOneByEmail(ctx context.Context, email string) (*User, error)
+		UpdateWithdrawWhitelistOnly(ctx context.Context, id uint64, whitelistOnly bool, until sql.NullTime) error
+		UpdatePassword(ctx context.Context, id uint64, password string) error
+		UpdateStatus(ctx context.Context, id uint64, status int64) error
+		MarkEmailVerified(ctx context.Context, id uint64) error
+		// 子账户相关方法
+		FindByParentID(ctx context.Context, parentID uint64) ([]*User, error)
+		CountByParentID(ctx context.Context, parentID uint64) (int64, error)
 	}
 
 	customUserModel struct {
@@ -25,13 +56,19 @@ type (
 
 	// User 用户基础信息模型
 	User struct {
-		ID        uint64    `db:"id"`         // 用户ID，主键
-		Email     string    `db:"email"`      // 用户邮箱，唯一标识
-		Password  string    `db:"password"`   // 密码哈希值，使用bcrypt加密
-		Nickname  string    `db:"nickname"`   // 用户昵称，显示名称
-		Status    int64     `db:"status"`     // 用户状态：1-正常，2-禁用，3-删除
-		CreatedAt time.Time `db:"created_at"` // 账户创建时间
-		UpdatedAt time.Time `db:"updated_at"` // 最后更新时间
+		ID                     uint64       `db:"id"`                       // 用户ID，主键
+		Email                  string       `db:"email"`                    // 用户邮箱，唯一标识
+		Password               string       `db:"password"`                 // 密码哈希值，使用bcrypt加密
+		Nickname               string       `db:"nickname"`                 // 用户昵称，显示名称
+		Status                 int64        `db:"status"`                   // 用户状态：1-正常，2-禁用，3-删除
+		ParentID               uint64       `db:"parent_id"`                // 母账户ID，0表示普通账户（可以作为母账户）
+		WithdrawWhitelistOnly  bool         `db:"withdraw_whitelist_only"`  // 是否只允许提现到地址簿中的地址
+		WithdrawWhitelistUntil sql.NullTime `db:"withdraw_whitelist_until"` // 关闭白名单的冷却截止时间，之前仍按白名单校验
+		VerificationLevel      int64        `db:"verification_level"`       // 认证等级：0-未认证，1-基础认证，2-高级认证
+		PasswordChangedAt      sql.NullTime `db:"password_changed_at"`      // 最近一次修改密码的时间，从未修改时为空
+		EmailVerifiedAt        sql.NullTime `db:"email_verified_at"`        // 邮箱验证时间，未验证时为空
+		CreatedAt              time.Time    `db:"created_at"`               // 账户创建时间
+		UpdatedAt              time.Time    `db:"updated_at"`               // 最后更新时间
 	}
 
 	userModel interface {
@@ -62,13 +99,13 @@ func newUserModel(conn sqlx.SqlConn) *defaultUserModel {
 }
 
 func (m *defaultUserModel) Insert(ctx context.Context, data *User) (sql.Result, error) {
-	query := `INSERT INTO ` + m.table + ` (email, password, nickname, status, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`
-	ret, err := m.conn.ExecCtx(ctx, query, data.Email, data.Password, data.Nickname, data.Status, data.CreatedAt, data.UpdatedAt)
+	query := `INSERT INTO ` + m.table + ` (email, password, nickname, status, parent_id, email_verified_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.Email, data.Password, data.Nickname, data.Status, data.ParentID, data.EmailVerifiedAt, data.CreatedAt, data.UpdatedAt)
 	return ret, err
 }
 
 func (m *defaultUserModel) FindOne(ctx context.Context, id uint64) (*User, error) {
-	query := `SELECT id, email, password, nickname, status, created_at, updated_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	query := `SELECT id, email, password, nickname, status, parent_id, withdraw_whitelist_only, withdraw_whitelist_until, verification_level, password_changed_at, email_verified_at, created_at, updated_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
 	var resp User
 	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
 	switch err {
@@ -82,7 +119,7 @@ func (m *defaultUserModel) FindOne(ctx context.Context, id uint64) (*User, error
 }
 
 func (m *customUserModel) FindOneByEmail(ctx context.Context, email string) (*User, error) {
-	query := `SELECT id, email, password, nickname, status, created_at, updated_at FROM ` + m.table + ` WHERE email = $1 LIMIT 1`
+	query := `SELECT id, email, password, nickname, status, parent_id, withdraw_whitelist_only, withdraw_whitelist_until, verification_level, password_changed_at, email_verified_at, created_at, updated_at FROM ` + m.table + ` WHERE email = $1 LIMIT 1`
 	var resp User
 	err := m.conn.QueryRowCtx(ctx, &resp, query, email)
 	switch err {
@@ -96,8 +133,8 @@ func (m *customUserModel) FindOneByEmail(ctx context.Context, email string) (*Us
 }
 
 func (m *defaultUserModel) Update(ctx context.Context, data *User) error {
-	query := `UPDATE ` + m.table + ` SET email = $1, password = $2, nickname = $3, status = $4, updated_at = $5 WHERE id = $6`
-	_, err := m.conn.ExecCtx(ctx, query, data.Email, data.Password, data.Nickname, data.Status, data.UpdatedAt, data.ID)
+	query := `UPDATE ` + m.table + ` SET email = $1, password = $2, nickname = $3, status = $4, withdraw_whitelist_only = $5, updated_at = $6 WHERE id = $7`
+	_, err := m.conn.ExecCtx(ctx, query, data.Email, data.Password, data.Nickname, data.Status, data.WithdrawWhitelistOnly, data.UpdatedAt, data.ID)
 	return err
 }
 
@@ -105,4 +142,50 @@ func (m *defaultUserModel) Delete(ctx context.Context, id uint64) error {
 	query := `DELETE FROM ` + m.table + ` WHERE id = $1`
 	_, err := m.conn.ExecCtx(ctx, query, id)
 	return err
-}
\ No newline at end of file
+}
+
+// UpdateWithdrawWhitelistOnly 设置是否只允许提现到地址簿中的地址，until为关闭白名单的冷却截止时间
+func (m *customUserModel) UpdateWithdrawWhitelistOnly(ctx context.Context, id uint64, whitelistOnly bool, until sql.NullTime) error {
+	query := `UPDATE ` + m.table + ` SET withdraw_whitelist_only = $1, withdraw_whitelist_until = $2, updated_at = $3 WHERE id = $4`
+	_, err := m.conn.ExecCtx(ctx, query, whitelistOnly, until, time.Now(), id)
+	return err
+}
+
+// UpdatePassword 修改密码哈希并记录修改时间，修改密码后一段时间内禁止提现
+func (m *customUserModel) UpdatePassword(ctx context.Context, id uint64, password string) error {
+	now := time.Now()
+	query := `UPDATE ` + m.table + ` SET password = $1, password_changed_at = $2, updated_at = $2 WHERE id = $3`
+	_, err := m.conn.ExecCtx(ctx, query, password, now, id)
+	return err
+}
+
+// UpdateStatus 修改用户状态
+func (m *customUserModel) UpdateStatus(ctx context.Context, id uint64, status int64) error {
+	query := `UPDATE ` + m.table + ` SET status = $1, updated_at = $2 WHERE id = $3`
+	_, err := m.conn.ExecCtx(ctx, query, status, time.Now(), id)
+	return err
+}
+
+// MarkEmailVerified 记录邮箱验证时间，已验证的用户保留首次验证时间
+func (m *customUserModel) MarkEmailVerified(ctx context.Context, id uint64) error {
+	now := time.Now()
+	query := `UPDATE ` + m.table + ` SET email_verified_at = $1, updated_at = $1 WHERE id = $2 AND email_verified_at IS NULL`
+	_, err := m.conn.ExecCtx(ctx, query, now, id)
+	return err
+}
+
+// FindByParentID 查询母账户下的所有子账户，按创建顺序排列
+func (m *customUserModel) FindByParentID(ctx context.Context, parentID uint64) ([]*User, error) {
+	query := `SELECT id, email, password, nickname, status, parent_id, withdraw_whitelist_only, withdraw_whitelist_until, verification_level, password_changed_at, email_verified_at, created_at, updated_at FROM ` + m.table + ` WHERE parent_id = $1 ORDER BY id`
+	var resp []*User
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, parentID)
+	return resp, err
+}
+
+// CountByParentID 统计母账户下的子账户数量（包括已禁用和已删除的子账户）
+func (m *customUserModel) CountByParentID(ctx context.Context, parentID uint64) (int64, error) {
+	query := `SELECT COUNT(*) FROM ` + m.table + ` WHERE parent_id = $1`
+	var count int64
+	err := m.conn.QueryRowCtx(ctx, &count, query, parentID)
+	return count, err
+}
@@ -0,0 +1,220 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ UserRestrictionModel = (*customUserRestrictionModel)(nil)
+
+// 账户限制类型 / Account Restriction Types
+const (
+	RestrictionLogin    = "login"    // 禁止登录，施加时注销全部登录会话
+	RestrictionTrading  = "trading"  // 禁止下单，允许撤单
+	RestrictionWithdraw = "withdraw" // 禁止提现和转出资产
+	RestrictionFreeze   = "freeze"   // 冻结账户，包含以上全部限制
+)
+
+// 账户限制变更操作 / Restriction Audit Actions
+const (
+	RestrictionAuditActionApply int64 = 1 // 施加或更新限制
+	RestrictionAuditActionLift  int64 = 2 // 解除限制
+)
+
+// IsValidRestrictionType 判断是否为已定义的账户限制类型
+func IsValidRestrictionType(restrictionType string) bool {
+	switch restrictionType {
+	case RestrictionLogin, RestrictionTrading, RestrictionWithdraw, RestrictionFreeze:
+		return true
+	}
+	return false
+}
+
+// MatchRestriction 在生效的限制中查找限制restrictionType操作的限制，冻结账户包含全部限制，未受限时返回nil
+func MatchRestriction(restrictions []*UserRestriction, restrictionType string) *AccountRestrictedError {
+	for _, r := range restrictions {
+		if r.Type != restrictionType && r.Type != RestrictionFreeze {
+			continue
+		}
+		restricted := &AccountRestrictedError{Type: r.Type}
+		if r.ExpiresAt.Valid {
+			restricted.ExpiresAt = r.ExpiresAt.Time
+		}
+		return restricted
+	}
+	return nil
+}
+
+type (
+	// UserRestrictionModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customUserRestrictionModel.
+	UserRestrictionModel interface {
+		userRestrictionModel
+		// 自定义方法
+		FindByUserID(ctx context.Context, userID uint64) ([]*UserRestriction, error)
+		FindActiveByUserID(ctx context.Context, userID uint64, now time.Time) ([]*UserRestriction, error)
+		Apply(ctx context.Context, data *UserRestriction, audit *RestrictionAuditLog) error
+		Lift(ctx context.Context, userID uint64, restrictionType string, audit *RestrictionAuditLog) error
+		FindAuditLogs(ctx context.Context, userID uint64, page, size int64) ([]*RestrictionAuditLog, int64, error)
+	}
+
+	customUserRestrictionModel struct {
+		*defaultUserRestrictionModel
+	}
+
+	// UserRestriction 账户限制，同一用户同一类型只有一条记录，过期后自动失效
+	UserRestriction struct {
+		ID        uint64       `db:"id"`         // 主键
+		UserID    uint64       `db:"user_id"`    // 用户ID
+		Type      string       `db:"type"`       // 限制类型：login/trading/withdraw/freeze
+		Reason    string       `db:"reason"`     // 限制原因，仅管理员可见
+		ExpiresAt sql.NullTime `db:"expires_at"` // 到期时间，为空时永久有效直到解除
+		CreatedBy uint64       `db:"created_by"` // 操作人用户ID，系统任务为0
+		CreatedAt time.Time    `db:"created_at"` // 施加时间
+		UpdatedAt time.Time    `db:"updated_at"` // 最后更新时间
+	}
+
+	// RestrictionAuditLog 账户限制变更审计日志，由UserRestrictionModel在限制变更的同一事务中写入
+	RestrictionAuditLog struct {
+		ID         uint64       `db:"id"`          // 主键
+		UserID     uint64       `db:"user_id"`     // 被限制的用户ID
+		Type       string       `db:"type"`        // 限制类型
+		Action     int64        `db:"action"`      // 操作：1-施加，2-解除
+		Reason     string       `db:"reason"`      // 变更原因
+		ExpiresAt  sql.NullTime `db:"expires_at"`  // 施加时设置的到期时间
+		OperatorID uint64       `db:"operator_id"` // 操作人用户ID，系统任务为0
+		CreatedAt  time.Time    `db:"created_at"`  // 记录时间
+	}
+
+	userRestrictionModel interface {
+		Insert(ctx context.Context, data *UserRestriction) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*UserRestriction, error)
+	}
+
+	defaultUserRestrictionModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewUserRestrictionModel returns a model for the database table.
+func NewUserRestrictionModel(conn sqlx.SqlConn) UserRestrictionModel {
+	return &customUserRestrictionModel{
+		defaultUserRestrictionModel: newUserRestrictionModel(conn),
+	}
+}
+
+func newUserRestrictionModel(conn sqlx.SqlConn) *defaultUserRestrictionModel {
+	return &defaultUserRestrictionModel{
+		conn:  conn,
+		table: "user_restrictions",
+	}
+}
+
+const (
+	userRestrictionFields     = `id, user_id, type, reason, expires_at, created_by, created_at, updated_at`
+	restrictionAuditLogFields = `id, user_id, type, action, reason, expires_at, operator_id, created_at`
+)
+
+func (m *defaultUserRestrictionModel) Insert(ctx context.Context, data *UserRestriction) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (user_id, type, reason, expires_at, created_by, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.UserID, data.Type, data.Reason, data.ExpiresAt, data.CreatedBy, data.CreatedAt, data.UpdatedAt)
+	return ret, err
+}
+
+func (m *defaultUserRestrictionModel) FindOne(ctx context.Context, id uint64) (*UserRestriction, error) {
+	query := `SELECT ` + userRestrictionFields + ` FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp UserRestriction
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindByUserID 查询用户的全部限制（包括已过期未解除的），按类型排序
+func (m *customUserRestrictionModel) FindByUserID(ctx context.Context, userID uint64) ([]*UserRestriction, error) {
+	query := `SELECT ` + userRestrictionFields + ` FROM ` + m.table + ` WHERE user_id = $1 ORDER BY type`
+	var resp []*UserRestriction
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, userID)
+	return resp, err
+}
+
+// FindActiveByUserID 查询用户在now时仍然有效的限制
+func (m *customUserRestrictionModel) FindActiveByUserID(ctx context.Context, userID uint64, now time.Time) ([]*UserRestriction, error) {
+	query := `SELECT ` + userRestrictionFields + ` FROM ` + m.table + ` WHERE user_id = $1 AND (expires_at IS NULL OR expires_at > $2) ORDER BY type`
+	var resp []*UserRestriction
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, userID, now)
+	return resp, err
+}
+
+// Apply 施加限制并在同一事务中写入审计日志，同类型的限制已存在时更新原因、到期时间和操作人
+func (m *customUserRestrictionModel) Apply(ctx context.Context, data *UserRestriction, audit *RestrictionAuditLog) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		query := `INSERT INTO ` + m.table + ` (user_id, type, reason, expires_at, created_by, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (user_id, type) DO UPDATE SET reason = EXCLUDED.reason, expires_at = EXCLUDED.expires_at, created_by = EXCLUDED.created_by, updated_at = EXCLUDED.updated_at`
+		if _, err := session.ExecCtx(ctx, query, data.UserID, data.Type, data.Reason, data.ExpiresAt, data.CreatedBy, data.CreatedAt, data.UpdatedAt); err != nil {
+			return err
+		}
+		return insertRestrictionAuditLog(ctx, session, audit)
+	})
+}
+
+// Lift 解除限制并在同一事务中写入审计日志，用户没有该类型的限制时返回ErrRestrictionNotFound
+func (m *customUserRestrictionModel) Lift(ctx context.Context, userID uint64, restrictionType string, audit *RestrictionAuditLog) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		query := `DELETE FROM ` + m.table + ` WHERE user_id = $1 AND type = $2`
+		result, err := session.ExecCtx(ctx, query, userID, restrictionType)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrRestrictionNotFound
+		}
+		return insertRestrictionAuditLog(ctx, session, audit)
+	})
+}
+
+// FindAuditLogs 分页查询限制变更记录，按时间倒序，userID为0时查询全部用户
+func (m *customUserRestrictionModel) FindAuditLogs(ctx context.Context, userID uint64, page, size int64) ([]*RestrictionAuditLog, int64, error) {
+	where := ""
+	var args []interface{}
+	if userID != 0 {
+		args = append(args, userID)
+		where = ` WHERE user_id = $1`
+	}
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM restriction_audit_logs` + where
+	if err := m.conn.QueryRowCtx(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * size
+	query := fmt.Sprintf(`SELECT `+restrictionAuditLogFields+` FROM restriction_audit_logs`+where+` ORDER BY id DESC LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+	args = append(args, size, offset)
+	var resp []*RestrictionAuditLog
+	if err := m.conn.QueryRowsCtx(ctx, &resp, query, args...); err != nil {
+		return nil, 0, err
+	}
+	return resp, total, nil
+}
+
+// insertRestrictionAuditLog 在事务中写入限制变更记录
+func insertRestrictionAuditLog(ctx context.Context, session sqlx.Session, data *RestrictionAuditLog) error {
+	query := `INSERT INTO restriction_audit_logs (user_id, type, action, reason, expires_at, operator_id, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := session.ExecCtx(ctx, query, data.UserID, data.Type, data.Action, data.Reason, data.ExpiresAt, data.OperatorID, data.CreatedAt)
+	return err
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ UserRoleModel = (*customUserRoleModel)(nil)
+
+// 管理后台角色 / Admin Roles
+const (
+	RoleSuperAdmin = "super_admin" // 超级管理员，拥有全部权限，可以授予和撤销角色
+	RoleOperator   = "operator"    // 运营，管理交易对、币种和充值地址池
+	RoleFinance    = "finance"     // 财务，审核提现
+	RoleCompliance = "compliance"  // 合规，审核身份认证申请、调整用户认证等级、管理账户限制
+	RoleAuditor    = "auditor"     // 审计，只读访问管理后台
+)
+
+type (
+	// UserRoleModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customUserRoleModel.
+	UserRoleModel interface {
+		userRoleModel
+		// 自定义方法
+		FindRolesByUserID(ctx context.Context, userID uint64) ([]string, error)
+		Grant(ctx context.Context, data *UserRole, audit *RoleAuditLog) error
+		Revoke(ctx context.Context, userID uint64, role string, audit *RoleAuditLog) error
+	}
+
+	customUserRoleModel struct {
+		*defaultUserRoleModel
+	}
+
+	// UserRole 用户角色
+	UserRole struct {
+		ID        uint64    `db:"id"`         // 主键
+		UserID    uint64    `db:"user_id"`    // 用户ID
+		Role      string    `db:"role"`       // 角色
+		GrantedBy uint64    `db:"granted_by"` // 授予人用户ID，命令行授予时为0
+		CreatedAt time.Time `db:"created_at"` // 授予时间
+	}
+
+	userRoleModel interface {
+		Insert(ctx context.Context, data *UserRole) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*UserRole, error)
+	}
+
+	defaultUserRoleModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewUserRoleModel returns a model for the database table.
+func NewUserRoleModel(conn sqlx.SqlConn) UserRoleModel {
+	return &customUserRoleModel{
+		defaultUserRoleModel: newUserRoleModel(conn),
+	}
+}
+
+func newUserRoleModel(conn sqlx.SqlConn) *defaultUserRoleModel {
+	return &defaultUserRoleModel{
+		conn:  conn,
+		table: "user_roles",
+	}
+}
+
+func (m *defaultUserRoleModel) Insert(ctx context.Context, data *UserRole) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (user_id, role, granted_by, created_at) VALUES ($1, $2, $3, $4)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.UserID, data.Role, data.GrantedBy, data.CreatedAt)
+	return ret, err
+}
+
+func (m *defaultUserRoleModel) FindOne(ctx context.Context, id uint64) (*UserRole, error) {
+	query := `SELECT id, user_id, role, granted_by, created_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp UserRole
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindRolesByUserID 查询用户的全部角色，按角色名排序
+func (m *customUserRoleModel) FindRolesByUserID(ctx context.Context, userID uint64) ([]string, error) {
+	query := `SELECT role FROM ` + m.table + ` WHERE user_id = $1 ORDER BY role`
+	var resp []string
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, userID)
+	return resp, err
+}
+
+// Grant 授予角色并在同一事务中写入审计日志，角色已存在时返回ErrRoleAlreadyGranted
+func (m *customUserRoleModel) Grant(ctx context.Context, data *UserRole, audit *RoleAuditLog) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		query := `INSERT INTO ` + m.table + ` (user_id, role, granted_by, created_at) VALUES ($1, $2, $3, $4) ON CONFLICT (user_id, role) DO NOTHING`
+		result, err := session.ExecCtx(ctx, query, data.UserID, data.Role, data.GrantedBy, data.CreatedAt)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrRoleAlreadyGranted
+		}
+		return insertRoleAuditLog(ctx, session, audit)
+	})
+}
+
+// Revoke 撤销角色并在同一事务中写入审计日志，用户没有该角色时返回ErrRoleNotGranted
+func (m *customUserRoleModel) Revoke(ctx context.Context, userID uint64, role string, audit *RoleAuditLog) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		query := `DELETE FROM ` + m.table + ` WHERE user_id = $1 AND role = $2`
+		result, err := session.ExecCtx(ctx, query, userID, role)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrRoleNotGranted
+		}
+		return insertRoleAuditLog(ctx, session, audit)
+	})
+}
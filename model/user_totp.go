@@ -0,0 +1,164 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ UserTotpModel = (*customUserTotpModel)(nil)
+
+// 两步验证状态 / Two-Factor Status
+const (
+	UserTotpStatusPending int64 = 1 // 已生成密钥，等待用户验证首个验证码
+	UserTotpStatusEnabled int64 = 2 // 已启用
+)
+
+type (
+	// UserTotpModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customUserTotpModel.
+	UserTotpModel interface {
+		userTotpModel
+		// 自定义方法
+		FindOneByUserID(ctx context.Context, userID uint64) (*UserTotp, error)
+		Enable(ctx context.Context, id uint64, step int64, enabledAt time.Time) error
+		UseStep(ctx context.Context, id uint64, step int64) (bool, error)
+		UseRecoveryCode(ctx context.Context, id uint64, oldCodes, newCodes string) (bool, error)
+		DeleteByUserID(ctx context.Context, userID uint64) error
+	}
+
+	customUserTotpModel struct {
+		*defaultUserTotpModel
+	}
+
+	// UserTotp 用户的TOTP两步验证配置，每个用户最多一条
+	UserTotp struct {
+		ID              uint64       `db:"id"`               // 主键
+		UserID          uint64       `db:"user_id"`          // 用户ID
+		SecretEncrypted string       `db:"secret_encrypted"` // 加密后的TOTP密钥
+		RecoveryCodes   string       `db:"recovery_codes"`   // 未使用的恢复码SHA-256摘要，逗号分隔
+		Status          int64        `db:"status"`           // 状态：1-待验证，2-已启用
+		LastUsedStep    int64        `db:"last_used_step"`   // 最近一次通过校验的时间步，防止验证码重复使用
+		EnabledAt       sql.NullTime `db:"enabled_at"`       // 启用时间
+		CreatedAt       time.Time    `db:"created_at"`       // 创建时间
+		UpdatedAt       time.Time    `db:"updated_at"`       // 更新时间
+	}
+
+	userTotpModel interface {
+		Insert(ctx context.Context, data *UserTotp) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*UserTotp, error)
+	}
+
+	defaultUserTotpModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// RecoveryCodeHashes 返回未使用的恢复码摘要
+func (t *UserTotp) RecoveryCodeHashes() []string {
+	return splitList(t.RecoveryCodes)
+}
+
+// NewUserTotpModel returns a model for the database table.
+func NewUserTotpModel(conn sqlx.SqlConn) UserTotpModel {
+	return &customUserTotpModel{
+		defaultUserTotpModel: newUserTotpModel(conn),
+	}
+}
+
+func newUserTotpModel(conn sqlx.SqlConn) *defaultUserTotpModel {
+	return &defaultUserTotpModel{
+		conn:  conn,
+		table: "user_totp",
+	}
+}
+
+func (m *defaultUserTotpModel) Insert(ctx context.Context, data *UserTotp) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (user_id, secret_encrypted, recovery_codes, status, last_used_step, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.UserID, data.SecretEncrypted, data.RecoveryCodes, data.Status, data.LastUsedStep, data.CreatedAt, data.UpdatedAt)
+	return ret, err
+}
+
+func (m *defaultUserTotpModel) FindOne(ctx context.Context, id uint64) (*UserTotp, error) {
+	query := `SELECT id, user_id, secret_encrypted, recovery_codes, status, last_used_step, enabled_at, created_at, updated_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp UserTotp
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindOneByUserID 查询用户的两步验证配置
+func (m *customUserTotpModel) FindOneByUserID(ctx context.Context, userID uint64) (*UserTotp, error) {
+	query := `SELECT id, user_id, secret_encrypted, recovery_codes, status, last_used_step, enabled_at, created_at, updated_at FROM ` + m.table + ` WHERE user_id = $1 LIMIT 1`
+	var resp UserTotp
+	err := m.conn.QueryRowCtx(ctx, &resp, query, userID)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// Enable 启用两步验证，并记录验证时使用的时间步
+func (m *customUserTotpModel) Enable(ctx context.Context, id uint64, step int64, enabledAt time.Time) error {
+	query := `UPDATE ` + m.table + ` SET status = $1, last_used_step = $2, enabled_at = $3, updated_at = $3 WHERE id = $4 AND status = $5`
+	result, err := m.conn.ExecCtx(ctx, query, UserTotpStatusEnabled, step, enabledAt, id, UserTotpStatusPending)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UseStep 记录已使用的时间步，只有step大于已记录的值时才更新，返回false表示验证码已被使用
+func (m *customUserTotpModel) UseStep(ctx context.Context, id uint64, step int64) (bool, error) {
+	query := `UPDATE ` + m.table + ` SET last_used_step = $1, updated_at = $2 WHERE id = $3 AND last_used_step < $1`
+	result, err := m.conn.ExecCtx(ctx, query, step, time.Now(), id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// UseRecoveryCode 更新剩余的恢复码，oldCodes与当前值不一致时不更新并返回false，防止并发重复使用
+func (m *customUserTotpModel) UseRecoveryCode(ctx context.Context, id uint64, oldCodes, newCodes string) (bool, error) {
+	query := `UPDATE ` + m.table + ` SET recovery_codes = $1, updated_at = $2 WHERE id = $3 AND recovery_codes = $4`
+	result, err := m.conn.ExecCtx(ctx, query, newCodes, time.Now(), id, oldCodes)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// DeleteByUserID 删除用户的两步验证配置，用于关闭两步验证或重新绑定
+func (m *customUserTotpModel) DeleteByUserID(ctx context.Context, userID uint64) error {
+	query := `DELETE FROM ` + m.table + ` WHERE user_id = $1`
+	_, err := m.conn.ExecCtx(ctx, query, userID)
+	return err
+}
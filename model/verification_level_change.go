@@ -0,0 +1,117 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ VerificationLevelChangeModel = (*customVerificationLevelChangeModel)(nil)
+
+type (
+	// VerificationLevelChangeModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customVerificationLevelChangeModel.
+	VerificationLevelChangeModel interface {
+		verificationLevelChangeModel
+		// 自定义方法
+		FindByUserID(ctx context.Context, userID uint64) ([]*VerificationLevelChange, error)
+		SetLevel(ctx context.Context, data *VerificationLevelChange) error
+	}
+
+	customVerificationLevelChangeModel struct {
+		*defaultVerificationLevelChangeModel
+	}
+
+	// VerificationLevelChange 用户认证等级变更记录，与等级变更在同一事务中写入
+	VerificationLevelChange struct {
+		ID           uint64    `db:"id"`            // 主键
+		UserID       uint64    `db:"user_id"`       // 用户ID
+		FromLevel    int64     `db:"from_level"`    // 变更前等级
+		ToLevel      int64     `db:"to_level"`      // 变更后等级
+		SubmissionID uint64    `db:"submission_id"` // 审核通过的认证申请ID，管理员直接调整时为0
+		ReviewerID   uint64    `db:"reviewer_id"`   // 审核人或操作人用户ID
+		Reason       string    `db:"reason"`        // 变更原因
+		CreatedAt    time.Time `db:"created_at"`    // 记录时间
+	}
+
+	verificationLevelChangeModel interface {
+		Insert(ctx context.Context, data *VerificationLevelChange) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*VerificationLevelChange, error)
+	}
+
+	defaultVerificationLevelChangeModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewVerificationLevelChangeModel returns a model for the database table.
+func NewVerificationLevelChangeModel(conn sqlx.SqlConn) VerificationLevelChangeModel {
+	return &customVerificationLevelChangeModel{
+		defaultVerificationLevelChangeModel: newVerificationLevelChangeModel(conn),
+	}
+}
+
+func newVerificationLevelChangeModel(conn sqlx.SqlConn) *defaultVerificationLevelChangeModel {
+	return &defaultVerificationLevelChangeModel{
+		conn:  conn,
+		table: "verification_level_changes",
+	}
+}
+
+func (m *defaultVerificationLevelChangeModel) Insert(ctx context.Context, data *VerificationLevelChange) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (user_id, from_level, to_level, submission_id, reviewer_id, reason, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.UserID, data.FromLevel, data.ToLevel, data.SubmissionID, data.ReviewerID, data.Reason, data.CreatedAt)
+	return ret, err
+}
+
+func (m *defaultVerificationLevelChangeModel) FindOne(ctx context.Context, id uint64) (*VerificationLevelChange, error) {
+	query := `SELECT id, user_id, from_level, to_level, submission_id, reviewer_id, reason, created_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp VerificationLevelChange
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindByUserID 查询用户的认证等级变更记录，按时间倒序
+func (m *customVerificationLevelChangeModel) FindByUserID(ctx context.Context, userID uint64) ([]*VerificationLevelChange, error) {
+	query := `SELECT id, user_id, from_level, to_level, submission_id, reviewer_id, reason, created_at FROM ` + m.table + ` WHERE user_id = $1 ORDER BY id DESC`
+	var resp []*VerificationLevelChange
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, userID)
+	return resp, err
+}
+
+// SetLevel 将用户认证等级从FromLevel调整为ToLevel并在同一事务中写入变更记录
+// 用户当前等级已不是FromLevel时返回ErrVerificationLevelChanged，避免覆盖并发的审核结果
+func (m *customVerificationLevelChangeModel) SetLevel(ctx context.Context, data *VerificationLevelChange) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, session sqlx.Session) error {
+		query := `UPDATE users SET verification_level = $1, updated_at = $2 WHERE id = $3 AND verification_level = $4`
+		result, err := session.ExecCtx(ctx, query, data.ToLevel, data.CreatedAt, data.UserID, data.FromLevel)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrVerificationLevelChanged
+		}
+		return insertVerificationLevelChange(ctx, session, data)
+	})
+}
+
+// insertVerificationLevelChange 在事务中写入认证等级变更记录
+func insertVerificationLevelChange(ctx context.Context, session sqlx.Session, data *VerificationLevelChange) error {
+	query := `INSERT INTO verification_level_changes (user_id, from_level, to_level, submission_id, reviewer_id, reason, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := session.ExecCtx(ctx, query, data.UserID, data.FromLevel, data.ToLevel, data.SubmissionID, data.ReviewerID, data.Reason, data.CreatedAt)
+	return err
+}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ WithdrawalAddressModel = (*customWithdrawalAddressModel)(nil)
+
+type (
+	// WithdrawalAddressModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customWithdrawalAddressModel.
+	WithdrawalAddressModel interface {
+		withdrawalAddressModel
+		// 自定义方法
+		FindByUserID(ctx context.Context, userID uint64) ([]*WithdrawalAddress, error)
+		FindByUserAndAddress(ctx context.Context, userID uint64, network, address string) (*WithdrawalAddress, error)
+		DeleteByUser(ctx context.Context, userID, id uint64) error
+	}
+
+	customWithdrawalAddressModel struct {
+		*defaultWithdrawalAddressModel
+	}
+
+	// WithdrawalAddress 用户提现地址簿条目
+	// 新添加的地址在冷却期结束（AvailableAt）之前不能用于提现
+	WithdrawalAddress struct {
+		ID          uint64    `db:"id"`           // 地址ID，主键
+		UserID      uint64    `db:"user_id"`      // 用户ID
+		Network     string    `db:"network"`      // 网络代码
+		Address     string    `db:"address"`      // 提现地址
+		Label       string    `db:"label"`        // 地址备注
+		AvailableAt time.Time `db:"available_at"` // 冷却期结束时间，之后可用于提现
+		CreatedAt   time.Time `db:"created_at"`   // 添加时间
+	}
+
+	withdrawalAddressModel interface {
+		Insert(ctx context.Context, data *WithdrawalAddress) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*WithdrawalAddress, error)
+	}
+
+	defaultWithdrawalAddressModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewWithdrawalAddressModel returns a model for the database table.
+func NewWithdrawalAddressModel(conn sqlx.SqlConn) WithdrawalAddressModel {
+	return &customWithdrawalAddressModel{
+		defaultWithdrawalAddressModel: newWithdrawalAddressModel(conn),
+	}
+}
+
+func newWithdrawalAddressModel(conn sqlx.SqlConn) *defaultWithdrawalAddressModel {
+	return &defaultWithdrawalAddressModel{
+		conn:  conn,
+		table: "withdrawal_addresses",
+	}
+}
+
+func (m *defaultWithdrawalAddressModel) Insert(ctx context.Context, data *WithdrawalAddress) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (user_id, network, address, label, available_at, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.UserID, data.Network, data.Address, data.Label, data.AvailableAt, data.CreatedAt)
+	return ret, err
+}
+
+func (m *defaultWithdrawalAddressModel) FindOne(ctx context.Context, id uint64) (*WithdrawalAddress, error) {
+	query := `SELECT id, user_id, network, address, label, available_at, created_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp WithdrawalAddress
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindByUserID 查询用户的提现地址簿，按添加时间倒序
+func (m *customWithdrawalAddressModel) FindByUserID(ctx context.Context, userID uint64) ([]*WithdrawalAddress, error) {
+	query := `SELECT id, user_id, network, address, label, available_at, created_at FROM ` + m.table + ` WHERE user_id = $1 ORDER BY created_at DESC, id DESC`
+	var resp []*WithdrawalAddress
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, userID)
+	return resp, err
+}
+
+// FindByUserAndAddress 查询用户地址簿中指定网络上的地址
+func (m *customWithdrawalAddressModel) FindByUserAndAddress(ctx context.Context, userID uint64, network, address string) (*WithdrawalAddress, error) {
+	query := `SELECT id, user_id, network, address, label, available_at, created_at FROM ` + m.table + ` WHERE user_id = $1 AND network = $2 AND address = $3 LIMIT 1`
+	var resp WithdrawalAddress
+	err := m.conn.QueryRowCtx(ctx, &resp, query, userID, network, address)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// DeleteByUser 删除用户地址簿中的地址，地址不属于该用户时返回ErrNotFound
+func (m *customWithdrawalAddressModel) DeleteByUser(ctx context.Context, userID, id uint64) error {
+	query := `DELETE FROM ` + m.table + ` WHERE id = $1 AND user_id = $2`
+	result, err := m.conn.ExecCtx(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+var _ WithdrawalAuditLogModel = (*customWithdrawalAuditLogModel)(nil)
+
+type (
+	// WithdrawalAuditLogModel is an interface to be customized, add more methods here,
+	// and implement the added methods in customWithdrawalAuditLogModel.
+	WithdrawalAuditLogModel interface {
+		withdrawalAuditLogModel
+		// 自定义方法
+		FindByTransactionID(ctx context.Context, transactionID string) ([]*WithdrawalAuditLog, error)
+		WithSession(session sqlx.Session) WithdrawalAuditLogModel
+	}
+
+	customWithdrawalAuditLogModel struct {
+		*defaultWithdrawalAuditLogModel
+	}
+
+	// WithdrawalAuditLog 提现审核日志模型，记录提现每一次状态流转
+	WithdrawalAuditLog struct {
+		ID            uint64    `db:"id"`             // 日志ID，主键
+		TransactionID string    `db:"transaction_id"` // 提现交易ID
+		FromStatus    int64     `db:"from_status"`    // 变更前状态，创建时为0
+		ToStatus      int64     `db:"to_status"`      // 变更后状态
+		OperatorID    uint64    `db:"operator_id"`    // 操作人用户ID，系统任务为0
+		TxHash        string    `db:"tx_hash"`        // 区块链交易哈希
+		Remark        string    `db:"remark"`         // 备注，如拒绝原因
+		CreatedAt     time.Time `db:"created_at"`     // 记录时间
+	}
+
+	withdrawalAuditLogModel interface {
+		Insert(ctx context.Context, data *WithdrawalAuditLog) (sql.Result, error)
+		FindOne(ctx context.Context, id uint64) (*WithdrawalAuditLog, error)
+	}
+
+	defaultWithdrawalAuditLogModel struct {
+		conn  sqlx.SqlConn
+		table string
+	}
+)
+
+// NewWithdrawalAuditLogModel returns a model for the database table.
+func NewWithdrawalAuditLogModel(conn sqlx.SqlConn) WithdrawalAuditLogModel {
+	return &customWithdrawalAuditLogModel{
+		defaultWithdrawalAuditLogModel: newWithdrawalAuditLogModel(conn),
+	}
+}
+
+func newWithdrawalAuditLogModel(conn sqlx.SqlConn) *defaultWithdrawalAuditLogModel {
+	return &defaultWithdrawalAuditLogModel{
+		conn:  conn,
+		table: "withdrawal_audit_logs",
+	}
+}
+
+func (m *defaultWithdrawalAuditLogModel) Insert(ctx context.Context, data *WithdrawalAuditLog) (sql.Result, error) {
+	query := `INSERT INTO ` + m.table + ` (transaction_id, from_status, to_status, operator_id, tx_hash, remark, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	ret, err := m.conn.ExecCtx(ctx, query, data.TransactionID, data.FromStatus, data.ToStatus, data.OperatorID, data.TxHash, data.Remark, data.CreatedAt)
+	return ret, err
+}
+
+func (m *defaultWithdrawalAuditLogModel) FindOne(ctx context.Context, id uint64) (*WithdrawalAuditLog, error) {
+	query := `SELECT id, transaction_id, from_status, to_status, operator_id, tx_hash, remark, created_at FROM ` + m.table + ` WHERE id = $1 LIMIT 1`
+	var resp WithdrawalAuditLog
+	err := m.conn.QueryRowCtx(ctx, &resp, query, id)
+	switch err {
+	case nil:
+		return &resp, nil
+	case sqlx.ErrNotFound:
+		return nil, ErrNotFound
+	default:
+		return nil, err
+	}
+}
+
+// FindByTransactionID 查询提现的全部审核日志，按时间先后排序
+func (m *customWithdrawalAuditLogModel) FindByTransactionID(ctx context.Context, transactionID string) ([]*WithdrawalAuditLog, error) {
+	query := `SELECT id, transaction_id, from_status, to_status, operator_id, tx_hash, remark, created_at FROM ` + m.table + ` WHERE transaction_id = $1 ORDER BY created_at ASC, id ASC`
+	var resp []*WithdrawalAuditLog
+	err := m.conn.QueryRowsCtx(ctx, &resp, query, transactionID)
+	return resp, err
+}
+
+// WithSession 返回在事务session中写入审核日志的模型
+func (m *customWithdrawalAuditLogModel) WithSession(session sqlx.Session) WithdrawalAuditLogModel {
+	return NewWithdrawalAuditLogModel(sqlx.NewSqlConnFromSession(session))
+}
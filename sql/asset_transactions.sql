@@ -4,12 +4,16 @@ CREATE TABLE IF NOT EXISTS asset_transactions (
     user_id BIGINT NOT NULL,
     transaction_id VARCHAR(64) NOT NULL UNIQUE,
     currency VARCHAR(10) NOT NULL,
-    type SMALLINT NOT NULL, -- 1-充值，2-提现
+    network VARCHAR(20) NOT NULL DEFAULT '',
+    type SMALLINT NOT NULL, -- 1-充值，2-提现，3-转账转出，4-转账转入，5-母子账户划出，6-母子账户划入
     amount DECIMAL(36,18) NOT NULL,
     fee DECIMAL(36,18) NOT NULL DEFAULT 0,
-    status SMALLINT NOT NULL DEFAULT 1, -- 1-待处理，2-成功，3-失败，4-已取消
+    status SMALLINT NOT NULL DEFAULT 1, -- 1-待处理，2-成功，3-失败，4-已取消，5-已审核，6-广播中，7-已拒绝，8-已回滚，9-待用户确认
     address VARCHAR(255) DEFAULT '',
     tx_hash VARCHAR(128) DEFAULT '',
+    block_height BIGINT NOT NULL DEFAULT 0,
+    confirmations BIGINT NOT NULL DEFAULT 0,
+    counterparty_id BIGINT NOT NULL DEFAULT 0,
     remark TEXT DEFAULT '',
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
@@ -22,6 +26,9 @@ CREATE INDEX IF NOT EXISTS idx_asset_transactions_user_type ON asset_transaction
 CREATE INDEX IF NOT EXISTS idx_asset_transactions_currency ON asset_transactions(currency);
 CREATE INDEX IF NOT EXISTS idx_asset_transactions_status ON asset_transactions(status);
 CREATE INDEX IF NOT EXISTS idx_asset_transactions_created_at ON asset_transactions(created_at);
+CREATE INDEX IF NOT EXISTS idx_asset_transactions_user_type_created ON asset_transactions(user_id, type, created_at);
+CREATE INDEX IF NOT EXISTS idx_asset_transactions_type_status ON asset_transactions(type, status);
+CREATE INDEX IF NOT EXISTS idx_asset_transactions_network_block ON asset_transactions(network, block_height) WHERE block_height > 0;
 
 -- 添加外键约束（假设users表存在）
 -- ALTER TABLE asset_transactions ADD CONSTRAINT fk_asset_transactions_user_id 
@@ -29,10 +36,10 @@ CREATE INDEX IF NOT EXISTS idx_asset_transactions_created_at ON asset_transactio
 
 -- 添加检查约束
 ALTER TABLE asset_transactions ADD CONSTRAINT chk_asset_transactions_type 
-    CHECK (type IN (1, 2));
+    CHECK (type IN (1, 2, 3, 4, 5, 6));
 
 ALTER TABLE asset_transactions ADD CONSTRAINT chk_asset_transactions_status 
-    CHECK (status IN (1, 2, 3, 4));
+    CHECK (status IN (1, 2, 3, 4, 5, 6, 7, 8, 9));
 
 ALTER TABLE asset_transactions ADD CONSTRAINT chk_asset_transactions_amount 
     CHECK (amount > 0);
@@ -46,12 +53,40 @@ COMMENT ON COLUMN asset_transactions.id IS '交易记录ID，主键';
 COMMENT ON COLUMN asset_transactions.user_id IS '用户ID，关联users表';
 COMMENT ON COLUMN asset_transactions.transaction_id IS '交易ID，唯一标识';
 COMMENT ON COLUMN asset_transactions.currency IS '币种代码，如BTC、ETH、USDT等';
-COMMENT ON COLUMN asset_transactions.type IS '交易类型：1-充值，2-提现';
+COMMENT ON COLUMN asset_transactions.network IS '充提网络，如BTC、ERC20、TRC20';
+COMMENT ON COLUMN asset_transactions.type IS '交易类型：1-充值，2-提现，3-站内转账转出，4-站内转账转入，5-母子账户划转转出，6-母子账户划转转入';
 COMMENT ON COLUMN asset_transactions.amount IS '交易金额';
 COMMENT ON COLUMN asset_transactions.fee IS '手续费';
-COMMENT ON COLUMN asset_transactions.status IS '交易状态：1-待处理，2-成功，3-失败，4-已取消';
+COMMENT ON COLUMN asset_transactions.status IS '交易状态：1-待处理，2-成功，3-失败，4-已取消，5-已审核，6-广播中，7-已拒绝，8-已回滚，9-待用户确认；链上充值按 待确认(1) -> 成功(2) 流转，被链重组移除时为已取消(4)或已回滚(8)；提现按 待用户确认(9) -> 待审核(1) -> 已审核(5) -> 广播中(6) -> 成功(2)/失败(3) 流转，待用户确认时可取消(4)，待审核时可拒绝(7)';
 COMMENT ON COLUMN asset_transactions.address IS '地址（提现时有值，充值时可为空）';
 COMMENT ON COLUMN asset_transactions.tx_hash IS '区块链交易哈希';
+COMMENT ON COLUMN asset_transactions.block_height IS '链上充值所在区块高度，手动充值和提现为0';
+COMMENT ON COLUMN asset_transactions.confirmations IS '链上充值最近一次扫描时的确认数';
+COMMENT ON COLUMN asset_transactions.counterparty_id IS '站内转账的对方用户ID，充提记录为0';
 COMMENT ON COLUMN asset_transactions.remark IS '备注信息';
 COMMENT ON COLUMN asset_transactions.created_at IS '创建时间';
-COMMENT ON COLUMN asset_transactions.updated_at IS '更新时间';
\ No newline at end of file
+COMMENT ON COLUMN asset_transactions.updated_at IS '更新时间';
+
+-- 创建提现审核日志表：记录提现每一次状态流转及操作人
+CREATE TABLE IF NOT EXISTS withdrawal_audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id VARCHAR(64) NOT NULL, -- 提现交易ID
+    from_status SMALLINT NOT NULL,       -- 变更前状态，创建时为0
+    to_status SMALLINT NOT NULL,         -- 变更后状态
+    operator_id BIGINT NOT NULL DEFAULT 0, -- 操作人用户ID，系统任务为0
+    tx_hash VARCHAR(128) DEFAULT '',
+    remark TEXT DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_withdrawal_audit_logs_transaction_id ON withdrawal_audit_logs(transaction_id);
+
+COMMENT ON TABLE withdrawal_audit_logs IS '提现审核日志表，记录提现状态流转的完整轨迹';
+COMMENT ON COLUMN withdrawal_audit_logs.id IS '日志ID，主键';
+COMMENT ON COLUMN withdrawal_audit_logs.transaction_id IS '提现交易ID，关联asset_transactions.transaction_id';
+COMMENT ON COLUMN withdrawal_audit_logs.from_status IS '变更前状态，提现申请创建时为0';
+COMMENT ON COLUMN withdrawal_audit_logs.to_status IS '变更后状态，取值同asset_transactions.status';
+COMMENT ON COLUMN withdrawal_audit_logs.operator_id IS '操作人用户ID，用户申请时为用户本人，系统任务为0';
+COMMENT ON COLUMN withdrawal_audit_logs.tx_hash IS '区块链交易哈希，广播后有值';
+COMMENT ON COLUMN withdrawal_audit_logs.remark IS '备注，如拒绝原因、失败原因';
+COMMENT ON COLUMN withdrawal_audit_logs.created_at IS '记录时间';
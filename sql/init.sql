@@ -11,6 +11,12 @@ CREATE TABLE IF NOT EXISTS users (
     password VARCHAR(255) NOT NULL,                           -- 密码哈希值
     nickname VARCHAR(100),                                    -- 用户昵称
     status INTEGER DEFAULT 1,                                 -- 用户状态：1-正常，2-禁用，3-删除
+    parent_id INTEGER NOT NULL DEFAULT 0,                     -- 母账户ID，0表示普通账户
+    withdraw_whitelist_only BOOLEAN NOT NULL DEFAULT FALSE,   -- 是否只允许提现到地址簿中的地址
+    withdraw_whitelist_until TIMESTAMP,                       -- 关闭提现白名单后仍按白名单校验的截止时间
+    verification_level INTEGER NOT NULL DEFAULT 0,            -- 认证等级：0-未认证，1-基础认证，2-高级认证
+    password_changed_at TIMESTAMP,                            -- 最近一次修改密码的时间
+    email_verified_at TIMESTAMP,                              -- 邮箱验证时间，未验证时为空
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 创建时间
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP            -- 更新时间
 );
@@ -21,6 +27,12 @@ COMMENT ON COLUMN users.email IS '用户邮箱，唯一标识';
 COMMENT ON COLUMN users.password IS '密码哈希值，使用bcrypt加密';
 COMMENT ON COLUMN users.nickname IS '用户昵称，显示名称';
 COMMENT ON COLUMN users.status IS '用户状态：1-正常，2-禁用，3-删除';
+COMMENT ON COLUMN users.parent_id IS '母账户ID，0表示普通账户；子账户有独立的登录凭证、余额和订单，由母账户管理';
+COMMENT ON COLUMN users.withdraw_whitelist_only IS '是否开启提现白名单，开启后只能提现到地址簿中已过冷却期的地址';
+COMMENT ON COLUMN users.withdraw_whitelist_until IS '关闭提现白名单的冷却截止时间，冷却期内仍只能提现到地址簿中的地址，为空表示没有冷却';
+COMMENT ON COLUMN users.verification_level IS '认证等级：0-未认证，1-基础认证，2-高级认证，决定提现限额';
+COMMENT ON COLUMN users.password_changed_at IS '最近一次修改密码的时间，修改后一段时间内禁止提现';
+COMMENT ON COLUMN users.email_verified_at IS '邮箱验证时间，未验证邮箱的用户不能交易和提现；子账户由母账户创建，创建时即视为已验证';
 COMMENT ON COLUMN users.created_at IS '账户创建时间';
 COMMENT ON COLUMN users.updated_at IS '最后更新时间';
 
@@ -43,6 +55,340 @@ COMMENT ON COLUMN balances.available IS '可用余额，可以用于交易的金
 COMMENT ON COLUMN balances.frozen IS '冻结余额，挂单时冻结的金额';
 COMMENT ON COLUMN balances.updated_at IS '余额最后更新时间';
 
+-- 币种配置表
+CREATE TABLE IF NOT EXISTS currencies (
+    code VARCHAR(10) PRIMARY KEY,                             -- 币种代码，如BTC
+    name VARCHAR(50) NOT NULL,                                -- 币种名称
+    precision INTEGER NOT NULL DEFAULT 8,                     -- 金额精度，小数位数
+    deposit_enabled BOOLEAN NOT NULL DEFAULT TRUE,            -- 是否开放充值（总开关）
+    withdraw_enabled BOOLEAN NOT NULL DEFAULT TRUE,           -- 是否开放提现（总开关）
+    max_deposit VARCHAR(50) NOT NULL DEFAULT '0',             -- 最大充值金额，0表示不限制
+    max_withdraw VARCHAR(50) NOT NULL DEFAULT '0',            -- 最大提现金额，0表示不限制
+    status INTEGER NOT NULL DEFAULT 1,                        -- 状态：1-启用，2-停用
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 创建时间
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP            -- 更新时间
+);
+
+COMMENT ON TABLE currencies IS '币种配置表，充值、提现和交易对创建都以此表为准；按网络区分的充提参数见currency_networks表';
+COMMENT ON COLUMN currencies.code IS '币种代码，主键，2-10位大写字母';
+COMMENT ON COLUMN currencies.name IS '币种名称';
+COMMENT ON COLUMN currencies.precision IS '充提金额精度，小数点后位数';
+COMMENT ON COLUMN currencies.deposit_enabled IS '是否开放充值，关闭后所有网络均不可充值';
+COMMENT ON COLUMN currencies.withdraw_enabled IS '是否开放提现，关闭后所有网络均不可提现';
+COMMENT ON COLUMN currencies.max_deposit IS '单笔最大充值金额，0表示不限制';
+COMMENT ON COLUMN currencies.max_withdraw IS '单笔最大提现金额，0表示不限制';
+COMMENT ON COLUMN currencies.status IS '币种状态：1-启用，2-停用（不可充提，也不能用于新建交易对）';
+COMMENT ON COLUMN currencies.created_at IS '创建时间';
+COMMENT ON COLUMN currencies.updated_at IS '最后更新时间';
+
+-- 币种网络配置表
+CREATE TABLE IF NOT EXISTS currency_networks (
+    id BIGSERIAL PRIMARY KEY,                                 -- 记录ID
+    currency VARCHAR(10) NOT NULL,                            -- 币种代码
+    network VARCHAR(20) NOT NULL,                             -- 网络代码，如ERC20、TRC20
+    address_type VARCHAR(20) NOT NULL,                        -- 地址格式：bitcoin、evm、tron
+    deposit_enabled BOOLEAN NOT NULL DEFAULT TRUE,            -- 是否开放充值
+    withdraw_enabled BOOLEAN NOT NULL DEFAULT TRUE,           -- 是否开放提现
+    withdraw_fee_fixed VARCHAR(50) NOT NULL DEFAULT '0',      -- 提现固定手续费
+    withdraw_fee_rate VARCHAR(50) NOT NULL DEFAULT '0',       -- 提现比例手续费
+    min_deposit VARCHAR(50) NOT NULL DEFAULT '0',             -- 最小充值金额
+    min_withdraw VARCHAR(50) NOT NULL DEFAULT '0',            -- 最小提现金额
+    confirmations INTEGER NOT NULL DEFAULT 0,                 -- 充值所需区块确认数
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 创建时间
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 更新时间
+    UNIQUE(currency, network)                                 -- 币种+网络唯一索引
+);
+
+COMMENT ON TABLE currency_networks IS '币种网络配置表，同一币种在不同网络上的地址格式、手续费、最小金额和确认数各不相同';
+COMMENT ON COLUMN currency_networks.id IS '记录ID，自增主键';
+COMMENT ON COLUMN currency_networks.currency IS '币种代码，关联currencies表';
+COMMENT ON COLUMN currency_networks.network IS '网络代码，2-20位大写字母或数字，如BTC、ERC20、TRC20';
+COMMENT ON COLUMN currency_networks.address_type IS '地址格式：bitcoin-比特币地址，evm-以太坊兼容链地址，tron-波场地址';
+COMMENT ON COLUMN currency_networks.deposit_enabled IS '该网络是否开放充值';
+COMMENT ON COLUMN currency_networks.withdraw_enabled IS '该网络是否开放提现';
+COMMENT ON COLUMN currency_networks.withdraw_fee_fixed IS '提现固定手续费，手续费 = 固定手续费 + 提现金额 × 比例手续费';
+COMMENT ON COLUMN currency_networks.withdraw_fee_rate IS '提现比例手续费，如0.001表示0.1%';
+COMMENT ON COLUMN currency_networks.min_deposit IS '单笔最小充值金额';
+COMMENT ON COLUMN currency_networks.min_withdraw IS '单笔最小提现金额';
+COMMENT ON COLUMN currency_networks.confirmations IS '充值入账所需的区块确认数';
+COMMENT ON COLUMN currency_networks.created_at IS '创建时间';
+COMMENT ON COLUMN currency_networks.updated_at IS '最后更新时间';
+
+-- 充值地址表
+CREATE TABLE IF NOT EXISTS deposit_addresses (
+    id BIGSERIAL PRIMARY KEY,                                 -- 地址记录ID
+    user_id INTEGER NOT NULL DEFAULT 0,                       -- 用户ID，0表示地址池中未分配
+    currency VARCHAR(10) NOT NULL DEFAULT '',                 -- 币种代码，未分配时为空
+    network VARCHAR(20) NOT NULL,                             -- 网络代码
+    address VARCHAR(128) NOT NULL,                            -- 链上地址
+    derivation_index BIGINT NOT NULL DEFAULT -1,              -- 派生序号，地址池导入的地址为-1
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 创建时间
+    assigned_at TIMESTAMP,                                    -- 分配时间
+    UNIQUE(network, address)                                  -- 同一网络上地址唯一
+);
+
+COMMENT ON TABLE deposit_addresses IS '充值地址表，每个用户每个币种每个网络分配一个地址，来自预生成的地址池或按派生序号生成';
+COMMENT ON COLUMN deposit_addresses.id IS '地址记录ID，自增主键';
+COMMENT ON COLUMN deposit_addresses.user_id IS '地址所属用户ID，0表示地址池中尚未分配的地址';
+COMMENT ON COLUMN deposit_addresses.currency IS '币种代码，分配时写入';
+COMMENT ON COLUMN deposit_addresses.network IS '网络代码，关联currency_networks.network';
+COMMENT ON COLUMN deposit_addresses.address IS '链上充值地址';
+COMMENT ON COLUMN deposit_addresses.derivation_index IS 'HD派生序号，同一网络内递增；地址池导入的地址为-1';
+COMMENT ON COLUMN deposit_addresses.created_at IS '创建或导入时间';
+COMMENT ON COLUMN deposit_addresses.assigned_at IS '分配给用户的时间，未分配时为空';
+
+-- 充值扫描进度表
+CREATE TABLE IF NOT EXISTS chain_scan_cursors (
+    network VARCHAR(20) PRIMARY KEY,                          -- 网络代码
+    height BIGINT NOT NULL DEFAULT 0,                         -- 最近扫描时的最新区块高度
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP            -- 更新时间
+);
+
+COMMENT ON TABLE chain_scan_cursors IS '充值扫描进度表，每个网络一条记录';
+COMMENT ON COLUMN chain_scan_cursors.network IS '网络代码，主键';
+COMMENT ON COLUMN chain_scan_cursors.height IS '最近一次扫描时的最新区块高度，下次从该高度减去重组窗口处重新扫描';
+COMMENT ON COLUMN chain_scan_cursors.updated_at IS '最后扫描时间';
+
+-- 提现地址簿表
+CREATE TABLE IF NOT EXISTS withdrawal_addresses (
+    id BIGSERIAL PRIMARY KEY,                                 -- 地址ID
+    user_id INTEGER NOT NULL REFERENCES users(id),            -- 用户ID，外键
+    network VARCHAR(20) NOT NULL,                             -- 网络代码
+    address VARCHAR(128) NOT NULL,                            -- 提现地址
+    label VARCHAR(64) NOT NULL DEFAULT '',                    -- 地址备注
+    available_at TIMESTAMP NOT NULL,                          -- 冷却期结束时间
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 添加时间
+    UNIQUE(user_id, network, address)                         -- 同一用户同一网络上地址唯一
+);
+
+COMMENT ON TABLE withdrawal_addresses IS '提现地址簿表，新添加的地址在冷却期结束前不能用于提现';
+COMMENT ON COLUMN withdrawal_addresses.id IS '地址ID，自增主键';
+COMMENT ON COLUMN withdrawal_addresses.user_id IS '地址所属用户ID';
+COMMENT ON COLUMN withdrawal_addresses.network IS '网络代码，关联currency_networks.network';
+COMMENT ON COLUMN withdrawal_addresses.address IS '提现地址，添加时按网络的地址格式校验';
+COMMENT ON COLUMN withdrawal_addresses.label IS '用户填写的地址备注';
+COMMENT ON COLUMN withdrawal_addresses.available_at IS '冷却期结束时间，添加时间加上配置的冷却时长';
+COMMENT ON COLUMN withdrawal_addresses.created_at IS '添加时间';
+
+-- API密钥表
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGSERIAL PRIMARY KEY,                                 -- 主键
+    user_id INTEGER NOT NULL REFERENCES users(id),            -- 所属用户ID
+    key_id VARCHAR(64) UNIQUE NOT NULL,                       -- 公开的密钥ID
+    secret_encrypted TEXT NOT NULL,                           -- 加密后的密钥
+    label VARCHAR(64) NOT NULL DEFAULT '',                    -- 备注
+    permissions VARCHAR(64) NOT NULL DEFAULT 'read',          -- 权限列表，逗号分隔
+    ip_whitelist TEXT NOT NULL DEFAULT '',                    -- IP白名单，逗号分隔
+    status INTEGER NOT NULL DEFAULT 1,                        -- 状态：1-有效，2-已删除
+    expires_at TIMESTAMP,                                     -- 过期时间
+    last_used_at TIMESTAMP,                                   -- 最近一次使用时间
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 创建时间
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP            -- 更新时间
+);
+
+COMMENT ON TABLE api_keys IS 'API密钥表，请求使用HMAC-SHA256签名，服务端加密保存密钥';
+COMMENT ON COLUMN api_keys.id IS '主键';
+COMMENT ON COLUMN api_keys.user_id IS '所属用户ID，子账户可以创建自己的API密钥';
+COMMENT ON COLUMN api_keys.key_id IS '公开的密钥ID，请求时放在X-API-KEY请求头中';
+COMMENT ON COLUMN api_keys.secret_encrypted IS '使用AES-256-GCM加密的密钥，加密密钥来自配置TwoFactor.EncryptionKey，明文密钥只在创建时返回一次';
+COMMENT ON COLUMN api_keys.label IS '用户填写的备注';
+COMMENT ON COLUMN api_keys.permissions IS '权限列表，逗号分隔：read-查询，trade-交易，withdraw-提现及转账';
+COMMENT ON COLUMN api_keys.ip_whitelist IS 'IP白名单，逗号分隔的IP或CIDR，为空表示不限制来源IP';
+COMMENT ON COLUMN api_keys.status IS '状态：1-有效，2-已删除';
+COMMENT ON COLUMN api_keys.expires_at IS '过期时间，为空表示永不过期';
+COMMENT ON COLUMN api_keys.last_used_at IS '最近一次通过签名校验的时间';
+COMMENT ON COLUMN api_keys.created_at IS '创建时间';
+COMMENT ON COLUMN api_keys.updated_at IS '更新时间';
+
+-- 两步验证表
+CREATE TABLE IF NOT EXISTS user_totp (
+    id BIGSERIAL PRIMARY KEY,                                 -- 主键
+    user_id INTEGER UNIQUE NOT NULL REFERENCES users(id),     -- 用户ID，每个用户最多一条
+    secret_encrypted TEXT NOT NULL,                           -- 加密后的TOTP密钥
+    recovery_codes TEXT NOT NULL DEFAULT '',                  -- 未使用的恢复码摘要，逗号分隔
+    status INTEGER NOT NULL DEFAULT 1,                        -- 状态：1-待验证，2-已启用
+    last_used_step BIGINT NOT NULL DEFAULT 0,                 -- 最近一次通过校验的时间步
+    enabled_at TIMESTAMP,                                     -- 启用时间
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 创建时间
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP            -- 更新时间
+);
+
+COMMENT ON TABLE user_totp IS 'TOTP两步验证表（RFC 6238），开启后登录、提现和创建API密钥需要验证码';
+COMMENT ON COLUMN user_totp.id IS '主键';
+COMMENT ON COLUMN user_totp.user_id IS '用户ID，每个用户最多一条记录，重新绑定时先删除旧记录';
+COMMENT ON COLUMN user_totp.secret_encrypted IS '使用AES-256-GCM加密的Base32密钥，加密密钥来自配置TwoFactor.EncryptionKey';
+COMMENT ON COLUMN user_totp.recovery_codes IS '未使用的恢复码SHA-256摘要，逗号分隔，每个恢复码只能使用一次';
+COMMENT ON COLUMN user_totp.status IS '状态：1-已生成密钥待验证，2-已启用';
+COMMENT ON COLUMN user_totp.last_used_step IS '最近一次通过校验的时间步（Unix时间/30），同一时间步的验证码不能重复使用';
+COMMENT ON COLUMN user_totp.enabled_at IS '验证首个验证码、正式启用的时间';
+COMMENT ON COLUMN user_totp.created_at IS '创建时间';
+COMMENT ON COLUMN user_totp.updated_at IS '更新时间';
+
+-- 用户角色表
+CREATE TABLE IF NOT EXISTS user_roles (
+    id BIGSERIAL PRIMARY KEY,                                 -- 主键
+    user_id INTEGER NOT NULL REFERENCES users(id),            -- 用户ID
+    role VARCHAR(32) NOT NULL,                                -- 角色
+    granted_by BIGINT NOT NULL DEFAULT 0,                     -- 授予人用户ID
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 授予时间
+    UNIQUE(user_id, role)                                     -- 同一用户同一角色只有一条记录
+);
+
+COMMENT ON TABLE user_roles IS '用户角色表，管理后台接口按角色对应的权限授权';
+COMMENT ON COLUMN user_roles.id IS '主键';
+COMMENT ON COLUMN user_roles.user_id IS '用户ID';
+COMMENT ON COLUMN user_roles.role IS '角色：super_admin-超级管理员，operator-运营，finance-财务，compliance-合规，auditor-审计';
+COMMENT ON COLUMN user_roles.granted_by IS '授予人用户ID，通过命令行授予时为0';
+COMMENT ON COLUMN user_roles.created_at IS '授予时间';
+
+-- 角色变更审计日志表
+CREATE TABLE IF NOT EXISTS role_audit_logs (
+    id BIGSERIAL PRIMARY KEY,                                 -- 主键
+    user_id INTEGER NOT NULL REFERENCES users(id),            -- 被变更角色的用户ID
+    role VARCHAR(32) NOT NULL,                                -- 角色
+    action SMALLINT NOT NULL,                                 -- 操作：1-授予，2-撤销
+    operator_id BIGINT NOT NULL DEFAULT 0,                    -- 操作人用户ID
+    reason TEXT NOT NULL DEFAULT '',                          -- 变更原因
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 记录时间
+    CHECK (action IN (1, 2))
+);
+
+COMMENT ON TABLE role_audit_logs IS '角色变更审计日志表，与角色变更在同一事务中写入';
+COMMENT ON COLUMN role_audit_logs.id IS '主键';
+COMMENT ON COLUMN role_audit_logs.user_id IS '被变更角色的用户ID';
+COMMENT ON COLUMN role_audit_logs.role IS '被授予或撤销的角色';
+COMMENT ON COLUMN role_audit_logs.action IS '操作：1-授予，2-撤销';
+COMMENT ON COLUMN role_audit_logs.operator_id IS '操作人用户ID，通过命令行操作时为0';
+COMMENT ON COLUMN role_audit_logs.reason IS '变更原因';
+COMMENT ON COLUMN role_audit_logs.created_at IS '记录时间';
+
+-- 身份认证（KYC）申请表
+CREATE TABLE IF NOT EXISTS kyc_submissions (
+    id BIGSERIAL PRIMARY KEY,                                 -- 主键
+    user_id INTEGER NOT NULL REFERENCES users(id),            -- 申请用户ID
+    level SMALLINT NOT NULL,                                  -- 申请的认证等级：1-基础认证，2-高级认证
+    full_name VARCHAR(100) NOT NULL,                          -- 证件上的姓名
+    country VARCHAR(2) NOT NULL,                              -- 国家或地区代码（ISO 3166-1 alpha-2）
+    document_type VARCHAR(32) NOT NULL,                       -- 证件类型
+    document_number VARCHAR(64) NOT NULL,                     -- 证件号码
+    documents TEXT NOT NULL DEFAULT '[]',                     -- 证件文件列表（JSON）
+    status SMALLINT NOT NULL DEFAULT 1,                       -- 状态：1-待审核，2-已通过，3-已拒绝
+    reviewer_id BIGINT NOT NULL DEFAULT 0,                    -- 审核人用户ID
+    review_reason TEXT NOT NULL DEFAULT '',                   -- 审核意见
+    reviewed_at TIMESTAMP,                                    -- 审核时间
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 提交时间
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 更新时间
+    CHECK (level IN (1, 2)),
+    CHECK (status IN (1, 2, 3))
+);
+
+COMMENT ON TABLE kyc_submissions IS '身份认证（KYC）申请表，每个用户同时只能有一个待审核的申请';
+COMMENT ON COLUMN kyc_submissions.id IS '主键';
+COMMENT ON COLUMN kyc_submissions.user_id IS '申请用户ID';
+COMMENT ON COLUMN kyc_submissions.level IS '申请的认证等级：1-基础认证，2-高级认证';
+COMMENT ON COLUMN kyc_submissions.full_name IS '证件上的姓名';
+COMMENT ON COLUMN kyc_submissions.country IS '国家或地区代码（ISO 3166-1 alpha-2）';
+COMMENT ON COLUMN kyc_submissions.document_type IS '证件类型：id_card-身份证，passport-护照，driver_license-驾驶证';
+COMMENT ON COLUMN kyc_submissions.document_number IS '证件号码';
+COMMENT ON COLUMN kyc_submissions.documents IS '证件文件列表（JSON数组），文件内容保存在文件存储中，记录存储key、文件类型和大小';
+COMMENT ON COLUMN kyc_submissions.status IS '状态：1-待审核，2-已通过，3-已拒绝';
+COMMENT ON COLUMN kyc_submissions.reviewer_id IS '审核人用户ID，未审核时为0';
+COMMENT ON COLUMN kyc_submissions.review_reason IS '审核意见，拒绝时必填';
+COMMENT ON COLUMN kyc_submissions.reviewed_at IS '审核时间';
+COMMENT ON COLUMN kyc_submissions.created_at IS '提交时间';
+COMMENT ON COLUMN kyc_submissions.updated_at IS '更新时间';
+
+-- 认证等级变更记录表
+CREATE TABLE IF NOT EXISTS verification_level_changes (
+    id BIGSERIAL PRIMARY KEY,                                 -- 主键
+    user_id INTEGER NOT NULL REFERENCES users(id),            -- 用户ID
+    from_level SMALLINT NOT NULL,                             -- 变更前等级
+    to_level SMALLINT NOT NULL,                               -- 变更后等级
+    submission_id BIGINT NOT NULL DEFAULT 0,                  -- 关联的认证申请ID
+    reviewer_id BIGINT NOT NULL DEFAULT 0,                    -- 操作人用户ID
+    reason TEXT NOT NULL DEFAULT '',                          -- 变更原因
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP            -- 记录时间
+);
+
+COMMENT ON TABLE verification_level_changes IS '用户认证等级变更记录表，与等级变更在同一事务中写入';
+COMMENT ON COLUMN verification_level_changes.id IS '主键';
+COMMENT ON COLUMN verification_level_changes.user_id IS '用户ID';
+COMMENT ON COLUMN verification_level_changes.from_level IS '变更前等级：0-未认证，1-基础认证，2-高级认证';
+COMMENT ON COLUMN verification_level_changes.to_level IS '变更后等级：0-未认证，1-基础认证，2-高级认证';
+COMMENT ON COLUMN verification_level_changes.submission_id IS '审核通过的认证申请ID，管理员直接调整等级时为0';
+COMMENT ON COLUMN verification_level_changes.reviewer_id IS '审核人或操作人用户ID';
+COMMENT ON COLUMN verification_level_changes.reason IS '变更原因';
+COMMENT ON COLUMN verification_level_changes.created_at IS '记录时间';
+
+-- 账户安全事件表
+CREATE TABLE IF NOT EXISTS security_events (
+    id BIGSERIAL PRIMARY KEY,                                 -- 主键
+    user_id BIGINT NOT NULL DEFAULT 0,                        -- 用户ID
+    event_type VARCHAR(50) NOT NULL,                          -- 事件类型
+    ip VARCHAR(64) NOT NULL DEFAULT '',                       -- 客户端IP
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',              -- 客户端标识
+    device_fingerprint VARCHAR(64) NOT NULL DEFAULT '',       -- 设备指纹
+    detail TEXT NOT NULL DEFAULT '',                          -- 事件详情
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP            -- 发生时间
+);
+
+COMMENT ON TABLE security_events IS '账户安全事件表，记录登录成功和失败、重置密码、两步验证、API密钥和提现地址变更，只追加不修改';
+COMMENT ON COLUMN security_events.id IS '主键';
+COMMENT ON COLUMN security_events.user_id IS '用户ID，登录邮箱不存在时为0';
+COMMENT ON COLUMN security_events.event_type IS '事件类型：login_success、login_failed、password_reset、2fa_enabled、2fa_disabled、api_key_created、api_key_deleted、withdraw_address_added、withdraw_address_deleted、withdraw_settings_changed';
+COMMENT ON COLUMN security_events.ip IS '客户端IP';
+COMMENT ON COLUMN security_events.user_agent IS '客户端标识（User-Agent）';
+COMMENT ON COLUMN security_events.device_fingerprint IS '设备指纹，由X-Device-Id、User-Agent和Accept-Language计算';
+COMMENT ON COLUMN security_events.detail IS '事件详情，如登录失败原因、API密钥ID、提现地址';
+COMMENT ON COLUMN security_events.created_at IS '发生时间';
+
+-- 账户限制表
+CREATE TABLE IF NOT EXISTS user_restrictions (
+    id BIGSERIAL PRIMARY KEY,                                 -- 主键
+    user_id INTEGER NOT NULL REFERENCES users(id),            -- 用户ID
+    type VARCHAR(16) NOT NULL,                                -- 限制类型
+    reason TEXT NOT NULL DEFAULT '',                          -- 限制原因
+    expires_at TIMESTAMP,                                     -- 到期时间
+    created_by BIGINT NOT NULL DEFAULT 0,                     -- 操作人用户ID
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 施加时间
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 最后更新时间
+    UNIQUE(user_id, type),                                    -- 同一用户同一类型只有一条记录
+    CHECK (type IN ('login', 'trading', 'withdraw', 'freeze'))
+);
+
+COMMENT ON TABLE user_restrictions IS '账户限制表，合规管控禁止登录、交易、提现或冻结账户，解除时删除记录';
+COMMENT ON COLUMN user_restrictions.id IS '主键';
+COMMENT ON COLUMN user_restrictions.user_id IS '用户ID';
+COMMENT ON COLUMN user_restrictions.type IS '限制类型：login-禁止登录，trading-禁止下单，withdraw-禁止提现和转出，freeze-冻结账户（包含全部限制）';
+COMMENT ON COLUMN user_restrictions.reason IS '限制原因，仅管理员可见';
+COMMENT ON COLUMN user_restrictions.expires_at IS '到期时间，为空时永久有效直到解除';
+COMMENT ON COLUMN user_restrictions.created_by IS '最后施加或更新限制的操作人用户ID';
+COMMENT ON COLUMN user_restrictions.created_at IS '施加时间';
+COMMENT ON COLUMN user_restrictions.updated_at IS '最后更新时间';
+
+-- 账户限制变更审计日志表
+CREATE TABLE IF NOT EXISTS restriction_audit_logs (
+    id BIGSERIAL PRIMARY KEY,                                 -- 主键
+    user_id INTEGER NOT NULL REFERENCES users(id),            -- 被限制的用户ID
+    type VARCHAR(16) NOT NULL,                                -- 限制类型
+    action SMALLINT NOT NULL,                                 -- 操作：1-施加，2-解除
+    reason TEXT NOT NULL DEFAULT '',                          -- 变更原因
+    expires_at TIMESTAMP,                                     -- 施加时设置的到期时间
+    operator_id BIGINT NOT NULL DEFAULT 0,                    -- 操作人用户ID
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 记录时间
+    CHECK (action IN (1, 2))
+);
+
+COMMENT ON TABLE restriction_audit_logs IS '账户限制变更审计日志表，与限制变更在同一事务中写入';
+COMMENT ON COLUMN restriction_audit_logs.id IS '主键';
+COMMENT ON COLUMN restriction_audit_logs.user_id IS '被限制的用户ID';
+COMMENT ON COLUMN restriction_audit_logs.type IS '限制类型：login、trading、withdraw、freeze';
+COMMENT ON COLUMN restriction_audit_logs.action IS '操作：1-施加或更新，2-解除';
+COMMENT ON COLUMN restriction_audit_logs.reason IS '变更原因';
+COMMENT ON COLUMN restriction_audit_logs.expires_at IS '施加时设置的到期时间，为空表示永久有效';
+COMMENT ON COLUMN restriction_audit_logs.operator_id IS '操作人用户ID';
+COMMENT ON COLUMN restriction_audit_logs.created_at IS '记录时间';
+
 -- 交易对表
 CREATE TABLE IF NOT EXISTS trading_pairs (
     id SERIAL PRIMARY KEY,                                    -- 交易对ID
@@ -53,7 +399,12 @@ CREATE TABLE IF NOT EXISTS trading_pairs (
     max_amount VARCHAR(50) DEFAULT '0',                       -- 最大交易数量
     price_scale INTEGER DEFAULT 8,                            -- 价格精度，小数位数
     amount_scale INTEGER DEFAULT 8,                           -- 数量精度，小数位数
-    status INTEGER DEFAULT 1,                                 -- 交易对状态：1-正常，2-禁用
+    tick_size VARCHAR(50) DEFAULT '0',                        -- 价格最小变动单位，0表示不限制
+    step_size VARCHAR(50) DEFAULT '0',                        -- 数量最小变动单位，0表示不限制
+    min_notional VARCHAR(50) DEFAULT '0',                     -- 最小下单金额，0表示不限制
+    max_open_orders INTEGER DEFAULT 0,                        -- 单用户最大挂单数量，0表示不限制
+    min_verification_level INTEGER DEFAULT 0,                 -- 下单所需最低认证等级，0表示不限制
+    status INTEGER DEFAULT 1,                                 -- 交易对状态：1-正常，2-暂停，3-预上线，4-仅撤单，5-仅挂单，6-已下架
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP            -- 创建时间
 );
 
@@ -66,9 +417,42 @@ COMMENT ON COLUMN trading_pairs.min_amount IS '单笔交易最小数量限制';
 COMMENT ON COLUMN trading_pairs.max_amount IS '单笔交易最大数量限制';
 COMMENT ON COLUMN trading_pairs.price_scale IS '价格显示精度，小数点后位数';
 COMMENT ON COLUMN trading_pairs.amount_scale IS '数量显示精度，小数点后位数';
-COMMENT ON COLUMN trading_pairs.status IS '交易对状态：1-正常交易，2-禁用交易';
+COMMENT ON COLUMN trading_pairs.tick_size IS '价格最小变动单位，限价单价格必须是其整数倍，0表示不限制';
+COMMENT ON COLUMN trading_pairs.step_size IS '数量最小变动单位，订单数量必须是其整数倍，0表示不限制';
+COMMENT ON COLUMN trading_pairs.min_notional IS '最小下单金额（价格×数量），以计价币种计，0表示不限制';
+COMMENT ON COLUMN trading_pairs.max_open_orders IS '单个用户在该交易对同时存在的最大挂单数量，0表示不限制';
+COMMENT ON COLUMN trading_pairs.min_verification_level IS '在该交易对下单所需的最低用户认证等级：0-不限制，1-基础认证，2-高级认证';
+COMMENT ON COLUMN trading_pairs.status IS '交易对状态：1-正常交易，2-暂停交易（禁止下单，允许撤单），3-预上线（禁止下单），4-仅可撤单，5-仅挂单（只接受不会立即成交的限价单），6-已下架（终态，挂单全部撤销）';
 COMMENT ON COLUMN trading_pairs.created_at IS '交易对创建时间';
 
+-- 交易对状态变更记录表
+CREATE TABLE IF NOT EXISTS trading_pair_status_changes (
+    id SERIAL PRIMARY KEY,                                    -- 记录ID
+    symbol VARCHAR(20) NOT NULL,                              -- 交易对符号
+    from_status INTEGER NOT NULL,                             -- 变更前状态
+    to_status INTEGER NOT NULL,                               -- 目标状态
+    effective_at TIMESTAMP NOT NULL,                          -- 计划生效时间
+    state INTEGER DEFAULT 1,                                  -- 执行状态：1-待执行，2-已执行，3-已取消，4-执行失败
+    operator_id INTEGER DEFAULT 0,                            -- 操作人用户ID
+    reason VARCHAR(255) DEFAULT '',                           -- 变更原因
+    remark VARCHAR(500) DEFAULT '',                           -- 执行备注
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,           -- 创建时间
+    executed_at TIMESTAMP                                     -- 实际执行时间
+);
+
+COMMENT ON TABLE trading_pair_status_changes IS '交易对状态变更记录表，同时作为状态变更排期和审计日志';
+COMMENT ON COLUMN trading_pair_status_changes.id IS '记录ID，主键';
+COMMENT ON COLUMN trading_pair_status_changes.symbol IS '交易对符号，如BTC/USDT';
+COMMENT ON COLUMN trading_pair_status_changes.from_status IS '变更前状态，执行时更新为实际的变更前状态';
+COMMENT ON COLUMN trading_pair_status_changes.to_status IS '目标状态，取值同trading_pairs.status';
+COMMENT ON COLUMN trading_pair_status_changes.effective_at IS '计划生效时间，立即执行的变更为提交时间';
+COMMENT ON COLUMN trading_pair_status_changes.state IS '执行状态：1-待执行（排期中），2-已执行，3-已取消，4-执行失败';
+COMMENT ON COLUMN trading_pair_status_changes.operator_id IS '操作人用户ID，系统任务为0';
+COMMENT ON COLUMN trading_pair_status_changes.reason IS '状态变更原因';
+COMMENT ON COLUMN trading_pair_status_changes.remark IS '执行备注，如下架撤单数量、失败原因';
+COMMENT ON COLUMN trading_pair_status_changes.created_at IS '记录创建时间';
+COMMENT ON COLUMN trading_pair_status_changes.executed_at IS '实际执行或取消时间';
+
 -- 订单表
 CREATE TABLE IF NOT EXISTS orders (
     id SERIAL PRIMARY KEY,                                    -- 订单ID
@@ -121,6 +505,125 @@ COMMENT ON COLUMN trades.price IS '成交价格，以计价币种计价';
 COMMENT ON COLUMN trades.amount IS '成交数量，基础币种数量';
 COMMENT ON COLUMN trades.created_at IS '成交时间戳';
 
+-- 账本分录表（复式记账，只追加不修改）
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id BIGSERIAL PRIMARY KEY,                                 -- 分录ID
+    journal_id VARCHAR(64) NOT NULL,                          -- 记账凭证ID
+    user_id INTEGER NOT NULL DEFAULT 0,                       -- 用户ID，系统科目为0
+    currency VARCHAR(10) NOT NULL,                            -- 币种代码
+    account SMALLINT NOT NULL,                                -- 科目
+    direction SMALLINT NOT NULL,                              -- 方向：1-借方，2-贷方
+    amount VARCHAR(50) NOT NULL,                              -- 金额
+    biz_type SMALLINT NOT NULL,                               -- 业务类型
+    biz_id VARCHAR(64) NOT NULL,                              -- 业务ID
+    remark VARCHAR(255) DEFAULT '',                           -- 备注
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP            -- 记账时间
+);
+
+COMMENT ON TABLE ledger_entries IS '账本分录表，复式记账，同一凭证下各币种借贷平衡，只追加不修改';
+COMMENT ON COLUMN ledger_entries.id IS '分录ID，主键';
+COMMENT ON COLUMN ledger_entries.journal_id IS '记账凭证ID，同一业务事件产生的分录共享';
+COMMENT ON COLUMN ledger_entries.user_id IS '用户ID，关联users表，系统科目为0';
+COMMENT ON COLUMN ledger_entries.currency IS '币种代码，如BTC、USDT';
+COMMENT ON COLUMN ledger_entries.account IS '科目：1-用户可用余额，2-用户冻结余额，3-系统外部科目，4-手续费收入';
+COMMENT ON COLUMN ledger_entries.direction IS '记账方向：1-借方，2-贷方；用户科目贷方增加、借方减少';
+COMMENT ON COLUMN ledger_entries.amount IS '分录金额，始终为正数';
+COMMENT ON COLUMN ledger_entries.biz_type IS '业务类型：1-充值，2-提现，3-下单冻结，4-解冻，5-成交结算，6-站内转账';
+COMMENT ON COLUMN ledger_entries.biz_id IS '业务ID：交易ID、订单ID或成交记录ID';
+COMMENT ON COLUMN ledger_entries.remark IS '备注';
+COMMENT ON COLUMN ledger_entries.created_at IS '记账时间';
+
+-- 余额对账报告表
+CREATE TABLE IF NOT EXISTS reconciliation_reports (
+    id BIGSERIAL PRIMARY KEY,                                 -- 报告ID
+    status SMALLINT NOT NULL DEFAULT 1,                       -- 任务状态
+    checked_accounts INTEGER NOT NULL DEFAULT 0,              -- 检查账户数
+    discrepancy_count INTEGER NOT NULL DEFAULT 0,             -- 不平账户数
+    frozen_users INTEGER NOT NULL DEFAULT 0,                  -- 自动冻结用户数
+    auto_freeze BOOLEAN NOT NULL DEFAULT FALSE,               -- 是否开启自动冻结
+    remark TEXT DEFAULT '',                                   -- 备注
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,  -- 开始时间
+    finished_at TIMESTAMP                                     -- 结束时间
+);
+
+COMMENT ON TABLE reconciliation_reports IS '余额对账报告表，每次对账任务生成一条';
+COMMENT ON COLUMN reconciliation_reports.id IS '报告ID，主键';
+COMMENT ON COLUMN reconciliation_reports.status IS '任务状态：1-执行中，2-已完成，3-执行失败';
+COMMENT ON COLUMN reconciliation_reports.checked_accounts IS '检查的用户币种账户数量';
+COMMENT ON COLUMN reconciliation_reports.discrepancy_count IS '期望余额与实际余额不一致的账户数量';
+COMMENT ON COLUMN reconciliation_reports.frozen_users IS '本次自动冻结的用户数量';
+COMMENT ON COLUMN reconciliation_reports.auto_freeze IS '是否开启自动冻结';
+COMMENT ON COLUMN reconciliation_reports.remark IS '备注，如失败原因';
+COMMENT ON COLUMN reconciliation_reports.started_at IS '对账开始时间';
+COMMENT ON COLUMN reconciliation_reports.finished_at IS '对账结束时间';
+
+-- 对账差异明细表
+CREATE TABLE IF NOT EXISTS balance_discrepancies (
+    id BIGSERIAL PRIMARY KEY,                                 -- 记录ID
+    report_id BIGINT NOT NULL REFERENCES reconciliation_reports(id), -- 对账报告ID
+    user_id INTEGER NOT NULL,                                 -- 用户ID
+    currency VARCHAR(10) NOT NULL,                            -- 币种代码
+    expected_total VARCHAR(50) NOT NULL,                      -- 期望总余额
+    actual_total VARCHAR(50) NOT NULL,                        -- 实际总余额
+    expected_frozen VARCHAR(50) NOT NULL,                     -- 期望冻结余额
+    actual_frozen VARCHAR(50) NOT NULL,                       -- 实际冻结余额
+    user_frozen BOOLEAN NOT NULL DEFAULT FALSE,               -- 是否已冻结用户
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP            -- 创建时间
+);
+
+COMMENT ON TABLE balance_discrepancies IS '对账差异明细表，记录期望余额与实际余额不一致的账户';
+COMMENT ON COLUMN balance_discrepancies.id IS '记录ID，主键';
+COMMENT ON COLUMN balance_discrepancies.report_id IS '对账报告ID，关联reconciliation_reports表';
+COMMENT ON COLUMN balance_discrepancies.user_id IS '用户ID，关联users表';
+COMMENT ON COLUMN balance_discrepancies.currency IS '币种代码，如BTC、USDT';
+COMMENT ON COLUMN balance_discrepancies.expected_total IS '期望总余额：成功充值-待审核及成功提现(含手续费)±成交';
+COMMENT ON COLUMN balance_discrepancies.actual_total IS '实际总余额：balances.available+frozen';
+COMMENT ON COLUMN balance_discrepancies.expected_frozen IS '期望冻结余额：未完成订单剩余部分的冻结数量';
+COMMENT ON COLUMN balance_discrepancies.actual_frozen IS '实际冻结余额：balances.frozen';
+COMMENT ON COLUMN balance_discrepancies.user_frozen IS '是否已自动冻结该用户';
+COMMENT ON COLUMN balance_discrepancies.created_at IS '记录创建时间';
+
+-- 储备金证明快照表
+CREATE TABLE IF NOT EXISTS reserve_snapshots (
+    id BIGSERIAL PRIMARY KEY,                                 -- 快照ID
+    batch_id VARCHAR(64) NOT NULL,                            -- 批次ID
+    currency VARCHAR(10) NOT NULL,                            -- 币种代码
+    root_hash VARCHAR(64) NOT NULL,                           -- 根哈希
+    total_liabilities VARCHAR(50) NOT NULL,                   -- 用户负债总额
+    leaf_count INTEGER NOT NULL DEFAULT 0,                    -- 叶子数量
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP            -- 快照时间
+);
+
+COMMENT ON TABLE reserve_snapshots IS '储备金证明快照表，每个币种一条，记录Merkle求和树的根';
+COMMENT ON COLUMN reserve_snapshots.id IS '快照ID，主键';
+COMMENT ON COLUMN reserve_snapshots.batch_id IS '批次ID，同一次快照任务生成的各币种快照共享';
+COMMENT ON COLUMN reserve_snapshots.currency IS '币种代码，如BTC、USDT';
+COMMENT ON COLUMN reserve_snapshots.root_hash IS 'Merkle求和树根哈希，公开发布';
+COMMENT ON COLUMN reserve_snapshots.total_liabilities IS '用户负债总额，即根节点的余额之和，公开发布';
+COMMENT ON COLUMN reserve_snapshots.leaf_count IS '叶子数量，即余额非零的用户数';
+COMMENT ON COLUMN reserve_snapshots.created_at IS '快照时间';
+
+-- 储备金证明快照叶子表
+CREATE TABLE IF NOT EXISTS reserve_snapshot_leaves (
+    id BIGSERIAL PRIMARY KEY,                                 -- 记录ID
+    snapshot_id BIGINT NOT NULL REFERENCES reserve_snapshots(id), -- 快照ID
+    leaf_index INTEGER NOT NULL,                              -- 叶子序号
+    user_id INTEGER NOT NULL,                                 -- 用户ID
+    user_hash VARCHAR(64) NOT NULL,                           -- 加盐哈希后的用户ID
+    salt VARCHAR(64) NOT NULL,                                -- 盐值
+    balance VARCHAR(50) NOT NULL,                             -- 用户余额
+    UNIQUE(snapshot_id, leaf_index)                           -- 唯一索引：快照+叶子序号
+);
+
+COMMENT ON TABLE reserve_snapshot_leaves IS '储备金证明快照叶子表，保存重建Merkle求和树和生成包含证明所需的数据';
+COMMENT ON COLUMN reserve_snapshot_leaves.id IS '记录ID，主键';
+COMMENT ON COLUMN reserve_snapshot_leaves.snapshot_id IS '快照ID，关联reserve_snapshots表';
+COMMENT ON COLUMN reserve_snapshot_leaves.leaf_index IS '叶子序号，从0开始，决定叶子在树中的位置';
+COMMENT ON COLUMN reserve_snapshot_leaves.user_id IS '用户ID，关联users表，不对外公开';
+COMMENT ON COLUMN reserve_snapshot_leaves.user_hash IS '加盐哈希后的用户ID：sha256(用户ID:盐值)';
+COMMENT ON COLUMN reserve_snapshot_leaves.salt IS '用户专属随机盐值，仅通过包含证明返回给用户本人';
+COMMENT ON COLUMN reserve_snapshot_leaves.balance IS '快照时的用户余额：available+frozen';
+
 -- K线数据表
 CREATE TABLE IF NOT EXISTS klines (
     id SERIAL PRIMARY KEY,                                    -- K线记录ID
@@ -172,18 +675,56 @@ COMMENT ON COLUMN tickers.updated_at IS '数据最后更新时间';
 -- 用户表索引
 CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
 CREATE INDEX IF NOT EXISTS idx_users_status ON users(status);
+CREATE INDEX IF NOT EXISTS idx_users_parent_id ON users(parent_id);
+
+-- API密钥表索引
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_status ON api_keys(user_id, status);
+
+-- 角色审计日志表索引
+CREATE INDEX IF NOT EXISTS idx_role_audit_logs_user_id ON role_audit_logs(user_id, id);
+
+-- 身份认证申请表索引
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_status ON kyc_submissions(status, id);
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_user_id ON kyc_submissions(user_id, id);
+CREATE UNIQUE INDEX IF NOT EXISTS uniq_kyc_submissions_pending ON kyc_submissions(user_id) WHERE status = 1;
+
+-- 认证等级变更记录表索引
+CREATE INDEX IF NOT EXISTS idx_verification_level_changes_user_id ON verification_level_changes(user_id, id);
+
+-- 账户安全事件表索引
+CREATE INDEX IF NOT EXISTS idx_security_events_user_id ON security_events(user_id, id);
+CREATE INDEX IF NOT EXISTS idx_security_events_user_type ON security_events(user_id, event_type, created_at);
+
+-- 账户限制变更审计日志表索引
+CREATE INDEX IF NOT EXISTS idx_restriction_audit_logs_user_id ON restriction_audit_logs(user_id, id);
 
 -- 余额表索引
 CREATE INDEX IF NOT EXISTS idx_balances_user_id ON balances(user_id);
 CREATE INDEX IF NOT EXISTS idx_balances_currency ON balances(currency);
 CREATE INDEX IF NOT EXISTS idx_balances_user_currency ON balances(user_id, currency);
 
+-- 币种配置表索引
+CREATE INDEX IF NOT EXISTS idx_currencies_status ON currencies(status);
+
+-- 币种网络配置表索引
+CREATE INDEX IF NOT EXISTS idx_currency_networks_currency ON currency_networks(currency);
+
+-- 充值地址表索引
+CREATE UNIQUE INDEX IF NOT EXISTS idx_deposit_addresses_user ON deposit_addresses(user_id, currency, network) WHERE user_id > 0; -- 每个用户每个币种每个网络一个地址
+CREATE UNIQUE INDEX IF NOT EXISTS idx_deposit_addresses_derivation ON deposit_addresses(network, derivation_index) WHERE derivation_index >= 0;
+CREATE INDEX IF NOT EXISTS idx_deposit_addresses_pool ON deposit_addresses(network, id) WHERE user_id = 0; -- 地址池分配
+CREATE INDEX IF NOT EXISTS idx_withdrawal_addresses_user ON withdrawal_addresses(user_id);
+
 -- 交易对表索引
 CREATE INDEX IF NOT EXISTS idx_trading_pairs_symbol ON trading_pairs(symbol);
 CREATE INDEX IF NOT EXISTS idx_trading_pairs_status ON trading_pairs(status);
 CREATE INDEX IF NOT EXISTS idx_trading_pairs_base_currency ON trading_pairs(base_currency);
 CREATE INDEX IF NOT EXISTS idx_trading_pairs_quote_currency ON trading_pairs(quote_currency);
 
+-- 交易对状态变更记录表索引
+CREATE INDEX IF NOT EXISTS idx_trading_pair_status_changes_symbol ON trading_pair_status_changes(symbol, created_at);
+CREATE INDEX IF NOT EXISTS idx_trading_pair_status_changes_due ON trading_pair_status_changes(effective_at) WHERE state = 1; -- 只对待执行排期建索引
+
 -- 订单表索引（撮合引擎性能关键）
 CREATE INDEX IF NOT EXISTS idx_orders_user_id ON orders(user_id);
 CREATE INDEX IF NOT EXISTS idx_orders_symbol ON orders(symbol);
@@ -203,6 +744,20 @@ CREATE INDEX IF NOT EXISTS idx_trades_sell_user_id ON trades(sell_user_id);
 CREATE INDEX IF NOT EXISTS idx_trades_buy_order_id ON trades(buy_order_id);
 CREATE INDEX IF NOT EXISTS idx_trades_sell_order_id ON trades(sell_order_id);
 
+-- 账本分录表索引
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_user_currency ON ledger_entries(user_id, currency, id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_journal_id ON ledger_entries(journal_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_biz ON ledger_entries(biz_type, biz_id);
+
+-- 对账差异明细表索引
+CREATE INDEX IF NOT EXISTS idx_balance_discrepancies_report_id ON balance_discrepancies(report_id);
+CREATE INDEX IF NOT EXISTS idx_balance_discrepancies_user_id ON balance_discrepancies(user_id);
+
+-- 储备金证明表索引
+CREATE INDEX IF NOT EXISTS idx_reserve_snapshots_currency ON reserve_snapshots(currency, id);
+CREATE INDEX IF NOT EXISTS idx_reserve_snapshots_batch_id ON reserve_snapshots(batch_id);
+CREATE INDEX IF NOT EXISTS idx_reserve_snapshot_leaves_user ON reserve_snapshot_leaves(snapshot_id, user_id);
+
 -- K线数据表索引
 CREATE INDEX IF NOT EXISTS idx_klines_symbol_interval ON klines(symbol, interval);
 CREATE INDEX IF NOT EXISTS idx_klines_symbol_interval_open_time ON klines(symbol, interval, open_time);
@@ -211,9 +766,31 @@ CREATE INDEX IF NOT EXISTS idx_klines_open_time ON klines(open_time);
 -- 24小时统计表索引
 CREATE INDEX IF NOT EXISTS idx_tickers_updated_at ON tickers(updated_at);
 
+-- 插入初始币种数据
+INSERT INTO currencies (code, name, precision, deposit_enabled, withdraw_enabled, max_deposit, max_withdraw, status) VALUES
+('BTC', 'Bitcoin', 8, TRUE, TRUE, '1000000', '10', 1),
+('ETH', 'Ethereum', 8, TRUE, TRUE, '1000000', '100', 1),
+('USDT', 'Tether USD', 8, TRUE, TRUE, '1000000', '100000', 1),
+('USDC', 'USD Coin', 8, TRUE, TRUE, '1000000', '100000', 1),
+('BNB', 'BNB', 8, FALSE, FALSE, '1000000', '1000000', 1),
+('ADA', 'Cardano', 6, FALSE, FALSE, '1000000', '1000000', 1),
+('DOT', 'Polkadot', 8, FALSE, FALSE, '1000000', '1000000', 1),
+('LTC', 'Litecoin', 8, FALSE, FALSE, '1000000', '1000000', 1)
+ON CONFLICT (code) DO NOTHING;
+
+-- 插入初始币种网络数据
+INSERT INTO currency_networks (currency, network, address_type, deposit_enabled, withdraw_enabled, withdraw_fee_fixed, withdraw_fee_rate, min_deposit, min_withdraw, confirmations) VALUES
+('BTC', 'BTC', 'bitcoin', TRUE, TRUE, '0.0005', '0', '0.00000001', '0.001', 2),
+('ETH', 'ERC20', 'evm', TRUE, TRUE, '0.005', '0', '0.00000001', '0.01', 12),
+('USDT', 'ERC20', 'evm', TRUE, TRUE, '1', '0', '0.00000001', '10', 12),
+('USDT', 'TRC20', 'tron', TRUE, TRUE, '0.8', '0', '0.00000001', '10', 20),
+('USDC', 'ERC20', 'evm', TRUE, TRUE, '1', '0', '0.00000001', '10', 12),
+('BNB', 'BEP20', 'evm', FALSE, FALSE, '0', '0.001', '0.00000001', '0.00000001', 15)
+ON CONFLICT (currency, network) DO NOTHING;
+
 -- 插入初始交易对数据
-INSERT INTO trading_pairs (symbol, base_currency, quote_currency, min_amount, max_amount, price_scale, amount_scale, status) VALUES
-('BTC/USDT', 'BTC', 'USDT', '0.00001', '1000', 2, 8, 1),
-('ETH/USDT', 'ETH', 'USDT', '0.001', '10000', 2, 6, 1),
-('BNB/USDT', 'BNB', 'USDT', '0.01', '100000', 2, 4, 1)
+INSERT INTO trading_pairs (symbol, base_currency, quote_currency, min_amount, max_amount, price_scale, amount_scale, tick_size, step_size, min_notional, max_open_orders, status) VALUES
+('BTC/USDT', 'BTC', 'USDT', '0.00001', '1000', 2, 8, '0.01', '0.00000001', '10', 200, 1),
+('ETH/USDT', 'ETH', 'USDT', '0.001', '10000', 2, 6, '0.01', '0.000001', '10', 200, 1),
+('BNB/USDT', 'BNB', 'USDT', '0.01', '100000', 2, 4, '0.01', '0.0001', '5', 200, 1)
 ON CONFLICT (symbol) DO NOTHING;
\ No newline at end of file